@@ -16,12 +16,16 @@ import (
 type BackendSecurityPolicyType string
 
 const (
-	BackendSecurityPolicyTypeAPIKey           BackendSecurityPolicyType = "APIKey"
-	BackendSecurityPolicyTypeAWSCredentials   BackendSecurityPolicyType = "AWSCredentials"
-	BackendSecurityPolicyTypeAzureAPIKey      BackendSecurityPolicyType = "AzureAPIKey"
-	BackendSecurityPolicyTypeAnthropicAPIKey  BackendSecurityPolicyType = "AnthropicAPIKey" // #nosec G101
-	BackendSecurityPolicyTypeAzureCredentials BackendSecurityPolicyType = "AzureCredentials"
-	BackendSecurityPolicyTypeGCPCredentials   BackendSecurityPolicyType = "GCPCredentials"
+	BackendSecurityPolicyTypeAPIKey            BackendSecurityPolicyType = "APIKey"
+	BackendSecurityPolicyTypeAWSCredentials    BackendSecurityPolicyType = "AWSCredentials"
+	BackendSecurityPolicyTypeAzureAPIKey       BackendSecurityPolicyType = "AzureAPIKey"
+	BackendSecurityPolicyTypeAnthropicAPIKey   BackendSecurityPolicyType = "AnthropicAPIKey" // #nosec G101
+	BackendSecurityPolicyTypeAzureCredentials  BackendSecurityPolicyType = "AzureCredentials"
+	BackendSecurityPolicyTypeGCPCredentials    BackendSecurityPolicyType = "GCPCredentials"
+	BackendSecurityPolicyTypeAPIKeyPool        BackendSecurityPolicyType = "APIKeyPool"
+	BackendSecurityPolicyTypeVault             BackendSecurityPolicyType = "Vault"
+	BackendSecurityPolicyTypeClientCertificate BackendSecurityPolicyType = "ClientCertificate"
+	BackendSecurityPolicyTypeRequestSigning    BackendSecurityPolicyType = "RequestSigning"
 )
 
 // BackendSecurityPolicy specifies configuration for authentication and authorization rules on the traffic
@@ -47,12 +51,16 @@ type BackendSecurityPolicy struct {
 //
 // Only one type of BackendSecurityPolicy can be defined.
 // +kubebuilder:validation:MaxProperties=3
-// +kubebuilder:validation:XValidation:rule="self.type == 'APIKey' ? (has(self.apiKey) && !has(self.awsCredentials) && !has(self.azureAPIKey) && !has(self.azureCredentials) && !has(self.gcpCredentials) && !has(self.anthropicAPIKey)) : true",message="When type is APIKey, only apiKey field should be set"
-// +kubebuilder:validation:XValidation:rule="self.type == 'AWSCredentials' ? (has(self.awsCredentials) && !has(self.apiKey) && !has(self.azureAPIKey) && !has(self.azureCredentials) && !has(self.gcpCredentials) && !has(self.anthropicAPIKey)) : true",message="When type is AWSCredentials, only awsCredentials field should be set"
-// +kubebuilder:validation:XValidation:rule="self.type == 'AzureAPIKey' ? (has(self.azureAPIKey) && !has(self.apiKey) && !has(self.awsCredentials) && !has(self.azureCredentials) && !has(self.gcpCredentials) && !has(self.anthropicAPIKey)) : true",message="When type is AzureAPIKey, only azureAPIKey field should be set"
-// +kubebuilder:validation:XValidation:rule="self.type == 'AzureCredentials' ? (has(self.azureCredentials) && !has(self.apiKey) && !has(self.awsCredentials) && !has(self.azureAPIKey) && !has(self.gcpCredentials) && !has(self.anthropicAPIKey)) : true",message="When type is AzureCredentials, only azureCredentials field should be set"
-// +kubebuilder:validation:XValidation:rule="self.type == 'GCPCredentials' ? (has(self.gcpCredentials) && !has(self.apiKey) && !has(self.awsCredentials) && !has(self.azureAPIKey) && !has(self.azureCredentials) && !has(self.anthropicAPIKey)) : true",message="When type is GCPCredentials, only gcpCredentials field should be set"
-// +kubebuilder:validation:XValidation:rule="self.type == 'AnthropicAPIKey' ? (has(self.anthropicAPIKey) && !has(self.apiKey) && !has(self.awsCredentials) && !has(self.azureAPIKey) && !has(self.azureCredentials) && !has(self.gcpCredentials)) : true",message="When type is AnthropicAPIKey, only anthropicAPIKey field should be set"
+// +kubebuilder:validation:XValidation:rule="self.type == 'APIKey' ? (has(self.apiKey) && !has(self.awsCredentials) && !has(self.azureAPIKey) && !has(self.azureCredentials) && !has(self.gcpCredentials) && !has(self.anthropicAPIKey) && !has(self.apiKeyPool) && !has(self.vault) && !has(self.clientCertificate) && !has(self.requestSigning)) : true",message="When type is APIKey, only apiKey field should be set"
+// +kubebuilder:validation:XValidation:rule="self.type == 'AWSCredentials' ? (has(self.awsCredentials) && !has(self.apiKey) && !has(self.azureAPIKey) && !has(self.azureCredentials) && !has(self.gcpCredentials) && !has(self.anthropicAPIKey) && !has(self.apiKeyPool) && !has(self.vault) && !has(self.clientCertificate) && !has(self.requestSigning)) : true",message="When type is AWSCredentials, only awsCredentials field should be set"
+// +kubebuilder:validation:XValidation:rule="self.type == 'AzureAPIKey' ? (has(self.azureAPIKey) && !has(self.apiKey) && !has(self.awsCredentials) && !has(self.azureCredentials) && !has(self.gcpCredentials) && !has(self.anthropicAPIKey) && !has(self.apiKeyPool) && !has(self.vault) && !has(self.clientCertificate) && !has(self.requestSigning)) : true",message="When type is AzureAPIKey, only azureAPIKey field should be set"
+// +kubebuilder:validation:XValidation:rule="self.type == 'AzureCredentials' ? (has(self.azureCredentials) && !has(self.apiKey) && !has(self.awsCredentials) && !has(self.azureAPIKey) && !has(self.gcpCredentials) && !has(self.anthropicAPIKey) && !has(self.apiKeyPool) && !has(self.vault) && !has(self.clientCertificate) && !has(self.requestSigning)) : true",message="When type is AzureCredentials, only azureCredentials field should be set"
+// +kubebuilder:validation:XValidation:rule="self.type == 'GCPCredentials' ? (has(self.gcpCredentials) && !has(self.apiKey) && !has(self.awsCredentials) && !has(self.azureAPIKey) && !has(self.azureCredentials) && !has(self.anthropicAPIKey) && !has(self.apiKeyPool) && !has(self.vault) && !has(self.clientCertificate) && !has(self.requestSigning)) : true",message="When type is GCPCredentials, only gcpCredentials field should be set"
+// +kubebuilder:validation:XValidation:rule="self.type == 'AnthropicAPIKey' ? (has(self.anthropicAPIKey) && !has(self.apiKey) && !has(self.awsCredentials) && !has(self.azureAPIKey) && !has(self.azureCredentials) && !has(self.gcpCredentials) && !has(self.apiKeyPool) && !has(self.vault) && !has(self.clientCertificate) && !has(self.requestSigning)) : true",message="When type is AnthropicAPIKey, only anthropicAPIKey field should be set"
+// +kubebuilder:validation:XValidation:rule="self.type == 'APIKeyPool' ? (has(self.apiKeyPool) && !has(self.apiKey) && !has(self.awsCredentials) && !has(self.azureAPIKey) && !has(self.azureCredentials) && !has(self.gcpCredentials) && !has(self.anthropicAPIKey) && !has(self.vault) && !has(self.clientCertificate) && !has(self.requestSigning)) : true",message="When type is APIKeyPool, only apiKeyPool field should be set"
+// +kubebuilder:validation:XValidation:rule="self.type == 'Vault' ? (has(self.vault) && !has(self.apiKey) && !has(self.awsCredentials) && !has(self.azureAPIKey) && !has(self.azureCredentials) && !has(self.gcpCredentials) && !has(self.anthropicAPIKey) && !has(self.apiKeyPool) && !has(self.clientCertificate) && !has(self.requestSigning)) : true",message="When type is Vault, only vault field should be set"
+// +kubebuilder:validation:XValidation:rule="self.type == 'ClientCertificate' ? (has(self.clientCertificate) && !has(self.apiKey) && !has(self.awsCredentials) && !has(self.azureAPIKey) && !has(self.azureCredentials) && !has(self.gcpCredentials) && !has(self.anthropicAPIKey) && !has(self.apiKeyPool) && !has(self.vault) && !has(self.requestSigning)) : true",message="When type is ClientCertificate, only clientCertificate field should be set"
+// +kubebuilder:validation:XValidation:rule="self.type == 'RequestSigning' ? (has(self.requestSigning) && !has(self.apiKey) && !has(self.awsCredentials) && !has(self.azureAPIKey) && !has(self.azureCredentials) && !has(self.gcpCredentials) && !has(self.anthropicAPIKey) && !has(self.apiKeyPool) && !has(self.vault) && !has(self.clientCertificate)) : true",message="When type is RequestSigning, only requestSigning field should be set"
 type BackendSecurityPolicySpec struct {
 	// TargetRefs are the names of the AIServiceBackend or InferencePool resources this BackendSecurityPolicy is being attached to.
 	// Attaching multiple BackendSecurityPolicies to the same resource is invalid and will result in an error
@@ -65,7 +73,7 @@ type BackendSecurityPolicySpec struct {
 
 	// Type specifies the type of the backend security policy.
 	//
-	// +kubebuilder:validation:Enum=APIKey;AWSCredentials;AzureAPIKey;AzureCredentials;GCPCredentials;AnthropicAPIKey
+	// +kubebuilder:validation:Enum=APIKey;AWSCredentials;AzureAPIKey;AzureCredentials;GCPCredentials;AnthropicAPIKey;APIKeyPool;Vault;ClientCertificate;RequestSigning
 	Type BackendSecurityPolicyType `json:"type"`
 
 	// APIKey is a mechanism to access a backend(s). The API key will be injected into the Authorization header.
@@ -98,6 +106,36 @@ type BackendSecurityPolicySpec struct {
 	//
 	// +optional
 	AnthropicAPIKey *BackendSecurityPolicyAnthropicAPIKey `json:"anthropicAPIKey,omitempty"`
+
+	// APIKeyPool is a mechanism to access a backend(s) with multiple API keys, for example of different organizations
+	// of the provider, so that the requests are spread across their rate limits. The keys that are rejected or rate
+	// limited by the backend are temporarily ejected from the pool.
+	//
+	// +optional
+	APIKeyPool *BackendSecurityPolicyAPIKeyPool `json:"apiKeyPool,omitempty"`
+
+	// Vault is a mechanism to access a backend(s) with credentials read from HashiCorp Vault, either from a KV
+	// version 2 secrets engine or from a dynamic secrets engine. The credentials are kept in the memory of the
+	// controller instead of a Secret, and read again or renewed before their lease expires.
+	//
+	// This requires the config server of the controller, which delivers the credentials to the external processors
+	// instead of the filter config Secrets. Otherwise, the policy is not accepted.
+	//
+	// +optional
+	Vault *BackendSecurityPolicyVault `json:"vault,omitempty"`
+
+	// ClientCertificate is a mechanism to access a backend(s) with a TLS client certificate, for example self-hosted
+	// inference servers behind an internal PKI. The certificate is presented by Envoy in the TLS handshake with the
+	// backend, whose TLS connection must be configured by a BackendTLSPolicy targeting the Backend.
+	//
+	// +optional
+	ClientCertificate *BackendSecurityPolicyClientCertificate `json:"clientCertificate,omitempty"`
+
+	// RequestSigning is a mechanism to access a backend(s) that authenticates the requests with a signature computed
+	// from a shared key over the request, such as an HMAC HTTP signature.
+	//
+	// +optional
+	RequestSigning *BackendSecurityPolicyRequestSigning `json:"requestSigning,omitempty"`
 }
 
 // BackendSecurityPolicyList contains a list of BackendSecurityPolicy
@@ -118,6 +156,66 @@ type BackendSecurityPolicyAPIKey struct {
 	SecretRef *gwapiv1.SecretObjectReference `json:"secretRef"`
 }
 
+// BackendSecurityPolicyAPIKeyPool specifies a pool of API keys.
+type BackendSecurityPolicyAPIKeyPool struct {
+	// Keys are the API keys of the pool.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=64
+	Keys []BackendSecurityPolicyAPIKeyPoolKey `json:"keys"`
+
+	// Strategy is the strategy used to pick the key of each request. Defaults to RoundRobin.
+	//
+	// +optional
+	// +kubebuilder:default=RoundRobin
+	Strategy APIKeyPoolStrategy `json:"strategy,omitempty"`
+
+	// Header is the header the API key is injected into. Defaults to "Authorization", in which case the key is
+	// prefixed with "Bearer ". The key is injected as-is into the other headers, such as "api-key" for Azure OpenAI
+	// or "x-api-key" for Anthropic.
+	//
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	Header *string `json:"header,omitempty"`
+
+	// EjectionDuration is how long a key is ejected from the pool after the backend responds with 401 or 429 to a
+	// request using it. A 429 response with a Retry-After header ejects the key for the duration it specifies instead.
+	// When all the keys are ejected, the one whose ejection ends first is used. Defaults to 30s.
+	//
+	// +optional
+	// +kubebuilder:default="30s"
+	EjectionDuration *gwapiv1.Duration `json:"ejectionDuration,omitempty"`
+}
+
+// BackendSecurityPolicyAPIKeyPoolKey specifies an API key of a pool.
+type BackendSecurityPolicyAPIKeyPoolKey struct {
+	// SecretRef is the reference to the secret containing the API key.
+	// ai-gateway must be given the permission to read this secret.
+	// The key of the secret should be "apiKey".
+	SecretRef *gwapiv1.SecretObjectReference `json:"secretRef"`
+
+	// Weight is the relative share of the requests sent with this key. Defaults to 1.
+	//
+	// +optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// APIKeyPoolStrategy is the strategy used to pick the key of each request from a pool of API keys.
+//
+// +kubebuilder:validation:Enum=RoundRobin;LeastRecentlyRateLimited
+type APIKeyPoolStrategy string
+
+const (
+	// APIKeyPoolStrategyRoundRobin picks the keys in turn, in proportion to their weights.
+	APIKeyPoolStrategyRoundRobin APIKeyPoolStrategy = "RoundRobin"
+	// APIKeyPoolStrategyLeastRecentlyRateLimited picks the key that was rate limited by the backend the longest time
+	// ago, in proportion to their weights among the keys that were never rate limited.
+	APIKeyPoolStrategyLeastRecentlyRateLimited APIKeyPoolStrategy = "LeastRecentlyRateLimited"
+)
+
 // BackendSecurityPolicyAzureAPIKey specifies the Azure OpenAI API key.
 type BackendSecurityPolicyAzureAPIKey struct {
 	// SecretRef is the reference to the secret containing the Azure API key.
@@ -233,22 +331,29 @@ type BackendSecurityPolicyGCPCredentials struct {
 }
 
 // BackendSecurityPolicyAzureCredentials contains the supported authentication mechanisms to access Azure.
-// Only one of ClientSecretRef or OIDCExchangeToken must be specified. Credentials will not be generated if
-// neither are set.
+// Exactly one of ClientSecretRef, OIDCExchangeToken, ManagedIdentity or WorkloadIdentity must be specified.
+//
+// With ClientSecretRef and OIDCExchangeToken, the controller obtains the access tokens and stores them in a secret.
+// With ManagedIdentity and WorkloadIdentity, the external processor acquires and refreshes the access tokens itself
+// with the identity of the gateway pods, so that no long-lived credential is stored in the cluster.
 //
-// +kubebuilder:validation:XValidation:rule="(has(self.clientSecretRef) && !has(self.oidcExchangeToken)) || (!has(self.clientSecretRef) && has(self.oidcExchangeToken))",message="Exactly one of clientSecretRef or oidcExchangeToken must be specified"
+// +kubebuilder:validation:XValidation:rule="[has(self.clientSecretRef), has(self.oidcExchangeToken), has(self.managedIdentity), has(self.workloadIdentity)].filter(x, x).size() == 1",message="Exactly one of clientSecretRef, oidcExchangeToken, managedIdentity or workloadIdentity must be specified"
+// +kubebuilder:validation:XValidation:rule="has(self.managedIdentity) || (has(self.clientID) && has(self.tenantID))",message="clientID and tenantID must be specified unless managedIdentity is set"
 type BackendSecurityPolicyAzureCredentials struct {
 	// ClientID is a unique identifier for an application in Azure.
 	//
-	// +kubebuilder:validation:Required
+	// With ManagedIdentity, this is the client ID of the user-assigned managed identity to use, and the
+	// system-assigned managed identity is used when unset.
+	//
+	// +optional
 	// +kubebuilder:validation:MinLength=1
-	ClientID string `json:"clientID"`
+	ClientID string `json:"clientID,omitempty"`
 
 	// TenantId is a unique identifier for an Azure Active Directory instance.
 	//
-	// +kubebuilder:validation:Required
+	// +optional
 	// +kubebuilder:validation:MinLength=1
-	TenantID string `json:"tenantID"`
+	TenantID string `json:"tenantID,omitempty"`
 
 	// ClientSecretRef is the reference to the secret containing the Azure client secret.
 	// ai-gateway must be given the permission to read this secret.
@@ -262,6 +367,32 @@ type BackendSecurityPolicyAzureCredentials struct {
 	//
 	// +optional
 	OIDCExchangeToken *AzureOIDCExchangeToken `json:"oidcExchangeToken,omitempty"`
+
+	// ManagedIdentity specifies that the access tokens are acquired from the Azure instance metadata service (IMDS)
+	// of the nodes running the gateway, with the managed identity selected by ClientID.
+	//
+	// +optional
+	ManagedIdentity *AzureManagedIdentity `json:"managedIdentity,omitempty"`
+
+	// WorkloadIdentity specifies that the access tokens are acquired with AKS Workload Identity, by exchanging the
+	// federated service account token of the gateway pods for an access token of the application identified by
+	// ClientID and TenantID.
+	//
+	// +optional
+	WorkloadIdentity *AzureWorkloadIdentity `json:"workloadIdentity,omitempty"`
+}
+
+// AzureManagedIdentity specifies the use of the managed identity of the nodes running the gateway.
+type AzureManagedIdentity struct{}
+
+// AzureWorkloadIdentity specifies the use of AKS Workload Identity.
+type AzureWorkloadIdentity struct {
+	// TokenFilePath is the path of the federated service account token file in the gateway pods.
+	// Defaults to the value of the AZURE_FEDERATED_TOKEN_FILE environment variable set by the AKS Workload Identity
+	// webhook.
+	//
+	// +optional
+	TokenFilePath *string `json:"tokenFilePath,omitempty"`
 }
 
 // AzureOIDCExchangeToken specifies credentials to obtain oidc token from a sso server.
@@ -349,3 +480,191 @@ type BackendSecurityPolicyAnthropicAPIKey struct {
 	// The key of the secret should be "apiKey".
 	SecretRef *gwapiv1.SecretObjectReference `json:"secretRef"`
 }
+
+// BackendSecurityPolicyVault specifies the credentials read from HashiCorp Vault.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.kv) != has(self.dynamic)",message="Exactly one of kv or dynamic must be specified"
+// +kubebuilder:validation:XValidation:rule="self.credential.type == 'AWSCredentials' ? has(self.credential.region) : true",message="region must be specified for AWSCredentials"
+type BackendSecurityPolicyVault struct {
+	// Address is the address of the Vault server, such as "https://vault.vault.svc:8200". It must be one of the
+	// Vault servers allowed by the controller configuration.
+	//
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Address string `json:"address"`
+
+	// Namespace is the Vault Enterprise namespace of the secret.
+	//
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+
+	// Auth specifies how the controller authenticates to Vault.
+	Auth VaultAuth `json:"auth"`
+
+	// KV reads the credentials from a secret of a KV version 2 secrets engine.
+	//
+	// +optional
+	KV *VaultKVSecret `json:"kv,omitempty"`
+
+	// Dynamic reads the credentials from a dynamic secrets engine, such as the AWS secrets engine. The lease of the
+	// credentials is renewed before it expires, and new credentials are read once it cannot be renewed anymore.
+	//
+	// +optional
+	Dynamic *VaultDynamicSecret `json:"dynamic,omitempty"`
+
+	// Credential specifies how the data of the secret is used to access the backend.
+	Credential VaultCredential `json:"credential"`
+}
+
+// VaultAuth specifies how to authenticate to Vault. Exactly one of the fields must be set.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.tokenSecretRef) != has(self.kubernetes)",message="Exactly one of tokenSecretRef or kubernetes must be specified"
+type VaultAuth struct {
+	// TokenSecretRef is the reference to the secret containing a Vault token.
+	// ai-gateway must be given the permission to read this secret.
+	// The key of the secret should be "token".
+	// A secret in another namespace must be granted to the BackendSecurityPolicy with a ReferenceGrant.
+	//
+	// +optional
+	TokenSecretRef *gwapiv1.SecretObjectReference `json:"tokenSecretRef,omitempty"`
+
+	// Kubernetes authenticates with the Kubernetes auth method of Vault, using a service account token of the
+	// controller projected with the dedicated audience of the Vault roles.
+	//
+	// +optional
+	Kubernetes *VaultKubernetesAuth `json:"kubernetes,omitempty"`
+}
+
+// VaultKubernetesAuth specifies the Kubernetes auth method of Vault.
+type VaultKubernetesAuth struct {
+	// Role is the Vault role bound to the service account of the controller. It must be one of the roles allowed for
+	// the namespace of the BackendSecurityPolicy by the controller configuration.
+	//
+	// +kubebuilder:validation:MinLength=1
+	Role string `json:"role"`
+
+	// MountPath is the path the Kubernetes auth method is mounted at. Defaults to "kubernetes".
+	//
+	// +optional
+	// +kubebuilder:default=kubernetes
+	MountPath string `json:"mountPath,omitempty"`
+}
+
+// VaultKVSecret specifies a secret of a KV version 2 secrets engine.
+type VaultKVSecret struct {
+	// MountPath is the path the secrets engine is mounted at. Defaults to "secret".
+	//
+	// +optional
+	// +kubebuilder:default=secret
+	MountPath string `json:"mountPath,omitempty"`
+
+	// Path is the path of the secret in the secrets engine, such as "ai/openai".
+	//
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+
+	// RefreshInterval is how often the secret is read again, so that its new versions are used. Defaults to 5m.
+	//
+	// +optional
+	// +kubebuilder:default="5m"
+	RefreshInterval *gwapiv1.Duration `json:"refreshInterval,omitempty"`
+}
+
+// VaultDynamicSecret specifies a secret generated by a dynamic secrets engine.
+type VaultDynamicSecret struct {
+	// Path is the path generating the credentials, such as "aws/creds/bedrock".
+	//
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+}
+
+// VaultCredentialType specifies the type of the credentials read from Vault.
+//
+// +kubebuilder:validation:Enum=APIKey;AzureAPIKey;AnthropicAPIKey;AWSCredentials
+type VaultCredentialType string
+
+const (
+	// VaultCredentialTypeAPIKey injects the API key into the Authorization header, like the APIKey type.
+	VaultCredentialTypeAPIKey VaultCredentialType = "APIKey"
+	// VaultCredentialTypeAzureAPIKey injects the API key into the api-key header, like the AzureAPIKey type.
+	VaultCredentialTypeAzureAPIKey VaultCredentialType = "AzureAPIKey"
+	// VaultCredentialTypeAnthropicAPIKey injects the API key into the x-api-key header, like the AnthropicAPIKey type.
+	VaultCredentialTypeAnthropicAPIKey VaultCredentialType = "AnthropicAPIKey" // #nosec G101
+	// VaultCredentialTypeAWSCredentials signs the requests with the AWS credentials, like the AWSCredentials type.
+	VaultCredentialTypeAWSCredentials VaultCredentialType = "AWSCredentials"
+)
+
+// VaultCredential specifies how the data of a Vault secret is used to access a backend.
+type VaultCredential struct {
+	// Type is the type of the credentials.
+	Type VaultCredentialType `json:"type"`
+
+	// Key is the key of the data of the secret holding the API key. Defaults to "apiKey".
+	//
+	// The AWS credentials are read from the "access_key", "secret_key" and "security_token" keys, as returned by
+	// the AWS secrets engine.
+	//
+	// +optional
+	// +kubebuilder:default=apiKey
+	Key string `json:"key,omitempty"`
+
+	// Region is the AWS region of the backend. It is required for the AWSCredentials type.
+	//
+	// +optional
+	Region *string `json:"region,omitempty"`
+}
+
+// BackendSecurityPolicyClientCertificate specifies the TLS client certificate presented to the backend.
+type BackendSecurityPolicyClientCertificate struct {
+	// SecretRef is the reference to the secret of type kubernetes.io/tls containing the client certificate chain
+	// and its private key.
+	// ai-gateway must be given the permission to read this secret.
+	// The keys of the secret should be "tls.crt" and "tls.key".
+	SecretRef *gwapiv1.SecretObjectReference `json:"secretRef"`
+}
+
+// RequestSigningScheme is the scheme of the signature of the requests.
+type RequestSigningScheme string
+
+const (
+	// RequestSigningSchemeHMACSHA256 signs the requests with HMAC-SHA256 as an HTTP signature
+	// (https://datatracker.ietf.org/doc/html/draft-cavage-http-signatures-12) in the Authorization header:
+	//
+	//	Authorization: Signature keyId="<keyID>",algorithm="hmac-sha256",headers="<signed headers>",signature="<signature>"
+	//
+	// The "date" header is set to the current time when absent, and the "digest" header holds the SHA-256 digest
+	// of the body.
+	RequestSigningSchemeHMACSHA256 RequestSigningScheme = "HMAC-SHA256"
+	// RequestSigningSchemeOCI signs the requests with an RSA key as the Oracle Cloud Infrastructure (OCI) API
+	// request signature (https://docs.oracle.com/en-us/iaas/Content/API/Concepts/signingrequests.htm).
+	// The key ID is "<tenancy OCID>/<user OCID>/<key fingerprint>" and the signing key is the PEM encoded private key
+	// of the API key. The "date", "x-content-sha256", "content-type" and "content-length" headers are set as needed.
+	// This is the scheme to use with the OCIGenAI API schema.
+	RequestSigningSchemeOCI RequestSigningScheme = "OCI"
+)
+
+// BackendSecurityPolicyRequestSigning specifies the key and the scheme to sign the requests with.
+type BackendSecurityPolicyRequestSigning struct {
+	// Scheme is the scheme of the signature.
+	//
+	// +kubebuilder:validation:Enum=HMAC-SHA256;OCI
+	Scheme RequestSigningScheme `json:"scheme"`
+
+	// KeyID is the identifier of the key sent along the signature, so that the backend finds the key to verify it.
+	//
+	// +kubebuilder:validation:MinLength=1
+	KeyID string `json:"keyID"`
+
+	// SecretRef is the reference to the secret containing the signing key.
+	// ai-gateway must be given the permission to read this secret.
+	// The key of the secret should be "signingKey".
+	SecretRef *gwapiv1.SecretObjectReference `json:"secretRef"`
+
+	// SignedHeaders are the names of the headers included in the signature, in order. The pseudo header
+	// "(request-target)" stands for the method and the path of the request.
+	// Defaults to "(request-target)", "host", "date" and "digest" for the HMAC-SHA256 scheme, and to the headers
+	// required by OCI for the OCI scheme.
+	//
+	// +optional
+	// +kubebuilder:validation:MaxItems=32
+	SignedHeaders []string `json:"signedHeaders,omitempty"`
+}
@@ -23,6 +23,7 @@ type AigatewayV1alpha1Interface interface {
 	GatewayConfigsGetter
 	MCPRoutesGetter
 	QuotaPoliciesGetter
+	VirtualKeysGetter
 }
 
 // AigatewayV1alpha1Client is used to interact with features provided by the aigateway.envoyproxy.io group.
@@ -54,6 +55,10 @@ func (c *AigatewayV1alpha1Client) QuotaPolicies(namespace string) QuotaPolicyInt
 	return newQuotaPolicies(c, namespace)
 }
 
+func (c *AigatewayV1alpha1Client) VirtualKeys(namespace string) VirtualKeyInterface {
+	return newVirtualKeys(c, namespace)
+}
+
 // NewForConfig creates a new AigatewayV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
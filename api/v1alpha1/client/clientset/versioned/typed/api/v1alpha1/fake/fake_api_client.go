@@ -41,6 +41,10 @@ func (c *FakeAigatewayV1alpha1) QuotaPolicies(namespace string) v1alpha1.QuotaPo
 	return newFakeQuotaPolicies(c, namespace)
 }
 
+func (c *FakeAigatewayV1alpha1) VirtualKeys(namespace string) v1alpha1.VirtualKeyInterface {
+	return newFakeVirtualKeys(c, namespace)
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeAigatewayV1alpha1) RESTClient() rest.Interface {
@@ -18,3 +18,5 @@ type GatewayConfigExpansion interface{}
 type MCPRouteExpansion interface{}
 
 type QuotaPolicyExpansion interface{}
+
+type VirtualKeyExpansion interface{}
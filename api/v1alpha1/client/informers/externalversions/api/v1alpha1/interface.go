@@ -25,6 +25,8 @@ type Interface interface {
 	MCPRoutes() MCPRouteInformer
 	// QuotaPolicies returns a QuotaPolicyInformer.
 	QuotaPolicies() QuotaPolicyInformer
+	// VirtualKeys returns a VirtualKeyInformer.
+	VirtualKeys() VirtualKeyInformer
 }
 
 type version struct {
@@ -67,3 +69,8 @@ func (v *version) MCPRoutes() MCPRouteInformer {
 func (v *version) QuotaPolicies() QuotaPolicyInformer {
 	return &quotaPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// VirtualKeys returns a VirtualKeyInformer.
+func (v *version) VirtualKeys() VirtualKeyInformer {
+	return &virtualKeyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
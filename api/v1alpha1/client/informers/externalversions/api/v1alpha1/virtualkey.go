@@ -0,0 +1,91 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	context "context"
+	time "time"
+
+	aigatewayapiv1alpha1 "github.com/envoyproxy/ai-gateway/api/v1alpha1"
+	versioned "github.com/envoyproxy/ai-gateway/api/v1alpha1/client/clientset/versioned"
+	internalinterfaces "github.com/envoyproxy/ai-gateway/api/v1alpha1/client/informers/externalversions/internalinterfaces"
+	apiv1alpha1 "github.com/envoyproxy/ai-gateway/api/v1alpha1/client/listers/api/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// VirtualKeyInformer provides access to a shared informer and lister for
+// VirtualKeys.
+type VirtualKeyInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() apiv1alpha1.VirtualKeyLister
+}
+
+type virtualKeyInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewVirtualKeyInformer constructs a new informer for VirtualKey type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewVirtualKeyInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredVirtualKeyInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredVirtualKeyInformer constructs a new informer for VirtualKey type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredVirtualKeyInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		cache.ToListWatcherWithWatchListSemantics(&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.AigatewayV1alpha1().VirtualKeys(namespace).List(context.Background(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.AigatewayV1alpha1().VirtualKeys(namespace).Watch(context.Background(), options)
+			},
+			ListWithContextFunc: func(ctx context.Context, options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.AigatewayV1alpha1().VirtualKeys(namespace).List(ctx, options)
+			},
+			WatchFuncWithContext: func(ctx context.Context, options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.AigatewayV1alpha1().VirtualKeys(namespace).Watch(ctx, options)
+			},
+		}, client),
+		&aigatewayapiv1alpha1.VirtualKey{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *virtualKeyInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredVirtualKeyInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *virtualKeyInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&aigatewayapiv1alpha1.VirtualKey{}, f.defaultInformer)
+}
+
+func (f *virtualKeyInformer) Lister() apiv1alpha1.VirtualKeyLister {
+	return apiv1alpha1.NewVirtualKeyLister(f.Informer().GetIndexer())
+}
@@ -54,6 +54,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Aigateway().V1alpha1().MCPRoutes().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("quotapolicies"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Aigateway().V1alpha1().QuotaPolicies().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("virtualkeys"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Aigateway().V1alpha1().VirtualKeys().Informer()}, nil
 
 	}
 
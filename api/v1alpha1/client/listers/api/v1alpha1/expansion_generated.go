@@ -54,3 +54,11 @@ type QuotaPolicyListerExpansion interface{}
 // QuotaPolicyNamespaceListerExpansion allows custom methods to be added to
 // QuotaPolicyNamespaceLister.
 type QuotaPolicyNamespaceListerExpansion interface{}
+
+// VirtualKeyListerExpansion allows custom methods to be added to
+// VirtualKeyLister.
+type VirtualKeyListerExpansion interface{}
+
+// VirtualKeyNamespaceListerExpansion allows custom methods to be added to
+// VirtualKeyNamespaceLister.
+type VirtualKeyNamespaceListerExpansion interface{}
@@ -7,6 +7,7 @@ package v1alpha1
 
 import (
 	egv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
@@ -42,6 +43,8 @@ type MCPRouteList struct {
 }
 
 // MCPRouteSpec details the MCPRoute configuration.
+//
+// +kubebuilder:validation:XValidation:rule="!self.backendRefs.exists(b, has(b.securityPolicy) && (has(b.securityPolicy.tokenExchange) || has(b.securityPolicy.userCredentials))) || (has(self.securityPolicy) && has(self.securityPolicy.oauth))", message="securityPolicy.oauth must be configured when a backend uses tokenExchange or userCredentials"
 type MCPRouteSpec struct {
 	// ParentRefs are the names of the Gateway resources this MCPRoute is being attached to.
 	// Cross namespace references are not supported. In other words, the Gateway resources must be in the
@@ -91,10 +94,177 @@ type MCPRouteSpec struct {
 	// +kubebuilder:validation:Optional
 	// +optional
 	SecurityPolicy *MCPRouteSecurityPolicy `json:"securityPolicy,omitempty"`
+
+	// RateLimit defines the rate and concurrency limits applied to the tool calls made through this MCPRoute.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	RateLimit *MCPRouteRateLimit `json:"rateLimit,omitempty"`
+
+	// LegacySSE additionally serves this MCPRoute over the deprecated HTTP+SSE transport of the MCP
+	// specification 2024-11-05, for the clients that don't support the Streamable HTTP transport yet.
+	//
+	// The legacy transport keeps the state of each session in memory, so the message requests of a session must
+	// reach the same Envoy instance as its SSE stream.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	LegacySSE *MCPRouteLegacySSE `json:"legacySSE,omitempty"`
+
+	// ToolValidation configures the validation of the tool calls made through this MCPRoute against the JSON
+	// schemas of the tools, as advertised by the backends in their last tools/list responses.
+	//
+	// If not specified, the arguments of the tool calls are validated and the tool results are not.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	ToolValidation *MCPRouteToolValidation `json:"toolValidation,omitempty"`
+
+	// ToolSearch enables the tool search mode of this MCPRoute. In this mode, tools/list returns a small set of
+	// meta-tools that let the clients search the tools of all the backends, describe them and call them, instead
+	// of the full list of tools, which can exceed the context window of the clients when many backends are
+	// aggregated.
+	//
+	// The tools can still be called directly with tools/call using their prefixed names.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	ToolSearch *MCPRouteToolSearch `json:"toolSearch,omitempty"`
+
+	// HealthCheck enables the health checking of the backends of this MCPRoute.
+	//
+	// The backends are actively probed with MCP pings, and the backends failing consecutive probes or requests are
+	// ejected for a while: they are left out of the new sessions and of the aggregated list results such as
+	// tools/list, and the tool calls to them are rejected without waiting for them. The clients with an open
+	// notification stream receive a notifications/tools/list_changed notification when a backend is ejected or
+	// recovers.
+	//
+	// If not specified, the backends are always considered healthy.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	HealthCheck *MCPRouteHealthCheck `json:"healthCheck,omitempty"`
+}
+
+// MCPRouteHealthCheck configures the health checking of the backends of an MCPRoute.
+//
+// The health of the backends is tracked by each AI Gateway instance independently.
+type MCPRouteHealthCheck struct {
+	// Interval is the time between two active probes of a backend. Each probe initializes an MCP session with the
+	// backend and sends a ping request.
+	//
+	// The backends with a per-user security policy are not actively probed, as the probes are not made on behalf of
+	// a user. They are only ejected based on the results of the requests of the clients.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="10s"
+	// +optional
+	Interval *gwapiv1.Duration `json:"interval,omitempty"`
+
+	// Timeout is the maximum time to wait for an active probe to complete before it is considered failed.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="5s"
+	// +optional
+	Timeout *gwapiv1.Duration `json:"timeout,omitempty"`
+
+	// UnhealthyThreshold is the number of consecutive failed probes or requests after which a backend is ejected.
+	// A request fails when the backend cannot be reached or responds with a 5xx status code.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=3
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	UnhealthyThreshold *int32 `json:"unhealthyThreshold,omitempty"`
+
+	// EjectionTime is the minimum time a backend stays ejected. After that, the backend recovers as soon as a probe
+	// or a request to it succeeds, and is ejected again for the same time if it fails.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="30s"
+	// +optional
+	EjectionTime *gwapiv1.Duration `json:"ejectionTime,omitempty"`
+}
+
+// MCPRouteToolSearch configures the tool search mode of an MCPRoute.
+//
+// The tools/list response then contains the following meta-tools:
+//   - search_tools returns the tools whose name and description best match a keyword query, ranked with BM25.
+//   - describe_tool returns the full definition of a tool, including its input schema.
+//   - call_tool calls a tool with the given arguments.
+//
+// The search index is built from the tools/list responses of the backends filtered by the tool selectors of the
+// MCPRoute, and shared by the sessions of the MCPRoute. It is rebuilt every 5 minutes, and when a backend sends a
+// notifications/tools/list_changed notification, is ejected or recovers. The authorization rules of the MCPRoute
+// are applied to the results of each search.
+type MCPRouteToolSearch struct {
+	// MaxResults is the maximum number of tools returned by search_tools.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxResults *int32 `json:"maxResults,omitempty"`
+}
+
+// MCPRouteToolValidation defines which parts of the tool calls are validated against the schemas of the tools.
+//
+// The schemas are learned from the tools/list responses of the backends, so a tool call is not validated
+// if its tool has not been listed yet by the AI Gateway instance handling the call.
+type MCPRouteToolValidation struct {
+	// Arguments enables the validation of the arguments of the tool calls against the inputSchema of the tools.
+	// The calls with invalid arguments are rejected with a JSON-RPC invalid params error without reaching the
+	// backends.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=true
+	// +optional
+	Arguments *bool `json:"arguments,omitempty"`
+
+	// StructuredContent enables the validation of the structured content of the tool results against the
+	// outputSchema of the tools. The results that don't conform to the schema are replaced with a JSON-RPC
+	// internal error.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=false
+	// +optional
+	StructuredContent *bool `json:"structuredContent,omitempty"`
+}
+
+// MCPRouteLegacySSE defines the endpoints of the legacy HTTP+SSE transport.
+//
+// +kubebuilder:validation:XValidation:rule="self.ssePath != self.messagePath", message="ssePath and messagePath must be different"
+type MCPRouteLegacySSE struct {
+	// SSEPath is the path of the endpoint the clients open the SSE stream with.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=/sse
+	// +kubebuilder:validation:MaxLength=1024
+	// +optional
+	SSEPath string `json:"ssePath,omitempty"`
+
+	// MessagePath is the path of the endpoint the clients send their messages to.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=/messages
+	// +kubebuilder:validation:MaxLength=1024
+	// +optional
+	MessagePath string `json:"messagePath,omitempty"`
 }
 
 // MCPRouteBackendRef wraps a EG's BackendObjectReference to reference an MCP server.
 // TODO: move to a standalone MCPBackend CRD to avoid k8s object size limit.
+//
+// +kubebuilder:validation:XValidation:rule="!has(self.stdio) || !has(self.securityPolicy)", message="securityPolicy cannot be used with stdio"
+// +kubebuilder:validation:XValidation:rule="!has(self.stdio) || !has(self.transport) || self.transport != 'SSE'", message="transport cannot be SSE with stdio"
+// +kubebuilder:validation:XValidation:rule="!has(self.aiGatewayRoute) || !has(self.stdio)", message="aiGatewayRoute and stdio are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!has(self.aiGatewayRoute) || !has(self.securityPolicy)", message="securityPolicy cannot be used with aiGatewayRoute"
+// +kubebuilder:validation:XValidation:rule="!has(self.aiGatewayRoute) || !has(self.transport) || self.transport != 'SSE'", message="transport cannot be SSE with aiGatewayRoute"
+// +kubebuilder:validation:XValidation:rule="!has(self.openAPI) || (!has(self.stdio) && !has(self.aiGatewayRoute))", message="openAPI, stdio and aiGatewayRoute are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!has(self.openAPI) || !has(self.transport) || self.transport != 'SSE'", message="transport cannot be SSE with openAPI"
+// +kubebuilder:validation:XValidation:rule="!has(self.openAPI) || !has(self.securityPolicy) || !has(self.securityPolicy.apiKey) || !has(self.securityPolicy.apiKey.queryParam)", message="apiKey.queryParam cannot be used with openAPI"
 type MCPRouteBackendRef struct {
 	gwapiv1.BackendObjectReference `json:",inline"`
 
@@ -107,6 +277,17 @@ type MCPRouteBackendRef struct {
 	// +optional
 	Path *string `json:"path,omitempty"`
 
+	// Transport is the MCP transport spoken by the backend MCP server.
+	//
+	// With the SSE transport, Path is the SSE endpoint of the server. The AI Gateway keeps the SSE stream of each
+	// MCP session open and bridges it to the Streamable HTTP transport, so the server can be aggregated with the others.
+	// An API key configured with a query parameter is only sent with the request opening the SSE stream.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=StreamableHTTP
+	// +optional
+	Transport MCPBackendTransport `json:"transport,omitempty"`
+
 	// ToolSelector filters the tools exposed by this MCP server.
 	// Supports exact matches and RE2-compatible regular expressions for both include and exclude patterns.
 	// If not specified, all tools from the MCP server are exposed.
@@ -132,6 +313,349 @@ type MCPRouteBackendRef struct {
 	// +kubebuilder:validation:MaxItems=32
 	// +optional
 	ForwardHeaders []MCPHeaderForward `json:"forwardHeaders,omitempty"`
+
+	// Stdio configures this backend as a stdio MCP server launched and supervised by the AI Gateway
+	// next to the external processor, instead of a remote MCP server.
+	//
+	// When specified, the name of the reference is used as the backend name, and the group, kind,
+	// namespace, port and path of the reference are ignored. The requests to a stdio MCP server
+	// don't go through the Envoy proxy, so Envoy filters are not applied to them.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Stdio *MCPStdioServer `json:"stdio,omitempty"`
+
+	// ServerRequests controls the sampling and elicitation requests this MCP server sends to the clients.
+	//
+	// If not specified, the requests are forwarded to the clients as-is.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	ServerRequests *MCPBackendServerRequests `json:"serverRequests,omitempty"`
+
+	// AIGatewayRoute configures this backend to expose the models of an AIGatewayRoute as MCP tools and prompts,
+	// instead of a remote MCP server. This lets the agents delegate to other models through MCP alone.
+	//
+	// When specified, the name of the reference is used as the backend name, and the group, kind, namespace, port
+	// and path of the reference are ignored. The tools are served by the AI Gateway itself, and their calls are sent
+	// as OpenAI requests to the AIGatewayRoute through the Envoy proxy, so they are translated, authenticated, rate
+	// limited and accounted with the LLMRequestCosts of the route like any other request to it.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	AIGatewayRoute *MCPAIGatewayRouteBackend `json:"aiGatewayRoute,omitempty"`
+
+	// OpenAPI configures this backend as a REST API described by an OpenAPI 3 document, whose operations are
+	// exposed as MCP tools named after their operationId, instead of an MCP server.
+	//
+	// The tool calls are translated by the AI Gateway to HTTP requests sent to the referenced backend through the
+	// Envoy proxy, with the credentials of the SecurityPolicy. The path of the reference is ignored: the requests are
+	// sent to the paths of the operations, prefixed with the path of the first server of the document. The
+	// ToolSelector can be used to select the exposed operations.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	OpenAPI *MCPOpenAPIBackend `json:"openAPI,omitempty"`
+}
+
+// MCPOpenAPIBackend defines the OpenAPI document of a REST API exposed as MCP tools.
+//
+// The input schema of each tool is derived from the path, query and header parameters of the operation, and from
+// its JSON request body as the "body" argument. The JSON responses are returned as structured content.
+//
+// The document is loaded by the AI Gateway controller when the MCPRoute is reconciled. The document referenced by URL
+// is fetched by the controller in the background, and fetched again every 5 minutes. The backend has no tools until
+// it is fetched, and keeps the last document fetched if it cannot be fetched again.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.configMapRef) != has(self.url)", message="exactly one of configMapRef or url must be set"
+type MCPOpenAPIBackend struct {
+	// ConfigMapRef references the key of a ConfigMap in the same namespace as the MCPRoute holding the OpenAPI
+	// document, in JSON or YAML.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	ConfigMapRef *MCPOpenAPIConfigMapKeyReference `json:"configMapRef,omitempty"`
+
+	// URL is the HTTP or HTTPS URL the OpenAPI document is fetched from.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +kubebuilder:validation:MaxLength=2048
+	// +optional
+	URL *string `json:"url,omitempty"`
+}
+
+// MCPOpenAPIConfigMapKeyReference references a key of a ConfigMap.
+type MCPOpenAPIConfigMapKeyReference struct {
+	// Name is the name of the ConfigMap.
+	//
+	// +kubebuilder:validation:Required
+	Name gwapiv1.ObjectName `json:"name"`
+
+	// Key is the key of the ConfigMap holding the OpenAPI document.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="openapi.yaml"
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// MCPBackendServerRequests controls the requests a backend MCP server sends to the clients.
+//
+// The requests denied by the policy are answered by the AI Gateway with a JSON-RPC error without reaching the
+// clients, so that an untrusted MCP server cannot use them to harvest the input of the users.
+type MCPBackendServerRequests struct {
+	// Sampling is the policy of the sampling/createMessage requests, which ask the client to sample an LLM.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Sampling *MCPSamplingPolicy `json:"sampling,omitempty"`
+
+	// Elicitation is the policy of the elicitation/create requests, which ask the user of the client for input.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Elicitation *MCPElicitationPolicy `json:"elicitation,omitempty"`
+}
+
+// MCPServerRequestAction is the action taken on a server->client request.
+//
+// +kubebuilder:validation:Enum=Allow;Deny;Audit
+type MCPServerRequestAction string
+
+const (
+	// MCPServerRequestActionAllow forwards the requests to the clients.
+	MCPServerRequestActionAllow MCPServerRequestAction = "Allow"
+	// MCPServerRequestActionDeny answers the requests with a JSON-RPC error without forwarding them to the clients.
+	MCPServerRequestActionDeny MCPServerRequestAction = "Deny"
+	// MCPServerRequestActionAudit forwards the requests to the clients and records them in the MCP audit log.
+	// The requests are denied when the audit log is not enabled.
+	MCPServerRequestActionAudit MCPServerRequestAction = "Audit"
+)
+
+// MCPSamplingPolicy is the policy of the sampling/createMessage requests of a backend MCP server.
+type MCPSamplingPolicy struct {
+	// Action is the action taken on the sampling requests.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Allow
+	// +optional
+	Action MCPServerRequestAction `json:"action,omitempty"`
+
+	// MaxTokens caps the maxTokens of the sampling requests. The requests asking for more tokens are forwarded
+	// with this value instead.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxTokens *int32 `json:"maxTokens,omitempty"`
+
+	// IncludeContext is the widest context the sampling requests can ask the client to include in the prompt,
+	// from the narrowest to the widest: "none", "thisServer" and "allServers". The requests asking for a wider
+	// context are forwarded with this value instead.
+	//
+	// If not specified, the context requested by the server is forwarded as-is.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=none;thisServer;allServers
+	// +optional
+	IncludeContext *string `json:"includeContext,omitempty"`
+}
+
+// MCPElicitationPolicy is the policy of the elicitation/create requests of a backend MCP server.
+type MCPElicitationPolicy struct {
+	// Action is the action taken on the elicitation requests.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Allow
+	// +optional
+	Action MCPServerRequestAction `json:"action,omitempty"`
+
+	// DeniedFields is the list of sensitive fields that cannot be requested from the users. An elicitation request
+	// is denied when the name or the title of a property of its requested schema contains one of these values,
+	// ignoring the case, spaces, dashes and underscores. For example, "apiKey" matches the "api_key" property.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:={"password","passwd","passphrase","secret","token","apikey","credential","privatekey","ssn","creditcard","cvv"}
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	DeniedFields []string `json:"deniedFields,omitempty"`
+}
+
+// MCPBackendTransport is the MCP transport spoken by a backend MCP server.
+//
+// +kubebuilder:validation:Enum=StreamableHTTP;SSE
+type MCPBackendTransport string
+
+const (
+	// MCPBackendTransportStreamableHTTP is the Streamable HTTP transport of the MCP specification 2025-03-26 and later.
+	MCPBackendTransportStreamableHTTP MCPBackendTransport = "StreamableHTTP"
+	// MCPBackendTransportSSE is the deprecated HTTP+SSE transport of the MCP specification 2024-11-05.
+	MCPBackendTransportSSE MCPBackendTransport = "SSE"
+)
+
+// MCPAIGatewayRouteBackend defines the models of an AIGatewayRoute exposed as MCP tools and prompts.
+//
+// The following tools are synthesized:
+//   - ask_<model> for each chat model, sending a chat completion request with the given prompt to the model.
+//   - embed_text, sending an embeddings request with the given texts to the EmbeddingModel.
+//   - generate_image, sending an image generation request with the given prompt to the ImageModel.
+//
+// Each chat model is also exposed as the ask_<model> prompt, whose messages are the given prompt and the answer of
+// the model. The characters of the model names that are not allowed in the tool names are replaced by underscores.
+type MCPAIGatewayRouteBackend struct {
+	// Name is the name of the AIGatewayRoute in the same namespace as the MCPRoute.
+	// The AIGatewayRoute must be attached to an HTTP or HTTPS listener of a Gateway the MCPRoute is attached to.
+	//
+	// +kubebuilder:validation:Required
+	Name gwapiv1.ObjectName `json:"name"`
+
+	// ChatModels are the models exposed as the ask_<model> tools and prompts.
+	//
+	// If not specified, all the models declared by the rules of the AIGatewayRoute with an exact match on the
+	// x-ai-eg-model header are exposed, except the EmbeddingModel and the ImageModel.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	ChatModels []string `json:"chatModels,omitempty"`
+
+	// EmbeddingModel is the model used by the embed_text tool. If not specified, the tool is not exposed.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	EmbeddingModel *string `json:"embeddingModel,omitempty"`
+
+	// ImageModel is the model used by the generate_image tool. If not specified, the tool is not exposed.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	ImageModel *string `json:"imageModel,omitempty"`
+}
+
+// MCPStdioServer defines a stdio MCP server launched by the AI Gateway.
+//
+// The server is run as a child process of the AI Gateway external processor, so the command must be available in
+// the external processor container image. The image can be customized with the GatewayConfig resource.
+//
+// The command runs with the same privileges as the external processor, so it can read the credentials and the
+// filter configuration of the Gateway. Stdio MCP servers are therefore disabled unless the controller is started
+// with the --enableMCPStdioServers flag, which must only be set when every author of MCPRoutes is trusted to run
+// arbitrary commands in the Gateway pods. When disabled, the MCPRoutes declaring stdio MCP servers are not accepted.
+//
+// +kubebuilder:validation:XValidation:rule="!has(self.maxProcesses) || self.isolation == 'PerSession'", message="maxProcesses can only be specified with the PerSession isolation"
+type MCPStdioServer struct {
+	// Command is the command to run the stdio MCP server.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Command string `json:"command"`
+
+	// Args are the arguments passed to the command.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Env is the list of environment variables set for the stdio MCP server.
+	// The process doesn't inherit the environment of the external processor, except for PATH and HOME.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	Env []MCPStdioEnvVar `json:"env,omitempty"`
+
+	// EnvFrom references a Secret in the same namespace as the MCPRoute whose key-value pairs are all
+	// set as environment variables of the stdio MCP server. Variables defined in Env take precedence.
+	// Cross-namespace references are not allowed: the backend is skipped if the namespace is set to another one.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	EnvFrom *gwapiv1.SecretObjectReference `json:"envFrom,omitempty"`
+
+	// Isolation defines how the stdio MCP server processes are shared across MCP sessions.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Shared
+	// +optional
+	Isolation MCPStdioIsolation `json:"isolation,omitempty"`
+
+	// MaxProcesses is the maximum number of concurrent processes with the PerSession isolation.
+	// New MCP sessions are rejected when the limit is reached. If not specified, there is no limit.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxProcesses *int32 `json:"maxProcesses,omitempty"`
+
+	// SessionIdleTimeout is the duration after which an MCP session that didn't receive any request is closed.
+	// With the PerSession isolation, this also terminates the process of the session.
+	// If not specified, idle sessions are not closed.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	SessionIdleTimeout *gwapiv1.Duration `json:"sessionIdleTimeout,omitempty"`
+
+	// Resources defines the resource limits applied to each stdio MCP server process.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Resources *MCPStdioResources `json:"resources,omitempty"`
+}
+
+// MCPStdioEnvVar defines an environment variable of a stdio MCP server.
+type MCPStdioEnvVar struct {
+	// Name is the name of the environment variable.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Value is the value of the environment variable.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+// MCPStdioIsolation defines how the stdio MCP server processes are shared across MCP sessions.
+//
+// +kubebuilder:validation:Enum=Shared;PerSession
+type MCPStdioIsolation string
+
+const (
+	// MCPStdioIsolationShared runs a single process for all the MCP sessions. The process is restarted
+	// with an exponential backoff when it exits.
+	MCPStdioIsolationShared MCPStdioIsolation = "Shared"
+	// MCPStdioIsolationPerSession runs a dedicated process for each MCP session, which is terminated
+	// when the session ends.
+	MCPStdioIsolationPerSession MCPStdioIsolation = "PerSession"
+)
+
+// MCPStdioResources defines the resource limits of a stdio MCP server process.
+//
+// The limits are enforced with Linux resource limits (rlimits) on each process, set before its command is executed.
+// The total resources of all processes are bounded by the resources of the external processor container, which can
+// be configured with the GatewayConfig resource.
+type MCPStdioResources struct {
+	// Memory is the maximum size of the virtual address space of the process.
+	// This limits the address space rather than the memory in use, so it doesn't suit the runtimes reserving large
+	// amounts of virtual memory upfront: the Node.js servers, such as the ones run with npx, fail to start even with
+	// a generous limit. Their memory is only bounded by the memory limit of the external processor container.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+
+	// CPUTime is the maximum CPU time the process can consume before it is terminated.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	CPUTime *gwapiv1.Duration `json:"cpuTime,omitempty"`
 }
 
 // MCPHeaderForward specifies a header to extract from the incoming request and forward to a backend.
@@ -192,10 +716,109 @@ type MCPToolFilter struct {
 }
 
 // MCPBackendSecurityPolicy defines the security policy for a backend MCP server.
+//
+// +kubebuilder:validation:XValidation:rule="(has(self.apiKey) ? 1 : 0) + (has(self.tokenExchange) ? 1 : 0) + (has(self.userCredentials) ? 1 : 0) <= 1", message="only one of apiKey, tokenExchange or userCredentials can be set"
 type MCPBackendSecurityPolicy struct {
 	// APIKey is a mechanism to access a backend. The API key will be injected into the request headers.
 	// +optional
 	APIKey *MCPBackendAPIKey `json:"apiKey,omitempty"`
+
+	// TokenExchange exchanges the OAuth access token of the client for a token scoped to this backend using
+	// the OAuth 2.0 Token Exchange (RFC 8693), and injects it into the requests to this backend.
+	// This lets the backend see the identity of the user without the client ever holding the backend token.
+	//
+	// The exchanged tokens are cached until they expire. The OAuth security policy of the MCPRoute must be
+	// configured so that the access token of the client is validated before being exchanged.
+	//
+	// +optional
+	TokenExchange *MCPBackendTokenExchange `json:"tokenExchange,omitempty"`
+
+	// UserCredentials injects a credential specific to each user, such as a personal access token, into the
+	// requests to this backend. The user is identified by the "sub" claim of the OAuth access token of the client,
+	// and requests of users without a credential are rejected.
+	//
+	// The OAuth security policy of the MCPRoute must be configured so that the access token of the client is
+	// validated before the user is identified.
+	//
+	// +optional
+	UserCredentials *MCPBackendUserCredentials `json:"userCredentials,omitempty"`
+}
+
+// MCPBackendTokenExchange defines the OAuth 2.0 Token Exchange (RFC 8693) of the access token of the client for a
+// token scoped to a backend.
+type MCPBackendTokenExchange struct {
+	// TokenEndpoint is the URL of the token endpoint of the authorization server performing the exchange.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https?://`
+	TokenEndpoint string `json:"tokenEndpoint"`
+
+	// ClientID is the OAuth client ID the AI Gateway authenticates to the token endpoint with.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ClientID string `json:"clientID"`
+
+	// ClientSecretRef is the Kubernetes secret which contains the OAuth client secret in the "client-secret" key.
+	// The client authenticates with HTTP Basic authentication. If not specified, the client ID is sent in the
+	// request body as a public client.
+	//
+	// +optional
+	ClientSecretRef *gwapiv1.SecretObjectReference `json:"clientSecretRef,omitempty"`
+
+	// Audience is the logical name of the backend the token is requested for.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Audience *string `json:"audience,omitempty"`
+
+	// Resource is the URI of the backend the token is requested for.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Resource *string `json:"resource,omitempty"`
+
+	// Scopes is the list of scopes requested for the token.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=32
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
+
+	// RequestedTokenType is the type of the requested token. If not specified, defaults to
+	// "urn:ietf:params:oauth:token-type:access_token".
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	RequestedTokenType *string `json:"requestedTokenType,omitempty"`
+
+	// Header is the HTTP header to inject the exchanged token into. If not specified, defaults to "Authorization".
+	// When the header is "Authorization", the injected header value will be prefixed with "Bearer ".
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Header *string `json:"header,omitempty"`
+}
+
+// MCPBackendUserCredentials defines the credentials of each user for a backend.
+type MCPBackendUserCredentials struct {
+	// SecretRef is the Kubernetes secret which contains the credentials of the users. Each key of the secret is
+	// the subject of a user, i.e. the "sub" claim of their access token, and its value is the credential of that user.
+	//
+	// +kubebuilder:validation:Required
+	SecretRef gwapiv1.SecretObjectReference `json:"secretRef"`
+
+	// Header is the HTTP header to inject the credential into. If not specified, defaults to "Authorization".
+	// When the header is "Authorization", the injected header value will be prefixed with "Bearer ".
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Header *string `json:"header,omitempty"`
 }
 
 // MCPBackendAPIKey defines the configuration for the API Key Authentication to a backend.
@@ -510,3 +1133,115 @@ type ProtectedResourceMetadata struct {
 	// +optional
 	ResourcePolicyURI *string `json:"resourcePolicyUri,omitempty"`
 }
+
+// MCPRouteRateLimit defines the rate and concurrency limits for the tool calls of a MCPRoute.
+//
+// The limits are enforced locally by each AI Gateway external processor instance. In other words, when the Gateway
+// runs with multiple Envoy replicas, the effective limit is the configured limit multiplied by the number of replicas.
+type MCPRouteRateLimit struct {
+	// Rules is the list of rate limit rules. Every rule matching a tool call is applied, and the
+	// call is rejected if any of the matching rules is exceeded.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=32
+	Rules []MCPRateLimitRule `json:"rules"`
+}
+
+// MCPRateLimitRule defines a single rate and/or concurrency limit for tool calls.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.requests) || has(self.maxConcurrency)", message="at least one of requests or maxConcurrency must be specified"
+// +kubebuilder:validation:XValidation:rule="!has(self.tool) || has(self.backend)", message="backend must be specified when tool is specified"
+type MCPRateLimitRule struct {
+	// Backend is the name of the backend this rule applies to.
+	// If not specified, the rule applies to the tool calls to all backends of the MCPRoute.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Backend *string `json:"backend,omitempty"`
+
+	// Tool is the name of the tool this rule applies to, without the backend prefix.
+	// If not specified, the rule applies to all tools of the backend. Tool requires Backend to be set.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Tool *string `json:"tool,omitempty"`
+
+	// ClientKey specifies how tool calls are grouped into per-client limits.
+	// If not specified, all tool calls matching this rule share the same limit.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	ClientKey *MCPRateLimitClientKey `json:"clientKey,omitempty"`
+
+	// Requests is the maximum number of tool calls allowed per time unit.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Requests *MCPRateLimitValue `json:"requests,omitempty"`
+
+	// MaxConcurrency is the maximum number of tool calls that can be in flight at the same time.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrency *uint32 `json:"maxConcurrency,omitempty"`
+}
+
+// MCPRateLimitClientKeyType specifies the source of the client key for MCP rate limiting.
+//
+// +kubebuilder:validation:Enum=Subject;Header
+type MCPRateLimitClientKeyType string
+
+const (
+	// MCPRateLimitClientKeyTypeSubject uses the "sub" claim of the client's JWT as the client key.
+	MCPRateLimitClientKeyTypeSubject MCPRateLimitClientKeyType = "Subject"
+	// MCPRateLimitClientKeyTypeHeader uses the value of a request header as the client key.
+	MCPRateLimitClientKeyTypeHeader MCPRateLimitClientKeyType = "Header"
+)
+
+// MCPRateLimitClientKey specifies how tool calls are grouped into per-client limits.
+// Requests without the configured subject or header share a single limit.
+//
+// +kubebuilder:validation:XValidation:rule="self.type == 'Header' ? has(self.header) : !has(self.header)", message="header must be specified if and only if type is Header"
+type MCPRateLimitClientKey struct {
+	// Type is the source of the client key.
+	//
+	// +kubebuilder:validation:Required
+	Type MCPRateLimitClientKeyType `json:"type"`
+
+	// Header is the name of the request header whose value is used as the client key.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Header *string `json:"header,omitempty"`
+}
+
+// MCPRateLimitUnit specifies the time unit of a rate limit.
+//
+// +kubebuilder:validation:Enum=Second;Minute;Hour;Day
+type MCPRateLimitUnit string
+
+const (
+	MCPRateLimitUnitSecond MCPRateLimitUnit = "Second"
+	MCPRateLimitUnitMinute MCPRateLimitUnit = "Minute"
+	MCPRateLimitUnitHour   MCPRateLimitUnit = "Hour"
+	MCPRateLimitUnitDay    MCPRateLimitUnit = "Day"
+)
+
+// MCPRateLimitValue defines the number of tool calls allowed per time unit.
+type MCPRateLimitValue struct {
+	// Limit is the number of tool calls allowed per Unit.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	Limit uint32 `json:"limit"`
+
+	// Unit is the time unit of the limit.
+	//
+	// +kubebuilder:validation:Required
+	Unit MCPRateLimitUnit `json:"unit"`
+}
@@ -15,14 +15,16 @@ package v1alpha1
 type VersionedAPISchema struct {
 	// Name is the name of the API schema of the AIGatewayRoute or AIServiceBackend.
 	//
-	// +kubebuilder:validation:Enum=OpenAI;Cohere;AWSBedrock;AzureOpenAI;GCPVertexAI;GCPAnthropic;Anthropic;AWSAnthropic
+	// +kubebuilder:validation:Enum=OpenAI;Cohere;AWSBedrock;AzureOpenAI;GCPVertexAI;GCPAnthropic;Anthropic;AWSAnthropic;OCIGenAI;DashScope;Ollama;HuggingFaceTGI
 	Name APISchema `json:"name"`
 
 	// Version is the version of the API schema.
 	//
 	// When the name is set to AzureOpenAI, this version maps to "API Version" in the
 	// Azure OpenAI API documentation (https://learn.microsoft.com/en-us/azure/ai-services/openai/reference#rest-api-versioning).
-	// This field is ignored for OpenAI, AWSBedrock, GCPVertexAI, and Anthropic.
+	// When the name is set to OCIGenAI, this version is the OCID of the compartment that the requests are made in,
+	// which is required by the OCI Generative AI inference API.
+	// This field is ignored for OpenAI, AWSBedrock, GCPVertexAI, Anthropic, DashScope, Ollama, and HuggingFaceTGI.
 	// For OpenAI and Anthropic, use prefix to configure custom request paths.
 	//
 	// See https://aigateway.envoyproxy.io/docs/capabilities/llm-integrations/supported-providers for details.
@@ -89,6 +91,32 @@ const (
 	// https://aws.amazon.com/bedrock/anthropic/
 	// https://docs.claude.com/en/api/claude-on-amazon-bedrock
 	APISchemaAWSAnthropic APISchema = "AWSAnthropic"
+	// APISchemaOCIGenAI is the schema of the Oracle Cloud Infrastructure (OCI) Generative AI inference API.
+	// The chat completions use the generic chat API format, and the embeddings use the embed text API.
+	// Note: Using this schema requires a RequestSigning BackendSecurityPolicy with the OCI scheme to be configured
+	// and attached, as well as the compartment OCID to be set in the version field.
+	//
+	// https://docs.oracle.com/en-us/iaas/api/#/en/generative-ai-inference/20231130/
+	APISchemaOCIGenAI APISchema = "OCIGenAI"
+	// APISchemaDashScope is the schema of the native Alibaba Cloud Model Studio (DashScope) API serving the Qwen models.
+	// The API key of Model Studio is configured with an APIKey BackendSecurityPolicy.
+	//
+	// https://www.alibabacloud.com/help/en/model-studio/qwen-api-reference
+	APISchemaDashScope APISchema = "DashScope"
+	// APISchemaOllama is the schema of the native Ollama API.
+	// The chat completions use the /api/chat endpoint, and the embeddings use the /api/embed endpoint,
+	// which, unlike the OpenAI compatible endpoints of Ollama, support the keep_alive and the options of the models.
+	//
+	// https://github.com/ollama/ollama/blob/main/docs/api.md
+	APISchemaOllama APISchema = "Ollama"
+	// APISchemaHuggingFaceTGI is the schema of the native Hugging Face Text Generation Inference (TGI) and
+	// Text Embeddings Inference (TEI) APIs.
+	// The completions use the /generate and /generate_stream endpoints of TGI, and the embeddings use the /embed
+	// endpoint of TEI. The chat completions use the OpenAI compatible Messages API of TGI at /v1/chat/completions.
+	//
+	// https://huggingface.github.io/text-generation-inference/
+	// https://huggingface.github.io/text-embeddings-inference/
+	APISchemaHuggingFaceTGI APISchema = "HuggingFaceTGI"
 )
 
 const (
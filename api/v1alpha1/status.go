@@ -14,23 +14,31 @@ const (
 	// ConditionTypeNotAccepted is a condition type for the reconciliation result
 	// where resources are not accepted.
 	ConditionTypeNotAccepted = "NotAccepted"
+	// ConditionTypeProgrammed is a condition type for whether the configuration of the resources has been
+	// applied by the external processors of the Gateways. This requires the configuration to be delivered by
+	// the config server of the controller, and is Unknown otherwise.
+	ConditionTypeProgrammed = "Programmed"
+	// ConditionTypeResolvedRefs is a condition type for whether the references of the resources, such as the
+	// credentials of the backends, have been resolved by the external processors of the Gateways. This requires
+	// the configuration to be delivered by the config server of the controller, and is Unknown otherwise.
+	ConditionTypeResolvedRefs = "ResolvedRefs"
 )
 
 // AIGatewayRouteStatus contains the conditions by the reconciliation result.
 type AIGatewayRouteStatus struct {
 	// Conditions is the list of conditions by the reconciliation result.
-	// Currently, at most one condition is set.
+	// Currently, at most one of "Accepted" and "NotAccepted" is set, along with "Programmed".
 	//
-	// Known .status.conditions.type are: "Accepted", "NotAccepted".
+	// Known .status.conditions.type are: "Accepted", "NotAccepted", "Programmed".
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // AIServiceBackendStatus contains the conditions by the reconciliation result.
 type AIServiceBackendStatus struct {
 	// Conditions is the list of conditions by the reconciliation result.
-	// Currently, at most one condition is set.
+	// Currently, at most one of "Accepted" and "NotAccepted" is set, along with "Programmed" and "ResolvedRefs".
 	//
-	// Known .status.conditions.type are: "Accepted", "NotAccepted".
+	// Known .status.conditions.type are: "Accepted", "NotAccepted", "Programmed", "ResolvedRefs".
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
@@ -0,0 +1,124 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// VirtualKey is a client API key issued by the gateway. The clients send the key in the "Authorization: Bearer"
+// or the "x-api-key" header of their requests to the AIGatewayRoutes targeted by the VirtualKey, and the gateway
+// validates it before routing the requests, so that the keys can be handed out per team or per application without
+// exposing the credentials of the AI providers.
+//
+// The key is generated by the controller and stored in the Secret named in the status of the VirtualKey, in the
+// "key" data field. Deleting the Secret rotates the key. Only the SHA-256 hash of the key is sent to the gateway.
+//
+// Once an AIGatewayRoute is targeted by a VirtualKey, the requests to the route must carry a valid virtual key.
+// The namespace/name of the VirtualKey of a request is set in the "x-ai-eg-virtual-key" request header, and each
+// of its labels in an "x-ai-eg-virtual-key-label-<name>" request header, which can be used in the client selectors
+// of the QuotaPolicies. The namespace/name is also recorded in the metrics, the traces and the access logs, and is
+// available as the "virtual_key" variable of the CEL expressions of the LLM request costs.
+//
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Secret",type=string,JSONPath=`.status.secretName`
+// +kubebuilder:printcolumn:name="Expires",type=string,JSONPath=`.spec.expiresAt`
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.conditions[-1:].type`
+type VirtualKey struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              VirtualKeySpec `json:"spec,omitempty"`
+	// Status defines the status details of the VirtualKey.
+	Status VirtualKeyStatus `json:"status,omitempty"`
+}
+
+// VirtualKeySpec specifies what the holders of a virtual key are allowed to do.
+type VirtualKeySpec struct {
+	// TargetRefs are the names of the AIGatewayRoute resources in the same namespace the key can be used with.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=16
+	// +kubebuilder:validation:XValidation:rule="self.all(ref, ref.group == 'aigateway.envoyproxy.io' && ref.kind == 'AIGatewayRoute')", message="targetRefs must reference AIGatewayRoute resources"
+	TargetRefs []gwapiv1a2.LocalPolicyTargetReference `json:"targetRefs"`
+	// AllowedModels are the names of the models the key can be used with, matched against the model of the
+	// requests. The requests for the other models are rejected with a 403 status code.
+	//
+	// When empty, the key can be used with all the models of the targeted AIGatewayRoutes.
+	//
+	// +optional
+	// +kubebuilder:validation:MaxItems=64
+	AllowedModels []string `json:"allowedModels,omitempty"`
+	// ExpiresAt is the time after which the requests using the key are rejected with a 401 status code.
+	//
+	// When unset, the key doesn't expire.
+	//
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+	// Labels are metadata about the holder of the key, for example the team or the cost center, which are set
+	// in the "x-ai-eg-virtual-key-label-<name>" request headers of the requests using the key.
+	//
+	// +optional
+	// +kubebuilder:validation:MaxProperties=16
+	// +kubebuilder:validation:XValidation:rule="self.all(k, k.matches('^[a-z0-9]([-a-z0-9]*[a-z0-9])?$'))", message="label names must be lowercase alphanumeric characters or '-', and must start and end with an alphanumeric character"
+	Labels map[string]string `json:"labels,omitempty"`
+	// Budget limits the cost of the requests using the key in a time window. The requests are rejected with a 429
+	// status code once the budget is exhausted, until the end of the window.
+	//
+	// The cost is accounted by each replica of the gateway independently, so the effective budget is multiplied
+	// by the number of replicas. Use a QuotaPolicy with the "x-ai-eg-virtual-key" header as client selector for
+	// a budget shared across the replicas.
+	//
+	// +optional
+	Budget *VirtualKeyBudget `json:"budget,omitempty"`
+}
+
+// VirtualKeyBudget specifies the cost allowed for a virtual key in a time window.
+type VirtualKeyBudget struct {
+	// Limit is the cost allowed in the window.
+	//
+	// +kubebuilder:validation:Minimum=1
+	Limit uint `json:"limit"`
+	// Window is the duration of the window. The cost is reset at the end of each window.
+	//
+	// +kubebuilder:validation:Enum="1m";"1h";"1d";"7d";"30d"
+	Window string `json:"window"`
+	// CostExpression specifies a CEL expression for computing the cost of a request, with the same variables as
+	// the CEL expressions of the LLM request costs. If no expression is specified the "total_tokens" value is used.
+	// For example:
+	//
+	//  * "input_tokens + cached_input_tokens * 0.1 + output_tokens * 6"
+	//
+	// +optional
+	CostExpression *string `json:"costExpression,omitempty"`
+}
+
+// VirtualKeyStatus contains the conditions by the reconciliation result and the Secret holding the key.
+type VirtualKeyStatus struct {
+	// Conditions is the list of conditions by the reconciliation result.
+	// Currently, at most one condition is set.
+	//
+	// Known .status.conditions.type are: "Accepted", "NotAccepted".
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// SecretName is the name of the Secret in the namespace of the VirtualKey that holds the key in its "key" data
+	// field.
+	//
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// VirtualKeyList contains a list of VirtualKey
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+type VirtualKeyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VirtualKey `json:"items"`
+}
@@ -1,8 +1,3 @@
-// Copyright Envoy AI Gateway Authors
-// SPDX-License-Identifier: Apache-2.0
-// The full text of the Apache license is available in the LICENSE file at
-// the root of the repo.
-
 //go:build !ignore_autogenerated
 
 // Code generated by controller-gen. DO NOT EDIT.
@@ -220,6 +215,11 @@ func (in *AIGatewayRouteSpec) DeepCopyInto(out *AIGatewayRouteSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ToolExecution != nil {
+		in, out := &in.ToolExecution, &out.ToolExecution
+		*out = new(AIGatewayRouteToolExecution)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AIGatewayRouteSpec.
@@ -254,6 +254,31 @@ func (in *AIGatewayRouteStatus) DeepCopy() *AIGatewayRouteStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AIGatewayRouteToolExecution) DeepCopyInto(out *AIGatewayRouteToolExecution) {
+	*out = *in
+	if in.MaxIterations != nil {
+		in, out := &in.MaxIterations, &out.MaxIterations
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AIGatewayRouteToolExecution.
+func (in *AIGatewayRouteToolExecution) DeepCopy() *AIGatewayRouteToolExecution {
+	if in == nil {
+		return nil
+	}
+	out := new(AIGatewayRouteToolExecution)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AIServiceBackend) DeepCopyInto(out *AIServiceBackend) {
 	*out = *in
@@ -398,6 +423,21 @@ func (in *AWSOIDCExchangeToken) DeepCopy() *AWSOIDCExchangeToken {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureManagedIdentity) DeepCopyInto(out *AzureManagedIdentity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureManagedIdentity.
+func (in *AzureManagedIdentity) DeepCopy() *AzureManagedIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureManagedIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureOIDCExchangeToken) DeepCopyInto(out *AzureOIDCExchangeToken) {
 	*out = *in
@@ -414,6 +454,26 @@ func (in *AzureOIDCExchangeToken) DeepCopy() *AzureOIDCExchangeToken {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureWorkloadIdentity) DeepCopyInto(out *AzureWorkloadIdentity) {
+	*out = *in
+	if in.TokenFilePath != nil {
+		in, out := &in.TokenFilePath, &out.TokenFilePath
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureWorkloadIdentity.
+func (in *AzureWorkloadIdentity) DeepCopy() *AzureWorkloadIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureWorkloadIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackendSecurityPolicy) DeepCopyInto(out *BackendSecurityPolicy) {
 	*out = *in
@@ -461,6 +521,63 @@ func (in *BackendSecurityPolicyAPIKey) DeepCopy() *BackendSecurityPolicyAPIKey {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendSecurityPolicyAPIKeyPool) DeepCopyInto(out *BackendSecurityPolicyAPIKeyPool) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]BackendSecurityPolicyAPIKeyPoolKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Header != nil {
+		in, out := &in.Header, &out.Header
+		*out = new(string)
+		**out = **in
+	}
+	if in.EjectionDuration != nil {
+		in, out := &in.EjectionDuration, &out.EjectionDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendSecurityPolicyAPIKeyPool.
+func (in *BackendSecurityPolicyAPIKeyPool) DeepCopy() *BackendSecurityPolicyAPIKeyPool {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendSecurityPolicyAPIKeyPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendSecurityPolicyAPIKeyPoolKey) DeepCopyInto(out *BackendSecurityPolicyAPIKeyPoolKey) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.SecretObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendSecurityPolicyAPIKeyPoolKey.
+func (in *BackendSecurityPolicyAPIKeyPoolKey) DeepCopy() *BackendSecurityPolicyAPIKeyPoolKey {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendSecurityPolicyAPIKeyPoolKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackendSecurityPolicyAWSCredentials) DeepCopyInto(out *BackendSecurityPolicyAWSCredentials) {
 	*out = *in
@@ -539,6 +656,16 @@ func (in *BackendSecurityPolicyAzureCredentials) DeepCopyInto(out *BackendSecuri
 		*out = new(AzureOIDCExchangeToken)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ManagedIdentity != nil {
+		in, out := &in.ManagedIdentity, &out.ManagedIdentity
+		*out = new(AzureManagedIdentity)
+		**out = **in
+	}
+	if in.WorkloadIdentity != nil {
+		in, out := &in.WorkloadIdentity, &out.WorkloadIdentity
+		*out = new(AzureWorkloadIdentity)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendSecurityPolicyAzureCredentials.
@@ -551,6 +678,26 @@ func (in *BackendSecurityPolicyAzureCredentials) DeepCopy() *BackendSecurityPoli
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendSecurityPolicyClientCertificate) DeepCopyInto(out *BackendSecurityPolicyClientCertificate) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.SecretObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendSecurityPolicyClientCertificate.
+func (in *BackendSecurityPolicyClientCertificate) DeepCopy() *BackendSecurityPolicyClientCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendSecurityPolicyClientCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackendSecurityPolicyGCPCredentials) DeepCopyInto(out *BackendSecurityPolicyGCPCredentials) {
 	*out = *in
@@ -624,6 +771,31 @@ func (in *BackendSecurityPolicyOIDC) DeepCopy() *BackendSecurityPolicyOIDC {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendSecurityPolicyRequestSigning) DeepCopyInto(out *BackendSecurityPolicyRequestSigning) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.SecretObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SignedHeaders != nil {
+		in, out := &in.SignedHeaders, &out.SignedHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendSecurityPolicyRequestSigning.
+func (in *BackendSecurityPolicyRequestSigning) DeepCopy() *BackendSecurityPolicyRequestSigning {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendSecurityPolicyRequestSigning)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackendSecurityPolicySpec) DeepCopyInto(out *BackendSecurityPolicySpec) {
 	*out = *in
@@ -662,6 +834,26 @@ func (in *BackendSecurityPolicySpec) DeepCopyInto(out *BackendSecurityPolicySpec
 		*out = new(BackendSecurityPolicyAnthropicAPIKey)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.APIKeyPool != nil {
+		in, out := &in.APIKeyPool, &out.APIKeyPool
+		*out = new(BackendSecurityPolicyAPIKeyPool)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(BackendSecurityPolicyVault)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientCertificate != nil {
+		in, out := &in.ClientCertificate, &out.ClientCertificate
+		*out = new(BackendSecurityPolicyClientCertificate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequestSigning != nil {
+		in, out := &in.RequestSigning, &out.RequestSigning
+		*out = new(BackendSecurityPolicyRequestSigning)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendSecurityPolicySpec.
@@ -696,6 +888,38 @@ func (in *BackendSecurityPolicyStatus) DeepCopy() *BackendSecurityPolicyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendSecurityPolicyVault) DeepCopyInto(out *BackendSecurityPolicyVault) {
+	*out = *in
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(string)
+		**out = **in
+	}
+	in.Auth.DeepCopyInto(&out.Auth)
+	if in.KV != nil {
+		in, out := &in.KV, &out.KV
+		*out = new(VaultKVSecret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dynamic != nil {
+		in, out := &in.Dynamic, &out.Dynamic
+		*out = new(VaultDynamicSecret)
+		**out = **in
+	}
+	in.Credential.DeepCopyInto(&out.Credential)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendSecurityPolicyVault.
+func (in *BackendSecurityPolicyVault) DeepCopy() *BackendSecurityPolicyVault {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendSecurityPolicyVault)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GCPCredentialsFile) DeepCopyInto(out *GCPCredentialsFile) {
 	*out = *in
@@ -1049,6 +1273,36 @@ func (in *LLMRequestCost) DeepCopy() *LLMRequestCost {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPAIGatewayRouteBackend) DeepCopyInto(out *MCPAIGatewayRouteBackend) {
+	*out = *in
+	if in.ChatModels != nil {
+		in, out := &in.ChatModels, &out.ChatModels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EmbeddingModel != nil {
+		in, out := &in.EmbeddingModel, &out.EmbeddingModel
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImageModel != nil {
+		in, out := &in.ImageModel, &out.ImageModel
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPAIGatewayRouteBackend.
+func (in *MCPAIGatewayRouteBackend) DeepCopy() *MCPAIGatewayRouteBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPAIGatewayRouteBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MCPAuthorizationSource) DeepCopyInto(out *MCPAuthorizationSource) {
 	*out = *in
@@ -1128,6 +1382,16 @@ func (in *MCPBackendSecurityPolicy) DeepCopyInto(out *MCPBackendSecurityPolicy)
 		*out = new(MCPBackendAPIKey)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TokenExchange != nil {
+		in, out := &in.TokenExchange, &out.TokenExchange
+		*out = new(MCPBackendTokenExchange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UserCredentials != nil {
+		in, out := &in.UserCredentials, &out.UserCredentials
+		*out = new(MCPBackendUserCredentials)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPBackendSecurityPolicy.
@@ -1141,90 +1405,316 @@ func (in *MCPBackendSecurityPolicy) DeepCopy() *MCPBackendSecurityPolicy {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPHeaderForward) DeepCopyInto(out *MCPHeaderForward) {
+func (in *MCPBackendServerRequests) DeepCopyInto(out *MCPBackendServerRequests) {
 	*out = *in
-	if in.BackendHeader != nil {
-		in, out := &in.BackendHeader, &out.BackendHeader
-		*out = new(string)
-		**out = **in
+	if in.Sampling != nil {
+		in, out := &in.Sampling, &out.Sampling
+		*out = new(MCPSamplingPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Elicitation != nil {
+		in, out := &in.Elicitation, &out.Elicitation
+		*out = new(MCPElicitationPolicy)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPHeaderForward.
-func (in *MCPHeaderForward) DeepCopy() *MCPHeaderForward {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPBackendServerRequests.
+func (in *MCPBackendServerRequests) DeepCopy() *MCPBackendServerRequests {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPHeaderForward)
+	out := new(MCPBackendServerRequests)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPRoute) DeepCopyInto(out *MCPRoute) {
+func (in *MCPBackendTokenExchange) DeepCopyInto(out *MCPBackendTokenExchange) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.ClientSecretRef != nil {
+		in, out := &in.ClientSecretRef, &out.ClientSecretRef
+		*out = new(v1.SecretObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Audience != nil {
+		in, out := &in.Audience, &out.Audience
+		*out = new(string)
+		**out = **in
+	}
+	if in.Resource != nil {
+		in, out := &in.Resource, &out.Resource
+		*out = new(string)
+		**out = **in
+	}
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequestedTokenType != nil {
+		in, out := &in.RequestedTokenType, &out.RequestedTokenType
+		*out = new(string)
+		**out = **in
+	}
+	if in.Header != nil {
+		in, out := &in.Header, &out.Header
+		*out = new(string)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPRoute.
-func (in *MCPRoute) DeepCopy() *MCPRoute {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPBackendTokenExchange.
+func (in *MCPBackendTokenExchange) DeepCopy() *MCPBackendTokenExchange {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPRoute)
+	out := new(MCPBackendTokenExchange)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MCPRoute) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPRouteAuthorization) DeepCopyInto(out *MCPRouteAuthorization) {
+func (in *MCPBackendUserCredentials) DeepCopyInto(out *MCPBackendUserCredentials) {
 	*out = *in
-	if in.DefaultAction != nil {
-		in, out := &in.DefaultAction, &out.DefaultAction
-		*out = new(apiv1alpha1.AuthorizationAction)
+	in.SecretRef.DeepCopyInto(&out.SecretRef)
+	if in.Header != nil {
+		in, out := &in.Header, &out.Header
+		*out = new(string)
 		**out = **in
 	}
-	if in.Rules != nil {
-		in, out := &in.Rules, &out.Rules
-		*out = make([]MCPRouteAuthorizationRule, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPRouteAuthorization.
-func (in *MCPRouteAuthorization) DeepCopy() *MCPRouteAuthorization {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPBackendUserCredentials.
+func (in *MCPBackendUserCredentials) DeepCopy() *MCPBackendUserCredentials {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPRouteAuthorization)
+	out := new(MCPBackendUserCredentials)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPRouteAuthorizationRule) DeepCopyInto(out *MCPRouteAuthorizationRule) {
+func (in *MCPElicitationPolicy) DeepCopyInto(out *MCPElicitationPolicy) {
 	*out = *in
-	if in.Source != nil {
-		in, out := &in.Source, &out.Source
-		*out = new(MCPAuthorizationSource)
-		(*in).DeepCopyInto(*out)
+	if in.DeniedFields != nil {
+		in, out := &in.DeniedFields, &out.DeniedFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Target != nil {
-		in, out := &in.Target, &out.Target
-		*out = new(MCPAuthorizationTarget)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPElicitationPolicy.
+func (in *MCPElicitationPolicy) DeepCopy() *MCPElicitationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPElicitationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPHeaderForward) DeepCopyInto(out *MCPHeaderForward) {
+	*out = *in
+	if in.BackendHeader != nil {
+		in, out := &in.BackendHeader, &out.BackendHeader
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPHeaderForward.
+func (in *MCPHeaderForward) DeepCopy() *MCPHeaderForward {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPHeaderForward)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPOpenAPIBackend) DeepCopyInto(out *MCPOpenAPIBackend) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(MCPOpenAPIConfigMapKeyReference)
+		**out = **in
+	}
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPOpenAPIBackend.
+func (in *MCPOpenAPIBackend) DeepCopy() *MCPOpenAPIBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPOpenAPIBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPOpenAPIConfigMapKeyReference) DeepCopyInto(out *MCPOpenAPIConfigMapKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPOpenAPIConfigMapKeyReference.
+func (in *MCPOpenAPIConfigMapKeyReference) DeepCopy() *MCPOpenAPIConfigMapKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPOpenAPIConfigMapKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPRateLimitClientKey) DeepCopyInto(out *MCPRateLimitClientKey) {
+	*out = *in
+	if in.Header != nil {
+		in, out := &in.Header, &out.Header
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPRateLimitClientKey.
+func (in *MCPRateLimitClientKey) DeepCopy() *MCPRateLimitClientKey {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPRateLimitClientKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPRateLimitRule) DeepCopyInto(out *MCPRateLimitRule) {
+	*out = *in
+	if in.Backend != nil {
+		in, out := &in.Backend, &out.Backend
+		*out = new(string)
+		**out = **in
+	}
+	if in.Tool != nil {
+		in, out := &in.Tool, &out.Tool
+		*out = new(string)
+		**out = **in
+	}
+	if in.ClientKey != nil {
+		in, out := &in.ClientKey, &out.ClientKey
+		*out = new(MCPRateLimitClientKey)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = new(MCPRateLimitValue)
+		**out = **in
+	}
+	if in.MaxConcurrency != nil {
+		in, out := &in.MaxConcurrency, &out.MaxConcurrency
+		*out = new(uint32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPRateLimitRule.
+func (in *MCPRateLimitRule) DeepCopy() *MCPRateLimitRule {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPRateLimitRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPRateLimitValue) DeepCopyInto(out *MCPRateLimitValue) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPRateLimitValue.
+func (in *MCPRateLimitValue) DeepCopy() *MCPRateLimitValue {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPRateLimitValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPRoute) DeepCopyInto(out *MCPRoute) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPRoute.
+func (in *MCPRoute) DeepCopy() *MCPRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MCPRoute) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPRouteAuthorization) DeepCopyInto(out *MCPRouteAuthorization) {
+	*out = *in
+	if in.DefaultAction != nil {
+		in, out := &in.DefaultAction, &out.DefaultAction
+		*out = new(apiv1alpha1.AuthorizationAction)
+		**out = **in
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]MCPRouteAuthorizationRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPRouteAuthorization.
+func (in *MCPRouteAuthorization) DeepCopy() *MCPRouteAuthorization {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPRouteAuthorization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPRouteAuthorizationRule) DeepCopyInto(out *MCPRouteAuthorizationRule) {
+	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(MCPAuthorizationSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(MCPAuthorizationTarget)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.CEL != nil {
@@ -1275,6 +1765,26 @@ func (in *MCPRouteBackendRef) DeepCopyInto(out *MCPRouteBackendRef) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Stdio != nil {
+		in, out := &in.Stdio, &out.Stdio
+		*out = new(MCPStdioServer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServerRequests != nil {
+		in, out := &in.ServerRequests, &out.ServerRequests
+		*out = new(MCPBackendServerRequests)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AIGatewayRoute != nil {
+		in, out := &in.AIGatewayRoute, &out.AIGatewayRoute
+		*out = new(MCPAIGatewayRouteBackend)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OpenAPI != nil {
+		in, out := &in.OpenAPI, &out.OpenAPI
+		*out = new(MCPOpenAPIBackend)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPRouteBackendRef.
@@ -1287,6 +1797,56 @@ func (in *MCPRouteBackendRef) DeepCopy() *MCPRouteBackendRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPRouteHealthCheck) DeepCopyInto(out *MCPRouteHealthCheck) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.UnhealthyThreshold != nil {
+		in, out := &in.UnhealthyThreshold, &out.UnhealthyThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.EjectionTime != nil {
+		in, out := &in.EjectionTime, &out.EjectionTime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPRouteHealthCheck.
+func (in *MCPRouteHealthCheck) DeepCopy() *MCPRouteHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPRouteHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPRouteLegacySSE) DeepCopyInto(out *MCPRouteLegacySSE) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPRouteLegacySSE.
+func (in *MCPRouteLegacySSE) DeepCopy() *MCPRouteLegacySSE {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPRouteLegacySSE)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MCPRouteList) DeepCopyInto(out *MCPRouteList) {
 	*out = *in
@@ -1350,6 +1910,28 @@ func (in *MCPRouteOAuth) DeepCopy() *MCPRouteOAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPRouteRateLimit) DeepCopyInto(out *MCPRouteRateLimit) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]MCPRateLimitRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPRouteRateLimit.
+func (in *MCPRouteRateLimit) DeepCopy() *MCPRouteRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPRouteRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MCPRouteSecurityPolicy) DeepCopyInto(out *MCPRouteSecurityPolicy) {
 	*out = *in
@@ -1419,6 +2001,31 @@ func (in *MCPRouteSpec) DeepCopyInto(out *MCPRouteSpec) {
 		*out = new(MCPRouteSecurityPolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(MCPRouteRateLimit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LegacySSE != nil {
+		in, out := &in.LegacySSE, &out.LegacySSE
+		*out = new(MCPRouteLegacySSE)
+		**out = **in
+	}
+	if in.ToolValidation != nil {
+		in, out := &in.ToolValidation, &out.ToolValidation
+		*out = new(MCPRouteToolValidation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ToolSearch != nil {
+		in, out := &in.ToolSearch, &out.ToolSearch
+		*out = new(MCPRouteToolSearch)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(MCPRouteHealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPRouteSpec.
@@ -1453,6 +2060,161 @@ func (in *MCPRouteStatus) DeepCopy() *MCPRouteStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPRouteToolSearch) DeepCopyInto(out *MCPRouteToolSearch) {
+	*out = *in
+	if in.MaxResults != nil {
+		in, out := &in.MaxResults, &out.MaxResults
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPRouteToolSearch.
+func (in *MCPRouteToolSearch) DeepCopy() *MCPRouteToolSearch {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPRouteToolSearch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPRouteToolValidation) DeepCopyInto(out *MCPRouteToolValidation) {
+	*out = *in
+	if in.Arguments != nil {
+		in, out := &in.Arguments, &out.Arguments
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StructuredContent != nil {
+		in, out := &in.StructuredContent, &out.StructuredContent
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPRouteToolValidation.
+func (in *MCPRouteToolValidation) DeepCopy() *MCPRouteToolValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPRouteToolValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPSamplingPolicy) DeepCopyInto(out *MCPSamplingPolicy) {
+	*out = *in
+	if in.MaxTokens != nil {
+		in, out := &in.MaxTokens, &out.MaxTokens
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IncludeContext != nil {
+		in, out := &in.IncludeContext, &out.IncludeContext
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPSamplingPolicy.
+func (in *MCPSamplingPolicy) DeepCopy() *MCPSamplingPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPSamplingPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPStdioEnvVar) DeepCopyInto(out *MCPStdioEnvVar) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPStdioEnvVar.
+func (in *MCPStdioEnvVar) DeepCopy() *MCPStdioEnvVar {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPStdioEnvVar)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPStdioResources) DeepCopyInto(out *MCPStdioResources) {
+	*out = *in
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.CPUTime != nil {
+		in, out := &in.CPUTime, &out.CPUTime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPStdioResources.
+func (in *MCPStdioResources) DeepCopy() *MCPStdioResources {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPStdioResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPStdioServer) DeepCopyInto(out *MCPStdioServer) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]MCPStdioEnvVar, len(*in))
+		copy(*out, *in)
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = new(v1.SecretObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxProcesses != nil {
+		in, out := &in.MaxProcesses, &out.MaxProcesses
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SessionIdleTimeout != nil {
+		in, out := &in.SessionIdleTimeout, &out.SessionIdleTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(MCPStdioResources)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPStdioServer.
+func (in *MCPStdioServer) DeepCopy() *MCPStdioServer {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPStdioServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MCPToolFilter) DeepCopyInto(out *MCPToolFilter) {
 	*out = *in
@@ -1765,6 +2527,101 @@ func (in *ToolCall) DeepCopy() *ToolCall {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultAuth) DeepCopyInto(out *VaultAuth) {
+	*out = *in
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(v1.SecretObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(VaultKubernetesAuth)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultAuth.
+func (in *VaultAuth) DeepCopy() *VaultAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultCredential) DeepCopyInto(out *VaultCredential) {
+	*out = *in
+	if in.Region != nil {
+		in, out := &in.Region, &out.Region
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultCredential.
+func (in *VaultCredential) DeepCopy() *VaultCredential {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultCredential)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultDynamicSecret) DeepCopyInto(out *VaultDynamicSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultDynamicSecret.
+func (in *VaultDynamicSecret) DeepCopy() *VaultDynamicSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultDynamicSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultKVSecret) DeepCopyInto(out *VaultKVSecret) {
+	*out = *in
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultKVSecret.
+func (in *VaultKVSecret) DeepCopy() *VaultKVSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultKVSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultKubernetesAuth) DeepCopyInto(out *VaultKubernetesAuth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultKubernetesAuth.
+func (in *VaultKubernetesAuth) DeepCopy() *VaultKubernetesAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultKubernetesAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VersionedAPISchema) DeepCopyInto(out *VersionedAPISchema) {
 	*out = *in
@@ -1789,3 +2646,145 @@ func (in *VersionedAPISchema) DeepCopy() *VersionedAPISchema {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualKey) DeepCopyInto(out *VirtualKey) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualKey.
+func (in *VirtualKey) DeepCopy() *VirtualKey {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualKey) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualKeyBudget) DeepCopyInto(out *VirtualKeyBudget) {
+	*out = *in
+	if in.CostExpression != nil {
+		in, out := &in.CostExpression, &out.CostExpression
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualKeyBudget.
+func (in *VirtualKeyBudget) DeepCopy() *VirtualKeyBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualKeyBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualKeyList) DeepCopyInto(out *VirtualKeyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualKeyList.
+func (in *VirtualKeyList) DeepCopy() *VirtualKeyList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualKeyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualKeyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualKeySpec) DeepCopyInto(out *VirtualKeySpec) {
+	*out = *in
+	if in.TargetRefs != nil {
+		in, out := &in.TargetRefs, &out.TargetRefs
+		*out = make([]v1alpha2.LocalPolicyTargetReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedModels != nil {
+		in, out := &in.AllowedModels, &out.AllowedModels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(VirtualKeyBudget)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualKeySpec.
+func (in *VirtualKeySpec) DeepCopy() *VirtualKeySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualKeySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualKeyStatus) DeepCopyInto(out *VirtualKeyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualKeyStatus.
+func (in *VirtualKeyStatus) DeepCopy() *VirtualKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -205,6 +205,57 @@ type AIGatewayRouteSpec struct {
 	// +optional
 	// +kubebuilder:validation:MaxItems=36
 	LLMRequestCosts []LLMRequestCost `json:"llmRequestCosts,omitempty"`
+
+	// ToolExecution enables the execution of the tools of an MCPRoute by the gateway for the chat completion
+	// requests of this route.
+	//
+	// When set, the tools of the MCPRoute are added to the `tools` of the requests to the `/v1/chat/completions`
+	// endpoint. When the model calls some of these tools, the gateway executes the calls through the MCPRoute,
+	// appends the results to the conversation and sends it back to the model, until the model returns a final
+	// answer, which is returned to the client. The tool calls to the tools defined by the client are returned to the
+	// client as usual.
+	//
+	// This requires the MCPRoute to be attached to the same Gateway as this AIGatewayRoute. The MCPRoute must not
+	// authenticate its clients with oauth, apiKeyAuth or extAuth, since the tool calls of the gateway do not go through
+	// this authentication. Otherwise, the tool execution is disabled.
+	//
+	// The tool execution needs the whole response of the model to find its tool calls, so the responses to the
+	// streaming requests are not streamed: the final answer is returned at once, as the events of the stream of the
+	// last call to the model.
+	//
+	// When the listener of this route is served over HTTPS, the certificate of the listener is verified for the host of
+	// the request with the certificate authorities trusted by the external processor when the model is called back.
+	//
+	// +optional
+	ToolExecution *AIGatewayRouteToolExecution `json:"toolExecution,omitempty"`
+}
+
+// AIGatewayRouteToolExecution configures the execution of the tools of an MCPRoute by the gateway.
+type AIGatewayRouteToolExecution struct {
+	// MCPRouteName is the name of the MCPRoute in the same namespace whose tools are executed by the gateway.
+	//
+	// +kubebuilder:validation:Required
+	MCPRouteName gwapiv1.ObjectName `json:"mcpRouteName"`
+
+	// MaxIterations is the maximum number of times the model is called back with the results of the tool calls.
+	// When reached, the last response of the model is returned to the client as is, including its tool calls.
+	//
+	// Defaults to 5.
+	//
+	// +optional
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=20
+	MaxIterations *int32 `json:"maxIterations,omitempty"`
+
+	// Timeout is the maximum time spent executing the tool calls and calling back the model for a request.
+	// When reached, the last response of the model is returned to the client as is, including its tool calls.
+	//
+	// Defaults to 60s.
+	//
+	// +optional
+	// +kubebuilder:default="60s"
+	Timeout *gwapiv1.Duration `json:"timeout,omitempty"`
 }
 
 // AIGatewayRouteRule is a rule that defines the routing behavior of the AIGatewayRoute.
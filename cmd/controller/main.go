@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -23,6 +24,7 @@ import (
 	"google.golang.org/grpc/health/grpc_health_v1"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -30,7 +32,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	"github.com/envoyproxy/ai-gateway/internal/configserver"
 	"github.com/envoyproxy/ai-gateway/internal/controller"
+	"github.com/envoyproxy/ai-gateway/internal/controller/rotators"
 	"github.com/envoyproxy/ai-gateway/internal/extensionserver"
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 	"github.com/envoyproxy/ai-gateway/internal/pprof"
@@ -67,11 +71,24 @@ type flags struct {
 	mcpFallbackSessionEncryptionSeed       string
 	mcpSessionEncryptionIterations         int
 	mcpFallbackSessionEncryptionIterations int
+	mcpSessionStore                        string
+	mcpAuditLog                            string
+	mcpAuditArguments                      string
 	watchNamespaces                        []string
 	cacheSyncTimeout                       time.Duration
 	quotaRateLimitServiceAddr              string
 	quotaRateLimitTimeout                  int64
 	quotaRateLimitFailureModeDeny          bool
+	// configServerAddr is the address of the config server that the external processors connect to. Optional.
+	configServerAddr string
+	// envoyGatewayNamespace is the namespace where Envoy Gateway deploys the Envoy proxies of the Gateways.
+	envoyGatewayNamespace string
+	// vaultAddresses are the addresses of the Vault servers that the backend security policies can use.
+	vaultAddresses []string
+	// vaultKubernetesAuthRoles are the Vault roles that the backend security policies of each namespace can use.
+	vaultKubernetesAuthRoles map[string][]string
+	// enableMCPStdioServers allows the MCPRoutes to run stdio MCP servers in the external processor container.
+	enableMCPStdioServers bool
 }
 
 func setOptionalString(dst **string) func(string) error {
@@ -191,7 +208,7 @@ func parseAndValidateFlags(args []string) (*flags, error) {
 	endpointPrefixes := fs.String(
 		"endpointPrefixes",
 		"",
-		"Comma-separated key-value pairs for endpoint prefixes. Format: openai:/,cohere:/cohere,anthropic:/anthropic.",
+		"Comma-separated key-value pairs for endpoint prefixes. Format: openai:/,cohere:/cohere,anthropic:/anthropic,ollama:/ollama.",
 	)
 	rootPrefix := fs.String(
 		"rootPrefix",
@@ -236,12 +253,34 @@ func parseAndValidateFlags(args []string) (*flags, error) {
 		"Optional fallback seed used for MCP session key rotation")
 	mcpFallbackSessionEncryptionIterations := fs.Int("mcpFallbackSessionEncryptionIterations", 100_000,
 		"Number of iterations used in the fallback PBKDF2 key derivation for MCP session encryption.")
+	mcpSessionStore := fs.String("mcpSessionStore", "",
+		"Optional store of the MCP session state shared by the replicas of the MCP proxy: 'memory', or a Redis URL such as redis://host:6379/0.")
+	mcpAuditLog := fs.String("mcpAuditLog", "",
+		"Optional destination of the audit log of the MCP requests: 'otlp', 'stdout', or the path of a file in the external processor container.")
+	mcpAuditArguments := fs.String("mcpAuditArguments", "redact",
+		"How the arguments of the audited MCP requests are recorded: 'redact' or 'hash'.")
 	quotaRateLimitServiceAddr := fs.String("quotaRateLimitServiceAddr", "envoy-ai-gateway-ratelimit.envoy-gateway-system",
 		"Host (or host:port) for the AI Gateway quota rate limit service. If no port is specified, 8081 is used.")
 	quotaRateLimitTimeout := fs.Int64("quotaRateLimitTimeout", 5,
 		"Timeout in seconds for the quota rate limit service.")
 	quotaRateLimitFailureModeDeny := fs.Bool("quotaRateLimitFailureModeDeny", false,
 		"If true, the rate limit filter will deny requests when the rate limit service is unavailable.")
+	configServerAddr := fs.String("configServerAddr", "",
+		"Optional host:port of the config server streaming the filter configs to the external processors over xDS, "+
+			"such as ai-gateway-controller.envoy-ai-gateway-system:18003. The config server listens on its port. "+
+			"When unset, the filter configs are delivered via the Secrets mounted in the Gateway pods.")
+	envoyGatewayNamespace := fs.String("envoyGatewayNamespace", "envoy-gateway-system",
+		"The namespace where Envoy Gateway deploys the Envoy proxies of the Gateways. The config server only serves "+
+			"the external processors of the pods in this namespace.")
+	vaultAddresses := fs.String("vaultAddresses", "",
+		"Comma-separated addresses of the Vault servers that the Vault backend security policies can read credentials "+
+			"from, such as https://vault.vault.svc:8200. When unset, the Vault backend security policies are not accepted.")
+	vaultKubernetesAuthRoles := fs.String("vaultKubernetesAuthRoles", "",
+		"Comma-separated namespace:role pairs of the roles of the Vault Kubernetes auth method that the Vault backend "+
+			"security policies of each namespace can log in with, such as team-a:ai-gateway-team-a.")
+	enableMCPStdioServers := fs.Bool("enableMCPStdioServers", false,
+		"Allow the MCPRoutes to run stdio MCP servers in the external processor container. Only enable this when "+
+			"every author of MCPRoutes is trusted to run arbitrary commands with the credentials of the external processor.")
 
 	if err := fs.Parse(args); err != nil {
 		err = fmt.Errorf("failed to parse flags: %w", err)
@@ -320,6 +359,27 @@ func parseAndValidateFlags(args []string) (*flags, error) {
 		}
 	}
 
+	if *configServerAddr != "" {
+		if _, _, err := net.SplitHostPort(*configServerAddr); err != nil {
+			return nil, fmt.Errorf("invalid config server address: %w", err)
+		}
+	}
+
+	parsedVaultKubernetesAuthRoles, err := rotators.ParseVaultKubernetesAuthRoles(*vaultKubernetesAuthRoles)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault kubernetes auth roles: %w", err)
+	}
+	var parsedVaultAddresses []string
+	for _, addr := range strings.Split(*vaultAddresses, ",") {
+		if addr = strings.TrimSpace(addr); addr == "" {
+			continue
+		}
+		if u, parseErr := url.Parse(addr); parseErr != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid vault address: %q", addr)
+		}
+		parsedVaultAddresses = append(parsedVaultAddresses, addr)
+	}
+
 	if *mcpSessionEncryptionIterations <= 0 {
 		return nil, fmt.Errorf("mcp session encryption iterations must be positive: %d", *mcpSessionEncryptionIterations)
 	}
@@ -356,9 +416,17 @@ func parseAndValidateFlags(args []string) (*flags, error) {
 		mcpFallbackSessionEncryptionSeed:       *mcpFallbackSessionEncryptionSeed,
 		mcpSessionEncryptionIterations:         *mcpSessionEncryptionIterations,
 		mcpFallbackSessionEncryptionIterations: *mcpFallbackSessionEncryptionIterations,
+		mcpSessionStore:                        *mcpSessionStore,
+		mcpAuditLog:                            *mcpAuditLog,
+		mcpAuditArguments:                      *mcpAuditArguments,
 		quotaRateLimitServiceAddr:              *quotaRateLimitServiceAddr,
 		quotaRateLimitTimeout:                  *quotaRateLimitTimeout,
 		quotaRateLimitFailureModeDeny:          *quotaRateLimitFailureModeDeny,
+		configServerAddr:                       *configServerAddr,
+		envoyGatewayNamespace:                  *envoyGatewayNamespace,
+		vaultAddresses:                         parsedVaultAddresses,
+		vaultKubernetesAuthRoles:               parsedVaultKubernetesAuthRoles,
+		enableMCPStdioServers:                  *enableMCPStdioServers,
 	}, nil
 }
 
@@ -442,6 +510,30 @@ func main() {
 		}
 	}()
 
+	// Start the filter config xDS server if enabled.
+	// The config server is served with the certificate of the webhook, which the external processors verify with
+	// its CA certificate.
+	var (
+		configServer       *configserver.Server
+		configServerCACert []byte
+	)
+	if parsedFlags.configServerAddr != "" {
+		configServerCACert, err = os.ReadFile(filepath.Join(parsedFlags.tlsCertDir, parsedFlags.caBundleName))
+		if err != nil {
+			setupLog.Error(err, "failed to read the CA certificate of the config server")
+			os.Exit(1)
+		}
+		_, port, _ := net.SplitHostPort(parsedFlags.configServerAddr)
+		configServer = configserver.New(ctrl.Log, net.JoinHostPort("", port), kubernetes.NewForConfigOrDie(k8sConfig),
+			parsedFlags.envoyGatewayNamespace, filepath.Join(parsedFlags.tlsCertDir, parsedFlags.tlsCertName), filepath.Join(parsedFlags.tlsCertDir, parsedFlags.tlsKeyName))
+		go func() {
+			if err := configServer.Start(ctx); err != nil {
+				setupLog.Error(err, "failed to start config xDS server")
+				os.Exit(1)
+			}
+		}()
+	}
+
 	// Start the controller.
 	if err := controller.StartControllers(ctx, mgr, k8sConfig, ctrl.Log.WithName("controller"), &controller.Options{
 		ExtProcImage:                           parsedFlags.extProcImage,
@@ -463,7 +555,18 @@ func main() {
 		MCPSessionEncryptionIterations:         parsedFlags.mcpSessionEncryptionIterations,
 		MCPFallbackSessionEncryptionSeed:       parsedFlags.mcpFallbackSessionEncryptionSeed,
 		MCPFallbackSessionEncryptionIterations: parsedFlags.mcpFallbackSessionEncryptionIterations,
+		MCPSessionStore:                        parsedFlags.mcpSessionStore,
+		MCPAuditLog:                            parsedFlags.mcpAuditLog,
+		MCPAuditArguments:                      parsedFlags.mcpAuditArguments,
 		RateLimitRunner:                        rlRunner,
+		ConfigServer:                           configServer,
+		ConfigServerAddr:                       parsedFlags.configServerAddr,
+		ConfigServerCACert:                     string(configServerCACert),
+		Vault: &rotators.VaultConfig{
+			Addresses:           parsedFlags.vaultAddresses,
+			KubernetesAuthRoles: parsedFlags.vaultKubernetesAuthRoles,
+		},
+		EnableMCPStdioServers: parsedFlags.enableMCPStdioServers,
 	}); err != nil {
 		setupLog.Error(err, "failed to start controller")
 	}
@@ -36,6 +36,7 @@ func Test_parseAndValidateFlags(t *testing.T) {
 		require.Equal(t, 4*1024*1024, f.maxRecvMsgSize)
 		require.Nil(t, f.spanRequestHeaderAttributes)
 		require.Nil(t, f.logRequestHeaderAttributes)
+		require.False(t, f.enableMCPStdioServers)
 		require.NoError(t, err)
 	})
 	t.Run("all flags", func(t *testing.T) {
@@ -68,6 +69,12 @@ func Test_parseAndValidateFlags(t *testing.T) {
 					tc.dash + "mcpSessionEncryptionIterations=100",
 					tc.dash + "mcpFallbackSessionEncryptionSeed=my-fallback-seed",
 					tc.dash + "mcpFallbackSessionEncryptionIterations=200",
+					tc.dash + "mcpSessionStore=redis://redis:6379",
+					tc.dash + "configServerAddr=ai-gateway-controller.envoy-ai-gateway-system:18003",
+					tc.dash + "envoyGatewayNamespace=eg-system",
+					tc.dash + "vaultAddresses=https://vault.vault.svc:8200, http://vault.local",
+					tc.dash + "vaultKubernetesAuthRoles=default:ai-gateway,team:team",
+					tc.dash + "enableMCPStdioServers=true",
 				}
 				f, err := parseAndValidateFlags(args)
 				require.Equal(t, "debug", f.extProcLogLevel)
@@ -93,6 +100,12 @@ func Test_parseAndValidateFlags(t *testing.T) {
 				require.Equal(t, 100, f.mcpSessionEncryptionIterations)
 				require.Equal(t, "my-fallback-seed", f.mcpFallbackSessionEncryptionSeed)
 				require.Equal(t, 200, f.mcpFallbackSessionEncryptionIterations)
+				require.Equal(t, "redis://redis:6379", f.mcpSessionStore)
+				require.Equal(t, "ai-gateway-controller.envoy-ai-gateway-system:18003", f.configServerAddr)
+				require.Equal(t, "eg-system", f.envoyGatewayNamespace)
+				require.Equal(t, []string{"https://vault.vault.svc:8200", "http://vault.local"}, f.vaultAddresses)
+				require.Equal(t, map[string][]string{"default": {"ai-gateway"}, "team": {"team"}}, f.vaultKubernetesAuthRoles)
+				require.True(t, f.enableMCPStdioServers)
 				require.NoError(t, err)
 			})
 		}
@@ -179,6 +192,21 @@ func Test_parseAndValidateFlags(t *testing.T) {
 				flags:  []string{"--mcpFallbackSessionEncryptionSeed=fallback", "--mcpFallbackSessionEncryptionIterations=-1"},
 				expErr: "mcp fallback session encryption iterations must be positive: -1",
 			},
+			{
+				name:   "config server address without port",
+				flags:  []string{"--configServerAddr=ai-gateway-controller.envoy-ai-gateway-system"},
+				expErr: "invalid config server address",
+			},
+			{
+				name:   "invalid vault address",
+				flags:  []string{"--vaultAddresses=vault.vault.svc:8200"},
+				expErr: `invalid vault address: "vault.vault.svc:8200"`,
+			},
+			{
+				name:   "invalid vault kubernetes auth roles",
+				flags:  []string{"--vaultKubernetesAuthRoles=ai-gateway"},
+				expErr: "invalid vault kubernetes auth roles",
+			},
 		} {
 			t.Run(tc.name, func(t *testing.T) {
 				_, err := parseAndValidateFlags(tc.flags)
@@ -26,6 +26,8 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health/grpc_health_v1"
 
+	"github.com/envoyproxy/ai-gateway/internal/backendauth"
+	"github.com/envoyproxy/ai-gateway/internal/configserver"
 	"github.com/envoyproxy/ai-gateway/internal/endpointspec"
 	"github.com/envoyproxy/ai-gateway/internal/extproc"
 	"github.com/envoyproxy/ai-gateway/internal/filterapi"
@@ -40,6 +42,9 @@ import (
 // extProcFlags is the struct that holds the flags passed to the external processor.
 type extProcFlags struct {
 	configPath                             string        // path to the configuration file.
+	configServerAddr                       string        // gRPC address of the controller's config server. Optional.
+	configServerNodeID                     string        // xDS node ID identifying the Gateway to the config server.
+	configServerCACert                     string        // PEM encoded CA certificate of the config server.
 	extProcAddr                            string        // gRPC address for the external processor.
 	logLevel                               slog.Level    // log level for the external processor.
 	enableRedaction                        bool          // enable redaction of sensitive information in debug logs.
@@ -54,6 +59,9 @@ type extProcFlags struct {
 	mcpFallbackSessionEncryptionSeed       string        // Fallback seed for deriving the key for encrypting MCP sessions.
 	mcpFallbackSessionEncryptionIterations int           // Number of iterations to use for PBKDF2 key derivation for fallback MCP session encryption.
 	mcpWriteTimeout                        time.Duration // the maximum duration before timing out writes of the MCP response.
+	mcpSessionStore                        string        // Store of the MCP session state shared by the replicas. Optional.
+	mcpAuditLog                            string        // Destination of the audit log of the MCP requests. Optional.
+	mcpAuditArguments                      string        // How the arguments of the audited MCP requests are recorded.
 	// rootPrefix is the root prefix for all the processors.
 	rootPrefix string
 	// maxRecvMsgSize is the maximum message size in bytes that the gRPC server can receive.
@@ -83,6 +91,23 @@ func parseAndValidateFlags(args []string) (extProcFlags, error) {
 		"path to the configuration file. The file must be in YAML format specified in filterapi.Config type. "+
 			"The configuration file is watched for changes.",
 	)
+	fs.StringVar(&flags.configServerAddr,
+		"configServerAddr",
+		"",
+		"gRPC address of the config server of the AI Gateway controller, such as ai-gateway-controller.envoy-ai-gateway-system:18003. "+
+			"When set, the configuration is streamed from the config server instead of read from configPath.",
+	)
+	fs.StringVar(&flags.configServerNodeID,
+		"configServerNodeID",
+		"",
+		"xDS node ID identifying the Gateway to the config server, in the form of <gateway namespace>/<gateway name>. "+
+			"Required when configServerAddr is set.",
+	)
+	fs.StringVar(&flags.configServerCACert,
+		"configServerCACert",
+		"",
+		"PEM encoded CA certificate verifying the TLS certificate of the config server. Required when configServerAddr is set.",
+	)
 	fs.StringVar(&flags.extProcAddr,
 		"extProcAddr",
 		":1063",
@@ -120,12 +145,13 @@ func parseAndValidateFlags(args []string) (extProcFlags, error) {
 	fs.StringVar(&flags.endpointPrefixes,
 		"endpointPrefixes",
 		"",
-		"Comma-separated key-value pairs for endpoint prefixes. Format: openai:/,cohere:/cohere,anthropic:/anthropic.",
+		"Comma-separated key-value pairs for endpoint prefixes. Format: openai:/,cohere:/cohere,anthropic:/anthropic,ollama:/ollama.",
 	)
 	fs.IntVar(&flags.maxRecvMsgSize,
 		"maxRecvMsgSize",
 		math.MaxInt,
-		"Maximum message size in bytes that the gRPC server can receive. Default is unlimited since the flow control should be handled by Envoy.",
+		"Maximum message size in bytes that the gRPC server can receive, which also bounds the size of the config received from the config server. "+
+			"Default is unlimited since the flow control should be handled by Envoy.",
 	)
 	fs.StringVar(&flags.mcpAddr, "mcpAddr", "", "the address (TCP or UDS) for the MCP proxy server, such as :1063 or unix:///tmp/ext_proc.sock. Optional.")
 	fs.StringVar(&flags.mcpSessionEncryptionSeed, "mcpSessionEncryptionSeed", "default-insecure-seed",
@@ -138,12 +164,32 @@ func parseAndValidateFlags(args []string) (extProcFlags, error) {
 		"Number of iterations used in the fallback PBKDF2 key derivation for MCP session encryption.")
 	fs.DurationVar(&flags.mcpWriteTimeout, "mcpWriteTimeout", 120*time.Second,
 		"The maximum duration before timing out writes of the MCP response")
+	fs.StringVar(&flags.mcpSessionStore, "mcpSessionStore", "",
+		"Optional store of the MCP session state, such as the event replay buffers and the resource subscriptions: "+
+			"'memory' for a store local to the process, or a Redis URL such as redis://host:6379/0 for a store shared by all the replicas.")
+	fs.StringVar(&flags.mcpAuditLog, "mcpAuditLog", "",
+		"Optional destination of the audit log of the MCP tools/call, resources/read and prompts/get requests: "+
+			"'otlp' to export OpenTelemetry logs configured with the OTEL_EXPORTER_OTLP_* environment variables, "+
+			"'stdout' to write JSON lines to stdout, or the path of a file to append JSON lines to.")
+	fs.StringVar(&flags.mcpAuditArguments, "mcpAuditArguments", string(mcpproxy.AuditArgumentsRedact),
+		"How the arguments of the audited MCP requests are recorded: 'redact' to redact every value, or 'hash' to record a hash of the arguments.")
 
 	if err := fs.Parse(args); err != nil {
 		return extProcFlags{}, fmt.Errorf("failed to parse extProcFlags: %w", err)
 	}
 
-	if flags.configPath == "" {
+	switch {
+	case flags.configServerAddr != "":
+		if flags.configPath != "" {
+			errs = append(errs, fmt.Errorf("configPath and configServerAddr are mutually exclusive"))
+		}
+		if flags.configServerNodeID == "" {
+			errs = append(errs, fmt.Errorf("configServerNodeID must be provided when configServerAddr is set"))
+		}
+		if flags.configServerCACert == "" {
+			errs = append(errs, fmt.Errorf("configServerCACert must be provided when configServerAddr is set"))
+		}
+	case flags.configPath == "":
 		errs = append(errs, fmt.Errorf("configPath must be provided"))
 	}
 	if err := flags.logLevel.UnmarshalText([]byte(*logLevelPtr)); err != nil {
@@ -174,6 +220,17 @@ func parseAndValidateFlags(args []string) (extProcFlags, error) {
 			errs = append(errs, fmt.Errorf("failed to parse endpoint prefixes: %w", err))
 		}
 	}
+	if flags.mcpSessionStore != "" {
+		// The store does not connect until used, so this only validates the flag.
+		if store, err := mcpproxy.NewSessionStore(flags.mcpSessionStore); err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse MCP session store: %w", err))
+		} else {
+			_ = store.Close()
+		}
+	}
+	if _, err := mcpproxy.ParseAuditArgumentsMode(flags.mcpAuditArguments); err != nil {
+		errs = append(errs, fmt.Errorf("failed to parse MCP audit arguments: %w", err))
+	}
 
 	return flags, errors.Join(errs...)
 }
@@ -205,6 +262,7 @@ func Main(ctx context.Context, args []string, stderr io.Writer) (err error) {
 		slog.String("version", version.Parse()),
 		slog.String("address", flags.extProcAddr),
 		slog.String("configPath", flags.configPath),
+		slog.String("configServerAddr", flags.configServerAddr),
 	)
 
 	network, address := listenAddress(flags.extProcAddr)
@@ -287,6 +345,7 @@ func Main(ctx context.Context, args []string, stderr io.Writer) (err error) {
 	translationMetricsFactory := metrics.NewMetricsFactory(meter, metricsRequestHeaderAttributes, metrics.GenAIOperationTranslation)
 	rerankMetricsFactory := metrics.NewMetricsFactory(meter, metricsRequestHeaderAttributes, metrics.GenAIOperationRerank)
 	mcpMetrics := metrics.NewMCP(meter, metricsRequestHeaderAttributes)
+	backendauth.APIKeyPoolMetrics = metrics.NewAPIKeyPool(meter)
 
 	extproc.LogRequestHeaderAttributes = logRequestHeaderAttributes
 
@@ -315,11 +374,12 @@ func Main(ctx context.Context, args []string, stderr io.Writer) (err error) {
 	server.Register(path.Join(flags.rootPrefix, endpointPrefixes.OpenAI, "/v1/models"), extproc.NewModelsProcessor)
 	server.Register(path.Join(flags.rootPrefix, endpointPrefixes.Anthropic, "/v1/messages"), extproc.NewFactory(
 		messagesMetricsFactory, tracing.MessageTracer(), endpointspec.MessagesEndpointSpec{}))
+	server.Register(path.Join(flags.rootPrefix, endpointPrefixes.Ollama, "/api/chat"), extproc.NewFactory(
+		chatCompletionMetricsFactory, tracing.OllamaChatTracer(), endpointspec.OllamaChatEndpointSpec{}))
+	server.Register(path.Join(flags.rootPrefix, endpointPrefixes.Ollama, "/api/embed"), extproc.NewFactory(
+		embeddingsMetricsFactory, tracing.OllamaEmbedTracer(), endpointspec.OllamaEmbedEndpointSpec{}))
 
-	// Create and register gRPC server with ExternalProcessorServer (the service Envoy calls).
-	if err = filterapi.StartConfigWatcher(ctx, flags.configPath, server, l, time.Second*5); err != nil {
-		return fmt.Errorf("failed to start config watcher: %w", err)
-	}
+	configReceivers := []filterapi.ConfigReceiver{server}
 
 	var mcpServer *http.Server
 	if mcpLis != nil {
@@ -334,22 +394,64 @@ func Main(ctx context.Context, args []string, stderr io.Writer) (err error) {
 			}
 		}
 
+		var mcpSessionStore mcpproxy.SessionStore
+		if flags.mcpSessionStore != "" {
+			if mcpSessionStore, err = mcpproxy.NewSessionStore(flags.mcpSessionStore); err != nil {
+				return fmt.Errorf("failed to create MCP session store: %w", err)
+			}
+			defer func() { _ = mcpSessionStore.Close() }()
+		}
+
+		var mcpAuditLog *mcpproxy.AuditLog
+		if flags.mcpAuditLog != "" {
+			mcpAuditLog, err = mcpproxy.NewAuditLog(ctx, l.With("component", "mcp-audit"), flags.mcpAuditLog,
+				mcpproxy.AuditArgumentsMode(flags.mcpAuditArguments), os.Stdout)
+			if err != nil {
+				return fmt.Errorf("failed to create MCP audit log: %w", err)
+			}
+			// Flush the pending records after the MCP proxy has been shut down.
+			defer func() {
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := mcpAuditLog.Close(shutdownCtx); err != nil {
+					l.Error("Failed to close MCP audit log", "error", err)
+				}
+			}()
+		}
+
 		var mcpProxyMux *http.ServeMux
 		var mcpProxyConfig *mcpproxy.ProxyConfig
 		mcpProxyConfig, mcpProxyMux, err = mcpproxy.NewMCPProxy(l.With("component", "mcp-proxy"), mcpMetrics,
-			tracing.MCPTracer(), mcpSessionCrypto, logRequestHeaderAttributes)
+			tracing.MCPTracer(), mcpSessionCrypto, mcpSessionStore, mcpAuditLog, logRequestHeaderAttributes)
 		if err != nil {
 			return fmt.Errorf("failed to create MCP proxy: %w", err)
 		}
-		if err = filterapi.StartConfigWatcher(ctx, flags.configPath, mcpProxyConfig, l, time.Second*5); err != nil {
-			return fmt.Errorf("failed to start config watcher: %w", err)
-		}
+		configReceivers = append(configReceivers, mcpProxyConfig)
+		// The gateway-side tool execution of the chat completions calls the tools through the MCP proxy.
+		extproc.MCPToolClient = mcpproxy.NewToolClient(mcpLis.Addr())
 
 		mcpServer = &http.Server{
 			Handler:           mcpProxyMux,
 			ReadHeaderTimeout: 120 * time.Second,
 			WriteTimeout:      flags.mcpWriteTimeout,
 		}
+	}
+
+	// The initial configuration must be loaded before serving.
+	if flags.configServerAddr != "" {
+		if err = configserver.StartConfigStream(ctx, flags.configServerAddr, flags.configServerNodeID,
+			[]byte(flags.configServerCACert), configserver.TokenPath, flags.maxRecvMsgSize, l, configReceivers...); err != nil {
+			return fmt.Errorf("failed to start config stream: %w", err)
+		}
+	} else {
+		for _, rcv := range configReceivers {
+			if err = filterapi.StartConfigWatcher(ctx, flags.configPath, rcv, l, time.Second*5); err != nil {
+				return fmt.Errorf("failed to start config watcher: %w", err)
+			}
+		}
+	}
+
+	if mcpServer != nil {
 		go func() {
 			l.Info("Starting mcp proxy", "addr", mcpLis.Addr())
 			if err2 := mcpServer.Serve(mcpLis); err2 != nil && !errors.Is(err2, http.ErrServerClosed) {
@@ -108,6 +108,32 @@ func Test_parseAndValidateFlags(t *testing.T) {
 				logLevel:        slog.LevelInfo,
 				enableRedaction: false,
 			},
+			{
+				name:            "with MCP audit log",
+				args:            []string{"-configPath", "/path/to/config.yaml", "-mcpAuditLog", "otlp", "-mcpAuditArguments", "hash"},
+				configPath:      "/path/to/config.yaml",
+				addr:            ":1063",
+				rootPrefix:      "/",
+				logLevel:        slog.LevelInfo,
+				enableRedaction: false,
+			},
+			{
+				name:            "with redis MCP session store",
+				args:            []string{"-configPath", "/path/to/config.yaml", "-mcpSessionStore", "redis://localhost:6379/0"},
+				configPath:      "/path/to/config.yaml",
+				addr:            ":1063",
+				rootPrefix:      "/",
+				logLevel:        slog.LevelInfo,
+				enableRedaction: false,
+			},
+			{
+				name:            "with config server",
+				args:            []string{"-configServerAddr", "ai-gateway-controller.envoy-ai-gateway-system:18003", "-configServerNodeID", "default/gw", "-configServerCACert", "ca"},
+				addr:            ":1063",
+				rootPrefix:      "/",
+				logLevel:        slog.LevelInfo,
+				enableRedaction: false,
+			},
 			{
 				name: "with metrics header mapping",
 				args: []string{
@@ -181,16 +207,36 @@ func Test_parseAndValidateFlags(t *testing.T) {
 				args:          []string{"-logLevel", "invalid"},
 				expectedError: "configPath must be provided\nfailed to unmarshal log level: slog: level string \"invalid\": unknown name",
 			},
+			{
+				name:          "config server without node ID",
+				args:          []string{"-configServerAddr", "localhost:18003"},
+				expectedError: "configServerNodeID must be provided when configServerAddr is set\nconfigServerCACert must be provided when configServerAddr is set",
+			},
+			{
+				name:          "both config path and config server",
+				args:          []string{"-configPath", "/path/to/config.yaml", "-configServerAddr", "localhost:18003", "-configServerNodeID", "default/gw", "-configServerCACert", "ca"},
+				expectedError: "configPath and configServerAddr are mutually exclusive",
+			},
 			{
 				name:          "invalid endpoint prefixes - unknown key",
 				args:          []string{"-configPath", "/path/to/config.yaml", "-endpointPrefixes", "foo:/x"},
-				expectedError: "failed to parse endpoint prefixes: unknown endpointPrefixes key \"foo\" at position 1 (allowed: openai, cohere, anthropic)",
+				expectedError: "failed to parse endpoint prefixes: unknown endpointPrefixes key \"foo\" at position 1 (allowed: openai, cohere, anthropic, ollama)",
 			},
 			{
 				name:          "invalid endpoint prefixes - missing colon",
 				args:          []string{"-configPath", "/path/to/config.yaml", "-endpointPrefixes", "openai"},
 				expectedError: "failed to parse endpoint prefixes: invalid endpointPrefixes pair at position 1: \"openai\" (expected format: key:value)",
 			},
+			{
+				name:          "invalid MCP audit arguments",
+				args:          []string{"-configPath", "/path/to/config.yaml", "-mcpAuditLog", "stdout", "-mcpAuditArguments", "plain"},
+				expectedError: "failed to parse MCP audit arguments: invalid audit arguments mode \"plain\": must be \"redact\" or \"hash\"",
+			},
+			{
+				name:          "invalid MCP session store",
+				args:          []string{"-configPath", "/path/to/config.yaml", "-mcpSessionStore", "memcached://localhost"},
+				expectedError: "failed to parse MCP session store: invalid session store \"memcached://localhost\": must be \"memory\" or a redis:// or rediss:// URL",
+			},
 			{
 				name:          "invalid tracing header attributes - missing colon",
 				args:          []string{"-configPath", "/path/to/config.yaml", "-spanRequestHeaderAttributes", "x-session-id"},
@@ -0,0 +1,167 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package dashscope contains the types of the native Alibaba Cloud Model Studio (DashScope) API serving the Qwen models.
+//
+// https://www.alibabacloud.com/help/en/model-studio/qwen-api-reference
+package dashscope
+
+import "github.com/envoyproxy/ai-gateway/internal/json"
+
+const (
+	// TextGenerationPath is the path of the text generation API.
+	TextGenerationPath = "/api/v1/services/aigc/text-generation/generation"
+	// TextEmbeddingPath is the path of the text embedding API.
+	TextEmbeddingPath = "/api/v1/services/embeddings/text-embedding/text-embedding"
+
+	// SSEHeaderName is the header enabling the server-sent events of the streamed responses.
+	SSEHeaderName = "x-dashscope-sse"
+
+	// ResultFormatMessage is a Parameters.ResultFormat enum value for the responses in the messages format.
+	ResultFormatMessage = "message"
+
+	// FinishReasonNull is the finish reason of the choices that are not finished yet.
+	FinishReasonNull = "null"
+	// FinishReasonStop is a Choice.FinishReason enum value.
+	FinishReasonStop = "stop"
+	// FinishReasonLength is a Choice.FinishReason enum value.
+	FinishReasonLength = "length"
+	// FinishReasonToolCalls is a Choice.FinishReason enum value.
+	FinishReasonToolCalls = "tool_calls"
+)
+
+// GenerationRequest is the request body of the text generation API.
+type GenerationRequest struct {
+	Model      string          `json:"model"`
+	Input      GenerationInput `json:"input"`
+	Parameters *Parameters     `json:"parameters,omitempty"`
+}
+
+// GenerationInput is the input of the text generation API.
+type GenerationInput struct {
+	Messages []Message `json:"messages"`
+}
+
+// Message is a message of the conversation. The format follows the OpenAI chat completions messages.
+type Message struct {
+	Role             string     `json:"role"`
+	Content          string     `json:"content"`
+	Name             string     `json:"name,omitempty"`
+	ReasoningContent string     `json:"reasoning_content,omitempty"` //nolint:tagliatelle //follow dashscope api
+	ToolCalls        []ToolCall `json:"tool_calls,omitempty"`        //nolint:tagliatelle //follow dashscope api
+	ToolCallID       string     `json:"tool_call_id,omitempty"`      //nolint:tagliatelle //follow dashscope api
+}
+
+// ToolCall is a function call of the model.
+type ToolCall struct {
+	Index    int64            `json:"index"`
+	ID       string           `json:"id,omitempty"`
+	Type     string           `json:"type,omitempty"`
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction is the function called by a ToolCall.
+type ToolCallFunction struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments"`
+}
+
+// Parameters are the generation parameters.
+type Parameters struct {
+	ResultFormat      string          `json:"result_format"`                //nolint:tagliatelle //follow dashscope api
+	IncrementalOutput bool            `json:"incremental_output,omitempty"` //nolint:tagliatelle //follow dashscope api
+	MaxTokens         *int64          `json:"max_tokens,omitempty"`         //nolint:tagliatelle //follow dashscope api
+	Temperature       *float64        `json:"temperature,omitempty"`
+	TopP              *float64        `json:"top_p,omitempty"`            //nolint:tagliatelle //follow dashscope api
+	PresencePenalty   *float32        `json:"presence_penalty,omitempty"` //nolint:tagliatelle //follow dashscope api
+	Seed              *int            `json:"seed,omitempty"`
+	N                 *int            `json:"n,omitempty"`
+	Stop              []string        `json:"stop,omitempty"`
+	Tools             json.RawMessage `json:"tools,omitempty"`
+	ToolChoice        json.RawMessage `json:"tool_choice,omitempty"`         //nolint:tagliatelle //follow dashscope api
+	ParallelToolCalls *bool           `json:"parallel_tool_calls,omitempty"` //nolint:tagliatelle //follow dashscope api
+	ResponseFormat    json.RawMessage `json:"response_format,omitempty"`     //nolint:tagliatelle //follow dashscope api
+	EnableThinking    *bool           `json:"enable_thinking,omitempty"`     //nolint:tagliatelle //follow dashscope api
+}
+
+// GenerationResponse is the response body of the text generation API, as well as the data of each server-sent
+// event of the streamed responses.
+type GenerationResponse struct {
+	RequestID string           `json:"request_id"` //nolint:tagliatelle //follow dashscope api
+	Output    GenerationOutput `json:"output"`
+	Usage     *Usage           `json:"usage,omitempty"`
+}
+
+// GenerationOutput is the output of the text generation API.
+type GenerationOutput struct {
+	Choices []Choice `json:"choices"`
+}
+
+// Choice is a generated message.
+type Choice struct {
+	FinishReason string  `json:"finish_reason"` //nolint:tagliatelle //follow dashscope api
+	Message      Message `json:"message"`
+}
+
+// Usage is the token usage of a request. The streamed responses carry the usage so far in each event.
+type Usage struct {
+	InputTokens         int                  `json:"input_tokens"`                    //nolint:tagliatelle //follow dashscope api
+	OutputTokens        int                  `json:"output_tokens"`                   //nolint:tagliatelle //follow dashscope api
+	TotalTokens         int                  `json:"total_tokens"`                    //nolint:tagliatelle //follow dashscope api
+	PromptTokensDetails *PromptTokensDetails `json:"prompt_tokens_details,omitempty"` //nolint:tagliatelle //follow dashscope api
+	OutputTokensDetails *OutputTokensDetails `json:"output_tokens_details,omitempty"` //nolint:tagliatelle //follow dashscope api
+}
+
+// PromptTokensDetails is the breakdown of the input tokens.
+type PromptTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"` //nolint:tagliatelle //follow dashscope api
+}
+
+// OutputTokensDetails is the breakdown of the output tokens.
+type OutputTokensDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens"` //nolint:tagliatelle //follow dashscope api
+}
+
+// EmbeddingRequest is the request body of the text embedding API.
+type EmbeddingRequest struct {
+	Model      string               `json:"model"`
+	Input      EmbeddingInput       `json:"input"`
+	Parameters *EmbeddingParameters `json:"parameters,omitempty"`
+}
+
+// EmbeddingInput is the input of the text embedding API.
+type EmbeddingInput struct {
+	Texts []string `json:"texts"`
+}
+
+// EmbeddingParameters are the parameters of the text embedding API.
+type EmbeddingParameters struct {
+	Dimension *int `json:"dimension,omitempty"`
+}
+
+// EmbeddingResponse is the response body of the text embedding API.
+type EmbeddingResponse struct {
+	RequestID string          `json:"request_id"` //nolint:tagliatelle //follow dashscope api
+	Output    EmbeddingOutput `json:"output"`
+	Usage     *Usage          `json:"usage,omitempty"`
+}
+
+// EmbeddingOutput is the output of the text embedding API.
+type EmbeddingOutput struct {
+	Embeddings []Embedding `json:"embeddings"`
+}
+
+// Embedding is the embedding of the input text at TextIndex.
+type Embedding struct {
+	TextIndex int       `json:"text_index"` //nolint:tagliatelle //follow dashscope api
+	Embedding []float64 `json:"embedding"`
+}
+
+// Error is the response body of the failed requests.
+type Error struct {
+	RequestID string `json:"request_id"` //nolint:tagliatelle //follow dashscope api
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+}
@@ -0,0 +1,123 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package huggingface contains the schema of the native APIs of Hugging Face Text Generation Inference (TGI)
+// and Text Embeddings Inference (TEI).
+// https://huggingface.github.io/text-generation-inference/
+// https://huggingface.github.io/text-embeddings-inference/
+package huggingface
+
+const (
+	// GeneratePath is the path of the text generation API of TGI.
+	GeneratePath = "/generate"
+	// GenerateStreamPath is the path of the streamed text generation API of TGI.
+	GenerateStreamPath = "/generate_stream"
+	// EmbedPath is the path of the embedding API of TEI.
+	EmbedPath = "/embed"
+
+	// PromptTokensHeaderName is the response header of TGI carrying the number of tokens in the prompt.
+	PromptTokensHeaderName = "x-prompt-tokens"
+	// GeneratedTokensHeaderName is the response header of TGI carrying the number of generated tokens.
+	GeneratedTokensHeaderName = "x-generated-tokens"
+	// ComputeTokensHeaderName is the response header of TEI carrying the number of tokens in the inputs.
+	ComputeTokensHeaderName = "x-compute-tokens"
+)
+
+// Finish reasons.
+const (
+	FinishReasonLength       = "length"
+	FinishReasonEOSToken     = "eos_token"
+	FinishReasonStopSequence = "stop_sequence"
+)
+
+// GenerateRequest is the request of the text generation API of TGI.
+type GenerateRequest struct {
+	// Inputs is the prompt.
+	Inputs string `json:"inputs"`
+	// Parameters are the generation parameters.
+	Parameters *GenerateParameters `json:"parameters,omitempty"`
+}
+
+// GenerateParameters are the generation parameters of TGI.
+type GenerateParameters struct {
+	BestOf            *int     `json:"best_of,omitempty"`
+	Details           bool     `json:"details,omitempty"`
+	DoSample          *bool    `json:"do_sample,omitempty"`
+	FrequencyPenalty  *float64 `json:"frequency_penalty,omitempty"`
+	MaxNewTokens      *int     `json:"max_new_tokens,omitempty"`
+	RepetitionPenalty *float64 `json:"repetition_penalty,omitempty"`
+	ReturnFullText    *bool    `json:"return_full_text,omitempty"`
+	Seed              *int64   `json:"seed,omitempty"`
+	Stop              []string `json:"stop,omitempty"`
+	Temperature       *float64 `json:"temperature,omitempty"`
+	TopK              *int     `json:"top_k,omitempty"`
+	TopNTokens        *int     `json:"top_n_tokens,omitempty"`
+	TopP              *float64 `json:"top_p,omitempty"`
+	Truncate          *int     `json:"truncate,omitempty"`
+	TypicalP          *float64 `json:"typical_p,omitempty"`
+	Watermark         bool     `json:"watermark,omitempty"`
+}
+
+// GenerateResponse is the response of the text generation API of TGI.
+type GenerateResponse struct {
+	GeneratedText string   `json:"generated_text"`
+	Details       *Details `json:"details,omitempty"`
+}
+
+// Details are the details of the generation.
+type Details struct {
+	FinishReason    string  `json:"finish_reason"`
+	GeneratedTokens int     `json:"generated_tokens"`
+	Seed            *int64  `json:"seed,omitempty"`
+	Tokens          []Token `json:"tokens,omitempty"`
+}
+
+// StreamResponse is an event of the streamed text generation API of TGI.
+// The last event has the generated text and the details set.
+type StreamResponse struct {
+	Index         int            `json:"index"`
+	Token         Token          `json:"token"`
+	GeneratedText *string        `json:"generated_text"`
+	Details       *StreamDetails `json:"details"`
+}
+
+// StreamDetails are the details of the streamed generation.
+type StreamDetails struct {
+	FinishReason    string `json:"finish_reason"`
+	GeneratedTokens int    `json:"generated_tokens"`
+	// InputLength is the number of tokens in the prompt.
+	InputLength int    `json:"input_length"`
+	Seed        *int64 `json:"seed,omitempty"`
+}
+
+// Token is a generated token.
+type Token struct {
+	ID      int     `json:"id"`
+	Text    string  `json:"text"`
+	Logprob float64 `json:"logprob"`
+	// Special is whether the token is a special token such as the end of sequence, which is not part of the text.
+	Special bool `json:"special"`
+}
+
+// EmbedRequest is the request of the embedding API of TEI.
+type EmbedRequest struct {
+	// Inputs are the texts to embed.
+	Inputs []string `json:"inputs"`
+	// Normalize is whether the embeddings are normalized. Defaults to true when unset.
+	Normalize *bool `json:"normalize,omitempty"`
+	// Truncate is whether the inputs exceeding the maximum length are truncated.
+	Truncate *bool `json:"truncate,omitempty"`
+	// Dimensions is the number of dimensions of the embeddings of the Matryoshka models.
+	Dimensions *int `json:"dimensions,omitempty"`
+}
+
+// EmbedResponse is the response of the embedding API of TEI, which is the list of the embeddings of the inputs.
+type EmbedResponse [][]float64
+
+// Error is the error response of TGI and TEI.
+type Error struct {
+	Error     string `json:"error"`
+	ErrorType string `json:"error_type"`
+}
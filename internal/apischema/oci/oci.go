@@ -0,0 +1,220 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package oci contains the types of the Oracle Cloud Infrastructure (OCI) Generative AI inference API.
+//
+// https://docs.oracle.com/en-us/iaas/api/#/en/generative-ai-inference/20231130/
+package oci
+
+const (
+	// APIVersion is the version of the Generative AI inference API, used as the prefix of the paths.
+	APIVersion = "20231130"
+
+	// ServingTypeOnDemand is a ServingMode.ServingType enum value for the models served on demand.
+	ServingTypeOnDemand = "ON_DEMAND"
+	// ServingTypeDedicated is a ServingMode.ServingType enum value for the models served by a dedicated AI cluster.
+	ServingTypeDedicated = "DEDICATED"
+
+	// APIFormatGeneric is a ChatRequest.APIFormat enum value for the generic chat format of the Meta, xAI, OpenAI
+	// and Google models among others.
+	APIFormatGeneric = "GENERIC"
+
+	// RoleSystem is a Message.Role enum value.
+	RoleSystem = "SYSTEM"
+	// RoleDeveloper is a Message.Role enum value.
+	RoleDeveloper = "DEVELOPER"
+	// RoleUser is a Message.Role enum value.
+	RoleUser = "USER"
+	// RoleAssistant is a Message.Role enum value.
+	RoleAssistant = "ASSISTANT"
+	// RoleTool is a Message.Role enum value.
+	RoleTool = "TOOL"
+
+	// ContentTypeText is a Content.Type enum value.
+	ContentTypeText = "TEXT"
+	// ContentTypeImage is a Content.Type enum value.
+	ContentTypeImage = "IMAGE"
+
+	// ToolTypeFunction is a ToolDefinition.Type and ToolCall.Type enum value.
+	ToolTypeFunction = "FUNCTION"
+
+	// ToolChoiceTypeAuto is a ToolChoice.Type enum value.
+	ToolChoiceTypeAuto = "AUTO"
+	// ToolChoiceTypeNone is a ToolChoice.Type enum value.
+	ToolChoiceTypeNone = "NONE"
+	// ToolChoiceTypeRequired is a ToolChoice.Type enum value.
+	ToolChoiceTypeRequired = "REQUIRED"
+	// ToolChoiceTypeFunction is a ToolChoice.Type enum value.
+	ToolChoiceTypeFunction = "FUNCTION"
+)
+
+// ServingMode is the model to serve the request with.
+type ServingMode struct {
+	// ServingType is either ON_DEMAND, in which case ModelID is the name or the OCID of the model,
+	// or DEDICATED, in which case EndpointID is the OCID of the dedicated AI cluster endpoint.
+	ServingType string `json:"servingType"`
+	ModelID     string `json:"modelId,omitempty"`
+	EndpointID  string `json:"endpointId,omitempty"`
+}
+
+// ChatDetails is the request body of the chat action.
+// https://docs.oracle.com/en-us/iaas/api/#/en/generative-ai-inference/20231130/ChatResult/Chat
+type ChatDetails struct {
+	CompartmentID string       `json:"compartmentId"`
+	ServingMode   ServingMode  `json:"servingMode"`
+	ChatRequest   *ChatRequest `json:"chatRequest"`
+}
+
+// ChatRequest is the generic chat request.
+// https://docs.oracle.com/en-us/iaas/api/#/en/generative-ai-inference/20231130/datatypes/GenericChatRequest
+type ChatRequest struct {
+	APIFormat           string           `json:"apiFormat"`
+	Messages            []Message        `json:"messages"`
+	IsStream            bool             `json:"isStream,omitempty"`
+	StreamOptions       *StreamOptions   `json:"streamOptions,omitempty"`
+	NumGenerations      *int             `json:"numGenerations,omitempty"`
+	Seed                *int             `json:"seed,omitempty"`
+	MaxTokens           *int64           `json:"maxTokens,omitempty"`
+	MaxCompletionTokens *int64           `json:"maxCompletionTokens,omitempty"`
+	Temperature         *float64         `json:"temperature,omitempty"`
+	TopP                *float64         `json:"topP,omitempty"`
+	FrequencyPenalty    *float32         `json:"frequencyPenalty,omitempty"`
+	PresencePenalty     *float32         `json:"presencePenalty,omitempty"`
+	Stop                []string         `json:"stop,omitempty"`
+	LogProbs            *int             `json:"logProbs,omitempty"`
+	ReasoningEffort     string           `json:"reasoningEffort,omitempty"`
+	Tools               []ToolDefinition `json:"tools,omitempty"`
+	ToolChoice          *ToolChoice      `json:"toolChoice,omitempty"`
+	IsParallelToolCalls *bool            `json:"isParallelToolCalls,omitempty"`
+}
+
+// StreamOptions are the options of the streamed responses.
+type StreamOptions struct {
+	// IsIncludeUsage makes the last event of the stream carry the token usage.
+	IsIncludeUsage bool `json:"isIncludeUsage"`
+}
+
+// Message is a message of the generic chat format.
+type Message struct {
+	Role    string    `json:"role"`
+	Content []Content `json:"content,omitempty"`
+	Name    string    `json:"name,omitempty"`
+	// ToolCalls are the tool calls of the ASSISTANT messages.
+	ToolCalls []ToolCall `json:"toolCalls,omitempty"`
+	// ToolCallID is the ID of the tool call answered by the TOOL messages.
+	ToolCallID string `json:"toolCallId,omitempty"`
+}
+
+// Content is a content part of a message.
+type Content struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"imageUrl,omitempty"`
+}
+
+// ImageURL is the image of an IMAGE content, either as a URL or as a base64 encoded data URI.
+type ImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ToolDefinition is a function the model may call.
+type ToolDefinition struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters,omitempty"`
+}
+
+// ToolChoice controls which tool is called by the model.
+type ToolChoice struct {
+	Type string `json:"type"`
+	// Name is the name of the function to call when Type is FUNCTION.
+	Name string `json:"name,omitempty"`
+}
+
+// ToolCall is a function call of the model.
+type ToolCall struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// ChatResult is the response body of the chat action when not streamed.
+type ChatResult struct {
+	ModelID      string        `json:"modelId"`
+	ModelVersion string        `json:"modelVersion"`
+	ChatResponse *ChatResponse `json:"chatResponse"`
+}
+
+// ChatResponse is the generic chat response.
+type ChatResponse struct {
+	APIFormat   string       `json:"apiFormat"`
+	TimeCreated string       `json:"timeCreated"`
+	Choices     []ChatChoice `json:"choices"`
+	Usage       *Usage       `json:"usage,omitempty"`
+}
+
+// ChatChoice is a generated message.
+type ChatChoice struct {
+	Index        int64   `json:"index"`
+	Message      Message `json:"message"`
+	FinishReason string  `json:"finishReason"`
+}
+
+// StreamEvent is the data of a server-sent event of a streamed chat response in the generic format.
+// The events carry the message deltas, then the finish reason, and finally the usage when requested.
+type StreamEvent struct {
+	Index        int64    `json:"index"`
+	Message      *Message `json:"message,omitempty"`
+	FinishReason string   `json:"finishReason,omitempty"`
+	Usage        *Usage   `json:"usage,omitempty"`
+}
+
+// Usage is the token usage of a request.
+type Usage struct {
+	PromptTokens            int                      `json:"promptTokens"`
+	CompletionTokens        int                      `json:"completionTokens"`
+	TotalTokens             int                      `json:"totalTokens"`
+	PromptTokensDetails     *PromptTokensDetails     `json:"promptTokensDetails,omitempty"`
+	CompletionTokensDetails *CompletionTokensDetails `json:"completionTokensDetails,omitempty"`
+}
+
+// PromptTokensDetails is the breakdown of the prompt tokens.
+type PromptTokensDetails struct {
+	CachedTokens int `json:"cachedTokens"`
+}
+
+// CompletionTokensDetails is the breakdown of the completion tokens.
+type CompletionTokensDetails struct {
+	ReasoningTokens int `json:"reasoningTokens"`
+}
+
+// EmbedTextDetails is the request body of the embedText action.
+// https://docs.oracle.com/en-us/iaas/api/#/en/generative-ai-inference/20231130/EmbedTextResult/EmbedText
+type EmbedTextDetails struct {
+	CompartmentID    string      `json:"compartmentId"`
+	ServingMode      ServingMode `json:"servingMode"`
+	Inputs           []string    `json:"inputs"`
+	OutputDimensions *int        `json:"outputDimensions,omitempty"`
+	Truncate         string      `json:"truncate,omitempty"`
+	InputType        string      `json:"inputType,omitempty"`
+}
+
+// EmbedTextResult is the response body of the embedText action.
+type EmbedTextResult struct {
+	ID           string      `json:"id"`
+	ModelID      string      `json:"modelId"`
+	ModelVersion string      `json:"modelVersion"`
+	Embeddings   [][]float64 `json:"embeddings"`
+	Usage        *Usage      `json:"usage,omitempty"`
+}
+
+// Error is the response body of the failed requests.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
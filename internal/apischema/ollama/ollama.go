@@ -0,0 +1,188 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package ollama contains the schema of the native Ollama API.
+// https://github.com/ollama/ollama/blob/main/docs/api.md
+package ollama
+
+import (
+	"errors"
+
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+const (
+	// ChatPath is the path of the chat API.
+	ChatPath = "/api/chat"
+	// EmbedPath is the path of the embed API.
+	EmbedPath = "/api/embed"
+	// NDJSONContentType is the content type of the streamed responses, which are newline delimited JSON objects.
+	NDJSONContentType = "application/x-ndjson"
+)
+
+// Message roles.
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// Done reasons.
+const (
+	DoneReasonStop   = "stop"
+	DoneReasonLength = "length"
+	DoneReasonLoad   = "load"
+	DoneReasonUnload = "unload"
+)
+
+// ChatRequest is the request of the chat API.
+// https://github.com/ollama/ollama/blob/main/docs/api.md#generate-a-chat-completion
+type ChatRequest struct {
+	// Model is the name of the model.
+	Model string `json:"model"`
+	// Messages are the messages of the chat.
+	Messages []Message `json:"messages"`
+	// Tools are the tools the model may call.
+	Tools []Tool `json:"tools,omitempty"`
+	// Format is either "json" or a JSON schema the response is constrained to.
+	Format json.RawMessage `json:"format,omitempty"`
+	// Options are the model parameters such as temperature, num_predict and num_ctx.
+	// https://github.com/ollama/ollama/blob/main/docs/modelfile.md#valid-parameters-and-values
+	Options map[string]any `json:"options,omitempty"`
+	// Stream is whether the response is streamed. Defaults to true when unset.
+	Stream *bool `json:"stream,omitempty"`
+	// KeepAlive controls how long the model stays loaded after the request, e.g. "5m", 300 or -1.
+	KeepAlive json.RawMessage `json:"keep_alive,omitempty"`
+	// Think is whether the thinking models think before responding. Either a boolean or "low", "medium" or "high".
+	Think json.RawMessage `json:"think,omitempty"`
+}
+
+// IsStream returns whether the response of the request is streamed.
+func (r *ChatRequest) IsStream() bool {
+	return r.Stream == nil || *r.Stream
+}
+
+// Message is a message of the chat.
+type Message struct {
+	// Role is either "system", "user", "assistant" or "tool".
+	Role string `json:"role"`
+	// Content is the content of the message.
+	Content string `json:"content"`
+	// Thinking is the thinking of the model before responding.
+	Thinking string `json:"thinking,omitempty"`
+	// Images are the base64 encoded images of the message.
+	Images []string `json:"images,omitempty"`
+	// ToolCalls are the tools the model called.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// ToolName is the name of the tool whose result the tool message has.
+	ToolName string `json:"tool_name,omitempty"`
+}
+
+// Tool is a tool the model may call.
+type Tool struct {
+	// Type is always "function".
+	Type string `json:"type"`
+	// Function is the function of the tool.
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction is a function the model may call.
+type ToolFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+// ToolCall is a call of a tool by the model.
+type ToolCall struct {
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction is the function the model called.
+type ToolCallFunction struct {
+	// Index is the index of the call in the message.
+	Index int `json:"index,omitempty"`
+	// Name is the name of the function.
+	Name string `json:"name"`
+	// Arguments is the JSON object of the arguments of the call.
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// ChatResponse is the response of the chat API. A streamed response is a sequence of ChatResponse
+// delimited by newlines, the last of which has Done set and carries the metrics of the request.
+type ChatResponse struct {
+	Model      string  `json:"model"`
+	CreatedAt  string  `json:"created_at"`
+	Message    Message `json:"message"`
+	Done       bool    `json:"done"`
+	DoneReason string  `json:"done_reason,omitempty"`
+	Metrics
+}
+
+// Metrics are the metrics of the request, which are set in the final response.
+type Metrics struct {
+	// TotalDuration is the time spent on the request in nanoseconds.
+	TotalDuration int64 `json:"total_duration,omitempty"`
+	// LoadDuration is the time spent loading the model in nanoseconds.
+	LoadDuration int64 `json:"load_duration,omitempty"`
+	// PromptEvalCount is the number of tokens in the prompt.
+	PromptEvalCount int `json:"prompt_eval_count,omitempty"`
+	// PromptEvalDuration is the time spent evaluating the prompt in nanoseconds.
+	PromptEvalDuration int64 `json:"prompt_eval_duration,omitempty"`
+	// EvalCount is the number of tokens in the response.
+	EvalCount int `json:"eval_count,omitempty"`
+	// EvalDuration is the time spent generating the response in nanoseconds.
+	EvalDuration int64 `json:"eval_duration,omitempty"`
+}
+
+// EmbedRequest is the request of the embed API.
+// https://github.com/ollama/ollama/blob/main/docs/api.md#generate-embeddings
+type EmbedRequest struct {
+	// Model is the name of the model.
+	Model string `json:"model"`
+	// Input is the text or the list of texts to embed.
+	Input EmbedInput `json:"input"`
+	// Truncate is whether the inputs exceeding the context length are truncated. Defaults to true when unset.
+	Truncate *bool `json:"truncate,omitempty"`
+	// Dimensions is the number of dimensions of the embeddings.
+	Dimensions *int `json:"dimensions,omitempty"`
+	// Options are the model parameters.
+	Options map[string]any `json:"options,omitempty"`
+	// KeepAlive controls how long the model stays loaded after the request.
+	KeepAlive json.RawMessage `json:"keep_alive,omitempty"`
+}
+
+// EmbedInput is the list of texts to embed, which is either a string or a list of strings in JSON.
+type EmbedInput []string
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (e *EmbedInput) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*e = EmbedInput{text}
+		return nil
+	}
+	var texts []string
+	if err := json.Unmarshal(data, &texts); err != nil {
+		return errors.New("input must be either a string or a list of strings")
+	}
+	*e = texts
+	return nil
+}
+
+// EmbedResponse is the response of the embed API.
+type EmbedResponse struct {
+	Model           string      `json:"model"`
+	Embeddings      [][]float64 `json:"embeddings"`
+	TotalDuration   int64       `json:"total_duration,omitempty"`
+	LoadDuration    int64       `json:"load_duration,omitempty"`
+	PromptEvalCount int         `json:"prompt_eval_count,omitempty"`
+}
+
+// Error is the error response of the API.
+type Error struct {
+	Error string `json:"error"`
+}
@@ -1193,6 +1193,9 @@ type ChatCompletionRequest struct {
 	// GCPVertexAIVendorFields configures the GCP VertexAI specific fields during schema translation.
 	*GCPVertexAIVendorFields `json:",inline,omitempty"`
 
+	// OllamaVendorFields configures the Ollama specific fields during schema translation.
+	*OllamaVendorFields `json:",inline,omitempty"`
+
 	// GuidedChoice: The output will be exactly one of the choices.
 	GuidedChoice []string `json:"guided_choice,omitzero"`
 
@@ -1746,6 +1749,9 @@ type EmbeddingBaseRequest struct {
 
 	// GCPVertexAIEmbeddingVendorFields configures the GCP VertexAI specific fields for embedding during schema translation.
 	*GCPVertexAIEmbeddingVendorFields `json:",inline,omitempty"`
+
+	// OllamaVendorFields configures the Ollama specific fields for embedding during schema translation.
+	*OllamaVendorFields `json:",inline,omitempty"`
 }
 
 // EmbeddingCompletionRequest is the text-only embedding request (classic OpenAI style).
@@ -2009,6 +2015,20 @@ type GCPVertexAIVendorFields struct {
 	SafetySettings []*genai.SafetySetting `json:"safetySettings,omitzero"`
 }
 
+// OllamaVendorFields contains Ollama vendor-specific fields.
+//
+// https://github.com/ollama/ollama/blob/main/docs/api.md
+type OllamaVendorFields struct {
+	// KeepAlive controls how long the model stays loaded after the request, e.g. "5m", 300 or -1.
+	KeepAlive json.RawMessage `json:"keep_alive,omitempty"` //nolint:tagliatelle //follow ollama api
+
+	// Options are the model parameters such as num_ctx and repeat_penalty. They take precedence over
+	// the ones translated from the OpenAI fields such as temperature.
+	//
+	// https://github.com/ollama/ollama/blob/main/docs/modelfile.md#valid-parameters-and-values
+	Options map[string]any `json:"options,omitempty"`
+}
+
 // GCPVertexAIGenerationConfig represents Gemini generation configuration options.
 type GCPVertexAIGenerationConfig struct {
 	// MediaResolution is to set global media resolution in gemini models: https://ai.google.dev/api/caching#MediaResolution
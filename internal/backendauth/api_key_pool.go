@@ -0,0 +1,248 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package backendauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+)
+
+// APIKeyPoolMetrics records the usage of the keys of the API key pools. Nil means the usage is not recorded.
+var APIKeyPoolMetrics metrics.APIKeyPoolMetrics
+
+const (
+	// defaultAPIKeyEjectionDuration is the ejection duration of the pools that don't specify one.
+	defaultAPIKeyEjectionDuration = 30 * time.Second
+	// maxAPIKeyEjectionDuration caps the ejection durations requested by the Retry-After headers of the backends.
+	maxAPIKeyEjectionDuration = time.Hour
+)
+
+// apiKeyStates are the ejection states of the keys of the pools by fingerprint. They are shared by all the handlers,
+// so that the ejections survive the reloads of the configuration and apply to all the backends using a key.
+// The states of the keys removed from the configuration are dropped by PruneAPIKeyStates.
+var apiKeyStates sync.Map // map[string]*apiKeyState
+
+// apiKeyState is the ejection state of an API key.
+type apiKeyState struct {
+	mu              sync.Mutex
+	ejectedUntil    time.Time
+	lastRateLimited time.Time
+}
+
+// apiKeyPoolHandler implements [filterapi.BackendAuthHandler] and [filterapi.BackendAuthResponseHandler] for a pool
+// of API keys.
+type apiKeyPoolHandler struct {
+	// header is the lower-cased header the keys are injected into.
+	header           string
+	strategy         filterapi.APIKeyPoolStrategy
+	ejectionDuration time.Duration
+	keys             []*pooledAPIKey
+	// byValue are the keys by the value of the header they are injected with.
+	byValue map[string]*pooledAPIKey
+	now     func() time.Time
+
+	// mu protects the current weights of the keys.
+	mu sync.Mutex
+}
+
+// pooledAPIKey is an API key of a pool.
+type pooledAPIKey struct {
+	// value is the value of the header the key is injected with.
+	value       string
+	fingerprint string
+	weight      int
+	// currentWeight is the current weight of the key in the smooth weighted round-robin.
+	currentWeight int
+	state         *apiKeyState
+}
+
+func newAPIKeyPoolHandler(auth *filterapi.APIKeyPoolAuth) (filterapi.BackendAuthHandler, error) {
+	if len(auth.Keys) == 0 {
+		return nil, errors.New("the API key pool has no keys")
+	}
+	h := &apiKeyPoolHandler{
+		header:           strings.ToLower(auth.Header),
+		strategy:         auth.Strategy,
+		ejectionDuration: auth.EjectionDuration,
+		byValue:          make(map[string]*pooledAPIKey, len(auth.Keys)),
+		now:              time.Now,
+	}
+	if h.header == "" {
+		h.header = "authorization"
+	}
+	if h.ejectionDuration <= 0 {
+		h.ejectionDuration = defaultAPIKeyEjectionDuration
+	}
+	for _, k := range auth.Keys {
+		key := strings.TrimSpace(k.Key)
+		if key == "" {
+			return nil, errors.New("the API key pool has an empty key")
+		}
+		fingerprint := apiKeyFingerprint(key)
+		state, _ := apiKeyStates.LoadOrStore(fingerprint, &apiKeyState{})
+		pooled := &pooledAPIKey{
+			value:       key,
+			fingerprint: fingerprint,
+			weight:      max(k.Weight, 1),
+			state:       state.(*apiKeyState),
+		}
+		if h.header == "authorization" {
+			pooled.value = "Bearer " + key
+		}
+		h.keys = append(h.keys, pooled)
+		h.byValue[pooled.value] = pooled
+	}
+	return h, nil
+}
+
+// PruneAPIKeyStates drops the ejection states of the keys that are not in the pools of the given backends, so that
+// the states of the removed keys are not kept forever. It is called once the handlers of the new configuration are
+// created, so that the states of the keys that are still used are kept.
+func PruneAPIKeyStates(backends []filterapi.Backend) {
+	fingerprints := make(map[string]struct{})
+	for i := range backends {
+		if auth := backends[i].Auth; auth != nil && auth.APIKeyPool != nil {
+			for _, k := range auth.APIKeyPool.Keys {
+				fingerprints[apiKeyFingerprint(strings.TrimSpace(k.Key))] = struct{}{}
+			}
+		}
+	}
+	apiKeyStates.Range(func(fingerprint, _ any) bool {
+		if _, ok := fingerprints[fingerprint.(string)]; !ok {
+			apiKeyStates.Delete(fingerprint)
+		}
+		return true
+	})
+}
+
+// apiKeyFingerprint returns the fingerprint identifying the given key in the metrics and logs, which cannot be used
+// to recover the key.
+func apiKeyFingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "sha256:" + hex.EncodeToString(sum[:])[:12]
+}
+
+// Do implements [filterapi.BackendAuthHandler.Do].
+//
+// Picks a key of the pool according to the strategy and sets it in the configured header.
+func (a *apiKeyPoolHandler) Do(_ context.Context, requestHeaders map[string]string, _ []byte) ([]internalapi.Header, error) {
+	key := a.pick()
+	requestHeaders[a.header] = key.value
+	return []internalapi.Header{{a.header, key.value}}, nil
+}
+
+// pick returns the key of the next request.
+func (a *apiKeyPoolHandler) pick() *pooledAPIKey {
+	now := a.now()
+	type candidate struct {
+		key             *pooledAPIKey
+		ejectedUntil    time.Time
+		lastRateLimited time.Time
+	}
+	all := make([]candidate, len(a.keys))
+	available := make([]candidate, 0, len(a.keys))
+	for i, k := range a.keys {
+		k.state.mu.Lock()
+		all[i] = candidate{key: k, ejectedUntil: k.state.ejectedUntil, lastRateLimited: k.state.lastRateLimited}
+		k.state.mu.Unlock()
+		if !all[i].ejectedUntil.After(now) {
+			available = append(available, all[i])
+		}
+	}
+	if len(available) == 0 {
+		// Rather than failing the request, use the key that is expected to be accepted the soonest.
+		return slices.MinFunc(all, func(x, y candidate) int { return x.ejectedUntil.Compare(y.ejectedUntil) }).key
+	}
+	if a.strategy == filterapi.APIKeyPoolStrategyLeastRecentlyRateLimited {
+		oldest := slices.MinFunc(available, func(x, y candidate) int {
+			return x.lastRateLimited.Compare(y.lastRateLimited)
+		}).lastRateLimited
+		available = slices.DeleteFunc(available, func(c candidate) bool { return !c.lastRateLimited.Equal(oldest) })
+	}
+
+	// Smooth weighted round-robin, which spreads the requests of each key evenly instead of sending them in bursts.
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var best *pooledAPIKey
+	total := 0
+	for _, c := range available {
+		c.key.currentWeight += c.key.weight
+		total += c.key.weight
+		if best == nil || c.key.currentWeight > best.currentWeight {
+			best = c.key
+		}
+	}
+	best.currentWeight -= total
+	return best
+}
+
+// ResponseHeaders implements [filterapi.BackendAuthResponseHandler.ResponseHeaders].
+//
+// Ejects the key of the request when the backend rejected it with a 401 or rate limited it with a 429.
+func (a *apiKeyPoolHandler) ResponseHeaders(ctx context.Context, requestHeaders map[string]string, responseHeaders map[string]string) {
+	key, ok := a.byValue[requestHeaders[a.header]]
+	if !ok {
+		return
+	}
+	status, _ := strconv.Atoi(responseHeaders[":status"])
+	if APIKeyPoolMetrics != nil {
+		APIKeyPoolMetrics.RecordResponse(ctx, key.fingerprint, status)
+	}
+
+	now := a.now()
+	var reason metrics.APIKeyEjectionReason
+	duration := a.ejectionDuration
+	switch status {
+	case http.StatusUnauthorized:
+		reason = metrics.APIKeyEjectionUnauthorized
+	case http.StatusTooManyRequests:
+		reason = metrics.APIKeyEjectionRateLimited
+		if d := retryAfter(responseHeaders["retry-after"], now); d > 0 {
+			duration = min(d, maxAPIKeyEjectionDuration)
+		}
+	default:
+		return
+	}
+
+	key.state.mu.Lock()
+	if until := now.Add(duration); until.After(key.state.ejectedUntil) {
+		key.state.ejectedUntil = until
+	}
+	if reason == metrics.APIKeyEjectionRateLimited {
+		key.state.lastRateLimited = now
+	}
+	key.state.mu.Unlock()
+	if APIKeyPoolMetrics != nil {
+		APIKeyPoolMetrics.RecordEjection(ctx, key.fingerprint, reason)
+	}
+}
+
+// retryAfter returns the duration of the given Retry-After header value, which is either a number of seconds or an
+// HTTP date. It returns zero if the value is invalid.
+func retryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return date.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,230 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package backendauth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+)
+
+// newTestAPIKeyPoolHandler creates a handler whose keys are unique to the test, since the ejection states of the keys
+// are shared by all the handlers.
+func newTestAPIKeyPoolHandler(t *testing.T, auth filterapi.APIKeyPoolAuth, now *time.Time) *apiKeyPoolHandler {
+	for i := range auth.Keys {
+		auth.Keys[i].Key = t.Name() + "-" + auth.Keys[i].Key
+	}
+	handler, err := newAPIKeyPoolHandler(&auth)
+	require.NoError(t, err)
+	h := handler.(*apiKeyPoolHandler)
+	h.now = func() time.Time { return *now }
+	return h
+}
+
+// doKeys returns the keys set by n requests, without the test prefix.
+func doKeys(t *testing.T, h *apiKeyPoolHandler, n int) []string {
+	var keys []string
+	for range n {
+		requestHeaders := map[string]string{}
+		hdrs, err := h.Do(t.Context(), requestHeaders, nil)
+		require.NoError(t, err)
+		require.Len(t, hdrs, 1)
+		require.Equal(t, requestHeaders[hdrs[0][0]], hdrs[0][1])
+		key, ok := h.byValue[hdrs[0][1]]
+		require.True(t, ok)
+		keys = append(keys, key.value[strings.LastIndex(key.value, "-")+1:])
+	}
+	return keys
+}
+
+func TestNewAPIKeyPoolHandler(t *testing.T) {
+	_, err := newAPIKeyPoolHandler(&filterapi.APIKeyPoolAuth{})
+	require.ErrorContains(t, err, "the API key pool has no keys")
+	_, err = newAPIKeyPoolHandler(&filterapi.APIKeyPoolAuth{Keys: []filterapi.APIKeyPoolKey{{Key: " \n"}}})
+	require.ErrorContains(t, err, "the API key pool has an empty key")
+
+	handler, err := newAPIKeyPoolHandler(&filterapi.APIKeyPoolAuth{Keys: []filterapi.APIKeyPoolKey{{Key: "key \n"}}})
+	require.NoError(t, err)
+	h := handler.(*apiKeyPoolHandler)
+	require.Equal(t, "authorization", h.header)
+	require.Equal(t, defaultAPIKeyEjectionDuration, h.ejectionDuration)
+	require.Equal(t, "Bearer key", h.keys[0].value)
+	require.Equal(t, 1, h.keys[0].weight)
+	require.Regexp(t, `^sha256:[0-9a-f]{12}$`, h.keys[0].fingerprint)
+	require.NotContains(t, h.keys[0].fingerprint, "key")
+
+	handler, err = newAPIKeyPoolHandler(&filterapi.APIKeyPoolAuth{
+		Keys:   []filterapi.APIKeyPoolKey{{Key: "key"}},
+		Header: "X-API-Key",
+	})
+	require.NoError(t, err)
+	h = handler.(*apiKeyPoolHandler)
+	require.Equal(t, "x-api-key", h.header)
+	require.Equal(t, "key", h.keys[0].value)
+}
+
+func TestAPIKeyPoolHandler_WeightedRoundRobin(t *testing.T) {
+	now := time.Now()
+	h := newTestAPIKeyPoolHandler(t, filterapi.APIKeyPoolAuth{
+		Keys: []filterapi.APIKeyPoolKey{{Key: "a", Weight: 2}, {Key: "b", Weight: 1}},
+	}, &now)
+	require.Equal(t, []string{"a", "b", "a", "a", "b", "a"}, doKeys(t, h, 6))
+}
+
+func TestAPIKeyPoolHandler_Ejection(t *testing.T) {
+	now := time.Now()
+	h := newTestAPIKeyPoolHandler(t, filterapi.APIKeyPoolAuth{
+		Keys:             []filterapi.APIKeyPoolKey{{Key: "a"}, {Key: "b"}},
+		Header:           "x-api-key",
+		EjectionDuration: time.Minute,
+	}, &now)
+	m := &fakeAPIKeyPoolMetrics{}
+	APIKeyPoolMetrics = m
+	t.Cleanup(func() { APIKeyPoolMetrics = nil })
+
+	a := map[string]string{"x-api-key": h.keys[0].value}
+	b := map[string]string{"x-api-key": h.keys[1].value}
+
+	h.ResponseHeaders(t.Context(), a, map[string]string{":status": "200"})
+	require.Equal(t, []string{"a", "b"}, doKeys(t, h, 2))
+
+	// The key a is ejected for the ejection duration after a 401.
+	h.ResponseHeaders(t.Context(), a, map[string]string{":status": "401"})
+	require.Equal(t, []string{"b", "b"}, doKeys(t, h, 2))
+
+	// The key b is ejected for the Retry-After duration after a 429.
+	h.ResponseHeaders(t.Context(), b, map[string]string{":status": "429", "retry-after": "120"})
+	// When all the keys are ejected, the key whose ejection ends first is used.
+	require.Equal(t, []string{"a"}, doKeys(t, h, 1))
+
+	now = now.Add(time.Minute)
+	require.Equal(t, []string{"a", "a"}, doKeys(t, h, 2))
+	now = now.Add(time.Minute)
+	require.ElementsMatch(t, []string{"a", "b"}, doKeys(t, h, 2))
+
+	// The responses of the requests that didn't use a key of the pool are ignored.
+	h.ResponseHeaders(t.Context(), map[string]string{"x-api-key": "other"}, map[string]string{":status": "401"})
+
+	require.Equal(t, []fakeAPIKeyResponse{
+		{h.keys[0].fingerprint, http.StatusOK},
+		{h.keys[0].fingerprint, http.StatusUnauthorized},
+		{h.keys[1].fingerprint, http.StatusTooManyRequests},
+	}, m.responses)
+	require.Equal(t, []fakeAPIKeyEjection{
+		{h.keys[0].fingerprint, metrics.APIKeyEjectionUnauthorized},
+		{h.keys[1].fingerprint, metrics.APIKeyEjectionRateLimited},
+	}, m.ejections)
+}
+
+func TestAPIKeyPoolHandler_EjectionSurvivesReload(t *testing.T) {
+	now := time.Now()
+	auth := filterapi.APIKeyPoolAuth{Keys: []filterapi.APIKeyPoolKey{{Key: "a"}, {Key: "b"}}}
+	h := newTestAPIKeyPoolHandler(t, auth, &now)
+	h.ResponseHeaders(t.Context(), map[string]string{"authorization": h.keys[0].value}, map[string]string{":status": "401"})
+
+	reloaded, err := newAPIKeyPoolHandler(&auth)
+	require.NoError(t, err)
+	reloaded.(*apiKeyPoolHandler).now = h.now
+	require.Equal(t, []string{"b", "b"}, doKeys(t, reloaded.(*apiKeyPoolHandler), 2))
+}
+
+func TestPruneAPIKeyStates(t *testing.T) {
+	now := time.Now()
+	h := newTestAPIKeyPoolHandler(t, filterapi.APIKeyPoolAuth{Keys: []filterapi.APIKeyPoolKey{{Key: "a"}, {Key: "b"}}}, &now)
+	kept, removed := h.keys[0], h.keys[1]
+
+	PruneAPIKeyStates([]filterapi.Backend{
+		{Name: "no-auth"},
+		{Name: "pool", Auth: &filterapi.BackendAuth{APIKeyPool: &filterapi.APIKeyPoolAuth{
+			Keys: []filterapi.APIKeyPoolKey{{Key: strings.TrimPrefix(kept.value, "Bearer ") + "\n"}},
+		}}},
+	})
+	state, ok := apiKeyStates.Load(kept.fingerprint)
+	require.True(t, ok)
+	require.Same(t, kept.state, state)
+	_, ok = apiKeyStates.Load(removed.fingerprint)
+	require.False(t, ok)
+
+	PruneAPIKeyStates(nil)
+	_, ok = apiKeyStates.Load(kept.fingerprint)
+	require.False(t, ok)
+}
+
+func TestAPIKeyPoolHandler_LeastRecentlyRateLimited(t *testing.T) {
+	now := time.Now()
+	h := newTestAPIKeyPoolHandler(t, filterapi.APIKeyPoolAuth{
+		Keys:             []filterapi.APIKeyPoolKey{{Key: "a"}, {Key: "b"}, {Key: "c"}},
+		Strategy:         filterapi.APIKeyPoolStrategyLeastRecentlyRateLimited,
+		EjectionDuration: time.Second,
+	}, &now)
+	rateLimit := func(i int) {
+		h.ResponseHeaders(t.Context(), map[string]string{"authorization": h.keys[i].value}, map[string]string{":status": "429"})
+	}
+
+	// The keys that were never rate limited are balanced.
+	require.Equal(t, []string{"a", "b", "c"}, doKeys(t, h, 3))
+
+	rateLimit(0)
+	now = now.Add(time.Second)
+	rateLimit(1)
+	now = now.Add(time.Second)
+	// Only c was never rate limited.
+	require.Equal(t, []string{"c", "c"}, doKeys(t, h, 2))
+
+	rateLimit(2)
+	now = now.Add(time.Second)
+	// a was rate limited the least recently.
+	require.Equal(t, []string{"a", "a"}, doKeys(t, h, 2))
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.Zero(t, retryAfter("", now))
+	require.Zero(t, retryAfter("soon", now))
+	require.Equal(t, 5*time.Second, retryAfter("5", now))
+	require.Equal(t, time.Minute, retryAfter(now.Add(time.Minute).Format(http.TimeFormat), now))
+}
+
+func TestAPIKeyPoolHandler_RetryAfterIsCapped(t *testing.T) {
+	now := time.Now()
+	h := newTestAPIKeyPoolHandler(t, filterapi.APIKeyPoolAuth{Keys: []filterapi.APIKeyPoolKey{{Key: "a"}}}, &now)
+	h.ResponseHeaders(t.Context(), map[string]string{"authorization": h.keys[0].value},
+		map[string]string{":status": "429", "retry-after": "86400"})
+	require.Equal(t, now.Add(maxAPIKeyEjectionDuration), h.keys[0].state.ejectedUntil)
+}
+
+type fakeAPIKeyResponse struct {
+	fingerprint string
+	status      int
+}
+
+type fakeAPIKeyEjection struct {
+	fingerprint string
+	reason      metrics.APIKeyEjectionReason
+}
+
+// fakeAPIKeyPoolMetrics records the usage of the keys.
+type fakeAPIKeyPoolMetrics struct {
+	responses []fakeAPIKeyResponse
+	ejections []fakeAPIKeyEjection
+}
+
+// RecordResponse implements [metrics.APIKeyPoolMetrics.RecordResponse].
+func (f *fakeAPIKeyPoolMetrics) RecordResponse(_ context.Context, fingerprint string, statusCode int) {
+	f.responses = append(f.responses, fakeAPIKeyResponse{fingerprint, statusCode})
+}
+
+// RecordEjection implements [metrics.APIKeyPoolMetrics.RecordEjection].
+func (f *fakeAPIKeyPoolMetrics) RecordEjection(_ context.Context, fingerprint string, reason metrics.APIKeyEjectionReason) {
+	f.ejections = append(f.ejections, fakeAPIKeyEjection{fingerprint, reason})
+}
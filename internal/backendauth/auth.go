@@ -27,6 +27,10 @@ func NewHandler(ctx context.Context, config *filterapi.BackendAuth) (filterapi.B
 		return newGCPHandler(ctx, config.GCPAuth)
 	case config.AnthropicAPIKey != nil:
 		return newAnthropicAPIKeyHandler(config.AnthropicAPIKey)
+	case config.APIKeyPool != nil:
+		return newAPIKeyPoolHandler(config.APIKeyPool)
+	case config.RequestSigning != nil:
+		return newRequestSigningHandler(config.RequestSigning)
 	default:
 		return nil, errors.New("no backend auth handler found")
 	}
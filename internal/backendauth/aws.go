@@ -24,6 +24,10 @@ import (
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 )
 
+// awsCredentialsExpiryWindow is how long before their expiry the temporary credentials are refreshed, so that
+// the requests in flight are not signed with credentials expiring before they reach AWS.
+const awsCredentialsExpiryWindow = 5 * time.Minute
+
 // awsHandler implements [Handler] for AWS Bedrock authz.
 type awsHandler struct {
 	credentialsProvider aws.CredentialsProvider
@@ -63,10 +67,16 @@ func newAWSHandler(ctx context.Context, awsAuth *filterapi.AWSAuth) (filterapi.B
 			return nil, fmt.Errorf("cannot load from credentials file: %w", err)
 		}
 	} else {
-		// Use default credential chain (supports IRSA, EKS Pod Identity, etc.)
+		// Use the default credential chain: environment variables, IRSA web identity token files, EKS Pod Identity
+		// agent and IMDS. The temporary credentials are cached and refreshed in-process before they expire, and
+		// the endpoints can be overridden with the standard AWS environment variables such as AWS_ENDPOINT_URL_STS,
+		// AWS_CONTAINER_CREDENTIALS_FULL_URI and AWS_EC2_METADATA_SERVICE_ENDPOINT.
 		cfg, err = config.LoadDefaultConfig(
 			ctx,
 			config.WithRegion(awsAuth.Region),
+			config.WithCredentialsCacheOptions(func(o *aws.CredentialsCacheOptions) {
+				o.ExpiryWindow = awsCredentialsExpiryWindow
+			}),
 		)
 		if err != nil {
 			return nil, fmt.Errorf("cannot load AWS config: %w", err)
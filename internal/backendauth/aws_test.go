@@ -6,8 +6,16 @@
 package backendauth
 
 import (
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -231,3 +239,119 @@ func TestAWSHandler_Do(t *testing.T) {
 		}
 	})
 }
+
+// clearAWSCredentialEnv clears the environment variables of the default credential chain, so that only the
+// provider under test can succeed.
+func clearAWSCredentialEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{
+		"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_PROFILE",
+		"AWS_ROLE_ARN", "AWS_WEB_IDENTITY_TOKEN_FILE", "AWS_ROLE_SESSION_NAME", "AWS_ENDPOINT_URL_STS",
+		"AWS_CONTAINER_CREDENTIALS_FULL_URI", "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI",
+		"AWS_CONTAINER_AUTHORIZATION_TOKEN", "AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE",
+		"AWS_EC2_METADATA_SERVICE_ENDPOINT",
+	} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/dev/null")
+	t.Setenv("AWS_CONFIG_FILE", "/dev/null")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+}
+
+func TestAWSHandler_DefaultCredentialChain(t *testing.T) {
+	body := map[string]string{":method": "POST", ":path": "/model/test/converse"}
+
+	t.Run("IRSA web identity", func(t *testing.T) {
+		clearAWSCredentialEnv(t)
+		tokenFile := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(tokenFile, []byte("web-identity-token"), 0o600))
+
+		var calls atomic.Int32
+		sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := calls.Add(1)
+			require.NoError(t, r.ParseForm())
+			require.Equal(t, "AssumeRoleWithWebIdentity", r.Form.Get("Action"))
+			require.Equal(t, "arn:aws:iam::123456789012:role/bedrock", r.Form.Get("RoleArn"))
+			require.Equal(t, "web-identity-token", r.Form.Get("WebIdentityToken"))
+			// The first credentials expire within the expiry window, so they are refreshed on the next request.
+			expiration := time.Now().Add(time.Hour)
+			if n == 1 {
+				expiration = time.Now().Add(awsCredentialsExpiryWindow / 2)
+			}
+			w.Header().Set("Content-Type", "text/xml")
+			_, _ = fmt.Fprintf(w, `<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+<AssumeRoleWithWebIdentityResult><Credentials>
+<AccessKeyId>ASIAIRSA%d</AccessKeyId><SecretAccessKey>irsa-secret</SecretAccessKey><SessionToken>irsa-token</SessionToken>
+<Expiration>%s</Expiration>
+</Credentials></AssumeRoleWithWebIdentityResult></AssumeRoleWithWebIdentityResponse>`,
+				n, expiration.UTC().Format(time.RFC3339))
+		}))
+		t.Cleanup(sts.Close)
+		t.Setenv("AWS_ROLE_ARN", "arn:aws:iam::123456789012:role/bedrock")
+		t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", tokenFile)
+		t.Setenv("AWS_ENDPOINT_URL_STS", sts.URL)
+
+		handler, err := newAWSHandler(t.Context(), &filterapi.AWSAuth{Region: "us-east-1"})
+		require.NoError(t, err)
+		for _, expected := range []string{"ASIAIRSA1", "ASIAIRSA2", "ASIAIRSA2"} {
+			hdrs, err := handler.Do(t.Context(), maps.Clone(body), nil)
+			require.NoError(t, err)
+			headers := stringPairsToMap(hdrs)
+			require.Contains(t, headers["Authorization"], "Credential="+expected+"/")
+			require.Equal(t, "irsa-token", headers["X-Amz-Security-Token"])
+		}
+		// The credentials are cached until they are close to their expiry.
+		require.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("EKS Pod Identity", func(t *testing.T) {
+		clearAWSCredentialEnv(t)
+		tokenFile := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(tokenFile, []byte("pod-identity-token"), 0o600))
+
+		agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "pod-identity-token", r.Header.Get("Authorization"))
+			_, _ = fmt.Fprintf(w, `{"AccessKeyId":"ASIAPODIDENTITY","SecretAccessKey":"pod-secret","Token":"pod-token","Expiration":%q}`,
+				time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+		}))
+		t.Cleanup(agent.Close)
+		t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", agent.URL+"/v1/credentials")
+		t.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE", tokenFile)
+
+		handler, err := newAWSHandler(t.Context(), &filterapi.AWSAuth{Region: "us-east-1"})
+		require.NoError(t, err)
+		hdrs, err := handler.Do(t.Context(), maps.Clone(body), nil)
+		require.NoError(t, err)
+		headers := stringPairsToMap(hdrs)
+		require.Contains(t, headers["Authorization"], "Credential=ASIAPODIDENTITY/")
+		require.Equal(t, "pod-token", headers["X-Amz-Security-Token"])
+	})
+
+	t.Run("IMDS", func(t *testing.T) {
+		clearAWSCredentialEnv(t)
+		imds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/latest/api/token":
+				_, _ = w.Write([]byte("imds-token"))
+			case "/latest/meta-data/iam/security-credentials/":
+				_, _ = w.Write([]byte("node-role"))
+			case "/latest/meta-data/iam/security-credentials/node-role":
+				_, _ = fmt.Fprintf(w, `{"Code":"Success","AccessKeyId":"ASIAIMDS","SecretAccessKey":"imds-secret","Token":"imds-token","Expiration":%q}`,
+					time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		t.Cleanup(imds.Close)
+		t.Setenv("AWS_EC2_METADATA_DISABLED", "")
+		t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", imds.URL)
+
+		handler, err := newAWSHandler(t.Context(), &filterapi.AWSAuth{Region: "us-east-1"})
+		require.NoError(t, err)
+		hdrs, err := handler.Do(t.Context(), maps.Clone(body), nil)
+		require.NoError(t, err)
+		headers := stringPairsToMap(hdrs)
+		require.Contains(t, headers["Authorization"], "Credential=ASIAIMDS/")
+		require.Equal(t, "imds-token", headers["X-Amz-Security-Token"])
+	})
+}
@@ -10,22 +10,74 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+
 	"github.com/envoyproxy/ai-gateway/internal/filterapi"
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 )
 
 type azureHandler struct {
 	azureAccessToken string
+	// credential acquires the access tokens when the identity of the gateway pods is used. The credentials cache
+	// the tokens and refresh them before they expire.
+	credential azcore.TokenCredential
+	scope      string
 }
 
 func newAzureHandler(auth *filterapi.AzureAuth) (filterapi.BackendAuthHandler, error) {
+	if auth.Identity != nil {
+		credential, err := newAzureIdentityCredential(auth.Identity, azcore.ClientOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &azureHandler{credential: credential, scope: auth.Identity.Scope}, nil
+	}
 	return &azureHandler{azureAccessToken: strings.TrimSpace(auth.AccessToken)}, nil
 }
 
+// newAzureIdentityCredential creates the credential of the given identity.
+func newAzureIdentityCredential(identity *filterapi.AzureIdentity, clientOptions azcore.ClientOptions) (azcore.TokenCredential, error) {
+	switch identity.Type {
+	case filterapi.AzureIdentityTypeManagedIdentity:
+		options := &azidentity.ManagedIdentityCredentialOptions{ClientOptions: clientOptions}
+		if identity.ClientID != "" {
+			options.ID = azidentity.ClientID(identity.ClientID)
+		}
+		credential, err := azidentity.NewManagedIdentityCredential(options)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create Azure managed identity credential: %w", err)
+		}
+		return credential, nil
+	case filterapi.AzureIdentityTypeWorkloadIdentity:
+		credential, err := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			ClientOptions: clientOptions,
+			ClientID:      identity.ClientID,
+			TenantID:      identity.TenantID,
+			TokenFilePath: identity.TokenFilePath,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot create Azure workload identity credential: %w", err)
+		}
+		return credential, nil
+	default:
+		return nil, fmt.Errorf("unsupported Azure identity type %q", identity.Type)
+	}
+}
+
 // Do implements [Handler.Do].
 //
-// Extracts the azure access token from the local file and set it as an authorization header.
-func (a *azureHandler) Do(_ context.Context, requestHeaders map[string]string, _ []byte) ([]internalapi.Header, error) {
-	requestHeaders["Authorization"] = fmt.Sprintf("Bearer %s", a.azureAccessToken)
-	return []internalapi.Header{{"Authorization", fmt.Sprintf("Bearer %s", a.azureAccessToken)}}, nil
+// Sets the azure access token as an authorization header, acquiring it with the identity of the gateway pods if configured.
+func (a *azureHandler) Do(ctx context.Context, requestHeaders map[string]string, _ []byte) ([]internalapi.Header, error) {
+	accessToken := a.azureAccessToken
+	if a.credential != nil {
+		token, err := a.credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{a.scope}})
+		if err != nil {
+			return nil, fmt.Errorf("cannot acquire Azure access token: %w", err)
+		}
+		accessToken = token.Token
+	}
+	requestHeaders["Authorization"] = fmt.Sprintf("Bearer %s", accessToken)
+	return []internalapi.Header{{"Authorization", fmt.Sprintf("Bearer %s", accessToken)}}, nil
 }
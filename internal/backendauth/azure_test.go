@@ -6,11 +6,22 @@
 package backendauth
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/stretchr/testify/require"
 
 	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 )
 
 func TestNewAzureHandler(t *testing.T) {
@@ -40,3 +51,98 @@ func TestNewAzureHandler_Do(t *testing.T) {
 	require.Equal(t, "Authorization", headers[0][0])
 	require.Equal(t, "Bearer some-access-token", headers[0][1])
 }
+
+// redirectTransport sends all the requests to the target server, for faking the fixed Azure endpoints like IMDS.
+type redirectTransport struct {
+	target *url.URL
+	client *http.Client
+}
+
+func (r *redirectTransport) Do(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme, req.URL.Host = r.target.Scheme, r.target.Host
+	return r.client.Do(req)
+}
+
+func TestAzureHandler_ManagedIdentity(t *testing.T) {
+	var calls atomic.Int32
+	imds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		require.Equal(t, "/metadata/identity/oauth2/token", r.URL.Path)
+		require.Equal(t, "true", r.Header.Get("Metadata"))
+		require.Equal(t, "https://cognitiveservices.azure.com", r.URL.Query().Get("resource"))
+		require.Equal(t, "user-assigned-client-id", r.URL.Query().Get("client_id"))
+		_, _ = fmt.Fprintf(w, `{"access_token":"mi-token","expires_in":"3600","expires_on":"%d","token_type":"Bearer","resource":"https://cognitiveservices.azure.com"}`,
+			time.Now().Add(time.Hour).Unix())
+	}))
+	t.Cleanup(imds.Close)
+	target, err := url.Parse(imds.URL)
+	require.NoError(t, err)
+
+	credential, err := newAzureIdentityCredential(&filterapi.AzureIdentity{
+		Type:     filterapi.AzureIdentityTypeManagedIdentity,
+		ClientID: "user-assigned-client-id",
+	}, azcore.ClientOptions{Transport: &redirectTransport{target: target, client: imds.Client()}})
+	require.NoError(t, err)
+	handler := &azureHandler{credential: credential, scope: "https://cognitiveservices.azure.com/.default"}
+
+	for range 2 {
+		requestHeaders := map[string]string{}
+		headers, err := handler.Do(t.Context(), requestHeaders, nil)
+		require.NoError(t, err)
+		require.Equal(t, "Bearer mi-token", requestHeaders["Authorization"])
+		require.Equal(t, []internalapi.Header{{"Authorization", "Bearer mi-token"}}, headers)
+	}
+	// The token is cached until it needs to be refreshed.
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestAzureHandler_WorkloadIdentity(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "azure-identity-token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("federated-token"), 0o600))
+
+	var authority *httptest.Server
+	authority = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/common/discovery/instance":
+			_, _ = fmt.Fprintf(w, `{"tenant_discovery_endpoint":"%s/my-tenant/v2.0/.well-known/openid-configuration","api-version":"1.1","metadata":[]}`,
+				authority.URL)
+		case "/my-tenant/v2.0/.well-known/openid-configuration":
+			_, _ = fmt.Fprintf(w, `{"token_endpoint":"%[1]s/my-tenant/oauth2/v2.0/token","authorization_endpoint":"%[1]s/my-tenant/oauth2/v2.0/authorize","issuer":"%[1]s/my-tenant/v2.0"}`,
+				authority.URL)
+		case "/my-tenant/oauth2/v2.0/token":
+			require.NoError(t, r.ParseForm())
+			require.Equal(t, "my-client", r.Form.Get("client_id"))
+			require.Equal(t, "federated-token", r.Form.Get("client_assertion"))
+			require.Contains(t, r.Form.Get("scope"), "https://cognitiveservices.azure.com/.default")
+			_, _ = w.Write([]byte(`{"access_token":"wi-token","expires_in":3600,"token_type":"Bearer"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(authority.Close)
+	target, err := url.Parse(authority.URL)
+	require.NoError(t, err)
+
+	credential, err := newAzureIdentityCredential(&filterapi.AzureIdentity{
+		Type:          filterapi.AzureIdentityTypeWorkloadIdentity,
+		ClientID:      "my-client",
+		TenantID:      "my-tenant",
+		TokenFilePath: tokenFile,
+	}, azcore.ClientOptions{
+		Cloud: cloud.Configuration{ActiveDirectoryAuthorityHost: authority.URL + "/"},
+		// The instance discovery is sent to the public cloud, so all the requests are redirected to the fake authority.
+		Transport: &redirectTransport{target: target, client: authority.Client()},
+	})
+	require.NoError(t, err)
+	handler := &azureHandler{credential: credential, scope: "https://cognitiveservices.azure.com/.default"}
+
+	requestHeaders := map[string]string{}
+	_, err = handler.Do(t.Context(), requestHeaders, nil)
+	require.NoError(t, err)
+	require.Equal(t, "Bearer wi-token", requestHeaders["Authorization"])
+}
+
+func TestNewAzureIdentityCredential_UnknownType(t *testing.T) {
+	_, err := newAzureIdentityCredential(&filterapi.AzureIdentity{Type: "unknown"}, azcore.ClientOptions{})
+	require.ErrorContains(t, err, `unsupported Azure identity type "unknown"`)
+}
@@ -0,0 +1,102 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package backendauth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+var (
+	// defaultOCISignedHeaders are the headers signed by the ociSigner for the requests without a body.
+	defaultOCISignedHeaders = []string{"date", requestTargetHeader, "host"}
+	// defaultOCISignedHeadersWithBody are the headers signed by the ociSigner for the requests with a body.
+	defaultOCISignedHeadersWithBody = []string{"date", requestTargetHeader, "host", "content-length", "content-type", "x-content-sha256"}
+)
+
+// ociSigner signs the requests with an RSA key as the Oracle Cloud Infrastructure API request signature.
+// https://docs.oracle.com/en-us/iaas/Content/API/Concepts/signingrequests.htm
+type ociSigner struct {
+	// keyID is "<tenancy OCID>/<user OCID>/<key fingerprint>".
+	keyID string
+	key   *rsa.PrivateKey
+	// signedHeaders overrides the default signed headers when set.
+	signedHeaders []string
+}
+
+func newOCISigner(auth *filterapi.RequestSigningAuth) (requestSigner, error) {
+	if strings.Count(auth.KeyID, "/") != 2 {
+		return nil, fmt.Errorf("key ID %s must be <tenancy OCID>/<user OCID>/<key fingerprint>", auth.KeyID)
+	}
+	key, err := parseRSAPrivateKey([]byte(strings.TrimSpace(auth.Key)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key of %s: %w", auth.KeyID, err)
+	}
+	return &ociSigner{keyID: auth.KeyID, key: key, signedHeaders: signedHeadersOf(auth, nil)}, nil
+}
+
+// parseRSAPrivateKey parses the PEM encoded RSA private key in either the PKCS #1 or the PKCS #8 form.
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is %T, not RSA", key)
+	}
+	return rsaKey, nil
+}
+
+// sign implements [requestSigner.sign].
+func (s *ociSigner) sign(requestHeaders map[string]string, body []byte, now time.Time) ([]internalapi.Header, error) {
+	method := requestHeaders[":method"]
+	hasBody := method == "POST" || method == "PUT" || method == "PATCH"
+	if hasBody && len(body) == 0 {
+		// The body is not known when it is not replaced by the extproc, and OCI rejects a signed empty body.
+		return nil, fmt.Errorf("the body of the %s request to sign is missing", method)
+	}
+	signedHeaders := s.signedHeaders
+	if len(signedHeaders) == 0 {
+		if hasBody {
+			signedHeaders = defaultOCISignedHeadersWithBody
+		} else {
+			signedHeaders = defaultOCISignedHeaders
+		}
+	}
+	signingString, headers, err := buildSigningString(signedHeaders, requestHeaders, body, now)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(signingString))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign with RSA key: %w", err)
+	}
+	headers = append(headers, internalapi.Header{"Authorization", fmt.Sprintf(
+		`Signature version="1",keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		s.keyID, strings.Join(signedHeaders, " "), base64.StdEncoding.EncodeToString(signature))})
+	return headers, nil
+}
@@ -0,0 +1,103 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package backendauth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+)
+
+func TestNewOCISigner(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	pkcs8 := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes})
+
+	for _, pemKey := range [][]byte{pkcs1, pkcs8} {
+		signer, err := newOCISigner(&filterapi.RequestSigningAuth{Scheme: filterapi.RequestSigningSchemeOCI, KeyID: "tenancy/user/fingerprint", Key: string(pemKey) + "\n"})
+		require.NoError(t, err)
+		require.True(t, key.Equal(signer.(*ociSigner).key))
+	}
+
+	_, err = newOCISigner(&filterapi.RequestSigningAuth{Scheme: filterapi.RequestSigningSchemeOCI, KeyID: "fingerprint", Key: string(pkcs1)})
+	require.ErrorContains(t, err, "key ID fingerprint must be <tenancy OCID>/<user OCID>/<key fingerprint>")
+	_, err = newOCISigner(&filterapi.RequestSigningAuth{Scheme: filterapi.RequestSigningSchemeOCI, KeyID: "tenancy/user/fingerprint", Key: "secret"})
+	require.ErrorContains(t, err, "invalid signing key of tenancy/user/fingerprint: no PEM data found")
+}
+
+func TestRequestSigningHandler_Do_OCI(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	handler, err := NewHandler(t.Context(), &filterapi.BackendAuth{RequestSigning: &filterapi.RequestSigningAuth{
+		Scheme: filterapi.RequestSigningSchemeOCI, KeyID: "tenancy/user/fingerprint", Key: string(pemKey),
+	}})
+	require.NoError(t, err)
+	handler.(*requestSigningHandler).now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	verify := func(t *testing.T, authorization, expHeaders, signingString string) {
+		m := regexp.MustCompile(`^Signature version="1",keyId="tenancy/user/fingerprint",algorithm="rsa-sha256",headers="([^"]+)",signature="([^"]+)"$`).
+			FindStringSubmatch(authorization)
+		require.Len(t, m, 3, authorization)
+		require.Equal(t, expHeaders, m[1])
+		signature, err := base64.StdEncoding.DecodeString(m[2])
+		require.NoError(t, err)
+		digest := sha256.Sum256([]byte(signingString))
+		require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+	}
+
+	t.Run("with body", func(t *testing.T) {
+		body := []byte(`{"compartmentId":"ocid1.compartment"}`)
+		requestHeaders := map[string]string{
+			":method": "POST", ":path": "/20231130/actions/chat", ":authority": "inference.generativeai.us-chicago-1.oci.oraclecloud.com",
+			"content-type": "application/json",
+		}
+		hdrs, err := handler.Do(t.Context(), requestHeaders, body)
+		require.NoError(t, err)
+		require.Len(t, hdrs, 4)
+		digest := sha256.Sum256(body)
+		bodySHA256 := base64.StdEncoding.EncodeToString(digest[:])
+		require.Equal(t, "Fri, 02 Jan 2026 03:04:05 GMT", requestHeaders["date"])
+		require.Equal(t, "37", requestHeaders["content-length"])
+		require.Equal(t, bodySHA256, requestHeaders["x-content-sha256"])
+		verify(t, requestHeaders["Authorization"], "date (request-target) host content-length content-type x-content-sha256",
+			"date: Fri, 02 Jan 2026 03:04:05 GMT\n(request-target): post /20231130/actions/chat\n"+
+				"host: inference.generativeai.us-chicago-1.oci.oraclecloud.com\ncontent-length: 37\n"+
+				"content-type: application/json\nx-content-sha256: "+bodySHA256)
+	})
+
+	t.Run("missing body", func(t *testing.T) {
+		// The body that is not replaced by the extproc must not be signed as an empty one.
+		require.True(t, handler.(filterapi.BackendAuthBodySigner).SignsBody())
+		requestHeaders := map[string]string{":method": "POST", ":path": "/20231130/actions/chat", ":authority": "oci.example.com"}
+		_, err := handler.Do(t.Context(), requestHeaders, nil)
+		require.ErrorContains(t, err, "the body of the POST request to sign is missing")
+		require.Empty(t, requestHeaders["Authorization"])
+	})
+
+	t.Run("without body", func(t *testing.T) {
+		requestHeaders := map[string]string{":method": "GET", ":path": "/20231130/models", ":authority": "oci.example.com"}
+		hdrs, err := handler.Do(t.Context(), requestHeaders, nil)
+		require.NoError(t, err)
+		require.Len(t, hdrs, 2)
+		verify(t, requestHeaders["Authorization"], "date (request-target) host",
+			"date: Fri, 02 Jan 2026 03:04:05 GMT\n(request-target): get /20231130/models\nhost: oci.example.com")
+	})
+}
@@ -0,0 +1,163 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package backendauth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+// requestSigner computes the headers carrying the signature of a request for a signing scheme.
+type requestSigner interface {
+	// sign returns the headers to add to the request with the given headers and body, signed at the given time.
+	sign(requestHeaders map[string]string, body []byte, now time.Time) ([]internalapi.Header, error)
+}
+
+// newRequestSignerFunc creates the requestSigner of a signing scheme.
+type newRequestSignerFunc func(auth *filterapi.RequestSigningAuth) (requestSigner, error)
+
+// requestSigners are the signing schemes supported by the requestSigningHandler.
+var requestSigners = map[filterapi.RequestSigningScheme]newRequestSignerFunc{
+	filterapi.RequestSigningSchemeHMACSHA256: newHMACSigner,
+	filterapi.RequestSigningSchemeOCI:        newOCISigner,
+}
+
+// requestSigningHandler implements [filterapi.BackendAuthHandler] and [filterapi.BackendAuthBodySigner] for the request
+// signing schemes.
+type requestSigningHandler struct {
+	signer requestSigner
+	now    func() time.Time
+}
+
+func newRequestSigningHandler(auth *filterapi.RequestSigningAuth) (filterapi.BackendAuthHandler, error) {
+	newSigner, ok := requestSigners[auth.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported request signing scheme %q", auth.Scheme)
+	}
+	signer, err := newSigner(auth)
+	if err != nil {
+		return nil, err
+	}
+	return &requestSigningHandler{signer: signer, now: time.Now}, nil
+}
+
+// Do implements [filterapi.BackendAuthHandler.Do].
+//
+// This assumes that during the transformation, the path is set in the header mutation as well as
+// the body in the body mutation.
+func (h *requestSigningHandler) Do(_ context.Context, requestHeaders map[string]string, mutatedBody []byte) ([]internalapi.Header, error) {
+	headers, err := h.signer.sign(requestHeaders, mutatedBody, h.now())
+	if err != nil {
+		return nil, fmt.Errorf("cannot sign request: %w", err)
+	}
+	for _, hdr := range headers {
+		requestHeaders[hdr.Key()] = hdr.Value()
+	}
+	return headers, nil
+}
+
+// SignsBody implements [filterapi.BackendAuthBodySigner.SignsBody].
+//
+// The signatures cover the digest and the length of the body by default.
+func (h *requestSigningHandler) SignsBody() bool { return true }
+
+// requestTargetHeader is the pseudo header of the HTTP signatures standing for the method and the path of the request.
+const requestTargetHeader = "(request-target)"
+
+// defaultHMACSignedHeaders are the headers signed by the hmacSigner by default.
+var defaultHMACSignedHeaders = []string{requestTargetHeader, "host", "date", "digest"}
+
+// hmacSigner signs the requests with HMAC-SHA256 as an HTTP signature in the Authorization header.
+// https://datatracker.ietf.org/doc/html/draft-cavage-http-signatures-12
+type hmacSigner struct {
+	keyID         string
+	key           []byte
+	signedHeaders []string
+}
+
+func newHMACSigner(auth *filterapi.RequestSigningAuth) (requestSigner, error) {
+	key := strings.TrimSpace(auth.Key)
+	if key == "" {
+		return nil, fmt.Errorf("signing key of %s is empty", auth.KeyID)
+	}
+	return &hmacSigner{keyID: auth.KeyID, key: []byte(key), signedHeaders: signedHeadersOf(auth, defaultHMACSignedHeaders)}, nil
+}
+
+// signedHeadersOf returns the lower-cased signed headers configured in the given auth, or the given defaults if none.
+func signedHeadersOf(auth *filterapi.RequestSigningAuth, defaults []string) []string {
+	if len(auth.SignedHeaders) == 0 {
+		return defaults
+	}
+	signedHeaders := make([]string, len(auth.SignedHeaders))
+	for i, h := range auth.SignedHeaders {
+		signedHeaders[i] = strings.ToLower(h)
+	}
+	return signedHeaders
+}
+
+// sign implements [requestSigner.sign].
+func (s *hmacSigner) sign(requestHeaders map[string]string, body []byte, now time.Time) ([]internalapi.Header, error) {
+	signingString, headers, err := buildSigningString(s.signedHeaders, requestHeaders, body, now)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(signingString))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	headers = append(headers, internalapi.Header{"Authorization", fmt.Sprintf(
+		`Signature keyId="%s",algorithm="hmac-sha256",headers="%s",signature="%s"`,
+		s.keyID, strings.Join(s.signedHeaders, " "), signature)})
+	return headers, nil
+}
+
+// buildSigningString returns the string to sign made of the given signed headers of the request, along with the
+// headers that are computed from the request and must be added to it.
+func buildSigningString(signedHeaders []string, requestHeaders map[string]string, body []byte, now time.Time) (string, []internalapi.Header, error) {
+	var headers []internalapi.Header
+	lines := make([]string, 0, len(signedHeaders))
+	for _, name := range signedHeaders {
+		var value string
+		switch name {
+		case requestTargetHeader:
+			value = strings.ToLower(requestHeaders[":method"]) + " " + requestHeaders[":path"]
+		case "host":
+			value = requestHeaders[":authority"]
+		case "date":
+			if value = requestHeaders["date"]; value == "" {
+				value = now.UTC().Format(http.TimeFormat)
+				headers = append(headers, internalapi.Header{"date", value})
+			}
+		case "digest":
+			digest := sha256.Sum256(body)
+			value = "SHA-256=" + base64.StdEncoding.EncodeToString(digest[:])
+			headers = append(headers, internalapi.Header{"digest", value})
+		case "x-content-sha256":
+			digest := sha256.Sum256(body)
+			value = base64.StdEncoding.EncodeToString(digest[:])
+			headers = append(headers, internalapi.Header{"x-content-sha256", value})
+		case "content-length":
+			value = strconv.Itoa(len(body))
+			headers = append(headers, internalapi.Header{"content-length", value})
+		default:
+			value = requestHeaders[name]
+		}
+		if value == "" {
+			return "", nil, fmt.Errorf("signed header %s is missing", name)
+		}
+		lines = append(lines, name+": "+value)
+	}
+	return strings.Join(lines, "\n"), headers, nil
+}
@@ -0,0 +1,90 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package backendauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+func TestNewRequestSigningHandler(t *testing.T) {
+	_, err := newRequestSigningHandler(&filterapi.RequestSigningAuth{Scheme: "unknown", KeyID: "key", Key: "secret"})
+	require.ErrorContains(t, err, `unsupported request signing scheme "unknown"`)
+
+	_, err = newRequestSigningHandler(&filterapi.RequestSigningAuth{Scheme: filterapi.RequestSigningSchemeHMACSHA256, KeyID: "key", Key: " \n"})
+	require.ErrorContains(t, err, "signing key of key is empty")
+
+	handler, err := NewHandler(t.Context(), &filterapi.BackendAuth{RequestSigning: &filterapi.RequestSigningAuth{
+		Scheme: filterapi.RequestSigningSchemeHMACSHA256, KeyID: "key", Key: "secret\n", SignedHeaders: []string{"(request-target)", "X-Tenant"},
+	}})
+	require.NoError(t, err)
+	signer := handler.(*requestSigningHandler).signer.(*hmacSigner)
+	require.Equal(t, []byte("secret"), signer.key)
+	require.Equal(t, []string{"(request-target)", "x-tenant"}, signer.signedHeaders)
+	// The body is always sent as signed, even when the translation does not modify it.
+	require.True(t, handler.(filterapi.BackendAuthBodySigner).SignsBody())
+}
+
+func TestRequestSigningHandler_Do_HMACSHA256(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	sign := func(signingString string) string {
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(signingString))
+		return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+	body := []byte(`{"model":"llama"}`)
+	digest := sha256.Sum256(body)
+	expDigest := "SHA-256=" + base64.StdEncoding.EncodeToString(digest[:])
+
+	t.Run("default headers", func(t *testing.T) {
+		handler, err := newRequestSigningHandler(&filterapi.RequestSigningAuth{
+			Scheme: filterapi.RequestSigningSchemeHMACSHA256, KeyID: "my-key", Key: "secret",
+		})
+		require.NoError(t, err)
+		handler.(*requestSigningHandler).now = func() time.Time { return now }
+
+		requestHeaders := map[string]string{":method": "POST", ":path": "/v1/chat/completions", ":authority": "llm.internal"}
+		hdrs, err := handler.Do(t.Context(), requestHeaders, body)
+		require.NoError(t, err)
+
+		signature := sign("(request-target): post /v1/chat/completions\nhost: llm.internal\n" +
+			"date: Fri, 02 Jan 2026 03:04:05 GMT\ndigest: " + expDigest)
+		expAuthorization := `Signature keyId="my-key",algorithm="hmac-sha256",headers="(request-target) host date digest",signature="` + signature + `"`
+		require.Equal(t, []internalapi.Header{
+			{"date", "Fri, 02 Jan 2026 03:04:05 GMT"},
+			{"digest", expDigest},
+			{"Authorization", expAuthorization},
+		}, hdrs)
+		require.Equal(t, expAuthorization, requestHeaders["Authorization"])
+		require.Equal(t, expDigest, requestHeaders["digest"])
+	})
+
+	t.Run("custom headers", func(t *testing.T) {
+		handler, err := newRequestSigningHandler(&filterapi.RequestSigningAuth{
+			Scheme: filterapi.RequestSigningSchemeHMACSHA256, KeyID: "my-key", Key: "secret",
+			SignedHeaders: []string{"date", "x-tenant"},
+		})
+		require.NoError(t, err)
+
+		// The date of the request is kept.
+		requestHeaders := map[string]string{":method": "POST", "date": "Thu, 01 Jan 2026 00:00:00 GMT", "x-tenant": "team-a"}
+		hdrs, err := handler.Do(t.Context(), requestHeaders, body)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{{"Authorization", `Signature keyId="my-key",algorithm="hmac-sha256",headers="date x-tenant",signature="` +
+			sign("date: Thu, 01 Jan 2026 00:00:00 GMT\nx-tenant: team-a") + `"`}}, hdrs)
+
+		_, err = handler.Do(t.Context(), map[string]string{":method": "POST"}, body)
+		require.ErrorContains(t, err, "cannot sign request: signed header x-tenant is missing")
+	})
+}
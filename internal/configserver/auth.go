@@ -0,0 +1,152 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package configserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// TokenAudience is the audience of the ServiceAccount tokens that the external processors present to the
+	// config server. The tokens of this audience are not accepted by the Kubernetes API server.
+	TokenAudience = "envoy-ai-gateway-config-server"
+	// TokenDir is the directory where the ServiceAccount token of [TokenAudience] is projected in the external
+	// processor container.
+	TokenDir = "/var/run/secrets/envoy-ai-gateway/config-server"
+	// TokenPath is the path of the ServiceAccount token of [TokenAudience] in the external processor container.
+	TokenPath = TokenDir + "/token"
+
+	// authorizationHeader is the gRPC metadata key carrying the bearer token of the external processors.
+	authorizationHeader = "authorization"
+	// serviceAccountUsernamePrefix is the prefix of the usernames of the ServiceAccounts.
+	serviceAccountUsernamePrefix = "system:serviceaccount:"
+	// podNameExtra and podUIDExtra are the extra info of the authenticated tokens bound to a pod.
+	podNameExtra = "authentication.kubernetes.io/pod-name"
+	podUIDExtra  = "authentication.kubernetes.io/pod-uid"
+	// owningGatewayNameLabel and owningGatewayNamespaceLabel are the labels set by Envoy Gateway on the pods of
+	// a Gateway.
+	owningGatewayNameLabel      = "gateway.envoyproxy.io/owning-gateway-name"
+	owningGatewayNamespaceLabel = "gateway.envoyproxy.io/owning-gateway-namespace"
+	// envoyProxyNamePrefix is the prefix of the names of the resources that Envoy Gateway creates for the Envoy
+	// proxies of a Gateway, including their ServiceAccount.
+	envoyProxyNamePrefix = "envoy"
+	// envoyProxyNameMaxLength is the length at which Envoy Gateway truncates the Gateway part of the names of the
+	// resources of the Envoy proxies before appending the hash.
+	envoyProxyNameMaxLength = 48
+)
+
+// authenticator authenticates the external processors connecting to the config server.
+//
+// The external processors present the token of the ServiceAccount of their pod, projected with [TokenAudience],
+// which is verified with a TokenReview. The token is bound to the pod, so the external processor is only allowed
+// to stream the configuration of the Gateway owning the pod.
+//
+// The labels of the pod naming its Gateway can be set by anyone creating pods, so the pod must also run in the
+// namespace where Envoy Gateway deploys the Envoy proxies, as the ServiceAccount that Envoy Gateway creates for the
+// Envoy proxies of that Gateway.
+type authenticator struct {
+	kube kubernetes.Interface
+	// envoyGatewayNamespace is the namespace where Envoy Gateway deploys the Envoy proxies.
+	envoyGatewayNamespace string
+}
+
+// authenticate returns the xDS node ID that the caller of the given incoming gRPC context is allowed to stream.
+func (a *authenticator) authenticate(ctx context.Context) (nodeID string, err error) {
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, v := range md.Get(authorizationHeader) {
+			if t, found := strings.CutPrefix(v, "Bearer "); found {
+				token = t
+			}
+		}
+	}
+	if token == "" {
+		return "", fmt.Errorf("missing bearer token")
+	}
+
+	review, err := a.kube.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token, Audiences: []string{TokenAudience}},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to review the token: %w", err)
+	}
+	if !review.Status.Authenticated {
+		return "", fmt.Errorf("invalid token: %s", review.Status.Error)
+	}
+	if !slices.Contains(review.Status.Audiences, TokenAudience) {
+		return "", fmt.Errorf("token is not issued for the audience %s", TokenAudience)
+	}
+	user := review.Status.User
+	namespace, serviceAccount, _ := strings.Cut(strings.TrimPrefix(user.Username, serviceAccountUsernamePrefix), ":")
+	if !strings.HasPrefix(user.Username, serviceAccountUsernamePrefix) || namespace == "" || serviceAccount == "" {
+		return "", fmt.Errorf("%s is not a service account", user.Username)
+	}
+	if namespace != a.envoyGatewayNamespace {
+		return "", fmt.Errorf("%s is not in the Envoy Gateway namespace %s", user.Username, a.envoyGatewayNamespace)
+	}
+	podNames, podUIDs := user.Extra[podNameExtra], user.Extra[podUIDExtra]
+	if len(podNames) != 1 || len(podUIDs) != 1 {
+		return "", fmt.Errorf("token of %s is not bound to a pod", user.Username)
+	}
+
+	pod, err := a.kube.CoreV1().Pods(namespace).Get(ctx, podNames[0], metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get the pod %s/%s: %w", namespace, podNames[0], err)
+	}
+	if string(pod.UID) != podUIDs[0] {
+		return "", fmt.Errorf("pod %s/%s was recreated", namespace, podNames[0])
+	}
+	gatewayName, gatewayNamespace := pod.Labels[owningGatewayNameLabel], pod.Labels[owningGatewayNamespaceLabel]
+	if gatewayName == "" || gatewayNamespace == "" {
+		return "", fmt.Errorf("pod %s/%s is not a Gateway pod", namespace, podNames[0])
+	}
+	if expected := envoyProxyServiceAccount(gatewayNamespace, gatewayName); pod.Spec.ServiceAccountName != serviceAccount ||
+		serviceAccount != expected {
+		return "", fmt.Errorf("pod %s/%s does not run as the service account %s of the Gateway %s/%s",
+			namespace, podNames[0], expected, gatewayNamespace, gatewayName)
+	}
+	return NodeID(gatewayNamespace, gatewayName), nil
+}
+
+// envoyProxyServiceAccount returns the name of the ServiceAccount that Envoy Gateway creates for the Envoy proxies
+// of the given Gateway, following the naming of its Kubernetes infrastructure provider.
+func envoyProxyServiceAccount(gatewayNamespace, gatewayName string) string {
+	nsName := gatewayNamespace + "/" + gatewayName
+	hash := sha256.Sum256([]byte(nsName))
+	name := strings.ReplaceAll(nsName, "/", "-")
+	if len(name) > envoyProxyNameMaxLength {
+		name = strings.TrimSuffix(name[:envoyProxyNameMaxLength], "-")
+	}
+	return fmt.Sprintf("%s-%s-%s", envoyProxyNamePrefix, name, hex.EncodeToString(hash[:])[:8])
+}
+
+// tokenCredentials implements [credentials.PerRPCCredentials] sending the ServiceAccount token at the path.
+//
+// The token is read on every stream as the kubelet rotates it.
+type tokenCredentials string
+
+// GetRequestMetadata implements [credentials.PerRPCCredentials.GetRequestMetadata].
+func (t tokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	token, err := os.ReadFile(string(t))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the service account token: %w", err)
+	}
+	return map[string]string{authorizationHeader: "Bearer " + strings.TrimSpace(string(token))}, nil
+}
+
+// RequireTransportSecurity implements [credentials.PerRPCCredentials.RequireTransportSecurity].
+func (tokenCredentials) RequireTransportSecurity() bool { return true }
@@ -0,0 +1,243 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package configserver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const (
+	// testToken is the token of the pod of the Gateway of newTestKube.
+	testToken = "test-token"
+	// testPodNamespace is the namespace of the pod of the Gateway of newTestKube.
+	testPodNamespace = "envoy-gateway-system"
+)
+
+// newTestKube returns a fake clientset with a pod of the given Gateway, whose token is testToken.
+func newTestKube(gwNamespace, gwName string) *fake.Clientset {
+	return newTestKubeWithPod(testPodNamespace, envoyProxyServiceAccount(gwNamespace, gwName), gwNamespace, gwName)
+}
+
+// newTestKubeWithPod returns a fake clientset with a pod in the given namespace running as the given service
+// account, labeled with the given Gateway, whose token is testToken.
+func newTestKubeWithPod(podNamespace, serviceAccount, gwNamespace, gwName string) *fake.Clientset {
+	kube := fake.NewClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "envoy-gw", Namespace: podNamespace, UID: "envoy-gw-uid",
+			Labels: map[string]string{owningGatewayNameLabel: gwName, owningGatewayNamespaceLabel: gwNamespace},
+		},
+		Spec: corev1.PodSpec{ServiceAccountName: serviceAccount},
+	})
+	kube.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview).DeepCopy()
+		if review.Spec.Token != testToken || !slices.Contains(review.Spec.Audiences, TokenAudience) {
+			review.Status = authenticationv1.TokenReviewStatus{Error: "invalid token"}
+			return true, review, nil
+		}
+		review.Status = authenticationv1.TokenReviewStatus{
+			Authenticated: true,
+			Audiences:     []string{TokenAudience},
+			User: authenticationv1.UserInfo{
+				Username: "system:serviceaccount:" + podNamespace + ":" + serviceAccount,
+				Extra: map[string]authenticationv1.ExtraValue{
+					podNameExtra: {"envoy-gw"},
+					podUIDExtra:  {"envoy-gw-uid"},
+				},
+			},
+		}
+		return true, review, nil
+	})
+	return kube
+}
+
+// testAuthContext returns an incoming gRPC context carrying testToken.
+func testAuthContext(t *testing.T) context.Context {
+	return metadata.NewIncomingContext(t.Context(), metadata.Pairs(authorizationHeader, "Bearer "+testToken))
+}
+
+// requireTestToken writes the given token to a file and returns its path.
+func requireTestToken(t *testing.T, token string) string {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte(token+"\n"), 0o600))
+	return path
+}
+
+// requireTestCert writes a self-signed certificate for 127.0.0.1 and its key to files, and returns their paths
+// and the PEM encoded certificate.
+func requireTestCert(t *testing.T) (certFile, keyFile string, caCert []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "config-server"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	caCert = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	require.NoError(t, os.WriteFile(certFile, caCert, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile, caCert
+}
+
+func TestAuthenticator_authenticate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		md     metadata.MD
+		pod    *corev1.Pod
+		expErr string
+	}{
+		{name: "no metadata", expErr: "missing bearer token"},
+		{name: "no bearer token", md: metadata.Pairs(authorizationHeader, "Basic "+testToken), expErr: "missing bearer token"},
+		{name: "invalid token", md: metadata.Pairs(authorizationHeader, "Bearer invalid"), expErr: "invalid token"},
+		{
+			name:   "pod not found",
+			md:     metadata.Pairs(authorizationHeader, "Bearer "+testToken),
+			pod:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: testPodNamespace}},
+			expErr: `failed to get the pod envoy-gateway-system/envoy-gw: pods "envoy-gw" not found`,
+		},
+		{
+			name: "pod recreated",
+			md:   metadata.Pairs(authorizationHeader, "Bearer "+testToken),
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name: "envoy-gw", Namespace: testPodNamespace, UID: "new-uid",
+				Labels: map[string]string{owningGatewayNameLabel: "gw", owningGatewayNamespaceLabel: "ns"},
+			}, Spec: corev1.PodSpec{ServiceAccountName: envoyProxyServiceAccount("ns", "gw")}},
+			expErr: "pod envoy-gateway-system/envoy-gw was recreated",
+		},
+		{
+			name: "not a Gateway pod",
+			md:   metadata.Pairs(authorizationHeader, "Bearer "+testToken),
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name: "envoy-gw", Namespace: testPodNamespace, UID: "envoy-gw-uid",
+			}},
+			expErr: "pod envoy-gateway-system/envoy-gw is not a Gateway pod",
+		},
+		{
+			name: "labeled for another Gateway",
+			md:   metadata.Pairs(authorizationHeader, "Bearer "+testToken),
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name: "envoy-gw", Namespace: testPodNamespace, UID: "envoy-gw-uid",
+				Labels: map[string]string{owningGatewayNameLabel: "other", owningGatewayNamespaceLabel: "ns"},
+			}, Spec: corev1.PodSpec{ServiceAccountName: envoyProxyServiceAccount("ns", "gw")}},
+			expErr: "pod envoy-gateway-system/envoy-gw does not run as the service account " +
+				envoyProxyServiceAccount("ns", "other") + " of the Gateway ns/other",
+		},
+		{name: "ok", md: metadata.Pairs(authorizationHeader, "Bearer "+testToken)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			kube := newTestKube("ns", "gw")
+			if tc.pod != nil {
+				require.NoError(t, kube.Tracker().Delete(corev1.SchemeGroupVersion.WithResource("pods"), testPodNamespace, "envoy-gw"))
+				require.NoError(t, kube.Tracker().Add(tc.pod))
+			}
+			ctx := t.Context()
+			if tc.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tc.md)
+			}
+			nodeID, err := (&authenticator{kube: kube, envoyGatewayNamespace: testPodNamespace}).authenticate(ctx)
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, NodeID("ns", "gw"), nodeID)
+		})
+	}
+}
+
+func TestAuthenticator_authenticate_notBoundToPod(t *testing.T) {
+	kube := fake.NewClientset()
+	kube.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview).DeepCopy()
+		review.Status = authenticationv1.TokenReviewStatus{
+			Authenticated: true,
+			Audiences:     []string{TokenAudience},
+			User:          authenticationv1.UserInfo{Username: "system:serviceaccount:" + testPodNamespace + ":sa"},
+		}
+		return true, review, nil
+	})
+	_, err := (&authenticator{kube: kube, envoyGatewayNamespace: testPodNamespace}).authenticate(testAuthContext(t))
+	require.ErrorContains(t, err, "token of system:serviceaccount:envoy-gateway-system:sa is not bound to a pod")
+}
+
+func TestAuthenticator_authenticate_foreignPod(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		podNamespace   string
+		serviceAccount string
+		expErr         string
+	}{
+		{
+			// A tenant labeling their own pod with the Gateway, even running as a service account of the same name.
+			name:           "foreign namespace",
+			podNamespace:   "tenant",
+			serviceAccount: envoyProxyServiceAccount("ns", "gw"),
+			expErr:         "is not in the Envoy Gateway namespace envoy-gateway-system",
+		},
+		{
+			name:           "other service account",
+			podNamespace:   testPodNamespace,
+			serviceAccount: "default",
+			expErr:         "pod envoy-gateway-system/envoy-gw does not run as the service account",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			kube := newTestKubeWithPod(tc.podNamespace, tc.serviceAccount, "ns", "gw")
+			_, err := (&authenticator{kube: kube, envoyGatewayNamespace: testPodNamespace}).authenticate(testAuthContext(t))
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestEnvoyProxyServiceAccount(t *testing.T) {
+	// The name of the Envoy proxy resources of the Gateway default/eg of the Envoy Gateway quickstart.
+	require.Equal(t, "envoy-default-eg-e41e7b31", envoyProxyServiceAccount("default", "eg"))
+	require.Equal(t, "envoy-default-a-very-long-gateway-name-that-goes-beyon-5e9b9a51",
+		envoyProxyServiceAccount("default", "a-very-long-gateway-name-that-goes-beyond-the-limit"))
+}
+
+func TestTokenCredentials(t *testing.T) {
+	md, err := tokenCredentials(requireTestToken(t, testToken)).GetRequestMetadata(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{authorizationHeader: "Bearer " + testToken}, md)
+
+	_, err = tokenCredentials(filepath.Join(t.TempDir(), "missing")).GetRequestMetadata(t.Context())
+	require.ErrorContains(t, err, "failed to read the service account token")
+}
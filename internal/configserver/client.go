@@ -0,0 +1,227 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package configserver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"sigs.k8s.io/yaml"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/version"
+)
+
+const (
+	// minReconnectBackoff and maxReconnectBackoff bound the delay before reconnecting a broken stream.
+	minReconnectBackoff = time.Second
+	maxReconnectBackoff = 30 * time.Second
+)
+
+type configClient struct {
+	nodeID     string
+	rcvs       []filterapi.ConfigReceiver
+	l          *slog.Logger
+	versionStr string
+	// resourceVersion is the xDS version of the last applied resource, sent when reconnecting so that the
+	// server doesn't send the same configuration again.
+	resourceVersion string
+	// loaded is closed when the first configuration is applied.
+	loaded chan struct{}
+}
+
+// StartConfigStream connects to the config server at the given address as the given xDS node, and applies the
+// configurations it streams to the receivers. See [NodeID] for the node ID of a Gateway.
+//
+// The certificate of the config server is verified with the given PEM encoded CA certificate, and the
+// ServiceAccount token at tokenPath is presented to the config server. See [TokenPath].
+//
+// The whole configuration is sent in a single message, so maxRecvMsgSize bounds the size of the configurations
+// that can be received, rather than the default receive limit of gRPC.
+//
+// This blocks until the first configuration is applied, like [filterapi.StartConfigWatcher] loads the initial
+// configuration. The stream is reconnected in the background until ctx is cancelled.
+func StartConfigStream(ctx context.Context, addr, nodeID string, caCert []byte, tokenPath string, maxRecvMsgSize int, l *slog.Logger, rcvs ...filterapi.ConfigReceiver) error {
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(caCert) {
+		return fmt.Errorf("failed to parse the CA certificate of the config server")
+	}
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12})),
+		grpc.WithPerRPCCredentials(tokenCredentials(tokenPath)),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxRecvMsgSize)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create config xDS client for %s: %w", addr, err)
+	}
+	cc := &configClient{
+		nodeID: nodeID, rcvs: rcvs, l: l, versionStr: version.Parse(), loaded: make(chan struct{}),
+	}
+
+	l.Info("start streaming the config", slog.String("address", addr), slog.String("node", nodeID))
+	go func() {
+		defer func() { _ = conn.Close() }()
+		cc.run(ctx, discoveryv3.NewAggregatedDiscoveryServiceClient(conn))
+	}()
+
+	select {
+	case <-cc.loaded:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to load initial config: %w", ctx.Err())
+	}
+}
+
+// run keeps the stream open until ctx is cancelled, reconnecting with a backoff when it breaks.
+func (cc *configClient) run(ctx context.Context, client discoveryv3.AggregatedDiscoveryServiceClient) {
+	backoff := minReconnectBackoff
+	for {
+		received, err := cc.stream(ctx, client)
+		if ctx.Err() != nil {
+			cc.l.Info("stop streaming the config")
+			return
+		}
+		if received {
+			backoff = minReconnectBackoff
+		}
+		cc.l.Error("config stream broken, reconnecting",
+			slog.String("error", err.Error()), slog.String("backoff", backoff.String()))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxReconnectBackoff)
+	}
+}
+
+// stream subscribes to the configuration and applies the responses until the stream breaks. It returns whether
+// at least one response was received.
+func (cc *configClient) stream(ctx context.Context, client discoveryv3.AggregatedDiscoveryServiceClient) (received bool, _ error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s, err := client.DeltaAggregatedResources(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to open stream: %w", err)
+	}
+	req := &discoveryv3.DeltaDiscoveryRequest{
+		Node:                   &corev3.Node{Id: cc.nodeID},
+		TypeUrl:                ResourceType,
+		ResourceNamesSubscribe: []string{ResourceName},
+	}
+	if cc.resourceVersion != "" {
+		req.InitialResourceVersions = map[string]string{ResourceName: cc.resourceVersion}
+	}
+	if err = s.Send(req); err != nil {
+		return false, fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	for {
+		resp, err := s.Recv()
+		if err != nil {
+			return received, err
+		}
+		received = true
+		ack := &discoveryv3.DeltaDiscoveryRequest{TypeUrl: ResourceType, ResponseNonce: resp.GetNonce()}
+		if err = cc.apply(ctx, resp); err != nil {
+			cc.l.Error("failed to apply config", slog.String("version", resp.GetSystemVersionInfo()), slog.String("error", err.Error()))
+			ack.ErrorDetail = errorDetail(err)
+		}
+		if err = s.Send(ack); err != nil {
+			return received, fmt.Errorf("failed to acknowledge: %w", err)
+		}
+	}
+}
+
+// apply applies the configuration in the given response to the receivers.
+func (cc *configClient) apply(ctx context.Context, resp *discoveryv3.DeltaDiscoveryResponse) error {
+	var resource *discoveryv3.Resource
+	for _, r := range resp.GetResources() {
+		if r.GetName() == ResourceName {
+			resource = r
+		}
+	}
+	if resource == nil {
+		// The configuration of the Gateway was removed. Keep serving the last one until a new one is received.
+		return nil
+	}
+
+	cfg, err := unmarshalResource(resource)
+	if err != nil {
+		return err
+	}
+	if cfg.Version != cc.versionStr {
+		return fmt.Errorf(`config version mismatch: expected %q, got %q. Likely in the middle of rolling update`,
+			cc.versionStr, cfg.Version)
+	}
+	cc.l.Info("loading a new config", slog.String("uuid", cfg.UUID))
+	var errs []error
+	for _, rcv := range cc.rcvs {
+		if err = rcv.LoadConfig(ctx, cfg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err = errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	cc.resourceVersion = resource.GetVersion()
+	select {
+	case <-cc.loaded:
+	default:
+		close(cc.loaded)
+	}
+	return nil
+}
+
+// errorDetail returns the error detail of the NACK of a configuration that failed to apply with the given error.
+// The backends that cannot be used are reported as [errdetails.ResourceInfo] details of [BackendResourceType], so
+// that the controller can report them on the AIServiceBackends.
+func errorDetail(err error) *status.Status {
+	detail := &status.Status{Code: int32(codes.InvalidArgument), Message: err.Error()}
+	for _, backendErr := range filterapi.BackendErrors(err) {
+		info, anyErr := anypb.New(&errdetails.ResourceInfo{
+			ResourceType: BackendResourceType,
+			ResourceName: backendErr.Backend,
+			Description:  backendErr.Err.Error(),
+		})
+		if anyErr == nil {
+			detail.Details = append(detail.Details, info)
+		}
+	}
+	return detail
+}
+
+// unmarshalResource returns the configuration held by the given xDS resource.
+func unmarshalResource(resource *discoveryv3.Resource) (*filterapi.Config, error) {
+	var typed corev3.TypedExtensionConfig
+	if err := resource.GetResource().UnmarshalTo(&typed); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resource: %w", err)
+	}
+	var raw wrapperspb.BytesValue
+	if err := typed.GetTypedConfig().UnmarshalTo(&raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal typed config: %w", err)
+	}
+	var cfg filterapi.Config
+	if err := yaml.Unmarshal(raw.GetValue(), &cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	return &cfg, nil
+}
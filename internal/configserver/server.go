@@ -0,0 +1,420 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package configserver delivers the filter configuration of the Gateways to their external processors over a
+// streaming delta xDS channel, as an alternative to polling the file mounted from the filter config Secret.
+//
+// The configuration of each Gateway is served as a single [corev3.TypedExtensionConfig] resource named
+// [ResourceName] wrapping the YAML encoded [filterapi.Config], for the xDS node whose ID is [NodeID] of the
+// Gateway. The version of the snapshot is the UUID of the configuration, so that the acknowledgements of the
+// external processors can be reported per configuration.
+//
+// The server is served over TLS, and the external processors authenticate with the ServiceAccount token of their
+// pod. They are only served the configuration of the Gateway owning the pod. See [TokenAudience].
+package configserver
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	cachetype "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	serverv3 "github.com/envoyproxy/go-control-plane/pkg/server/v3"
+	"github.com/go-logr/logr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/yaml"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+)
+
+const (
+	// ResourceName is the name of the xDS resource holding the filter configuration.
+	ResourceName = "envoy-ai-gateway-filter-config"
+	// ResourceType is the xDS type URL of the resource holding the filter configuration.
+	ResourceType = resourcev3.ExtensionConfigType
+	// BackendResourceType is the resource type of the [errdetails.ResourceInfo] details of a NACK reporting a
+	// backend of the configuration that cannot be used. The resource name is the name of the [filterapi.Backend].
+	BackendResourceType = "backend"
+)
+
+// NodeID returns the xDS node ID used by the external processors of the given Gateway.
+func NodeID(gatewayNamespace, gatewayName string) string {
+	return gatewayNamespace + "/" + gatewayName
+}
+
+// ParseNodeID returns the namespace and the name of the Gateway of the given xDS node ID.
+func ParseNodeID(nodeID string) (gatewayNamespace, gatewayName string, ok bool) {
+	return strings.Cut(nodeID, "/")
+}
+
+// Status is the result of the delivery of the latest configuration of a Gateway to all its connected external
+// processors.
+type Status struct {
+	// NodeID is the xDS node ID of the external processors. See [NodeID].
+	NodeID string
+	// Version is the UUID of the latest configuration.
+	Version string
+	// Pending is true when some external processors have not acknowledged the configuration yet, and none rejected it.
+	Pending bool
+	// Error is the reason why some external processors rejected the configuration. Empty when it was applied by all
+	// of them or is pending.
+	Error string
+	// BackendErrors maps the names of the [filterapi.Backend] that the external processors failed to set up, for
+	// example because the auth handler cannot be created, to the reason. This is only set when Error is set, and
+	// is empty when the configuration was rejected before the backends were set up, e.g. on version mismatch.
+	BackendErrors map[string]string
+}
+
+// StatusHandler is called when the status of the latest configuration of a Gateway changes.
+type StatusHandler func(ctx context.Context, status Status)
+
+// Server is the xDS gRPC server that streams the filter configurations to the external processors.
+// It is modeled after the rate limit xDS config server in internal/ratelimit/runner.
+type Server struct {
+	logger        logr.Logger
+	addr          string
+	auth          *authenticator
+	certFile      string
+	keyFile       string
+	cache         cachev3.SnapshotCache
+	mu            sync.Mutex
+	statusHandler StatusHandler
+	streams       map[int64]*stream
+	nodes         map[string]*node
+}
+
+// node is the state of the configuration of a Gateway.
+type node struct {
+	// version is the version of the latest snapshot of the node.
+	version string
+	// resourceVersion is the xDS version of the resource of the latest snapshot, which the external processors
+	// that already applied it send when reconnecting.
+	resourceVersion string
+	// reportMu serializes the calls to the status handler for the node, so that the last call reports the latest
+	// status.
+	reportMu sync.Mutex
+	// reported is the last status passed to the status handler, guarded by reportMu.
+	reported *Status
+}
+
+// stream is the state of an xDS stream opened by an external processor.
+type stream struct {
+	// authNodeID is the node ID that the authenticated external processor is allowed to stream.
+	authNodeID string
+	// nodeID is the node ID of the external processor, which is only sent on the first request of the stream.
+	nodeID string
+	// versions maps the nonces of the responses sent on the stream to the versions they carried.
+	versions map[string]string
+	// version is the version of the latest configuration of the node acknowledged or rejected on the stream.
+	version string
+	// err and backendErrors are the reasons of the rejection of version, if it was rejected.
+	err           string
+	backendErrors map[string]string
+}
+
+// New creates a new config server listening on the given address.
+//
+// The server is served over TLS with the certificate and the key in the given files, which are reloaded when they
+// change. The tokens of the external processors are reviewed with the given Kubernetes client, and only the
+// pods of the Envoy proxies deployed by Envoy Gateway in envoyGatewayNamespace are served.
+func New(logger logr.Logger, addr string, kube kubernetes.Interface, envoyGatewayNamespace, certFile, keyFile string) *Server {
+	return &Server{
+		logger:   logger.WithName("config-xds-server"),
+		addr:     addr,
+		auth:     &authenticator{kube: kube, envoyGatewayNamespace: envoyGatewayNamespace},
+		certFile: certFile,
+		keyFile:  keyFile,
+		cache:    cachev3.NewSnapshotCache(false, cachev3.IDHash{}, nil),
+		streams:  make(map[int64]*stream),
+		nodes:    make(map[string]*node),
+	}
+}
+
+// SetStatusHandler sets the handler called when an external processor acknowledges or rejects a configuration.
+func (s *Server) SetStatusHandler(h StatusHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statusHandler = h
+}
+
+// Start starts the xDS gRPC server. It blocks until ctx is cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.addr, err)
+	}
+	return s.serve(ctx, lis)
+}
+
+// serve serves the xDS gRPC server on the given listener until ctx is cancelled.
+func (s *Server) serve(ctx context.Context, lis net.Listener) error {
+	certWatcher, err := certwatcher.New(s.certFile, s.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load the TLS certificate: %w", err)
+	}
+	go func() {
+		if err := certWatcher.Start(ctx); err != nil {
+			s.logger.Error(err, "failed to watch the TLS certificate")
+		}
+	}()
+	grpcServer := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		GetCertificate: certWatcher.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	})))
+	xdsServer := serverv3.NewServer(ctx, s.cache, serverv3.CallbackFuncs{
+		DeltaStreamOpenFunc:     s.onDeltaStreamOpen,
+		DeltaStreamClosedFunc:   s.onDeltaStreamClosed,
+		StreamDeltaRequestFunc:  s.onStreamDeltaRequest,
+		StreamDeltaResponseFunc: s.onStreamDeltaResponse,
+	})
+	discoveryv3.RegisterAggregatedDiscoveryServiceServer(grpcServer, xdsServer)
+
+	go func() {
+		<-ctx.Done()
+		s.logger.Info("shutting down config xDS server")
+		grpcServer.GracefulStop()
+	}()
+
+	s.logger.Info("starting config xDS server", "address", lis.Addr().String())
+	if err := grpcServer.Serve(lis); err != nil {
+		return fmt.Errorf("failed to serve config xDS: %w", err)
+	}
+	return nil
+}
+
+// UpdateConfig updates the configuration served to the external processors of the given Gateway.
+func (s *Server) UpdateConfig(ctx context.Context, gatewayNamespace, gatewayName string, config *filterapi.Config) error {
+	marshaled, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter config: %w", err)
+	}
+	typedConfig, err := anypb.New(wrapperspb.Bytes(marshaled))
+	if err != nil {
+		return fmt.Errorf("failed to wrap filter config: %w", err)
+	}
+	snapshot, err := cachev3.NewSnapshot(config.UUID, map[resourcev3.Type][]cachetype.Resource{
+		ResourceType: {&corev3.TypedExtensionConfig{Name: ResourceName, TypedConfig: typedConfig}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create xDS snapshot: %w", err)
+	}
+	if err = snapshot.ConstructVersionMap(); err != nil {
+		return fmt.Errorf("failed to compute the versions of the xDS snapshot: %w", err)
+	}
+	nodeID := NodeID(gatewayNamespace, gatewayName)
+
+	// The node is updated before the snapshot so that the acknowledgements of the new version are not dropped.
+	s.mu.Lock()
+	n, ok := s.nodes[nodeID]
+	if !ok {
+		n = &node{}
+		s.nodes[nodeID] = n
+	}
+	n.version = config.UUID
+	n.resourceVersion = snapshot.GetVersionMap(ResourceType)[ResourceName]
+	s.mu.Unlock()
+
+	if err = s.cache.SetSnapshot(ctx, nodeID, snapshot); err != nil {
+		return fmt.Errorf("failed to set xDS snapshot: %w", err)
+	}
+	s.logger.Info("updated config xDS snapshot", "node", nodeID, "version", config.UUID, "size", len(marshaled))
+	s.report(nodeID)
+	return nil
+}
+
+// RemoveConfig removes the configuration of the given Gateway, which is deleted.
+func (s *Server) RemoveConfig(gatewayNamespace, gatewayName string) {
+	nodeID := NodeID(gatewayNamespace, gatewayName)
+	s.mu.Lock()
+	delete(s.nodes, nodeID)
+	s.mu.Unlock()
+	s.cache.ClearSnapshot(nodeID)
+	s.logger.Info("removed config xDS snapshot", "node", nodeID)
+}
+
+// report calls the status handler with the status of the latest configuration of the given node if it changed.
+//
+// The handler is called asynchronously as it may take a while, e.g. updating the status of the resources, which
+// must not block the streams. The calls are serialized per node and each computes the status when it runs, so the
+// last one always reports the latest status.
+func (s *Server) report(nodeID string) {
+	s.mu.Lock()
+	n, handler := s.nodes[nodeID], s.statusHandler
+	s.mu.Unlock()
+	if n == nil || handler == nil {
+		return
+	}
+	go func() {
+		n.reportMu.Lock()
+		defer n.reportMu.Unlock()
+		s.mu.Lock()
+		status, ok := s.statusLocked(nodeID)
+		s.mu.Unlock()
+		if !ok || (n.reported != nil && reflect.DeepEqual(*n.reported, status)) {
+			return
+		}
+		n.reported = &status
+		handler(context.Background(), status)
+	}()
+}
+
+// statusLocked returns the status of the latest configuration of the given node over all its connected streams.
+// This returns false when no external processor of the node is connected. s.mu must be held.
+func (s *Server) statusLocked(nodeID string) (status Status, ok bool) {
+	n := s.nodes[nodeID]
+	if n == nil {
+		return Status{}, false
+	}
+	status = Status{NodeID: nodeID, Version: n.version}
+	var errs []string
+	for _, st := range s.streams {
+		if st.nodeID != nodeID {
+			continue
+		}
+		ok = true
+		switch {
+		case st.version != n.version:
+			status.Pending = true
+		case st.err != "":
+			if !slices.Contains(errs, st.err) {
+				errs = append(errs, st.err)
+			}
+			for backend, e := range st.backendErrors {
+				if status.BackendErrors == nil {
+					status.BackendErrors = make(map[string]string)
+				}
+				status.BackendErrors[backend] = e
+			}
+		}
+	}
+	if len(errs) > 0 {
+		// The configuration is rejected as soon as one external processor rejects it.
+		slices.Sort(errs)
+		status.Pending = false
+		status.Error = strings.Join(errs, "; ")
+	}
+	return status, ok
+}
+
+// onDeltaStreamOpen implements [serverv3.CallbackFuncs.DeltaStreamOpenFunc].
+//
+// This authenticates the external processor opening the stream, and rejects the stream if it fails.
+func (s *Server) onDeltaStreamOpen(ctx context.Context, streamID int64, _ string) error {
+	authNodeID, err := s.auth.authenticate(ctx)
+	if err != nil {
+		s.logger.Info("rejected unauthenticated config stream", "error", err.Error())
+		return grpcstatus.Errorf(codes.Unauthenticated, "authentication failed: %v", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.streams[streamID] = &stream{authNodeID: authNodeID, versions: make(map[string]string)}
+	return nil
+}
+
+// onDeltaStreamClosed implements [serverv3.CallbackFuncs.DeltaStreamClosedFunc].
+func (s *Server) onDeltaStreamClosed(streamID int64, _ *corev3.Node) {
+	s.mu.Lock()
+	st, ok := s.streams[streamID]
+	delete(s.streams, streamID)
+	s.mu.Unlock()
+	// The remaining external processors of the node may have all applied the configuration.
+	if ok && st.nodeID != "" {
+		s.report(st.nodeID)
+	}
+}
+
+// onStreamDeltaResponse implements [serverv3.CallbackFuncs.StreamDeltaResponseFunc].
+//
+// This records the version carried by the response so that the (N)ACK referring to its nonce can be attributed.
+func (s *Server) onStreamDeltaResponse(streamID int64, _ *discoveryv3.DeltaDiscoveryRequest, resp *discoveryv3.DeltaDiscoveryResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st, ok := s.streams[streamID]; ok {
+		st.versions[resp.GetNonce()] = resp.GetSystemVersionInfo()
+	}
+}
+
+// onStreamDeltaRequest implements [serverv3.CallbackFuncs.StreamDeltaRequestFunc].
+//
+// The requests carrying the nonce of a previous response are the (N)ACKs of the configuration it delivered. The
+// (N)ACKs of the versions older than the latest snapshot of the node are dropped since they are superseded.
+func (s *Server) onStreamDeltaRequest(streamID int64, req *discoveryv3.DeltaDiscoveryRequest) error {
+	if req.GetTypeUrl() != ResourceType {
+		return nil
+	}
+	s.mu.Lock()
+	st, ok := s.streams[streamID]
+	if !ok {
+		s.mu.Unlock()
+		return nil
+	}
+	if node := req.GetNode(); node != nil {
+		if node.GetId() != st.authNodeID {
+			s.mu.Unlock()
+			s.logger.Info("rejected config stream of another Gateway", "node", node.GetId(), "allowed", st.authNodeID)
+			return grpcstatus.Errorf(codes.PermissionDenied, "not allowed to stream the config of node %s", node.GetId())
+		}
+		st.nodeID = node.GetId()
+	}
+	n := s.nodes[st.nodeID]
+	if n == nil {
+		s.mu.Unlock()
+		return nil
+	}
+	var version string
+	if req.GetResponseNonce() == "" {
+		// The external processors reconnecting with the latest configuration already applied are not sent it again.
+		if n.resourceVersion != "" && req.GetInitialResourceVersions()[ResourceName] == n.resourceVersion {
+			version = n.version
+		}
+	} else {
+		version = st.versions[req.GetResponseNonce()]
+		delete(st.versions, req.GetResponseNonce())
+	}
+	if version == "" || version != n.version {
+		s.mu.Unlock()
+		// Still report the new stream, which makes the latest configuration pending until it is acknowledged.
+		if req.GetNode() != nil {
+			s.report(st.nodeID)
+		}
+		return nil
+	}
+	st.version, st.err, st.backendErrors = version, "", nil
+	if detail := req.GetErrorDetail(); detail != nil {
+		st.err = detail.GetMessage()
+		for _, d := range detail.GetDetails() {
+			var info errdetails.ResourceInfo
+			if d.UnmarshalTo(&info) != nil || info.GetResourceType() != BackendResourceType {
+				continue
+			}
+			if st.backendErrors == nil {
+				st.backendErrors = make(map[string]string)
+			}
+			st.backendErrors[info.GetResourceName()] = info.GetDescription()
+		}
+		s.logger.Info("external processor rejected the config", "node", st.nodeID, "version", version, "error", st.err)
+	}
+	nodeID := st.nodeID
+	s.mu.Unlock()
+	s.report(nodeID)
+	return nil
+}
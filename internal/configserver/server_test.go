@@ -0,0 +1,305 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package configserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/version"
+)
+
+// testMaxRecvMsgSize is the maximum size of the configurations received in the tests.
+const testMaxRecvMsgSize = 64 << 20
+
+// mockReceiver is a mock implementation of filterapi.ConfigReceiver.
+type mockReceiver struct {
+	cfg *filterapi.Config
+	mux sync.Mutex
+}
+
+// LoadConfig implements filterapi.ConfigReceiver.
+//
+// The backends named "bad-*" fail like the auth handlers that cannot be created.
+func (m *mockReceiver) LoadConfig(_ context.Context, cfg *filterapi.Config) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	var errs []error
+	for _, b := range cfg.Backends {
+		if strings.HasPrefix(b.Name, "bad-") {
+			errs = append(errs, &filterapi.BackendError{Backend: b.Name, Err: errors.New("invalid credentials")})
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("cannot create runtime filter config: %w", err)
+	}
+	m.cfg = cfg
+	return nil
+}
+
+func (m *mockReceiver) getConfig() *filterapi.Config {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return m.cfg
+}
+
+func TestNodeID(t *testing.T) {
+	nodeID := NodeID("envoy-gateway-system", "gw")
+	require.Equal(t, "envoy-gateway-system/gw", nodeID)
+	namespace, name, ok := ParseNodeID(nodeID)
+	require.True(t, ok)
+	require.Equal(t, "envoy-gateway-system", namespace)
+	require.Equal(t, "gw", name)
+
+	_, _, ok = ParseNodeID("gw")
+	require.False(t, ok)
+}
+
+func TestConfigStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	const gwNamespace, gwName = "envoy-gateway-system", "gw"
+	certFile, keyFile, caCert := requireTestCert(t)
+	s := New(logr.Discard(), "", newTestKube(gwNamespace, gwName), testPodNamespace, certFile, keyFile)
+	statuses := make(chan Status, 10)
+	s.SetStatusHandler(func(_ context.Context, status Status) { statuses <- status })
+	go func() { _ = s.serve(ctx, lis) }()
+
+	nodeID := NodeID(gwNamespace, gwName)
+	// requireStatus skips the pending statuses reported until the external processor answers.
+	requireStatus := func(t *testing.T, exp Status) {
+		t.Helper()
+		var status Status
+		for status.NodeID == "" || status.Pending {
+			select {
+			case status = <-statuses:
+			case <-time.After(10 * time.Second):
+				t.Fatal("timed out waiting for the status")
+			}
+		}
+		require.Equal(t, exp.NodeID, status.NodeID)
+		require.Equal(t, exp.Version, status.Version)
+		if exp.Error == "" {
+			require.Empty(t, status.Error)
+		} else {
+			require.Contains(t, status.Error, exp.Error)
+		}
+		require.Equal(t, exp.BackendErrors, status.BackendErrors)
+	}
+
+	require.NoError(t, s.UpdateConfig(ctx, gwNamespace, gwName, &filterapi.Config{
+		UUID: "uuid-1", Version: version.Parse(), Backends: []filterapi.Backend{{Name: "openai"}},
+	}))
+
+	rcv := &mockReceiver{}
+	tokenPath := requireTestToken(t, testToken)
+	require.NoError(t, StartConfigStream(ctx, lis.Addr().String(), nodeID, caCert, tokenPath, testMaxRecvMsgSize, slog.Default(), rcv))
+	require.Equal(t, "uuid-1", rcv.getConfig().UUID)
+	require.Equal(t, []filterapi.Backend{{Name: "openai"}}, rcv.getConfig().Backends)
+	requireStatus(t, Status{NodeID: nodeID, Version: "uuid-1"})
+
+	// A configuration of another version of the AI Gateway is rejected, and the last one is kept.
+	require.NoError(t, s.UpdateConfig(ctx, gwNamespace, gwName, &filterapi.Config{UUID: "uuid-2", Version: "v0.0.0"}))
+	requireStatus(t, Status{NodeID: nodeID, Version: "uuid-2", Error: "config version mismatch"})
+	require.Equal(t, "uuid-1", rcv.getConfig().UUID)
+
+	// The backends that cannot be set up are reported.
+	require.NoError(t, s.UpdateConfig(ctx, gwNamespace, gwName, &filterapi.Config{
+		UUID: "uuid-2b", Version: version.Parse(),
+		Backends: []filterapi.Backend{{Name: "bad-1"}, {Name: "openai"}, {Name: "bad-2"}},
+	}))
+	requireStatus(t, Status{
+		NodeID: nodeID, Version: "uuid-2b", Error: "backend bad-1: invalid credentials",
+		BackendErrors: map[string]string{"bad-1": "invalid credentials", "bad-2": "invalid credentials"},
+	})
+	require.Equal(t, "uuid-1", rcv.getConfig().UUID)
+
+	// The configurations larger than the default receive limit of gRPC are delivered.
+	require.NoError(t, s.UpdateConfig(ctx, gwNamespace, gwName, &filterapi.Config{
+		UUID: "uuid-large", Version: version.Parse(), Backends: []filterapi.Backend{{Name: strings.Repeat("a", 8<<20)}},
+	}))
+	requireStatus(t, Status{NodeID: nodeID, Version: "uuid-large"})
+	require.Equal(t, "uuid-large", rcv.getConfig().UUID)
+
+	require.NoError(t, s.UpdateConfig(ctx, gwNamespace, gwName, &filterapi.Config{UUID: "uuid-3", Version: version.Parse()}))
+	requireStatus(t, Status{NodeID: nodeID, Version: "uuid-3"})
+	require.Equal(t, "uuid-3", rcv.getConfig().UUID)
+
+	// The configuration of other Gateways is not delivered.
+	require.NoError(t, s.UpdateConfig(ctx, gwNamespace, "other", &filterapi.Config{UUID: "uuid-4", Version: version.Parse()}))
+	select {
+	case status := <-statuses:
+		t.Fatalf("unexpected status %+v", status)
+	case <-time.After(100 * time.Millisecond):
+	}
+	require.Equal(t, "uuid-3", rcv.getConfig().UUID)
+
+	// The external processors cannot stream the configuration of another Gateway, nor without a valid token.
+	for _, tc := range []struct {
+		name, nodeID, token string
+	}{
+		{name: "other gateway", nodeID: NodeID(gwNamespace, "other"), token: testToken},
+		{name: "invalid token", nodeID: nodeID, token: "invalid"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			other := &mockReceiver{}
+			startCtx, startCancel := context.WithTimeout(ctx, 500*time.Millisecond)
+			defer startCancel()
+			err := StartConfigStream(startCtx, lis.Addr().String(), tc.nodeID, caCert, requireTestToken(t, tc.token), testMaxRecvMsgSize, slog.Default(), other)
+			require.ErrorContains(t, err, "failed to load initial config")
+			require.Nil(t, other.getConfig())
+		})
+	}
+}
+
+func TestStartConfigStream_Cancelled(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	certFile, keyFile, caCert := requireTestCert(t)
+	go func() {
+		_ = New(logr.Discard(), "", newTestKube("ns", "gw"), testPodNamespace, certFile, keyFile).serve(ctx, lis)
+	}()
+
+	// No configuration is served for the node, so this blocks until the context is cancelled.
+	startCtx, startCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer startCancel()
+	err = StartConfigStream(startCtx, lis.Addr().String(), NodeID("ns", "gw"), caCert, requireTestToken(t, testToken), testMaxRecvMsgSize, slog.Default(), &mockReceiver{})
+	require.ErrorContains(t, err, "failed to load initial config")
+}
+
+func TestStartConfigStream_InvalidCACert(t *testing.T) {
+	err := StartConfigStream(t.Context(), "127.0.0.1:0", NodeID("ns", "gw"), []byte("invalid"), "", testMaxRecvMsgSize, slog.Default())
+	require.ErrorContains(t, err, "failed to parse the CA certificate of the config server")
+}
+
+func TestServer_RemoveConfig(t *testing.T) {
+	s := New(logr.Discard(), "", newTestKube("ns", "gw"), testPodNamespace, "", "")
+	require.NoError(t, s.UpdateConfig(t.Context(), "ns", "gw", &filterapi.Config{UUID: "v1"}))
+	_, err := s.cache.GetSnapshot("ns/gw")
+	require.NoError(t, err)
+
+	s.RemoveConfig("ns", "gw")
+	_, err = s.cache.GetSnapshot("ns/gw")
+	require.Error(t, err)
+	require.Empty(t, s.nodes)
+}
+
+func TestServer_authentication(t *testing.T) {
+	s := New(logr.Discard(), "", newTestKube("ns", "gw"), testPodNamespace, "", "")
+
+	err := s.onDeltaStreamOpen(t.Context(), 1, ResourceType)
+	require.Equal(t, codes.Unauthenticated, grpcstatus.Code(err))
+	require.ErrorContains(t, err, "missing bearer token")
+
+	require.NoError(t, s.onDeltaStreamOpen(testAuthContext(t), 2, ResourceType))
+	err = s.onStreamDeltaRequest(2, &discoveryv3.DeltaDiscoveryRequest{Node: &corev3.Node{Id: "ns/other"}, TypeUrl: ResourceType})
+	require.Equal(t, codes.PermissionDenied, grpcstatus.Code(err))
+	require.NoError(t, s.onStreamDeltaRequest(2, &discoveryv3.DeltaDiscoveryRequest{Node: &corev3.Node{Id: "ns/gw"}, TypeUrl: ResourceType}))
+}
+
+func TestServer_status(t *testing.T) {
+	s := New(logr.Discard(), "", newTestKube("ns", "gw"), testPodNamespace, "", "")
+	statuses := make(chan Status, 10)
+	s.SetStatusHandler(func(_ context.Context, status Status) { statuses <- status })
+	requireStatus := func(t *testing.T, exp Status) {
+		t.Helper()
+		select {
+		case status := <-statuses:
+			require.Equal(t, exp, status)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for the status")
+		}
+	}
+	requireNoStatus := func(t *testing.T) {
+		t.Helper()
+		select {
+		case status := <-statuses:
+			t.Fatalf("unexpected status %+v", status)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	const nodeID = "ns/gw"
+	node := &corev3.Node{Id: nodeID}
+	send := func(streamID int64, version, nonce string) {
+		s.onStreamDeltaResponse(streamID, nil, &discoveryv3.DeltaDiscoveryResponse{SystemVersionInfo: version, Nonce: nonce})
+	}
+	ack := func(streamID int64, nonce, errMsg string) {
+		req := &discoveryv3.DeltaDiscoveryRequest{TypeUrl: ResourceType, ResponseNonce: nonce}
+		if errMsg != "" {
+			req.ErrorDetail = &status.Status{Message: errMsg}
+		}
+		require.NoError(t, s.onStreamDeltaRequest(streamID, req))
+	}
+
+	// Nothing is reported until an external processor connects.
+	require.NoError(t, s.UpdateConfig(t.Context(), "ns", "gw", &filterapi.Config{UUID: "v1"}))
+	requireNoStatus(t)
+
+	for _, streamID := range []int64{1, 2} {
+		require.NoError(t, s.onDeltaStreamOpen(testAuthContext(t), streamID, ResourceType))
+		require.NoError(t, s.onStreamDeltaRequest(streamID, &discoveryv3.DeltaDiscoveryRequest{Node: node, TypeUrl: ResourceType}))
+		send(streamID, "v1", fmt.Sprintf("nonce-%d", streamID))
+	}
+	requireStatus(t, Status{NodeID: nodeID, Version: "v1", Pending: true})
+
+	// The configuration is applied once all the connected external processors acknowledged it.
+	ack(1, "nonce-1", "")
+	requireNoStatus(t)
+	ack(2, "nonce-2", "")
+	requireStatus(t, Status{NodeID: nodeID, Version: "v1"})
+
+	// The acknowledgements of the versions older than the latest snapshot are dropped.
+	send(1, "v1", "nonce-stale")
+	require.NoError(t, s.UpdateConfig(t.Context(), "ns", "gw", &filterapi.Config{UUID: "v2"}))
+	requireStatus(t, Status{NodeID: nodeID, Version: "v2", Pending: true})
+	ack(1, "nonce-stale", "stale error")
+	requireNoStatus(t)
+
+	// A single rejection rejects the configuration.
+	send(1, "v2", "nonce-3")
+	send(2, "v2", "nonce-4")
+	ack(1, "nonce-3", "invalid")
+	requireStatus(t, Status{NodeID: nodeID, Version: "v2", Error: "invalid"})
+	ack(2, "nonce-4", "")
+	requireNoStatus(t)
+
+	// The configuration is applied by the remaining external processors once the one rejecting it is gone.
+	s.onDeltaStreamClosed(1, node)
+	requireStatus(t, Status{NodeID: nodeID, Version: "v2"})
+
+	// An external processor reconnecting with the latest configuration applied is not sent it again.
+	s.mu.Lock()
+	resourceVersion := s.nodes[nodeID].resourceVersion
+	s.mu.Unlock()
+	require.NotEmpty(t, resourceVersion)
+	require.NoError(t, s.onDeltaStreamOpen(testAuthContext(t), 3, ResourceType))
+	require.NoError(t, s.onStreamDeltaRequest(3, &discoveryv3.DeltaDiscoveryRequest{
+		Node: node, TypeUrl: ResourceType, InitialResourceVersions: map[string]string{ResourceName: resourceVersion},
+	}))
+	requireNoStatus(t)
+}
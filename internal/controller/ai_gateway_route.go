@@ -43,6 +43,8 @@ const (
 	egOwningGatewayNamespaceLabel                      = egAnnotationPrefix + "owning-gateway-namespace"
 	// apiKeyInSecret is the key to store OpenAI API key.
 	apiKeyInSecret = "apiKey"
+	// signingKeyInSecret is the key to store the key of the RequestSigning BackendSecurityPolicy.
+	signingKeyInSecret = "signingKey"
 	// GatewayConfigAnnotationKey is the annotation key used on Gateway objects to reference a GatewayConfig.
 	// The value should be the name of the GatewayConfig resource in the same namespace as the Gateway.
 	GatewayConfigAnnotationKey = "aigateway.envoyproxy.io/gateway-config"
@@ -441,7 +443,7 @@ func (c *AIGatewayRouteController) updateAIGatewayRouteStatus(ctx context.Contex
 			return err
 		}
 
-		route.Status.Conditions = newConditions(conditionType, message)
+		route.Status.Conditions = withReportedConditions(route.Status.Conditions, newConditions(conditionType, message))
 		return c.client.Status().Update(ctx, route)
 	})
 	if err != nil {
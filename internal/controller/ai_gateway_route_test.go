@@ -375,6 +375,18 @@ func TestAIGatewayRouteController_updateAIGatewayRouteStatus(t *testing.T) {
 	require.Len(t, updatedRoute.Status.Conditions, 1)
 	require.Equal(t, "ok", updatedRoute.Status.Conditions[0].Message)
 	require.Equal(t, aigv1b1.ConditionTypeAccepted, updatedRoute.Status.Conditions[0].Type)
+
+	// The Programmed condition reported by the config server is preserved.
+	updatedRoute.Status.Conditions = append(updatedRoute.Status.Conditions, metav1.Condition{
+		Type: aigv1b1.ConditionTypeProgrammed, Status: metav1.ConditionTrue, Reason: "ConfigApplied", LastTransitionTime: metav1.Now(),
+	})
+	require.NoError(t, s.client.Status().Update(t.Context(), &updatedRoute))
+	s.updateAIGatewayRouteStatus(t.Context(), &updatedRoute, aigv1b1.ConditionTypeNotAccepted, "err")
+	err = s.client.Get(t.Context(), client.ObjectKey{Name: "route1", Namespace: "default"}, &updatedRoute)
+	require.NoError(t, err)
+	require.Len(t, updatedRoute.Status.Conditions, 2)
+	require.Equal(t, aigv1b1.ConditionTypeNotAccepted, updatedRoute.Status.Conditions[0].Type)
+	require.Equal(t, aigv1b1.ConditionTypeProgrammed, updatedRoute.Status.Conditions[1].Type)
 }
 
 func Test_buildPriorityAnnotation(t *testing.T) {
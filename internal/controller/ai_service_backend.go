@@ -109,7 +109,7 @@ func (c *AIBackendController) updateAIServiceBackendStatus(ctx context.Context,
 			}
 			return err
 		}
-		backend.Status.Conditions = newConditions(conditionType, message)
+		backend.Status.Conditions = withReportedConditions(backend.Status.Conditions, newConditions(conditionType, message))
 		return c.client.Status().Update(ctx, backend)
 	})
 	if err != nil {
@@ -83,6 +83,20 @@ func TestAIServiceBackendController_Reconcile(t *testing.T) {
 	require.Equal(t, "AIServiceBackend reconciled successfully", backend.Status.Conditions[0].Message)
 	require.Contains(t, backend.Finalizers, aiGatewayControllerFinalizer, "Finalizer should be set")
 
+	// The conditions reported by the config server are preserved.
+	backend.Status.Conditions = append(backend.Status.Conditions,
+		metav1.Condition{Type: aigv1b1.ConditionTypeProgrammed, Status: metav1.ConditionTrue, Reason: "ConfigApplied", LastTransitionTime: metav1.Now()},
+		metav1.Condition{Type: aigv1b1.ConditionTypeResolvedRefs, Status: metav1.ConditionFalse, Reason: "BackendSetupFailed", LastTransitionTime: metav1.Now()},
+	)
+	require.NoError(t, fakeClient.Status().Update(t.Context(), &backend))
+	_, err = c.Reconcile(t.Context(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "mybackend"}})
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(t.Context(), types.NamespacedName{Namespace: "default", Name: "mybackend"}, &backend))
+	require.Len(t, backend.Status.Conditions, 3)
+	require.Equal(t, aigv1b1.ConditionTypeAccepted, backend.Status.Conditions[0].Type)
+	require.Equal(t, aigv1b1.ConditionTypeProgrammed, backend.Status.Conditions[1].Type)
+	require.Equal(t, aigv1b1.ConditionTypeResolvedRefs, backend.Status.Conditions[2].Type)
+
 	// Test the case where the AIServiceBackend is being deleted.
 	err = fakeClient.Delete(t.Context(), &aigv1b1.AIServiceBackend{ObjectMeta: metav1.ObjectMeta{Name: "mybackend", Namespace: "default"}})
 	require.NoError(t, err)
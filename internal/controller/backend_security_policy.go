@@ -7,6 +7,7 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -60,8 +61,18 @@ type BackendSecurityPolicyController struct {
 	logger                    logr.Logger
 	aiServiceBackendEventChan chan event.GenericEvent
 	inferencePoolEventChan    chan event.GenericEvent
+	// vaultCredentials holds the credentials read from Vault, shared with the Gateway controller. This is nil when the
+	// config server is disabled, in which case the Vault policies are not accepted since their credentials would be
+	// written in the filter config Secrets.
+	vaultCredentials *rotators.VaultCredentialStore
+	// vaultConfig restricts the Vault servers and the roles that the Vault policies can use.
+	vaultConfig *rotators.VaultConfig
 }
 
+// errVaultRequiresConfigServer is returned for the Vault policies when the config server is disabled.
+var errVaultRequiresConfigServer = errors.New("the Vault backend security policies require the config server of the controller " +
+	"to be enabled, so that the credentials are never written in the filter config Secrets")
+
 func NewBackendSecurityPolicyController(client client.Client, kube kubernetes.Interface, logger logr.Logger, aiServiceBackendEventChan chan event.GenericEvent, inferencePoolEventChan chan event.GenericEvent) *BackendSecurityPolicyController {
 	return &BackendSecurityPolicyController{
 		client:                    client,
@@ -79,6 +90,7 @@ func (c *BackendSecurityPolicyController) Reconcile(ctx context.Context, req ctr
 		if apierrors.IsNotFound(err) {
 			c.logger.Info("Deleting backend security policy",
 				"namespace", req.Namespace, "name", req.Name)
+			c.deleteVaultCredentials(ctx, req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
@@ -97,13 +109,23 @@ func (c *BackendSecurityPolicyController) Reconcile(ctx context.Context, req ctr
 
 // reconcile reconciles BackendSecurityPolicy but extracted from Reconcile to centralize error handling.
 func (c *BackendSecurityPolicyController) reconcile(ctx context.Context, bsp *aigv1b1.BackendSecurityPolicy) (res ctrl.Result, err error) {
-	if handleFinalizer(ctx, c.client, c.logger, bsp, c.syncBackendSecurityPolicy) { // Propagate the bsp deletion all the way to relevant Gateways.
+	if handleFinalizer(ctx, c.client, c.logger, bsp, func(ctx context.Context, bsp *aigv1b1.BackendSecurityPolicy) error {
+		c.deleteVaultCredentials(ctx, bsp.Namespace, bsp.Name)
+		return c.syncBackendSecurityPolicy(ctx, bsp) // Propagate the bsp deletion all the way to relevant Gateways.
+	}) {
 		return res, nil
 	}
+	if bsp.Spec.Type != aigv1b1.BackendSecurityPolicyTypeVault {
+		// The policy might have been changed from the Vault type.
+		c.deleteVaultCredentials(ctx, bsp.Namespace, bsp.Name)
+	}
 	// Determine if credential rotation is needed
 	requiresRotation := bsp.Spec.Type != aigv1b1.BackendSecurityPolicyTypeAPIKey &&
 		bsp.Spec.Type != aigv1b1.BackendSecurityPolicyTypeAzureAPIKey &&
-		bsp.Spec.Type != aigv1b1.BackendSecurityPolicyTypeAnthropicAPIKey
+		bsp.Spec.Type != aigv1b1.BackendSecurityPolicyTypeAnthropicAPIKey &&
+		bsp.Spec.Type != aigv1b1.BackendSecurityPolicyTypeAPIKeyPool &&
+		bsp.Spec.Type != aigv1b1.BackendSecurityPolicyTypeClientCertificate &&
+		bsp.Spec.Type != aigv1b1.BackendSecurityPolicyTypeRequestSigning
 
 	// Skip rotation for AWS when neither credentials file nor OIDC exchange is configured
 	// This allows IRSA/Pod Identity to work via the default credential chain
@@ -116,6 +138,15 @@ func (c *BackendSecurityPolicyController) reconcile(ctx context.Context, bsp *ai
 			requiresRotation = false
 		}
 	}
+	// Skip rotation for Azure managed identity and workload identity, whose tokens are acquired by the extproc.
+	if bsp.Spec.Type == aigv1b1.BackendSecurityPolicyTypeAzureCredentials {
+		if bsp.Spec.AzureCredentials != nil &&
+			(bsp.Spec.AzureCredentials.ManagedIdentity != nil || bsp.Spec.AzureCredentials.WorkloadIdentity != nil) {
+			c.logger.Info("Using Azure managed identity or workload identity, skipping rotation",
+				"namespace", bsp.Namespace, "name", bsp.Name)
+			requiresRotation = false
+		}
+	}
 
 	if requiresRotation {
 		res, err = c.rotateCredential(ctx, bsp)
@@ -127,6 +158,18 @@ func (c *BackendSecurityPolicyController) reconcile(ctx context.Context, bsp *ai
 	return res, err
 }
 
+// deleteVaultCredentials removes the credentials read from Vault for the given backend security policy, if any, and
+// revokes their lease.
+func (c *BackendSecurityPolicyController) deleteVaultCredentials(ctx context.Context, namespace, name string) {
+	if c.vaultCredentials == nil {
+		return
+	}
+	if err := c.vaultCredentials.Delete(ctx, namespace, name); err != nil {
+		c.logger.Error(err, "failed to revoke the vault credentials of backend security policy",
+			"namespace", namespace, "name", name)
+	}
+}
+
 // rotateCredential rotates the credentials using the access token from OIDC provider and return the requeue time for next rotation.
 func (c *BackendSecurityPolicyController) rotateCredential(ctx context.Context, bsp *aigv1b1.BackendSecurityPolicy) (res ctrl.Result, err error) {
 	var rotator rotators.Rotator
@@ -239,6 +282,21 @@ func (c *BackendSecurityPolicyController) rotateCredential(ctx context.Context,
 			return ctrl.Result{}, nil
 		}
 
+	case aigv1b1.BackendSecurityPolicyTypeVault:
+		if c.vaultCredentials == nil {
+			return ctrl.Result{}, errVaultRequiresConfigServer
+		}
+		if ref := bsp.Spec.Vault.Auth.TokenSecretRef; ref != nil && ref.Namespace != nil {
+			// The controller can read the Secrets of any namespace, so the cross-namespace references must be granted.
+			if err = newReferenceGrantValidator(c.client).validateSecretReference(ctx, bsp.Namespace,
+				string(*ref.Namespace), string(ref.Name)); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		rotator, err = rotators.NewVaultRotator(c.client, c.logger, bsp, preRotationWindow, c.vaultCredentials, c.vaultConfig)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
 	default:
 		err = fmt.Errorf("backend security type %s does not support OIDC token exchange", bsp.Spec.Type)
 		c.logger.Error(err, "unsupported backend security type", "namespace", bsp.Namespace, "name", bsp.Name)
@@ -432,8 +490,13 @@ func getBSPGeneratedSecretName(bsp *aigv1b1.BackendSecurityPolicy) string {
 		}
 	case aigv1b1.BackendSecurityPolicyTypeAPIKey,
 		aigv1b1.BackendSecurityPolicyTypeAzureAPIKey,
-		aigv1b1.BackendSecurityPolicyTypeAnthropicAPIKey:
+		aigv1b1.BackendSecurityPolicyTypeAnthropicAPIKey,
+		aigv1b1.BackendSecurityPolicyTypeAPIKeyPool,
+		aigv1b1.BackendSecurityPolicyTypeClientCertificate,
+		aigv1b1.BackendSecurityPolicyTypeRequestSigning:
 		return "" // APIKey does not require rotation.
+	case aigv1b1.BackendSecurityPolicyTypeVault:
+		return "" // The Vault credentials are only kept in memory.
 	default:
 		panic("BUG: unsupported backend security policy type: " + string(bsp.Spec.Type))
 	}
@@ -129,6 +129,35 @@ func TestBackendSecurityController_Reconcile(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestBackendSecurityController_Reconcile_AzureIdentityWithoutRotation(t *testing.T) {
+	fakeClient := requireNewFakeClientWithIndexes(t)
+	kube := fake2.NewClientset()
+	c := NewBackendSecurityPolicyController(fakeClient, kube, ctrl.Log,
+		internaltesting.NewControllerEventChan[*aigv1b1.AIServiceBackend]().Ch,
+		internaltesting.NewControllerEventChan[*gwaiev1.InferencePool]().Ch)
+	for name, creds := range map[string]*aigv1b1.BackendSecurityPolicyAzureCredentials{
+		"managed-identity":  {ManagedIdentity: &aigv1b1.AzureManagedIdentity{}},
+		"workload-identity": {ClientID: "client-id", TenantID: "tenant-id", WorkloadIdentity: &aigv1b1.AzureWorkloadIdentity{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, fakeClient.Create(t.Context(), &aigv1b1.BackendSecurityPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec: aigv1b1.BackendSecurityPolicySpec{
+					Type:             aigv1b1.BackendSecurityPolicyTypeAzureCredentials,
+					AzureCredentials: creds,
+				},
+			}))
+			res, err := c.Reconcile(t.Context(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}})
+			require.NoError(t, err)
+			// No token is rotated by the controller.
+			require.Zero(t, res.RequeueAfter)
+			secrets, err := kube.CoreV1().Secrets("default").List(t.Context(), metav1.ListOptions{})
+			require.NoError(t, err)
+			require.Empty(t, secrets.Items)
+		})
+	}
+}
+
 // mockSTSClient implements the STSOperations interface for testing.
 type mockSTSClient struct {
 	expTime time.Time
@@ -183,6 +212,115 @@ func TestBackendSecurityPolicyController_Reconcile_SyncError(t *testing.T) {
 	require.Equal(t, aigv1b1.ConditionTypeNotAccepted, updatedBSP.Status.Conditions[0].Type)
 }
 
+func TestBackendSecurityPolicyController_Reconcile_VaultWithoutConfigServer(t *testing.T) {
+	eventCh := internaltesting.NewControllerEventChan[*aigv1b1.AIServiceBackend]()
+	fakeClient := requireNewFakeClientWithIndexes(t)
+	c := NewBackendSecurityPolicyController(fakeClient, fake2.NewClientset(), ctrl.Log, eventCh.Ch, nil)
+
+	bsp := &aigv1b1.BackendSecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "vault", Namespace: "default"},
+		Spec: aigv1b1.BackendSecurityPolicySpec{
+			Type: aigv1b1.BackendSecurityPolicyTypeVault,
+			Vault: &aigv1b1.BackendSecurityPolicyVault{
+				Address:    "https://vault:8200",
+				KV:         &aigv1b1.VaultKVSecret{Path: "ai/openai"},
+				Credential: aigv1b1.VaultCredential{Type: aigv1b1.VaultCredentialTypeAPIKey},
+			},
+		},
+	}
+	require.NoError(t, fakeClient.Create(t.Context(), bsp))
+
+	// The Vault credentials are not read without the config server, which is the only way to deliver them.
+	_, err := c.Reconcile(t.Context(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "vault"}})
+	require.ErrorIs(t, err, errVaultRequiresConfigServer)
+
+	var updatedBSP aigv1b1.BackendSecurityPolicy
+	require.NoError(t, fakeClient.Get(t.Context(), types.NamespacedName{Namespace: "default", Name: "vault"}, &updatedBSP))
+	require.Len(t, updatedBSP.Status.Conditions, 1)
+	require.Equal(t, aigv1b1.ConditionTypeNotAccepted, updatedBSP.Status.Conditions[0].Type)
+	require.Contains(t, updatedBSP.Status.Conditions[0].Message, "require the config server")
+}
+
+func TestBackendSecurityPolicyController_Reconcile_VaultNotAllowed(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		vault  *aigv1b1.BackendSecurityPolicyVault
+		expErr string
+	}{
+		{
+			name: "token secret in another namespace",
+			vault: &aigv1b1.BackendSecurityPolicyVault{
+				Address: "https://vault:8200",
+				Auth: aigv1b1.VaultAuth{TokenSecretRef: &gwapiv1.SecretObjectReference{
+					Name: "vault-token", Namespace: ptr.To[gwapiv1.Namespace]("vault"),
+				}},
+				KV:         &aigv1b1.VaultKVSecret{Path: "ai/openai"},
+				Credential: aigv1b1.VaultCredential{Type: aigv1b1.VaultCredentialTypeAPIKey},
+			},
+			expErr: "cross-namespace reference from BackendSecurityPolicy in namespace default to Secret vault-token in namespace vault is not permitted",
+		},
+		{
+			name: "vault server not allowed",
+			vault: &aigv1b1.BackendSecurityPolicyVault{
+				Address:    "https://attacker.example.com",
+				Auth:       aigv1b1.VaultAuth{Kubernetes: &aigv1b1.VaultKubernetesAuth{Role: "ai-gateway"}},
+				KV:         &aigv1b1.VaultKVSecret{Path: "ai/openai"},
+				Credential: aigv1b1.VaultCredential{Type: aigv1b1.VaultCredentialTypeAPIKey},
+			},
+			expErr: "vault server https://attacker.example.com is not allowed by the controller configuration",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			eventCh := internaltesting.NewControllerEventChan[*aigv1b1.AIServiceBackend]()
+			fakeClient := requireNewFakeClientWithIndexes(t)
+			c := NewBackendSecurityPolicyController(fakeClient, fake2.NewClientset(), ctrl.Log, eventCh.Ch, nil)
+			c.vaultCredentials = rotators.NewVaultCredentialStore()
+			c.vaultConfig = &rotators.VaultConfig{
+				Addresses:           []string{"https://vault:8200"},
+				KubernetesAuthRoles: map[string][]string{"default": {"ai-gateway"}},
+			}
+			require.NoError(t, fakeClient.Create(t.Context(), &aigv1b1.BackendSecurityPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "vault", Namespace: "default"},
+				Spec:       aigv1b1.BackendSecurityPolicySpec{Type: aigv1b1.BackendSecurityPolicyTypeVault, Vault: tc.vault},
+			}))
+
+			_, err := c.Reconcile(t.Context(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "vault"}})
+			require.ErrorContains(t, err, tc.expErr)
+			// The Vault server is never contacted.
+			_, ok := c.vaultCredentials.Get("default", "vault")
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestBackendSecurityPolicyController_Reconcile_VaultDeleted(t *testing.T) {
+	eventCh := internaltesting.NewControllerEventChan[*aigv1b1.AIServiceBackend]()
+	fakeClient := requireNewFakeClientWithIndexes(t)
+	c := NewBackendSecurityPolicyController(fakeClient, fake2.NewClientset(), ctrl.Log, eventCh.Ch, nil)
+	c.vaultCredentials = rotators.NewVaultCredentialStore()
+	c.vaultCredentials.Set("default", "deleted", map[string]string{"apiKey": "sk-deleted"}, time.Now().Add(time.Hour))
+	c.vaultCredentials.Set("default", "changed", map[string]string{"apiKey": "sk-changed"}, time.Now().Add(time.Hour))
+
+	// The credentials of a deleted policy are removed, so that a policy created again with the same name doesn't get them.
+	_, err := c.Reconcile(t.Context(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "deleted"}})
+	require.NoError(t, err)
+	_, ok := c.vaultCredentials.Get("default", "deleted")
+	require.False(t, ok)
+
+	// The credentials of a policy that is not of the Vault type anymore are removed as well.
+	require.NoError(t, fakeClient.Create(t.Context(), &aigv1b1.BackendSecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "changed", Namespace: "default"},
+		Spec: aigv1b1.BackendSecurityPolicySpec{
+			Type:   aigv1b1.BackendSecurityPolicyTypeAPIKey,
+			APIKey: &aigv1b1.BackendSecurityPolicyAPIKey{SecretRef: &gwapiv1.SecretObjectReference{Name: "api-key"}},
+		},
+	}))
+	_, err = c.Reconcile(t.Context(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "changed"}})
+	require.NoError(t, err)
+	_, ok = c.vaultCredentials.Get("default", "changed")
+	require.False(t, ok)
+}
+
 func TestBackendSecurityPolicyController_ReconcileOIDC_Fail(t *testing.T) {
 	eventCh := internaltesting.NewControllerEventChan[*aigv1b1.AIServiceBackend]()
 	cl := fake.NewClientBuilder().WithScheme(Scheme).Build()
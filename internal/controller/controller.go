@@ -17,6 +17,7 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -41,6 +42,8 @@ import (
 
 	aigv1a1 "github.com/envoyproxy/ai-gateway/api/v1alpha1"
 	aigv1b1 "github.com/envoyproxy/ai-gateway/api/v1beta1"
+	"github.com/envoyproxy/ai-gateway/internal/configserver"
+	"github.com/envoyproxy/ai-gateway/internal/controller/rotators"
 	"github.com/envoyproxy/ai-gateway/internal/ratelimit/runner"
 )
 
@@ -102,10 +105,29 @@ type Options struct {
 	MCPFallbackSessionEncryptionSeed string
 	// MCPFallbackSessionEncryptionIterations is the number of iterations used in the fallback PBKDF2 key derivation for MCP session encryption.
 	MCPFallbackSessionEncryptionIterations int
+	// MCPSessionStore is the optional store of the MCP session state shared by the replicas of the MCP proxy.
+	MCPSessionStore string
+	// MCPAuditLog is the optional destination of the audit log of the MCP requests.
+	MCPAuditLog string
+	// MCPAuditArguments specifies how the arguments of the audited MCP requests are recorded.
+	MCPAuditArguments string
 	// EndpointPrefixes is the comma-separated key-value pairs for endpoint prefixes.
 	EndpointPrefixes string
 	// RateLimitRunner is the xDS runner that serves rate limit configs to the rate limit service.
 	RateLimitRunner *runner.Runner
+	// ConfigServer is the xDS server that streams the filter configs to the external processors. When nil, the
+	// filter configs are delivered via the Secrets mounted in the Gateway pods.
+	ConfigServer *configserver.Server
+	// ConfigServerAddr is the address of the ConfigServer that the external processors connect to.
+	ConfigServerAddr string
+	// ConfigServerCACert is the PEM encoded CA certificate that the external processors verify the ConfigServer with.
+	ConfigServerCACert string
+	// Vault restricts the Vault servers and the roles that the Vault backend security policies can use. When nil, no
+	// Vault server is allowed.
+	Vault *rotators.VaultConfig
+	// EnableMCPStdioServers allows the MCPRoutes to run stdio MCP servers in the external processor container.
+	// This is disabled by default since the authors of the MCPRoutes can then run arbitrary commands there.
+	EnableMCPStdioServers bool
 }
 
 // StartControllers starts the controllers for the AI Gateway.
@@ -128,6 +150,16 @@ func StartControllers(ctx context.Context, mgr manager.Manager, config *rest.Con
 	gatewayEventChan := make(chan event.GenericEvent, 100)
 	gatewayC := NewGatewayController(c, kubernetes.NewForConfigOrDie(config),
 		logger.WithName("gateway"), options.ExtProcImage, options.ExtProcLogLevel, false, uuid.NewString, isKubernetes133OrLater(versionInfo, logger))
+	// The credentials read from Vault are only kept in memory and delivered by the config server.
+	var vaultCredentials *rotators.VaultCredentialStore
+	if options.ConfigServer != nil {
+		gatewayC.configServer = options.ConfigServer
+		options.ConfigServer.SetStatusHandler(gatewayC.updateConfigStatusConditions)
+		vaultCredentials = rotators.NewVaultCredentialStore()
+		gatewayC.vaultCredentials = vaultCredentials
+	}
+	gatewayC.enableMCPStdioServers = options.EnableMCPStdioServers
+	gatewayC.gatewayEventChan = gatewayEventChan
 	if err = TypedControllerBuilderForCRD(mgr, &gwapiv1.Gateway{}).
 		WatchesRawSource(source.Channel(
 			gatewayEventChan,
@@ -168,6 +200,8 @@ func StartControllers(ctx context.Context, mgr manager.Manager, config *rest.Con
 	inferencePoolEventChan := make(chan event.GenericEvent, 100)
 	backendSecurityPolicyC := NewBackendSecurityPolicyController(c, kubernetes.NewForConfigOrDie(config), logger.
 		WithName("backend-security-policy"), aiServiceBackendEventChan, inferencePoolEventChan)
+	backendSecurityPolicyC.vaultCredentials = vaultCredentials
+	backendSecurityPolicyC.vaultConfig = options.Vault
 	if err = TypedControllerBuilderForCRD(mgr, &aigv1b1.BackendSecurityPolicy{}).
 		WatchesRawSource(source.Channel(
 			backendSecurityPolicyEventChan,
@@ -220,6 +254,7 @@ func StartControllers(ctx context.Context, mgr manager.Manager, config *rest.Con
 	mcpRouteC := NewMCPRouteController(c, kubernetes.NewForConfigOrDie(config), logger.WithName("ai-gateway-mcp-route"),
 		gatewayEventChan,
 	)
+	mcpRouteC.enableStdioServers = options.EnableMCPStdioServers
 	if err = TypedControllerBuilderForCRD(mgr, &aigv1b1.MCPRoute{}).
 		Owns(&gwapiv1.HTTPRoute{}).
 		Owns(&egv1a1.Backend{}).
@@ -248,6 +283,14 @@ func StartControllers(ctx context.Context, mgr manager.Manager, config *rest.Con
 		}
 	}
 
+	// VirtualKey controller for the client API keys issued by the gateway.
+	virtualKeyC := NewVirtualKeyController(c, logger.WithName("virtual-key"), aiGatewayRouteEventChan)
+	if err = TypedControllerBuilderForCRD(mgr, &aigv1a1.VirtualKey{}).
+		Owns(&corev1.Secret{}).
+		Complete(virtualKeyC); err != nil {
+		return fmt.Errorf("failed to create controller for VirtualKey: %w", err)
+	}
+
 	// ReferenceGrant controller for cross-namespace access validation
 	referenceGrantC := NewReferenceGrantController(c, logger.WithName("reference-grant"), aiGatewayRouteEventChan)
 	if err = TypedControllerBuilderForCRD(mgr, &gwapiv1b1.ReferenceGrant{}).
@@ -277,6 +320,11 @@ func StartControllers(ctx context.Context, mgr manager.Manager, config *rest.Con
 			options.MCPSessionEncryptionIterations,
 			options.MCPFallbackSessionEncryptionSeed,
 			options.MCPFallbackSessionEncryptionIterations,
+			options.MCPSessionStore,
+			options.MCPAuditLog,
+			options.MCPAuditArguments,
+			options.ConfigServerAddr,
+			options.ConfigServerCACert,
 		))
 		mgr.GetWebhookServer().Register("/mutate", &webhook.Admission{Handler: h})
 	}
@@ -320,6 +368,9 @@ const (
 	// k8sClientIndexAIServiceBackendToTargetingQuotaPolicy is the index name that maps from an AIServiceBackend
 	// to the QuotaPolicy whose targetRefs contains the AIServiceBackend.
 	k8sClientIndexAIServiceBackendToTargetingQuotaPolicy = "AIServiceBackendToTargetingQuotaPolicy"
+	// k8sClientIndexAIGatewayRouteToTargetingVirtualKey is the index name that maps from an AIGatewayRoute
+	// to the VirtualKey whose targetRefs contains the AIGatewayRoute.
+	k8sClientIndexAIGatewayRouteToTargetingVirtualKey = "AIGatewayRouteToTargetingVirtualKey"
 	// k8sClientIndexGatewayToGatewayConfig maps from a GatewayConfig name to Gateways referencing it.
 	k8sClientIndexGatewayToGatewayConfig = "GatewayToGatewayConfig"
 
@@ -369,6 +420,12 @@ func ApplyIndexing(ctx context.Context, indexer func(ctx context.Context, obj cl
 		return fmt.Errorf("failed to index field for QuotaPolicy targetRefs: %w", err)
 	}
 
+	err = indexer(ctx, &aigv1a1.VirtualKey{},
+		k8sClientIndexAIGatewayRouteToTargetingVirtualKey, virtualKeyTargetRefsIndexFunc)
+	if err != nil {
+		return fmt.Errorf("failed to index field for VirtualKey targetRefs: %w", err)
+	}
+
 	err = indexer(ctx, &gwapiv1.Gateway{},
 		k8sClientIndexGatewayToGatewayConfig, gatewayToGatewayConfigIndexFunc)
 	if err != nil {
@@ -417,7 +474,20 @@ func mcpRouteToReferencedSecret(o client.Object) []string {
 	mcpRoute := o.(*aigv1b1.MCPRoute)
 	var ret []string
 	for _, ref := range mcpRoute.Spec.BackendRefs {
-		if ref.SecurityPolicy == nil || ref.SecurityPolicy.APIKey == nil || ref.SecurityPolicy.APIKey.SecretRef == nil {
+		if ref.Stdio != nil && ref.Stdio.EnvFrom != nil {
+			// Only the Secrets in the namespace of the MCPRoute are used, see resolveMCPStdioEnvFrom.
+			ret = append(ret, fmt.Sprintf("%s.%s", ref.Stdio.EnvFrom.Name, mcpRoute.Namespace))
+		}
+		if ref.SecurityPolicy == nil {
+			continue
+		}
+		if te := ref.SecurityPolicy.TokenExchange; te != nil && te.ClientSecretRef != nil {
+			ret = append(ret, fmt.Sprintf("%s.%s", te.ClientSecretRef.Name, secretNamespace(te.ClientSecretRef, mcpRoute.Namespace)))
+		}
+		if uc := ref.SecurityPolicy.UserCredentials; uc != nil {
+			ret = append(ret, fmt.Sprintf("%s.%s", uc.SecretRef.Name, secretNamespace(&uc.SecretRef, mcpRoute.Namespace)))
+		}
+		if ref.SecurityPolicy.APIKey == nil || ref.SecurityPolicy.APIKey.SecretRef == nil {
 			continue
 		}
 		apiKeyRef := ref.SecurityPolicy.APIKey.SecretRef
@@ -502,6 +572,21 @@ func backendSecurityPolicyIndexFunc(o client.Object) []string {
 	case aigv1b1.BackendSecurityPolicyTypeAnthropicAPIKey:
 		apiKey := backendSecurityPolicy.Spec.AnthropicAPIKey
 		key = getSecretNameAndNamespace(apiKey.SecretRef, backendSecurityPolicy.Namespace)
+	case aigv1b1.BackendSecurityPolicyTypeClientCertificate:
+		key = getSecretNameAndNamespace(backendSecurityPolicy.Spec.ClientCertificate.SecretRef, backendSecurityPolicy.Namespace)
+	case aigv1b1.BackendSecurityPolicyTypeRequestSigning:
+		key = getSecretNameAndNamespace(backendSecurityPolicy.Spec.RequestSigning.SecretRef, backendSecurityPolicy.Namespace)
+	case aigv1b1.BackendSecurityPolicyTypeVault:
+		if ref := backendSecurityPolicy.Spec.Vault.Auth.TokenSecretRef; ref != nil {
+			key = getSecretNameAndNamespace(ref, backendSecurityPolicy.Namespace)
+		}
+	case aigv1b1.BackendSecurityPolicyTypeAPIKeyPool:
+		// Each key of the pool is stored in its own secret.
+		var keys []string
+		for _, k := range backendSecurityPolicy.Spec.APIKeyPool.Keys {
+			keys = append(keys, getSecretNameAndNamespace(k.SecretRef, backendSecurityPolicy.Namespace))
+		}
+		return keys
 	case aigv1b1.BackendSecurityPolicyTypeAzureCredentials:
 		azureCreds := backendSecurityPolicy.Spec.AzureCredentials
 		if azureCreds.ClientSecretRef != nil {
@@ -531,6 +616,15 @@ func quotaPolicyTargetRefsIndexFunc(o client.Object) []string {
 	return ret
 }
 
+func virtualKeyTargetRefsIndexFunc(o client.Object) []string {
+	virtualKey := o.(*aigv1a1.VirtualKey)
+	var ret []string
+	for _, targetRef := range virtualKey.Spec.TargetRefs {
+		ret = append(ret, fmt.Sprintf("%s.%s", targetRef.Name, virtualKey.Namespace))
+	}
+	return ret
+}
+
 func getSecretNameAndNamespace(secretRef *gwapiv1.SecretObjectReference, namespace string) string {
 	if secretRef.Namespace != nil {
 		return fmt.Sprintf("%s.%s", secretRef.Name, *secretRef.Namespace)
@@ -573,6 +667,17 @@ func newConditions(conditionType, message string) []metav1.Condition {
 	return []metav1.Condition{condition}
 }
 
+// withReportedConditions appends to the given conditions the ones in existing that are not managed by the reconciler
+// of the resource, but set by the GatewayController from the status reported by the config server.
+func withReportedConditions(existing, conditions []metav1.Condition) []metav1.Condition {
+	for _, conditionType := range []string{aigv1b1.ConditionTypeProgrammed, aigv1b1.ConditionTypeResolvedRefs} {
+		if c := meta.FindStatusCondition(existing, conditionType); c != nil {
+			conditions = append(conditions, *c)
+		}
+	}
+	return conditions
+}
+
 // aiGatewayControllerFinalizer is the name of the finalizer added to various AI Gateway resources.
 const aiGatewayControllerFinalizer = "aigateway.envoyproxy.io/finalizer"
 
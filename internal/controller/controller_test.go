@@ -236,6 +236,34 @@ func Test_backendSecurityPolicyIndexFunc(t *testing.T) {
 			},
 			expKey: "some-aaaa.ns",
 		},
+		{
+			name: "client certificate",
+			backendSecurityPolicy: &aigv1b1.BackendSecurityPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "some-backend-security-policy-11", Namespace: "ns"},
+				Spec: aigv1b1.BackendSecurityPolicySpec{
+					Type: aigv1b1.BackendSecurityPolicyTypeClientCertificate,
+					ClientCertificate: &aigv1b1.BackendSecurityPolicyClientCertificate{
+						SecretRef: &gwapiv1.SecretObjectReference{Name: "client-tls"},
+					},
+				},
+			},
+			expKey: "client-tls.ns",
+		},
+		{
+			name: "request signing",
+			backendSecurityPolicy: &aigv1b1.BackendSecurityPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "some-backend-security-policy-12", Namespace: "ns"},
+				Spec: aigv1b1.BackendSecurityPolicySpec{
+					Type: aigv1b1.BackendSecurityPolicyTypeRequestSigning,
+					RequestSigning: &aigv1b1.BackendSecurityPolicyRequestSigning{
+						Scheme:    aigv1b1.RequestSigningSchemeHMACSHA256,
+						KeyID:     "key",
+						SecretRef: &gwapiv1.SecretObjectReference{Name: "signing-key", Namespace: ptr.To[gwapiv1.Namespace]("foo")},
+					},
+				},
+			},
+			expKey: "signing-key.foo",
+		},
 	} {
 		t.Run(bsp.name, func(t *testing.T) {
 			c := fake.NewClientBuilder().
@@ -257,6 +285,22 @@ func Test_backendSecurityPolicyIndexFunc(t *testing.T) {
 	}
 }
 
+func Test_backendSecurityPolicyIndexFunc_APIKeyPool(t *testing.T) {
+	keys := backendSecurityPolicyIndexFunc(&aigv1b1.BackendSecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "ns"},
+		Spec: aigv1b1.BackendSecurityPolicySpec{
+			Type: aigv1b1.BackendSecurityPolicyTypeAPIKeyPool,
+			APIKeyPool: &aigv1b1.BackendSecurityPolicyAPIKeyPool{
+				Keys: []aigv1b1.BackendSecurityPolicyAPIKeyPoolKey{
+					{SecretRef: &gwapiv1.SecretObjectReference{Name: "key1"}},
+					{SecretRef: &gwapiv1.SecretObjectReference{Name: "key2", Namespace: ptr.To[gwapiv1.Namespace]("foo")}},
+				},
+			},
+		},
+	})
+	require.Equal(t, []string{"key1.ns", "key2.foo"}, keys)
+}
+
 func Test_getSecretNameAndNamespace(t *testing.T) {
 	secretRef := &gwapiv1.SecretObjectReference{
 		Name:      "mysecret",
@@ -797,6 +841,52 @@ func Test_mcpRouteToAttachedGatewayIndexFunc(t *testing.T) {
 	}
 }
 
+func Test_mcpRouteToReferencedSecret(t *testing.T) {
+	route := &aigv1b1.MCPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "mcp-route", Namespace: "ns"},
+		Spec: aigv1b1.MCPRouteSpec{
+			BackendRefs: []aigv1b1.MCPRouteBackendRef{
+				{
+					BackendObjectReference: gwapiv1.BackendObjectReference{Name: "api-key"},
+					SecurityPolicy: &aigv1b1.MCPBackendSecurityPolicy{
+						APIKey: &aigv1b1.MCPBackendAPIKey{SecretRef: &gwapiv1.SecretObjectReference{Name: "key"}},
+					},
+				},
+				{
+					BackendObjectReference: gwapiv1.BackendObjectReference{Name: "stdio"},
+					Stdio: &aigv1b1.MCPStdioServer{
+						Command: "server",
+						EnvFrom: &gwapiv1.SecretObjectReference{Name: "env", Namespace: ptr.To(gwapiv1.Namespace("other"))},
+					},
+				},
+				{
+					BackendObjectReference: gwapiv1.BackendObjectReference{Name: "stdio-no-env"},
+					Stdio:                  &aigv1b1.MCPStdioServer{Command: "server"},
+				},
+				{
+					BackendObjectReference: gwapiv1.BackendObjectReference{Name: "token-exchange"},
+					SecurityPolicy: &aigv1b1.MCPBackendSecurityPolicy{
+						TokenExchange: &aigv1b1.MCPBackendTokenExchange{
+							TokenEndpoint:   "https://auth.example.com/token",
+							ClientID:        "gateway",
+							ClientSecretRef: &gwapiv1.SecretObjectReference{Name: "client"},
+						},
+					},
+				},
+				{
+					BackendObjectReference: gwapiv1.BackendObjectReference{Name: "user-credentials"},
+					SecurityPolicy: &aigv1b1.MCPBackendSecurityPolicy{
+						UserCredentials: &aigv1b1.MCPBackendUserCredentials{
+							SecretRef: gwapiv1.SecretObjectReference{Name: "users", Namespace: ptr.To(gwapiv1.Namespace("other"))},
+						},
+					},
+				},
+			},
+		},
+	}
+	require.ElementsMatch(t, []string{"key.ns", "env.ns", "client.ns", "users.other"}, mcpRouteToReferencedSecret(route))
+}
+
 func Test_isKubernetes133OrLater(t *testing.T) {
 	require.False(t, isKubernetes133OrLater(&version.Info{}, logr.Discard()))
 	require.False(t, isKubernetes133OrLater(&version.Info{Major: "invalid"}, logr.Discard()))
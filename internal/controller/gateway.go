@@ -8,9 +8,14 @@ package controller
 import (
 	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	egv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
@@ -19,21 +24,26 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	"sigs.k8s.io/yaml"
 
 	aigv1a1 "github.com/envoyproxy/ai-gateway/api/v1alpha1"
 	aigv1b1 "github.com/envoyproxy/ai-gateway/api/v1beta1"
+	"github.com/envoyproxy/ai-gateway/internal/configserver"
 	"github.com/envoyproxy/ai-gateway/internal/controller/rotators"
 	"github.com/envoyproxy/ai-gateway/internal/filterapi"
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 	"github.com/envoyproxy/ai-gateway/internal/llmcostcel"
+	"github.com/envoyproxy/ai-gateway/internal/mcpopenapi"
 	"github.com/envoyproxy/ai-gateway/internal/version"
 )
 
@@ -42,6 +52,10 @@ const (
 	FilterConfigKeyInSecret = "filter-config.yaml" //nolint: gosec
 	// defaultOwnedBy is the default value for the ModelsOwnedBy field in the filter config.
 	defaultOwnedBy = "Envoy AI Gateway"
+	// defaultToolExecutionMaxIterations and defaultToolExecutionTimeout must match the defaults of
+	// AIGatewayRouteToolExecution.
+	defaultToolExecutionMaxIterations = 5
+	defaultToolExecutionTimeout       = gwapiv1.Duration("60s")
 )
 
 // NewGatewayController creates a new reconcile.TypedReconciler for gwapiv1.Gateway.
@@ -56,7 +70,7 @@ func NewGatewayController(
 	if uf == nil {
 		uf = uuid.NewString
 	}
-	return &GatewayController{
+	c := &GatewayController{
 		client:           client,
 		kube:             kube,
 		logger:           logger,
@@ -66,6 +80,8 @@ func NewGatewayController(
 		uuidFn:           uf,
 		extProcAsSideCar: extProcAsSideCar,
 	}
+	c.openAPIDocuments = newOpenAPIDocumentCache(logger, c.enqueueGateways)
+	return c
 }
 
 // GatewayController implements reconcile.TypedReconciler for gwapiv1.Gateway.
@@ -81,6 +97,38 @@ type GatewayController struct {
 	// Whether to run the extProc container as a sidecar (true) as a normal container (false).
 	// This is essentially a workaround for old k8s versions, and we can remove this in the future.
 	extProcAsSideCar bool
+	// configServer streams the filter config to the external processors instead of the filter config Secret when set.
+	configServer *configserver.Server
+	// enableMCPStdioServers allows the MCPRoutes to run stdio MCP servers in the external processor container.
+	enableMCPStdioServers bool
+	// vaultCredentials holds the credentials read from Vault by the backend security policy controller. This is only
+	// set with the config server, so that these credentials never land in the filter config Secrets.
+	vaultCredentials *rotators.VaultCredentialStore
+	// statusConditions are the Programmed and ResolvedRefs conditions reported by each Gateway.
+	statusConditions gatewayStatusConditions
+	// openAPIDocuments holds the OpenAPI documents of the MCP backends referenced by URL.
+	openAPIDocuments *openAPIDocumentCache
+	// gatewayEventChan is the channel of the Gateways to reconcile again, e.g. when an OpenAPI document changed.
+	gatewayEventChan chan event.GenericEvent
+}
+
+// gatewayStatusConditions are the Programmed and ResolvedRefs conditions of the AIGatewayRoutes and the
+// AIServiceBackends reported by each Gateway. The resources shared by several Gateways get the combination of the
+// conditions of all of them, so that the Gateways don't overwrite each other's conditions.
+type gatewayStatusConditions struct {
+	// mu serializes the status updates, so that the last one is the combination of the latest conditions.
+	mu sync.Mutex
+	// byObject maps each resource to its conditions by Gateway node ID and condition type.
+	byObject map[statusObjectKey]map[string]map[string]metav1.Condition
+	// byGateway maps each Gateway node ID to the resources it reported conditions for.
+	byGateway map[string][]statusObjectKey
+}
+
+// statusObjectKey identifies a resource whose conditions are reported by the Gateways.
+type statusObjectKey struct {
+	// kind is either aiGatewayRouteKind or aiServiceBackendKind.
+	kind string
+	client.ObjectKey
 }
 
 // Reconcile implements the reconcile.Reconciler for gwapiv1.Gateway.
@@ -88,6 +136,12 @@ func (c *GatewayController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	gw := &gwapiv1.Gateway{}
 	if err := c.client.Get(ctx, req.NamespacedName, gw); err != nil {
 		if apierrors.IsNotFound(err) {
+			if c.configServer != nil {
+				c.configServer.RemoveConfig(req.Namespace, req.Name)
+			}
+			// The deleted Gateway doesn't contribute to the conditions of its resources anymore.
+			c.reportGatewayStatusConditions(ctx, configserver.NodeID(req.Namespace, req.Name), nil)
+			c.openAPIDocuments.retain(req.NamespacedName, nil)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
@@ -145,10 +199,14 @@ func (c *GatewayController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	// We need to create the filter config in Envoy Gateway system namespace because the sidecar extproc need
 	// to access it.
 	var hasEffectiveRoutes bool // indicates whether the filter config is effective (i.e., there is at least one active route).
-	hasEffectiveRoutes, err = c.reconcileFilterConfigSecret(ctx, FilterConfigSecretPerGatewayName(gw.Name, gw.Namespace), namespace, aiRoutes.Items, mcpRoutes.Items, uid, defaultLLMCosts)
+	hasEffectiveRoutes, err = c.reconcileFilterConfigSecret(ctx, gw, FilterConfigSecretPerGatewayName(gw.Name, gw.Namespace), namespace, aiRoutes.Items, mcpRoutes.Items, uid, defaultLLMCosts)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if c.configServer == nil {
+		// Whether the filter config Secret was applied by the external processors cannot be observed.
+		c.setConfigStatusConditionsUnknown(ctx, gw.Namespace, gw.Name)
+	}
 
 	// Finally, we need to annotate the pods of the gateway deployment with the new uuid to propagate the filter config Secret update faster.
 	// If the pod doesn't have the extproc container, it will roll out the deployment altogether which eventually ends up
@@ -161,6 +219,250 @@ func (c *GatewayController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	return result, nil
 }
 
+// updateConfigStatusConditions sets the conditions reporting whether the external processors of a Gateway applied
+// the latest filter config delivered by the config server: the Programmed condition of the AIGatewayRoutes attached
+// to the Gateway and of the AIServiceBackends they reference, and the ResolvedRefs condition of these
+// AIServiceBackends unless the config is pending or was rejected before the backends were set up.
+func (c *GatewayController) updateConfigStatusConditions(ctx context.Context, status configserver.Status) {
+	namespace, name, ok := configserver.ParseNodeID(status.NodeID)
+	if !ok {
+		c.logger.Info("Ignoring the config status of an unknown node", "node", status.NodeID)
+		return
+	}
+
+	programmed := metav1.Condition{
+		Type:    aigv1b1.ConditionTypeProgrammed,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ConfigApplied",
+		Message: fmt.Sprintf("Filter config %s applied by the external processors of Gateway %s", status.Version, status.NodeID),
+	}
+	switch {
+	case status.Pending:
+		programmed.Status = metav1.ConditionUnknown
+		programmed.Reason = "ConfigPending"
+		programmed.Message = fmt.Sprintf("Filter config %s not yet applied by all the external processors of Gateway %s",
+			status.Version, status.NodeID)
+	case status.Error != "":
+		programmed.Status = metav1.ConditionFalse
+		programmed.Reason = "ConfigRejected"
+		programmed.Message = fmt.Sprintf("Filter config %s rejected by the external processors of Gateway %s: %s",
+			status.Version, status.NodeID, status.Error)
+	}
+	c.setGatewayStatusConditions(ctx, namespace, name, programmed, func(backendNames []string) (metav1.Condition, bool) {
+		// The config rejected without backend errors, e.g. on version mismatch, didn't get to set up the backends.
+		if status.Pending || (status.Error != "" && len(status.BackendErrors) == 0) {
+			return metav1.Condition{}, false
+		}
+		return resolvedRefsCondition(status, backendNames), true
+	})
+}
+
+// setConfigStatusConditionsUnknown sets the Programmed and ResolvedRefs conditions of the resources of the given
+// Gateway to Unknown, as they are only reported by the external processors when the config server is enabled.
+func (c *GatewayController) setConfigStatusConditionsUnknown(ctx context.Context, gwNamespace, gwName string) {
+	const reason, message = "ConfigServerDisabled", "Only reported when the filter config is delivered by the config server"
+	programmed := metav1.Condition{
+		Type: aigv1b1.ConditionTypeProgrammed, Status: metav1.ConditionUnknown, Reason: reason, Message: message,
+	}
+	c.setGatewayStatusConditions(ctx, gwNamespace, gwName, programmed, func([]string) (metav1.Condition, bool) {
+		return metav1.Condition{
+			Type: aigv1b1.ConditionTypeResolvedRefs, Status: metav1.ConditionUnknown, Reason: reason, Message: message,
+		}, true
+	})
+}
+
+// setGatewayStatusConditions sets the given Programmed condition on the AIGatewayRoutes attached to the given
+// Gateway and on the AIServiceBackends they reference, along with the ResolvedRefs condition returned by
+// resolvedRefs, if any, for the names of the filterapi.Backend of each AIServiceBackend. These conditions are
+// combined with the ones reported by the other Gateways of the resources.
+func (c *GatewayController) setGatewayStatusConditions(ctx context.Context, gwNamespace, gwName string,
+	programmed metav1.Condition, resolvedRefs func(backendNames []string) (metav1.Condition, bool),
+) {
+	var aiRoutes aigv1b1.AIGatewayRouteList
+	err := c.client.List(ctx, &aiRoutes, client.MatchingFields{
+		k8sClientIndexAIGatewayRouteToAttachedGateway: fmt.Sprintf("%s.%s", gwName, gwNamespace),
+	})
+	if err != nil {
+		c.logger.Error(err, "Failed to list AIGatewayRoutes", "namespace", gwNamespace, "name", gwName)
+		return
+	}
+
+	// The names of the filterapi.Backend of each AIServiceBackend referenced by the routes.
+	backendNames := make(map[client.ObjectKey][]string)
+	reported := make(map[statusObjectKey][]metav1.Condition)
+	for i := range aiRoutes.Items {
+		route := &aiRoutes.Items[i]
+		reported[statusObjectKey{kind: aiGatewayRouteKind, ObjectKey: client.ObjectKeyFromObject(route)}] = []metav1.Condition{programmed}
+		for ruleIndex := range route.Spec.Rules {
+			for refIndex := range route.Spec.Rules[ruleIndex].BackendRefs {
+				backendRef := &route.Spec.Rules[ruleIndex].BackendRefs[refIndex]
+				if backendRef.IsInferencePool() {
+					continue
+				}
+				key := client.ObjectKey{Namespace: backendRef.GetNamespace(route.Namespace), Name: backendRef.Name}
+				backendNames[key] = append(backendNames[key],
+					internalapi.PerRouteRuleRefBackendName(route.Namespace, backendRef.Name, route.Name, ruleIndex, refIndex))
+			}
+		}
+	}
+	for key, names := range backendNames {
+		conditions := []metav1.Condition{programmed}
+		if condition, ok := resolvedRefs(names); ok {
+			conditions = append(conditions, condition)
+		}
+		reported[statusObjectKey{kind: aiServiceBackendKind, ObjectKey: key}] = conditions
+	}
+	c.reportGatewayStatusConditions(ctx, configserver.NodeID(gwNamespace, gwName), reported)
+}
+
+// reportGatewayStatusConditions records the conditions reported by the given Gateway for each resource, and sets
+// on these resources the combination of the conditions reported by all their Gateways. The conditions previously
+// reported by the Gateway for the resources missing from reported, e.g. the routes detached from it, are dropped.
+// The condition types missing from the conditions of a resource keep their previously reported condition.
+func (c *GatewayController) reportGatewayStatusConditions(ctx context.Context, nodeID string, reported map[statusObjectKey][]metav1.Condition) {
+	sc := &c.statusConditions
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.byObject == nil {
+		sc.byObject = make(map[statusObjectKey]map[string]map[string]metav1.Condition)
+		sc.byGateway = make(map[string][]statusObjectKey)
+	}
+
+	var removed []statusObjectKey
+	for _, key := range sc.byGateway[nodeID] {
+		if _, ok := reported[key]; !ok {
+			delete(sc.byObject[key], nodeID)
+			if len(sc.byObject[key]) == 0 {
+				delete(sc.byObject, key)
+			} else {
+				removed = append(removed, key)
+			}
+		}
+	}
+	keys := slices.Collect(maps.Keys(reported))
+	if len(keys) == 0 {
+		delete(sc.byGateway, nodeID)
+	} else {
+		sc.byGateway[nodeID] = keys
+	}
+	for key, conditions := range reported {
+		if sc.byObject[key] == nil {
+			sc.byObject[key] = make(map[string]map[string]metav1.Condition)
+		}
+		if sc.byObject[key][nodeID] == nil {
+			sc.byObject[key][nodeID] = make(map[string]metav1.Condition)
+		}
+		for _, condition := range conditions {
+			sc.byObject[key][nodeID][condition.Type] = condition
+		}
+	}
+
+	for _, key := range append(keys, removed...) {
+		var conditions []metav1.Condition
+		for _, conditionType := range []string{aigv1b1.ConditionTypeProgrammed, aigv1b1.ConditionTypeResolvedRefs} {
+			if condition, ok := combineGatewayConditions(sc.byObject[key], conditionType); ok {
+				conditions = append(conditions, condition)
+			}
+		}
+		var obj client.Object
+		var objConditions *[]metav1.Condition
+		if key.kind == aiGatewayRouteKind {
+			route := &aigv1b1.AIGatewayRoute{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+			obj, objConditions = route, &route.Status.Conditions
+		} else {
+			backend := &aigv1b1.AIServiceBackend{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+			obj, objConditions = backend, &backend.Status.Conditions
+		}
+		if err := c.setStatusConditions(ctx, obj, objConditions, conditions...); err != nil {
+			c.logger.Error(err, "Failed to update the conditions of "+key.kind, "namespace", key.Namespace, "name", key.Name)
+		}
+	}
+}
+
+// combineGatewayConditions combines the conditions of the given type reported by each Gateway: the condition is False
+// as soon as one Gateway reports False, otherwise Unknown as soon as one reports Unknown, and True when all of them
+// report True. The message joins the messages of the Gateways reporting the combined status, which name the Gateway.
+// This returns false when no Gateway reported a condition of the given type.
+func combineGatewayConditions(byGateway map[string]map[string]metav1.Condition, conditionType string) (metav1.Condition, bool) {
+	severity := func(status metav1.ConditionStatus) int {
+		switch status {
+		case metav1.ConditionFalse:
+			return 2
+		case metav1.ConditionUnknown:
+			return 1
+		default:
+			return 0
+		}
+	}
+	var combined metav1.Condition
+	var messages []string
+	for _, nodeID := range slices.Sorted(maps.Keys(byGateway)) {
+		condition, ok := byGateway[nodeID][conditionType]
+		switch {
+		case !ok:
+		case messages == nil || severity(condition.Status) > severity(combined.Status):
+			combined, messages = condition, []string{condition.Message}
+		case condition.Status == combined.Status && !slices.Contains(messages, condition.Message):
+			messages = append(messages, condition.Message)
+		}
+	}
+	if messages == nil {
+		return metav1.Condition{}, false
+	}
+	combined.Message = strings.Join(messages, "; ")
+	return combined, true
+}
+
+// resolvedRefsCondition returns the ResolvedRefs condition of an AIServiceBackend whose filterapi.Backend are named
+// backendNames in the filter config of the given status.
+func resolvedRefsCondition(status configserver.Status, backendNames []string) metav1.Condition {
+	var errs []string
+	for _, n := range backendNames {
+		if e, ok := status.BackendErrors[n]; ok && !slices.Contains(errs, e) {
+			errs = append(errs, e)
+		}
+	}
+	if len(errs) == 0 {
+		return metav1.Condition{
+			Type:   aigv1b1.ConditionTypeResolvedRefs,
+			Status: metav1.ConditionTrue,
+			Reason: "BackendReady",
+			Message: fmt.Sprintf("Backend set up by the external processors of Gateway %s with filter config %s",
+				status.NodeID, status.Version),
+		}
+	}
+	return metav1.Condition{
+		Type:   aigv1b1.ConditionTypeResolvedRefs,
+		Status: metav1.ConditionFalse,
+		Reason: "BackendSetupFailed",
+		Message: fmt.Sprintf("Backend cannot be set up by the external processors of Gateway %s with filter config %s: %s",
+			status.NodeID, status.Version, strings.Join(errs, "; ")),
+	}
+}
+
+// setStatusConditions sets the given conditions in the status conditions of the given object, pointed to by
+// objConditions, and updates its status if any of them changed.
+func (c *GatewayController) setStatusConditions(ctx context.Context, obj client.Object, objConditions *[]metav1.Condition, conditions ...metav1.Condition) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := c.client.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		var changed bool
+		for _, condition := range conditions {
+			condition.ObservedGeneration = obj.GetGeneration()
+			changed = meta.SetStatusCondition(objConditions, condition) || changed
+		}
+		// Each AIServiceBackend is referenced by many routes, so the status is only updated when it changed.
+		if !changed {
+			return nil
+		}
+		return c.client.Status().Update(ctx, obj)
+	})
+}
+
 // schemaToFilterAPI converts an aigv1b1.VersionedAPISchema to filterapi.VersionedAPISchema.
 func schemaToFilterAPI(schema aigv1b1.VersionedAPISchema) filterapi.VersionedAPISchema {
 	ret := filterapi.VersionedAPISchema{}
@@ -342,9 +644,11 @@ func mergeHeaderMutations(routeLevel, backendLevel *aigv1b1.HTTPHeaderMutation)
 	return result
 }
 
-// reconcileFilterConfigSecret updates the filter config secret for the external processor.
+// reconcileFilterConfigSecret updates the filter config secret for the external processor of the given Gateway,
+// or the config served by the config server when it is enabled.
 func (c *GatewayController) reconcileFilterConfigSecret(
 	ctx context.Context,
+	gw *gwapiv1.Gateway,
 	configSecretName,
 	configSecretNamespace string,
 	aiGatewayRoutes []aigv1b1.AIGatewayRoute,
@@ -525,6 +829,19 @@ func (c *GatewayController) reconcileFilterConfigSecret(
 	var effectiveMCPRoute bool
 	ec.MCPConfig, effectiveMCPRoute = mcpConfig(mcpRoutes)
 	hasEffectiveRoute = hasEffectiveRoute || effectiveMCPRoute
+	c.resolveMCPStdioEnvFrom(ctx, mcpRoutes, ec.MCPConfig)
+	c.resolveMCPAIGatewayRouteBackends(gw, aiGatewayRoutes, mcpRoutes, ec.MCPConfig)
+	c.resolveMCPOpenAPIBackends(ctx, gw, mcpRoutes, ec.MCPConfig)
+	c.resolveMCPBackendCredentials(ctx, mcpRoutes, ec.MCPConfig)
+	ec.ToolExecutions = c.toolExecutions(aiGatewayRoutes, mcpRoutes, ec.MCPConfig)
+	ec.VirtualKeys = c.virtualKeys(ctx, aiGatewayRoutes)
+
+	if c.configServer != nil {
+		if err = c.configServer.UpdateConfig(ctx, gw.Namespace, gw.Name, ec); err != nil {
+			return false, fmt.Errorf("failed to update the config of the config server: %w", err)
+		}
+		return hasEffectiveRoute, nil
+	}
 
 	marshaled, err := yaml.Marshal(ec)
 	if err != nil {
@@ -594,6 +911,26 @@ func mcpConfig(mcpRoutes []aigv1b1.MCPRoute) (_ *filterapi.MCPConfig, hasEffecti
 				}
 				mcpBackend.ForwardHeaders = append(mcpBackend.ForwardHeaders, hf)
 			}
+			if b.Stdio != nil {
+				mcpBackend.Stdio = mcpStdioBackend(b.Stdio)
+			}
+			if b.Transport == aigv1b1.MCPBackendTransportSSE {
+				mcpBackend.Transport = filterapi.MCPBackendTransportSSE
+				mcpBackend.Path = ptr.Deref(b.Path, defaultMCPPath)
+			}
+			if b.SecurityPolicy != nil {
+				mcpBackend.Credential = mcpBackendCredential(b.SecurityPolicy)
+			}
+			if b.ServerRequests != nil {
+				mcpBackend.ServerRequests = mcpServerRequestPolicy(b.ServerRequests)
+			}
+			if b.AIGatewayRoute != nil {
+				mcpBackend.AIGatewayRoute = &filterapi.MCPAIGatewayRouteBackend{
+					ChatModels:     b.AIGatewayRoute.ChatModels,
+					EmbeddingModel: ptr.Deref(b.AIGatewayRoute.EmbeddingModel, ""),
+					ImageModel:     ptr.Deref(b.AIGatewayRoute.ImageModel, ""),
+				}
+			}
 			mcpRoute.Backends = append(
 				mcpRoute.Backends, mcpBackend)
 		}
@@ -663,11 +1000,171 @@ func mcpConfig(mcpRoutes []aigv1b1.MCPRoute) (_ *filterapi.MCPConfig, hasEffecti
 				mcpRoute.ForwardHeaders = append(mcpRoute.ForwardHeaders, ctoh.Header)
 			}
 		}
+		if route.Spec.RateLimit != nil {
+			for _, rule := range route.Spec.RateLimit.Rules {
+				mcpRule := filterapi.MCPRateLimitRule{
+					Backend:        ptr.Deref(rule.Backend, ""),
+					Tool:           ptr.Deref(rule.Tool, ""),
+					MaxConcurrency: ptr.Deref(rule.MaxConcurrency, 0),
+				}
+				if rule.ClientKey != nil {
+					mcpRule.ClientKey = &filterapi.MCPRateLimitClientKey{
+						Type:   filterapi.MCPRateLimitClientKeyType(rule.ClientKey.Type),
+						Header: ptr.Deref(rule.ClientKey.Header, ""),
+					}
+				}
+				if rule.Requests != nil {
+					mcpRule.Requests = &filterapi.MCPRateLimitValue{
+						Limit: rule.Requests.Limit,
+						Unit:  string(rule.Requests.Unit),
+					}
+				}
+				mcpRoute.RateLimits = append(mcpRoute.RateLimits, mcpRule)
+			}
+		}
+		if sse := route.Spec.LegacySSE; sse != nil {
+			mcpRoute.LegacySSE = &filterapi.MCPLegacySSE{
+				SSEPath:     cmp.Or(sse.SSEPath, defaultMCPLegacySSEPath),
+				MessagePath: cmp.Or(sse.MessagePath, defaultMCPLegacyMessagePath),
+			}
+		}
+		if v := route.Spec.ToolValidation; v != nil {
+			mcpRoute.ToolValidation = &filterapi.MCPToolValidation{
+				Arguments:         ptr.Deref(v.Arguments, true),
+				StructuredContent: ptr.Deref(v.StructuredContent, false),
+			}
+		}
+		if v := route.Spec.ToolSearch; v != nil {
+			mcpRoute.ToolSearch = &filterapi.MCPToolSearch{
+				MaxResults: int(ptr.Deref(v.MaxResults, defaultMCPToolSearchMaxResults)),
+			}
+		}
+		if hc := route.Spec.HealthCheck; hc != nil {
+			// The format of the durations is validated by the CRD, so the errors can be ignored.
+			interval, _ := time.ParseDuration(string(ptr.Deref(hc.Interval, defaultMCPHealthCheckInterval)))
+			timeout, _ := time.ParseDuration(string(ptr.Deref(hc.Timeout, defaultMCPHealthCheckTimeout)))
+			ejectionTime, _ := time.ParseDuration(string(ptr.Deref(hc.EjectionTime, defaultMCPHealthCheckEjectionTime)))
+			mcpRoute.HealthCheck = &filterapi.MCPHealthCheck{
+				Interval:           interval,
+				Timeout:            timeout,
+				UnhealthyThreshold: int(ptr.Deref(hc.UnhealthyThreshold, defaultMCPHealthCheckUnhealthyThreshold)),
+				EjectionTime:       ejectionTime,
+			}
+		}
 		mc.Routes = append(mc.Routes, mcpRoute)
 	}
 	return mc, hasEffectiveRoute
 }
 
+// toolExecutions returns the tool execution configurations of the given AIGatewayRoutes. The routes whose MCPRoute is
+// not part of the given MCP configuration, i.e. not attached to the same Gateway, are skipped.
+//
+// The routes whose MCPRoute authenticates its clients are skipped as well: the tool calls of the gateway are sent
+// directly to the MCP proxy, so the authentication enforced by Envoy on the MCPRoute would not apply to them, and the
+// MCP proxy would trust the unverified JWT claims of the chat completion requests.
+func (c *GatewayController) toolExecutions(aiGatewayRoutes []aigv1b1.AIGatewayRoute, mcpRoutes []aigv1b1.MCPRoute,
+	mc *filterapi.MCPConfig,
+) []filterapi.ToolExecution {
+	var executions []filterapi.ToolExecution
+	for i := range aiGatewayRoutes {
+		route := &aiGatewayRoutes[i]
+		te := route.Spec.ToolExecution
+		if te == nil || !route.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		mcpRouteName := fmt.Sprintf("%s/%s", route.Namespace, te.MCPRouteName)
+		var found bool
+		if mc != nil {
+			found = slices.ContainsFunc(mc.Routes, func(r filterapi.MCPRoute) bool { return r.Name == mcpRouteName })
+		}
+		if !found {
+			c.logger.Info("MCPRoute of the tool execution is not attached to the Gateway, skipping the tool execution",
+				"aigatewayroute", route.Name, "namespace", route.Namespace, "mcproute", te.MCPRouteName)
+			continue
+		}
+		if i := slices.IndexFunc(mcpRoutes, func(r aigv1b1.MCPRoute) bool {
+			return r.Namespace == route.Namespace && r.Name == string(te.MCPRouteName)
+		}); i >= 0 && mcpRouteAuthenticatesClients(&mcpRoutes[i]) {
+			c.logger.Info("MCPRoute of the tool execution authenticates its clients, which is not supported by the tool "+
+				"execution, skipping the tool execution",
+				"aigatewayroute", route.Name, "namespace", route.Namespace, "mcproute", te.MCPRouteName)
+			continue
+		}
+		timeout, _ := time.ParseDuration(string(ptr.Deref(te.Timeout, defaultToolExecutionTimeout)))
+		executions = append(executions, filterapi.ToolExecution{
+			RouteName:     fmt.Sprintf("%s/%s", route.Namespace, route.Name),
+			MCPRoute:      mcpRouteName,
+			MaxIterations: int(ptr.Deref(te.MaxIterations, defaultToolExecutionMaxIterations)),
+			Timeout:       timeout,
+		})
+	}
+	return executions
+}
+
+// mcpRouteAuthenticatesClients returns true if the clients of the given MCPRoute are authenticated by Envoy.
+func mcpRouteAuthenticatesClients(mcpRoute *aigv1b1.MCPRoute) bool {
+	sp := mcpRoute.Spec.SecurityPolicy
+	return sp != nil && (sp.OAuth != nil || sp.APIKeyAuth != nil || sp.ExtAuth != nil)
+}
+
+// virtualKeys returns the virtual keys targeting the given AIGatewayRoutes. The VirtualKeys whose key is not
+// generated yet are skipped.
+func (c *GatewayController) virtualKeys(ctx context.Context, aiGatewayRoutes []aigv1b1.AIGatewayRoute) []filterapi.VirtualKey {
+	var keys []filterapi.VirtualKey
+	index := make(map[string]int) // namespace/name of the VirtualKey -> index in keys.
+	for i := range aiGatewayRoutes {
+		route := &aiGatewayRoutes[i]
+		if !route.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		var virtualKeys aigv1a1.VirtualKeyList
+		if err := c.client.List(ctx, &virtualKeys, client.MatchingFields{
+			k8sClientIndexAIGatewayRouteToTargetingVirtualKey: fmt.Sprintf("%s.%s", route.Name, route.Namespace),
+		}); err != nil {
+			c.logger.Error(err, "failed to list VirtualKeys", "aigatewayroute", route.Name, "namespace", route.Namespace)
+			continue
+		}
+		routeName := fmt.Sprintf("%s/%s", route.Namespace, route.Name)
+		for j := range virtualKeys.Items {
+			vk := &virtualKeys.Items[j]
+			name := fmt.Sprintf("%s/%s", vk.Namespace, vk.Name)
+			if k, ok := index[name]; ok {
+				keys[k].Routes = append(keys[k].Routes, routeName)
+				continue
+			}
+			key, err := c.getSecretData(ctx, vk.Namespace, virtualKeySecretName(vk.Name), virtualKeyInSecret)
+			if err != nil {
+				c.logger.Info("key of the VirtualKey is not available, skipping the VirtualKey",
+					"virtualkey", vk.Name, "namespace", vk.Namespace, "error", err.Error())
+				continue
+			}
+			sum := sha256.Sum256([]byte(key))
+			fk := filterapi.VirtualKey{
+				Name:    name,
+				KeyHash: hex.EncodeToString(sum[:]),
+				Routes:  []string{routeName},
+				Models:  vk.Spec.AllowedModels,
+				Labels:  vk.Spec.Labels,
+			}
+			if vk.Spec.ExpiresAt != nil {
+				fk.ExpiresAt = vk.Spec.ExpiresAt.UTC()
+			}
+			if b := vk.Spec.Budget; b != nil {
+				window, err := virtualKeyBudgetWindow(b.Window)
+				if err != nil {
+					c.logger.Error(err, "invalid budget of the VirtualKey, skipping the VirtualKey",
+						"virtualkey", vk.Name, "namespace", vk.Namespace)
+					continue
+				}
+				fk.Budget = &filterapi.VirtualKeyBudget{Limit: uint64(b.Limit), Window: window, CEL: ptr.Deref(b.CostExpression, "")}
+			}
+			index[name] = len(keys)
+			keys = append(keys, fk)
+		}
+	}
+	return keys
+}
+
 func (c *GatewayController) bspToFilterAPIBackendAuth(ctx context.Context, backendSecurityPolicy *aigv1b1.BackendSecurityPolicy) (*filterapi.BackendAuth, error) {
 	namespace := backendSecurityPolicy.Namespace
 	switch backendSecurityPolicy.Spec.Type {
@@ -692,6 +1189,73 @@ func (c *GatewayController) bspToFilterAPIBackendAuth(ctx context.Context, backe
 			return nil, fmt.Errorf("failed to get secret %s: %w", secretName, err)
 		}
 		return &filterapi.BackendAuth{AnthropicAPIKey: &filterapi.AnthropicAPIKeyAuth{Key: apiKey}}, nil
+	case aigv1b1.BackendSecurityPolicyTypeRequestSigning:
+		signing := backendSecurityPolicy.Spec.RequestSigning
+		secretName := string(signing.SecretRef.Name)
+		key, err := c.getSecretData(ctx, namespace, secretName, signingKeyInSecret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %s: %w", secretName, err)
+		}
+		return &filterapi.BackendAuth{RequestSigning: &filterapi.RequestSigningAuth{
+			Scheme:        filterapi.RequestSigningScheme(signing.Scheme),
+			KeyID:         signing.KeyID,
+			Key:           key,
+			SignedHeaders: signing.SignedHeaders,
+		}}, nil
+	case aigv1b1.BackendSecurityPolicyTypeClientCertificate:
+		// The client certificate is presented by Envoy, which is configured by the extension server.
+		return nil, nil
+	case aigv1b1.BackendSecurityPolicyTypeAPIKeyPool:
+		pool := backendSecurityPolicy.Spec.APIKeyPool
+		auth := &filterapi.APIKeyPoolAuth{Strategy: filterapi.APIKeyPoolStrategy(pool.Strategy)}
+		if pool.Header != nil {
+			auth.Header = *pool.Header
+		}
+		if pool.EjectionDuration != nil {
+			d, err := time.ParseDuration(string(*pool.EjectionDuration))
+			if err != nil {
+				return nil, fmt.Errorf("invalid ejection duration %q: %w", *pool.EjectionDuration, err)
+			}
+			auth.EjectionDuration = d
+		}
+		for _, key := range pool.Keys {
+			secretName := string(key.SecretRef.Name)
+			apiKey, err := c.getSecretData(ctx, namespace, secretName, apiKeyInSecret)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get secret %s: %w", secretName, err)
+			}
+			weight := 1
+			if key.Weight != nil {
+				weight = int(*key.Weight)
+			}
+			auth.Keys = append(auth.Keys, filterapi.APIKeyPoolKey{Key: apiKey, Weight: weight})
+		}
+		return &filterapi.BackendAuth{APIKeyPool: auth}, nil
+	case aigv1b1.BackendSecurityPolicyTypeVault:
+		if c.vaultCredentials == nil {
+			return nil, errVaultRequiresConfigServer
+		}
+		vault := backendSecurityPolicy.Spec.Vault
+		data, ok := c.vaultCredentials.Get(namespace, backendSecurityPolicy.Name)
+		if !ok {
+			return nil, fmt.Errorf("the credentials of backend security policy %s/%s have not been read from vault yet",
+				namespace, backendSecurityPolicy.Name)
+		}
+		switch vault.Credential.Type {
+		case aigv1b1.VaultCredentialTypeAPIKey:
+			return &filterapi.BackendAuth{APIKey: &filterapi.APIKeyAuth{Key: data[rotators.VaultCredentialKey(&vault.Credential)]}}, nil
+		case aigv1b1.VaultCredentialTypeAzureAPIKey:
+			return &filterapi.BackendAuth{AzureAPIKey: &filterapi.AzureAPIKeyAuth{Key: data[rotators.VaultCredentialKey(&vault.Credential)]}}, nil
+		case aigv1b1.VaultCredentialTypeAnthropicAPIKey:
+			return &filterapi.BackendAuth{AnthropicAPIKey: &filterapi.AnthropicAPIKeyAuth{Key: data[rotators.VaultCredentialKey(&vault.Credential)]}}, nil
+		case aigv1b1.VaultCredentialTypeAWSCredentials:
+			region := ptr.Deref(vault.Credential.Region, "")
+			return &filterapi.BackendAuth{AWSAuth: &filterapi.AWSAuth{
+				CredentialFileLiteral: rotators.FormatVaultAWSCredentials(data, region),
+				Region:                region,
+			}}, nil
+		}
+		return nil, fmt.Errorf("invalid vault credential type %s for policy %s", vault.Credential.Type, backendSecurityPolicy.Name)
 	case aigv1b1.BackendSecurityPolicyTypeAWSCredentials:
 		awsCred := backendSecurityPolicy.Spec.AWSCredentials
 
@@ -723,6 +1287,25 @@ func (c *GatewayController) bspToFilterAPIBackendAuth(ctx context.Context, backe
 			},
 		}, nil
 	case aigv1b1.BackendSecurityPolicyTypeAzureCredentials:
+		azureCreds := backendSecurityPolicy.Spec.AzureCredentials
+		// The managed identity and the workload identity tokens are acquired by the extproc itself.
+		if azureCreds.ManagedIdentity != nil {
+			return &filterapi.BackendAuth{AzureAuth: &filterapi.AzureAuth{Identity: &filterapi.AzureIdentity{
+				Type:     filterapi.AzureIdentityTypeManagedIdentity,
+				ClientID: azureCreds.ClientID,
+				Scope:    azureScopeURL,
+			}}}, nil
+		}
+		if wi := azureCreds.WorkloadIdentity; wi != nil {
+			return &filterapi.BackendAuth{AzureAuth: &filterapi.AzureAuth{Identity: &filterapi.AzureIdentity{
+				Type:          filterapi.AzureIdentityTypeWorkloadIdentity,
+				ClientID:      azureCreds.ClientID,
+				TenantID:      azureCreds.TenantID,
+				TokenFilePath: ptr.Deref(wi.TokenFilePath, ""),
+				Scope:         azureScopeURL,
+			}}}, nil
+		}
+
 		secretName := rotators.GetBSPSecretName(backendSecurityPolicy.Name)
 		azureAccessToken, err := c.getSecretData(ctx, namespace, secretName, rotators.AzureAccessTokenKey)
 		if err != nil {
@@ -763,6 +1346,404 @@ func (c *GatewayController) bspToFilterAPIBackendAuth(ctx context.Context, backe
 	}
 }
 
+// mcpStdioBackend converts the given stdio MCP server to the filter API configuration.
+// The environment variables from the EnvFrom Secret are resolved separately by resolveMCPStdioEnvFrom.
+func mcpStdioBackend(stdio *aigv1b1.MCPStdioServer) *filterapi.MCPStdioBackend {
+	backend := &filterapi.MCPStdioBackend{
+		Command:      stdio.Command,
+		Args:         stdio.Args,
+		PerSession:   stdio.Isolation == aigv1b1.MCPStdioIsolationPerSession,
+		MaxProcesses: int(ptr.Deref(stdio.MaxProcesses, 0)),
+	}
+	for _, env := range stdio.Env {
+		if backend.Env == nil {
+			backend.Env = make(map[string]string, len(stdio.Env))
+		}
+		backend.Env[env.Name] = env.Value
+	}
+	if stdio.SessionIdleTimeout != nil {
+		// The format is validated by the CRD, so the error can be ignored.
+		backend.SessionIdleTimeout, _ = time.ParseDuration(string(*stdio.SessionIdleTimeout))
+	}
+	if r := stdio.Resources; r != nil {
+		if r.Memory != nil {
+			backend.MemoryLimitBytes = uint64(r.Memory.Value()) // nolint: gosec
+		}
+		if r.CPUTime != nil {
+			backend.CPUTimeLimit, _ = time.ParseDuration(string(*r.CPUTime))
+		}
+	}
+	return backend
+}
+
+// resolveMCPStdioEnvFrom reads the Secrets referenced by the EnvFrom field of the stdio MCP servers and adds their
+// key-value pairs to the environment of the corresponding backends. Variables defined explicitly take precedence.
+//
+// If a Secret cannot be read, or it is in another namespace than the MCPRoute, the backend is removed from the
+// configuration since the server cannot run without its environment. All the stdio backends are removed when the
+// stdio MCP servers are disabled.
+func (c *GatewayController) resolveMCPStdioEnvFrom(ctx context.Context, mcpRoutes []aigv1b1.MCPRoute, mc *filterapi.MCPConfig) {
+	if mc == nil {
+		return
+	}
+	for i := range mcpRoutes {
+		route := &mcpRoutes[i]
+		routeName := filterapi.MCPRouteName(fmt.Sprintf("%s/%s", route.Namespace, route.Name))
+		idx := slices.IndexFunc(mc.Routes, func(r filterapi.MCPRoute) bool { return r.Name == routeName })
+		if idx < 0 {
+			continue
+		}
+		mcpRoute := &mc.Routes[idx]
+		for _, ref := range route.Spec.BackendRefs {
+			if ref.Stdio == nil {
+				continue
+			}
+			backendIdx := slices.IndexFunc(mcpRoute.Backends, func(b filterapi.MCPBackend) bool {
+				return b.Name == filterapi.MCPBackendName(ref.Name)
+			})
+			if backendIdx < 0 {
+				continue
+			}
+			if !c.enableMCPStdioServers {
+				c.logger.Error(nil, "stdio MCP servers are disabled in the controller. Skipping this backend.",
+					"backend_name", ref.Name, "mcproute", route.Name, "namespace", route.Namespace)
+				mcpRoute.Backends = slices.Delete(mcpRoute.Backends, backendIdx, backendIdx+1)
+				continue
+			}
+			if ref.Stdio.EnvFrom == nil {
+				continue
+			}
+			// The environment may hold credentials, so only the Secrets of the namespace of the MCPRoute can be read.
+			if namespace := secretNamespace(ref.Stdio.EnvFrom, route.Namespace); namespace != route.Namespace {
+				c.logger.Error(nil, "the environment Secret of the stdio MCP server must be in the same namespace as the MCPRoute. Skipping this backend.",
+					"backend_name", ref.Name, "mcproute", route.Name, "namespace", route.Namespace, "secret_namespace", namespace)
+				mcpRoute.Backends = slices.Delete(mcpRoute.Backends, backendIdx, backendIdx+1)
+				continue
+			}
+			secret, err := c.kube.CoreV1().Secrets(route.Namespace).Get(ctx, string(ref.Stdio.EnvFrom.Name), metav1.GetOptions{})
+			if err != nil {
+				c.logger.Error(err, "failed to get the environment Secret of the stdio MCP server. Skipping this backend.",
+					"backend_name", ref.Name, "mcproute", route.Name, "namespace", route.Namespace)
+				mcpRoute.Backends = slices.Delete(mcpRoute.Backends, backendIdx, backendIdx+1)
+				continue
+			}
+			stdio := mcpRoute.Backends[backendIdx].Stdio
+			if stdio.Env == nil {
+				stdio.Env = make(map[string]string, len(secret.Data))
+			}
+			for k, v := range secret.Data {
+				if _, ok := stdio.Env[k]; !ok {
+					stdio.Env[k] = string(v)
+				}
+			}
+		}
+	}
+}
+
+// resolveMCPAIGatewayRouteBackends resolves the listener of the given Gateway serving the AIGatewayRoutes exposed as
+// MCP tools, and their chat models when not specified. The backends whose AIGatewayRoute is not attached to an HTTP
+// or HTTPS listener of the Gateway are removed from the configuration.
+func (c *GatewayController) resolveMCPAIGatewayRouteBackends(gw *gwapiv1.Gateway, aiGatewayRoutes []aigv1b1.AIGatewayRoute,
+	mcpRoutes []aigv1b1.MCPRoute, mc *filterapi.MCPConfig,
+) {
+	if mc == nil {
+		return
+	}
+	for i := range mcpRoutes {
+		route := &mcpRoutes[i]
+		routeName := filterapi.MCPRouteName(fmt.Sprintf("%s/%s", route.Namespace, route.Name))
+		idx := slices.IndexFunc(mc.Routes, func(r filterapi.MCPRoute) bool { return r.Name == routeName })
+		if idx < 0 {
+			continue
+		}
+		mcpRoute := &mc.Routes[idx]
+		for _, ref := range route.Spec.BackendRefs {
+			if ref.AIGatewayRoute == nil {
+				continue
+			}
+			backendIdx := slices.IndexFunc(mcpRoute.Backends, func(b filterapi.MCPBackend) bool {
+				return b.Name == filterapi.MCPBackendName(ref.Name)
+			})
+			if backendIdx < 0 {
+				continue
+			}
+			aiGatewayRouteIdx := slices.IndexFunc(aiGatewayRoutes, func(r aigv1b1.AIGatewayRoute) bool {
+				return r.Namespace == route.Namespace && r.Name == string(ref.AIGatewayRoute.Name) && r.GetDeletionTimestamp().IsZero()
+			})
+			var url, host string
+			if aiGatewayRouteIdx >= 0 {
+				url, host = aiGatewayRouteListener(gw, &aiGatewayRoutes[aiGatewayRouteIdx])
+			}
+			if url == "" {
+				c.logger.Error(nil, "the AIGatewayRoute is not attached to an HTTP or HTTPS listener of the Gateway. Skipping this backend.",
+					"backend_name", ref.Name, "aigatewayroute", ref.AIGatewayRoute.Name, "mcproute", route.Name, "namespace", route.Namespace)
+				mcpRoute.Backends = slices.Delete(mcpRoute.Backends, backendIdx, backendIdx+1)
+				continue
+			}
+			backend := mcpRoute.Backends[backendIdx].AIGatewayRoute
+			backend.URL, backend.Host = url, host
+			if len(backend.ChatModels) == 0 {
+				for _, model := range aiGatewayRouteModels(&aiGatewayRoutes[aiGatewayRouteIdx]) {
+					if model != backend.EmbeddingModel && model != backend.ImageModel && !slices.Contains(backend.ChatModels, model) {
+						backend.ChatModels = append(backend.ChatModels, model)
+					}
+				}
+			}
+		}
+	}
+}
+
+// aiGatewayRouteListener returns the URL of the Envoy listener of the given Gateway the given AIGatewayRoute is
+// attached to, and the host of the requests sent to the route through it. It returns an empty URL if the route is not
+// attached to an HTTP or HTTPS listener of the Gateway.
+//
+// The listeners on the well-known ports are shifted by 10000 in the Envoy container by Envoy Gateway, since the
+// container cannot bind them.
+func aiGatewayRouteListener(gw *gwapiv1.Gateway, route *aigv1b1.AIGatewayRoute) (url, host string) {
+	for _, parentRef := range route.Spec.ParentRefs {
+		if string(parentRef.Name) != gw.Name || string(ptr.Deref(parentRef.Namespace, gwapiv1.Namespace(route.Namespace))) != gw.Namespace {
+			continue
+		}
+		for _, l := range gw.Spec.Listeners {
+			if l.Protocol != gwapiv1.HTTPProtocolType && l.Protocol != gwapiv1.HTTPSProtocolType {
+				continue
+			}
+			if (parentRef.SectionName != nil && *parentRef.SectionName != l.Name) || (parentRef.Port != nil && *parentRef.Port != l.Port) {
+				continue
+			}
+			port := l.Port
+			if port < 1024 {
+				port += 10000
+			}
+			scheme := "http"
+			if l.Protocol == gwapiv1.HTTPSProtocolType {
+				scheme = "https"
+			}
+			switch {
+			case len(route.Spec.Hostnames) > 0:
+				host = string(route.Spec.Hostnames[0])
+			case l.Hostname != nil:
+				host = string(*l.Hostname)
+			}
+			// Any host matches a wildcard hostname.
+			if rest, ok := strings.CutPrefix(host, "*."); ok {
+				host = "mcp." + rest
+			}
+			return fmt.Sprintf("%s://127.0.0.1:%d", scheme, port), host
+		}
+	}
+	return "", ""
+}
+
+// aiGatewayRouteModels returns the models declared by the rules of the given AIGatewayRoute with an exact match on
+// the model name header.
+func aiGatewayRouteModels(route *aigv1b1.AIGatewayRoute) []string {
+	var models []string
+	for i := range route.Spec.Rules {
+		for _, m := range route.Spec.Rules[i].Matches {
+			for _, h := range m.Headers {
+				if (h.Type != nil && *h.Type != gwapiv1.HeaderMatchExact) || string(h.Name) != internalapi.ModelNameHeaderKeyDefault {
+					continue
+				}
+				models = append(models, h.Value)
+			}
+		}
+	}
+	return models
+}
+
+// resolveMCPOpenAPIBackends loads the OpenAPI documents of the REST API backends exposed as MCP tools, from their
+// ConfigMap or URL, and converts their operations to the filter API configuration. The documents referenced by URL
+// are fetched in the background, see openAPIDocumentCache.
+//
+// If a document cannot be loaded or parsed, or is not fetched yet, the backend is removed from the configuration
+// since it has no tools.
+func (c *GatewayController) resolveMCPOpenAPIBackends(ctx context.Context, gw *gwapiv1.Gateway, mcpRoutes []aigv1b1.MCPRoute, mc *filterapi.MCPConfig) {
+	var gwKey types.NamespacedName
+	if gw != nil {
+		gwKey = client.ObjectKeyFromObject(gw)
+	}
+	var urls []string
+	defer func() { c.openAPIDocuments.retain(gwKey, urls) }()
+	if mc == nil {
+		return
+	}
+	for i := range mcpRoutes {
+		route := &mcpRoutes[i]
+		routeName := filterapi.MCPRouteName(fmt.Sprintf("%s/%s", route.Namespace, route.Name))
+		idx := slices.IndexFunc(mc.Routes, func(r filterapi.MCPRoute) bool { return r.Name == routeName })
+		if idx < 0 {
+			continue
+		}
+		mcpRoute := &mc.Routes[idx]
+		for _, ref := range route.Spec.BackendRefs {
+			if ref.OpenAPI == nil {
+				continue
+			}
+			backendIdx := slices.IndexFunc(mcpRoute.Backends, func(b filterapi.MCPBackend) bool {
+				return b.Name == filterapi.MCPBackendName(ref.Name)
+			})
+			if backendIdx < 0 {
+				continue
+			}
+			if ref.OpenAPI.URL != nil {
+				urls = append(urls, *ref.OpenAPI.URL)
+			}
+			doc, err := c.openAPIDocument(ctx, gwKey, route.Namespace, ref.OpenAPI)
+			if err == nil {
+				mcpRoute.Backends[backendIdx].OpenAPI, err = mcpopenapi.Parse(doc)
+			}
+			if err != nil {
+				c.logger.Error(err, "failed to load the OpenAPI document of the backend. Skipping this backend.",
+					"backend_name", ref.Name, "mcproute", route.Name, "namespace", route.Namespace)
+				mcpRoute.Backends = slices.Delete(mcpRoute.Backends, backendIdx, backendIdx+1)
+			}
+		}
+	}
+}
+
+// openAPIDocument returns the OpenAPI document of the given backend used by the given Gateway, read from its
+// ConfigMap in the given namespace or from the cache of the documents fetched from their URL.
+func (c *GatewayController) openAPIDocument(ctx context.Context, gateway types.NamespacedName, namespace string, b *aigv1b1.MCPOpenAPIBackend) ([]byte, error) {
+	if b.ConfigMapRef != nil {
+		cm, err := c.kube.CoreV1().ConfigMaps(namespace).Get(ctx, string(b.ConfigMapRef.Name), metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the ConfigMap %s: %w", b.ConfigMapRef.Name, err)
+		}
+		key := cmp.Or(b.ConfigMapRef.Key, "openapi.yaml")
+		if doc, ok := cm.Data[key]; ok {
+			return []byte(doc), nil
+		}
+		if doc, ok := cm.BinaryData[key]; ok {
+			return doc, nil
+		}
+		return nil, fmt.Errorf("the ConfigMap %s has no %s key", b.ConfigMapRef.Name, key)
+	}
+
+	return c.openAPIDocuments.get(gateway, ptr.Deref(b.URL, ""))
+}
+
+// enqueueGateways reconciles the given Gateways again.
+func (c *GatewayController) enqueueGateways(gateways []types.NamespacedName) {
+	if c.gatewayEventChan == nil {
+		return
+	}
+	for _, gw := range gateways {
+		c.gatewayEventChan <- event.GenericEvent{Object: &gwapiv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: gw.Namespace, Name: gw.Name},
+		}}
+	}
+}
+
+// mcpBackendCredential converts the per-user credential of the given backend security policy to the filter API
+// configuration, or returns nil if the policy has none. The secrets are resolved separately by
+// resolveMCPBackendCredentials.
+func mcpBackendCredential(policy *aigv1b1.MCPBackendSecurityPolicy) *filterapi.MCPBackendCredential {
+	switch {
+	case policy.TokenExchange != nil:
+		te := policy.TokenExchange
+		return &filterapi.MCPBackendCredential{
+			Header: ptr.Deref(te.Header, "Authorization"),
+			TokenExchange: &filterapi.MCPTokenExchange{
+				TokenEndpoint:      te.TokenEndpoint,
+				ClientID:           te.ClientID,
+				Audience:           ptr.Deref(te.Audience, ""),
+				Resource:           ptr.Deref(te.Resource, ""),
+				Scopes:             te.Scopes,
+				RequestedTokenType: ptr.Deref(te.RequestedTokenType, ""),
+			},
+		}
+	case policy.UserCredentials != nil:
+		return &filterapi.MCPBackendCredential{Header: ptr.Deref(policy.UserCredentials.Header, "Authorization")}
+	default:
+		return nil
+	}
+}
+
+// mcpServerRequestPolicy translates the policy of the server->client requests of an MCP backend.
+func mcpServerRequestPolicy(policy *aigv1b1.MCPBackendServerRequests) *filterapi.MCPServerRequestPolicy {
+	ret := &filterapi.MCPServerRequestPolicy{}
+	if s := policy.Sampling; s != nil {
+		ret.Sampling = &filterapi.MCPSamplingPolicy{
+			Action:         filterapi.MCPServerRequestAction(cmp.Or(s.Action, aigv1b1.MCPServerRequestActionAllow)),
+			MaxTokens:      int64(ptr.Deref(s.MaxTokens, 0)),
+			IncludeContext: ptr.Deref(s.IncludeContext, ""),
+		}
+	}
+	if e := policy.Elicitation; e != nil {
+		ret.Elicitation = &filterapi.MCPElicitationPolicy{
+			Action:       filterapi.MCPServerRequestAction(cmp.Or(e.Action, aigv1b1.MCPServerRequestActionAllow)),
+			DeniedFields: e.DeniedFields,
+		}
+		if ret.Elicitation.DeniedFields == nil {
+			ret.Elicitation.DeniedFields = defaultMCPElicitationDeniedFields
+		}
+	}
+	return ret
+}
+
+// resolveMCPBackendCredentials reads the Secrets referenced by the per-user credentials of the MCP backends: the
+// client secret of the token exchange, and the credentials of the users.
+//
+// If a Secret cannot be read, the backend is removed from the configuration so that its requests are never sent
+// without the credential of the user.
+func (c *GatewayController) resolveMCPBackendCredentials(ctx context.Context, mcpRoutes []aigv1b1.MCPRoute, mc *filterapi.MCPConfig) {
+	if mc == nil {
+		return
+	}
+	for i := range mcpRoutes {
+		route := &mcpRoutes[i]
+		routeName := filterapi.MCPRouteName(fmt.Sprintf("%s/%s", route.Namespace, route.Name))
+		idx := slices.IndexFunc(mc.Routes, func(r filterapi.MCPRoute) bool { return r.Name == routeName })
+		if idx < 0 {
+			continue
+		}
+		mcpRoute := &mc.Routes[idx]
+		for _, ref := range route.Spec.BackendRefs {
+			if ref.SecurityPolicy == nil {
+				continue
+			}
+			backendIdx := slices.IndexFunc(mcpRoute.Backends, func(b filterapi.MCPBackend) bool {
+				return b.Name == filterapi.MCPBackendName(ref.Name)
+			})
+			if backendIdx < 0 {
+				continue
+			}
+			credential := mcpRoute.Backends[backendIdx].Credential
+			var err error
+			switch {
+			case ref.SecurityPolicy.TokenExchange != nil && ref.SecurityPolicy.TokenExchange.ClientSecretRef != nil:
+				secretRef := ref.SecurityPolicy.TokenExchange.ClientSecretRef
+				credential.TokenExchange.ClientSecret, err = c.getSecretData(ctx,
+					secretNamespace(secretRef, route.Namespace), string(secretRef.Name), "client-secret")
+			case ref.SecurityPolicy.UserCredentials != nil:
+				secretRef := &ref.SecurityPolicy.UserCredentials.SecretRef
+				var secret *corev1.Secret
+				secret, err = c.kube.CoreV1().Secrets(secretNamespace(secretRef, route.Namespace)).Get(ctx, string(secretRef.Name), metav1.GetOptions{})
+				if err == nil {
+					credential.UserCredentials = make(map[string]string, len(secret.Data))
+					for subject, v := range secret.Data {
+						credential.UserCredentials[subject] = string(v)
+					}
+				}
+			}
+			if err != nil {
+				c.logger.Error(err, "failed to get the credential Secret of the MCP backend. Skipping this backend.",
+					"backend_name", ref.Name, "mcproute", route.Name, "namespace", route.Namespace)
+				mcpRoute.Backends = slices.Delete(mcpRoute.Backends, backendIdx, backendIdx+1)
+			}
+		}
+	}
+}
+
+// secretNamespace returns the namespace of the given Secret reference, defaulting to the given namespace.
+func secretNamespace(ref *gwapiv1.SecretObjectReference, defaultNamespace string) string {
+	if ref.Namespace != nil && *ref.Namespace != "" {
+		return string(*ref.Namespace)
+	}
+	return defaultNamespace
+}
+
 func (c *GatewayController) getSecretData(ctx context.Context, namespace, name, dataKey string) (string, error) {
 	secret, err := c.kube.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
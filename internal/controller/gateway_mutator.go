@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -26,6 +27,7 @@ import (
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	aigv1b1 "github.com/envoyproxy/ai-gateway/api/v1beta1"
+	"github.com/envoyproxy/ai-gateway/internal/configserver"
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 )
 
@@ -62,6 +64,17 @@ type gatewayMutator struct {
 	mcpFallbackSessionEncryptionSeed string
 	// mcpFallbackSessionEncryptionIterations is the number of iterations used in the fallback PBKDF2 key derivation for MCP session encryption.
 	mcpFallbackSessionEncryptionIterations int
+	// mcpSessionStore is the optional store of the MCP session state shared by the replicas of the MCP proxy.
+	mcpSessionStore string
+	// mcpAuditLog is the optional destination of the audit log of the MCP requests.
+	mcpAuditLog string
+	// mcpAuditArguments specifies how the arguments of the audited MCP requests are recorded.
+	mcpAuditArguments string
+	// configServerAddr is the address of the config server streaming the filter config to the extproc. When empty,
+	// the filter config is read from the mounted filter config Secret.
+	configServerAddr string
+	// configServerCACert is the PEM encoded CA certificate that the extproc verifies the config server with.
+	configServerCACert string
 
 	// Whether to run the extProc container as a sidecar (true) as a normal container (false).
 	// This is essentially a workaround for old k8s versions, and we can remove this in the future.
@@ -73,6 +86,8 @@ func newGatewayMutator(c client.Client, noCacheReader client.Reader, kube kubern
 	udsPath string, requestHeaderAttributes, spanRequestHeaderAttributes, metricsRequestHeaderAttributes, logRequestHeaderAttributes *string, rootPrefix, endpointPrefixes, extProcExtraEnvVars, extProcImagePullSecrets string, extProcMaxRecvMsgSize int,
 	extProcAsSideCar bool,
 	mcpSessionEncryptionSeed string, mcpSessionEncryptionIterations int, mcpFallbackSessionEncryptionSeed string, mcpFallbackSessionEncryptionIterations int,
+	mcpSessionStore, mcpAuditLog, mcpAuditArguments string,
+	configServerAddr, configServerCACert string,
 ) *gatewayMutator {
 	var parsedEnvVars []corev1.EnvVar
 	if extProcExtraEnvVars != "" {
@@ -118,6 +133,11 @@ func newGatewayMutator(c client.Client, noCacheReader client.Reader, kube kubern
 		mcpSessionEncryptionIterations:         mcpSessionEncryptionIterations,
 		mcpFallbackSessionEncryptionSeed:       mcpFallbackSessionEncryptionSeed,
 		mcpFallbackSessionEncryptionIterations: mcpFallbackSessionEncryptionIterations,
+		mcpSessionStore:                        mcpSessionStore,
+		mcpAuditLog:                            mcpAuditLog,
+		mcpAuditArguments:                      mcpAuditArguments,
+		configServerAddr:                       configServerAddr,
+		configServerCACert:                     configServerCACert,
 	}
 }
 
@@ -141,15 +161,16 @@ func (g *gatewayMutator) Default(ctx context.Context, obj runtime.Object) error
 }
 
 // buildExtProcArgs builds all command line arguments for the extproc container.
-func (g *gatewayMutator) buildExtProcArgs(filterConfigFullPath string, extProcAdminPort int, needMCP bool) []string {
-	args := []string{
-		"-configPath", filterConfigFullPath,
+//
+// configArgs are the arguments specifying where the extproc gets the filter config from.
+func (g *gatewayMutator) buildExtProcArgs(configArgs []string, extProcAdminPort int, needMCP bool) []string {
+	args := append(slices.Clone(configArgs),
 		"-logLevel", g.extProcLogLevel,
-		"-extProcAddr", "unix://" + g.udsPath,
+		"-extProcAddr", "unix://"+g.udsPath,
 		"-adminPort", fmt.Sprintf("%d", extProcAdminPort),
 		"-rootPrefix", g.rootPrefix,
 		"-maxRecvMsgSize", fmt.Sprintf("%d", g.extProcMaxRecvMsgSize),
-	}
+	)
 	if needMCP {
 		args = append(args,
 			"-mcpAddr", ":"+strconv.Itoa(internalapi.MCPProxyPort),
@@ -162,6 +183,15 @@ func (g *gatewayMutator) buildExtProcArgs(filterConfigFullPath string, extProcAd
 				"-mcpFallbackSessionEncryptionIterations", strconv.Itoa(g.mcpFallbackSessionEncryptionIterations),
 			)
 		}
+		if g.mcpSessionStore != "" {
+			args = append(args, "-mcpSessionStore", g.mcpSessionStore)
+		}
+		if g.mcpAuditLog != "" {
+			args = append(args, "-mcpAuditLog", g.mcpAuditLog)
+			if g.mcpAuditArguments != "" {
+				args = append(args, "-mcpAuditArguments", g.mcpAuditArguments)
+			}
+		}
 	}
 
 	if g.requestHeaderAttributes != nil {
@@ -357,14 +387,18 @@ func (g *gatewayMutator) mutatePod(ctx context.Context, pod *corev1.Pod, gateway
 	// Check if the config secret is already created. If not, let's skip the mutation for this pod to avoid blocking the Envoy pod creation.
 	// The config secret will be eventually created by the controller, and that will trigger the mutation for new pods since the Gateway controller
 	// will update the pod annotation in the deployment/daemonset template once it creates the config secret.
-	_, err = g.kube.CoreV1().Secrets(pod.Namespace).Get(ctx,
-		FilterConfigSecretPerGatewayName(gatewayName, gatewayNamespace), metav1.GetOptions{})
-	if err != nil && apierrors.IsNotFound(err) {
-		g.logger.Info("filter config secret not found, skipping mutation",
-			"gateway_name", gatewayName, "gateway_namespace", gatewayNamespace)
-		return nil
-	} else if err != nil {
-		return fmt.Errorf("failed to get filter config secret: %w", err)
+	//
+	// This is not needed when the filter config is streamed by the config server, as the extproc waits for it.
+	if g.configServerAddr == "" {
+		_, err = g.kube.CoreV1().Secrets(pod.Namespace).Get(ctx,
+			FilterConfigSecretPerGatewayName(gatewayName, gatewayNamespace), metav1.GetOptions{})
+		if err != nil && apierrors.IsNotFound(err) {
+			g.logger.Info("filter config secret not found, skipping mutation",
+				"gateway_name", gatewayName, "gateway_namespace", gatewayNamespace)
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to get filter config secret: %w", err)
+		}
 	}
 
 	gatewayConfig, err := g.fetchGatewayConfig(ctx, gatewayName, gatewayNamespace)
@@ -385,21 +419,41 @@ func (g *gatewayMutator) mutatePod(ctx context.Context, pod *corev1.Pod, gateway
 	// Now we construct the AI Gateway managed containers and volumes.
 	filterConfigSecretName := FilterConfigSecretPerGatewayName(gatewayName, gatewayNamespace)
 	filterConfigVolumeName := mutationNamePrefix + filterConfigSecretName
-	const extProcUDSVolumeName = mutationNamePrefix + "extproc-uds"
-	podspec.Volumes = append(podspec.Volumes,
-		corev1.Volume{
+	const (
+		extProcUDSVolumeName        = mutationNamePrefix + "extproc-uds"
+		configServerTokenVolumeName = mutationNamePrefix + "config-server-token"
+	)
+	if g.configServerAddr == "" {
+		podspec.Volumes = append(podspec.Volumes, corev1.Volume{
 			Name: filterConfigVolumeName,
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{SecretName: filterConfigSecretName},
 			},
-		},
-		corev1.Volume{
-			Name: extProcUDSVolumeName,
+		})
+	} else {
+		// The extproc authenticates to the config server with a token of the pod's ServiceAccount bound to the pod,
+		// so that it is only served the config of the Gateway owning the pod.
+		podspec.Volumes = append(podspec.Volumes, corev1.Volume{
+			Name: configServerTokenVolumeName,
 			VolumeSource: corev1.VolumeSource{
-				EmptyDir: &corev1.EmptyDirVolumeSource{},
+				Projected: &corev1.ProjectedVolumeSource{
+					Sources: []corev1.VolumeProjection{{
+						ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+							Audience:          configserver.TokenAudience,
+							ExpirationSeconds: ptr.To(int64(3600)),
+							Path:              filepath.Base(configserver.TokenPath),
+						},
+					}},
+				},
 			},
+		})
+	}
+	podspec.Volumes = append(podspec.Volumes, corev1.Volume{
+		Name: extProcUDSVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
 		},
-	)
+	})
 
 	// Add imagePullSecrets for extProc if configured
 	if len(g.extProcImagePullSecrets) > 0 {
@@ -440,6 +494,14 @@ func (g *gatewayMutator) mutatePod(ctx context.Context, pod *corev1.Pod, gateway
 	if kubernetesExtProc != nil && kubernetesExtProc.SecurityContext != nil {
 		securityContext = kubernetesExtProc.SecurityContext
 	}
+	configArgs := []string{"-configPath", filterConfigFullPath}
+	if g.configServerAddr != "" {
+		configArgs = []string{
+			"-configServerAddr", g.configServerAddr,
+			"-configServerNodeID", configserver.NodeID(gatewayNamespace, gatewayName),
+			"-configServerCACert", g.configServerCACert,
+		}
+	}
 
 	container := corev1.Container{
 		Name:            extProcContainerName,
@@ -448,7 +510,7 @@ func (g *gatewayMutator) mutatePod(ctx context.Context, pod *corev1.Pod, gateway
 		Ports: []corev1.ContainerPort{
 			{Name: "aigw-admin", ContainerPort: extProcAdminPort},
 		},
-		Args: g.buildExtProcArgs(filterConfigFullPath, extProcAdminPort, len(mcpRoutes.Items) > 0),
+		Args: g.buildExtProcArgs(configArgs, extProcAdminPort, len(mcpRoutes.Items) > 0),
 		Env:  envVars,
 		VolumeMounts: []corev1.VolumeMount{
 			{
@@ -456,11 +518,6 @@ func (g *gatewayMutator) mutatePod(ctx context.Context, pod *corev1.Pod, gateway
 				MountPath: udsMountPath,
 				ReadOnly:  false,
 			},
-			{
-				Name:      filterConfigVolumeName,
-				MountPath: filterConfigMountPath,
-				ReadOnly:  true,
-			},
 		},
 		SecurityContext: securityContext,
 		ReadinessProbe: &corev1.Probe{
@@ -480,9 +537,23 @@ func (g *gatewayMutator) mutatePod(ctx context.Context, pod *corev1.Pod, gateway
 		Resources: resources,
 	}
 
+	if g.configServerAddr == "" {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      filterConfigVolumeName,
+			MountPath: filterConfigMountPath,
+			ReadOnly:  true,
+		})
+	} else {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      configServerTokenVolumeName,
+			MountPath: configserver.TokenDir,
+			ReadOnly:  true,
+		})
+	}
 	if kubernetesExtProc != nil && len(kubernetesExtProc.VolumeMounts) > 0 {
 		container.VolumeMounts = append(container.VolumeMounts, kubernetesExtProc.VolumeMounts...)
 	}
+	inheritWorkloadIdentity(&container, podspec.Containers)
 
 	if g.extProcAsSideCar {
 		// When running as a sidecar, we want to ensure the extProc container is shutdown last after Envoy is shutdown.
@@ -506,6 +577,54 @@ func (g *gatewayMutator) mutatePod(ctx context.Context, pod *corev1.Pod, gateway
 	return nil
 }
 
+// workloadIdentityEnvVars are the environment variables of the cloud provider credential chains that the EKS and
+// AKS webhooks set for IRSA, EKS Pod Identity and AKS Workload Identity.
+var workloadIdentityEnvVars = []string{
+	"AWS_ROLE_ARN",
+	"AWS_WEB_IDENTITY_TOKEN_FILE",
+	"AWS_ROLE_SESSION_NAME",
+	"AWS_STS_REGIONAL_ENDPOINTS",
+	"AWS_REGION",
+	"AWS_DEFAULT_REGION",
+	"AWS_CONTAINER_CREDENTIALS_FULL_URI",
+	"AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE",
+	"AZURE_CLIENT_ID",
+	"AZURE_TENANT_ID",
+	"AZURE_FEDERATED_TOKEN_FILE",
+	"AZURE_AUTHORITY_HOST",
+}
+
+// inheritWorkloadIdentity copies the IRSA, EKS Pod Identity and AKS Workload Identity environment variables and the
+// volume mounts of their token files from the Envoy container to the extproc container.
+//
+// The extproc acquires the cloud provider credentials itself with the identity of the pod for the
+// BackendSecurityPolicies without credentials, but the webhooks only inject the settings into the containers
+// existing when they run, which doesn't include the extproc container if they run before this mutator. The
+// variables already set on the extproc container, for example by the GatewayConfig, take precedence.
+func inheritWorkloadIdentity(container *corev1.Container, containers []corev1.Container) {
+	i := slices.IndexFunc(containers, func(c corev1.Container) bool { return c.Name == "envoy" })
+	if i < 0 {
+		return
+	}
+	envoy := &containers[i]
+	for _, env := range envoy.Env {
+		if !slices.Contains(workloadIdentityEnvVars, env.Name) ||
+			slices.ContainsFunc(container.Env, func(e corev1.EnvVar) bool { return e.Name == env.Name }) {
+			continue
+		}
+		container.Env = append(container.Env, env)
+		if !strings.HasSuffix(env.Name, "_TOKEN_FILE") {
+			continue
+		}
+		for _, m := range envoy.VolumeMounts {
+			if strings.HasPrefix(env.Value, strings.TrimSuffix(m.MountPath, "/")+"/") &&
+				!slices.ContainsFunc(container.VolumeMounts, func(v corev1.VolumeMount) bool { return v.MountPath == m.MountPath }) {
+				container.VolumeMounts = append(container.VolumeMounts, m)
+			}
+		}
+	}
+}
+
 // fetchGatewayConfig returns the referenced GatewayConfig (if present) for the given Gateway.
 // Returns (nil, nil) if: Gateway not found, no annotation, empty annotation, or GatewayConfig not found.
 // Returns (nil, error) for transient failures (API errors) to trigger mutation retry.
@@ -25,6 +25,7 @@ import (
 	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	aigv1b1 "github.com/envoyproxy/ai-gateway/api/v1beta1"
+	"github.com/envoyproxy/ai-gateway/internal/configserver"
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 )
 
@@ -99,7 +100,7 @@ func TestGatewayMutator_mutatePod(t *testing.T) {
 			name:    "basic extproc container with MCPRoute",
 			needMCP: true,
 			extprocTest: func(t *testing.T, container corev1.Container) {
-				var foundMCPAddr, foundMCPSeed, foundMCPSIterations, foundFallbackSeed, foundFallbackIterations bool
+				var foundMCPAddr, foundMCPSeed, foundMCPSIterations, foundFallbackSeed, foundFallbackIterations, foundSessionStore, foundAuditLog, foundAuditArguments bool
 				for i, arg := range container.Args {
 					switch arg {
 					case "-mcpAddr":
@@ -117,6 +118,15 @@ func TestGatewayMutator_mutatePod(t *testing.T) {
 					case "-mcpFallbackSessionEncryptionIterations":
 						foundFallbackIterations = true
 						require.Equal(t, "200", container.Args[i+1])
+					case "-mcpSessionStore":
+						foundSessionStore = true
+						require.Equal(t, "redis://redis:6379", container.Args[i+1])
+					case "-mcpAuditLog":
+						foundAuditLog = true
+						require.Equal(t, "otlp", container.Args[i+1])
+					case "-mcpAuditArguments":
+						foundAuditArguments = true
+						require.Equal(t, "hash", container.Args[i+1])
 					}
 				}
 				require.True(t, foundMCPAddr)
@@ -124,6 +134,9 @@ func TestGatewayMutator_mutatePod(t *testing.T) {
 				require.True(t, foundMCPSIterations)
 				require.True(t, foundFallbackSeed)
 				require.True(t, foundFallbackIterations)
+				require.True(t, foundSessionStore)
+				require.True(t, foundAuditLog)
+				require.True(t, foundAuditArguments)
 			},
 		},
 		{
@@ -433,7 +446,7 @@ func newTestGatewayMutator(fakeClient client.Client, fakeKube *fake2.Clientset,
 	return newGatewayMutator(
 		fakeClient, fakeClient, fakeKube, ctrl.Log, "docker.io/envoyproxy/ai-gateway-extproc:latest", corev1.PullIfNotPresent,
 		"info", false, "/tmp/extproc.sock", requestHeaderAttributes, spanRequestHeaderAttributes, metricsRequestHeaderAttributes, logRequestHeaderAttributes, "/v1", endpointPrefixes, extProcExtraEnvVars, extProcImagePullSecrets, 512*1024*1024,
-		sidecar, "seed", 100, "fallback", 200,
+		sidecar, "seed", 100, "fallback", 200, "redis://redis:6379", "otlp", "hash", "", "",
 	)
 }
 
@@ -704,6 +717,59 @@ func TestGatewayMutator_mergeEnvVars(t *testing.T) {
 	}
 }
 
+func TestInheritWorkloadIdentity(t *testing.T) {
+	containers := []corev1.Container{
+		{Name: "shutdown-manager", Env: []corev1.EnvVar{{Name: "AWS_ROLE_ARN", Value: "ignored"}}},
+		{
+			Name: "envoy",
+			Env: []corev1.EnvVar{
+				{Name: "AWS_ROLE_ARN", Value: "arn:aws:iam::123456789012:role/bedrock"},
+				{Name: "AWS_WEB_IDENTITY_TOKEN_FILE", Value: "/var/run/secrets/eks.amazonaws.com/serviceaccount/token"},
+				{Name: "AWS_REGION", Value: "us-east-1"},
+				{Name: "AWS_CONTAINER_CREDENTIALS_FULL_URI", Value: "http://169.254.170.23/v1/credentials"},
+				{Name: "AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE", Value: "/var/run/secrets/pods.eks.amazonaws.com/serviceaccount/eks-pod-identity-token"},
+				{Name: "AZURE_CLIENT_ID", Value: "client-id"},
+				{Name: "AZURE_FEDERATED_TOKEN_FILE", Value: "/var/run/secrets/azure/tokens/azure-identity-token"},
+				{Name: "ENVOY_GATEWAY_NAMESPACE", Value: "envoy-gateway-system"},
+			},
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "certs", MountPath: "/certs"},
+				{Name: "aws-iam-token", MountPath: "/var/run/secrets/eks.amazonaws.com/serviceaccount", ReadOnly: true},
+				{Name: "eks-pod-identity-token", MountPath: "/var/run/secrets/pods.eks.amazonaws.com/serviceaccount", ReadOnly: true},
+				{Name: "azure-identity-token", MountPath: "/var/run/secrets/azure/tokens", ReadOnly: true},
+			},
+		},
+	}
+	container := corev1.Container{
+		Name: extProcContainerName,
+		// Set by the GatewayConfig.
+		Env:          []corev1.EnvVar{{Name: "AWS_REGION", Value: "eu-west-1"}},
+		VolumeMounts: []corev1.VolumeMount{{Name: "uds", MountPath: "/tmp"}},
+	}
+	inheritWorkloadIdentity(&container, containers)
+	require.Equal(t, []corev1.EnvVar{
+		{Name: "AWS_REGION", Value: "eu-west-1"},
+		{Name: "AWS_ROLE_ARN", Value: "arn:aws:iam::123456789012:role/bedrock"},
+		{Name: "AWS_WEB_IDENTITY_TOKEN_FILE", Value: "/var/run/secrets/eks.amazonaws.com/serviceaccount/token"},
+		{Name: "AWS_CONTAINER_CREDENTIALS_FULL_URI", Value: "http://169.254.170.23/v1/credentials"},
+		{Name: "AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE", Value: "/var/run/secrets/pods.eks.amazonaws.com/serviceaccount/eks-pod-identity-token"},
+		{Name: "AZURE_CLIENT_ID", Value: "client-id"},
+		{Name: "AZURE_FEDERATED_TOKEN_FILE", Value: "/var/run/secrets/azure/tokens/azure-identity-token"},
+	}, container.Env)
+	require.Equal(t, []corev1.VolumeMount{
+		{Name: "uds", MountPath: "/tmp"},
+		{Name: "aws-iam-token", MountPath: "/var/run/secrets/eks.amazonaws.com/serviceaccount", ReadOnly: true},
+		{Name: "eks-pod-identity-token", MountPath: "/var/run/secrets/pods.eks.amazonaws.com/serviceaccount", ReadOnly: true},
+		{Name: "azure-identity-token", MountPath: "/var/run/secrets/azure/tokens", ReadOnly: true},
+	}, container.VolumeMounts)
+
+	// Nothing is inherited without the Envoy container.
+	container = corev1.Container{Name: extProcContainerName}
+	inheritWorkloadIdentity(&container, containers[:1])
+	require.Empty(t, container.Env)
+	require.Empty(t, container.VolumeMounts)
+}
+
 func TestGatewayMutator_resolveExtProcImage(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -765,7 +831,7 @@ func TestGatewayMutator_mutatePod_UsesNoCacheReader(t *testing.T) {
 		cacheClient, noCacheReader, fakeKube, ctrl.Log,
 		"docker.io/envoyproxy/ai-gateway-extproc:latest", corev1.PullIfNotPresent,
 		"info", false, "/tmp/extproc.sock", nil, nil, nil, nil, "/v1", "", "", "", 512*1024*1024,
-		false, "seed", 100, "fallback", 200,
+		false, "seed", 100, "fallback", 200, "", "", "", "", "",
 	)
 
 	const gwName, gwNamespace = "test-gateway", "test-namespace"
@@ -807,6 +873,70 @@ func TestGatewayMutator_mutatePod_UsesNoCacheReader(t *testing.T) {
 	require.Equal(t, extProcContainerName, pod.Spec.Containers[1].Name)
 }
 
+func TestGatewayMutator_mutatePod_ConfigServer(t *testing.T) {
+	fakeClient := requireNewFakeClientWithIndexes(t)
+	g := newGatewayMutator(
+		fakeClient, fakeClient, fake2.NewClientset(), ctrl.Log,
+		"docker.io/envoyproxy/ai-gateway-extproc:latest", corev1.PullIfNotPresent,
+		"info", false, "/tmp/extproc.sock", nil, nil, nil, nil, "/v1", "", "", "", 512*1024*1024,
+		false, "seed", 100, "fallback", 200, "", "", "", "ai-gateway-controller.envoy-ai-gateway-system:18003", "ca",
+	)
+
+	const gwName, gwNamespace = "test-gateway", "test-namespace"
+	err := fakeClient.Create(t.Context(), &aigv1b1.AIGatewayRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "route-1", Namespace: gwNamespace},
+		Spec: aigv1b1.AIGatewayRouteSpec{
+			ParentRefs: []gwapiv1a2.ParentReference{
+				{
+					Name:  gwapiv1a2.ObjectName(gwName),
+					Kind:  ptr.To(gwapiv1a2.Kind("Gateway")),
+					Group: ptr.To(gwapiv1a2.Group("gateway.networking.k8s.io")),
+				},
+			},
+			Rules: []aigv1b1.AIGatewayRouteRule{
+				{BackendRefs: []aigv1b1.AIGatewayRouteRuleBackendRef{{Name: "backend"}}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// The filter config Secret is not needed as the config is streamed by the config server.
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: gwNamespace},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "envoy"}}},
+	}
+	err = g.mutatePod(t.Context(), pod, gwName, gwNamespace)
+	require.NoError(t, err)
+	require.Len(t, pod.Spec.Containers, 2)
+	extProc := pod.Spec.Containers[1]
+	require.Equal(t, extProcContainerName, extProc.Name)
+	require.Equal(t, []string{
+		"-configServerAddr", "ai-gateway-controller.envoy-ai-gateway-system:18003",
+		"-configServerNodeID", "test-namespace/test-gateway",
+		"-configServerCACert", "ca",
+	}, extProc.Args[:6])
+	require.NotContains(t, extProc.Args, "-configPath")
+	for _, m := range extProc.VolumeMounts {
+		require.NotEqual(t, "/etc/filter-config", m.MountPath)
+	}
+	for _, v := range pod.Spec.Volumes {
+		require.Nil(t, v.Secret)
+	}
+
+	// The extproc authenticates to the config server with a projected ServiceAccount token.
+	require.Contains(t, extProc.VolumeMounts, corev1.VolumeMount{
+		Name: mutationNamePrefix + "config-server-token", MountPath: configserver.TokenDir, ReadOnly: true,
+	})
+	require.Contains(t, pod.Spec.Volumes, corev1.Volume{
+		Name: mutationNamePrefix + "config-server-token",
+		VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+			Sources: []corev1.VolumeProjection{{ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+				Audience: configserver.TokenAudience, ExpirationSeconds: ptr.To(int64(3600)), Path: "token",
+			}}},
+		}},
+	})
+}
+
 func TestGatewayMutator_listAIGatewayRoutesForGateway_NoCacheReaderFallback(t *testing.T) {
 	cacheClient := requireNewFakeClientWithIndexes(t)
 	noCacheReader := requireNewFakeClientWithIndexes(t)
@@ -815,7 +945,7 @@ func TestGatewayMutator_listAIGatewayRoutesForGateway_NoCacheReaderFallback(t *t
 		cacheClient, noCacheReader, fakeKube, ctrl.Log,
 		"docker.io/envoyproxy/ai-gateway-extproc:latest", corev1.PullIfNotPresent,
 		"info", false, "/tmp/extproc.sock", nil, nil, nil, nil, "/v1", "", "", "", 512*1024*1024,
-		false, "seed", 100, "fallback", 200,
+		false, "seed", 100, "fallback", 200, "", "", "", "", "",
 	)
 
 	const gwName, gwNamespace = "test-gateway", "test-namespace"
@@ -859,7 +989,7 @@ func TestGatewayMutator_listMCPRoutesForGateway_NoCacheReaderFallback(t *testing
 		cacheClient, noCacheReader, fakeKube, ctrl.Log,
 		"docker.io/envoyproxy/ai-gateway-extproc:latest", corev1.PullIfNotPresent,
 		"info", false, "/tmp/extproc.sock", nil, nil, nil, nil, "/v1", "", "", "", 512*1024*1024,
-		false, "seed", 100, "fallback", 200,
+		false, "seed", 100, "fallback", 200, "", "", "", "", "",
 	)
 
 	const gwName, gwNamespace = "test-gateway", "test-namespace"
@@ -7,27 +7,38 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"testing"
 	"time"
 
+	egv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zapcore"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	fake2 "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	"sigs.k8s.io/yaml"
 
+	aigv1a1 "github.com/envoyproxy/ai-gateway/api/v1alpha1"
 	aigv1b1 "github.com/envoyproxy/ai-gateway/api/v1beta1"
+	"github.com/envoyproxy/ai-gateway/internal/configserver"
 	"github.com/envoyproxy/ai-gateway/internal/controller/rotators"
 	"github.com/envoyproxy/ai-gateway/internal/filterapi"
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
@@ -275,7 +286,7 @@ func TestGatewayController_reconcileFilterConfigSecret(t *testing.T) {
 	for range 2 { // Reconcile twice to make sure the secret update path is working.
 		const someNamespace = "some-namespace"
 		configName := FilterConfigSecretPerGatewayName("gw", gwNamespace)
-		effective, err := c.reconcileFilterConfigSecret(t.Context(), configName, someNamespace, routes, nil, "foouuid", nil)
+		effective, err := c.reconcileFilterConfigSecret(t.Context(), nil, configName, someNamespace, routes, nil, "foouuid", nil)
 		require.NoError(t, err)
 		require.True(t, effective, "expected filter config to be effective")
 
@@ -304,7 +315,7 @@ func TestGatewayController_reconcileFilterConfigSecret(t *testing.T) {
 
 		catProg, err := llmcostcel.NewProgram(wantLLMRequestCosts[6].CEL)
 		require.NoError(t, err)
-		catVal, err := llmcostcel.EvaluateProgram(catProg, "model", "foo.default", "ns/route2", 3, 0, 0, 4, 7, 0)
+		catVal, err := llmcostcel.EvaluateProgram(catProg, "model", "foo.default", "ns/route2", "", 3, 0, 0, 4, 7, 0)
 		require.NoError(t, err)
 		require.Equal(t, uint64(7), catVal)
 
@@ -386,7 +397,7 @@ func TestGatewayController_reconcileFilterConfigSecret_HostnameScopedModels(t *t
 
 	const someNamespace = "some-namespace"
 	configName := FilterConfigSecretPerGatewayName("gw-hostname", gwNamespace)
-	effective, err := c.reconcileFilterConfigSecret(t.Context(), configName, someNamespace, routes, nil, "foouuid", nil)
+	effective, err := c.reconcileFilterConfigSecret(t.Context(), nil, configName, someNamespace, routes, nil, "foouuid", nil)
 	require.NoError(t, err)
 	require.True(t, effective, "expected filter config to be effective")
 
@@ -456,7 +467,7 @@ func TestGatewayController_reconcileFilterConfigSecret_AllUnscopedRoutesLeaveUns
 
 	const someNamespace = "some-namespace"
 	configName := FilterConfigSecretPerGatewayName("gw-unscoped-only", gwNamespace)
-	effective, err := c.reconcileFilterConfigSecret(t.Context(), configName, someNamespace, routes, nil, "foouuid", nil)
+	effective, err := c.reconcileFilterConfigSecret(t.Context(), nil, configName, someNamespace, routes, nil, "foouuid", nil)
 	require.NoError(t, err)
 	require.True(t, effective)
 
@@ -473,6 +484,224 @@ func TestGatewayController_reconcileFilterConfigSecret_AllUnscopedRoutesLeaveUns
 	require.Nil(t, fc.UnscopedModels)
 }
 
+func TestGatewayController_reconcileFilterConfigSecret_ConfigServer(t *testing.T) {
+	fakeClient := requireNewFakeClientWithIndexes(t)
+	kube := fake2.NewClientset()
+	c := NewGatewayController(fakeClient, kube, ctrl.Log,
+		"docker.io/envoyproxy/ai-gateway-extproc:latest", "info", false, nil, true)
+	c.configServer = configserver.New(ctrl.Log, "", nil, "envoy-gateway-system", "", "")
+
+	const gwNamespace = "ns"
+	routes := []aigv1b1.AIGatewayRoute{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: gwNamespace},
+			Spec: aigv1b1.AIGatewayRouteSpec{
+				Rules: []aigv1b1.AIGatewayRouteRule{{BackendRefs: []aigv1b1.AIGatewayRouteRuleBackendRef{{Name: "apple"}}}},
+			},
+		},
+	}
+	require.NoError(t, fakeClient.Create(t.Context(), &aigv1b1.AIServiceBackend{
+		ObjectMeta: metav1.ObjectMeta{Name: "apple", Namespace: gwNamespace},
+		Spec: aigv1b1.AIServiceBackendSpec{
+			BackendRef: gwapiv1.BackendObjectReference{Name: "some-backend1", Namespace: ptr.To[gwapiv1.Namespace](gwNamespace)},
+		},
+	}))
+
+	gw := &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: gwNamespace}}
+	const someNamespace = "some-namespace"
+	configName := FilterConfigSecretPerGatewayName(gw.Name, gwNamespace)
+	effective, err := c.reconcileFilterConfigSecret(t.Context(), gw, configName, someNamespace, routes, nil, "foouuid", nil)
+	require.NoError(t, err)
+	require.True(t, effective)
+
+	// The config is served by the config server instead of the Secret.
+	_, err = kube.CoreV1().Secrets(someNamespace).Get(t.Context(), configName, metav1.GetOptions{})
+	require.True(t, apierrors.IsNotFound(err))
+
+	// The config of the Gateway is removed from the config server once it is deleted.
+	_, err = c.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKey{Name: gw.Name, Namespace: gwNamespace}})
+	require.NoError(t, err)
+}
+
+func TestGatewayController_updateConfigStatusConditions(t *testing.T) {
+	fakeClient := requireNewFakeClientWithIndexes(t)
+	c := NewGatewayController(fakeClient, fake2.NewClientset(), ctrl.Log,
+		"docker.io/envoyproxy/ai-gateway-extproc:latest", "info", false, nil, true)
+
+	const gwNamespace, gwName = "ns", "gw"
+	for _, name := range []string{"attached", "other"} {
+		gateway := gwName
+		if name == "other" {
+			gateway = "other-gw"
+		}
+		require.NoError(t, fakeClient.Create(t.Context(), &aigv1b1.AIGatewayRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: gwNamespace},
+			Spec: aigv1b1.AIGatewayRouteSpec{
+				ParentRefs: []gwapiv1.ParentReference{{Name: gwapiv1.ObjectName(gateway)}},
+				Rules: []aigv1b1.AIGatewayRouteRule{
+					{BackendRefs: []aigv1b1.AIGatewayRouteRuleBackendRef{{Name: name + "-openai"}, {Name: name + "-bad"}}},
+				},
+			},
+			Status: aigv1b1.AIGatewayRouteStatus{Conditions: newConditions(aigv1b1.ConditionTypeAccepted, "ok")},
+		}))
+		for _, backend := range []string{name + "-openai", name + "-bad"} {
+			require.NoError(t, fakeClient.Create(t.Context(), &aigv1b1.AIServiceBackend{
+				ObjectMeta: metav1.ObjectMeta{Name: backend, Namespace: gwNamespace},
+				Status:     aigv1b1.AIServiceBackendStatus{Conditions: newConditions(aigv1b1.ConditionTypeAccepted, "ok")},
+			}))
+		}
+	}
+	getConditions := func(name string) []metav1.Condition {
+		var route aigv1b1.AIGatewayRoute
+		require.NoError(t, fakeClient.Get(t.Context(), client.ObjectKey{Name: name, Namespace: gwNamespace}, &route))
+		return route.Status.Conditions
+	}
+	getBackendConditions := func(name string) []metav1.Condition {
+		var backend aigv1b1.AIServiceBackend
+		require.NoError(t, fakeClient.Get(t.Context(), client.ObjectKey{Name: name, Namespace: gwNamespace}, &backend))
+		return backend.Status.Conditions
+	}
+
+	nodeID := configserver.NodeID(gwNamespace, gwName)
+	c.updateConfigStatusConditions(t.Context(), configserver.Status{NodeID: nodeID, Version: "uuid-1"})
+	conditions := getConditions("attached")
+	require.Len(t, conditions, 2)
+	require.Equal(t, aigv1b1.ConditionTypeAccepted, conditions[0].Type)
+	programmed := meta.FindStatusCondition(conditions, aigv1b1.ConditionTypeProgrammed)
+	require.NotNil(t, programmed)
+	require.Equal(t, metav1.ConditionTrue, programmed.Status)
+	require.Equal(t, "ConfigApplied", programmed.Reason)
+	require.Contains(t, programmed.Message, "uuid-1")
+	require.Len(t, getConditions("other"), 1)
+	for _, backend := range []string{"attached-openai", "attached-bad"} {
+		conditions = getBackendConditions(backend)
+		require.Len(t, conditions, 3)
+		require.True(t, meta.IsStatusConditionTrue(conditions, aigv1b1.ConditionTypeProgrammed))
+		resolvedRefs := meta.FindStatusCondition(conditions, aigv1b1.ConditionTypeResolvedRefs)
+		require.NotNil(t, resolvedRefs)
+		require.Equal(t, metav1.ConditionTrue, resolvedRefs.Status)
+		require.Equal(t, "BackendReady", resolvedRefs.Reason)
+	}
+	require.Len(t, getBackendConditions("other-openai"), 1)
+
+	// The config rejected before the backends were set up doesn't change the ResolvedRefs condition.
+	c.updateConfigStatusConditions(t.Context(), configserver.Status{NodeID: nodeID, Version: "uuid-2", Error: "config version mismatch"})
+	programmed = meta.FindStatusCondition(getConditions("attached"), aigv1b1.ConditionTypeProgrammed)
+	require.NotNil(t, programmed)
+	require.Equal(t, metav1.ConditionFalse, programmed.Status)
+	require.Equal(t, "ConfigRejected", programmed.Reason)
+	require.Equal(t, "Filter config uuid-2 rejected by the external processors of Gateway ns/gw: config version mismatch", programmed.Message)
+	conditions = getBackendConditions("attached-bad")
+	require.True(t, meta.IsStatusConditionFalse(conditions, aigv1b1.ConditionTypeProgrammed))
+	require.True(t, meta.IsStatusConditionTrue(conditions, aigv1b1.ConditionTypeResolvedRefs))
+
+	// The backends that failed to be set up are reported on the AIServiceBackends.
+	badBackendName := internalapi.PerRouteRuleRefBackendName(gwNamespace, "attached-bad", "attached", 0, 1)
+	c.updateConfigStatusConditions(t.Context(), configserver.Status{
+		NodeID: nodeID, Version: "uuid-3", Error: "backend " + badBackendName + ": invalid credentials",
+		BackendErrors: map[string]string{badBackendName: "invalid credentials"},
+	})
+	conditions = getBackendConditions("attached-bad")
+	require.True(t, meta.IsStatusConditionFalse(conditions, aigv1b1.ConditionTypeProgrammed))
+	resolvedRefs := meta.FindStatusCondition(conditions, aigv1b1.ConditionTypeResolvedRefs)
+	require.NotNil(t, resolvedRefs)
+	require.Equal(t, metav1.ConditionFalse, resolvedRefs.Status)
+	require.Equal(t, "BackendSetupFailed", resolvedRefs.Reason)
+	require.Equal(t, "Backend cannot be set up by the external processors of Gateway ns/gw with filter config uuid-3: invalid credentials", resolvedRefs.Message)
+	conditions = getBackendConditions("attached-openai")
+	require.True(t, meta.IsStatusConditionFalse(conditions, aigv1b1.ConditionTypeProgrammed))
+	require.True(t, meta.IsStatusConditionTrue(conditions, aigv1b1.ConditionTypeResolvedRefs))
+
+	// The pending config makes the Programmed condition unknown and doesn't change the ResolvedRefs condition.
+	c.updateConfigStatusConditions(t.Context(), configserver.Status{NodeID: nodeID, Version: "uuid-4", Pending: true})
+	programmed = meta.FindStatusCondition(getConditions("attached"), aigv1b1.ConditionTypeProgrammed)
+	require.NotNil(t, programmed)
+	require.Equal(t, metav1.ConditionUnknown, programmed.Status)
+	require.Equal(t, "ConfigPending", programmed.Reason)
+	conditions = getBackendConditions("attached-bad")
+	require.Equal(t, metav1.ConditionUnknown, meta.FindStatusCondition(conditions, aigv1b1.ConditionTypeProgrammed).Status)
+	require.True(t, meta.IsStatusConditionFalse(conditions, aigv1b1.ConditionTypeResolvedRefs))
+
+	// Unknown nodes are ignored.
+	c.updateConfigStatusConditions(t.Context(), configserver.Status{NodeID: "unknown", Version: "uuid-5"})
+	programmed = meta.FindStatusCondition(getConditions("attached"), aigv1b1.ConditionTypeProgrammed)
+	require.Equal(t, metav1.ConditionUnknown, programmed.Status)
+
+	// Without the config server, the conditions are Unknown.
+	c.setConfigStatusConditionsUnknown(t.Context(), gwNamespace, gwName)
+	programmed = meta.FindStatusCondition(getConditions("attached"), aigv1b1.ConditionTypeProgrammed)
+	require.Equal(t, metav1.ConditionUnknown, programmed.Status)
+	require.Equal(t, "ConfigServerDisabled", programmed.Reason)
+	resolvedRefs = meta.FindStatusCondition(getBackendConditions("attached-bad"), aigv1b1.ConditionTypeResolvedRefs)
+	require.Equal(t, metav1.ConditionUnknown, resolvedRefs.Status)
+	require.Equal(t, "ConfigServerDisabled", resolvedRefs.Reason)
+	require.Len(t, getConditions("other"), 1)
+}
+
+func TestGatewayController_updateConfigStatusConditions_SharedResources(t *testing.T) {
+	fakeClient := requireNewFakeClientWithIndexes(t)
+	c := NewGatewayController(fakeClient, fake2.NewClientset(), ctrl.Log,
+		"docker.io/envoyproxy/ai-gateway-extproc:latest", "info", false, nil, true)
+
+	const namespace = "ns"
+	require.NoError(t, fakeClient.Create(t.Context(), &aigv1b1.AIGatewayRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: namespace},
+		Spec: aigv1b1.AIGatewayRouteSpec{
+			ParentRefs: []gwapiv1.ParentReference{{Name: "gw1"}, {Name: "gw2"}},
+			Rules:      []aigv1b1.AIGatewayRouteRule{{BackendRefs: []aigv1b1.AIGatewayRouteRuleBackendRef{{Name: "openai"}}}},
+		},
+	}))
+	require.NoError(t, fakeClient.Create(t.Context(), &aigv1b1.AIServiceBackend{
+		ObjectMeta: metav1.ObjectMeta{Name: "openai", Namespace: namespace},
+	}))
+	getProgrammed := func() (route, backend *metav1.Condition) {
+		var r aigv1b1.AIGatewayRoute
+		require.NoError(t, fakeClient.Get(t.Context(), client.ObjectKey{Name: "shared", Namespace: namespace}, &r))
+		var b aigv1b1.AIServiceBackend
+		require.NoError(t, fakeClient.Get(t.Context(), client.ObjectKey{Name: "openai", Namespace: namespace}, &b))
+		return meta.FindStatusCondition(r.Status.Conditions, aigv1b1.ConditionTypeProgrammed),
+			meta.FindStatusCondition(b.Status.Conditions, aigv1b1.ConditionTypeProgrammed)
+	}
+	gw1, gw2 := configserver.NodeID(namespace, "gw1"), configserver.NodeID(namespace, "gw2")
+
+	// The rejection of the config by one Gateway is not hidden by the other one applying its config.
+	c.updateConfigStatusConditions(t.Context(), configserver.Status{NodeID: gw2, Version: "uuid-2", Error: "config version mismatch"})
+	c.updateConfigStatusConditions(t.Context(), configserver.Status{NodeID: gw1, Version: "uuid-1"})
+	route, backend := getProgrammed()
+	for _, programmed := range []*metav1.Condition{route, backend} {
+		require.Equal(t, metav1.ConditionFalse, programmed.Status)
+		require.Equal(t, "ConfigRejected", programmed.Reason)
+		require.Equal(t, "Filter config uuid-2 rejected by the external processors of Gateway ns/gw2: config version mismatch", programmed.Message)
+	}
+
+	// The conditions are Unknown while one Gateway is pending.
+	c.updateConfigStatusConditions(t.Context(), configserver.Status{NodeID: gw2, Version: "uuid-3", Pending: true})
+	route, _ = getProgrammed()
+	require.Equal(t, metav1.ConditionUnknown, route.Status)
+	require.Equal(t, "Filter config uuid-3 not yet applied by all the external processors of Gateway ns/gw2", route.Message)
+
+	// The conditions are True once all the Gateways applied their config.
+	c.updateConfigStatusConditions(t.Context(), configserver.Status{NodeID: gw2, Version: "uuid-3"})
+	route, backend = getProgrammed()
+	for _, programmed := range []*metav1.Condition{route, backend} {
+		require.Equal(t, metav1.ConditionTrue, programmed.Status)
+		require.Equal(t, "Filter config uuid-1 applied by the external processors of Gateway ns/gw1; "+
+			"Filter config uuid-3 applied by the external processors of Gateway ns/gw2", programmed.Message)
+	}
+
+	// A deleted Gateway doesn't contribute to the conditions anymore.
+	c.updateConfigStatusConditions(t.Context(), configserver.Status{NodeID: gw2, Version: "uuid-4", Error: "config version mismatch"})
+	route, _ = getProgrammed()
+	require.Equal(t, metav1.ConditionFalse, route.Status)
+	_, err := c.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "gw2", Namespace: namespace}})
+	require.NoError(t, err)
+	route, backend = getProgrammed()
+	for _, programmed := range []*metav1.Condition{route, backend} {
+		require.Equal(t, metav1.ConditionTrue, programmed.Status)
+		require.Equal(t, "Filter config uuid-1 applied by the external processors of Gateway ns/gw1", programmed.Message)
+	}
+}
+
 // TestGatewayController_reconcileFilterConfigSecret_RouteLevelLLMRequestCostAggregation verifies that
 // routes sharing the same metadataKey each get their own filter-config row (scoped by routeName).
 func TestGatewayController_reconcileFilterConfigSecret_RouteLevelLLMRequestCostAggregation(t *testing.T) {
@@ -533,7 +762,7 @@ func TestGatewayController_reconcileFilterConfigSecret_RouteLevelLLMRequestCostA
 
 	const someNamespace = "some-namespace"
 	configName := FilterConfigSecretPerGatewayName("gw", gwNamespace)
-	effective, err := c.reconcileFilterConfigSecret(t.Context(), configName, someNamespace, routes, nil, "foouuid", nil)
+	effective, err := c.reconcileFilterConfigSecret(t.Context(), nil, configName, someNamespace, routes, nil, "foouuid", nil)
 	require.NoError(t, err)
 	require.True(t, effective, "expected filter config to be effective")
 
@@ -564,12 +793,12 @@ func TestGatewayController_reconcileFilterConfigSecret_RouteLevelLLMRequestCostA
 
 	freeProg, err := llmcostcel.NewProgram(wantLLMRequestCosts[0].CEL)
 	require.NoError(t, err)
-	val, err := llmcostcel.EvaluateProgram(freeProg, "model", "free-backend", "ns/free-model-route", 10, 0, 0, 5, 15, 0)
+	val, err := llmcostcel.EvaluateProgram(freeProg, "model", "free-backend", "ns/free-model-route", "", 10, 0, 0, 5, 15, 0)
 	require.NoError(t, err)
 	require.Equal(t, uint64(0), val)
 	paidProg, err := llmcostcel.NewProgram(wantLLMRequestCosts[1].CEL)
 	require.NoError(t, err)
-	val, err = llmcostcel.EvaluateProgram(paidProg, "model", "paid-backend", "ns/paid-model-route", 10, 0, 0, 5, 15, 0)
+	val, err = llmcostcel.EvaluateProgram(paidProg, "model", "paid-backend", "ns/paid-model-route", "", 10, 0, 0, 5, 15, 0)
 	require.NoError(t, err)
 	require.Equal(t, uint64(15), val)
 }
@@ -609,7 +838,7 @@ func TestGatewayController_reconcileFilterConfigSecret_RouteLevelLLMRequestCostA
 
 	const someNamespace = "some-namespace"
 	configName := FilterConfigSecretPerGatewayName("gw", gwNamespace)
-	effective, err := c.reconcileFilterConfigSecret(t.Context(), configName, someNamespace, routes, nil, "foouuid", nil)
+	effective, err := c.reconcileFilterConfigSecret(t.Context(), nil, configName, someNamespace, routes, nil, "foouuid", nil)
 	require.NoError(t, err)
 	require.True(t, effective, "expected filter config to be effective")
 
@@ -666,7 +895,7 @@ func TestGatewayController_reconcileFilterConfigSecret_InvalidCELExpression(t *t
 
 	const someNamespace = "some-namespace"
 	configName := FilterConfigSecretPerGatewayName("gw", gwNamespace)
-	_, err = c.reconcileFilterConfigSecret(t.Context(), configName, someNamespace, routes, nil, "foouuid", nil)
+	_, err = c.reconcileFilterConfigSecret(t.Context(), nil, configName, someNamespace, routes, nil, "foouuid", nil)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "invalid CEL expression")
 }
@@ -760,7 +989,7 @@ func TestGatewayController_reconcileFilterConfigSecret_SkipsDeletedRoutes(t *tes
 	configName := FilterConfigSecretPerGatewayName("gw", gwNamespace)
 
 	// Reconcile filter config secret.
-	effective, err := c.reconcileFilterConfigSecret(t.Context(), configName, someNamespace, routes, nil, "foouuid", nil)
+	effective, err := c.reconcileFilterConfigSecret(t.Context(), nil, configName, someNamespace, routes, nil, "foouuid", nil)
 	require.NoError(t, err)
 	require.True(t, effective, "expected filter config to be effective")
 
@@ -838,6 +1067,29 @@ func TestGatewayController_bspToFilterAPIBackendAuth(t *testing.T) {
 				AzureCredentials: &aigv1b1.BackendSecurityPolicyAzureCredentials{},
 			},
 		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "azure-managed-identity", Namespace: namespace},
+			Spec: aigv1b1.BackendSecurityPolicySpec{
+				Type: aigv1b1.BackendSecurityPolicyTypeAzureCredentials,
+				AzureCredentials: &aigv1b1.BackendSecurityPolicyAzureCredentials{
+					ClientID:        "user-assigned-client-id",
+					ManagedIdentity: &aigv1b1.AzureManagedIdentity{},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "azure-workload-identity", Namespace: namespace},
+			Spec: aigv1b1.BackendSecurityPolicySpec{
+				Type: aigv1b1.BackendSecurityPolicyTypeAzureCredentials,
+				AzureCredentials: &aigv1b1.BackendSecurityPolicyAzureCredentials{
+					ClientID: "client-id",
+					TenantID: "tenant-id",
+					WorkloadIdentity: &aigv1b1.AzureWorkloadIdentity{
+						TokenFilePath: ptr.To("/var/run/secrets/azure/tokens/azure-identity-token"),
+					},
+				},
+			},
+		},
 		{
 			ObjectMeta: metav1.ObjectMeta{Name: "gcp-sa-key-file", Namespace: namespace},
 			Spec: aigv1b1.BackendSecurityPolicySpec{
@@ -879,14 +1131,84 @@ func TestGatewayController_bspToFilterAPIBackendAuth(t *testing.T) {
 				},
 			},
 		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "bsp-apikey-pool", Namespace: namespace},
+			Spec: aigv1b1.BackendSecurityPolicySpec{
+				Type: aigv1b1.BackendSecurityPolicyTypeAPIKeyPool,
+				APIKeyPool: &aigv1b1.BackendSecurityPolicyAPIKeyPool{
+					Keys: []aigv1b1.BackendSecurityPolicyAPIKeyPoolKey{
+						{SecretRef: &gwapiv1.SecretObjectReference{Name: "api-key-secret"}, Weight: ptr.To[int32](3)},
+						{SecretRef: &gwapiv1.SecretObjectReference{Name: "api-key-secret-2"}},
+					},
+					Strategy:         aigv1b1.APIKeyPoolStrategyLeastRecentlyRateLimited,
+					Header:           ptr.To("x-api-key"),
+					EjectionDuration: ptr.To[gwapiv1.Duration]("1m"),
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "bsp-request-signing", Namespace: namespace},
+			Spec: aigv1b1.BackendSecurityPolicySpec{
+				Type: aigv1b1.BackendSecurityPolicyTypeRequestSigning,
+				RequestSigning: &aigv1b1.BackendSecurityPolicyRequestSigning{
+					Scheme:        aigv1b1.RequestSigningSchemeHMACSHA256,
+					KeyID:         "my-key",
+					SecretRef:     &gwapiv1.SecretObjectReference{Name: "signing-key-secret"},
+					SignedHeaders: []string{"date", "host"},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "bsp-client-certificate", Namespace: namespace},
+			Spec: aigv1b1.BackendSecurityPolicySpec{
+				Type: aigv1b1.BackendSecurityPolicyTypeClientCertificate,
+				ClientCertificate: &aigv1b1.BackendSecurityPolicyClientCertificate{
+					SecretRef: &gwapiv1.SecretObjectReference{Name: "client-tls"},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vault-apikey", Namespace: namespace},
+			Spec: aigv1b1.BackendSecurityPolicySpec{
+				Type: aigv1b1.BackendSecurityPolicyTypeVault,
+				Vault: &aigv1b1.BackendSecurityPolicyVault{
+					KV:         &aigv1b1.VaultKVSecret{Path: "ai/openai"},
+					Credential: aigv1b1.VaultCredential{Type: aigv1b1.VaultCredentialTypeAPIKey, Key: "openai"},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vault-aws", Namespace: namespace},
+			Spec: aigv1b1.BackendSecurityPolicySpec{
+				Type: aigv1b1.BackendSecurityPolicyTypeVault,
+				Vault: &aigv1b1.BackendSecurityPolicyVault{
+					Dynamic: &aigv1b1.VaultDynamicSecret{Path: "aws/creds/bedrock"},
+					Credential: aigv1b1.VaultCredential{
+						Type:   aigv1b1.VaultCredentialTypeAWSCredentials,
+						Region: ptr.To("us-east-1"),
+					},
+				},
+			},
+		},
 	} {
 		require.NoError(t, fakeClient.Create(t.Context(), bsp))
 	}
+	c.vaultCredentials = rotators.NewVaultCredentialStore()
+	c.vaultCredentials.Set(namespace, "vault-apikey", map[string]string{"openai": "thisisvaultapikey"}, time.Now().Add(time.Hour))
+	c.vaultCredentials.Set(namespace, "vault-aws", map[string]string{"access_key": "AKIA", "secret_key": "secret"}, time.Now().Add(time.Hour))
 	for _, s := range []*corev1.Secret{
 		{
 			ObjectMeta: metav1.ObjectMeta{Name: "api-key-secret", Namespace: namespace},
 			StringData: map[string]string{apiKeyInSecret: "thisisapikey"},
 		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-key-secret-2", Namespace: namespace},
+			StringData: map[string]string{apiKeyInSecret: "thisisanotherapikey"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "signing-key-secret", Namespace: namespace},
+			StringData: map[string]string{signingKeyInSecret: "thisissigningkey"},
+		},
 		{
 			ObjectMeta: metav1.ObjectMeta{Name: "aws-credentials-file-secret", Namespace: namespace},
 			StringData: map[string]string{rotators.AwsCredentialsKey: "thisisawscredentials"},
@@ -949,6 +1271,28 @@ func TestGatewayController_bspToFilterAPIBackendAuth(t *testing.T) {
 				AzureAuth: &filterapi.AzureAuth{AccessToken: "thisisazurecredentials"},
 			},
 		},
+		{
+			bspName: "azure-managed-identity",
+			exp: &filterapi.BackendAuth{
+				AzureAuth: &filterapi.AzureAuth{Identity: &filterapi.AzureIdentity{
+					Type:     filterapi.AzureIdentityTypeManagedIdentity,
+					ClientID: "user-assigned-client-id",
+					Scope:    "https://cognitiveservices.azure.com/.default",
+				}},
+			},
+		},
+		{
+			bspName: "azure-workload-identity",
+			exp: &filterapi.BackendAuth{
+				AzureAuth: &filterapi.AzureAuth{Identity: &filterapi.AzureIdentity{
+					Type:          filterapi.AzureIdentityTypeWorkloadIdentity,
+					ClientID:      "client-id",
+					TenantID:      "tenant-id",
+					TokenFilePath: "/var/run/secrets/azure/tokens/azure-identity-token",
+					Scope:         "https://cognitiveservices.azure.com/.default",
+				}},
+			},
+		},
 		{
 			bspName: "gcp-wif",
 			exp: &filterapi.BackendAuth{
@@ -970,6 +1314,49 @@ func TestGatewayController_bspToFilterAPIBackendAuth(t *testing.T) {
 				AnthropicAPIKey: &filterapi.AnthropicAPIKeyAuth{Key: "thisisapikey"},
 			},
 		},
+		{
+			bspName: "bsp-apikey-pool",
+			exp: &filterapi.BackendAuth{
+				APIKeyPool: &filterapi.APIKeyPoolAuth{
+					Keys: []filterapi.APIKeyPoolKey{
+						{Key: "thisisapikey", Weight: 3},
+						{Key: "thisisanotherapikey", Weight: 1},
+					},
+					Strategy:         filterapi.APIKeyPoolStrategyLeastRecentlyRateLimited,
+					Header:           "x-api-key",
+					EjectionDuration: time.Minute,
+				},
+			},
+		},
+		{
+			bspName: "bsp-request-signing",
+			exp: &filterapi.BackendAuth{
+				RequestSigning: &filterapi.RequestSigningAuth{
+					Scheme:        filterapi.RequestSigningSchemeHMACSHA256,
+					KeyID:         "my-key",
+					Key:           "thisissigningkey",
+					SignedHeaders: []string{"date", "host"},
+				},
+			},
+		},
+		{
+			// The client certificate is configured on the Envoy cluster by the extension server.
+			bspName: "bsp-client-certificate",
+			exp:     nil,
+		},
+		{
+			bspName: "vault-apikey",
+			exp:     &filterapi.BackendAuth{APIKey: &filterapi.APIKeyAuth{Key: "thisisvaultapikey"}},
+		},
+		{
+			bspName: "vault-aws",
+			exp: &filterapi.BackendAuth{
+				AWSAuth: &filterapi.AWSAuth{
+					CredentialFileLiteral: "[default]\naws_access_key_id = AKIA\naws_secret_access_key = secret\nregion = us-east-1\n",
+					Region:                "us-east-1",
+				},
+			},
+		},
 	} {
 		t.Run(tc.bspName, func(t *testing.T) {
 			bsp := &aigv1b1.BackendSecurityPolicy{}
@@ -989,6 +1376,7 @@ func TestGatewayController_bspToFilterAPIBackendAuth_ErrorCases(t *testing.T) {
 	fakeClient := requireNewFakeClientWithIndexes(t)
 	c := NewGatewayController(fakeClient, fake2.NewClientset(), ctrl.Log,
 		"docker.io/envoyproxy/ai-gateway-extproc:latest", "info", false, nil, true)
+	c.vaultCredentials = rotators.NewVaultCredentialStore()
 
 	ctx := context.Background()
 	namespace := "test-namespace"
@@ -1034,6 +1422,37 @@ func TestGatewayController_bspToFilterAPIBackendAuth_ErrorCases(t *testing.T) {
 			},
 			expectedError: "failed to get secret missing-aws-secret",
 		},
+		{
+			name:    "api key pool with missing secret",
+			bspName: "api-key-pool-bsp",
+			bsp: &aigv1b1.BackendSecurityPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-key-pool-bsp", Namespace: namespace},
+				Spec: aigv1b1.BackendSecurityPolicySpec{
+					Type: aigv1b1.BackendSecurityPolicyTypeAPIKeyPool,
+					APIKeyPool: &aigv1b1.BackendSecurityPolicyAPIKeyPool{
+						Keys: []aigv1b1.BackendSecurityPolicyAPIKeyPoolKey{
+							{SecretRef: &gwapiv1.SecretObjectReference{Name: "missing-pool-secret"}},
+						},
+					},
+				},
+			},
+			expectedError: "failed to get secret missing-pool-secret",
+		},
+		{
+			name:    "vault credentials not read yet",
+			bspName: "vault-bsp",
+			bsp: &aigv1b1.BackendSecurityPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "vault-bsp", Namespace: namespace},
+				Spec: aigv1b1.BackendSecurityPolicySpec{
+					Type: aigv1b1.BackendSecurityPolicyTypeVault,
+					Vault: &aigv1b1.BackendSecurityPolicyVault{
+						KV:         &aigv1b1.VaultKVSecret{Path: "ai/openai"},
+						Credential: aigv1b1.VaultCredential{Type: aigv1b1.VaultCredentialTypeAPIKey},
+					},
+				},
+			},
+			expectedError: "the credentials of backend security policy test-namespace/vault-bsp have not been read from vault yet",
+		},
 	}
 
 	for _, tt := range tests {
@@ -1044,6 +1463,11 @@ func TestGatewayController_bspToFilterAPIBackendAuth_ErrorCases(t *testing.T) {
 			require.Nil(t, result)
 		})
 	}
+
+	// The Vault credentials are never written in the filter config Secrets.
+	c.vaultCredentials = nil
+	_, err := c.bspToFilterAPIBackendAuth(ctx, tests[len(tests)-1].bsp)
+	require.ErrorIs(t, err, errVaultRequiresConfigServer)
 }
 
 func TestGatewayController_GetSecretData_ErrorCases(t *testing.T) {
@@ -2145,10 +2569,10 @@ func TestGatewayController_reconcileFilterMCPConfigSecret(t *testing.T) {
 	const someNamespace = "some-namespace"
 	configName := FilterConfigSecretPerGatewayName("gw", gwNamespace)
 
-	effective, err := c.reconcileFilterConfigSecret(t.Context(), configName, someNamespace, nil, nil, "mcp-uuid", nil)
+	effective, err := c.reconcileFilterConfigSecret(t.Context(), nil, configName, someNamespace, nil, nil, "mcp-uuid", nil)
 	require.NoError(t, err)
 	require.False(t, effective) // No MCP routes, so not effective.
-	effective, err = c.reconcileFilterConfigSecret(t.Context(), configName, someNamespace, nil, mcpRoutes, "mcp-uuid", nil)
+	effective, err = c.reconcileFilterConfigSecret(t.Context(), nil, configName, someNamespace, nil, mcpRoutes, "mcp-uuid", nil)
 	require.NoError(t, err)
 	require.True(t, effective)
 
@@ -2239,6 +2663,677 @@ func Test_mcpConfig_ForwardHeaders(t *testing.T) {
 	require.Empty(t, backendB.ForwardHeaders)
 }
 
+func Test_mcpConfig_RateLimit(t *testing.T) {
+	mcpRoutes := []aigv1b1.MCPRoute{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "ns"},
+			Spec: aigv1b1.MCPRouteSpec{
+				BackendRefs: []aigv1b1.MCPRouteBackendRef{
+					{BackendObjectReference: gwapiv1.BackendObjectReference{Name: gwapiv1.ObjectName("backendA")}},
+				},
+				RateLimit: &aigv1b1.MCPRouteRateLimit{
+					Rules: []aigv1b1.MCPRateLimitRule{
+						{
+							Requests: &aigv1b1.MCPRateLimitValue{Limit: 100, Unit: aigv1b1.MCPRateLimitUnitMinute},
+						},
+						{
+							Backend: ptr.To("backendA"),
+							Tool:    ptr.To("search"),
+							ClientKey: &aigv1b1.MCPRateLimitClientKey{
+								Type:   aigv1b1.MCPRateLimitClientKeyTypeHeader,
+								Header: ptr.To("x-user-id"),
+							},
+							Requests:       &aigv1b1.MCPRateLimitValue{Limit: 5, Unit: aigv1b1.MCPRateLimitUnitSecond},
+							MaxConcurrency: ptr.To[uint32](2),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	mc, effective := mcpConfig(mcpRoutes)
+	require.True(t, effective)
+	require.Len(t, mc.Routes, 1)
+	require.Equal(t, []filterapi.MCPRateLimitRule{
+		{
+			Requests: &filterapi.MCPRateLimitValue{Limit: 100, Unit: "Minute"},
+		},
+		{
+			Backend: "backendA",
+			Tool:    "search",
+			ClientKey: &filterapi.MCPRateLimitClientKey{
+				Type:   filterapi.MCPRateLimitClientKeyTypeHeader,
+				Header: "x-user-id",
+			},
+			Requests:       &filterapi.MCPRateLimitValue{Limit: 5, Unit: "Second"},
+			MaxConcurrency: 2,
+		},
+	}, mc.Routes[0].RateLimits)
+}
+
+func Test_mcpConfig_Stdio(t *testing.T) {
+	mcpRoutes := []aigv1b1.MCPRoute{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "ns"},
+			Spec: aigv1b1.MCPRouteSpec{
+				BackendRefs: []aigv1b1.MCPRouteBackendRef{
+					{
+						BackendObjectReference: gwapiv1.BackendObjectReference{Name: gwapiv1.ObjectName("stdio")},
+						Stdio: &aigv1b1.MCPStdioServer{
+							Command:            "npx",
+							Args:               []string{"-y", "server"},
+							Env:                []aigv1b1.MCPStdioEnvVar{{Name: "FOO", Value: "bar"}},
+							Isolation:          aigv1b1.MCPStdioIsolationPerSession,
+							MaxProcesses:       ptr.To[int32](4),
+							SessionIdleTimeout: ptr.To(gwapiv1.Duration("10m")),
+							Resources: &aigv1b1.MCPStdioResources{
+								Memory:  ptr.To(resource.MustParse("256Mi")),
+								CPUTime: ptr.To(gwapiv1.Duration("1h")),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	mc, effective := mcpConfig(mcpRoutes)
+	require.True(t, effective)
+	require.Len(t, mc.Routes, 1)
+	require.Len(t, mc.Routes[0].Backends, 1)
+	require.Equal(t, &filterapi.MCPStdioBackend{
+		Command:            "npx",
+		Args:               []string{"-y", "server"},
+		Env:                map[string]string{"FOO": "bar"},
+		PerSession:         true,
+		MaxProcesses:       4,
+		SessionIdleTimeout: 10 * time.Minute,
+		MemoryLimitBytes:   256 * 1024 * 1024,
+		CPUTimeLimit:       time.Hour,
+	}, mc.Routes[0].Backends[0].Stdio)
+}
+
+func Test_mcpConfig_LegacySSE(t *testing.T) {
+	mcpRoutes := []aigv1b1.MCPRoute{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "ns"},
+			Spec: aigv1b1.MCPRouteSpec{
+				LegacySSE: &aigv1b1.MCPRouteLegacySSE{MessagePath: "/custom/messages"},
+				BackendRefs: []aigv1b1.MCPRouteBackendRef{
+					{
+						BackendObjectReference: gwapiv1.BackendObjectReference{Name: gwapiv1.ObjectName("sse")},
+						Transport:              aigv1b1.MCPBackendTransportSSE,
+					},
+					{
+						BackendObjectReference: gwapiv1.BackendObjectReference{Name: gwapiv1.ObjectName("sse-path")},
+						Path:                   ptr.To("/sse"),
+						Transport:              aigv1b1.MCPBackendTransportSSE,
+					},
+					{
+						BackendObjectReference: gwapiv1.BackendObjectReference{Name: gwapiv1.ObjectName("streamable")},
+						Transport:              aigv1b1.MCPBackendTransportStreamableHTTP,
+					},
+				},
+			},
+		},
+	}
+
+	mc, effective := mcpConfig(mcpRoutes)
+	require.True(t, effective)
+	require.Len(t, mc.Routes, 1)
+	require.Equal(t, &filterapi.MCPLegacySSE{SSEPath: "/sse", MessagePath: "/custom/messages"}, mc.Routes[0].LegacySSE)
+	require.Len(t, mc.Routes[0].Backends, 3)
+	require.Equal(t, filterapi.MCPBackendTransportSSE, mc.Routes[0].Backends[0].Transport)
+	require.Equal(t, "/mcp", mc.Routes[0].Backends[0].Path)
+	require.Equal(t, filterapi.MCPBackendTransportSSE, mc.Routes[0].Backends[1].Transport)
+	require.Equal(t, "/sse", mc.Routes[0].Backends[1].Path)
+	require.Empty(t, mc.Routes[0].Backends[2].Transport)
+	require.Empty(t, mc.Routes[0].Backends[2].Path)
+}
+
+func Test_mcpConfig_ToolValidation(t *testing.T) {
+	newRoute := func(name string, v *aigv1b1.MCPRouteToolValidation) aigv1b1.MCPRoute {
+		return aigv1b1.MCPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Spec: aigv1b1.MCPRouteSpec{
+				ToolValidation: v,
+				BackendRefs: []aigv1b1.MCPRouteBackendRef{
+					{BackendObjectReference: gwapiv1.BackendObjectReference{Name: gwapiv1.ObjectName("backend")}},
+				},
+			},
+		}
+	}
+	mc, effective := mcpConfig([]aigv1b1.MCPRoute{
+		newRoute("default", nil),
+		newRoute("output", &aigv1b1.MCPRouteToolValidation{StructuredContent: ptr.To(true)}),
+		newRoute("disabled", &aigv1b1.MCPRouteToolValidation{Arguments: ptr.To(false)}),
+	})
+	require.True(t, effective)
+	require.Len(t, mc.Routes, 3)
+	require.Nil(t, mc.Routes[0].ToolValidation)
+	require.Equal(t, &filterapi.MCPToolValidation{Arguments: true, StructuredContent: true}, mc.Routes[1].ToolValidation)
+	require.Equal(t, &filterapi.MCPToolValidation{}, mc.Routes[2].ToolValidation)
+}
+
+func Test_mcpConfig_ToolSearch(t *testing.T) {
+	newRoute := func(name string, v *aigv1b1.MCPRouteToolSearch) aigv1b1.MCPRoute {
+		return aigv1b1.MCPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Spec: aigv1b1.MCPRouteSpec{
+				ToolSearch: v,
+				BackendRefs: []aigv1b1.MCPRouteBackendRef{
+					{BackendObjectReference: gwapiv1.BackendObjectReference{Name: gwapiv1.ObjectName("backend")}},
+				},
+			},
+		}
+	}
+	mc, _ := mcpConfig([]aigv1b1.MCPRoute{
+		newRoute("disabled", nil),
+		newRoute("default", &aigv1b1.MCPRouteToolSearch{}),
+		newRoute("custom", &aigv1b1.MCPRouteToolSearch{MaxResults: ptr.To[int32](3)}),
+	})
+	require.Len(t, mc.Routes, 3)
+	require.Nil(t, mc.Routes[0].ToolSearch)
+	require.Equal(t, &filterapi.MCPToolSearch{MaxResults: 10}, mc.Routes[1].ToolSearch)
+	require.Equal(t, &filterapi.MCPToolSearch{MaxResults: 3}, mc.Routes[2].ToolSearch)
+}
+
+func Test_mcpConfig_HealthCheck(t *testing.T) {
+	newRoute := func(name string, hc *aigv1b1.MCPRouteHealthCheck) aigv1b1.MCPRoute {
+		return aigv1b1.MCPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Spec: aigv1b1.MCPRouteSpec{
+				HealthCheck: hc,
+				BackendRefs: []aigv1b1.MCPRouteBackendRef{
+					{BackendObjectReference: gwapiv1.BackendObjectReference{Name: gwapiv1.ObjectName("backend")}},
+				},
+			},
+		}
+	}
+	mc, _ := mcpConfig([]aigv1b1.MCPRoute{
+		newRoute("disabled", nil),
+		newRoute("default", &aigv1b1.MCPRouteHealthCheck{}),
+		newRoute("custom", &aigv1b1.MCPRouteHealthCheck{
+			Interval:           ptr.To(gwapiv1.Duration("1m")),
+			Timeout:            ptr.To(gwapiv1.Duration("2s")),
+			UnhealthyThreshold: ptr.To[int32](1),
+			EjectionTime:       ptr.To(gwapiv1.Duration("5m")),
+		}),
+	})
+	require.Len(t, mc.Routes, 3)
+	require.Nil(t, mc.Routes[0].HealthCheck)
+	require.Equal(t, &filterapi.MCPHealthCheck{
+		Interval: 10 * time.Second, Timeout: 5 * time.Second, UnhealthyThreshold: 3, EjectionTime: 30 * time.Second,
+	}, mc.Routes[1].HealthCheck)
+	require.Equal(t, &filterapi.MCPHealthCheck{
+		Interval: time.Minute, Timeout: 2 * time.Second, UnhealthyThreshold: 1, EjectionTime: 5 * time.Minute,
+	}, mc.Routes[2].HealthCheck)
+}
+
+func Test_mcpServerRequestPolicy(t *testing.T) {
+	mc, _ := mcpConfig([]aigv1b1.MCPRoute{{
+		ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "ns"},
+		Spec: aigv1b1.MCPRouteSpec{
+			BackendRefs: []aigv1b1.MCPRouteBackendRef{
+				{BackendObjectReference: gwapiv1.BackendObjectReference{Name: "none"}},
+				{
+					BackendObjectReference: gwapiv1.BackendObjectReference{Name: "default"},
+					ServerRequests: &aigv1b1.MCPBackendServerRequests{
+						Sampling:    &aigv1b1.MCPSamplingPolicy{},
+						Elicitation: &aigv1b1.MCPElicitationPolicy{},
+					},
+				},
+				{
+					BackendObjectReference: gwapiv1.BackendObjectReference{Name: "custom"},
+					ServerRequests: &aigv1b1.MCPBackendServerRequests{
+						Sampling: &aigv1b1.MCPSamplingPolicy{
+							Action:         aigv1b1.MCPServerRequestActionAudit,
+							MaxTokens:      ptr.To[int32](100),
+							IncludeContext: ptr.To("none"),
+						},
+						Elicitation: &aigv1b1.MCPElicitationPolicy{
+							Action:       aigv1b1.MCPServerRequestActionDeny,
+							DeniedFields: []string{"pin"},
+						},
+					},
+				},
+			},
+		},
+	}})
+	require.Len(t, mc.Routes, 1)
+	backends := mc.Routes[0].Backends
+	require.Len(t, backends, 3)
+	require.Nil(t, backends[0].ServerRequests)
+	require.Equal(t, &filterapi.MCPServerRequestPolicy{
+		Sampling: &filterapi.MCPSamplingPolicy{Action: filterapi.MCPServerRequestActionAllow},
+		Elicitation: &filterapi.MCPElicitationPolicy{
+			Action:       filterapi.MCPServerRequestActionAllow,
+			DeniedFields: defaultMCPElicitationDeniedFields,
+		},
+	}, backends[1].ServerRequests)
+	require.Equal(t, &filterapi.MCPServerRequestPolicy{
+		Sampling: &filterapi.MCPSamplingPolicy{
+			Action: filterapi.MCPServerRequestActionAudit, MaxTokens: 100, IncludeContext: "none",
+		},
+		Elicitation: &filterapi.MCPElicitationPolicy{Action: filterapi.MCPServerRequestActionDeny, DeniedFields: []string{"pin"}},
+	}, backends[2].ServerRequests)
+}
+
+func TestGatewayController_toolExecutions(t *testing.T) {
+	c := NewGatewayController(requireNewFakeClientWithIndexes(t), fake2.NewClientset(), ctrl.Log,
+		"docker.io/envoyproxy/ai-gateway-extproc:latest", "info", false, nil, true)
+	newRoute := func(name string, te *aigv1b1.AIGatewayRouteToolExecution) aigv1b1.AIGatewayRoute {
+		return aigv1b1.AIGatewayRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Spec:       aigv1b1.AIGatewayRouteSpec{ToolExecution: te},
+		}
+	}
+	routes := []aigv1b1.AIGatewayRoute{
+		newRoute("disabled", nil),
+		newRoute("default", &aigv1b1.AIGatewayRouteToolExecution{MCPRouteName: "tools"}),
+		newRoute("custom", &aigv1b1.AIGatewayRouteToolExecution{
+			MCPRouteName: "tools", MaxIterations: ptr.To[int32](2), Timeout: ptr.To(gwapiv1.Duration("2m")),
+		}),
+		newRoute("unattached", &aigv1b1.AIGatewayRouteToolExecution{MCPRouteName: "other"}),
+		newRoute("oauth", &aigv1b1.AIGatewayRouteToolExecution{MCPRouteName: "oauth-tools"}),
+		newRoute("api-key", &aigv1b1.AIGatewayRouteToolExecution{MCPRouteName: "api-key-tools"}),
+	}
+	newMCPRoute := func(name string, sp *aigv1b1.MCPRouteSecurityPolicy) aigv1b1.MCPRoute {
+		return aigv1b1.MCPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Spec:       aigv1b1.MCPRouteSpec{SecurityPolicy: sp},
+		}
+	}
+	mcpRoutes := []aigv1b1.MCPRoute{
+		newMCPRoute("tools", nil),
+		// The MCPRoutes authenticating their clients are not supported, since the tool calls would skip the authentication.
+		newMCPRoute("oauth-tools", &aigv1b1.MCPRouteSecurityPolicy{OAuth: &aigv1b1.MCPRouteOAuth{Issuer: "https://issuer"}}),
+		newMCPRoute("api-key-tools", &aigv1b1.MCPRouteSecurityPolicy{APIKeyAuth: &egv1a1.APIKeyAuth{}}),
+	}
+	mc := &filterapi.MCPConfig{Routes: []filterapi.MCPRoute{{Name: "ns/tools"}, {Name: "ns/oauth-tools"}, {Name: "ns/api-key-tools"}}}
+	require.Equal(t, []filterapi.ToolExecution{
+		{RouteName: "ns/default", MCPRoute: "ns/tools", MaxIterations: 5, Timeout: time.Minute},
+		{RouteName: "ns/custom", MCPRoute: "ns/tools", MaxIterations: 2, Timeout: 2 * time.Minute},
+	}, c.toolExecutions(routes, mcpRoutes, mc))
+	require.Empty(t, c.toolExecutions(routes, mcpRoutes, nil))
+}
+
+func TestGatewayController_virtualKeys(t *testing.T) {
+	fakeClient := requireNewFakeClientWithIndexes(t)
+	kube := fake2.NewClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ai-eg-vk-team-a", Namespace: "ns"},
+		Data:       map[string][]byte{"key": []byte("sk-aigw-a")},
+	})
+	c := NewGatewayController(fakeClient, kube, ctrl.Log,
+		"docker.io/envoyproxy/ai-gateway-extproc:latest", "info", false, nil, true)
+	targetRefs := func(names ...string) (refs []gwapiv1a2.LocalPolicyTargetReference) {
+		for _, name := range names {
+			refs = append(refs, gwapiv1a2.LocalPolicyTargetReference{
+				Group: "aigateway.envoyproxy.io", Kind: "AIGatewayRoute", Name: gwapiv1.ObjectName(name),
+			})
+		}
+		return
+	}
+	expiresAt := metav1.NewTime(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	for _, vk := range []*aigv1a1.VirtualKey{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: "ns"},
+			Spec: aigv1a1.VirtualKeySpec{
+				TargetRefs:    targetRefs("route1", "route2"),
+				AllowedModels: []string{"gpt-4o"},
+				ExpiresAt:     &expiresAt,
+				Labels:        map[string]string{"team": "a"},
+				Budget: &aigv1a1.VirtualKeyBudget{
+					Limit: 1000, Window: "7d", CostExpression: ptr.To("input_tokens + output_tokens * 2"),
+				},
+			},
+		},
+		// The key of this one is not generated yet.
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-b", Namespace: "ns"},
+			Spec:       aigv1a1.VirtualKeySpec{TargetRefs: targetRefs("route1")},
+		},
+	} {
+		require.NoError(t, fakeClient.Create(t.Context(), vk))
+	}
+
+	routes := []aigv1b1.AIGatewayRoute{
+		{ObjectMeta: metav1.ObjectMeta{Name: "route1", Namespace: "ns"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "route2", Namespace: "ns"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "route3", Namespace: "ns"}},
+	}
+	sum := sha256.Sum256([]byte("sk-aigw-a"))
+	require.Equal(t, []filterapi.VirtualKey{
+		{
+			Name:      "ns/team-a",
+			KeyHash:   hex.EncodeToString(sum[:]),
+			Routes:    []string{"ns/route1", "ns/route2"},
+			Models:    []string{"gpt-4o"},
+			ExpiresAt: expiresAt.Time,
+			Labels:    map[string]string{"team": "a"},
+			Budget: &filterapi.VirtualKeyBudget{
+				Limit: 1000, Window: 7 * 24 * time.Hour, CEL: "input_tokens + output_tokens * 2",
+			},
+		},
+	}, c.virtualKeys(t.Context(), routes))
+	require.Empty(t, c.virtualKeys(t.Context(), routes[2:]))
+}
+
+func TestGatewayController_resolveMCPStdioEnvFrom(t *testing.T) {
+	kube := fake2.NewClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "env", Namespace: "ns"},
+		Data:       map[string][]byte{"TOKEN": []byte("secret"), "FOO": []byte("overridden")},
+	}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "env", Namespace: "other"},
+		Data:       map[string][]byte{"TOKEN": []byte("other")},
+	})
+	c := NewGatewayController(requireNewFakeClientWithIndexes(t), kube, ctrl.Log,
+		"docker.io/envoyproxy/ai-gateway-extproc:latest", "info", false, nil, true)
+	c.enableMCPStdioServers = true
+
+	newRef := func(name, secretName string) aigv1b1.MCPRouteBackendRef {
+		return aigv1b1.MCPRouteBackendRef{
+			BackendObjectReference: gwapiv1.BackendObjectReference{Name: gwapiv1.ObjectName(name)},
+			Stdio: &aigv1b1.MCPStdioServer{
+				Command: "server",
+				Env:     []aigv1b1.MCPStdioEnvVar{{Name: "FOO", Value: "bar"}},
+				EnvFrom: &gwapiv1.SecretObjectReference{Name: gwapiv1.ObjectName(secretName)},
+			},
+		}
+	}
+	mcpRoutes := []aigv1b1.MCPRoute{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "ns"},
+			Spec: aigv1b1.MCPRouteSpec{
+				BackendRefs: []aigv1b1.MCPRouteBackendRef{newRef("found", "env"), newRef("missing", "missing"), newRef("other-namespace", "env")},
+			},
+		},
+	}
+	mcpRoutes[0].Spec.BackendRefs[2].Stdio.EnvFrom.Namespace = ptr.To(gwapiv1.Namespace("other"))
+	mc, _ := mcpConfig(mcpRoutes)
+	c.resolveMCPStdioEnvFrom(t.Context(), mcpRoutes, mc)
+
+	// The backends whose Secret is missing or in another namespace are removed, and the explicit variables take precedence.
+	require.Len(t, mc.Routes[0].Backends, 1)
+	require.Equal(t, filterapi.MCPBackendName("found"), mc.Routes[0].Backends[0].Name)
+	require.Equal(t, map[string]string{"FOO": "bar", "TOKEN": "secret"}, mc.Routes[0].Backends[0].Stdio.Env)
+
+	// All the stdio backends are removed when the stdio MCP servers are disabled.
+	c.enableMCPStdioServers = false
+	mc, _ = mcpConfig(mcpRoutes)
+	c.resolveMCPStdioEnvFrom(t.Context(), mcpRoutes, mc)
+	require.Empty(t, mc.Routes[0].Backends)
+}
+
+func TestGatewayController_resolveMCPAIGatewayRouteBackends(t *testing.T) {
+	c := NewGatewayController(requireNewFakeClientWithIndexes(t), fake2.NewClientset(), ctrl.Log,
+		"docker.io/envoyproxy/ai-gateway-extproc:latest", "info", false, nil, true)
+
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "ns"},
+		Spec: gwapiv1.GatewaySpec{Listeners: []gwapiv1.Listener{
+			{Name: "tcp", Protocol: gwapiv1.TCPProtocolType, Port: 9000},
+			{Name: "http", Protocol: gwapiv1.HTTPProtocolType, Port: 80},
+		}},
+	}
+	aiGatewayRoutes := []aigv1b1.AIGatewayRoute{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "llm", Namespace: "ns"},
+			Spec: aigv1b1.AIGatewayRouteSpec{
+				ParentRefs: []gwapiv1.ParentReference{{Name: "gw"}},
+				Hostnames:  []gwapiv1.Hostname{"*.example.com"},
+				Rules: []aigv1b1.AIGatewayRouteRule{
+					{Matches: []aigv1b1.AIGatewayRouteRuleMatch{
+						{Headers: []gwapiv1.HTTPHeaderMatch{{Name: internalapi.ModelNameHeaderKeyDefault, Value: "gpt-4o"}}},
+						{Headers: []gwapiv1.HTTPHeaderMatch{{Name: internalapi.ModelNameHeaderKeyDefault, Value: "embedder"}}},
+						{Headers: []gwapiv1.HTTPHeaderMatch{{Name: internalapi.ModelNameHeaderKeyDefault, Value: "gpt-4o"}}},
+					}},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-gateway", Namespace: "ns"},
+			Spec:       aigv1b1.AIGatewayRouteSpec{ParentRefs: []gwapiv1.ParentReference{{Name: "other"}}},
+		},
+	}
+	newRef := func(name, route string, chatModels ...string) aigv1b1.MCPRouteBackendRef {
+		return aigv1b1.MCPRouteBackendRef{
+			BackendObjectReference: gwapiv1.BackendObjectReference{Name: gwapiv1.ObjectName(name)},
+			AIGatewayRoute: &aigv1b1.MCPAIGatewayRouteBackend{
+				Name:           gwapiv1.ObjectName(route),
+				ChatModels:     chatModels,
+				EmbeddingModel: ptr.To("embedder"),
+			},
+		}
+	}
+	mcpRoutes := []aigv1b1.MCPRoute{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "ns"},
+			Spec: aigv1b1.MCPRouteSpec{
+				BackendRefs: []aigv1b1.MCPRouteBackendRef{
+					newRef("all", "llm"),
+					newRef("selected", "llm", "gpt-4o-mini"),
+					newRef("missing", "missing"),
+					newRef("not-attached", "other-gateway"),
+				},
+			},
+		},
+	}
+	mc, _ := mcpConfig(mcpRoutes)
+	c.resolveMCPAIGatewayRouteBackends(gw, aiGatewayRoutes, mcpRoutes, mc)
+
+	// The backends whose AIGatewayRoute is missing or not attached to the Gateway are removed.
+	require.Equal(t, []filterapi.MCPBackend{
+		{Name: "all", AIGatewayRoute: &filterapi.MCPAIGatewayRouteBackend{
+			URL: "http://127.0.0.1:10080", Host: "mcp.example.com", ChatModels: []string{"gpt-4o"}, EmbeddingModel: "embedder",
+		}},
+		{Name: "selected", AIGatewayRoute: &filterapi.MCPAIGatewayRouteBackend{
+			URL: "http://127.0.0.1:10080", Host: "mcp.example.com", ChatModels: []string{"gpt-4o-mini"}, EmbeddingModel: "embedder",
+		}},
+	}, mc.Routes[0].Backends)
+}
+
+func TestGatewayController_resolveMCPOpenAPIBackends(t *testing.T) {
+	const doc = `openapi: 3.0.0
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+`
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openapi.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(doc))
+	}))
+	t.Cleanup(docServer.Close)
+	kube := fake2.NewClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "pets", Namespace: "ns"},
+			Data:       map[string]string{"openapi.yaml": doc, "spec.json": `{"openapi":"3.1.0","paths":{"/a":{"post":{}}}}`, "invalid": "swagger: '2.0'"},
+		},
+	)
+	c := NewGatewayController(requireNewFakeClientWithIndexes(t), kube, ctrl.Log,
+		"docker.io/envoyproxy/ai-gateway-extproc:latest", "info", false, nil, true)
+
+	newRef := func(name string, openAPI aigv1b1.MCPOpenAPIBackend) aigv1b1.MCPRouteBackendRef {
+		return aigv1b1.MCPRouteBackendRef{
+			BackendObjectReference: gwapiv1.BackendObjectReference{Name: gwapiv1.ObjectName(name)},
+			OpenAPI:                &openAPI,
+		}
+	}
+	mcpRoutes := []aigv1b1.MCPRoute{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "ns"},
+			Spec: aigv1b1.MCPRouteSpec{
+				BackendRefs: []aigv1b1.MCPRouteBackendRef{
+					newRef("configmap", aigv1b1.MCPOpenAPIBackend{ConfigMapRef: &aigv1b1.MCPOpenAPIConfigMapKeyReference{Name: "pets"}}),
+					newRef("configmap-key", aigv1b1.MCPOpenAPIBackend{ConfigMapRef: &aigv1b1.MCPOpenAPIConfigMapKeyReference{Name: "pets", Key: "spec.json"}}),
+					newRef("url", aigv1b1.MCPOpenAPIBackend{URL: ptr.To(docServer.URL + "/openapi.yaml")}),
+					newRef("missing-configmap", aigv1b1.MCPOpenAPIBackend{ConfigMapRef: &aigv1b1.MCPOpenAPIConfigMapKeyReference{Name: "missing"}}),
+					newRef("missing-key", aigv1b1.MCPOpenAPIBackend{ConfigMapRef: &aigv1b1.MCPOpenAPIConfigMapKeyReference{Name: "pets", Key: "missing"}}),
+					newRef("invalid", aigv1b1.MCPOpenAPIBackend{ConfigMapRef: &aigv1b1.MCPOpenAPIConfigMapKeyReference{Name: "pets", Key: "invalid"}}),
+					newRef("missing-url", aigv1b1.MCPOpenAPIBackend{URL: ptr.To(docServer.URL + "/missing")}),
+				},
+			},
+		},
+	}
+	gatewayEvents := make(chan event.GenericEvent, 10)
+	c.gatewayEventChan = gatewayEvents
+	gw := &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "ns"}}
+
+	// The documents referenced by URL are fetched in the background, so their backends are removed until then.
+	mc, _ := mcpConfig(mcpRoutes)
+	c.resolveMCPOpenAPIBackends(t.Context(), gw, mcpRoutes, mc)
+	require.Len(t, mc.Routes[0].Backends, 2)
+	// The Gateway is reconciled again once each document is fetched.
+	for range 2 {
+		select {
+		case e := <-gatewayEvents:
+			require.Equal(t, client.ObjectKeyFromObject(gw), client.ObjectKeyFromObject(e.Object))
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for the Gateway to be reconciled again")
+		}
+	}
+
+	mc, _ = mcpConfig(mcpRoutes)
+	c.resolveMCPOpenAPIBackends(t.Context(), gw, mcpRoutes, mc)
+
+	// The backends whose document cannot be loaded or parsed are removed.
+	backends := mc.Routes[0].Backends
+	require.Len(t, backends, 3)
+	require.Equal(t, filterapi.MCPBackendName("configmap"), backends[0].Name)
+	require.Equal(t, "/v1", backends[0].OpenAPI.BasePath)
+	require.Len(t, backends[0].OpenAPI.Operations, 1)
+	require.Equal(t, "listPets", backends[0].OpenAPI.Operations[0].Name)
+	require.Equal(t, filterapi.MCPBackendName("configmap-key"), backends[1].Name)
+	require.Equal(t, "post_a", backends[1].OpenAPI.Operations[0].Name)
+	require.Equal(t, filterapi.MCPBackendName("url"), backends[2].Name)
+	require.Equal(t, backends[0].OpenAPI, backends[2].OpenAPI)
+
+	// The documents are dropped once the Gateway doesn't use them anymore.
+	c.resolveMCPOpenAPIBackends(t.Context(), gw, nil, nil)
+	c.openAPIDocuments.mu.Lock()
+	defer c.openAPIDocuments.mu.Unlock()
+	require.Empty(t, c.openAPIDocuments.entries)
+}
+
+func Test_aiGatewayRouteListener(t *testing.T) {
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "ns"},
+		Spec: gwapiv1.GatewaySpec{Listeners: []gwapiv1.Listener{
+			{Name: "http", Protocol: gwapiv1.HTTPProtocolType, Port: 8080},
+			{Name: "https", Protocol: gwapiv1.HTTPSProtocolType, Port: 443, Hostname: ptr.To[gwapiv1.Hostname]("ai.example.com")},
+		}},
+	}
+	for _, tc := range []struct {
+		name      string
+		parentRef gwapiv1.ParentReference
+		url, host string
+	}{
+		{name: "first listener", parentRef: gwapiv1.ParentReference{Name: "gw"}, url: "http://127.0.0.1:8080"},
+		{
+			name:      "section name",
+			parentRef: gwapiv1.ParentReference{Name: "gw", SectionName: ptr.To[gwapiv1.SectionName]("https")},
+			url:       "https://127.0.0.1:10443", host: "ai.example.com",
+		},
+		{
+			name:      "port",
+			parentRef: gwapiv1.ParentReference{Name: "gw", Port: ptr.To[gwapiv1.PortNumber](443)},
+			url:       "https://127.0.0.1:10443", host: "ai.example.com",
+		},
+		{name: "other namespace", parentRef: gwapiv1.ParentReference{Name: "gw", Namespace: ptr.To[gwapiv1.Namespace]("other")}},
+		{name: "unknown section", parentRef: gwapiv1.ParentReference{Name: "gw", SectionName: ptr.To[gwapiv1.SectionName]("grpc")}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			route := &aigv1b1.AIGatewayRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "llm", Namespace: "ns"},
+				Spec:       aigv1b1.AIGatewayRouteSpec{ParentRefs: []gwapiv1.ParentReference{tc.parentRef}},
+			}
+			url, host := aiGatewayRouteListener(gw, route)
+			require.Equal(t, tc.url, url)
+			require.Equal(t, tc.host, host)
+		})
+	}
+}
+
+func TestGatewayController_resolveMCPBackendCredentials(t *testing.T) {
+	kube := fake2.NewClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "client", Namespace: "ns"},
+			Data:       map[string][]byte{"client-secret": []byte("s3cr3t")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "users", Namespace: "ns"},
+			Data:       map[string][]byte{"alice": []byte("alice-pat"), "bob": []byte("bob-pat")},
+		},
+	)
+	c := NewGatewayController(requireNewFakeClientWithIndexes(t), kube, ctrl.Log,
+		"docker.io/envoyproxy/ai-gateway-extproc:latest", "info", false, nil, true)
+
+	newTokenExchangeRef := func(name, secretName string) aigv1b1.MCPRouteBackendRef {
+		return aigv1b1.MCPRouteBackendRef{
+			BackendObjectReference: gwapiv1.BackendObjectReference{Name: gwapiv1.ObjectName(name)},
+			SecurityPolicy: &aigv1b1.MCPBackendSecurityPolicy{
+				TokenExchange: &aigv1b1.MCPBackendTokenExchange{
+					TokenEndpoint:   "https://auth.example.com/token",
+					ClientID:        "gateway",
+					ClientSecretRef: &gwapiv1.SecretObjectReference{Name: gwapiv1.ObjectName(secretName)},
+					Audience:        ptr.To("github"),
+					Scopes:          []string{"repo"},
+				},
+			},
+		}
+	}
+	newUserCredentialsRef := func(name, secretName string) aigv1b1.MCPRouteBackendRef {
+		return aigv1b1.MCPRouteBackendRef{
+			BackendObjectReference: gwapiv1.BackendObjectReference{Name: gwapiv1.ObjectName(name)},
+			SecurityPolicy: &aigv1b1.MCPBackendSecurityPolicy{
+				UserCredentials: &aigv1b1.MCPBackendUserCredentials{
+					SecretRef: gwapiv1.SecretObjectReference{Name: gwapiv1.ObjectName(secretName)},
+					Header:    ptr.To("X-Token"),
+				},
+			},
+		}
+	}
+	mcpRoutes := []aigv1b1.MCPRoute{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "ns"},
+			Spec: aigv1b1.MCPRouteSpec{
+				BackendRefs: []aigv1b1.MCPRouteBackendRef{
+					newTokenExchangeRef("github", "client"),
+					newTokenExchangeRef("github-missing", "missing"),
+					newUserCredentialsRef("jira", "users"),
+					newUserCredentialsRef("jira-missing", "missing"),
+				},
+			},
+		},
+	}
+	mc, _ := mcpConfig(mcpRoutes)
+	c.resolveMCPBackendCredentials(t.Context(), mcpRoutes, mc)
+
+	// The backends whose Secret is missing are removed.
+	require.Len(t, mc.Routes[0].Backends, 2)
+	require.Equal(t, &filterapi.MCPBackendCredential{
+		Header: "Authorization",
+		TokenExchange: &filterapi.MCPTokenExchange{
+			TokenEndpoint: "https://auth.example.com/token",
+			ClientID:      "gateway",
+			ClientSecret:  "s3cr3t",
+			Audience:      "github",
+			Scopes:        []string{"repo"},
+		},
+	}, mc.Routes[0].Backends[0].Credential)
+	require.Equal(t, &filterapi.MCPBackendCredential{
+		Header:          "X-Token",
+		UserCredentials: map[string]string{"alice": "alice-pat", "bob": "bob-pat"},
+	}, mc.Routes[0].Backends[1].Credential)
+}
+
 func Test_mergeHeaderMutations(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -2616,7 +3711,7 @@ func TestGatewayController_reconcileFilterConfigSecret_GlobalDefaults(t *testing
 
 			const someNamespace = "some-namespace"
 			configName := FilterConfigSecretPerGatewayName("gw", gwNamespace)
-			effective, err := c.reconcileFilterConfigSecret(t.Context(), configName, someNamespace, tt.routes, nil, "test-uuid", tt.globalCosts)
+			effective, err := c.reconcileFilterConfigSecret(t.Context(), nil, configName, someNamespace, tt.routes, nil, "test-uuid", tt.globalCosts)
 			require.NoError(t, err)
 			require.True(t, effective)
 
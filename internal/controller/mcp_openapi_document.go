@@ -0,0 +1,168 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// openAPIDocumentTTL is how often the OpenAPI documents fetched by URL are fetched again.
+	openAPIDocumentTTL = 5 * time.Minute
+	// openAPIDocumentFetchTimeout is the timeout of the requests fetching the OpenAPI documents.
+	openAPIDocumentFetchTimeout = 10 * time.Second
+	// maxOpenAPIDocumentSize is the maximum size of the OpenAPI documents fetched by URL.
+	maxOpenAPIDocumentSize = 8 << 20
+)
+
+// errOpenAPIDocumentPending is returned for the OpenAPI documents that were not fetched yet.
+var errOpenAPIDocumentPending = errors.New("the OpenAPI document is not fetched yet")
+
+// openAPIDocumentCache holds the OpenAPI documents of the MCP backends referenced by URL.
+//
+// The documents are fetched in the background rather than when the Gateways are reconciled, so that a slow or
+// unreachable server doesn't hold up the reconciliation, and are fetched again every ttl while a Gateway uses them.
+// The Gateways using a document are reconciled again when it changes.
+type openAPIDocumentCache struct {
+	logger logr.Logger
+	client *http.Client
+	ttl    time.Duration
+	// onChange is called with the Gateways using a document when it changed.
+	onChange func(gateways []types.NamespacedName)
+
+	mu      sync.Mutex
+	entries map[string]*openAPIDocumentEntry
+}
+
+// openAPIDocumentEntry is the state of the document of a URL.
+type openAPIDocumentEntry struct {
+	// doc is the last document fetched successfully, and err is the error of the last fetch if none succeeded yet.
+	doc []byte
+	err error
+	// fetched is true once the document was fetched at least once.
+	fetched bool
+	// timer fetches the document again once it expires.
+	timer *time.Timer
+	// gateways are the Gateways using the document.
+	gateways map[types.NamespacedName]struct{}
+}
+
+func newOpenAPIDocumentCache(logger logr.Logger, onChange func(gateways []types.NamespacedName)) *openAPIDocumentCache {
+	return &openAPIDocumentCache{
+		logger:   logger,
+		client:   &http.Client{Timeout: openAPIDocumentFetchTimeout},
+		ttl:      openAPIDocumentTTL,
+		onChange: onChange,
+		entries:  make(map[string]*openAPIDocumentEntry),
+	}
+}
+
+// get returns the document of the given URL used by the given Gateway. The document is fetched in the background
+// the first time it is used, and errOpenAPIDocumentPending is returned until then.
+func (c *openAPIDocumentCache) get(gateway types.NamespacedName, url string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[url]
+	if !ok {
+		e = &openAPIDocumentEntry{gateways: make(map[types.NamespacedName]struct{})}
+		c.entries[url] = e
+		go c.fetch(url, e)
+	}
+	e.gateways[gateway] = struct{}{}
+	if !e.fetched {
+		return nil, errOpenAPIDocumentPending
+	}
+	return e.doc, e.err
+}
+
+// retain records that the given Gateway only uses the documents of the given URLs. The documents that are not used
+// by any Gateway anymore are dropped.
+func (c *openAPIDocumentCache) retain(gateway types.NamespacedName, urls []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for url, e := range c.entries {
+		if slices.Contains(urls, url) {
+			continue
+		}
+		delete(e.gateways, gateway)
+		if len(e.gateways) == 0 {
+			if e.timer != nil {
+				e.timer.Stop()
+			}
+			delete(c.entries, url)
+		}
+	}
+}
+
+// fetch fetches the document of the given entry, and schedules the next fetch.
+func (c *openAPIDocumentCache) fetch(url string, e *openAPIDocumentEntry) {
+	ctx, cancel := context.WithTimeout(context.Background(), openAPIDocumentFetchTimeout)
+	doc, err := c.fetchDocument(ctx, url)
+	cancel()
+
+	c.mu.Lock()
+	if c.entries[url] != e {
+		// The document is not used anymore.
+		c.mu.Unlock()
+		return
+	}
+	changed := !e.fetched
+	switch {
+	case err == nil:
+		changed = changed || e.err != nil || !bytes.Equal(e.doc, doc)
+		e.doc, e.err = doc, nil
+	case e.doc != nil:
+		// Keep using the last document fetched successfully.
+		c.logger.Error(err, "failed to fetch the OpenAPI document again, keeping the last one", "url", url)
+	default:
+		changed = changed || e.err == nil || e.err.Error() != err.Error()
+		e.err = err
+	}
+	e.fetched = true
+	e.timer = time.AfterFunc(c.ttl, func() { c.fetch(url, e) })
+	gateways := slices.Collect(maps.Keys(e.gateways))
+	c.mu.Unlock()
+
+	if changed && c.onChange != nil {
+		c.onChange(gateways)
+	}
+}
+
+// fetchDocument fetches the document of the given URL.
+func (c *openAPIDocumentCache) fetchDocument(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document URL: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the OpenAPI document: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch the OpenAPI document: status code %d", resp.StatusCode)
+	}
+	doc, err := io.ReadAll(io.LimitReader(resp.Body, maxOpenAPIDocumentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the OpenAPI document: %w", err)
+	}
+	if len(doc) > maxOpenAPIDocumentSize {
+		return nil, fmt.Errorf("the OpenAPI document exceeds %d bytes", maxOpenAPIDocumentSize)
+	}
+	return doc, nil
+}
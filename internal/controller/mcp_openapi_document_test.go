@@ -0,0 +1,93 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func TestOpenAPIDocumentCache(t *testing.T) {
+	var doc atomic.Pointer[string]
+	doc.Store(ptr.To("v1"))
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(*doc.Load()))
+	}))
+	t.Cleanup(server.Close)
+
+	changes := make(chan []types.NamespacedName, 10)
+	c := newOpenAPIDocumentCache(logr.Discard(), func(gateways []types.NamespacedName) { changes <- gateways })
+	c.ttl = 50 * time.Millisecond
+	t.Cleanup(func() { c.retain(types.NamespacedName{}, nil) })
+	requireChange := func(t *testing.T) []types.NamespacedName {
+		t.Helper()
+		select {
+		case gateways := <-changes:
+			return gateways
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for the document to change")
+			return nil
+		}
+	}
+	gw := types.NamespacedName{Namespace: "ns", Name: "gw"}
+	url := server.URL + "/openapi.yaml"
+
+	// The document is fetched in the background.
+	_, err := c.get(gw, url)
+	require.ErrorIs(t, err, errOpenAPIDocumentPending)
+	require.Equal(t, []types.NamespacedName{gw}, requireChange(t))
+	got, err := c.get(gw, url)
+	require.NoError(t, err)
+	require.Equal(t, "v1", string(got))
+
+	// The document is fetched again periodically, and the Gateways are notified when it changes.
+	doc.Store(ptr.To("v2"))
+	require.Equal(t, []types.NamespacedName{gw}, requireChange(t))
+	got, err = c.get(gw, url)
+	require.NoError(t, err)
+	require.Equal(t, "v2", string(got))
+
+	// The last document is kept when it cannot be fetched again.
+	fail.Store(true)
+	time.Sleep(5 * c.ttl)
+	got, err = c.get(gw, url)
+	require.NoError(t, err)
+	require.Equal(t, "v2", string(got))
+	require.Empty(t, changes)
+
+	// The errors are returned until the document is fetched once.
+	_, err = c.get(gw, server.URL+"/failing")
+	require.ErrorIs(t, err, errOpenAPIDocumentPending)
+	requireChange(t)
+	_, err = c.get(gw, server.URL+"/failing")
+	require.ErrorContains(t, err, "status code 500")
+
+	// The documents are dropped once no Gateway uses them.
+	other := types.NamespacedName{Namespace: "ns", Name: "other"}
+	_, _ = c.get(other, url)
+	c.retain(gw, nil)
+	c.mu.Lock()
+	require.Len(t, c.entries, 1)
+	require.Contains(t, c.entries, url)
+	c.mu.Unlock()
+	c.retain(other, nil)
+	c.mu.Lock()
+	require.Empty(t, c.entries)
+	c.mu.Unlock()
+}
@@ -6,8 +6,10 @@
 package controller
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	egv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
@@ -30,10 +32,25 @@ import (
 )
 
 const (
-	defaultMCPPath         = "/mcp"
-	mcpProxyBackendDummyIP = "192.0.2.42" // RFC 5737 TEST-NET-2, used as a dummy IP.
+	defaultMCPPath              = "/mcp"
+	defaultMCPLegacySSEPath     = "/sse"
+	defaultMCPLegacyMessagePath = "/messages"
+	// defaultMCPToolSearchMaxResults must match the default of MCPRouteToolSearch.MaxResults.
+	defaultMCPToolSearchMaxResults = 10
+	// defaultMCPHealthCheckInterval, defaultMCPHealthCheckTimeout, defaultMCPHealthCheckUnhealthyThreshold and
+	// defaultMCPHealthCheckEjectionTime must match the defaults of MCPRouteHealthCheck.
+	defaultMCPHealthCheckInterval           = gwapiv1.Duration("10s")
+	defaultMCPHealthCheckTimeout            = gwapiv1.Duration("5s")
+	defaultMCPHealthCheckUnhealthyThreshold = 3
+	defaultMCPHealthCheckEjectionTime       = gwapiv1.Duration("30s")
+	mcpProxyBackendDummyIP                  = "192.0.2.42" // RFC 5737 TEST-NET-2, used as a dummy IP.
 )
 
+// defaultMCPElicitationDeniedFields must match the default of MCPElicitationPolicy.DeniedFields.
+var defaultMCPElicitationDeniedFields = []string{
+	"password", "passwd", "passphrase", "secret", "token", "apikey", "credential", "privatekey", "ssn", "creditcard", "cvv",
+}
+
 // MCPRouteController implements [reconcile.TypedReconciler].
 //
 // This handles the MCPRoute resource and creates the necessary resources for the external process.
@@ -45,6 +62,8 @@ type MCPRouteController struct {
 	logger logr.Logger
 	// gatewayEventChan is a channel to send events to the gateway controller.
 	gatewayEventChan chan event.GenericEvent
+	// enableStdioServers allows the backends to run stdio MCP servers in the external processor container.
+	enableStdioServers bool
 }
 
 // NewMCPRouteController creates a new reconcile.TypedReconciler[reconcile.Request] for the MCPRoute resource.
@@ -90,6 +109,14 @@ func (c *MCPRouteController) syncMCPRoute(ctx context.Context, mcpRoute *aigv1b1
 		return nil
 	}
 
+	if !c.enableStdioServers {
+		for i := range mcpRoute.Spec.BackendRefs {
+			if ref := &mcpRoute.Spec.BackendRefs[i]; ref.Stdio != nil {
+				return fmt.Errorf("backend %s runs a stdio MCP server but stdio MCP servers are disabled in the controller", ref.Name)
+			}
+		}
+	}
+
 	// Ensure the MCP proxy Backend exists before creating/updating the HTTPRoute.
 	if err := c.ensureMCPProxyBackend(ctx, mcpRoute); err != nil {
 		return fmt.Errorf("failed to ensure MCP proxy Backend: %w", err)
@@ -124,6 +151,11 @@ func (c *MCPRouteController) syncMCPRoute(ctx context.Context, mcpRoute *aigv1b1
 	// This allows the MCP proxy to route requests to the correct backend based on the header.
 	for i := range mcpRoute.Spec.BackendRefs {
 		ref := &mcpRoute.Spec.BackendRefs[i]
+		if ref.Stdio != nil || ref.AIGatewayRoute != nil {
+			// Stdio MCP servers and the models of AIGatewayRoutes are served by the MCP proxy itself, so there is
+			// nothing to route to in Envoy.
+			continue
+		}
 		name := mcpPerBackendRefHTTPRouteName(mcpRoute.Name, ref.Name)
 		httpRoute, existing := existingPerBackendRoutes[name]
 		if !existing {
@@ -317,6 +349,20 @@ func (c *MCPRouteController) newMainHTTPRoute(dst *gwapiv1.HTTPRoute, mcpRoute *
 		},
 	}}
 
+	// Serve the legacy HTTP+SSE endpoints with the same rule, so that they are protected by the same security policy
+	// and routed to the MCP proxy.
+	if sse := mcpRoute.Spec.LegacySSE; sse != nil {
+		for _, path := range []string{cmp.Or(sse.SSEPath, defaultMCPLegacySSEPath), cmp.Or(sse.MessagePath, defaultMCPLegacyMessagePath)} {
+			rules[0].Matches = append(rules[0].Matches, gwapiv1.HTTPRouteMatch{
+				Path: &gwapiv1.HTTPPathMatch{
+					Type:  ptr.To(gwapiv1.PathMatchExact),
+					Value: ptr.To(path),
+				},
+				Headers: mcpRoute.Spec.Headers,
+			})
+		}
+	}
+
 	// Add OAuth metadata endpoints if authentication is configured.
 	if mcpRoute.Spec.SecurityPolicy != nil && mcpRoute.Spec.SecurityPolicy.OAuth != nil {
 		// OAuth 2.0 Protected Resource Metadata (RFC 9728) - serve in both root and suffix paths because different clients
@@ -440,6 +486,13 @@ func (c *MCPRouteController) newPerBackendRefHTTPRoute(ctx context.Context, dst
 		return fmt.Errorf("failed to convert MCPRouteRule to HTTPRouteRule: %w", err)
 	}
 	dst.Spec.Rules = []gwapiv1.HTTPRouteRule{mcpBackendToHTTPRouteRule}
+	if ref.OpenAPI != nil {
+		// The requests of the tool calls are sent by the MCP proxy to the paths of the operations.
+		dst.Spec.Rules = []gwapiv1.HTTPRouteRule{mcpPathPreservingHTTPRouteRule(mcpBackendToHTTPRouteRule)}
+	}
+	if ref.Transport == aigv1b1.MCPBackendTransportSSE {
+		dst.Spec.Rules = append(dst.Spec.Rules, mcpSSEMessageHTTPRouteRule(mcpBackendToHTTPRouteRule))
+	}
 
 	// Initialize labels and annotations maps if they don't exist.
 	if dst.Labels == nil {
@@ -687,6 +740,26 @@ func (c *MCPRouteController) mcpBackendRefToHTTPRouteRule(ctx context.Context, m
 	}, nil
 }
 
+// mcpSSEMessageHTTPRouteRule returns the rule routing the message requests of the legacy HTTP+SSE transport to the
+// backend. The path of the message endpoint is advertised by the backend in the SSE stream, so unlike the given rule
+// for the SSE stream, the path of the request is preserved.
+func mcpSSEMessageHTTPRouteRule(rule gwapiv1.HTTPRouteRule) gwapiv1.HTTPRouteRule {
+	rule = mcpPathPreservingHTTPRouteRule(rule)
+	rule.Matches[0].Headers = append(rule.Matches[0].Headers, gwapiv1.HTTPHeaderMatch{
+		Name: internalapi.MCPSSEMessageHeader, Value: "true",
+	})
+	return rule
+}
+
+// mcpPathPreservingHTTPRouteRule returns a copy of the given rule that doesn't rewrite the path of the requests.
+func mcpPathPreservingHTTPRouteRule(rule gwapiv1.HTTPRouteRule) gwapiv1.HTTPRouteRule {
+	rule = *rule.DeepCopy()
+	rule.Filters = slices.DeleteFunc(rule.Filters, func(f gwapiv1.HTTPRouteFilter) bool {
+		return f.Type == gwapiv1.HTTPRouteFilterURLRewrite
+	})
+	return rule
+}
+
 func mcpRouteHeaderValue(mcpRoute *aigv1b1.MCPRoute) string {
 	return fmt.Sprintf("%s/%s", mcpRoute.Namespace, mcpRoute.Name)
 }
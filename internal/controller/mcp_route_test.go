@@ -7,6 +7,7 @@ package controller
 
 import (
 	"context"
+	"slices"
 	"testing"
 
 	egv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
@@ -179,6 +180,48 @@ func TestMCPRouteController_Reconcile(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestMCPRouteController_Reconcile_StdioBackend(t *testing.T) {
+	fakeClient := requireNewFakeClientWithIndexesForMCP(t)
+	eventCh := internaltesting.NewControllerEventChan[*gwapiv1.Gateway]()
+	c := NewMCPRouteController(fakeClient, fakekube.NewClientset(), ctrl.Log, eventCh.Ch)
+	err := fakeClient.Create(t.Context(), &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "mytarget", Namespace: "default"}})
+	require.NoError(t, err)
+
+	route := &aigv1b1.MCPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "myroute", Namespace: "default"},
+		Spec: aigv1b1.MCPRouteSpec{
+			ParentRefs: []gwapiv1.ParentReference{{Name: gwapiv1.ObjectName("mytarget")}},
+			BackendRefs: []aigv1b1.MCPRouteBackendRef{
+				{BackendObjectReference: gwapiv1.BackendObjectReference{Name: "svc-a"}},
+				{
+					BackendObjectReference: gwapiv1.BackendObjectReference{Name: "stdio"},
+					Stdio:                  &aigv1b1.MCPStdioServer{Command: "server"},
+				},
+			},
+		},
+	}
+	require.NoError(t, fakeClient.Create(t.Context(), route))
+
+	// The stdio MCP servers are disabled by default, so the route is not accepted.
+	_, err = c.Reconcile(t.Context(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "myroute"}})
+	require.ErrorContains(t, err, "stdio MCP servers are disabled in the controller")
+	var current aigv1b1.MCPRoute
+	require.NoError(t, fakeClient.Get(t.Context(), client.ObjectKey{Name: "myroute", Namespace: "default"}, &current))
+	require.Len(t, current.Status.Conditions, 1)
+	require.Equal(t, aigv1b1.ConditionTypeNotAccepted, current.Status.Conditions[0].Type)
+
+	c.enableStdioServers = true
+	_, err = c.Reconcile(t.Context(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "myroute"}})
+	require.NoError(t, err)
+
+	var httpRoute gwapiv1.HTTPRoute
+	err = fakeClient.Get(t.Context(), client.ObjectKey{Name: mcpPerBackendRefHTTPRouteName(route.Name, "svc-a"), Namespace: "default"}, &httpRoute)
+	require.NoError(t, err)
+	// The stdio servers are reached directly by the MCP proxy, so there is no HTTPRoute for them.
+	err = fakeClient.Get(t.Context(), client.ObjectKey{Name: mcpPerBackendRefHTTPRouteName(route.Name, "stdio"), Namespace: "default"}, &httpRoute)
+	require.True(t, apierrors.IsNotFound(err))
+}
+
 func Test_newHTTPRoute_MCP_PathAndBackendsAndMetadata(t *testing.T) {
 	c := requireNewFakeClientWithIndexesForMCP(t)
 	eventCh := internaltesting.NewControllerEventChan[*gwapiv1.Gateway]()
@@ -215,6 +258,87 @@ func Test_newHTTPRoute_MCP_PathAndBackendsAndMetadata(t *testing.T) {
 	require.Equal(t, mcpRoute.Spec.ParentRefs, httpRoute.Spec.ParentRefs)
 }
 
+func Test_newHTTPRoute_MCPLegacySSE(t *testing.T) {
+	c := requireNewFakeClientWithIndexesForMCP(t)
+	eventCh := internaltesting.NewControllerEventChan[*gwapiv1.Gateway]()
+	ctrlr := NewMCPRouteController(c, nil, logr.Discard(), eventCh.Ch)
+
+	mcpRoute := &aigv1b1.MCPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "mcp-route", Namespace: "ns"},
+		Spec: aigv1b1.MCPRouteSpec{
+			Headers:    []gwapiv1.HTTPHeaderMatch{{Name: "x-match", Value: "yes"}},
+			ParentRefs: []gwapiv1.ParentReference{{Name: gwapiv1.ObjectName("gw")}},
+			LegacySSE:  &aigv1b1.MCPRouteLegacySSE{SSEPath: "/events"},
+			BackendRefs: []aigv1b1.MCPRouteBackendRef{
+				{
+					BackendObjectReference: gwapiv1.BackendObjectReference{Name: "legacy", Port: ptr.To[gwapiv1.PortNumber](80)},
+					Path:                   ptr.To("/sse"),
+					Transport:              aigv1b1.MCPBackendTransportSSE,
+				},
+			},
+		},
+	}
+
+	// The legacy endpoints are served by the MCP proxy along with the main path.
+	httpRoute := &gwapiv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: "mcp-route", Namespace: "ns"}}
+	require.NoError(t, ctrlr.newMainHTTPRoute(httpRoute, mcpRoute))
+	require.Len(t, httpRoute.Spec.Rules, 1)
+	matches := httpRoute.Spec.Rules[0].Matches
+	require.Len(t, matches, 3)
+	require.Equal(t, "/events", *matches[1].Path.Value)
+	require.Equal(t, gwapiv1.PathMatchExact, *matches[1].Path.Type)
+	require.Equal(t, "/messages", *matches[2].Path.Value)
+	require.Equal(t, mcpRoute.Spec.Headers, matches[2].Headers)
+
+	// The messages posted to the SSE backend keep the path advertised by the backend.
+	httpRoute = &gwapiv1.HTTPRoute{}
+	require.NoError(t, ctrlr.newPerBackendRefHTTPRoute(t.Context(), httpRoute, mcpRoute, &mcpRoute.Spec.BackendRefs[0]))
+	require.Len(t, httpRoute.Spec.Rules, 2)
+	require.True(t, slices.ContainsFunc(httpRoute.Spec.Rules[0].Filters, func(f gwapiv1.HTTPRouteFilter) bool {
+		return f.Type == gwapiv1.HTTPRouteFilterURLRewrite
+	}))
+	require.False(t, slices.ContainsFunc(httpRoute.Spec.Rules[1].Filters, func(f gwapiv1.HTTPRouteFilter) bool {
+		return f.Type == gwapiv1.HTTPRouteFilterURLRewrite
+	}))
+	require.Contains(t, httpRoute.Spec.Rules[1].Matches[0].Headers, gwapiv1.HTTPHeaderMatch{
+		Name: internalapi.MCPSSEMessageHeader, Value: "true",
+	})
+}
+
+func Test_newHTTPRoute_MCPOpenAPI(t *testing.T) {
+	c := requireNewFakeClientWithIndexesForMCP(t)
+	eventCh := internaltesting.NewControllerEventChan[*gwapiv1.Gateway]()
+	ctrlr := NewMCPRouteController(c, nil, logr.Discard(), eventCh.Ch)
+
+	mcpRoute := &aigv1b1.MCPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "mcp-route", Namespace: "ns"},
+		Spec: aigv1b1.MCPRouteSpec{
+			ParentRefs: []gwapiv1.ParentReference{{Name: gwapiv1.ObjectName("gw")}},
+			BackendRefs: []aigv1b1.MCPRouteBackendRef{
+				{
+					BackendObjectReference: gwapiv1.BackendObjectReference{Name: "rest", Port: ptr.To[gwapiv1.PortNumber](80)},
+					OpenAPI:                &aigv1b1.MCPOpenAPIBackend{URL: ptr.To("https://example.com/openapi.yaml")},
+				},
+			},
+		},
+	}
+
+	// The requests of the tool calls keep the paths of the operations.
+	httpRoute := &gwapiv1.HTTPRoute{}
+	require.NoError(t, ctrlr.newPerBackendRefHTTPRoute(t.Context(), httpRoute, mcpRoute, &mcpRoute.Spec.BackendRefs[0]))
+	require.Len(t, httpRoute.Spec.Rules, 1)
+	require.False(t, slices.ContainsFunc(httpRoute.Spec.Rules[0].Filters, func(f gwapiv1.HTTPRouteFilter) bool {
+		return f.Type == gwapiv1.HTTPRouteFilterURLRewrite
+	}))
+	require.True(t, slices.ContainsFunc(httpRoute.Spec.Rules[0].Filters, func(f gwapiv1.HTTPRouteFilter) bool {
+		return f.Type == gwapiv1.HTTPRouteFilterExtensionRef
+	}))
+	require.Equal(t, []gwapiv1.HTTPHeaderMatch{
+		{Name: internalapi.MCPBackendHeader, Value: "rest"},
+		{Name: internalapi.MCPRouteHeader, Value: "ns/mcp-route"},
+	}, httpRoute.Spec.Rules[0].Matches[0].Headers)
+}
+
 func Test_newHTTPRoute_MCPOauth(t *testing.T) {
 	c := requireNewFakeClientWithIndexesForMCP(t)
 	eventCh := internaltesting.NewControllerEventChan[*gwapiv1.Gateway]()
@@ -8,6 +8,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
@@ -16,6 +17,10 @@ import (
 const (
 	// aiGatewayRouteKind is the kind for AIGatewayRoute.
 	aiGatewayRouteKind = "AIGatewayRoute"
+	// backendSecurityPolicyKind is the kind for BackendSecurityPolicy.
+	backendSecurityPolicyKind = "BackendSecurityPolicy"
+	// secretKind is the kind for Secret.
+	secretKind = "Secret"
 )
 
 // ReferenceGrantValidator validates cross-namespace references using ReferenceGrant resources.
@@ -139,3 +144,46 @@ func (v *referenceGrantValidator) matchesTo(to *gwapiv1b1.ReferenceGrantTo) bool
 
 	return true
 }
+
+// validateSecretReference validates that a BackendSecurityPolicy can reference a Secret in a different namespace
+// by checking for a ReferenceGrant in the namespace of the Secret allowing it.
+func (v *referenceGrantValidator) validateSecretReference(
+	ctx context.Context,
+	policyNamespace string,
+	secretNamespace string,
+	secretName string,
+) error {
+	// Same namespace references don't need ReferenceGrant
+	if policyNamespace == secretNamespace {
+		return nil
+	}
+
+	indexKey := getReferenceGrantIndexKey(secretNamespace, secretKind)
+	var referenceGrants gwapiv1b1.ReferenceGrantList
+	if err := v.client.List(ctx, &referenceGrants,
+		client.MatchingFields{k8sClientIndexReferenceGrantToTargetKind: indexKey},
+	); err != nil {
+		return fmt.Errorf("failed to list ReferenceGrants in namespace %s for kind %s: %w",
+			secretNamespace, secretKind, err)
+	}
+
+	for i := range referenceGrants.Items {
+		grant := &referenceGrants.Items[i]
+		fromAllowed := slices.ContainsFunc(grant.Spec.From, func(from gwapiv1b1.ReferenceGrantFrom) bool {
+			return from.Group == aiServiceBackendGroup && from.Kind == backendSecurityPolicyKind &&
+				from.Namespace == gwapiv1b1.Namespace(policyNamespace)
+		})
+		toAllowed := slices.ContainsFunc(grant.Spec.To, func(to gwapiv1b1.ReferenceGrantTo) bool {
+			return to.Group == "" && to.Kind == secretKind && (to.Name == nil || string(*to.Name) == secretName)
+		})
+		if fromAllowed && toAllowed {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"cross-namespace reference from BackendSecurityPolicy in namespace %s to Secret %s in namespace %s is not permitted: "+
+			"no valid ReferenceGrant found in namespace %s",
+		policyNamespace, secretName, secretNamespace, secretNamespace,
+	)
+}
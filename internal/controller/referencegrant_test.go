@@ -280,6 +280,72 @@ func TestReferenceGrantValidator_ValidateAIServiceBackendReference(t *testing.T)
 	})
 }
 
+func TestReferenceGrantValidator_ValidateSecretReference(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = gwapiv1b1.Install(scheme)
+
+	grant := func(fromKind string, secretName *string) *gwapiv1b1.ReferenceGrant {
+		return &gwapiv1b1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: "grant", Namespace: "vault"},
+			Spec: gwapiv1b1.ReferenceGrantSpec{
+				From: []gwapiv1b1.ReferenceGrantFrom{{
+					Group: aiServiceBackendGroup, Kind: gwapiv1b1.Kind(fromKind), Namespace: "team",
+				}},
+				To: []gwapiv1b1.ReferenceGrantTo{{Kind: secretKind, Name: (*gwapiv1b1.ObjectName)(secretName)}},
+			},
+		}
+	}
+	tokenName, otherName := "vault-token", "other"
+	for _, tc := range []struct {
+		name            string
+		policyNamespace string
+		grant           *gwapiv1b1.ReferenceGrant
+		expErr          string
+	}{
+		{name: "same namespace", policyNamespace: "vault"},
+		{
+			name:            "no ReferenceGrant",
+			policyNamespace: "team",
+			expErr: "cross-namespace reference from BackendSecurityPolicy in namespace team to Secret vault-token " +
+				"in namespace vault is not permitted",
+		},
+		{name: "granted", policyNamespace: "team", grant: grant(backendSecurityPolicyKind, nil)},
+		{name: "granted by name", policyNamespace: "team", grant: grant(backendSecurityPolicyKind, &tokenName)},
+		{
+			name:            "other secret granted",
+			policyNamespace: "team",
+			grant:           grant(backendSecurityPolicyKind, &otherName),
+			expErr:          "is not permitted",
+		},
+		{
+			name:            "granted to another kind",
+			policyNamespace: "team",
+			grant:           grant(aiGatewayRouteKind, nil),
+			expErr:          "is not permitted",
+		},
+		{
+			name:            "granted to another namespace",
+			policyNamespace: "attacker",
+			grant:           grant(backendSecurityPolicyKind, nil),
+			expErr:          "is not permitted",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme).
+				WithIndex(&gwapiv1b1.ReferenceGrant{}, k8sClientIndexReferenceGrantToTargetKind, referenceGrantToTargetKindIndexFunc)
+			if tc.grant != nil {
+				builder = builder.WithObjects(tc.grant)
+			}
+			err := newReferenceGrantValidator(builder.Build()).validateSecretReference(t.Context(), tc.policyNamespace, "vault", tokenName)
+			if tc.expErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
+
 // TestReferenceGrantValidator_MatchesFrom_WrongGroup tests matchesFrom with wrong group
 func TestReferenceGrantValidator_MatchesFrom_WrongGroup(t *testing.T) {
 	scheme := runtime.NewScheme()
@@ -0,0 +1,512 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package rotators
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	aigv1b1 "github.com/envoyproxy/ai-gateway/api/v1beta1"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+const (
+	// VaultTokenKey is the key used to store the Vault token in Kubernetes secrets.
+	VaultTokenKey = "token"
+	// defaultVaultRefreshInterval is how often the KV secrets are read when their refresh interval is not set.
+	defaultVaultRefreshInterval = 5 * time.Minute
+	// VaultServiceAccountTokenPath is the path of the service account token of the controller used for the Kubernetes
+	// auth method of Vault. The token is projected with a dedicated audience bound to the Vault roles, so that it is
+	// not accepted by the Kubernetes API server if it leaks.
+	VaultServiceAccountTokenPath = "/var/run/secrets/envoy-ai-gateway/vault/token" // #nosec G101
+	// maxVaultResponseSize is the maximum size of the responses of Vault.
+	maxVaultResponseSize = 1 << 20
+)
+
+// VaultConfig is the configuration of the controller restricting the Vault servers and the roles that the backend
+// security policies can use. The address of the Vault server is chosen by the author of the policy, and the controller
+// sends its credentials to it, so only the servers and the roles allowed by the operator of the controller are used.
+type VaultConfig struct {
+	// Addresses are the addresses of the Vault servers that the backend security policies can read credentials from.
+	Addresses []string
+	// KubernetesAuthRoles are the roles of the Kubernetes auth method that the backend security policies of each
+	// namespace can log in with.
+	KubernetesAuthRoles map[string][]string
+}
+
+// ParseVaultKubernetesAuthRoles parses comma-separated namespace:role pairs into the roles allowed by namespace.
+func ParseVaultKubernetesAuthRoles(s string) (map[string][]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	roles := make(map[string][]string)
+	for i, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue // Skip empty pairs from trailing commas.
+		}
+		namespace, role, _ := strings.Cut(pair, ":")
+		namespace, role = strings.TrimSpace(namespace), strings.TrimSpace(role)
+		if namespace == "" || role == "" {
+			return nil, fmt.Errorf("invalid vault role at position %d: %q (expected format: namespace:role)", i+1, pair)
+		}
+		roles[namespace] = append(roles[namespace], role)
+	}
+	return roles, nil
+}
+
+// validate returns an error if the Vault configuration of a backend security policy in the given namespace uses a
+// Vault server or a role that is not allowed.
+func (c *VaultConfig) validate(namespace string, vault *aigv1b1.BackendSecurityPolicyVault) error {
+	if c == nil || !slices.ContainsFunc(c.Addresses, func(addr string) bool {
+		return strings.TrimSuffix(addr, "/") == strings.TrimSuffix(vault.Address, "/")
+	}) {
+		return fmt.Errorf("vault server %s is not allowed by the controller configuration", vault.Address)
+	}
+	if k8s := vault.Auth.Kubernetes; k8s != nil && !slices.Contains(c.KubernetesAuthRoles[namespace], k8s.Role) {
+		return fmt.Errorf("vault role %s is not allowed for the namespace %s by the controller configuration", k8s.Role, namespace)
+	}
+	return nil
+}
+
+// VaultCredentialStore holds the credentials read from Vault, by backend security policy. They are only kept in
+// memory so that they are never stored in a Secret, and the store is shared by the Vault rotators with the Gateway
+// controller building the filter configuration.
+type VaultCredentialStore struct {
+	mu     sync.Mutex
+	leases map[string]*vaultLease
+}
+
+// NewVaultCredentialStore creates a new empty VaultCredentialStore.
+func NewVaultCredentialStore() *VaultCredentialStore {
+	return &VaultCredentialStore{leases: make(map[string]*vaultLease)}
+}
+
+// vaultLease is the state of the credentials of a backend security policy.
+type vaultLease struct {
+	// data is the data of the secret.
+	data map[string]string
+	// expiresAt is when the credentials must be read again or their lease renewed.
+	expiresAt time.Time
+	// leaseID is the ID of the lease of the dynamic secrets.
+	leaseID string
+	// leaseDuration is the duration of the lease of the dynamic secrets, used as the renewal increment.
+	leaseDuration time.Duration
+	// renewable is true if the lease of the dynamic secrets can be renewed.
+	renewable bool
+	// token is the Vault token obtained with the Kubernetes auth method, reused until it expires.
+	token          string
+	tokenExpiresAt time.Time
+	// rotator is the rotator that read the dynamic secrets, used to revoke their lease once the policy is deleted.
+	rotator *vaultRotator
+}
+
+// Get returns the data of the secret read for the given backend security policy.
+func (s *VaultCredentialStore) Get(namespace, name string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lease, ok := s.leases[backendSecurityPolicyStoreKey(namespace, name)]
+	if !ok || lease.data == nil {
+		return nil, false
+	}
+	return lease.data, true
+}
+
+// Set sets the data of the secret read for the given backend security policy, which must be read again before the
+// given time.
+func (s *VaultCredentialStore) Set(namespace, name string, data map[string]string, expiresAt time.Time) {
+	s.update(namespace, name, func(l *vaultLease) {
+		*l = vaultLease{data: data, expiresAt: expiresAt, token: l.token, tokenExpiresAt: l.tokenExpiresAt}
+	})
+}
+
+// Delete removes the credentials of the given backend security policy, which is deleted, and revokes the lease of its
+// dynamic secrets so that they cannot be used anymore. The credentials are removed even if the revocation fails.
+func (s *VaultCredentialStore) Delete(ctx context.Context, namespace, name string) error {
+	lease := s.lease(namespace, name)
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.leases, backendSecurityPolicyStoreKey(namespace, name))
+	}()
+	if lease.leaseID == "" || lease.rotator == nil {
+		return nil
+	}
+	token, err := lease.rotator.token(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to authenticate to vault: %w", err)
+	}
+	if err = lease.rotator.revoke(ctx, token, lease.leaseID); err != nil {
+		return fmt.Errorf("failed to revoke the vault lease: %w", err)
+	}
+	return nil
+}
+
+// lease returns a copy of the state of the credentials of the given backend security policy.
+func (s *VaultCredentialStore) lease(namespace, name string) vaultLease {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if l, ok := s.leases[backendSecurityPolicyStoreKey(namespace, name)]; ok {
+		return *l
+	}
+	return vaultLease{}
+}
+
+// update updates the state of the credentials of the given backend security policy.
+func (s *VaultCredentialStore) update(namespace, name string, fn func(*vaultLease)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := backendSecurityPolicyStoreKey(namespace, name)
+	l, ok := s.leases[key]
+	if !ok {
+		l = &vaultLease{}
+		s.leases[key] = l
+	}
+	fn(l)
+}
+
+func backendSecurityPolicyStoreKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// vaultRotator implements Rotator interface for the credentials read from HashiCorp Vault.
+type vaultRotator struct {
+	// client is used for Kubernetes API operations.
+	client client.Client
+	// httpClient is used to send the requests to Vault.
+	httpClient *http.Client
+	// logger is used for structured logging.
+	logger logr.Logger
+	// backendSecurityPolicyName provides name of backend security policy.
+	backendSecurityPolicyName string
+	// backendSecurityPolicyNamespace provides namespace of backend security policy.
+	backendSecurityPolicyNamespace string
+	// vault is the Vault configuration of the backend security policy.
+	vault *aigv1b1.BackendSecurityPolicyVault
+	// preRotationWindow specifies how long before expiry to rotate.
+	preRotationWindow time.Duration
+	// store holds the credentials.
+	store *VaultCredentialStore
+	// serviceAccountTokenPath is the path of the service account token used for the Kubernetes auth method.
+	serviceAccountTokenPath string
+	// now returns the current time.
+	now func() time.Time
+}
+
+// NewVaultRotator creates a new Rotator reading the credentials of the given backend security policy from Vault into
+// the given store. The Vault server and the role of the policy must be allowed by the given configuration.
+func NewVaultRotator(
+	client client.Client,
+	logger logr.Logger,
+	bsp *aigv1b1.BackendSecurityPolicy,
+	preRotationWindow time.Duration,
+	store *VaultCredentialStore,
+	config *VaultConfig,
+) (Rotator, error) {
+	if bsp.Spec.Vault == nil {
+		return nil, fmt.Errorf("vault configuration is required for backend security policy %s/%s", bsp.Namespace, bsp.Name)
+	}
+	if err := config.validate(bsp.Namespace, bsp.Spec.Vault); err != nil {
+		return nil, fmt.Errorf("invalid vault configuration of backend security policy %s/%s: %w", bsp.Namespace, bsp.Name, err)
+	}
+	return &vaultRotator{
+		client:                         client,
+		httpClient:                     &http.Client{Timeout: 30 * time.Second},
+		logger:                         logger.WithName("vault-rotator"),
+		backendSecurityPolicyName:      bsp.Name,
+		backendSecurityPolicyNamespace: bsp.Namespace,
+		vault:                          bsp.Spec.Vault,
+		preRotationWindow:              preRotationWindow,
+		store:                          store,
+		serviceAccountTokenPath:        VaultServiceAccountTokenPath,
+		now:                            time.Now,
+	}, nil
+}
+
+// IsExpired implements Rotator.IsExpired method to check if the preRotation time is before the current time.
+func (r *vaultRotator) IsExpired(preRotationExpirationTime time.Time) bool {
+	return !preRotationExpirationTime.After(r.now())
+}
+
+// GetPreRotationTime implements Rotator.GetPreRotationTime method to retrieve the pre-rotation time of the credentials.
+// It returns the zero time if the credentials were never read, for example after a restart of the controller.
+func (r *vaultRotator) GetPreRotationTime(context.Context) (time.Time, error) {
+	lease := r.store.lease(r.backendSecurityPolicyNamespace, r.backendSecurityPolicyName)
+	if lease.data == nil {
+		return time.Time{}, nil
+	}
+	return lease.expiresAt.Add(-r.preRotationWindow), nil
+}
+
+// Rotate implements Rotator.Rotate method to renew the lease of the credentials or read them again from Vault.
+func (r *vaultRotator) Rotate(ctx context.Context) (time.Time, error) {
+	bspNamespace, bspName := r.backendSecurityPolicyNamespace, r.backendSecurityPolicyName
+	r.logger.Info("start rotating vault credentials", "namespace", bspNamespace, "name", bspName)
+
+	token, err := r.token(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to authenticate to vault: %w", err)
+	}
+
+	lease := r.store.lease(bspNamespace, bspName)
+	if lease.leaseID != "" && lease.renewable {
+		var expiresAt time.Time
+		expiresAt, err = r.renew(ctx, token, &lease)
+		if err == nil {
+			return expiresAt, nil
+		}
+		r.logger.Info("failed to renew the vault lease, reading new credentials", "namespace", bspNamespace,
+			"name", bspName, "error", err.Error())
+	}
+
+	var secret *vaultSecret
+	if kv := r.vault.KV; kv != nil {
+		mountPath := kv.MountPath
+		if mountPath == "" {
+			mountPath = "secret"
+		}
+		secret, err = r.do(ctx, token, http.MethodGet, mountPath+"/data/"+strings.TrimPrefix(kv.Path, "/"), nil)
+	} else {
+		secret, err = r.do(ctx, token, http.MethodGet, r.vault.Dynamic.Path, nil)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read the vault secret: %w", err)
+	}
+	data, err := r.credentialData(secret)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	now := r.now()
+	leaseDuration := time.Duration(secret.LeaseDuration) * time.Second
+	expiresAt := now.Add(leaseDuration)
+	if r.vault.KV != nil || secret.LeaseID == "" {
+		// The secrets without a lease are read again at the refresh interval.
+		refreshInterval := defaultVaultRefreshInterval
+		if r.vault.KV != nil && r.vault.KV.RefreshInterval != nil {
+			if d, parseErr := time.ParseDuration(string(*r.vault.KV.RefreshInterval)); parseErr == nil && d > 0 {
+				refreshInterval = d
+			}
+		}
+		expiresAt = now.Add(refreshInterval + r.preRotationWindow)
+	}
+	if lease.leaseID != "" && lease.leaseID != secret.LeaseID {
+		// The previous credentials are not used anymore.
+		if revokeErr := r.revoke(ctx, token, lease.leaseID); revokeErr != nil {
+			r.logger.Info("failed to revoke the previous vault lease", "namespace", bspNamespace, "name", bspName,
+				"error", revokeErr.Error())
+		}
+	}
+	r.store.update(bspNamespace, bspName, func(l *vaultLease) {
+		l.data = data
+		l.expiresAt = expiresAt
+		l.leaseID = secret.LeaseID
+		l.leaseDuration = leaseDuration
+		l.renewable = secret.Renewable
+		l.rotator = r
+	})
+	return expiresAt, nil
+}
+
+// revoke revokes the given lease of dynamic secrets.
+func (r *vaultRotator) revoke(ctx context.Context, token, leaseID string) error {
+	_, err := r.do(ctx, token, http.MethodPut, "sys/leases/revoke", map[string]any{"lease_id": leaseID})
+	return err
+}
+
+// renew renews the lease of the given dynamic secret. It fails if the lease cannot be extended beyond the
+// pre-rotation window anymore, typically because it reached its maximum TTL.
+func (r *vaultRotator) renew(ctx context.Context, token string, lease *vaultLease) (time.Time, error) {
+	secret, err := r.do(ctx, token, http.MethodPut, "sys/leases/renew", map[string]any{
+		"lease_id":  lease.leaseID,
+		"increment": int(lease.leaseDuration.Seconds()),
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	leaseDuration := time.Duration(secret.LeaseDuration) * time.Second
+	if leaseDuration <= r.preRotationWindow {
+		return time.Time{}, fmt.Errorf("the lease is only renewed for %s", leaseDuration)
+	}
+	expiresAt := r.now().Add(leaseDuration)
+	r.store.update(r.backendSecurityPolicyNamespace, r.backendSecurityPolicyName, func(l *vaultLease) {
+		l.expiresAt = expiresAt
+		l.renewable = secret.Renewable
+	})
+	return expiresAt, nil
+}
+
+// credentialData returns the data of the given secret used by the credentials, after checking that it has all the
+// keys they need.
+func (r *vaultRotator) credentialData(secret *vaultSecret) (map[string]string, error) {
+	raw := secret.Data
+	if r.vault.KV != nil {
+		// The KV version 2 secrets engine nests the data of the secret with its metadata.
+		nested, _ := raw["data"].(map[string]any)
+		raw = nested
+	}
+	data := make(map[string]string, len(raw))
+	for k, v := range raw {
+		if s, ok := v.(string); ok {
+			data[k] = s
+		} else if v != nil {
+			data[k] = fmt.Sprint(v)
+		}
+	}
+	var required []string
+	if r.vault.Credential.Type == aigv1b1.VaultCredentialTypeAWSCredentials {
+		required = []string{"access_key", "secret_key"}
+	} else {
+		required = []string{VaultCredentialKey(&r.vault.Credential)}
+	}
+	for _, key := range required {
+		if data[key] == "" {
+			return nil, fmt.Errorf("the vault secret has no %q key", key)
+		}
+	}
+	return data, nil
+}
+
+// VaultCredentialKey returns the key of the data of the secret holding the API key of the given credential.
+func VaultCredentialKey(credential *aigv1b1.VaultCredential) string {
+	if credential.Key == "" {
+		return "apiKey"
+	}
+	return credential.Key
+}
+
+// FormatVaultAWSCredentials formats the AWS credentials read from Vault into a credentials file.
+func FormatVaultAWSCredentials(data map[string]string, region string) string {
+	return formatAWSCredentialsFile(&awsCredentialsFile{creds: awsCredentials{
+		profile:         "default",
+		accessKeyID:     data["access_key"],
+		secretAccessKey: data["secret_key"],
+		sessionToken:    data["security_token"],
+		region:          region,
+	}})
+}
+
+// token returns the Vault token, reading it from its Secret or logging in with the Kubernetes auth method.
+func (r *vaultRotator) token(ctx context.Context) (string, error) {
+	if ref := r.vault.Auth.TokenSecretRef; ref != nil {
+		namespace := r.backendSecurityPolicyNamespace
+		if ref.Namespace != nil {
+			namespace = string(*ref.Namespace)
+		}
+		secret, err := LookupSecret(ctx, r.client, namespace, string(ref.Name))
+		if err != nil {
+			return "", err
+		}
+		token := strings.TrimSpace(string(secret.Data[VaultTokenKey]))
+		if token == "" {
+			return "", fmt.Errorf("secret %s/%s has no %q key", namespace, ref.Name, VaultTokenKey)
+		}
+		return token, nil
+	}
+
+	k8s := r.vault.Auth.Kubernetes
+	if k8s == nil {
+		return "", fmt.Errorf("no vault auth method is specified")
+	}
+	lease := r.store.lease(r.backendSecurityPolicyNamespace, r.backendSecurityPolicyName)
+	if lease.token != "" && lease.tokenExpiresAt.After(r.now().Add(time.Minute)) {
+		return lease.token, nil
+	}
+	jwt, err := os.ReadFile(r.serviceAccountTokenPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the service account token: %w", err)
+	}
+	mountPath := k8s.MountPath
+	if mountPath == "" {
+		mountPath = "kubernetes"
+	}
+	secret, err := r.do(ctx, "", http.MethodPost, "auth/"+mountPath+"/login", map[string]any{
+		"role": k8s.Role,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	})
+	if err != nil {
+		return "", err
+	}
+	if secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return "", fmt.Errorf("the vault login response has no token")
+	}
+	tokenExpiresAt := r.now().Add(time.Duration(secret.Auth.LeaseDuration) * time.Second)
+	r.store.update(r.backendSecurityPolicyNamespace, r.backendSecurityPolicyName, func(l *vaultLease) {
+		l.token = secret.Auth.ClientToken
+		l.tokenExpiresAt = tokenExpiresAt
+	})
+	return secret.Auth.ClientToken, nil
+}
+
+// vaultSecret is the response of the Vault API.
+type vaultSecret struct {
+	LeaseID       string         `json:"lease_id"`
+	LeaseDuration int            `json:"lease_duration"`
+	Renewable     bool           `json:"renewable"`
+	Data          map[string]any `json:"data"`
+	Auth          *struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+	} `json:"auth"`
+	Errors []string `json:"errors"`
+}
+
+// do sends a request to the given path of the Vault API.
+func (r *vaultRotator) do(ctx context.Context, token, method, path string, body map[string]any) (*vaultSecret, error) {
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+	url := strings.TrimSuffix(r.vault.Address, "/") + "/v1/" + strings.TrimPrefix(path, "/")
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if r.vault.Namespace != nil {
+		req.Header.Set("X-Vault-Namespace", *r.vault.Namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxVaultResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	secret := &vaultSecret{}
+	if len(bytes.TrimSpace(raw)) > 0 {
+		if err = json.Unmarshal(raw, secret); err != nil {
+			return nil, fmt.Errorf("invalid response from %s %s: %w", method, path, err)
+		}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s %s returned status code %d: %s", method, path, resp.StatusCode,
+			strings.Join(secret.Errors, "; "))
+	}
+	return secret, nil
+}
@@ -0,0 +1,361 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package rotators
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	aigv1b1 "github.com/envoyproxy/ai-gateway/api/v1beta1"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+// fakeVault is a stand-in for a Vault dev server, with a KV version 2 secrets engine mounted at "secret", a dynamic
+// secrets engine at "aws" and the Kubernetes auth method.
+type fakeVault struct {
+	mu sync.Mutex
+	// requests are the method and path of the requests received.
+	requests []string
+	// logins are the JWTs of the logins.
+	logins []string
+	// renewDuration is the lease duration returned by the renewals.
+	renewDuration int
+	// revoked are the revoked lease IDs.
+	revoked []string
+	// leases is the number of dynamic secrets generated.
+	leases int
+	// kvVersion is the version of the KV secret.
+	kvVersion int
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	var body map[string]any
+	if raw, _ := io.ReadAll(r.Body); len(raw) > 0 {
+		_ = json.Unmarshal(raw, &body)
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path == "/v1/auth/kubernetes/login" {
+		f.logins = append(f.logins, body["jwt"].(string))
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"k8s-token","lease_duration":3600}}`))
+		return
+	}
+	if token := r.Header.Get("X-Vault-Token"); token != "root" && token != "k8s-token" {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+		return
+	}
+	switch r.URL.Path {
+	case "/v1/secret/data/ai/openai":
+		f.kvVersion++
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"lease_duration": 0,
+			"data": map[string]any{
+				"data":     map[string]any{"apiKey": "sk-" + string(rune('0'+f.kvVersion))},
+				"metadata": map[string]any{"version": f.kvVersion},
+			},
+		})
+	case "/v1/secret/data/ai/empty":
+		_, _ = w.Write([]byte(`{"data":{"data":{}}}`))
+	case "/v1/aws/creds/bedrock":
+		f.leases++
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"lease_id":       "aws/creds/bedrock/" + string(rune('0'+f.leases)),
+			"lease_duration": 3600,
+			"renewable":      true,
+			"data":           map[string]any{"access_key": "AKIA", "secret_key": "secret", "security_token": "session"},
+		})
+	case "/v1/sys/leases/renew":
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"lease_id": body["lease_id"], "lease_duration": f.renewDuration, "renewable": true,
+		})
+	case "/v1/sys/leases/revoke":
+		f.revoked = append(f.revoked, body["lease_id"].(string))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[]}`))
+	}
+}
+
+func newTestVaultRotator(t *testing.T, vault *aigv1b1.BackendSecurityPolicyVault, now *time.Time) *vaultRotator {
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypes(corev1.SchemeGroupVersion, &corev1.Secret{})
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "vault-token", Namespace: "default"},
+		Data:       map[string][]byte{VaultTokenKey: []byte("root\n")},
+	}).Build()
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("service-account-jwt"), 0o600))
+
+	rotator, err := NewVaultRotator(client, logr.Discard(), &aigv1b1.BackendSecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "vault", Namespace: "default"},
+		Spec:       aigv1b1.BackendSecurityPolicySpec{Type: aigv1b1.BackendSecurityPolicyTypeVault, Vault: vault},
+	}, 5*time.Minute, NewVaultCredentialStore(), &VaultConfig{
+		Addresses:           []string{vault.Address + "/"},
+		KubernetesAuthRoles: map[string][]string{"default": {"ai-gateway"}},
+	})
+	require.NoError(t, err)
+	r := rotator.(*vaultRotator)
+	r.serviceAccountTokenPath = tokenPath
+	r.now = func() time.Time { return *now }
+	return r
+}
+
+func TestNewVaultRotator(t *testing.T) {
+	config := &VaultConfig{
+		Addresses:           []string{"https://vault.vault.svc:8200"},
+		KubernetesAuthRoles: map[string][]string{"default": {"ai-gateway"}, "team": {"team"}},
+	}
+	for _, tc := range []struct {
+		name   string
+		vault  *aigv1b1.BackendSecurityPolicyVault
+		config *VaultConfig
+		err    string
+	}{
+		{name: "no vault configuration", config: config, err: "vault configuration is required for backend security policy default/vault"},
+		{
+			name: "no controller configuration",
+			vault: &aigv1b1.BackendSecurityPolicyVault{
+				Address: "https://vault.vault.svc:8200",
+				Auth:    aigv1b1.VaultAuth{TokenSecretRef: &gwapiv1.SecretObjectReference{Name: "vault-token"}},
+			},
+			err: "vault server https://vault.vault.svc:8200 is not allowed by the controller configuration",
+		},
+		{
+			name: "address not allowed",
+			vault: &aigv1b1.BackendSecurityPolicyVault{
+				Address: "https://attacker.example.com",
+				Auth:    aigv1b1.VaultAuth{Kubernetes: &aigv1b1.VaultKubernetesAuth{Role: "ai-gateway"}},
+			},
+			config: config,
+			err:    "vault server https://attacker.example.com is not allowed by the controller configuration",
+		},
+		{
+			name: "role of another namespace",
+			vault: &aigv1b1.BackendSecurityPolicyVault{
+				Address: "https://vault.vault.svc:8200",
+				Auth:    aigv1b1.VaultAuth{Kubernetes: &aigv1b1.VaultKubernetesAuth{Role: "team"}},
+			},
+			config: config,
+			err:    "vault role team is not allowed for the namespace default by the controller configuration",
+		},
+		{
+			name: "allowed",
+			vault: &aigv1b1.BackendSecurityPolicyVault{
+				Address: "https://vault.vault.svc:8200/",
+				Auth:    aigv1b1.VaultAuth{Kubernetes: &aigv1b1.VaultKubernetesAuth{Role: "ai-gateway"}},
+			},
+			config: config,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewVaultRotator(nil, logr.Discard(), &aigv1b1.BackendSecurityPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "vault", Namespace: "default"},
+				Spec:       aigv1b1.BackendSecurityPolicySpec{Type: aigv1b1.BackendSecurityPolicyTypeVault, Vault: tc.vault},
+			}, time.Minute, NewVaultCredentialStore(), tc.config)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestParseVaultKubernetesAuthRoles(t *testing.T) {
+	roles, err := ParseVaultKubernetesAuthRoles("default:ai-gateway, team:a,team:b,")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"default": {"ai-gateway"}, "team": {"a", "b"}}, roles)
+
+	roles, err = ParseVaultKubernetesAuthRoles("")
+	require.NoError(t, err)
+	require.Nil(t, roles)
+
+	_, err = ParseVaultKubernetesAuthRoles("default:ai-gateway,ai-gateway")
+	require.ErrorContains(t, err, `invalid vault role at position 2: "ai-gateway" (expected format: namespace:role)`)
+}
+
+func TestVaultRotator_KV(t *testing.T) {
+	vault := &fakeVault{}
+	server := httptest.NewServer(vault)
+	t.Cleanup(server.Close)
+
+	now := time.Now()
+	r := newTestVaultRotator(t, &aigv1b1.BackendSecurityPolicyVault{
+		Address:    server.URL,
+		Auth:       aigv1b1.VaultAuth{TokenSecretRef: &gwapiv1.SecretObjectReference{Name: "vault-token"}},
+		KV:         &aigv1b1.VaultKVSecret{Path: "ai/openai", RefreshInterval: ptr.To[gwapiv1.Duration]("10m")},
+		Credential: aigv1b1.VaultCredential{Type: aigv1b1.VaultCredentialTypeAPIKey},
+	}, &now)
+
+	preRotationTime, err := r.GetPreRotationTime(t.Context())
+	require.NoError(t, err)
+	require.True(t, r.IsExpired(preRotationTime))
+
+	expiresAt, err := r.Rotate(t.Context())
+	require.NoError(t, err)
+	// The KV secrets are read again after the refresh interval.
+	require.Equal(t, now.Add(15*time.Minute), expiresAt)
+	data, ok := r.store.Get("default", "vault")
+	require.True(t, ok)
+	require.Equal(t, map[string]string{"apiKey": "sk-1"}, data)
+
+	preRotationTime, err = r.GetPreRotationTime(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, now.Add(10*time.Minute), preRotationTime)
+	require.False(t, r.IsExpired(preRotationTime))
+
+	now = now.Add(10 * time.Minute)
+	require.True(t, r.IsExpired(preRotationTime))
+	_, err = r.Rotate(t.Context())
+	require.NoError(t, err)
+	data, _ = r.store.Get("default", "vault")
+	require.Equal(t, map[string]string{"apiKey": "sk-2"}, data)
+	require.Equal(t, []string{"GET /v1/secret/data/ai/openai", "GET /v1/secret/data/ai/openai"}, vault.requests)
+}
+
+func TestVaultRotator_DynamicWithKubernetesAuth(t *testing.T) {
+	vault := &fakeVault{renewDuration: 3600}
+	server := httptest.NewServer(vault)
+	t.Cleanup(server.Close)
+
+	now := time.Now()
+	r := newTestVaultRotator(t, &aigv1b1.BackendSecurityPolicyVault{
+		Address: server.URL,
+		Auth:    aigv1b1.VaultAuth{Kubernetes: &aigv1b1.VaultKubernetesAuth{Role: "ai-gateway"}},
+		Dynamic: &aigv1b1.VaultDynamicSecret{Path: "aws/creds/bedrock"},
+		Credential: aigv1b1.VaultCredential{
+			Type:   aigv1b1.VaultCredentialTypeAWSCredentials,
+			Region: ptr.To("us-east-1"),
+		},
+	}, &now)
+
+	expiresAt, err := r.Rotate(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, now.Add(time.Hour), expiresAt)
+	data, ok := r.store.Get("default", "vault")
+	require.True(t, ok)
+	require.Equal(t, "[default]\naws_access_key_id = AKIA\naws_secret_access_key = secret\naws_session_token = session\nregion = us-east-1\n",
+		FormatVaultAWSCredentials(data, "us-east-1"))
+
+	// The lease is renewed, and the Vault token of the login is reused.
+	now = now.Add(55 * time.Minute)
+	expiresAt, err = r.Rotate(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, now.Add(time.Hour), expiresAt)
+
+	// Once the lease reaches its maximum TTL, new credentials are read and the previous lease is revoked.
+	vault.renewDuration = 60
+	now = now.Add(55 * time.Minute)
+	_, err = r.Rotate(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "aws/creds/bedrock/2", r.store.lease("default", "vault").leaseID)
+
+	require.Equal(t, []string{"service-account-jwt", "service-account-jwt"}, vault.logins)
+	require.Equal(t, []string{"aws/creds/bedrock/1"}, vault.revoked)
+	require.Equal(t, []string{
+		"POST /v1/auth/kubernetes/login",
+		"GET /v1/aws/creds/bedrock",
+		"PUT /v1/sys/leases/renew",
+		// The token of the first login expired.
+		"POST /v1/auth/kubernetes/login",
+		"PUT /v1/sys/leases/renew",
+		"GET /v1/aws/creds/bedrock",
+		"PUT /v1/sys/leases/revoke",
+	}, vault.requests)
+
+	// Once the policy is deleted, its credentials are removed and their lease is revoked.
+	require.NoError(t, r.store.Delete(t.Context(), "default", "vault"))
+	_, ok = r.store.Get("default", "vault")
+	require.False(t, ok)
+	require.Equal(t, vaultLease{}, r.store.lease("default", "vault"))
+	require.Equal(t, []string{"aws/creds/bedrock/1", "aws/creds/bedrock/2"}, vault.revoked)
+	// Deleting the credentials of a policy that has none is a no-op.
+	require.NoError(t, r.store.Delete(t.Context(), "default", "vault"))
+	require.Len(t, vault.revoked, 2)
+}
+
+func TestVaultRotator_Errors(t *testing.T) {
+	vault := &fakeVault{}
+	server := httptest.NewServer(vault)
+	t.Cleanup(server.Close)
+	now := time.Now()
+
+	for _, tc := range []struct {
+		name  string
+		vault *aigv1b1.BackendSecurityPolicyVault
+		err   string
+	}{
+		{
+			name: "missing token secret",
+			vault: &aigv1b1.BackendSecurityPolicyVault{
+				Address: server.URL,
+				Auth:    aigv1b1.VaultAuth{TokenSecretRef: &gwapiv1.SecretObjectReference{Name: "missing"}},
+				KV:      &aigv1b1.VaultKVSecret{Path: "ai/openai"},
+			},
+			err: "failed to authenticate to vault",
+		},
+		{
+			name: "token secret in another namespace",
+			vault: &aigv1b1.BackendSecurityPolicyVault{
+				Address:   server.URL,
+				Namespace: ptr.To("team"),
+				Auth: aigv1b1.VaultAuth{TokenSecretRef: &gwapiv1.SecretObjectReference{
+					Name: "vault-token", Namespace: ptr.To[gwapiv1.Namespace]("other"),
+				}},
+				KV: &aigv1b1.VaultKVSecret{Path: "ai/openai"},
+			},
+			err: "failed to authenticate to vault",
+		},
+		{
+			name: "missing secret",
+			vault: &aigv1b1.BackendSecurityPolicyVault{
+				Address: server.URL,
+				Auth:    aigv1b1.VaultAuth{TokenSecretRef: &gwapiv1.SecretObjectReference{Name: "vault-token"}},
+				KV:      &aigv1b1.VaultKVSecret{Path: "ai/missing"},
+			},
+			err: "failed to read the vault secret: GET secret/data/ai/missing returned status code 404",
+		},
+		{
+			name: "missing key",
+			vault: &aigv1b1.BackendSecurityPolicyVault{
+				Address:    server.URL,
+				Auth:       aigv1b1.VaultAuth{TokenSecretRef: &gwapiv1.SecretObjectReference{Name: "vault-token"}},
+				KV:         &aigv1b1.VaultKVSecret{Path: "ai/empty"},
+				Credential: aigv1b1.VaultCredential{Key: "token"},
+			},
+			err: `the vault secret has no "token" key`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestVaultRotator(t, tc.vault, &now)
+			_, err := r.Rotate(t.Context())
+			require.ErrorContains(t, err, tc.err)
+			_, ok := r.store.Get("default", "vault")
+			require.False(t, ok)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	aigv1a1 "github.com/envoyproxy/ai-gateway/api/v1alpha1"
+	aigv1b1 "github.com/envoyproxy/ai-gateway/api/v1beta1"
+	"github.com/envoyproxy/ai-gateway/internal/llmcostcel"
+)
+
+const (
+	// virtualKeySecretNamePrefix is the prefix of the names of the Secrets holding the virtual keys.
+	virtualKeySecretNamePrefix = "ai-eg-vk-" // #nosec G101
+	// virtualKeyInSecret is the data field of the Secret holding the virtual key.
+	virtualKeyInSecret = "key"
+	// virtualKeyPrefix is the prefix of the generated virtual keys, which makes them recognizable in the logs
+	// and by the secret scanners.
+	virtualKeyPrefix = "sk-aigw-"
+)
+
+// VirtualKeyController implements [reconcile.TypedReconciler] for [aigv1a1.VirtualKey].
+//
+// This generates the key of the VirtualKey in a Secret owned by it, and notifies the targeted AIGatewayRoutes
+// so that the key is sent to their Gateways.
+//
+// Exported for testing purposes.
+type VirtualKeyController struct {
+	client             client.Client
+	logger             logr.Logger
+	aiGatewayRouteChan chan event.GenericEvent
+}
+
+// NewVirtualKeyController creates a new reconcile.TypedReconciler[reconcile.Request] for the VirtualKey resource.
+func NewVirtualKeyController(
+	client client.Client,
+	logger logr.Logger,
+	aiGatewayRouteChan chan event.GenericEvent,
+) *VirtualKeyController {
+	return &VirtualKeyController{
+		client:             client,
+		logger:             logger,
+		aiGatewayRouteChan: aiGatewayRouteChan,
+	}
+}
+
+// Reconcile implements [reconcile.TypedReconciler] for [aigv1a1.VirtualKey].
+func (c *VirtualKeyController) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	var virtualKey aigv1a1.VirtualKey
+	if err := c.client.Get(ctx, req.NamespacedName, &virtualKey); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			c.logger.Info("Deleting VirtualKey", "namespace", req.Namespace, "name", req.Name)
+			// The Secret is garbage collected as it is owned by the VirtualKey.
+			c.notifyAllAIGatewayRoutesInNamespace(ctx, req.Namespace)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	c.logger.Info("Reconciling VirtualKey", "namespace", req.Namespace, "name", req.Name)
+
+	secretName, err := c.syncVirtualKey(ctx, &virtualKey)
+	if err != nil {
+		c.logger.Error(err, "failed to sync VirtualKey")
+		c.updateVirtualKeyStatus(ctx, &virtualKey, aigv1a1.ConditionTypeNotAccepted, err.Error(), secretName)
+		return ctrl.Result{}, err
+	}
+	c.updateVirtualKeyStatus(ctx, &virtualKey, aigv1a1.ConditionTypeAccepted, "VirtualKey reconciled successfully", secretName)
+	c.notifyAIGatewayRoutes(ctx, &virtualKey)
+	return ctrl.Result{}, nil
+}
+
+// syncVirtualKey validates the VirtualKey and makes sure that the Secret holding its key exists. It returns the
+// name of the Secret.
+func (c *VirtualKeyController) syncVirtualKey(ctx context.Context, virtualKey *aigv1a1.VirtualKey) (string, error) {
+	if b := virtualKey.Spec.Budget; b != nil && b.CostExpression != nil {
+		if _, err := llmcostcel.NewProgram(*b.CostExpression); err != nil {
+			return "", fmt.Errorf("invalid cost expression of the budget: %w", err)
+		}
+	}
+
+	secretName := virtualKeySecretName(virtualKey.Name)
+	var secret corev1.Secret
+	err := c.client.Get(ctx, client.ObjectKey{Name: secretName, Namespace: virtualKey.Namespace}, &secret)
+	if err == nil {
+		if _, ok := secret.Data[virtualKeyInSecret]; !ok {
+			return secretName, fmt.Errorf("secret %s does not contain key %s", secretName, virtualKeyInSecret)
+		}
+		return secretName, nil
+	} else if !apierrors.IsNotFound(err) {
+		return secretName, fmt.Errorf("failed to get secret %s: %w", secretName, err)
+	}
+
+	key, err := generateVirtualKey()
+	if err != nil {
+		return secretName, err
+	}
+	secret = corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: virtualKey.Namespace},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{virtualKeyInSecret: []byte(key)},
+	}
+	if err = ctrlutil.SetControllerReference(virtualKey, &secret, c.client.Scheme()); err != nil {
+		return secretName, fmt.Errorf("failed to set controller reference for secret %s: %w", secretName, err)
+	}
+	if err = c.client.Create(ctx, &secret); err != nil {
+		return secretName, fmt.Errorf("failed to create secret %s: %w", secretName, err)
+	}
+	c.logger.Info("generated a new key for VirtualKey", "namespace", virtualKey.Namespace, "name", virtualKey.Name)
+	return secretName, nil
+}
+
+// virtualKeySecretName returns the name of the Secret holding the key of the VirtualKey with the given name.
+func virtualKeySecretName(virtualKeyName string) string {
+	return virtualKeySecretNamePrefix + virtualKeyName
+}
+
+// generateVirtualKey generates a new random key.
+func generateVirtualKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate the key: %w", err)
+	}
+	return virtualKeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// virtualKeyBudgetWindow returns the duration of the given budget window.
+func virtualKeyBudgetWindow(window string) (time.Duration, error) {
+	switch window {
+	case "1m":
+		return time.Minute, nil
+	case "1h":
+		return time.Hour, nil
+	case "1d":
+		return 24 * time.Hour, nil
+	case "7d":
+		return 7 * 24 * time.Hour, nil
+	case "30d":
+		return 30 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("unknown budget window %q", window)
+	}
+}
+
+// notifyAIGatewayRoutes sends events to the AIGatewayRoute controller for the routes targeted by the given
+// VirtualKey, so that the key is propagated to the filter configuration of their Gateways.
+func (c *VirtualKeyController) notifyAIGatewayRoutes(ctx context.Context, virtualKey *aigv1a1.VirtualKey) {
+	for _, ref := range virtualKey.Spec.TargetRefs {
+		var route aigv1b1.AIGatewayRoute
+		key := client.ObjectKey{Name: string(ref.Name), Namespace: virtualKey.Namespace}
+		if err := c.client.Get(ctx, key, &route); err != nil {
+			if !apierrors.IsNotFound(err) {
+				c.logger.Error(err, "failed to get AIGatewayRoute", "route", key)
+			}
+			continue
+		}
+		c.logger.Info("notifying AIGatewayRoute of VirtualKey change",
+			"route", route.Name, "namespace", route.Namespace, "virtualKey", virtualKey.Name)
+		c.aiGatewayRouteChan <- event.GenericEvent{Object: &route}
+	}
+}
+
+// notifyAllAIGatewayRoutesInNamespace sends events for all AIGatewayRoutes in the given namespace. Used on
+// VirtualKey deletion when targetRefs are no longer available.
+func (c *VirtualKeyController) notifyAllAIGatewayRoutesInNamespace(ctx context.Context, namespace string) {
+	var aiGatewayRoutes aigv1b1.AIGatewayRouteList
+	if err := c.client.List(ctx, &aiGatewayRoutes, client.InNamespace(namespace)); err != nil {
+		c.logger.Error(err, "failed to list AIGatewayRoutes in namespace", "namespace", namespace)
+		return
+	}
+	for i := range aiGatewayRoutes.Items {
+		route := &aiGatewayRoutes.Items[i]
+		c.logger.Info("notifying AIGatewayRoute of VirtualKey deletion",
+			"route", route.Name, "namespace", route.Namespace)
+		c.aiGatewayRouteChan <- event.GenericEvent{Object: route}
+	}
+}
+
+// updateVirtualKeyStatus updates the status of the VirtualKey.
+func (c *VirtualKeyController) updateVirtualKeyStatus(ctx context.Context, virtualKey *aigv1a1.VirtualKey, conditionType, message, secretName string) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := c.client.Get(ctx, client.ObjectKey{Name: virtualKey.Name, Namespace: virtualKey.Namespace}, virtualKey); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		virtualKey.Status.Conditions = newConditions(conditionType, message)
+		virtualKey.Status.SecretName = secretName
+		return c.client.Status().Update(ctx, virtualKey)
+	})
+	if err != nil {
+		c.logger.Error(err, "failed to update VirtualKey status")
+	}
+}
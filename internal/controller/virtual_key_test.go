@@ -0,0 +1,144 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	aigv1a1 "github.com/envoyproxy/ai-gateway/api/v1alpha1"
+	aigv1b1 "github.com/envoyproxy/ai-gateway/api/v1beta1"
+)
+
+func requireNewFakeClientWithIndexesForVirtualKey(t *testing.T) client.Client {
+	t.Helper()
+	builder := fake.NewClientBuilder().WithScheme(Scheme).
+		WithStatusSubresource(&aigv1a1.VirtualKey{})
+	err := ApplyIndexing(t.Context(), func(_ context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+		builder = builder.WithIndex(obj, field, extractValue)
+		return nil
+	})
+	require.NoError(t, err)
+	return builder.Build()
+}
+
+func TestVirtualKeyController_Reconcile(t *testing.T) {
+	fakeClient := requireNewFakeClientWithIndexesForVirtualKey(t)
+	eventCh := make(chan event.GenericEvent, 100)
+	c := NewVirtualKeyController(fakeClient, ctrl.Log, eventCh)
+	namespace := "default"
+
+	route := &aigv1b1.AIGatewayRoute{ObjectMeta: metav1.ObjectMeta{Name: "myroute", Namespace: namespace}}
+	require.NoError(t, fakeClient.Create(t.Context(), route))
+	vk := &aigv1a1.VirtualKey{
+		ObjectMeta: metav1.ObjectMeta{Name: "myvk", Namespace: namespace},
+		Spec: aigv1a1.VirtualKeySpec{
+			TargetRefs: []gwapiv1a2.LocalPolicyTargetReference{
+				{Group: "aigateway.envoyproxy.io", Kind: "AIGatewayRoute", Name: gwapiv1.ObjectName("myroute")},
+				{Group: "aigateway.envoyproxy.io", Kind: "AIGatewayRoute", Name: gwapiv1.ObjectName("nonexistent")},
+			},
+		},
+	}
+	require.NoError(t, fakeClient.Create(t.Context(), vk))
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "myvk"}}
+	_, err := c.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+
+	var updated aigv1a1.VirtualKey
+	require.NoError(t, fakeClient.Get(t.Context(), req.NamespacedName, &updated))
+	require.Len(t, updated.Status.Conditions, 1)
+	require.Equal(t, aigv1a1.ConditionTypeAccepted, updated.Status.Conditions[0].Type)
+	require.Equal(t, "ai-eg-vk-myvk", updated.Status.SecretName)
+
+	var secret corev1.Secret
+	require.NoError(t, fakeClient.Get(t.Context(), types.NamespacedName{Namespace: namespace, Name: "ai-eg-vk-myvk"}, &secret))
+	key := string(secret.Data["key"])
+	require.True(t, strings.HasPrefix(key, "sk-aigw-"), key)
+	require.Len(t, secret.OwnerReferences, 1)
+	require.Equal(t, "myvk", secret.OwnerReferences[0].Name)
+
+	// Only the existing route is notified.
+	require.Len(t, eventCh, 1)
+	ev := <-eventCh
+	require.Equal(t, "myroute", ev.Object.GetName())
+
+	// Reconciling again keeps the key.
+	_, err = c.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(t.Context(), types.NamespacedName{Namespace: namespace, Name: "ai-eg-vk-myvk"}, &secret))
+	require.Equal(t, key, string(secret.Data["key"]))
+	<-eventCh
+
+	// Deleting the Secret rotates the key.
+	require.NoError(t, fakeClient.Delete(t.Context(), &secret))
+	_, err = c.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(t.Context(), types.NamespacedName{Namespace: namespace, Name: "ai-eg-vk-myvk"}, &secret))
+	require.NotEqual(t, key, string(secret.Data["key"]))
+	<-eventCh
+
+	// Deleting the VirtualKey notifies all the routes in the namespace.
+	require.NoError(t, fakeClient.Delete(t.Context(), &updated))
+	_, err = c.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	require.Len(t, eventCh, 1)
+}
+
+func TestVirtualKeyController_Reconcile_InvalidCostExpression(t *testing.T) {
+	fakeClient := requireNewFakeClientWithIndexesForVirtualKey(t)
+	c := NewVirtualKeyController(fakeClient, ctrl.Log, make(chan event.GenericEvent, 100))
+	vk := &aigv1a1.VirtualKey{
+		ObjectMeta: metav1.ObjectMeta{Name: "myvk", Namespace: "default"},
+		Spec: aigv1a1.VirtualKeySpec{
+			TargetRefs: []gwapiv1a2.LocalPolicyTargetReference{
+				{Group: "aigateway.envoyproxy.io", Kind: "AIGatewayRoute", Name: gwapiv1.ObjectName("myroute")},
+			},
+			Budget: &aigv1a1.VirtualKeyBudget{Limit: 100, Window: "1h", CostExpression: ptr.To("invalid +")},
+		},
+	}
+	require.NoError(t, fakeClient.Create(t.Context(), vk))
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "myvk"}}
+	_, err := c.Reconcile(t.Context(), req)
+	require.ErrorContains(t, err, "invalid cost expression of the budget")
+
+	var updated aigv1a1.VirtualKey
+	require.NoError(t, fakeClient.Get(t.Context(), req.NamespacedName, &updated))
+	require.Len(t, updated.Status.Conditions, 1)
+	require.Equal(t, aigv1a1.ConditionTypeNotAccepted, updated.Status.Conditions[0].Type)
+}
+
+func TestVirtualKeyBudgetWindow(t *testing.T) {
+	for window, expected := range map[string]time.Duration{
+		"1m":  time.Minute,
+		"1h":  time.Hour,
+		"1d":  24 * time.Hour,
+		"7d":  7 * 24 * time.Hour,
+		"30d": 30 * 24 * time.Hour,
+	} {
+		d, err := virtualKeyBudgetWindow(window)
+		require.NoError(t, err)
+		require.Equal(t, expected, d)
+	}
+	_, err := virtualKeyBudgetWindow("2d")
+	require.ErrorContains(t, err, `unknown budget window "2d"`)
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
 	cohereschema "github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/ollama"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
 	"github.com/envoyproxy/ai-gateway/internal/filterapi"
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
@@ -113,6 +114,10 @@ type (
 	TranscriptionEndpointSpec struct{}
 	// TranslationEndpointSpec implements EndpointSpec for /v1/audio/translations.
 	TranslationEndpointSpec struct{}
+	// OllamaChatEndpointSpec implements EndpointSpec for the Ollama /api/chat.
+	OllamaChatEndpointSpec struct{}
+	// OllamaEmbedEndpointSpec implements EndpointSpec for the Ollama /api/embed.
+	OllamaEmbedEndpointSpec struct{}
 )
 
 var errMultipartNotSupported = fmt.Errorf("%w: multipart body not supported for this endpoint", internalapi.ErrMalformedRequest)
@@ -167,6 +172,15 @@ func (ChatCompletionsEndpointSpec) GetTranslator(schema filterapi.VersionedAPISc
 		return translator.NewChatCompletionOpenAIToGCPVertexAITranslator(modelNameOverride), nil
 	case filterapi.APISchemaGCPAnthropic:
 		return translator.NewChatCompletionOpenAIToGCPAnthropicTranslator(schema.Version, modelNameOverride), nil
+	case filterapi.APISchemaOCIGenAI:
+		return translator.NewChatCompletionOpenAIToOCIGenAITranslator(schema.Version, modelNameOverride), nil
+	case filterapi.APISchemaDashScope:
+		return translator.NewChatCompletionOpenAIToDashScopeTranslator(modelNameOverride), nil
+	case filterapi.APISchemaOllama:
+		return translator.NewChatCompletionOpenAIToOllamaTranslator(modelNameOverride), nil
+	case filterapi.APISchemaHuggingFaceTGI:
+		// TGI serves the chat completions with its OpenAI compatible Messages API.
+		return translator.NewChatCompletionOpenAIToOpenAITranslator("v1", modelNameOverride), nil
 	default:
 		return nil, fmt.Errorf("unsupported API schema: backend=%s", schema)
 	}
@@ -219,6 +233,8 @@ func (CompletionsEndpointSpec) GetTranslator(schema filterapi.VersionedAPISchema
 	switch schema.Name {
 	case filterapi.APISchemaOpenAI:
 		return translator.NewCompletionOpenAIToOpenAITranslator(schema.OpenAIPrefix(), modelNameOverride), nil
+	case filterapi.APISchemaHuggingFaceTGI:
+		return translator.NewCompletionOpenAIToHuggingFaceTGITranslator(modelNameOverride), nil
 	default:
 		return nil, fmt.Errorf("unsupported API schema: backend=%s", schema)
 	}
@@ -258,6 +274,14 @@ func (EmbeddingsEndpointSpec) GetTranslator(schema filterapi.VersionedAPISchema,
 		return translator.NewEmbeddingOpenAIToGCPVertexAITranslator("", modelNameOverride), nil
 	case filterapi.APISchemaAWSBedrock:
 		return translator.NewEmbeddingOpenAIToAWSBedrockTranslator(modelNameOverride), nil
+	case filterapi.APISchemaOCIGenAI:
+		return translator.NewEmbeddingOpenAIToOCIGenAITranslator(schema.Version, modelNameOverride), nil
+	case filterapi.APISchemaDashScope:
+		return translator.NewEmbeddingOpenAIToDashScopeTranslator(modelNameOverride), nil
+	case filterapi.APISchemaOllama:
+		return translator.NewEmbeddingOpenAIToOllamaTranslator(modelNameOverride), nil
+	case filterapi.APISchemaHuggingFaceTGI:
+		return translator.NewEmbeddingOpenAIToHuggingFaceTEITranslator(modelNameOverride), nil
 	default:
 		return nil, fmt.Errorf("unsupported API schema: backend=%s", schema)
 	}
@@ -418,6 +442,102 @@ func (RerankEndpointSpec) RedactSensitiveInfoFromRequest(req *cohereschema.Reran
 	return req, nil
 }
 
+// ParseBody implements [EndpointSpec.ParseBody].
+func (OllamaChatEndpointSpec) ParseBody(
+	body []byte,
+	_ bool,
+) (internalapi.OriginalModel, *ollama.ChatRequest, bool, []byte, error) {
+	var req ollama.ChatRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return "", nil, false, nil, fmt.Errorf("%w: failed to parse JSON for /api/chat: %w", internalapi.ErrMalformedRequest, err)
+	}
+	if req.Model == "" {
+		return "", nil, false, nil, fmt.Errorf("%w: model field is required", internalapi.ErrInvalidRequestBody)
+	}
+	// The usage is always in the last object of the streamed response, so there's no need to mutate the body.
+	return req.Model, &req, req.IsStream(), nil, nil
+}
+
+// ParseMultipartBody implements [Spec.ParseMultipartBody].
+func (OllamaChatEndpointSpec) ParseMultipartBody([]byte, string, bool) (internalapi.OriginalModel, *ollama.ChatRequest, bool, []byte, error) {
+	return "", nil, false, nil, errMultipartNotSupported
+}
+
+// GetTranslator implements [EndpointSpec.GetTranslator].
+//
+// The backends other than Ollama are supported by translating the request to OpenAI Chat Completions API
+// followed by the translation of the /v1/chat/completions endpoint for the backend.
+func (OllamaChatEndpointSpec) GetTranslator(schema filterapi.VersionedAPISchema, modelNameOverride string) (translator.OllamaChatTranslator, error) {
+	if schema.Name == filterapi.APISchemaOllama {
+		return translator.NewOllamaToOllamaChatTranslator(modelNameOverride), nil
+	}
+	inner, err := ChatCompletionsEndpointSpec{}.GetTranslator(schema, modelNameOverride)
+	if err != nil {
+		return nil, err
+	}
+	return translator.NewOllamaToChatCompletionTranslator(inner), nil
+}
+
+// RedactSensitiveInfoFromRequest implements [EndpointSpec.RedactSensitiveInfoFromRequest].
+func (OllamaChatEndpointSpec) RedactSensitiveInfoFromRequest(req *ollama.ChatRequest) (redactedReq *ollama.ChatRequest, err error) {
+	redacted := *req
+	redacted.Messages = make([]ollama.Message, len(req.Messages))
+	for i, msg := range req.Messages {
+		msg.Content = redaction.RedactString(msg.Content)
+		msg.Thinking = redaction.RedactString(msg.Thinking)
+		if len(msg.Images) > 0 {
+			images := make([]string, len(msg.Images))
+			for j, image := range msg.Images {
+				images[j] = redaction.RedactString(image)
+			}
+			msg.Images = images
+		}
+		redacted.Messages[i] = msg
+	}
+	return &redacted, nil
+}
+
+// ParseBody implements [EndpointSpec.ParseBody].
+func (OllamaEmbedEndpointSpec) ParseBody(
+	body []byte,
+	_ bool,
+) (internalapi.OriginalModel, *ollama.EmbedRequest, bool, []byte, error) {
+	var req ollama.EmbedRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return "", nil, false, nil, fmt.Errorf("%w: failed to parse JSON for /api/embed: %w", internalapi.ErrMalformedRequest, err)
+	}
+	if req.Model == "" {
+		return "", nil, false, nil, fmt.Errorf("%w: model field is required", internalapi.ErrInvalidRequestBody)
+	}
+	return req.Model, &req, false, nil, nil
+}
+
+// ParseMultipartBody implements [Spec.ParseMultipartBody].
+func (OllamaEmbedEndpointSpec) ParseMultipartBody([]byte, string, bool) (internalapi.OriginalModel, *ollama.EmbedRequest, bool, []byte, error) {
+	return "", nil, false, nil, errMultipartNotSupported
+}
+
+// GetTranslator implements [EndpointSpec.GetTranslator].
+//
+// The backends other than Ollama are supported by translating the request to OpenAI Embeddings API
+// followed by the translation of the /v1/embeddings endpoint for the backend.
+func (OllamaEmbedEndpointSpec) GetTranslator(schema filterapi.VersionedAPISchema, modelNameOverride string) (translator.OllamaEmbedTranslator, error) {
+	if schema.Name == filterapi.APISchemaOllama {
+		return translator.NewOllamaToOllamaEmbedTranslator(modelNameOverride), nil
+	}
+	inner, err := EmbeddingsEndpointSpec{}.GetTranslator(schema, modelNameOverride)
+	if err != nil {
+		return nil, err
+	}
+	return translator.NewOllamaToEmbeddingTranslator(inner), nil
+}
+
+// RedactSensitiveInfoFromRequest implements [EndpointSpec.RedactSensitiveInfoFromRequest].
+func (OllamaEmbedEndpointSpec) RedactSensitiveInfoFromRequest(req *ollama.EmbedRequest) (redactedReq *ollama.EmbedRequest, err error) {
+	// Placeholder if redaction is required in future
+	return req, nil
+}
+
 // redactMessage redacts sensitive content from a chat message while preserving its type and structure.
 // This dispatches to role-specific redaction functions based on the message type.
 func redactMessage(msg openai.ChatCompletionMessageParamUnion) openai.ChatCompletionMessageParamUnion {
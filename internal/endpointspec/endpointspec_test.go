@@ -14,6 +14,7 @@ import (
 	"k8s.io/utils/ptr"
 
 	cohereschema "github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/ollama"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
 	"github.com/envoyproxy/ai-gateway/internal/filterapi"
 	"github.com/envoyproxy/ai-gateway/internal/json"
@@ -87,6 +88,10 @@ func TestChatCompletionsEndpointSpec_GetTranslator(t *testing.T) {
 		{Name: filterapi.APISchemaAzureOpenAI, Version: "2024-02-01"},
 		{Name: filterapi.APISchemaGCPVertexAI},
 		{Name: filterapi.APISchemaGCPAnthropic, Version: "2024-05-01"},
+		{Name: filterapi.APISchemaOCIGenAI, Version: "ocid1.compartment.oc1..aaaa"},
+		{Name: filterapi.APISchemaDashScope},
+		{Name: filterapi.APISchemaOllama},
+		{Name: filterapi.APISchemaHuggingFaceTGI},
 	}
 
 	for _, schema := range supported {
@@ -133,6 +138,9 @@ func TestCompletionsEndpointSpec_GetTranslator(t *testing.T) {
 	_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI}, "override")
 	require.NoError(t, err)
 
+	_, err = spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaHuggingFaceTGI}, "override")
+	require.NoError(t, err)
+
 	_, err = spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaAWSBedrock}, "override")
 	require.ErrorContains(t, err, "unsupported API schema")
 }
@@ -184,6 +192,10 @@ func TestEmbeddingsEndpointSpec_GetTranslator(t *testing.T) {
 		{Name: filterapi.APISchemaAzureOpenAI},
 		{Name: filterapi.APISchemaGCPVertexAI},
 		{Name: filterapi.APISchemaAWSBedrock},
+		{Name: filterapi.APISchemaOCIGenAI, Version: "ocid1.compartment.oc1..aaaa"},
+		{Name: filterapi.APISchemaDashScope},
+		{Name: filterapi.APISchemaOllama},
+		{Name: filterapi.APISchemaHuggingFaceTGI},
 	}
 	for _, schema := range supported {
 		s := schema
@@ -1350,6 +1362,109 @@ func TestTranslationEndpointSpec_RedactSensitiveInfoFromRequest(t *testing.T) {
 	})
 }
 
+func TestOllamaChatEndpointSpec_ParseBody(t *testing.T) {
+	spec := OllamaChatEndpointSpec{}
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, _, _, _, err := spec.ParseBody([]byte("{"), false)
+		require.ErrorContains(t, err, "malformed request")
+	})
+
+	t.Run("missing model", func(t *testing.T) {
+		_, _, _, _, err := spec.ParseBody([]byte(`{"messages":[]}`), false)
+		require.ErrorContains(t, err, "model field is required")
+	})
+
+	t.Run("streaming by default", func(t *testing.T) {
+		model, parsed, stream, mutated, err := spec.ParseBody([]byte(`{"model":"llama3.2","messages":[{"role":"user","content":"hi"}]}`), false)
+		require.NoError(t, err)
+		require.Equal(t, "llama3.2", model)
+		require.True(t, stream)
+		require.Len(t, parsed.Messages, 1)
+		require.Nil(t, mutated)
+	})
+
+	t.Run("non_streaming", func(t *testing.T) {
+		_, _, stream, _, err := spec.ParseBody([]byte(`{"model":"llama3.2","stream":false}`), false)
+		require.NoError(t, err)
+		require.False(t, stream)
+	})
+}
+
+func TestOllamaChatEndpointSpec_GetTranslator(t *testing.T) {
+	spec := OllamaChatEndpointSpec{}
+	for _, schema := range []filterapi.VersionedAPISchema{
+		{Name: filterapi.APISchemaOllama},
+		{Name: filterapi.APISchemaOpenAI, Prefix: "v1"},
+		{Name: filterapi.APISchemaAWSBedrock},
+		{Name: filterapi.APISchemaHuggingFaceTGI},
+	} {
+		tr, err := spec.GetTranslator(schema, "override")
+		require.NoError(t, err)
+		require.NotNil(t, tr)
+	}
+
+	_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaCohere}, "override")
+	require.ErrorContains(t, err, "unsupported API schema")
+}
+
+func TestOllamaChatEndpointSpec_RedactSensitiveInfoFromRequest(t *testing.T) {
+	spec := OllamaChatEndpointSpec{}
+	req := &ollama.ChatRequest{
+		Model: "llama3.2",
+		Messages: []ollama.Message{
+			{Role: "user", Content: "my secret", Images: []string{"AAAA"}},
+		},
+	}
+	redacted, err := spec.RedactSensitiveInfoFromRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, "llama3.2", redacted.Model)
+	require.Equal(t, redaction.RedactString("my secret"), redacted.Messages[0].Content)
+	require.Equal(t, []string{redaction.RedactString("AAAA")}, redacted.Messages[0].Images)
+	// The original request is not modified.
+	require.Equal(t, "my secret", req.Messages[0].Content)
+	require.Equal(t, []string{"AAAA"}, req.Messages[0].Images)
+}
+
+func TestOllamaEmbedEndpointSpec_ParseBody(t *testing.T) {
+	spec := OllamaEmbedEndpointSpec{}
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, _, _, _, err := spec.ParseBody([]byte("{"), false)
+		require.ErrorContains(t, err, "malformed request")
+	})
+
+	t.Run("missing model", func(t *testing.T) {
+		_, _, _, _, err := spec.ParseBody([]byte(`{"input":"hi"}`), false)
+		require.ErrorContains(t, err, "model field is required")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		model, parsed, stream, mutated, err := spec.ParseBody([]byte(`{"model":"all-minilm","input":["a","b"]}`), false)
+		require.NoError(t, err)
+		require.Equal(t, "all-minilm", model)
+		require.False(t, stream)
+		require.NotNil(t, parsed)
+		require.Nil(t, mutated)
+	})
+}
+
+func TestOllamaEmbedEndpointSpec_GetTranslator(t *testing.T) {
+	spec := OllamaEmbedEndpointSpec{}
+	for _, schema := range []filterapi.VersionedAPISchema{
+		{Name: filterapi.APISchemaOllama},
+		{Name: filterapi.APISchemaOpenAI},
+		{Name: filterapi.APISchemaHuggingFaceTGI},
+	} {
+		tr, err := spec.GetTranslator(schema, "override")
+		require.NoError(t, err)
+		require.NotNil(t, tr)
+	}
+
+	_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaCohere}, "override")
+	require.ErrorContains(t, err, "unsupported API schema")
+}
+
 // --- ParseMultipartBody defaults for JSON-only endpoints ---
 
 func TestParseMultipartBody_RejectsJSONOnlyEndpoints(t *testing.T) {
@@ -1367,4 +1482,10 @@ func TestParseMultipartBody_RejectsJSONOnlyEndpoints(t *testing.T) {
 
 	_, _, _, _, err = SpeechEndpointSpec{}.ParseMultipartBody(nil, "", false)
 	require.ErrorContains(t, err, "multipart body not supported")
+
+	_, _, _, _, err = OllamaChatEndpointSpec{}.ParseMultipartBody(nil, "", false)
+	require.ErrorContains(t, err, "multipart body not supported")
+
+	_, _, _, _, err = OllamaEmbedEndpointSpec{}.ParseMultipartBody(nil, "", false)
+	require.ErrorContains(t, err, "multipart body not supported")
 }
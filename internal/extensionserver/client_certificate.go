@@ -0,0 +1,140 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package extensionserver
+
+import (
+	"context"
+	"fmt"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	aigv1b1 "github.com/envoyproxy/ai-gateway/api/v1beta1"
+)
+
+// transportSocketMatchMetadataNamespace is the namespace of the endpoint metadata matched against the transport socket
+// matches of the cluster. Envoy Gateway sets it on the endpoints of each backend whose TLS is configured.
+const transportSocketMatchMetadataNamespace = "envoy.transport_socket_match"
+
+// maybeSetClientCertificates adds the client certificate of the backends of the given rule that have a
+// ClientCertificate BackendSecurityPolicy to the upstream TLS context of their endpoints in the cluster.
+//
+// The TLS connection to the backend itself is configured by Envoy Gateway from the BackendTLSPolicy targeting the
+// Backend, as a transport socket match per backend. So, the backends whose TLS is not configured are skipped.
+func (s *Server) maybeSetClientCertificates(ctx context.Context, cluster *clusterv3.Cluster, route *aigv1b1.AIGatewayRoute, rule *aigv1b1.AIGatewayRouteRule) error {
+	var lbEndpointIndex int
+	for _, backendRef := range rule.BackendRefs {
+		// The weight of 0 means this backend is disabled and is not included in the LoadAssignment by EG.
+		if backendRef.Weight != nil && *backendRef.Weight == 0 {
+			continue
+		}
+		if lbEndpointIndex >= len(cluster.LoadAssignment.Endpoints) {
+			return nil
+		}
+		endpoints := cluster.LoadAssignment.Endpoints[lbEndpointIndex]
+		lbEndpointIndex++
+
+		namespace := backendRef.GetNamespace(route.Namespace)
+		policy, err := s.clientCertificatePolicy(ctx, namespace, backendRef.Name)
+		if err != nil {
+			return err
+		}
+		if policy == nil {
+			continue
+		}
+
+		socket := transportSocketOf(cluster, endpoints)
+		if socket == nil || socket.Name != wellknown.TransportSocketTLS {
+			s.log.Info("Skipping the client certificate of a backend without TLS, configure a BackendTLSPolicy for its Backend",
+				"cluster_name", cluster.Name, "backend", backendRef.Name, "backend_security_policy", policy.Name)
+			continue
+		}
+		certificate, err := s.clientCertificate(ctx, policy)
+		if err != nil {
+			s.log.Error(err, "failed to read the client certificate", "cluster_name", cluster.Name,
+				"backend", backendRef.Name, "backend_security_policy", policy.Name)
+			continue
+		}
+		tlsContext := &tlsv3.UpstreamTlsContext{}
+		if err = socket.GetTypedConfig().UnmarshalTo(tlsContext); err != nil {
+			return fmt.Errorf("failed to unmarshal UpstreamTlsContext: %w", err)
+		}
+		if tlsContext.CommonTlsContext == nil {
+			tlsContext.CommonTlsContext = &tlsv3.CommonTlsContext{}
+		}
+		tlsContext.CommonTlsContext.TlsCertificates = []*tlsv3.TlsCertificate{certificate}
+		tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs = nil
+		tlsContextAny, err := toAny(tlsContext)
+		if err != nil {
+			return fmt.Errorf("failed to marshal UpstreamTlsContext to Any: %w", err)
+		}
+		socket.ConfigType = &corev3.TransportSocket_TypedConfig{TypedConfig: tlsContextAny}
+	}
+	return nil
+}
+
+// transportSocketOf returns the transport socket used for the given endpoints of the cluster, if any.
+func transportSocketOf(cluster *clusterv3.Cluster, endpoints *endpointv3.LocalityLbEndpoints) *corev3.TransportSocket {
+	match := endpoints.GetMetadata().GetFilterMetadata()[transportSocketMatchMetadataNamespace]
+	name := match.GetFields()["name"].GetStringValue()
+	if name == "" {
+		return cluster.TransportSocket
+	}
+	for _, m := range cluster.TransportSocketMatches {
+		if m.Name == name {
+			return m.TransportSocket
+		}
+	}
+	return nil
+}
+
+// clientCertificatePolicy returns the ClientCertificate BackendSecurityPolicy targeting the given AIServiceBackend,
+// or nil if there is none.
+func (s *Server) clientCertificatePolicy(ctx context.Context, namespace, name string) (*aigv1b1.BackendSecurityPolicy, error) {
+	var policies aigv1b1.BackendSecurityPolicyList
+	if err := s.k8sClient.List(ctx, &policies, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list BackendSecurityPolicies: %w", err)
+	}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		if policy.Spec.Type != aigv1b1.BackendSecurityPolicyTypeClientCertificate || policy.Spec.ClientCertificate == nil {
+			continue
+		}
+		for _, ref := range policy.Spec.TargetRefs {
+			if string(ref.Name) == name && ref.Group == aigv1b1.GroupName && ref.Kind == "AIServiceBackend" {
+				return policy, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// clientCertificate returns the TLS certificate held by the Secret referenced by the given ClientCertificate
+// BackendSecurityPolicy.
+func (s *Server) clientCertificate(ctx context.Context, policy *aigv1b1.BackendSecurityPolicy) (*tlsv3.TlsCertificate, error) {
+	ref := policy.Spec.ClientCertificate.SecretRef
+	namespace := policy.Namespace
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+	var secret corev1.Secret
+	if err := s.k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: string(ref.Name)}, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret %s/%s: %w", namespace, ref.Name, err)
+	}
+	certificateChain, privateKey := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+	if len(certificateChain) == 0 || len(privateKey) == 0 {
+		return nil, fmt.Errorf("secret %s/%s must contain %s and %s", namespace, ref.Name, corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+	}
+	return &tlsv3.TlsCertificate{
+		CertificateChain: &corev3.DataSource{Specifier: &corev3.DataSource_InlineBytes{InlineBytes: certificateChain}},
+		PrivateKey:       &corev3.DataSource{Specifier: &corev3.DataSource_InlineBytes{InlineBytes: privateKey}},
+	}, nil
+}
@@ -0,0 +1,114 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package extensionserver
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	aigv1b1 "github.com/envoyproxy/ai-gateway/api/v1beta1"
+)
+
+func TestServer_maybeSetClientCertificates(t *testing.T) {
+	c := newFakeClient()
+	const ns = "test-ns"
+	require.NoError(t, c.Create(t.Context(), &aigv1b1.AIGatewayRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: ns},
+		Spec: aigv1b1.AIGatewayRouteSpec{
+			Rules: []aigv1b1.AIGatewayRouteRule{{
+				BackendRefs: []aigv1b1.AIGatewayRouteRuleBackendRef{
+					{Name: "mtls"}, {Name: "disabled", Weight: ptr.To[int32](0)}, {Name: "plain"}, {Name: "mtls-no-tls"}, {Name: "missing-secret"},
+				},
+			}},
+		},
+	}))
+	for _, backend := range []string{"mtls", "mtls-no-tls", "missing-secret"} {
+		secretName := "client-tls"
+		if backend == "missing-secret" {
+			secretName = "missing"
+		}
+		require.NoError(t, c.Create(t.Context(), &aigv1b1.BackendSecurityPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: backend + "-policy", Namespace: ns},
+			Spec: aigv1b1.BackendSecurityPolicySpec{
+				TargetRefs: []gwapiv1a2.LocalPolicyTargetReference{{Group: aigv1b1.GroupName, Kind: "AIServiceBackend", Name: gwapiv1.ObjectName(backend)}},
+				Type:       aigv1b1.BackendSecurityPolicyTypeClientCertificate,
+				ClientCertificate: &aigv1b1.BackendSecurityPolicyClientCertificate{
+					SecretRef: &gwapiv1.SecretObjectReference{Name: gwapiv1.ObjectName(secretName)},
+				},
+			},
+		}))
+	}
+	require.NoError(t, c.Create(t.Context(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "client-tls", Namespace: ns},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+	}))
+
+	tlsSocket := func(t *testing.T) *corev3.TransportSocket {
+		a, err := toAny(&tlsv3.UpstreamTlsContext{Sni: "backend.internal"})
+		require.NoError(t, err)
+		return &corev3.TransportSocket{Name: wellknown.TransportSocketTLS, ConfigType: &corev3.TransportSocket_TypedConfig{TypedConfig: a}}
+	}
+	matchMetadata := func(name string) *corev3.Metadata {
+		return &corev3.Metadata{FilterMetadata: map[string]*structpb.Struct{
+			transportSocketMatchMetadataNamespace: {Fields: map[string]*structpb.Value{"name": structpb.NewStringValue(name)}},
+		}}
+	}
+	const clusterName = "httproute/test-ns/route/rule/0"
+	cluster := &clusterv3.Cluster{
+		Name: clusterName,
+		LoadAssignment: &endpointv3.ClusterLoadAssignment{Endpoints: []*endpointv3.LocalityLbEndpoints{
+			{Metadata: matchMetadata(clusterName + "/tls/0")},
+			{Metadata: matchMetadata(clusterName + "/tls/1")},
+			{},
+			{Metadata: matchMetadata(clusterName + "/tls/3")},
+		}},
+		TransportSocketMatches: []*clusterv3.Cluster_TransportSocketMatch{
+			{Name: clusterName + "/tls/0", TransportSocket: tlsSocket(t)},
+			{Name: clusterName + "/tls/1", TransportSocket: tlsSocket(t)},
+			{Name: clusterName + "/tls/3", TransportSocket: tlsSocket(t)},
+		},
+	}
+
+	var buf bytes.Buffer
+	s, err := New(c, logr.FromSlogHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{})), udsPath, false, nil, nil, "envoy-ai-gateway-ratelimit.envoy-gateway-system", 5, false)
+	require.NoError(t, err)
+	require.NoError(t, s.maybeModifyCluster(t.Context(), cluster))
+
+	tlsContextOf := func(i int) *tlsv3.UpstreamTlsContext {
+		tlsContext := &tlsv3.UpstreamTlsContext{}
+		require.NoError(t, cluster.TransportSocketMatches[i].TransportSocket.GetTypedConfig().UnmarshalTo(tlsContext))
+		return tlsContext
+	}
+	// The client certificate is added to the TLS context of the "mtls" backend, keeping the rest of it.
+	tlsContext := tlsContextOf(0)
+	require.Equal(t, "backend.internal", tlsContext.Sni)
+	require.Len(t, tlsContext.CommonTlsContext.TlsCertificates, 1)
+	require.Equal(t, []byte("cert"), tlsContext.CommonTlsContext.TlsCertificates[0].CertificateChain.GetInlineBytes())
+	require.Equal(t, []byte("key"), tlsContext.CommonTlsContext.TlsCertificates[0].PrivateKey.GetInlineBytes())
+	// The "plain" backend has no ClientCertificate policy.
+	require.Nil(t, tlsContextOf(1).CommonTlsContext)
+	// The "mtls-no-tls" backend has no TLS configured.
+	require.Contains(t, buf.String(), "Skipping the client certificate of a backend without TLS")
+	// The secret of the "missing-secret" backend doesn't exist.
+	require.Nil(t, tlsContextOf(2).CommonTlsContext)
+	require.Contains(t, buf.String(), "failed to read the client certificate")
+}
@@ -166,6 +166,8 @@ func (s *Server) PostTranslateModify(ctx context.Context, req *egextension.PostT
 //
 // 4. Configures special handling for InferencePool clusters (ORIGINAL_DST type).
 //
+// 5. Adds the client certificates of the backends with a ClientCertificate BackendSecurityPolicy to their TLS context.
+//
 // The resulting configuration is similar to the envoy.yaml files in tests/data-plane/.
 // Only clusters with names matching the AIGatewayRoute pattern are modified.
 func (s *Server) maybeModifyCluster(ctx context.Context, cluster *clusterv3.Cluster) error {
@@ -260,6 +262,9 @@ func (s *Server) maybeModifyCluster(ctx context.Context, cluster *clusterv3.Clus
 					)
 				}
 			}
+			if err = s.maybeSetClientCertificates(ctx, cluster, &aigwRoute, httpRouteRule); err != nil {
+				return fmt.Errorf("failed to set client certificates: %w", err)
+			}
 		}
 	} else {
 		// we can only specify one backend in a rule for InferencePool.
@@ -658,7 +663,11 @@ func (s *Server) insertRouterLevelAIGatewayExtProc(listener *listenerv3.Listener
 				ResponseBodyMode:    extprocv3.ProcessingMode_BUFFERED,
 				ResponseTrailerMode: extprocv3.ProcessingMode_SKIP,
 			},
-			MessageTimeout:    durationpb.New(10 * time.Second),
+			MessageTimeout: durationpb.New(10 * time.Second),
+			// The gateway-side tool execution extends the timeout of the response body message while it calls the tools
+			// and the model.
+			MaxMessageTimeout: durationpb.New(internalapi.RouterLevelExtProcMaxMessageTimeout),
+			RequestAttributes: []string{internalapi.DestinationAddressAttribute},
 			FailureModeAllow:  false,
 			AllowModeOverride: true,
 		})
@@ -9,6 +9,7 @@ import (
 	"context"
 	"io"
 	"log/slog"
+	"strconv"
 	"testing"
 	"time"
 
@@ -311,6 +312,31 @@ func (m *mockBackendAuthHandler) Do(context.Context, map[string]string, []byte)
 	return []internalapi.Header{{"foo", "mock-auth-handler"}}, nil
 }
 
+// mockBackendAuthResponseHandler implements [filterapi.BackendAuthResponseHandler] for testing.
+type mockBackendAuthResponseHandler struct {
+	mockBackendAuthHandler
+	requestHeaders, responseHeaders map[string]string
+}
+
+// ResponseHeaders implements [filterapi.BackendAuthResponseHandler.ResponseHeaders].
+func (m *mockBackendAuthResponseHandler) ResponseHeaders(_ context.Context, requestHeaders, responseHeaders map[string]string) {
+	m.requestHeaders, m.responseHeaders = requestHeaders, responseHeaders
+}
+
+// mockBackendAuthBodySigner implements [filterapi.BackendAuthBodySigner] for testing.
+type mockBackendAuthBodySigner struct {
+	body []byte
+}
+
+// Do implements [filterapi.BackendAuthHandler.Do].
+func (m *mockBackendAuthBodySigner) Do(_ context.Context, _ map[string]string, mutatedBody []byte) ([]internalapi.Header, error) {
+	m.body = mutatedBody
+	return []internalapi.Header{{"content-length", strconv.Itoa(len(mutatedBody))}}, nil
+}
+
+// SignsBody implements [filterapi.BackendAuthBodySigner.SignsBody].
+func (m *mockBackendAuthBodySigner) SignsBody() bool { return true }
+
 // mockBackendAuthHandlerError implements [filterapi.BackendAuthHandler] for testing auth errors.
 type mockBackendAuthHandlerError struct {
 	err error
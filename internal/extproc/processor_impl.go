@@ -97,6 +97,14 @@ type (
 		tracer tracingapi.RequestTracer[ReqT, RespT, RespChunkT]
 		// span is the tracing span for this request, created in ProcessRequestBody.
 		span tracingapi.Span[RespT, RespChunkT]
+		// traceHeaders are the headers injected by the tracer to propagate the context of the span.
+		traceHeaders []*corev3.HeaderValueOption
+		// destinationAddress is the address of the listener that received the request.
+		destinationAddress string
+		// toolExecution is the gateway-side tool execution of the request, if enabled for its route.
+		toolExecution *toolExecution
+		// virtualKey is the virtual key the request is authenticated with, if any.
+		virtualKey *filterapi.RuntimeVirtualKey
 		// upstreamFilterCount is the number of upstream filters that have been processed.
 		// This is used to determine if the request is a retry request.
 		upstreamFilterCount int
@@ -176,6 +184,9 @@ func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRespons
 	// r.upstreamFilter can be nil.
 	if r.upstreamFilter != nil { // See the comment on the "upstreamFilter" field.
 		resp, err = r.upstreamFilter.ProcessResponseBody(ctx, body)
+		if err == nil && r.toolExecution != nil && body.EndOfStream {
+			resp = r.runToolExecution(ctx, body, resp)
+		}
 	} else {
 		resp, err = r.passThroughProcessor.ProcessResponseBody(ctx, body)
 	}
@@ -217,7 +228,8 @@ func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRequest
 		mutatedOriginalBody []byte
 		err                 error
 	)
-	costConfigured := len(r.config.RequestCosts) > 0 || len(r.config.GlobalRequestCosts) > 0
+	// The token usage is also needed for the budgets of the virtual keys.
+	costConfigured := len(r.config.RequestCosts) > 0 || len(r.config.GlobalRequestCosts) > 0 || len(r.config.VirtualKeys) > 0
 	contentType := r.requestHeaders["content-type"]
 	if strings.HasPrefix(strings.ToLower(contentType), "multipart/form-data") {
 		originalModel, body, stream, mutatedOriginalBody, err = r.eh.ParseMultipartBody(rawBody.Body, contentType, costConfigured)
@@ -259,9 +271,14 @@ func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRequest
 		r.originalRequestBodyRaw = rawBody.Body
 	}
 
-	r.requestHeaders[internalapi.ModelNameHeaderKeyDefault] = originalModel
+	// The virtual key is validated before routing, so that the identity of the client can be used by the routing and
+	// the rate limiting.
+	additionalHeaders, removedHeaders, rejection := r.authenticateVirtualKey(originalModel)
+	if rejection != nil {
+		return rejection, nil
+	}
 
-	var additionalHeaders []*corev3.HeaderValueOption
+	r.requestHeaders[internalapi.ModelNameHeaderKeyDefault] = originalModel
 	additionalHeaders = append(additionalHeaders, &corev3.HeaderValueOption{
 		// Set the original model to the request header with the key `x-ai-eg-model`.
 		Header: &corev3.HeaderValue{Key: internalapi.ModelNameHeaderKeyDefault, RawValue: []byte(originalModel)},
@@ -283,7 +300,8 @@ func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRequest
 
 	// Tracing may need to inject headers, so create a header mutation here.
 	headerMutation := &extprocv3.HeaderMutation{
-		SetHeaders: additionalHeaders,
+		SetHeaders:    additionalHeaders,
+		RemoveHeaders: removedHeaders,
 	}
 	r.span = r.tracer.StartSpanAndInjectHeaders(
 		ctx,
@@ -292,6 +310,8 @@ func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRequest
 		body,
 		rawBody.Body,
 	)
+	r.traceHeaders = headerMutation.SetHeaders[len(additionalHeaders):]
+	r.destinationAddress, _ = ctx.Value(destinationAddressContextKey).(string)
 
 	return &extprocv3.ProcessingResponse{
 		Response: &extprocv3.ProcessingResponse_RequestBody{
@@ -330,11 +350,20 @@ func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessReque
 	reqModel := cmp.Or(u.requestHeaders[internalapi.ModelNameHeaderKeyDefault], u.parent.originalModel)
 	u.metrics.SetRequestModel(reqModel)
 
+	if rejection := u.checkVirtualKeyRoute(); rejection != nil {
+		u.metrics.RecordRequestCompletion(ctx, false, u.requestHeaders)
+		return rejection, nil
+	}
+
 	// We force the body mutation in the following cases:
 	// * The request is a retry request because the body mutation might have happened the previous iteration.
 	// * The request is a streaming request, and the IncludeUsage option is set to false since we need to ensure that
 	//	the token usage is calculated correctly without being bypassed.
+	// * The backend auth signs the body, which must then be the body sent to the backend.
 	forceBodyMutation := u.onRetry() || u.parent.forceBodyMutation
+	if s, ok := u.handler.(filterapi.BackendAuthBodySigner); ok && s.SignsBody() {
+		forceBodyMutation = true
+	}
 	newHeaders, newBody, err := u.translator.RequestBody(u.parent.originalRequestBodyRaw, u.parent.originalRequestBody, forceBodyMutation)
 	if err != nil {
 		if userFacingErr := internalapi.GetUserFacingError(err); userFacingErr != nil {
@@ -348,6 +377,8 @@ func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessReque
 	}
 
 	headerMutation, bodyMutation := mutationsFromTranslationResult(newHeaders, newBody)
+	// The virtual key headers are only meant for the filters of the gateway.
+	headerMutation.RemoveHeaders = append(headerMutation.RemoveHeaders, virtualKeyHeaders(u.requestHeaders)...)
 
 	// Apply header mutations from the route and also restore original headers on retry.
 	if h := u.headerMutator; h != nil {
@@ -449,6 +480,9 @@ func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRespo
 	}()
 
 	u.responseHeaders = headersToMap(headers)
+	if h, ok := u.handler.(filterapi.BackendAuthResponseHandler); ok {
+		h.ResponseHeaders(ctx, u.requestHeaders, u.responseHeaders)
+	}
 	if enc := u.responseHeaders["content-encoding"]; enc != "" {
 		u.responseEncoding = enc
 	}
@@ -460,8 +494,11 @@ func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRespo
 		return nil, fmt.Errorf("failed to transform response headers: %w", err)
 	}
 	var mode *extprocv3http.ProcessingMode
-	if u.parent.stream && u.responseHeaders[":status"] == "200" {
-		// We only stream the response if the status code is 200 and the response is a stream.
+	if u.parent.stream && u.responseHeaders[":status"] == "200" && u.parent.toolExecution == nil {
+		// We only stream the response if the status code is 200 and the response is a stream. The tool execution
+		// needs the whole response to find the tool calls of the model, and no body can be sent to the client after
+		// the end of the buffered one, so its final answer is returned at once. This limitation is documented in the
+		// ToolExecution of the AIGatewayRoute API.
 		mode = &extprocv3http.ProcessingMode{ResponseBodyMode: extprocv3http.ProcessingMode_STREAMED}
 	}
 	headerMutation, _ := mutationsFromTranslationResult(newHeaders, nil)
@@ -579,6 +616,9 @@ func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRespo
 		}
 		resp.DynamicMetadata = metadata
 	}
+	if body.EndOfStream {
+		u.recordVirtualKeyCost()
+	}
 
 	if body.EndOfStream && u.parent.span != nil {
 		u.parent.span.EndSpan()
@@ -621,6 +661,10 @@ func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) SetBackend(c
 		panic(fmt.Sprintf("BUG: expected routeProcessor to be of type *routerProcessor[%T], got %T", rp, routeProcessor))
 	}
 	rp.upstreamFilterCount++
+	if rp.upstreamFilterCount == 1 {
+		// The request body is updated with the tools only once, and reused on retries.
+		rp.maybeStartToolExecution(ctx, routeName)
+	}
 	u.metrics.SetBackend(backend.Backend)
 	u.modelNameOverride = backend.Backend.ModelNameOverride
 	u.backendName = backend.Backend.Name
@@ -701,10 +745,14 @@ func buildContentLengthDynamicMetadataOnRequest(contentLength int) *structpb.Str
 }
 
 func buildRequestHeaderDynamicMetadata(requestHeaders map[string]string) *structpb.Struct {
-	if len(LogRequestHeaderAttributes) == 0 {
+	virtualKey, hasVirtualKey := requestHeaders[internalapi.VirtualKeyHeader]
+	if len(LogRequestHeaderAttributes) == 0 && !hasVirtualKey {
 		return nil
 	}
-	fields := make(map[string]*structpb.Value, len(LogRequestHeaderAttributes))
+	fields := make(map[string]*structpb.Value, len(LogRequestHeaderAttributes)+1)
+	if hasVirtualKey {
+		fields[internalapi.VirtualKeyAttribute] = structpb.NewStringValue(virtualKey)
+	}
 	for header, attr := range LogRequestHeaderAttributes {
 		value, ok := requestHeaders[header]
 		if !ok || value == "" {
@@ -784,6 +832,7 @@ func evalCost(costType filterapi.LLMRequestCostType, celProg cel.Program, costs
 			requestHeaders[internalapi.ModelNameHeaderKeyDefault],
 			backendName,
 			routeName,
+			requestHeaders[internalapi.VirtualKeyHeader],
 			in,
 			cachedIn,
 			cacheCreation,
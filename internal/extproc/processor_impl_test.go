@@ -13,6 +13,7 @@ import (
 	"io"
 	"log/slog"
 	"mime/multipart"
+	"strconv"
 	"testing"
 
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
@@ -265,6 +266,25 @@ func Test_chatCompletionProcessorUpstreamFilter_ProcessResponseHeaders(t *testin
 		require.Empty(t, commonRes.HeaderMutation)
 		require.Equal(t, &extprocv3http.ProcessingMode{ResponseBodyMode: extprocv3http.ProcessingMode_STREAMED}, res.ModeOverride)
 	})
+	t.Run("backend auth response handler", func(t *testing.T) {
+		inHeaders := &corev3.HeaderMap{
+			Headers: []*corev3.HeaderValue{{Key: ":status", Value: "429"}, {Key: "retry-after", Value: "10"}},
+		}
+		expHeaders := map[string]string{":status": "429", "retry-after": "10"}
+		requestHeaders := map[string]string{"authorization": "Bearer key"}
+		handler := &mockBackendAuthResponseHandler{}
+		p := &chatCompletionProcessorUpstreamFilter{
+			translator:     &mockTranslator{t: t, expHeaders: expHeaders},
+			metrics:        &mockMetrics{},
+			parent:         &chatCompletionProcessorRouterFilter{},
+			handler:        handler,
+			requestHeaders: requestHeaders,
+		}
+		_, err := p.ProcessResponseHeaders(t.Context(), inHeaders)
+		require.NoError(t, err)
+		require.Equal(t, requestHeaders, handler.requestHeaders)
+		require.Equal(t, expHeaders, handler.responseHeaders)
+	})
 	t.Run("error/streaming", func(t *testing.T) {
 		inHeaders := &corev3.HeaderMap{
 			Headers: []*corev3.HeaderValue{{Key: ":status", Value: "500"}, {Key: "dog", RawValue: []byte("cat")}},
@@ -820,6 +840,50 @@ func Test_chatCompletionProcessorUpstreamFilter_ProcessRequestHeaders_BodyReplac
 			"buildContentLengthDynamicMetadataOnRequest must not be called when the body is not replaced")
 	})
 
+	t.Run("no translator body, body signing auth -> CONTINUE_AND_REPLACE", func(t *testing.T) {
+		someBody := bodyFromModel(t, "some-model", false, nil)
+		headers := map[string]string{
+			":path":                               "/foo",
+			internalapi.ModelNameHeaderKeyDefault: "some-model",
+		}
+		var expBody openai.ChatCompletionRequest
+		require.NoError(t, json.Unmarshal(someBody, &expBody))
+
+		mt := &mockTranslator{
+			t:                           t,
+			expRequestBody:              &expBody,
+			retHeaderMutation:           []internalapi.Header{{":path", "/v1/chat/completions"}},
+			retBodyMutation:             nil,
+			expForceRequestBodyMutation: true,
+		}
+		handler := &mockBackendAuthBodySigner{}
+		p := &chatCompletionProcessorUpstreamFilter{
+			parent: &chatCompletionProcessorRouterFilter{
+				config:                 &filterapi.RuntimeConfig{},
+				logger:                 slog.Default(),
+				originalRequestBodyRaw: someBody,
+				originalRequestBody:    &expBody,
+				originalModel:          "some-model",
+			},
+			requestHeaders: headers,
+			metrics:        &mockMetrics{},
+			translator:     mt,
+			handler:        handler,
+			bodyMutator:    bodymutator.NewBodyMutator(nil, someBody),
+		}
+
+		resp, err := p.ProcessRequestHeaders(t.Context(), nil)
+		require.NoError(t, err)
+
+		// The signed body is the body sent to the backend, not an empty one.
+		commonRes := resp.Response.(*extprocv3.ProcessingResponse_RequestHeaders).RequestHeaders.Response
+		require.Equal(t, extprocv3.CommonResponse_CONTINUE_AND_REPLACE, commonRes.Status)
+		require.Equal(t, someBody, handler.body)
+		require.Equal(t, someBody, commonRes.BodyMutation.GetBody())
+		require.Equal(t, "content-length", commonRes.HeaderMutation.SetHeaders[1].Header.Key)
+		require.Equal(t, strconv.Itoa(len(someBody)), string(commonRes.HeaderMutation.SetHeaders[1].Header.RawValue))
+	})
+
 	t.Run("translator body present -> CONTINUE_AND_REPLACE", func(t *testing.T) {
 		someBody := bodyFromModel(t, "some-model", false, nil)
 		headers := map[string]string{
@@ -14,6 +14,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"time"
 	"unicode/utf8"
 
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
@@ -23,6 +24,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/structpb"
 
 	"github.com/envoyproxy/ai-gateway/internal/backendauth"
@@ -84,6 +86,8 @@ func (s *Server) LoadConfig(ctx context.Context, config *filterapi.Config) error
 		return fmt.Errorf("cannot create runtime filter config: %w", err)
 	}
 	s.config = newConfig // This is racey, but we don't care.
+	pruneVirtualKeySpends(config.VirtualKeys)
+	backendauth.PruneAPIKeyStates(config.Backends)
 	return nil
 }
 
@@ -116,6 +120,13 @@ func (s *Server) processorForPath(requestHeaders map[string]string, isUpstreamFi
 	return newProcessor(s.config, requestHeaders, logger, isUpstreamFilter, s.enableRedaction)
 }
 
+// messageTimeoutExtender is implemented by the processors that may take longer than the message timeout of the filter
+// to process a message.
+type messageTimeoutExtender interface {
+	// messageTimeout returns the timeout to process the given message, or zero to keep the message timeout of the filter.
+	messageTimeout(req *extprocv3.ProcessingRequest) time.Duration
+}
+
 // originalPathHeader is the header used to pass the original path to the processor.
 // This is used in the upstream filter level to determine the original path of the request on retry.
 const originalPathHeader = internalapi.OriginalPathHeader
@@ -176,8 +187,11 @@ func (s *Server) Process(stream extprocv3.ExternalProcessor_ProcessServer) error
 		if headers := req.GetRequestHeaders().GetHeaders(); headers != nil {
 			headersMap := headersToMap(headers)
 			originalReqID = headersMap["x-request-id"]
-			// Assume that when attributes are set, this stream is for the upstream filter level.
-			isUpstreamFilter = req.GetAttributes() != nil
+			// Assume that when attributes other than the destination address are set, this stream is for the upstream
+			// filter level.
+			attributes := req.GetAttributes()
+			destinationAddress := attributes["envoy.filters.http.ext_proc"].GetFields()[internalapi.DestinationAddressAttribute].GetStringValue()
+			isUpstreamFilter = attributes != nil && destinationAddress == ""
 
 			if isUpstreamFilter {
 				// For upstream filter, use the internal request ID passed from the router filter
@@ -189,6 +203,9 @@ func (s *Server) Process(stream extprocv3.ExternalProcessor_ProcessServer) error
 				// For router filter, create a unique internal request ID to avoid race conditions
 				// with duplicate x-request-id values by appending a UUID suffix to the original request ID
 				internalReqID = originalReqID + "-" + s.uuidFn()
+				if destinationAddress != "" {
+					ctx = context.WithValue(ctx, destinationAddressContextKey, destinationAddress)
+				}
 			}
 
 			// Create request-scoped logger with request_id before creating processor
@@ -230,6 +247,17 @@ func (s *Server) Process(stream extprocv3.ExternalProcessor_ProcessServer) error
 			}
 		}
 
+		if e, ok := p.(messageTimeoutExtender); ok {
+			if timeout := e.messageTimeout(req); timeout > 0 {
+				// Extend the timeout of the message before processing it. This requires the max_message_timeout
+				// of the filter to be configured.
+				if err := stream.Send(&extprocv3.ProcessingResponse{OverrideMessageTimeout: durationpb.New(timeout)}); err != nil {
+					s.logger.Error("cannot send message timeout override", slog.String("error", err.Error()))
+					return status.Errorf(codes.Unknown, "cannot send message timeout override: %v", err)
+				}
+			}
+		}
+
 		// At this point, p is guaranteed to be a valid processor either from the concrete processor or the passThroughProcessor.
 		resp, err := s.processMsg(ctx, p, req, internalReqID, isUpstreamFilter)
 		if err != nil {
@@ -0,0 +1,482 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package extproc
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/tidwall/sjson"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+// MCPSessionConnector opens MCP client sessions on the MCPRoutes.
+type MCPSessionConnector interface {
+	// Connect opens an MCP session on the given route with the given headers of the original request.
+	Connect(ctx context.Context, route filterapi.MCPRouteName, headers http.Header) (*mcp.ClientSession, error)
+}
+
+// MCPToolClient is used by the gateway-side tool execution to list and call the tools of the MCPRoutes.
+// This is configured at the startup of the extproc server when the MCP proxy is enabled.
+var MCPToolClient MCPSessionConnector
+
+// destinationAddressContextKey is the context key for the address of the listener that received the request.
+const destinationAddressContextKey contextKey = "destination_address"
+
+// maxStreamedToolCalls is the maximum number of tool calls reconstructed from the chunks of a streamed response.
+// The tool call deltas with a greater index are dropped so that a backend cannot make the message grow without bound.
+const maxStreamedToolCalls = 128
+
+// toolExecutionTimeoutMargin is added to the timeout of the tool execution to get the timeout of the response body
+// message, so that the last response can still be returned when the tool execution times out.
+const toolExecutionTimeoutMargin = 10 * time.Second
+
+// toolExecution executes the MCP tool calls of a chat completion on the gateway side.
+//
+// The tools of the MCPRoute are added to the tools of the request. When the response of the model only calls these
+// tools, the calls are executed through the MCP proxy, and the conversation with the results is sent back to the
+// model through the same listener and route as the original request, until the model returns a final answer. The
+// requests sent back to the model carry the trace context of the original request so that they appear as its children.
+type toolExecution struct {
+	config *filterapi.ToolExecution
+	logger *slog.Logger
+	// tools is the set of the names of the tools of the MCPRoute added to the request.
+	tools map[string]struct{}
+	// destinationAddress is the address of the listener that received the original request.
+	destinationAddress string
+	// url is the URL of the original request.
+	url string
+	// headers are the headers of the original request that are sent with the requests to the model and the MCP proxy.
+	headers http.Header
+	// traceHeaders are the headers propagating the trace context of the original request.
+	traceHeaders map[string]string
+}
+
+// maybeStartToolExecution starts the tool execution of the request if configured for the given route. The tools of the
+// MCPRoute are added to the request body, so this must be called before the request body is translated.
+func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) maybeStartToolExecution(ctx context.Context, routeName string) {
+	if MCPToolClient == nil || r.destinationAddress == "" {
+		return
+	}
+	config := r.config.ToolExecutions[routeName]
+	if config == nil {
+		return
+	}
+	if _, ok := r.requestHeaders[internalapi.ToolExecutionHeader]; ok {
+		// This request is sent back to the model by the tool execution of another request.
+		return
+	}
+	body, ok := any(r.originalRequestBody).(*openai.ChatCompletionRequest)
+	if !ok {
+		return
+	}
+
+	t := newToolExecution(config, r.logger, r.requestHeaders, r.destinationAddress, r.traceHeaders)
+	raw, err := t.addTools(ctx, body, r.originalRequestBodyRaw)
+	if err != nil {
+		r.logger.Error("failed to add the MCP tools to the request, continuing without the tool execution",
+			slog.String("mcp_route", config.MCPRoute), slog.String("error", err.Error()))
+		return
+	}
+	r.originalRequestBodyRaw = raw
+	r.forceBodyMutation = true
+	r.toolExecution = t
+}
+
+// runToolExecution executes the tool calls of the given successful response to the original request, and replaces
+// its body with the final answer of the model.
+func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) runToolExecution(ctx context.Context, body *extprocv3.HttpBody, resp *extprocv3.ProcessingResponse) *extprocv3.ProcessingResponse {
+	u := r.upstreamFilter
+	common := resp.GetResponseBody().GetResponse()
+	if u.responseHeaders[":status"] != "200" || common == nil {
+		return resp
+	}
+	responseBody := common.GetBodyMutation().GetBody()
+	if responseBody == nil {
+		decodingResult, err := decodeContentIfNeeded(body.Body, u.responseEncoding)
+		if err != nil {
+			r.logger.Error("failed to decode the response for the tool execution", slog.String("error", err.Error()))
+			return resp
+		}
+		if responseBody, err = io.ReadAll(decodingResult.reader); err != nil {
+			r.logger.Error("failed to decode the response for the tool execution", slog.String("error", err.Error()))
+			return resp
+		}
+	}
+
+	final := r.toolExecution.run(ctx, r.originalRequestBodyRaw, r.stream, responseBody)
+	if final == nil {
+		return resp
+	}
+	if common.HeaderMutation == nil {
+		common.HeaderMutation = &extprocv3.HeaderMutation{}
+	}
+	common.HeaderMutation.SetHeaders = slices.DeleteFunc(common.HeaderMutation.SetHeaders, func(h *corev3.HeaderValueOption) bool {
+		return strings.EqualFold(h.GetHeader().GetKey(), "content-length")
+	})
+	setHeader(common.HeaderMutation, "content-length", strconv.Itoa(len(final)))
+	if u.responseEncoding != "" && !slices.Contains(common.HeaderMutation.RemoveHeaders, "content-encoding") {
+		common.HeaderMutation.RemoveHeaders = append(common.HeaderMutation.RemoveHeaders, "content-encoding")
+	}
+	common.BodyMutation = &extprocv3.BodyMutation{Mutation: &extprocv3.BodyMutation_Body{Body: final}}
+	return resp
+}
+
+// messageTimeout implements [messageTimeoutExtender].
+func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) messageTimeout(req *extprocv3.ProcessingRequest) time.Duration {
+	if r.toolExecution == nil || !req.GetResponseBody().GetEndOfStream() {
+		return 0
+	}
+	return min(r.toolExecution.config.Timeout+toolExecutionTimeoutMargin, internalapi.RouterLevelExtProcMaxMessageTimeout)
+}
+
+func newToolExecution(config *filterapi.ToolExecution, logger *slog.Logger, requestHeaders map[string]string,
+	destinationAddress string, traceHeaders []*corev3.HeaderValueOption,
+) *toolExecution {
+	t := &toolExecution{
+		config:             config,
+		logger:             logger.With("mcp_route", config.MCPRoute),
+		destinationAddress: destinationAddress,
+		url: cmp.Or(requestHeaders["x-forwarded-proto"], "http") + "://" + requestHeaders[":authority"] +
+			cmp.Or(requestHeaders[originalPathHeader], requestHeaders[":path"]),
+		headers:      make(http.Header, len(requestHeaders)),
+		traceHeaders: make(map[string]string, len(traceHeaders)),
+	}
+	for _, h := range traceHeaders {
+		t.traceHeaders[h.GetHeader().GetKey()] = string(h.GetHeader().GetRawValue())
+	}
+	for k, v := range requestHeaders {
+		if !isToolExecutionForwardedHeader(k) {
+			continue
+		}
+		if _, ok := t.traceHeaders[k]; ok {
+			continue
+		}
+		t.headers.Set(k, v)
+	}
+	return t
+}
+
+// isToolExecutionForwardedHeader returns true if the given header of the original request is sent with the requests
+// of the tool execution, so that they are authenticated, routed and rate limited like the original request.
+func isToolExecutionForwardedHeader(key string) bool {
+	switch {
+	case strings.HasPrefix(key, ":"),
+		strings.HasPrefix(key, internalapi.EnvoyAIGatewayHeaderPrefix),
+		strings.HasPrefix(key, "x-envoy-"):
+		return false
+	}
+	switch key {
+	case "content-length", "accept-encoding", "connection", "transfer-encoding", "te", "x-request-id":
+		return false
+	}
+	return true
+}
+
+// addTools lists the tools of the MCPRoute and adds them to the tools of the request. The tools whose name is already
+// used by a tool of the client are not added. It returns the updated raw request body.
+func (t *toolExecution) addTools(ctx context.Context, body *openai.ChatCompletionRequest, raw []byte) ([]byte, error) {
+	session, err := MCPToolClient.Connect(ctx, t.config.MCPRoute, t.headers)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = session.Close() }()
+
+	clientTools := make(map[string]struct{}, len(body.Tools))
+	for _, tool := range body.Tools {
+		if tool.Function != nil {
+			clientTools[tool.Function.Name] = struct{}{}
+		}
+	}
+	t.tools = make(map[string]struct{})
+	for tool, err := range session.Tools(ctx, nil) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the tools: %w", err)
+		}
+		if _, ok := clientTools[tool.Name]; ok {
+			t.logger.Warn("MCP tool is shadowed by a tool of the client", slog.String("tool", tool.Name))
+			continue
+		}
+		fn := openai.Tool{
+			Type:     openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{Name: tool.Name, Description: tool.Description, Parameters: tool.InputSchema},
+		}
+		encoded, err := json.Marshal(fn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal tool %s: %w", tool.Name, err)
+		}
+		if raw, err = sjson.SetRawBytes(raw, "tools.-1", encoded); err != nil {
+			return nil, fmt.Errorf("failed to add tool %s: %w", tool.Name, err)
+		}
+		body.Tools = append(body.Tools, fn)
+		t.tools[tool.Name] = struct{}{}
+	}
+	return raw, nil
+}
+
+// run executes the MCP tool calls of the given response to the given request body and calls back the model with the
+// results until it returns a final answer, the maximum number of iterations is reached or the timeout expires.
+// It returns the last response of the model, or nil if the given response is to be returned as is.
+func (t *toolExecution) run(ctx context.Context, request []byte, stream bool, response []byte) []byte {
+	ctx, cancel := context.WithTimeout(ctx, t.config.Timeout)
+	defer cancel()
+
+	var (
+		session *mcp.ClientSession
+		client  *http.Client
+		final   []byte
+	)
+	defer func() {
+		if session != nil {
+			_ = session.Close()
+		}
+		if client != nil {
+			client.CloseIdleConnections()
+		}
+	}()
+	for range t.config.MaxIterations {
+		message, err := assistantMessage(response, stream)
+		if err != nil {
+			t.logger.Error("failed to parse the response of the model", slog.String("error", err.Error()))
+			break
+		}
+		if len(message.ToolCalls) == 0 || slices.ContainsFunc(message.ToolCalls, func(c openai.ChatCompletionMessageToolCallParam) bool {
+			_, ok := t.tools[c.Function.Name]
+			return !ok
+		}) {
+			// The final answer, or the tool calls must be executed by the client.
+			break
+		}
+
+		if session == nil {
+			if session, err = MCPToolClient.Connect(ctx, t.config.MCPRoute, t.headers); err != nil {
+				t.logger.Error("failed to connect to the MCP route", slog.String("error", err.Error()))
+				break
+			}
+		}
+		if request, err = t.appendToolResults(ctx, session, request, message); err != nil {
+			t.logger.Error("failed to add the tool results to the request", slog.String("error", err.Error()))
+			break
+		}
+		if client == nil {
+			client = t.newModelClient()
+		}
+		if response, err = t.callModel(ctx, client, request); err != nil {
+			t.logger.Error("failed to call back the model with the tool results", slog.String("error", err.Error()))
+			break
+		}
+		final = response
+	}
+	return final
+}
+
+// appendToolResults executes the tool calls of the given assistant message, and appends the message and the results
+// of the calls to the messages of the given request body. The failed calls are reported to the model as their results.
+func (t *toolExecution) appendToolResults(ctx context.Context, session *mcp.ClientSession, request []byte,
+	message *openai.ChatCompletionResponseChoiceMessage,
+) ([]byte, error) {
+	for i := range message.ToolCalls {
+		if call := &message.ToolCalls[i]; call.ID == nil || *call.ID == "" {
+			// The results are matched to the calls by their ID, which some backends leave out.
+			id := "call_" + uuid.NewString()
+			call.ID = &id
+		}
+	}
+	assistant := &openai.ChatCompletionAssistantMessageParam{
+		Role:      openai.ChatMessageRoleAssistant,
+		ToolCalls: message.ToolCalls,
+	}
+	if message.Content != nil {
+		assistant.Content = openai.StringOrAssistantRoleContentUnion{Value: *message.Content}
+	}
+	encoded, err := json.Marshal(assistant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the assistant message: %w", err)
+	}
+	if request, err = sjson.SetRawBytes(request, "messages.-1", encoded); err != nil {
+		return nil, fmt.Errorf("failed to add the assistant message: %w", err)
+	}
+
+	for _, call := range message.ToolCalls {
+		result := t.callTool(ctx, session, call)
+		encoded, err = json.Marshal(&openai.ChatCompletionToolMessageParam{
+			Role:       openai.ChatMessageRoleTool,
+			ToolCallID: *call.ID,
+			Content:    openai.ContentUnion{Value: result},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal the tool message: %w", err)
+		}
+		if request, err = sjson.SetRawBytes(request, "messages.-1", encoded); err != nil {
+			return nil, fmt.Errorf("failed to add the tool message: %w", err)
+		}
+	}
+	return request, nil
+}
+
+// callTool executes the given tool call and returns its result as text.
+func (t *toolExecution) callTool(ctx context.Context, session *mcp.ClientSession, call openai.ChatCompletionMessageToolCallParam) string {
+	params := &mcp.CallToolParams{Name: call.Function.Name}
+	if args := strings.TrimSpace(call.Function.Arguments); args != "" {
+		params.Arguments = json.RawMessage(args)
+	}
+	if len(t.traceHeaders) > 0 {
+		// The MCP proxy reads the trace context from the metadata of the requests.
+		params.Meta = make(mcp.Meta, len(t.traceHeaders))
+		for k, v := range t.traceHeaders {
+			params.Meta[k] = v
+		}
+	}
+	res, err := session.CallTool(ctx, params)
+	if err != nil {
+		t.logger.Warn("failed to call tool", slog.String("tool", call.Function.Name), slog.String("error", err.Error()))
+		return "Error: " + err.Error()
+	}
+
+	var text strings.Builder
+	for _, c := range res.Content {
+		if text.Len() > 0 {
+			text.WriteByte('\n')
+		}
+		if c, ok := c.(*mcp.TextContent); ok {
+			text.WriteString(c.Text)
+			continue
+		}
+		encoded, _ := json.Marshal(c)
+		text.Write(encoded)
+	}
+	if text.Len() == 0 && res.StructuredContent != nil {
+		encoded, _ := json.Marshal(res.StructuredContent)
+		text.Write(encoded)
+	}
+	return text.String()
+}
+
+// newModelClient returns an HTTP client sending the requests to the listener that received the original request.
+func (t *toolExecution) newModelClient() *http.Client {
+	var dialer net.Dialer
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, t.destinationAddress)
+		},
+		ForceAttemptHTTP2: true,
+	}
+	if u, err := url.Parse(t.url); err == nil && u.Scheme == "https" {
+		// The connections are made to the address of the listener, so the certificate is verified for the host of the
+		// original request with the certificate authorities of the system, which can be set with SSL_CERT_FILE.
+		transport.TLSClientConfig = &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}
+	}
+	return &http.Client{Transport: transport}
+}
+
+// callModel sends the given request body to the model and returns the body of the successful response.
+func (t *toolExecution) callModel(ctx context.Context, client *http.Client, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the request: %w", err)
+	}
+	req.Header = t.headers.Clone()
+	for k, v := range t.traceHeaders {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set(internalapi.ToolExecutionHeader, "true")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, respBody)
+	}
+	return respBody, nil
+}
+
+// assistantMessage returns the message of the first choice of the given chat completion response. When stream is
+// true, the response is the SSE stream of the chunks, from which the message is reconstructed.
+func assistantMessage(body []byte, stream bool) (*openai.ChatCompletionResponseChoiceMessage, error) {
+	if !stream {
+		var resp openai.ChatCompletionResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Choices) == 0 {
+			return nil, errors.New("no choices in the response")
+		}
+		return &resp.Choices[0].Message, nil
+	}
+
+	var (
+		content   strings.Builder
+		hasText   bool
+		toolCalls []openai.ChatCompletionMessageToolCallParam
+	)
+	for line := range bytes.Lines(body) {
+		data, ok := bytes.CutPrefix(line, []byte("data:"))
+		if data = bytes.TrimSpace(data); !ok || bytes.Equal(data, []byte("[DONE]")) {
+			continue
+		}
+		var chunk openai.ChatCompletionResponseChunk
+		if err := json.Unmarshal(data, &chunk); err != nil {
+			return nil, err
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Index != 0 || choice.Delta == nil {
+				continue
+			}
+			if choice.Delta.Content != nil {
+				content.WriteString(*choice.Delta.Content)
+				hasText = true
+			}
+			for _, delta := range choice.Delta.ToolCalls {
+				if delta.Index < 0 || delta.Index >= maxStreamedToolCalls {
+					continue
+				}
+				for int64(len(toolCalls)) <= delta.Index {
+					toolCalls = append(toolCalls, openai.ChatCompletionMessageToolCallParam{
+						Type: openai.ChatCompletionMessageToolCallTypeFunction,
+					})
+				}
+				call := &toolCalls[delta.Index]
+				if delta.ID != nil {
+					call.ID = delta.ID
+				}
+				call.Function.Name += delta.Function.Name
+				call.Function.Arguments += delta.Function.Arguments
+			}
+		}
+	}
+	message := &openai.ChatCompletionResponseChoiceMessage{Role: openai.ChatMessageRoleAssistant, ToolCalls: toolCalls}
+	if hasText {
+		text := content.String()
+		message.Content = &text
+	}
+	return message, nil
+}
@@ -0,0 +1,321 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package extproc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+const testTraceparent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+// fakeMCPSessionConnector connects to an in-memory MCP server.
+type fakeMCPSessionConnector struct {
+	server *mcp.Server
+	mu     sync.Mutex
+	routes []filterapi.MCPRouteName
+	auth   []string
+}
+
+// Connect implements [MCPSessionConnector.Connect].
+func (f *fakeMCPSessionConnector) Connect(ctx context.Context, route filterapi.MCPRouteName, headers http.Header) (*mcp.ClientSession, error) {
+	f.mu.Lock()
+	f.routes = append(f.routes, route)
+	f.auth = append(f.auth, headers.Get("Authorization"))
+	f.mu.Unlock()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := f.server.Connect(ctx, serverTransport, nil); err != nil {
+		return nil, err
+	}
+	return mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(ctx, clientTransport, nil)
+}
+
+func newFakeMCPSessionConnector(t *testing.T) (*fakeMCPSessionConnector, *[]mcp.Meta) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	var metas []mcp.Meta
+	type weatherArgs struct {
+		City string `json:"city"`
+	}
+	mcp.AddTool(server, &mcp.Tool{Name: "get_weather", Description: "Get the weather of a city."},
+		func(_ context.Context, req *mcp.CallToolRequest, args weatherArgs) (*mcp.CallToolResult, any, error) {
+			metas = append(metas, req.Params.Meta)
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "sunny in " + args.City}}}, nil, nil
+		})
+	mcp.AddTool(server, &mcp.Tool{Name: "lookup"},
+		func(context.Context, *mcp.CallToolRequest, map[string]any) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "found"}}}, nil, nil
+		})
+	c := &fakeMCPSessionConnector{server: server}
+	MCPToolClient = c
+	t.Cleanup(func() { MCPToolClient = nil })
+	return c, &metas
+}
+
+// fakeModel is a model server that calls get_weather until it receives the results of the tool calls, unless
+// alwaysCallTools is set.
+type fakeModel struct {
+	*httptest.Server
+	mu              sync.Mutex
+	requests        []*openai.ChatCompletionRequest
+	headers         []http.Header
+	alwaysCallTools bool
+	status          int
+}
+
+func newFakeModel(t *testing.T) *fakeModel {
+	m := &fakeModel{status: http.StatusOK}
+	m.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req openai.ChatCompletionRequest
+		require.NoError(t, json.Unmarshal(body, &req))
+		m.mu.Lock()
+		m.requests = append(m.requests, &req)
+		m.headers = append(m.headers, r.Header.Clone())
+		m.headers[len(m.headers)-1].Set("Host", r.Host)
+		alwaysCallTools, status := m.alwaysCallTools, m.status
+		m.mu.Unlock()
+
+		w.WriteHeader(status)
+		last := req.Messages[len(req.Messages)-1]
+		switch {
+		case last.OfTool == nil || alwaysCallTools:
+			_, _ = w.Write([]byte(toolCallResponse(req.Stream)))
+		case req.Stream:
+			_, _ = w.Write([]byte("data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"It is \"}}]}\n\n" +
+				"data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"" + last.OfTool.Content.Value.(string) + "\"}}]}\n\n" +
+				"data: [DONE]\n\n"))
+		default:
+			_, _ = w.Write([]byte(`{"choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"It is ` +
+				last.OfTool.Content.Value.(string) + `"}}]}`))
+		}
+	}))
+	t.Cleanup(m.Close)
+	return m
+}
+
+func toolCallResponse(stream bool) string {
+	if stream {
+		return "data: {\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"tool_calls\":[{\"index\":0,\"id\":\"call_1\",\"type\":\"function\",\"function\":{\"name\":\"get_weather\",\"arguments\":\"\"}}]}}]}\n\n" +
+			"data: {\"choices\":[{\"index\":0,\"delta\":{\"tool_calls\":[{\"index\":0,\"function\":{\"arguments\":\"{\\\"city\\\":\"}}]}}]}\n\n" +
+			"data: {\"choices\":[{\"index\":0,\"delta\":{\"tool_calls\":[{\"index\":0,\"function\":{\"arguments\":\"\\\"Tokyo\\\"}\"}}]}}]}\n\n" +
+			"data: {\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"tool_calls\"}]}\n\n" +
+			"data: [DONE]\n\n"
+	}
+	return `{"choices":[{"index":0,"finish_reason":"tool_calls","message":{"role":"assistant","tool_calls":[` +
+		`{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Tokyo\"}"}}]}}]}`
+}
+
+func newTestToolExecution(t *testing.T, destinationAddress string, config *filterapi.ToolExecution) *toolExecution {
+	te := newToolExecution(config, slog.New(slog.DiscardHandler), map[string]string{
+		":authority":                          "api.example.com",
+		":path":                               "/v1/chat/completions",
+		originalPathHeader:                    "/v1/chat/completions",
+		internalapi.ModelNameHeaderKeyDefault: "gpt-4o",
+		"authorization":                       "Bearer key",
+		"content-length":                      "42",
+		"traceparent":                         "00-from-client",
+	}, destinationAddress, []*corev3.HeaderValueOption{
+		{Header: &corev3.HeaderValue{Key: "traceparent", RawValue: []byte(testTraceparent)}},
+	})
+	require.Equal(t, "http://api.example.com/v1/chat/completions", te.url)
+	return te
+}
+
+func TestToolExecution_addTools(t *testing.T) {
+	connector, _ := newFakeMCPSessionConnector(t)
+	te := newTestToolExecution(t, "127.0.0.1:0", &filterapi.ToolExecution{MCPRoute: "ns/tools"})
+
+	raw := []byte(`{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}],"tools":[{"type":"function","function":{"name":"lookup"}}]}`)
+	var body openai.ChatCompletionRequest
+	require.NoError(t, json.Unmarshal(raw, &body))
+	raw, err := te.addTools(t.Context(), &body, raw)
+	require.NoError(t, err)
+
+	// The client tool shadows the MCP tool with the same name.
+	require.Equal(t, map[string]struct{}{"get_weather": {}}, te.tools)
+	require.Len(t, body.Tools, 2)
+	require.Equal(t, "get_weather", body.Tools[1].Function.Name)
+	var updated openai.ChatCompletionRequest
+	require.NoError(t, json.Unmarshal(raw, &updated))
+	require.Len(t, updated.Tools, 2)
+	require.Equal(t, "lookup", updated.Tools[0].Function.Name)
+	require.Equal(t, "get_weather", updated.Tools[1].Function.Name)
+	require.Equal(t, "Get the weather of a city.", updated.Tools[1].Function.Description)
+	require.NotNil(t, updated.Tools[1].Function.Parameters)
+
+	require.Equal(t, []filterapi.MCPRouteName{"ns/tools"}, connector.routes)
+	require.Equal(t, []string{"Bearer key"}, connector.auth)
+}
+
+func TestToolExecution_run(t *testing.T) {
+	const request = `{"model":"gpt-4o","messages":[{"role":"user","content":"weather in Tokyo?"}]}`
+	config := &filterapi.ToolExecution{MCPRoute: "ns/tools", MaxIterations: 3, Timeout: time.Minute}
+
+	for _, stream := range []bool{false, true} {
+		t.Run("stream="+map[bool]string{false: "false", true: "true"}[stream], func(t *testing.T) {
+			_, metas := newFakeMCPSessionConnector(t)
+			model := newFakeModel(t)
+			te := newTestToolExecution(t, model.Listener.Addr().String(), config)
+			te.tools = map[string]struct{}{"get_weather": {}}
+
+			req := request
+			if stream {
+				req = `{"model":"gpt-4o","stream":true,"messages":[{"role":"user","content":"weather in Tokyo?"}]}`
+			}
+			final := te.run(t.Context(), []byte(req), stream, []byte(toolCallResponse(stream)))
+			message, err := assistantMessage(final, stream)
+			require.NoError(t, err)
+			require.Equal(t, "It is sunny in Tokyo", *message.Content)
+			if stream {
+				require.Contains(t, string(final), "data: [DONE]")
+			}
+
+			require.Len(t, model.requests, 1)
+			messages := model.requests[0].Messages
+			require.Len(t, messages, 3)
+			require.Equal(t, "call_1", *messages[1].OfAssistant.ToolCalls[0].ID)
+			require.Equal(t, "get_weather", messages[1].OfAssistant.ToolCalls[0].Function.Name)
+			require.JSONEq(t, `{"city":"Tokyo"}`, messages[1].OfAssistant.ToolCalls[0].Function.Arguments)
+			require.Equal(t, "call_1", messages[2].OfTool.ToolCallID)
+			require.Equal(t, "sunny in Tokyo", messages[2].OfTool.Content.Value)
+
+			h := model.headers[0]
+			require.Equal(t, "api.example.com", h.Get("Host"))
+			require.Equal(t, "Bearer key", h.Get("Authorization"))
+			require.Equal(t, "true", h.Get(internalapi.ToolExecutionHeader))
+			require.Equal(t, testTraceparent, h.Get("traceparent"))
+			require.Empty(t, h.Get(internalapi.ModelNameHeaderKeyDefault))
+			require.Empty(t, h.Get(originalPathHeader))
+
+			// The tool calls are children of the span of the original request.
+			require.Len(t, *metas, 1)
+			require.Equal(t, testTraceparent, (*metas)[0]["traceparent"])
+		})
+	}
+
+	t.Run("client tool calls", func(t *testing.T) {
+		newFakeMCPSessionConnector(t)
+		model := newFakeModel(t)
+		te := newTestToolExecution(t, model.Listener.Addr().String(), config)
+		te.tools = map[string]struct{}{"lookup": {}}
+		require.Nil(t, te.run(t.Context(), []byte(request), false, []byte(toolCallResponse(false))))
+		require.Empty(t, model.requests)
+	})
+
+	t.Run("final answer", func(t *testing.T) {
+		newFakeMCPSessionConnector(t)
+		model := newFakeModel(t)
+		te := newTestToolExecution(t, model.Listener.Addr().String(), config)
+		te.tools = map[string]struct{}{"get_weather": {}}
+		response := `{"choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"Hello"}}]}`
+		require.Nil(t, te.run(t.Context(), []byte(request), false, []byte(response)))
+		require.Empty(t, model.requests)
+	})
+
+	t.Run("max iterations", func(t *testing.T) {
+		newFakeMCPSessionConnector(t)
+		model := newFakeModel(t)
+		model.alwaysCallTools = true
+		te := newTestToolExecution(t, model.Listener.Addr().String(), config)
+		te.tools = map[string]struct{}{"get_weather": {}}
+		final := te.run(t.Context(), []byte(request), false, []byte(toolCallResponse(false)))
+		require.Equal(t, toolCallResponse(false), string(final))
+		require.Len(t, model.requests, 3)
+		require.Len(t, model.requests[2].Messages, 7)
+	})
+
+	t.Run("tool call without id", func(t *testing.T) {
+		newFakeMCPSessionConnector(t)
+		model := newFakeModel(t)
+		te := newTestToolExecution(t, model.Listener.Addr().String(), config)
+		te.tools = map[string]struct{}{"get_weather": {}}
+		response := `{"choices":[{"index":0,"finish_reason":"tool_calls","message":{"role":"assistant","tool_calls":[` +
+			`{"type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Tokyo\"}"}}]}}]}`
+		final := te.run(t.Context(), []byte(request), false, []byte(response))
+		message, err := assistantMessage(final, false)
+		require.NoError(t, err)
+		require.Equal(t, "It is sunny in Tokyo", *message.Content)
+
+		require.Len(t, model.requests, 1)
+		messages := model.requests[0].Messages
+		require.Len(t, messages, 3)
+		id := messages[1].OfAssistant.ToolCalls[0].ID
+		require.NotNil(t, id)
+		require.NotEmpty(t, *id)
+		require.Equal(t, *id, messages[2].OfTool.ToolCallID)
+	})
+
+	t.Run("model error", func(t *testing.T) {
+		newFakeMCPSessionConnector(t)
+		model := newFakeModel(t)
+		model.status = http.StatusTooManyRequests
+		te := newTestToolExecution(t, model.Listener.Addr().String(), config)
+		te.tools = map[string]struct{}{"get_weather": {}}
+		require.Nil(t, te.run(t.Context(), []byte(request), false, []byte(toolCallResponse(false))))
+		require.Len(t, model.requests, 1)
+	})
+}
+
+func TestAssistantMessage(t *testing.T) {
+	message, err := assistantMessage([]byte(toolCallResponse(true)), true)
+	require.NoError(t, err)
+	require.Nil(t, message.Content)
+	require.Equal(t, []openai.ChatCompletionMessageToolCallParam{{
+		ID:       ptr.To("call_1"),
+		Type:     openai.ChatCompletionMessageToolCallTypeFunction,
+		Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: "get_weather", Arguments: `{"city":"Tokyo"}`},
+	}}, message.ToolCalls)
+
+	message, err = assistantMessage([]byte(toolCallResponse(false)), false)
+	require.NoError(t, err)
+	require.Len(t, message.ToolCalls, 1)
+
+	// The tool call deltas with an out of range index are dropped.
+	message, err = assistantMessage([]byte("data: {\"choices\":[{\"index\":0,\"delta\":{\"tool_calls\":["+
+		"{\"index\":9223372036854775807,\"function\":{\"name\":\"a\"}},"+
+		"{\"index\":-1,\"function\":{\"name\":\"b\"}},"+
+		"{\"index\":0,\"id\":\"call_1\",\"function\":{\"name\":\"get_weather\"}}]}}]}\n\n"), true)
+	require.NoError(t, err)
+	require.Len(t, message.ToolCalls, 1)
+	require.Equal(t, "get_weather", message.ToolCalls[0].Function.Name)
+
+	_, err = assistantMessage([]byte(`{"choices":[]}`), false)
+	require.ErrorContains(t, err, "no choices")
+	_, err = assistantMessage([]byte("data: {invalid\n\n"), true)
+	require.Error(t, err)
+}
+
+func TestRouterProcessor_messageTimeout(t *testing.T) {
+	r := &chatCompletionProcessorRouterFilter{}
+	endOfStream := &extprocv3.ProcessingRequest{Request: &extprocv3.ProcessingRequest_ResponseBody{
+		ResponseBody: &extprocv3.HttpBody{EndOfStream: true},
+	}}
+	require.Zero(t, r.messageTimeout(endOfStream))
+
+	r.toolExecution = &toolExecution{config: &filterapi.ToolExecution{Timeout: time.Minute}}
+	require.Equal(t, time.Minute+toolExecutionTimeoutMargin, r.messageTimeout(endOfStream))
+	require.Zero(t, r.messageTimeout(&extprocv3.ProcessingRequest{Request: &extprocv3.ProcessingRequest_RequestHeaders{}}))
+
+	r.toolExecution.config.Timeout = time.Hour
+	require.Equal(t, internalapi.RouterLevelExtProcMaxMessageTimeout, r.messageTimeout(endOfStream))
+}
@@ -0,0 +1,213 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package extproc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+// virtualKeySpends are the costs spent by the virtual keys in their current budget window by name. They are shared by
+// all the processors, so that the spent costs survive the reloads of the configuration and the rotations of the keys.
+var virtualKeySpends sync.Map // map[string]*virtualKeySpend
+
+// virtualKeySpend is the cost spent by a virtual key in its current budget window.
+type virtualKeySpend struct {
+	mu          sync.Mutex
+	windowStart time.Time
+	spent       uint64
+}
+
+func virtualKeySpendOf(name string) *virtualKeySpend {
+	s, _ := virtualKeySpends.LoadOrStore(name, &virtualKeySpend{})
+	return s.(*virtualKeySpend)
+}
+
+// pruneVirtualKeySpends removes the spent costs of the virtual keys that are not in the given configuration anymore.
+func pruneVirtualKeySpends(keys []filterapi.VirtualKey) {
+	names := make(map[string]struct{}, len(keys))
+	for i := range keys {
+		names[keys[i].Name] = struct{}{}
+	}
+	virtualKeySpends.Range(func(name, _ any) bool {
+		if _, ok := names[name.(string)]; !ok {
+			virtualKeySpends.Delete(name)
+		}
+		return true
+	})
+}
+
+// exhausted returns true if the budget is spent in the current window.
+func (s *virtualKeySpend) exhausted(budget *filterapi.VirtualKeyBudget, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maybeResetLocked(budget.Window, now)
+	return s.spent >= budget.Limit
+}
+
+// add adds the given cost to the current window.
+func (s *virtualKeySpend) add(budget *filterapi.VirtualKeyBudget, cost uint64, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maybeResetLocked(budget.Window, now)
+	s.spent += cost
+}
+
+func (s *virtualKeySpend) maybeResetLocked(window time.Duration, now time.Time) {
+	if now.Sub(s.windowStart) >= window {
+		s.windowStart = now
+		s.spent = 0
+	}
+}
+
+// hashVirtualKey returns the hash of the given key, as in [filterapi.VirtualKey.KeyHash].
+func hashVirtualKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// virtualKeyCredential returns the header carrying the credential of the request and the credential itself, if any.
+// The credentials are read from the "Authorization: Bearer" header as sent by the OpenAI clients, and from the
+// "x-api-key" header as sent by the Anthropic clients.
+func virtualKeyCredential(headers map[string]string) (header, key string) {
+	if v := headers["authorization"]; len(v) > len("Bearer ") && strings.EqualFold(v[:len("Bearer ")], "Bearer ") {
+		return "authorization", strings.TrimSpace(v[len("Bearer "):])
+	}
+	if v := headers["x-api-key"]; v != "" {
+		return "x-api-key", strings.TrimSpace(v)
+	}
+	return "", ""
+}
+
+// authenticateVirtualKey validates the virtual key of the request, if any, for the given model. It returns the headers
+// to set and remove on the request, or the response rejecting the request.
+//
+// The requests without a credential, or whose credential isn't a virtual key, are left to the upstream filter which
+// knows their route, and rejects them if the route can only be used with a virtual key.
+func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) authenticateVirtualKey(model string) (
+	set []*corev3.HeaderValueOption, remove []string, rejection *extprocv3.ProcessingResponse,
+) {
+	// The clients must not be able to impersonate the holders of a virtual key.
+	remove = virtualKeyHeaders(r.requestHeaders)
+	for _, k := range remove {
+		delete(r.requestHeaders, k)
+	}
+	if len(r.config.VirtualKeys) == 0 {
+		return
+	}
+	header, key := virtualKeyCredential(r.requestHeaders)
+	if key == "" {
+		return
+	}
+	vk, ok := r.config.VirtualKeys[hashVirtualKey(key)]
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	if !vk.ExpiresAt.IsZero() && now.After(vk.ExpiresAt) {
+		r.logger.Info("rejecting request with expired virtual key", slog.String("virtual_key", vk.Name))
+		return nil, nil, createUserFacingErrorResponse(http.StatusUnauthorized, "Unauthorized", "the virtual key has expired")
+	}
+	if vk.AllowedModels != nil {
+		if _, ok = vk.AllowedModels[model]; !ok {
+			r.logger.Info("rejecting request for a model not allowed by the virtual key",
+				slog.String("virtual_key", vk.Name), slog.String("model", model))
+			return nil, nil, createUserFacingErrorResponse(http.StatusForbidden, "Forbidden",
+				fmt.Sprintf("the virtual key is not allowed to use the model %s", model))
+		}
+	}
+	if vk.Budget != nil && virtualKeySpendOf(vk.Name).exhausted(vk.Budget, now) {
+		r.logger.Info("rejecting request with exhausted virtual key budget", slog.String("virtual_key", vk.Name))
+		return nil, nil, createUserFacingErrorResponse(http.StatusTooManyRequests, "TooManyRequests",
+			"the budget of the virtual key is exhausted")
+	}
+	r.virtualKey = vk
+
+	// The virtual key is only meaningful to the gateway, so it is not forwarded to the backends.
+	delete(r.requestHeaders, header)
+	remove = append(remove, header)
+	set = append(set, &corev3.HeaderValueOption{
+		Header: &corev3.HeaderValue{Key: internalapi.VirtualKeyHeader, RawValue: []byte(vk.Name)},
+	})
+	r.requestHeaders[internalapi.VirtualKeyHeader] = vk.Name
+	labels := make([]string, 0, len(vk.Labels))
+	for name := range vk.Labels {
+		labels = append(labels, name)
+	}
+	slices.Sort(labels)
+	for _, name := range labels {
+		k := internalapi.VirtualKeyLabelHeaderPrefix + name
+		set = append(set, &corev3.HeaderValueOption{Header: &corev3.HeaderValue{Key: k, RawValue: []byte(vk.Labels[name])}})
+		r.requestHeaders[k] = vk.Labels[name]
+	}
+	return
+}
+
+// checkVirtualKeyRoute returns the response rejecting the request if its route can only be used with a virtual key
+// and the request has none, or if the virtual key of the request is not allowed to use the route.
+func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) checkVirtualKeyRoute() *extprocv3.ProcessingResponse {
+	vk := u.parent.virtualKey
+	if vk == nil {
+		if u.parent.config == nil {
+			return nil
+		}
+		if _, ok := u.parent.config.VirtualKeyRoutes[u.routeName]; ok {
+			u.logger.Info("rejecting request without a virtual key", slog.String("route", u.routeName))
+			return createUserFacingErrorResponse(http.StatusUnauthorized, "Unauthorized", "a valid virtual key is required")
+		}
+		return nil
+	}
+	if _, ok := vk.AllowedRoutes[u.routeName]; !ok {
+		u.logger.Info("rejecting request for a route not allowed by the virtual key",
+			slog.String("virtual_key", vk.Name), slog.String("route", u.routeName))
+		return createUserFacingErrorResponse(http.StatusForbidden, "Forbidden", "the virtual key is not allowed to use this route")
+	}
+	return nil
+}
+
+// virtualKeyHeaders returns the sorted names of the virtual key headers in the given request headers.
+func virtualKeyHeaders(headers map[string]string) (names []string) {
+	for k := range headers {
+		if strings.HasPrefix(k, internalapi.VirtualKeyHeader) {
+			names = append(names, k)
+		}
+	}
+	slices.Sort(names)
+	return
+}
+
+// recordVirtualKeyCost adds the cost of the request to the budget of its virtual key, if any.
+func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) recordVirtualKeyCost() {
+	vk := u.parent.virtualKey
+	if vk == nil || vk.Budget == nil {
+		return
+	}
+	costType := filterapi.LLMRequestCostTypeTotalToken
+	if vk.BudgetCELProg != nil {
+		costType = filterapi.LLMRequestCostTypeCEL
+	}
+	cost, err := evalCost(costType, vk.BudgetCELProg, &u.costs, u.requestHeaders, u.backendName, u.routeName)
+	if err != nil {
+		u.logger.Error("failed to compute the cost of the request for the virtual key budget",
+			slog.String("virtual_key", vk.Name), slog.String("error", err.Error()))
+		return
+	}
+	virtualKeySpendOf(vk.Name).add(vk.Budget, cost, time.Now())
+}
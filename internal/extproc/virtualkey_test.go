@@ -0,0 +1,280 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package extproc
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/llmcostcel"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+func newTestVirtualKeyConfig(t *testing.T, vks ...filterapi.VirtualKey) *filterapi.RuntimeConfig {
+	rc, err := filterapi.NewRuntimeConfig(t.Context(), &filterapi.Config{VirtualKeys: vks}, nil)
+	require.NoError(t, err)
+	return rc
+}
+
+func Test_routerProcessor_authenticateVirtualKey(t *testing.T) {
+	config := newTestVirtualKeyConfig(t,
+		filterapi.VirtualKey{
+			Name: "ns/team-a", KeyHash: hashVirtualKey("sk-team-a"), Routes: []string{"ns/route"},
+			Labels: map[string]string{"team": "a", "cost-center": "42"},
+		},
+		filterapi.VirtualKey{
+			Name: "ns/team-b", KeyHash: hashVirtualKey("sk-team-b"), Routes: []string{"ns/route"},
+			Models: []string{"allowed-model"},
+		},
+		filterapi.VirtualKey{
+			Name: "ns/expired", KeyHash: hashVirtualKey("sk-expired"), Routes: []string{"ns/route"},
+			ExpiresAt: time.Now().Add(-time.Minute),
+		},
+		filterapi.VirtualKey{
+			Name: "ns/exhausted", KeyHash: hashVirtualKey("sk-exhausted"), Routes: []string{"ns/route"},
+			Budget: &filterapi.VirtualKeyBudget{Limit: 10, Window: time.Hour},
+		},
+	)
+	virtualKeySpendOf("ns/exhausted").add(&filterapi.VirtualKeyBudget{Limit: 10, Window: time.Hour}, 10, time.Now())
+	t.Cleanup(func() { virtualKeySpends.Delete("ns/exhausted") })
+
+	process := func(t *testing.T, config *filterapi.RuntimeConfig, headers map[string]string, model string) (
+		*chatCompletionProcessorRouterFilter, *extprocv3.ProcessingResponse,
+	) {
+		headers[":path"] = "/v1/chat/completions"
+		p := &chatCompletionProcessorRouterFilter{
+			config:         config,
+			requestHeaders: headers,
+			logger:         slog.Default(),
+			tracer:         tracingapi.NoopTracer[openai.ChatCompletionRequest, openai.ChatCompletionResponse, openai.ChatCompletionResponseChunk]{},
+		}
+		resp, err := p.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{Body: bodyFromModel(t, model, false, nil)})
+		require.NoError(t, err)
+		return p, resp
+	}
+
+	t.Run("valid key", func(t *testing.T) {
+		headers := map[string]string{"authorization": "Bearer sk-team-a"}
+		p, resp := process(t, config, headers, "some-model")
+		require.Equal(t, "ns/team-a", p.virtualKey.Name)
+		mutation := resp.GetRequestBody().GetResponse().GetHeaderMutation()
+		require.Equal(t, []string{"authorization"}, mutation.RemoveHeaders)
+		setHeaders := map[string]string{}
+		for _, h := range mutation.SetHeaders {
+			setHeaders[h.Header.Key] = string(h.Header.RawValue)
+		}
+		require.Equal(t, "ns/team-a", setHeaders[internalapi.VirtualKeyHeader])
+		require.Equal(t, "a", setHeaders[internalapi.VirtualKeyLabelHeaderPrefix+"team"])
+		require.Equal(t, "42", setHeaders[internalapi.VirtualKeyLabelHeaderPrefix+"cost-center"])
+		require.NotContains(t, headers, "authorization")
+		require.Equal(t, "ns/team-a", headers[internalapi.VirtualKeyHeader])
+	})
+
+	t.Run("valid key in x-api-key", func(t *testing.T) {
+		p, resp := process(t, config, map[string]string{"x-api-key": "sk-team-b"}, "allowed-model")
+		require.Equal(t, "ns/team-b", p.virtualKey.Name)
+		require.Equal(t, []string{"x-api-key"}, resp.GetRequestBody().GetResponse().GetHeaderMutation().RemoveHeaders)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		headers := map[string]string{"authorization": "Bearer sk-unknown"}
+		p, resp := process(t, config, headers, "some-model")
+		require.Nil(t, p.virtualKey)
+		require.Empty(t, resp.GetRequestBody().GetResponse().GetHeaderMutation().RemoveHeaders)
+		require.Equal(t, "Bearer sk-unknown", headers["authorization"])
+	})
+
+	t.Run("impersonation", func(t *testing.T) {
+		for _, config := range []*filterapi.RuntimeConfig{{}, config} {
+			headers := map[string]string{
+				internalapi.VirtualKeyHeader:                     "ns/team-a",
+				internalapi.VirtualKeyLabelHeaderPrefix + "team": "a",
+			}
+			p, resp := process(t, config, headers, "some-model")
+			require.Nil(t, p.virtualKey)
+			require.Equal(t, []string{internalapi.VirtualKeyHeader, internalapi.VirtualKeyLabelHeaderPrefix + "team"},
+				resp.GetRequestBody().GetResponse().GetHeaderMutation().RemoveHeaders)
+			require.NotContains(t, headers, internalapi.VirtualKeyHeader)
+		}
+	})
+
+	for _, tc := range []struct {
+		name       string
+		key, model string
+		expStatus  typev3.StatusCode
+		expMessage string
+	}{
+		{
+			name: "expired", key: "sk-expired", model: "some-model",
+			expStatus: 401, expMessage: "the virtual key has expired",
+		},
+		{
+			name: "model not allowed", key: "sk-team-b", model: "other-model",
+			expStatus: 403, expMessage: "the virtual key is not allowed to use the model other-model",
+		},
+		{
+			name: "budget exhausted", key: "sk-exhausted", model: "some-model",
+			expStatus: 429, expMessage: "the budget of the virtual key is exhausted",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, resp := process(t, config, map[string]string{"authorization": "Bearer " + tc.key}, tc.model)
+			require.Nil(t, p.virtualKey)
+			immediate, ok := resp.Response.(*extprocv3.ProcessingResponse_ImmediateResponse)
+			require.True(t, ok)
+			require.Equal(t, tc.expStatus, immediate.ImmediateResponse.Status.Code)
+			require.Contains(t, string(immediate.ImmediateResponse.Body), tc.expMessage)
+		})
+	}
+}
+
+func Test_upstreamProcessor_checkVirtualKeyRoute(t *testing.T) {
+	config := newTestVirtualKeyConfig(t,
+		filterapi.VirtualKey{Name: "ns/team-a", KeyHash: "a", Routes: []string{"ns/route-a"}},
+		filterapi.VirtualKey{Name: "ns/team-b", KeyHash: "b", Routes: []string{"ns/route-b"}},
+	)
+	for _, tc := range []struct {
+		name       string
+		virtualKey *filterapi.RuntimeVirtualKey
+		route      string
+		expStatus  typev3.StatusCode
+	}{
+		{name: "no key on an open route", route: "ns/open"},
+		{name: "no key on a protected route", route: "ns/route-a", expStatus: 401},
+		{name: "allowed route", virtualKey: config.VirtualKeys["a"], route: "ns/route-a"},
+		{name: "route of another key", virtualKey: config.VirtualKeys["a"], route: "ns/route-b", expStatus: 403},
+		{name: "open route not allowed by the key", virtualKey: config.VirtualKeys["b"], route: "ns/open", expStatus: 403},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			u := &chatCompletionProcessorUpstreamFilter{
+				parent:    &chatCompletionProcessorRouterFilter{config: config, virtualKey: tc.virtualKey},
+				routeName: tc.route,
+				logger:    slog.Default(),
+			}
+			resp := u.checkVirtualKeyRoute()
+			if tc.expStatus == 0 {
+				require.Nil(t, resp)
+				return
+			}
+			require.Equal(t, tc.expStatus, resp.GetImmediateResponse().Status.Code)
+		})
+	}
+}
+
+func Test_upstreamProcessor_recordVirtualKeyCost(t *testing.T) {
+	prog, err := llmcostcel.NewProgram("input_tokens * uint(2)")
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		name     string
+		vk       *filterapi.RuntimeVirtualKey
+		expSpent uint64
+	}{
+		{
+			name: "total tokens",
+			vk: &filterapi.RuntimeVirtualKey{VirtualKey: &filterapi.VirtualKey{
+				Name: "ns/total", Budget: &filterapi.VirtualKeyBudget{Limit: 100, Window: time.Hour},
+			}},
+			expSpent: 30,
+		},
+		{
+			name: "cel",
+			vk: &filterapi.RuntimeVirtualKey{
+				VirtualKey: &filterapi.VirtualKey{
+					Name: "ns/cel", Budget: &filterapi.VirtualKeyBudget{Limit: 100, Window: time.Hour, CEL: "input_tokens * uint(2)"},
+				},
+				BudgetCELProg: prog,
+			},
+			expSpent: 20,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() { virtualKeySpends.Delete(tc.vk.Name) })
+			u := &chatCompletionProcessorUpstreamFilter{
+				parent: &chatCompletionProcessorRouterFilter{virtualKey: tc.vk},
+				logger: slog.Default(),
+			}
+			u.costs.SetInputTokens(10)
+			u.costs.SetOutputTokens(20)
+			u.costs.SetTotalTokens(30)
+			u.recordVirtualKeyCost()
+			u.recordVirtualKeyCost()
+			require.Equal(t, 2*tc.expSpent, virtualKeySpendOf(tc.vk.Name).spent)
+		})
+	}
+}
+
+func Test_virtualKeySpend(t *testing.T) {
+	budget := &filterapi.VirtualKeyBudget{Limit: 10, Window: time.Hour}
+	now := time.Now()
+	s := &virtualKeySpend{}
+	require.False(t, s.exhausted(budget, now))
+	s.add(budget, 6, now)
+	require.False(t, s.exhausted(budget, now.Add(time.Minute)))
+	s.add(budget, 4, now.Add(time.Minute))
+	require.True(t, s.exhausted(budget, now.Add(59*time.Minute)))
+	// The spent cost is reset at the end of the window.
+	require.False(t, s.exhausted(budget, now.Add(time.Hour)))
+	require.Zero(t, s.spent)
+}
+
+func Test_pruneVirtualKeySpends(t *testing.T) {
+	t.Cleanup(func() {
+		virtualKeySpends.Delete("ns/kept")
+		virtualKeySpends.Delete("ns/removed")
+	})
+	budget := &filterapi.VirtualKeyBudget{Limit: 10, Window: time.Hour}
+	virtualKeySpendOf("ns/kept").add(budget, 5, time.Now())
+	virtualKeySpendOf("ns/removed").add(budget, 5, time.Now())
+
+	pruneVirtualKeySpends([]filterapi.VirtualKey{{Name: "ns/kept", KeyHash: "old"}, {Name: "ns/kept", KeyHash: "new"}})
+	s, ok := virtualKeySpends.Load("ns/kept")
+	require.True(t, ok)
+	require.Equal(t, uint64(5), s.(*virtualKeySpend).spent)
+	_, ok = virtualKeySpends.Load("ns/removed")
+	require.False(t, ok)
+}
+
+func Test_virtualKeyCredential(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		headers           map[string]string
+		expHeader, expKey string
+	}{
+		{name: "none", headers: map[string]string{}},
+		{name: "bearer", headers: map[string]string{"authorization": "Bearer sk-1"}, expHeader: "authorization", expKey: "sk-1"},
+		{name: "lower case bearer", headers: map[string]string{"authorization": "bearer sk-1"}, expHeader: "authorization", expKey: "sk-1"},
+		{name: "basic", headers: map[string]string{"authorization": "Basic Zm9vOmJhcg=="}},
+		{name: "x-api-key", headers: map[string]string{"x-api-key": "sk-2"}, expHeader: "x-api-key", expKey: "sk-2"},
+		{
+			name:      "both",
+			headers:   map[string]string{"authorization": "Bearer sk-1", "x-api-key": "sk-2"},
+			expHeader: "authorization", expKey: "sk-1",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			header, key := virtualKeyCredential(tc.headers)
+			require.Equal(t, tc.expHeader, header)
+			require.Equal(t, tc.expKey, key)
+		})
+	}
+}
+
+func Test_buildRequestHeaderDynamicMetadata_virtualKey(t *testing.T) {
+	md := buildRequestHeaderDynamicMetadata(map[string]string{internalapi.VirtualKeyHeader: "ns/team-a"})
+	require.NotNil(t, md)
+	fields := md.Fields[internalapi.AIGatewayFilterMetadataNamespace].GetStructValue().Fields
+	require.Equal(t, "ns/team-a", fields[internalapi.VirtualKeyAttribute].GetStringValue())
+
+	require.Nil(t, buildRequestHeaderDynamicMetadata(map[string]string{}))
+}
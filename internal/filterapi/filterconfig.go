@@ -51,6 +51,52 @@ type Config struct {
 	UnscopedModels []Model `json:"unscopedModels,omitempty"`
 	// MCPConfig is the configuration for the MCPRoute implementations.
 	MCPConfig *MCPConfig `json:"mcpConfig,omitempty"`
+	// ToolExecutions is the list of the routes whose chat completion requests have their MCP tool calls executed by
+	// the gateway.
+	ToolExecutions []ToolExecution `json:"toolExecutions,omitempty"`
+	// VirtualKeys is the list of the client API keys issued by the gateway for the routes of this listener.
+	VirtualKeys []VirtualKey `json:"virtualKeys,omitempty"`
+}
+
+// ToolExecution configures the execution of the tools of an MCPRoute by the gateway for the chat completion requests
+// of an AIGatewayRoute.
+type ToolExecution struct {
+	// RouteName is the name of the AIGatewayRoute (format "namespace/name").
+	RouteName string `json:"routeName"`
+	// MCPRoute is the name of the MCPRoute whose tools are executed, as in [MCPRoute.Name].
+	MCPRoute MCPRouteName `json:"mcpRoute"`
+	// MaxIterations is the maximum number of times the model is called back with the results of the tool calls.
+	MaxIterations int `json:"maxIterations"`
+	// Timeout is the maximum time spent executing the tool calls and calling back the model for a request.
+	Timeout time.Duration `json:"timeout"`
+}
+
+// VirtualKey is a client API key issued by the gateway, which is validated by the filter before routing the requests.
+type VirtualKey struct {
+	// Name is the name of the VirtualKey (format "namespace/name"), which identifies the clients using the key.
+	Name string `json:"name"`
+	// KeyHash is the hex-encoded SHA-256 hash of the key. The keys themselves are not part of the configuration.
+	KeyHash string `json:"keyHash"`
+	// Routes are the names of the AIGatewayRoutes (format "namespace/name") the key can be used with.
+	Routes []string `json:"routes"`
+	// Models are the names of the models the key can be used with. Empty means all the models.
+	Models []string `json:"models,omitempty"`
+	// ExpiresAt is the time after which the key is rejected. Zero means the key doesn't expire.
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+	// Labels are the metadata about the holder of the key, set in the request headers of the requests using the key.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Budget limits the cost of the requests using the key. Optional.
+	Budget *VirtualKeyBudget `json:"budget,omitempty"`
+}
+
+// VirtualKeyBudget is the cost allowed for a virtual key in a time window.
+type VirtualKeyBudget struct {
+	// Limit is the cost allowed in the window.
+	Limit uint64 `json:"limit"`
+	// Window is the duration of the window.
+	Window time.Duration `json:"window"`
+	// CEL is the CEL expression computing the cost of a request. The total tokens are used when empty.
+	CEL string `json:"cel,omitempty"`
 }
 
 // Model corresponds to the OpenAI model object in the OpenAI-compatible APIs
@@ -177,6 +223,16 @@ const (
 	// Used for Claude models hosted on AWS Bedrock. Supports both OpenAI and Anthropic input formats
 	// depending on the endpoint path, similar to APISchemaGCPAnthropic.
 	APISchemaAWSAnthropic APISchemaName = "AWSAnthropic"
+	// APISchemaOCIGenAI represents the OCI Generative AI inference API schema.
+	// The version of the schema is the OCID of the compartment the requests are made in.
+	APISchemaOCIGenAI APISchemaName = "OCIGenAI"
+	// APISchemaDashScope represents the native Alibaba Cloud Model Studio (DashScope) API schema.
+	APISchemaDashScope APISchemaName = "DashScope"
+	// APISchemaOllama represents the native Ollama API schema.
+	APISchemaOllama APISchemaName = "Ollama"
+	// APISchemaHuggingFaceTGI represents the native Hugging Face Text Generation Inference (TGI) and
+	// Text Embeddings Inference (TEI) API schema.
+	APISchemaHuggingFaceTGI APISchemaName = "HuggingFaceTGI"
 )
 
 // RouteRuleName is the name of the route rule.
@@ -212,8 +268,35 @@ type BackendAuth struct {
 	AzureAuth *AzureAuth `json:"azure,omitempty"`
 	// GCPAuth specifies the location of GCP credential file.
 	GCPAuth *GCPAuth `json:"gcp,omitempty"`
+	// APIKeyPool is a pool of API keys load balanced across the requests.
+	APIKeyPool *APIKeyPoolAuth `json:"apiKeyPool,omitempty"`
+	// RequestSigning specifies the key to sign the requests with.
+	RequestSigning *RequestSigningAuth `json:"requestSigning,omitempty"`
 }
 
+// RequestSigningAuth defines the key and the scheme to sign the requests with.
+type RequestSigningAuth struct {
+	// Scheme is the scheme of the signature.
+	Scheme RequestSigningScheme `json:"scheme"`
+	// KeyID is the identifier of the key sent along the signature.
+	KeyID string `json:"keyID"`
+	// Key is the signing key as a literal string.
+	Key string `json:"key"`
+	// SignedHeaders are the names of the headers included in the signature, in order. Empty means the default of
+	// the scheme.
+	SignedHeaders []string `json:"signedHeaders,omitempty"`
+}
+
+// RequestSigningScheme is the scheme of the signature of the requests.
+type RequestSigningScheme string
+
+const (
+	// RequestSigningSchemeHMACSHA256 signs the requests with HMAC-SHA256 as an HTTP signature in the Authorization header.
+	RequestSigningSchemeHMACSHA256 RequestSigningScheme = "HMAC-SHA256"
+	// RequestSigningSchemeOCI signs the requests with an RSA key as the OCI API request signature in the Authorization header.
+	RequestSigningSchemeOCI RequestSigningScheme = "OCI"
+)
+
 // AWSAuth defines the credentials needed to access AWS.
 type AWSAuth struct {
 	// CredentialFileLiteral is the literal string of the AWS credential file. E.g.
@@ -228,6 +311,37 @@ type APIKeyAuth struct {
 	Key string `json:"key"`
 }
 
+// APIKeyPoolAuth defines a pool of API keys.
+type APIKeyPoolAuth struct {
+	// Keys are the API keys of the pool.
+	Keys []APIKeyPoolKey `json:"keys"`
+	// Strategy is the strategy used to pick the key of each request. Empty means APIKeyPoolStrategyRoundRobin.
+	Strategy APIKeyPoolStrategy `json:"strategy,omitempty"`
+	// Header is the header the API key is injected into. Empty means the Authorization header, in which case
+	// the key is prefixed with "Bearer ".
+	Header string `json:"header,omitempty"`
+	// EjectionDuration is how long a key is ejected from the pool after the backend responds with 401 or 429.
+	EjectionDuration time.Duration `json:"ejectionDuration,omitempty"`
+}
+
+// APIKeyPoolKey is an API key of a pool.
+type APIKeyPoolKey struct {
+	// Key is the API key as a literal string.
+	Key string `json:"key"`
+	// Weight is the relative share of the requests sent with this key. Zero means 1.
+	Weight int `json:"weight,omitempty"`
+}
+
+// APIKeyPoolStrategy is the strategy used to pick the key of each request from a pool of API keys.
+type APIKeyPoolStrategy string
+
+const (
+	// APIKeyPoolStrategyRoundRobin picks the keys in turn, in proportion to their weights.
+	APIKeyPoolStrategyRoundRobin APIKeyPoolStrategy = "RoundRobin"
+	// APIKeyPoolStrategyLeastRecentlyRateLimited picks the key that was rate limited the longest time ago.
+	APIKeyPoolStrategyLeastRecentlyRateLimited APIKeyPoolStrategy = "LeastRecentlyRateLimited"
+)
+
 // AzureAPIKeyAuth defines the Azure OpenAI API key.
 type AzureAPIKeyAuth struct {
 	// Key is the Azure API key as a literal string.
@@ -244,6 +358,35 @@ type AnthropicAPIKeyAuth struct {
 type AzureAuth struct {
 	// AccessToken is the access token as a literal string.
 	AccessToken string `json:"accessToken"`
+	// Identity, when set, makes the external processor acquire and refresh the access tokens itself with the
+	// identity of the gateway pods, instead of using AccessToken.
+	Identity *AzureIdentity `json:"identity,omitempty"`
+}
+
+// AzureIdentityType specifies the type of the identity used to acquire the Azure access tokens.
+type AzureIdentityType string
+
+const (
+	// AzureIdentityTypeManagedIdentity acquires the access tokens from the instance metadata service (IMDS).
+	AzureIdentityTypeManagedIdentity AzureIdentityType = "ManagedIdentity"
+	// AzureIdentityTypeWorkloadIdentity acquires the access tokens by exchanging a federated service account token.
+	AzureIdentityTypeWorkloadIdentity AzureIdentityType = "WorkloadIdentity"
+)
+
+// AzureIdentity defines the identity used by the external processor to acquire the Azure access tokens.
+type AzureIdentity struct {
+	// Type is the type of the identity.
+	Type AzureIdentityType `json:"type"`
+	// ClientID is the client ID of the application or of the user-assigned managed identity. Empty means the
+	// system-assigned managed identity, or the AZURE_CLIENT_ID environment variable for the workload identity.
+	ClientID string `json:"clientID,omitempty"`
+	// TenantID is the tenant ID of the application. Empty means the AZURE_TENANT_ID environment variable.
+	TenantID string `json:"tenantID,omitempty"`
+	// TokenFilePath is the path of the federated token file of the workload identity. Empty means the
+	// AZURE_FEDERATED_TOKEN_FILE environment variable.
+	TokenFilePath string `json:"tokenFilePath,omitempty"`
+	// Scope is the scope of the access tokens.
+	Scope string `json:"scope"`
 }
 
 // GCPAuth defines the GCP authentication configuration used to access Google Cloud AI services.
@@ -5,6 +5,8 @@
 
 package filterapi
 
+import "time"
+
 // MCPConfig is the configuration for the MCP listener and routing.
 type MCPConfig struct {
 	// BackendListenerAddr is the address that speaks plain HTTP and can be used to
@@ -33,6 +35,109 @@ type MCPRoute struct {
 
 	// ForwardHeaders specifies HTTP headers to extract from the incoming request and forward to backend MCP servers.
 	ForwardHeaders []string `json:"forwardHeaders,omitempty"`
+
+	// RateLimits is the list of rate and concurrency limits applied to the tool calls of this route.
+	RateLimits []MCPRateLimitRule `json:"rateLimits,omitempty"`
+
+	// LegacySSE is set when this route is also served over the legacy HTTP+SSE transport.
+	LegacySSE *MCPLegacySSE `json:"legacySSE,omitempty"`
+
+	// ToolValidation configures the validation of the tool calls against the schemas of the tools.
+	// If nil, only the arguments of the tool calls are validated.
+	ToolValidation *MCPToolValidation `json:"toolValidation,omitempty"`
+
+	// ToolSearch is set when tools/list returns the tool search meta-tools instead of the tools of the backends.
+	ToolSearch *MCPToolSearch `json:"toolSearch,omitempty"`
+
+	// HealthCheck is set when the health of the backends of this route is checked.
+	HealthCheck *MCPHealthCheck `json:"healthCheck,omitempty"`
+}
+
+// MCPHealthCheck is the configuration of the health checking of the backends of a route.
+type MCPHealthCheck struct {
+	// Interval is the time between two active probes of a backend.
+	Interval time.Duration `json:"interval"`
+
+	// Timeout is the maximum duration of an active probe.
+	Timeout time.Duration `json:"timeout"`
+
+	// UnhealthyThreshold is the number of consecutive failed probes or requests after which a backend is ejected.
+	UnhealthyThreshold int `json:"unhealthyThreshold"`
+
+	// EjectionTime is the minimum time a backend stays ejected.
+	EjectionTime time.Duration `json:"ejectionTime"`
+}
+
+// MCPToolSearch is the configuration of the tool search mode of a route.
+type MCPToolSearch struct {
+	// MaxResults is the maximum number of tools returned by a search.
+	MaxResults int `json:"maxResults"`
+}
+
+// MCPToolValidation defines which parts of the tool calls are validated against the schemas of the tools.
+type MCPToolValidation struct {
+	// Arguments enables the validation of the tool call arguments against the inputSchema of the tools.
+	Arguments bool `json:"arguments,omitempty"`
+
+	// StructuredContent enables the validation of the tool results against the outputSchema of the tools.
+	StructuredContent bool `json:"structuredContent,omitempty"`
+}
+
+// MCPLegacySSE is the configuration of the client-facing legacy HTTP+SSE transport of a route.
+type MCPLegacySSE struct {
+	// SSEPath is the path of the endpoint the clients open the SSE stream with.
+	SSEPath string `json:"ssePath"`
+
+	// MessagePath is the path of the endpoint the clients send their messages to.
+	MessagePath string `json:"messagePath"`
+}
+
+// MCPRateLimitRule defines a rate and/or concurrency limit for tool calls.
+type MCPRateLimitRule struct {
+	// Backend is the name of the backend this rule applies to. If empty, the rule applies to all backends.
+	Backend MCPBackendName `json:"backend,omitempty"`
+
+	// Tool is the name of the tool this rule applies to, without the backend prefix.
+	// If empty, the rule applies to all tools.
+	Tool string `json:"tool,omitempty"`
+
+	// ClientKey specifies how tool calls are grouped into per-client limits.
+	// If nil, all matching tool calls share the same limit.
+	ClientKey *MCPRateLimitClientKey `json:"clientKey,omitempty"`
+
+	// Requests is the maximum number of tool calls allowed per time unit. If nil, no rate limit is applied.
+	Requests *MCPRateLimitValue `json:"requests,omitempty"`
+
+	// MaxConcurrency is the maximum number of in-flight tool calls. Zero means no concurrency limit.
+	MaxConcurrency uint32 `json:"maxConcurrency,omitempty"`
+}
+
+// MCPRateLimitClientKey specifies how tool calls are grouped into per-client limits.
+type MCPRateLimitClientKey struct {
+	// Type is the source of the client key.
+	Type MCPRateLimitClientKeyType `json:"type"`
+
+	// Header is the request header whose value is used as the client key when Type is Header.
+	Header string `json:"header,omitempty"`
+}
+
+// MCPRateLimitClientKeyType is the source of the client key for MCP rate limiting.
+type MCPRateLimitClientKeyType string
+
+const (
+	// MCPRateLimitClientKeyTypeSubject uses the "sub" claim of the client's JWT as the client key.
+	MCPRateLimitClientKeyTypeSubject MCPRateLimitClientKeyType = "Subject"
+	// MCPRateLimitClientKeyTypeHeader uses the value of a request header as the client key.
+	MCPRateLimitClientKeyTypeHeader MCPRateLimitClientKeyType = "Header"
+)
+
+// MCPRateLimitValue defines the number of tool calls allowed per time unit.
+type MCPRateLimitValue struct {
+	// Limit is the number of tool calls allowed per Unit.
+	Limit uint32 `json:"limit"`
+
+	// Unit is the time unit of the limit, one of "Second", "Minute", "Hour" or "Day".
+	Unit string `json:"unit"`
 }
 
 // MCPBackend is the MCP backend configuration.
@@ -47,6 +152,212 @@ type MCPBackend struct {
 	// ForwardHeaders specifies HTTP headers to extract from the incoming request and forward to this backend.
 	// Each entry maps a source header name to an optional destination header name.
 	ForwardHeaders []MCPHeaderForward `json:"forwardHeaders,omitempty"`
+
+	// Stdio is set when this backend is a stdio MCP server launched by the MCP proxy.
+	// The requests to such backends are sent directly to the local server instead of the backend listener.
+	Stdio *MCPStdioBackend `json:"stdio,omitempty"`
+
+	// Transport is the MCP transport spoken by the backend. Empty means the Streamable HTTP transport.
+	Transport MCPBackendTransport `json:"transport,omitempty"`
+
+	// Path is the path of the SSE endpoint of the backend. It is only set with the SSE transport, and is used to
+	// resolve the relative message endpoint advertised by the backend.
+	Path string `json:"path,omitempty"`
+
+	// Credential is set when a credential specific to the user of each request is injected into the requests to
+	// this backend.
+	Credential *MCPBackendCredential `json:"credential,omitempty"`
+
+	// ServerRequests is the policy of the sampling and elicitation requests this backend sends to the clients.
+	// If nil, the requests are forwarded as-is.
+	ServerRequests *MCPServerRequestPolicy `json:"serverRequests,omitempty"`
+
+	// AIGatewayRoute is set when this backend exposes the models of an AIGatewayRoute as MCP tools and prompts
+	// served by the MCP proxy. The requests to such backends are sent directly to the local server.
+	AIGatewayRoute *MCPAIGatewayRouteBackend `json:"aiGatewayRoute,omitempty"`
+
+	// OpenAPI is set when this backend is a REST API whose operations are exposed as MCP tools served by the MCP
+	// proxy. The requests to such backends are sent directly to the local server, which sends the HTTP requests of the
+	// tool calls to the backend listener.
+	OpenAPI *MCPOpenAPIBackend `json:"openAPI,omitempty"`
+}
+
+// MCPOpenAPIBackend is the configuration of the operations of a REST API exposed as MCP tools. It is derived by the
+// controller from the OpenAPI document of the backend.
+type MCPOpenAPIBackend struct {
+	// BasePath is the path prefixed to the paths of the operations, e.g. "/api/v1".
+	BasePath string `json:"basePath,omitempty"`
+
+	// Operations are the operations exposed as tools.
+	Operations []MCPOpenAPIOperation `json:"operations,omitempty"`
+}
+
+// MCPOpenAPIOperation is an operation of a REST API exposed as an MCP tool.
+type MCPOpenAPIOperation struct {
+	// Name is the name of the tool.
+	Name string `json:"name"`
+
+	// Description is the description of the tool.
+	Description string `json:"description,omitempty"`
+
+	// Method is the HTTP method of the operation.
+	Method string `json:"method"`
+
+	// Path is the path template of the operation, e.g. "/pets/{petId}".
+	Path string `json:"path"`
+
+	// Parameters are the path, query and header parameters of the operation, each of which is a property of the
+	// arguments of the tool.
+	Parameters []MCPOpenAPIParameter `json:"parameters,omitempty"`
+
+	// BodyProperty is the property of the arguments of the tool holding the JSON request body. Empty means the
+	// operation has no request body.
+	BodyProperty string `json:"bodyProperty,omitempty"`
+
+	// InputSchema is the JSON schema of the arguments of the tool.
+	InputSchema map[string]any `json:"inputSchema,omitempty"`
+}
+
+// MCPOpenAPIParameter is a parameter of an operation.
+type MCPOpenAPIParameter struct {
+	// Name is the name of the parameter, which is also the name of the property of the arguments of the tool.
+	Name string `json:"name"`
+
+	// In is the location of the parameter: "path", "query" or "header".
+	In string `json:"in"`
+}
+
+// MCPAIGatewayRouteBackend is the configuration of the models of an AIGatewayRoute exposed as MCP tools and prompts.
+type MCPAIGatewayRouteBackend struct {
+	// URL is the base URL of the Envoy listener the AIGatewayRoute is attached to, e.g. "http://127.0.0.1:10080".
+	URL string `json:"url"`
+
+	// Host is the host of the requests sent to the AIGatewayRoute. Empty means the host of the URL.
+	Host string `json:"host,omitempty"`
+
+	// ChatModels are the models exposed as the ask_<model> tools and prompts.
+	ChatModels []string `json:"chatModels,omitempty"`
+
+	// EmbeddingModel is the model used by the embed_text tool. Empty means the tool is not exposed.
+	EmbeddingModel string `json:"embeddingModel,omitempty"`
+
+	// ImageModel is the model used by the generate_image tool. Empty means the tool is not exposed.
+	ImageModel string `json:"imageModel,omitempty"`
+}
+
+// MCPServerRequestPolicy is the policy of the server->client requests of a backend.
+type MCPServerRequestPolicy struct {
+	// Sampling is the policy of the sampling/createMessage requests. If nil, they are forwarded as-is.
+	Sampling *MCPSamplingPolicy `json:"sampling,omitempty"`
+
+	// Elicitation is the policy of the elicitation/create requests. If nil, they are forwarded as-is.
+	Elicitation *MCPElicitationPolicy `json:"elicitation,omitempty"`
+}
+
+// MCPServerRequestAction is the action taken on a server->client request.
+type MCPServerRequestAction string
+
+const (
+	// MCPServerRequestActionAllow forwards the requests to the client.
+	MCPServerRequestActionAllow MCPServerRequestAction = "Allow"
+	// MCPServerRequestActionDeny answers the requests with a JSON-RPC error.
+	MCPServerRequestActionDeny MCPServerRequestAction = "Deny"
+	// MCPServerRequestActionAudit forwards the requests to the client and records them in the audit log.
+	MCPServerRequestActionAudit MCPServerRequestAction = "Audit"
+)
+
+// MCPSamplingPolicy is the policy of the sampling/createMessage requests of a backend.
+type MCPSamplingPolicy struct {
+	// Action is the action taken on the requests.
+	Action MCPServerRequestAction `json:"action"`
+
+	// MaxTokens caps the maxTokens of the requests. Zero means no cap.
+	MaxTokens int64 `json:"maxTokens,omitempty"`
+
+	// IncludeContext is the widest context the requests can ask for. Empty means no limit.
+	IncludeContext string `json:"includeContext,omitempty"`
+}
+
+// MCPElicitationPolicy is the policy of the elicitation/create requests of a backend.
+type MCPElicitationPolicy struct {
+	// Action is the action taken on the requests.
+	Action MCPServerRequestAction `json:"action"`
+
+	// DeniedFields is the list of sensitive fields that cannot be requested, matched against the names and titles
+	// of the properties of the requested schema.
+	DeniedFields []string `json:"deniedFields,omitempty"`
+}
+
+// MCPBackendCredential is the configuration of the per-user credential injected into the requests to a backend.
+// Exactly one of TokenExchange or UserCredentials is set.
+type MCPBackendCredential struct {
+	// Header is the header to inject the credential into. The value is prefixed with "Bearer " for "Authorization".
+	Header string `json:"header"`
+
+	// TokenExchange is set when the credential is obtained by exchanging the access token of the client.
+	TokenExchange *MCPTokenExchange `json:"tokenExchange,omitempty"`
+
+	// UserCredentials maps the subject of the access token of the client to the credential of the user.
+	UserCredentials map[string]string `json:"userCredentials,omitempty"`
+}
+
+// MCPTokenExchange is the configuration of the OAuth 2.0 Token Exchange (RFC 8693) of the access token of the client.
+type MCPTokenExchange struct {
+	// TokenEndpoint is the URL of the token endpoint of the authorization server.
+	TokenEndpoint string `json:"tokenEndpoint"`
+
+	// ClientID is the OAuth client ID used to authenticate to the token endpoint.
+	ClientID string `json:"clientID"`
+
+	// ClientSecret is the OAuth client secret. If empty, the client is a public client.
+	ClientSecret string `json:"clientSecret,omitempty"`
+
+	// Audience is the logical name of the backend the token is requested for.
+	Audience string `json:"audience,omitempty"`
+
+	// Resource is the URI of the backend the token is requested for.
+	Resource string `json:"resource,omitempty"`
+
+	// Scopes is the list of scopes requested for the token.
+	Scopes []string `json:"scopes,omitempty"`
+
+	// RequestedTokenType is the type of the requested token.
+	RequestedTokenType string `json:"requestedTokenType,omitempty"`
+}
+
+// MCPBackendTransport is the MCP transport spoken by a backend.
+type MCPBackendTransport string
+
+const (
+	// MCPBackendTransportSSE is the legacy HTTP+SSE transport of the MCP specification 2024-11-05.
+	MCPBackendTransportSSE MCPBackendTransport = "SSE"
+)
+
+// MCPStdioBackend is the configuration of a stdio MCP server launched and supervised by the MCP proxy.
+type MCPStdioBackend struct {
+	// Command is the command to run the stdio MCP server.
+	Command string `json:"command"`
+
+	// Args are the arguments passed to the command.
+	Args []string `json:"args,omitempty"`
+
+	// Env is the environment variables set for the stdio MCP server.
+	Env map[string]string `json:"env,omitempty"`
+
+	// PerSession runs a dedicated process for each MCP session instead of a single shared process.
+	PerSession bool `json:"perSession,omitempty"`
+
+	// MaxProcesses is the maximum number of concurrent processes when PerSession is set. Zero means unlimited.
+	MaxProcesses int `json:"maxProcesses,omitempty"`
+
+	// SessionIdleTimeout is the duration after which an idle MCP session is closed. Zero means never.
+	SessionIdleTimeout time.Duration `json:"sessionIdleTimeout,omitempty"`
+
+	// MemoryLimitBytes is the maximum size of the virtual memory of each process. Zero means no limit.
+	MemoryLimitBytes uint64 `json:"memoryLimitBytes,omitempty"`
+
+	// CPUTimeLimit is the maximum CPU time each process can consume. Zero means no limit.
+	CPUTimeLimit time.Duration `json:"cpuTimeLimit,omitempty"`
 }
 
 // MCPHeaderForward specifies a header to extract from the incoming request and forward to a backend.
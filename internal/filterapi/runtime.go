@@ -7,6 +7,7 @@ package filterapi
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/google/cel-go/cel"
@@ -22,9 +23,63 @@ type BackendAuthHandler interface {
 	Do(ctx context.Context, requestHeaders map[string]string, mutatedBody []byte) ([]internalapi.Header, error)
 }
 
+// BackendAuthBodySigner is optionally implemented by the BackendAuthHandler whose credentials cover the request body,
+// such as a signature of its digest. The body of the request is then always replaced with the body passed to Do, even
+// when the translation does not modify it, so that the backend receives the body that was signed.
+type BackendAuthBodySigner interface {
+	// SignsBody returns true if the credentials added by Do depend on the request body.
+	SignsBody() bool
+}
+
+// BackendAuthResponseHandler is optionally implemented by the BackendAuthHandler that need to see the responses of
+// the backend, for example to stop using the credentials that were rejected or rate limited.
+type BackendAuthResponseHandler interface {
+	// ResponseHeaders is called with the request headers modified by Do and the response headers of the backend.
+	ResponseHeaders(ctx context.Context, requestHeaders map[string]string, responseHeaders map[string]string)
+}
+
 // NewBackendAuthHandlerFunc is a function type that creates a new BackendAuthHandler for a given BackendAuth configuration.
 type NewBackendAuthHandlerFunc func(ctx context.Context, auth *BackendAuth) (BackendAuthHandler, error)
 
+// BackendError is the error of a backend that cannot be used with the configuration, for example because its auth
+// handler cannot be created.
+type BackendError struct {
+	// Backend is the name of the backend.
+	Backend string
+	// Err is the cause of the error.
+	Err error
+}
+
+// Error implements error.
+func (e *BackendError) Error() string {
+	return fmt.Sprintf("backend %s: %v", e.Backend, e.Err)
+}
+
+// Unwrap returns the cause of the error.
+func (e *BackendError) Unwrap() error { return e.Err }
+
+// BackendErrors returns all the BackendError in the tree of the given error, including the ones joined with
+// errors.Join.
+func BackendErrors(err error) []*BackendError {
+	var ret []*BackendError
+	var walk func(err error)
+	walk = func(err error) {
+		switch e := err.(type) {
+		case nil:
+		case *BackendError:
+			ret = append(ret, e)
+		case interface{ Unwrap() []error }:
+			for _, err := range e.Unwrap() {
+				walk(err)
+			}
+		case interface{ Unwrap() error }:
+			walk(e.Unwrap())
+		}
+	}
+	walk(err)
+	return ret
+}
+
 // RuntimeConfig is the runtime filter configuration that is derived from the filterapi.Config.
 type RuntimeConfig struct {
 	// UUID is the unique identifier of the filter configuration, inherited from filterapi.Config.
@@ -45,6 +100,24 @@ type RuntimeConfig struct {
 	UnscopedModels []Model
 	// Backends is the map of backends by name.
 	Backends map[string]*RuntimeBackend
+	// ToolExecutions is the map of the tool execution configurations by route name.
+	ToolExecutions map[string]*ToolExecution
+	// VirtualKeys is the map of the virtual keys by the hash of the key.
+	VirtualKeys map[string]*RuntimeVirtualKey
+	// VirtualKeyRoutes is the set of the names of the routes that can only be used with a virtual key.
+	VirtualKeyRoutes map[string]struct{}
+}
+
+// RuntimeVirtualKey is a virtual key with its allowed routes and models indexed, derived from the filterapi.VirtualKey
+// configuration.
+type RuntimeVirtualKey struct {
+	*VirtualKey
+	// AllowedRoutes is the set of the routes the key can be used with.
+	AllowedRoutes map[string]struct{}
+	// AllowedModels is the set of the models the key can be used with. Nil means all the models.
+	AllowedModels map[string]struct{}
+	// BudgetCELProg is the compiled CEL program computing the cost of the requests for the budget, if any.
+	BudgetCELProg cel.Program
 }
 
 // RuntimeBackend is a filter backend with its auth handler that is derived from the filterapi.Backend configuration.
@@ -70,8 +143,12 @@ type RuntimeRequestCost struct {
 }
 
 // NewRuntimeConfig creates a new runtime filter configuration from the given filterapi.Config and a function to create backend auth handlers.
+//
+// The auth handlers of all the backends are created even if some fail, so that the returned error holds a
+// BackendError per failing backend. See BackendErrors.
 func NewRuntimeConfig(ctx context.Context, config *Config, fn NewBackendAuthHandlerFunc) (*RuntimeConfig, error) {
 	backends := make(map[string]*RuntimeBackend, len(config.Backends))
+	var backendErrs []error
 	for i := range config.Backends {
 		b := &config.Backends[i]
 		var h BackendAuthHandler
@@ -79,12 +156,16 @@ func NewRuntimeConfig(ctx context.Context, config *Config, fn NewBackendAuthHand
 			var err error
 			h, err = fn(ctx, b.Auth)
 			if err != nil {
-				return nil, fmt.Errorf("cannot create backend auth handler: %w", err)
+				backendErrs = append(backendErrs, &BackendError{Backend: b.Name, Err: err})
+				continue
 			}
 		}
 
 		backends[b.Name] = &RuntimeBackend{Backend: b, Handler: h}
 	}
+	if err := errors.Join(backendErrs...); err != nil {
+		return nil, fmt.Errorf("cannot create backend auth handler: %w", err)
+	}
 
 	// Compile CEL programs for GlobalLLMRequestCosts (gateway-level defaults).
 	globalCosts := make([]RuntimeGlobalRequestCost, 0, len(config.GlobalLLMRequestCosts))
@@ -123,6 +204,43 @@ func NewRuntimeConfig(ctx context.Context, config *Config, fn NewBackendAuthHand
 		costs = append(costs, RuntimeRequestCost{LLMRequestCost: c, CELProg: prog})
 	}
 
+	var toolExecutions map[string]*ToolExecution
+	if len(config.ToolExecutions) > 0 {
+		toolExecutions = make(map[string]*ToolExecution, len(config.ToolExecutions))
+		for i := range config.ToolExecutions {
+			toolExecutions[config.ToolExecutions[i].RouteName] = &config.ToolExecutions[i]
+		}
+	}
+
+	var virtualKeys map[string]*RuntimeVirtualKey
+	var virtualKeyRoutes map[string]struct{}
+	if len(config.VirtualKeys) > 0 {
+		virtualKeys = make(map[string]*RuntimeVirtualKey, len(config.VirtualKeys))
+		virtualKeyRoutes = make(map[string]struct{})
+		for i := range config.VirtualKeys {
+			vk := &config.VirtualKeys[i]
+			rvk := &RuntimeVirtualKey{VirtualKey: vk, AllowedRoutes: make(map[string]struct{}, len(vk.Routes))}
+			for _, r := range vk.Routes {
+				rvk.AllowedRoutes[r] = struct{}{}
+				virtualKeyRoutes[r] = struct{}{}
+			}
+			if len(vk.Models) > 0 {
+				rvk.AllowedModels = make(map[string]struct{}, len(vk.Models))
+				for _, m := range vk.Models {
+					rvk.AllowedModels[m] = struct{}{}
+				}
+			}
+			if vk.Budget != nil && vk.Budget.CEL != "" {
+				var err error
+				rvk.BudgetCELProg, err = llmcostcel.NewProgram(vk.Budget.CEL)
+				if err != nil {
+					return nil, fmt.Errorf("cannot create CEL program for the budget of virtual key %s: %w", vk.Name, err)
+				}
+			}
+			virtualKeys[vk.KeyHash] = rvk
+		}
+	}
+
 	return &RuntimeConfig{
 		UUID:               config.UUID,
 		Backends:           backends,
@@ -131,5 +249,8 @@ func NewRuntimeConfig(ctx context.Context, config *Config, fn NewBackendAuthHand
 		DeclaredModels:     config.Models,
 		ModelsByHost:       config.ModelsByHost,
 		UnscopedModels:     config.UnscopedModels,
+		ToolExecutions:     toolExecutions,
+		VirtualKeys:        virtualKeys,
+		VirtualKeyRoutes:   virtualKeyRoutes,
 	}, nil
 }
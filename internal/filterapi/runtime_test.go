@@ -7,6 +7,8 @@ package filterapi
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -59,10 +61,91 @@ func TestServer_LoadConfig(t *testing.T) {
 		require.Equal(t, "1 + 1", rc.RequestCosts[1].CEL)
 		prog := rc.RequestCosts[1].CELProg
 		require.NotNil(t, prog)
-		val, err := llmcostcel.EvaluateProgram(prog, "", "", "", 1, 1, 1, 1, 1, 0)
+		val, err := llmcostcel.EvaluateProgram(prog, "", "", "", "", 1, 1, 1, 1, 1, 0)
 		require.NoError(t, err)
 		require.Equal(t, uint64(2), val)
 		require.Equal(t, config.Models, rc.DeclaredModels)
+		require.Nil(t, rc.ToolExecutions)
+	})
+
+	t.Run("backend auth errors", func(t *testing.T) {
+		config := &Config{
+			Backends: []Backend{
+				{Name: "bad1", Auth: &BackendAuth{APIKey: &APIKeyAuth{Key: "bad1"}}},
+				{Name: "ok", Auth: &BackendAuth{APIKey: &APIKeyAuth{Key: "ok"}}},
+				{Name: "bad2", Auth: &BackendAuth{APIKey: &APIKeyAuth{Key: "bad2"}}},
+			},
+		}
+		_, err := NewRuntimeConfig(t.Context(), config, func(_ context.Context, b *BackendAuth) (BackendAuthHandler, error) {
+			if b.APIKey.Key == "ok" {
+				return nil, nil
+			}
+			return nil, errors.New("invalid " + b.APIKey.Key)
+		})
+		require.ErrorContains(t, err, "cannot create backend auth handler")
+		backendErrs := BackendErrors(fmt.Errorf("wrapped: %w", err))
+		require.Len(t, backendErrs, 2)
+		require.Equal(t, "bad1", backendErrs[0].Backend)
+		require.EqualError(t, backendErrs[0], "backend bad1: invalid bad1")
+		require.Equal(t, "bad2", backendErrs[1].Backend)
+		require.EqualError(t, backendErrs[1].Err, "invalid bad2")
+
+		require.Empty(t, BackendErrors(errors.New("version mismatch")))
+		require.Empty(t, BackendErrors(nil))
+	})
+
+	t.Run("with tool executions", func(t *testing.T) {
+		config := &Config{
+			ToolExecutions: []ToolExecution{
+				{RouteName: "ns/route1", MCPRoute: "ns/mcp-route", MaxIterations: 5, Timeout: time.Minute},
+			},
+		}
+		rc, err := NewRuntimeConfig(t.Context(), config, func(_ context.Context, _ *BackendAuth) (BackendAuthHandler, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]*ToolExecution{"ns/route1": &config.ToolExecutions[0]}, rc.ToolExecutions)
+	})
+
+	t.Run("with virtual keys", func(t *testing.T) {
+		config := &Config{
+			VirtualKeys: []VirtualKey{
+				{Name: "ns/team-a", KeyHash: "hash-a", Routes: []string{"ns/route1", "ns/route2"}},
+				{
+					Name: "ns/team-b", KeyHash: "hash-b", Routes: []string{"ns/route2"}, Models: []string{"gpt-4o"},
+					Budget: &VirtualKeyBudget{Limit: 100, Window: time.Hour, CEL: "input_tokens + output_tokens"},
+				},
+			},
+		}
+		rc, err := NewRuntimeConfig(t.Context(), config, func(_ context.Context, _ *BackendAuth) (BackendAuthHandler, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]struct{}{"ns/route1": {}, "ns/route2": {}}, rc.VirtualKeyRoutes)
+		require.Len(t, rc.VirtualKeys, 2)
+
+		a := rc.VirtualKeys["hash-a"]
+		require.Equal(t, &config.VirtualKeys[0], a.VirtualKey)
+		require.Equal(t, map[string]struct{}{"ns/route1": {}, "ns/route2": {}}, a.AllowedRoutes)
+		require.Nil(t, a.AllowedModels)
+		require.Nil(t, a.BudgetCELProg)
+
+		b := rc.VirtualKeys["hash-b"]
+		require.Equal(t, map[string]struct{}{"ns/route2": {}}, b.AllowedRoutes)
+		require.Equal(t, map[string]struct{}{"gpt-4o": {}}, b.AllowedModels)
+		require.NotNil(t, b.BudgetCELProg)
+	})
+
+	t.Run("error - invalid CEL in virtual key budget", func(t *testing.T) {
+		config := &Config{
+			VirtualKeys: []VirtualKey{
+				{Name: "ns/team-a", KeyHash: "hash-a", Budget: &VirtualKeyBudget{Limit: 1, Window: time.Hour, CEL: "bad syntax @@"}},
+			},
+		}
+		_, err := NewRuntimeConfig(t.Context(), config, func(_ context.Context, _ *BackendAuth) (BackendAuthHandler, error) {
+			return nil, nil
+		})
+		require.ErrorContains(t, err, "cannot create CEL program for the budget of virtual key ns/team-a")
 	})
 
 	t.Run("with global costs", func(t *testing.T) {
@@ -12,6 +12,7 @@ import (
 	"maps"
 	"slices"
 	"strings"
+	"time"
 
 	aigv1b1 "github.com/envoyproxy/ai-gateway/api/v1beta1"
 )
@@ -33,6 +34,21 @@ const (
 	MCPBackendHeader = EnvoyAIGatewayHeaderPrefix + "mcp-backend"
 	// MCPRouteHeader is the special header key used to identify the mcp route.
 	MCPRouteHeader = EnvoyAIGatewayHeaderPrefix + "mcp-route"
+	// MCPSSEMessageHeader is the special header key set on the message requests sent to the backends speaking the
+	// legacy HTTP+SSE transport, whose path must be preserved.
+	MCPSSEMessageHeader = EnvoyAIGatewayHeaderPrefix + "mcp-sse-message"
+	// ToolExecutionHeader is the special header key set on the requests sent back to the model by the gateway-side
+	// tool execution, so that their responses are returned as is to the tool execution loop.
+	ToolExecutionHeader = EnvoyAIGatewayHeaderPrefix + "tool-execution"
+	// VirtualKeyHeader is the special header key set to the name of the VirtualKey (format "namespace/name") of the
+	// requests authenticated with a virtual key, so that they can be selected by the QuotaPolicies.
+	VirtualKeyHeader = EnvoyAIGatewayHeaderPrefix + "virtual-key"
+	// VirtualKeyLabelHeaderPrefix is the prefix of the special header keys set to the labels of the VirtualKey of the
+	// requests authenticated with a virtual key.
+	VirtualKeyLabelHeaderPrefix = VirtualKeyHeader + "-label-"
+	// VirtualKeyAttribute is the attribute of the metrics, the spans and the access logs set to the name of the
+	// VirtualKey of the requests authenticated with a virtual key.
+	VirtualKeyAttribute = "virtual_key"
 	// MCPBackendListenerPort is the port for the MCP backend listener.
 	MCPBackendListenerPort = 10088
 	// MCPProxyPort is the port where the MCP proxy listens.
@@ -83,6 +99,12 @@ const (
 	XDSUpstreamHostMetadataBackendNamePath = "xds.upstream_host_metadata.filter_metadata['aigateway.envoy.io']['per_route_rule_backend_name']"
 	// XDSRouteMetadataRouteNamePath is the full attribute path to access the route name in route metadata in xDS attributes.
 	XDSRouteMetadataRouteNamePath = "xds.route_metadata.filter_metadata['aigateway.envoy.io']['aigw_route_name']"
+	// DestinationAddressAttribute is the attribute of the local address of the downstream connection, i.e. the address
+	// of the listener, sent to the router level extproc to send the requests of the gateway-side tool execution.
+	DestinationAddressAttribute = "destination.address"
+	// RouterLevelExtProcMaxMessageTimeout is the maximum timeout of a message of the router level extproc, up to which
+	// the gateway-side tool execution extends the timeout of the response body message.
+	RouterLevelExtProcMaxMessageTimeout = 10 * time.Minute
 )
 
 // PerRouteRuleRefBackendName generates a unique backend name for a per-route rule,
@@ -183,6 +205,8 @@ type EndpointPrefixes struct {
 	Cohere string
 	// Anthropic defaults to "/anthropic"
 	Anthropic string
+	// Ollama defaults to "/ollama"
+	Ollama string
 }
 
 // ParseEndpointPrefixes parses a comma-separated list of key:value pairs to populate EndpointPrefixes.
@@ -191,10 +215,11 @@ type EndpointPrefixes struct {
 //   - openai
 //   - cohere
 //   - anthropic
+//   - ollama
 //
 // Format example:
 //
-//	"openai:/,cohere:/cohere,anthropic:/anthropic"
+//	"openai:/,cohere:/cohere,anthropic:/anthropic,ollama:/ollama"
 //
 // Unknown keys cause an error; values must be non-empty.
 func ParseEndpointPrefixes(s string) (EndpointPrefixes, error) {
@@ -202,6 +227,7 @@ func ParseEndpointPrefixes(s string) (EndpointPrefixes, error) {
 		OpenAI:    "/",
 		Cohere:    "/cohere",
 		Anthropic: "/anthropic",
+		Ollama:    "/ollama",
 	}
 	if s == "" {
 		return out, nil
@@ -229,8 +255,10 @@ func ParseEndpointPrefixes(s string) (EndpointPrefixes, error) {
 			out.Cohere = value
 		case "anthropic":
 			out.Anthropic = value
+		case "ollama":
+			out.Ollama = value
 		default:
-			return EndpointPrefixes{}, fmt.Errorf("unknown endpointPrefixes key %q at position %d (allowed: openai, cohere, anthropic)", key, i+1)
+			return EndpointPrefixes{}, fmt.Errorf("unknown endpointPrefixes key %q at position %d (allowed: openai, cohere, anthropic, ollama)", key, i+1)
 		}
 	}
 	return out, nil
@@ -13,12 +13,13 @@ import (
 )
 
 func TestParseEndpointPrefixes_Success(t *testing.T) {
-	in := "openai:/foo,cohere:/1/2/3,anthropic:/cat"
+	in := "openai:/foo,cohere:/1/2/3,anthropic:/cat,ollama:/llama"
 	ep, err := ParseEndpointPrefixes(in)
 	require.NoError(t, err)
 	require.Equal(t, "/foo", ep.OpenAI)
 	require.Equal(t, "/1/2/3", ep.Cohere)
 	require.Equal(t, "/cat", ep.Anthropic)
+	require.Equal(t, "/llama", ep.Ollama)
 }
 
 func TestParseEndpointPrefixes_EmptyInput(t *testing.T) {
@@ -27,6 +28,7 @@ func TestParseEndpointPrefixes_EmptyInput(t *testing.T) {
 	require.Equal(t, "/", ep.OpenAI)
 	require.Equal(t, "/cohere", ep.Cohere)
 	require.Equal(t, "/anthropic", ep.Anthropic)
+	require.Equal(t, "/ollama", ep.Ollama)
 }
 
 func TestParseEndpointPrefixes_UnknownKey(t *testing.T) {
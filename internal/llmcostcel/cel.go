@@ -19,6 +19,7 @@ const (
 	celModelNameKey                = "model"
 	celBackendKey                  = "backend"
 	celRouteNameKey                = "route_name"
+	celVirtualKeyKey               = "virtual_key"
 	celInputTokensKey              = "input_tokens"
 	celCachedInputTokensKey        = "cached_input_tokens"         // #nosec G101
 	celCacheCreationInputTokensKey = "cache_creation_input_tokens" // #nosec G101
@@ -35,6 +36,7 @@ func init() {
 		cel.Variable(celModelNameKey, cel.StringType),
 		cel.Variable(celBackendKey, cel.StringType),
 		cel.Variable(celRouteNameKey, cel.StringType),
+		cel.Variable(celVirtualKeyKey, cel.StringType),
 		cel.Variable(celInputTokensKey, cel.UintType),
 		cel.Variable(celCachedInputTokensKey, cel.UintType),
 		cel.Variable(celCacheCreationInputTokensKey, cel.UintType),
@@ -60,19 +62,21 @@ func NewProgram(expr string) (prog cel.Program, err error) {
 	}
 
 	// Sanity check by evaluating the expression with some dummy values.
-	_, err = EvaluateProgram(prog, "dummy", "dummy", "dummy", 0, 0, 0, 0, 0, 0)
+	_, err = EvaluateProgram(prog, "dummy", "dummy", "dummy", "", 0, 0, 0, 0, 0, 0)
 	if err != nil {
 		return nil, fmt.Errorf("failed to evaluate CEL expression: %w", err)
 	}
 	return prog, nil
 }
 
-// EvaluateProgram evaluates the given CEL program with the given variables.
-func EvaluateProgram(prog cel.Program, modelName, backend, routeName string, inputTokens, cachedInputTokens, cacheCreationInputTokens, outputTokens, totalTokens, reasoningTokens uint32) (uint64, error) {
+// EvaluateProgram evaluates the given CEL program with the given variables. The virtualKey is the name of the
+// VirtualKey of the request (format "namespace/name"), or empty if the request doesn't use one.
+func EvaluateProgram(prog cel.Program, modelName, backend, routeName, virtualKey string, inputTokens, cachedInputTokens, cacheCreationInputTokens, outputTokens, totalTokens, reasoningTokens uint32) (uint64, error) {
 	out, _, err := prog.Eval(map[string]any{
 		celModelNameKey:                modelName,
 		celBackendKey:                  backend,
 		celRouteNameKey:                routeName,
+		celVirtualKeyKey:               virtualKey,
 		celInputTokensKey:              inputTokens,
 		celCachedInputTokensKey:        cachedInputTokens,
 		celCacheCreationInputTokensKey: cacheCreationInputTokens,
@@ -0,0 +1,86 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package mcplocal provides the building blocks shared by the Streamable HTTP MCP servers that the MCP proxy runs
+// on the loopback interface, whose tools are served by sending HTTP requests through Envoy.
+package mcplocal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+// Server serves an MCP server over Streamable HTTP on a random loopback port.
+type Server struct {
+	logger     *slog.Logger
+	url        string
+	httpServer *http.Server
+}
+
+// Start starts serving the given MCP server. The sessions that haven't received any request for sessionIdleTimeout
+// are closed, and zero means that idle sessions are never closed.
+func Start(logger *slog.Logger, mcpServer *mcp.Server, sessionIdleTimeout time.Duration) (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	s := &Server{
+		logger: logger,
+		url:    fmt.Sprintf("http://%s/mcp", listener.Addr().String()),
+	}
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return mcpServer },
+		&mcp.StreamableHTTPOptions{SessionTimeout: sessionIdleTimeout})
+	s.httpServer = &http.Server{Handler: handler, ReadHeaderTimeout: 120 * time.Second}
+	go func() {
+		s.logger.Info("serving local MCP server", slog.String("url", s.url))
+		if serveErr := s.httpServer.Serve(listener); serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+			s.logger.Error("local MCP server error", slog.String("error", serveErr.Error()))
+		}
+	}()
+	return s, nil
+}
+
+// URL returns the Streamable HTTP endpoint of the server.
+func (s *Server) URL() string {
+	return s.url
+}
+
+// Close stops the server and closes all its sessions.
+func (s *Server) Close() error {
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.httpServer.Shutdown(shutdownCtx)
+}
+
+// IsForwardedHeader returns true if the given header of the MCP request is forwarded with the requests serving the
+// tools, such as the credentials of the user. The headers of the MCP transport and the internal headers of the AI
+// Gateway are not.
+func IsForwardedHeader(key string) bool {
+	key = strings.ToLower(key)
+	if strings.HasPrefix(key, internalapi.EnvoyAIGatewayHeaderPrefix) || strings.HasPrefix(key, "x-envoy-") {
+		return false
+	}
+	switch key {
+	case "accept", "accept-encoding", "content-type", "content-length", "connection", "transfer-encoding", "te",
+		"host", "mcp-session-id", "mcp-protocol-version", "last-event-id":
+		return false
+	}
+	return true
+}
+
+// ToolError returns a tool result reporting the given error to the model.
+func ToolError(message string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: message}}, IsError: true}
+}
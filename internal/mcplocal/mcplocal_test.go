@@ -0,0 +1,51 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcplocal
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+func TestServer(t *testing.T) {
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	mcpServer.AddTool(&mcp.Tool{Name: "fail", InputSchema: map[string]any{"type": "object"}},
+		func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return ToolError("failed"), nil
+		})
+	s, err := Start(slog.New(slog.DiscardHandler), mcpServer, 0)
+	require.NoError(t, err)
+	require.Regexp(t, `^http://127\.0\.0\.1:\d+/mcp$`, s.URL())
+
+	client := mcp.NewClient(&mcp.Implementation{Name: t.Name()}, nil)
+	session, err := client.Connect(t.Context(), &mcp.StreamableClientTransport{Endpoint: s.URL()}, nil)
+	require.NoError(t, err)
+	res, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "fail"})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	require.Equal(t, "failed", res.Content[0].(*mcp.TextContent).Text)
+	require.NoError(t, session.Close())
+
+	// Closing the server stops accepting new sessions.
+	require.NoError(t, s.Close())
+	_, err = client.Connect(t.Context(), &mcp.StreamableClientTransport{Endpoint: s.URL()}, nil)
+	require.Error(t, err)
+}
+
+func TestIsForwardedHeader(t *testing.T) {
+	require.True(t, IsForwardedHeader("Authorization"))
+	require.True(t, IsForwardedHeader("x-api-key"))
+	require.False(t, IsForwardedHeader("Mcp-Session-Id"))
+	require.False(t, IsForwardedHeader("Content-Length"))
+	require.False(t, IsForwardedHeader(internalapi.MCPBackendHeader))
+	require.False(t, IsForwardedHeader("x-envoy-original-path"))
+}
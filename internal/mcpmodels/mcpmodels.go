@@ -0,0 +1,378 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package mcpmodels exposes the models of an AIGatewayRoute as a Streamable HTTP MCP server on the loopback
+// interface. The tool calls and prompt requests are sent as OpenAI requests to the AIGatewayRoute through Envoy.
+package mcpmodels
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/mcplocal"
+	"github.com/envoyproxy/ai-gateway/internal/version"
+)
+
+const (
+	// askToolPrefix is the prefix of the names of the tools and prompts sending a chat completion request to a model.
+	askToolPrefix = "ask_"
+	// embedTextTool is the name of the tool sending an embeddings request to the embedding model.
+	embedTextTool = "embed_text"
+	// generateImageTool is the name of the tool sending an image generation request to the image model.
+	generateImageTool = "generate_image"
+)
+
+// Config is the configuration of the models exposed by a server.
+type Config struct {
+	// URL is the base URL of the Envoy listener the AIGatewayRoute is attached to.
+	URL string
+	// Host is the host of the requests sent to the AIGatewayRoute. Empty means the host of the URL.
+	Host string
+	// ChatModels are the models exposed as the ask_<model> tools and prompts.
+	ChatModels []string
+	// EmbeddingModel is the model used by the embed_text tool. Empty means the tool is not exposed.
+	EmbeddingModel string
+	// ImageModel is the model used by the generate_image tool. Empty means the tool is not exposed.
+	ImageModel string
+	// SessionIdleTimeout closes the MCP sessions that haven't received any request for this duration.
+	// Zero means that idle sessions are never closed.
+	SessionIdleTimeout time.Duration
+}
+
+// Server is a Streamable HTTP MCP server whose tools and prompts are served by the models of an AIGatewayRoute.
+type Server struct {
+	*mcplocal.Server
+	cfg    Config
+	logger *slog.Logger
+	client *http.Client
+}
+
+// Start starts a server with the given name and configuration, listening on a random loopback port.
+func Start(logger *slog.Logger, name string, cfg Config) (*Server, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if u, err := url.Parse(cfg.URL); err == nil && u.Scheme == "https" {
+		// The connections are made to the address of the listener, so the certificate is verified for the host of the
+		// requests with the certificate authorities of the system, which can be set with SSL_CERT_FILE.
+		serverName := u.Hostname()
+		if cfg.Host != "" {
+			serverName = (&url.URL{Host: cfg.Host}).Hostname()
+		}
+		transport.TLSClientConfig = &tls.Config{ServerName: serverName, MinVersion: tls.VersionTLS12}
+	}
+	s := &Server{
+		cfg:    cfg,
+		logger: logger.With(slog.String("models_server", name)),
+		client: &http.Client{Transport: transport},
+	}
+	local, err := mcplocal.Start(s.logger, s.newMCPServer(), cfg.SessionIdleTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start the %s models server: %w", name, err)
+	}
+	s.Server = local
+	return s, nil
+}
+
+// newMCPServer creates the MCP server with the tools and prompts of the configured models.
+func (s *Server) newMCPServer() *mcp.Server {
+	server := mcp.NewServer(&mcp.Implementation{Name: "envoy-ai-gateway-models", Version: version.Parse()}, nil)
+	names := make(map[string]struct{}, len(s.cfg.ChatModels))
+	for _, model := range s.cfg.ChatModels {
+		name := ToolName(model)
+		if _, ok := names[name]; ok {
+			s.logger.Warn("skipping the model whose tool name is already used", slog.String("model", model), slog.String("tool", name))
+			continue
+		}
+		names[name] = struct{}{}
+		server.AddTool(&mcp.Tool{
+			Name:        name,
+			Description: fmt.Sprintf("Ask the %s model and return its answer.", model),
+			InputSchema: askInputSchema,
+		}, s.askToolHandler(model))
+		server.AddPrompt(&mcp.Prompt{
+			Name:        name,
+			Description: fmt.Sprintf("Ask the %s model and add its answer to the conversation.", model),
+			Arguments: []*mcp.PromptArgument{
+				{Name: "prompt", Description: "The prompt sent to the model.", Required: true},
+				{Name: "system", Description: "The system prompt sent to the model."},
+			},
+		}, s.askPromptHandler(model))
+	}
+	if s.cfg.EmbeddingModel != "" {
+		server.AddTool(&mcp.Tool{
+			Name:        embedTextTool,
+			Description: fmt.Sprintf("Compute the embeddings of the given texts with the %s model.", s.cfg.EmbeddingModel),
+			InputSchema: embedTextInputSchema,
+		}, s.embedTextHandler)
+	}
+	if s.cfg.ImageModel != "" {
+		server.AddTool(&mcp.Tool{
+			Name:        generateImageTool,
+			Description: fmt.Sprintf("Generate images from the given prompt with the %s model.", s.cfg.ImageModel),
+			InputSchema: generateImageInputSchema,
+		}, s.generateImageHandler)
+	}
+	return server
+}
+
+// ToolName returns the name of the ask_<model> tool and prompt of the given model. The characters that are not
+// allowed in the tool names are replaced by underscores.
+func ToolName(model string) string {
+	return askToolPrefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+			return r
+		}
+		return '_'
+	}, model)
+}
+
+var (
+	askInputSchema = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"prompt":      map[string]any{"type": "string", "description": "The prompt sent to the model."},
+			"system":      map[string]any{"type": "string", "description": "The system prompt sent to the model."},
+			"max_tokens":  map[string]any{"type": "integer", "minimum": 1, "description": "The maximum number of tokens of the answer."},
+			"temperature": map[string]any{"type": "number", "minimum": 0, "maximum": 2, "description": "The sampling temperature."},
+		},
+		"required": []string{"prompt"},
+	}
+	embedTextInputSchema = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"texts": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "minItems": 1, "description": "The texts to embed."},
+		},
+		"required": []string{"texts"},
+	}
+	generateImageInputSchema = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"prompt": map[string]any{"type": "string", "description": "The description of the images to generate."},
+			"size":   map[string]any{"type": "string", "description": "The size of the images, such as 1024x1024."},
+			"n":      map[string]any{"type": "integer", "minimum": 1, "maximum": 10, "description": "The number of images to generate."},
+		},
+		"required": []string{"prompt"},
+	}
+)
+
+type (
+	// askArguments are the arguments of the ask_<model> tools.
+	askArguments struct {
+		Prompt      string   `json:"prompt"`
+		System      string   `json:"system,omitempty"`
+		MaxTokens   *int64   `json:"max_tokens,omitempty"`
+		Temperature *float64 `json:"temperature,omitempty"`
+	}
+	// embedTextArguments are the arguments of the embed_text tool.
+	embedTextArguments struct {
+		Texts []string `json:"texts"`
+	}
+	// generateImageArguments are the arguments of the generate_image tool.
+	generateImageArguments struct {
+		Prompt string `json:"prompt"`
+		Size   string `json:"size,omitempty"`
+		N      int    `json:"n,omitempty"`
+	}
+	// chatMessage is a message of the chat completion requests sent to the models.
+	chatMessage struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+)
+
+// askToolHandler returns the handler of the ask_<model> tool of the given model.
+func (s *Server) askToolHandler(model string) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args askArguments
+		if err := unmarshalArguments(req.Params.Arguments, &args); err != nil || args.Prompt == "" {
+			return mcplocal.ToolError("the prompt argument is required"), nil
+		}
+		answer, err := s.ask(ctx, req.Extra, model, &args)
+		if err != nil {
+			return mcplocal.ToolError(err.Error()), nil
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: answer}}}, nil
+	}
+}
+
+// askPromptHandler returns the handler of the ask_<model> prompt of the given model.
+func (s *Server) askPromptHandler(model string) mcp.PromptHandler {
+	return func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := askArguments{Prompt: req.Params.Arguments["prompt"], System: req.Params.Arguments["system"]}
+		if args.Prompt == "" {
+			return nil, errors.New("the prompt argument is required")
+		}
+		answer, err := s.ask(ctx, req.Extra, model, &args)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.GetPromptResult{
+			Description: fmt.Sprintf("Answer of the %s model", model),
+			Messages: []*mcp.PromptMessage{
+				{Role: "user", Content: &mcp.TextContent{Text: args.Prompt}},
+				{Role: "assistant", Content: &mcp.TextContent{Text: answer}},
+			},
+		}, nil
+	}
+}
+
+// ask sends a chat completion request with the given arguments to the given model and returns its answer.
+func (s *Server) ask(ctx context.Context, extra *mcp.RequestExtra, model string, args *askArguments) (string, error) {
+	messages := make([]chatMessage, 0, 2)
+	if args.System != "" {
+		messages = append(messages, chatMessage{Role: openai.ChatMessageRoleSystem, Content: args.System})
+	}
+	messages = append(messages, chatMessage{Role: openai.ChatMessageRoleUser, Content: args.Prompt})
+	body := map[string]any{"model": model, "messages": messages}
+	if args.MaxTokens != nil {
+		body["max_completion_tokens"] = *args.MaxTokens
+	}
+	if args.Temperature != nil {
+		body["temperature"] = *args.Temperature
+	}
+
+	var resp openai.ChatCompletionResponse
+	if err := s.post(ctx, extra, "/v1/chat/completions", model, body, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == nil {
+		return "", fmt.Errorf("the %s model returned no answer", model)
+	}
+	return *resp.Choices[0].Message.Content, nil
+}
+
+// embedTextHandler is the handler of the embed_text tool.
+func (s *Server) embedTextHandler(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var args embedTextArguments
+	if err := unmarshalArguments(req.Params.Arguments, &args); err != nil || len(args.Texts) == 0 {
+		return mcplocal.ToolError("the texts argument is required"), nil
+	}
+	model := s.cfg.EmbeddingModel
+	var resp openai.EmbeddingResponse
+	if err := s.post(ctx, req.Extra, "/v1/embeddings", model, map[string]any{"model": model, "input": args.Texts}, &resp); err != nil {
+		return mcplocal.ToolError(err.Error()), nil
+	}
+	embeddings := make([]any, len(args.Texts))
+	for _, e := range resp.Data {
+		if e.Index >= 0 && e.Index < len(embeddings) {
+			embeddings[e.Index] = e.Embedding.Value
+		}
+	}
+	structured := map[string]any{"model": cmp.Or(resp.Model, model), "embeddings": embeddings}
+	text, _ := json.Marshal(structured)
+	return &mcp.CallToolResult{
+		Content:           []mcp.Content{&mcp.TextContent{Text: string(text)}},
+		StructuredContent: structured,
+	}, nil
+}
+
+// generateImageHandler is the handler of the generate_image tool.
+func (s *Server) generateImageHandler(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var args generateImageArguments
+	if err := unmarshalArguments(req.Params.Arguments, &args); err != nil || args.Prompt == "" {
+		return mcplocal.ToolError("the prompt argument is required"), nil
+	}
+	model := s.cfg.ImageModel
+	body := map[string]any{"model": model, "prompt": args.Prompt}
+	if args.Size != "" {
+		body["size"] = args.Size
+	}
+	if args.N > 0 {
+		body["n"] = args.N
+	}
+	var resp openai.ImageGenerationResponse
+	if err := s.post(ctx, req.Extra, "/v1/images/generations", model, body, &resp); err != nil {
+		return mcplocal.ToolError(err.Error()), nil
+	}
+
+	mimeType := "image/" + cmp.Or(resp.OutputFormat, "png")
+	result := &mcp.CallToolResult{}
+	for i, image := range resp.Data {
+		switch {
+		case image.B64JSON != "":
+			data, err := base64.StdEncoding.DecodeString(image.B64JSON)
+			if err != nil {
+				return mcplocal.ToolError(fmt.Sprintf("the %s model returned an invalid image: %v", model, err)), nil
+			}
+			result.Content = append(result.Content, &mcp.ImageContent{Data: data, MIMEType: mimeType})
+		case image.URL != "":
+			result.Content = append(result.Content, &mcp.ResourceLink{URI: image.URL, Name: fmt.Sprintf("image-%d", i+1)})
+		}
+		if image.RevisedPrompt != "" {
+			result.Content = append(result.Content, &mcp.TextContent{Text: "Revised prompt: " + image.RevisedPrompt})
+		}
+	}
+	if len(result.Content) == 0 {
+		return mcplocal.ToolError(fmt.Sprintf("the %s model returned no image", model)), nil
+	}
+	return result, nil
+}
+
+// post sends the given OpenAI request body to the given path of the AIGatewayRoute, and decodes the successful
+// response into out. The headers of the MCP request are forwarded, so that the request is authenticated and rate
+// limited as the MCP client.
+func (s *Server) post(ctx context.Context, extra *mcp.RequestExtra, path, model string, body any, out any) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode the request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(s.cfg.URL, "/")+path, bytes.NewReader(encoded))
+	if err != nil {
+		return fmt.Errorf("failed to create the request: %w", err)
+	}
+	if extra != nil {
+		for k, v := range extra.Header {
+			if mcplocal.IsForwardedHeader(k) {
+				req.Header[k] = v
+			}
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(internalapi.ModelNameHeaderKeyDefault, model)
+	if s.cfg.Host != "" {
+		req.Host = s.cfg.Host
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send the request to the %s model: %w", model, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read the response of the %s model: %w", model, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("the %s model returned status code %d: %s", model, resp.StatusCode, respBody)
+	}
+	if err = json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to decode the response of the %s model: %w", model, err)
+	}
+	return nil
+}
+
+// unmarshalArguments decodes the given arguments of a tool call.
+func unmarshalArguments(raw []byte, out any) error {
+	if len(raw) == 0 {
+		return errors.New("missing arguments")
+	}
+	return json.Unmarshal(raw, out)
+}
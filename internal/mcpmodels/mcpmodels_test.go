@@ -0,0 +1,237 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpmodels
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+// fakeRoute records the requests sent to the AIGatewayRoute and answers them like an OpenAI backend.
+type fakeRoute struct {
+	mu       sync.Mutex
+	requests []*recordedRequest
+}
+
+type recordedRequest struct {
+	path, host, model, auth, session string
+	body                             map[string]any
+}
+
+func (f *fakeRoute) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	raw, _ := io.ReadAll(r.Body)
+	req := &recordedRequest{
+		path: r.URL.Path, host: r.Host, model: r.Header.Get(internalapi.ModelNameHeaderKeyDefault),
+		auth: r.Header.Get("Authorization"), session: r.Header.Get("Mcp-Session-Id"),
+	}
+	_ = json.Unmarshal(raw, &req.body)
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case req.model == "broken":
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"message":"rate limited"}}`))
+	case r.URL.Path == "/v1/chat/completions":
+		_, _ = w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"answer of ` + req.model + `"}}]}`))
+	case r.URL.Path == "/v1/embeddings":
+		_, _ = w.Write([]byte(`{"object":"list","model":"embedder","data":[{"object":"embedding","index":1,"embedding":[0.3]},{"object":"embedding","index":0,"embedding":[0.1,0.2]}]}`))
+	case r.URL.Path == "/v1/images/generations":
+		_, _ = w.Write([]byte(`{"created":1,"data":[{"b64_json":"` + base64.StdEncoding.EncodeToString([]byte("png")) + `"},{"url":"https://example.com/image.png","revised_prompt":"a cat"}]}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeRoute) last() *recordedRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[len(f.requests)-1]
+}
+
+func TestServer(t *testing.T) {
+	route := &fakeRoute{}
+	routeServer := httptest.NewServer(route)
+	t.Cleanup(routeServer.Close)
+
+	s, err := Start(slog.New(slog.DiscardHandler), "test", Config{
+		URL:            routeServer.URL,
+		Host:           "ai.example.com",
+		ChatModels:     []string{"gpt-4o", "meta/llama3:8b", "meta/llama3.8b", "broken"},
+		EmbeddingModel: "embedder",
+		ImageModel:     "painter",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	require.Regexp(t, `^http://127\.0\.0\.1:\d+/mcp$`, s.URL())
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil)
+	session, err := client.Connect(t.Context(), &mcp.StreamableClientTransport{
+		Endpoint: s.URL(),
+		HTTPClient: &http.Client{Transport: headerTransport{
+			"Authorization": "Bearer user", internalapi.MCPBackendHeader: "backend",
+		}},
+	}, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+
+	tools, err := session.ListTools(t.Context(), nil)
+	require.NoError(t, err)
+	var toolNames []string
+	for _, tool := range tools.Tools {
+		toolNames = append(toolNames, tool.Name)
+	}
+	require.ElementsMatch(t, []string{"ask_gpt-4o", "ask_meta_llama3_8b", "ask_meta_llama3.8b", "ask_broken", "embed_text", "generate_image"}, toolNames)
+	prompts, err := session.ListPrompts(t.Context(), nil)
+	require.NoError(t, err)
+	require.Len(t, prompts.Prompts, 4)
+
+	t.Run("ask", func(t *testing.T) {
+		res, err := session.CallTool(t.Context(), &mcp.CallToolParams{
+			Name:      "ask_meta_llama3_8b",
+			Arguments: map[string]any{"prompt": "hello", "system": "be brief", "max_tokens": 10},
+		})
+		require.NoError(t, err)
+		require.False(t, res.IsError)
+		require.Equal(t, "answer of meta/llama3:8b", res.Content[0].(*mcp.TextContent).Text)
+
+		req := route.last()
+		require.Equal(t, "/v1/chat/completions", req.path)
+		require.Equal(t, "ai.example.com", req.host)
+		require.Equal(t, "meta/llama3:8b", req.model)
+		require.Equal(t, "Bearer user", req.auth)
+		require.Empty(t, req.session)
+		require.Equal(t, map[string]any{
+			"model":                 "meta/llama3:8b",
+			"max_completion_tokens": float64(10),
+			"messages": []any{
+				map[string]any{"role": "system", "content": "be brief"},
+				map[string]any{"role": "user", "content": "hello"},
+			},
+		}, req.body)
+	})
+
+	t.Run("ask errors", func(t *testing.T) {
+		res, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "ask_broken", Arguments: map[string]any{"prompt": "hello"}})
+		require.NoError(t, err)
+		require.True(t, res.IsError)
+		require.Contains(t, res.Content[0].(*mcp.TextContent).Text, "the broken model returned status code 429")
+
+		res, err = session.CallTool(t.Context(), &mcp.CallToolParams{Name: "ask_gpt-4o", Arguments: map[string]any{}})
+		require.NoError(t, err)
+		require.True(t, res.IsError)
+	})
+
+	t.Run("prompt", func(t *testing.T) {
+		res, err := session.GetPrompt(t.Context(), &mcp.GetPromptParams{Name: "ask_gpt-4o", Arguments: map[string]string{"prompt": "hello"}})
+		require.NoError(t, err)
+		require.Len(t, res.Messages, 2)
+		require.Equal(t, mcp.Role("user"), res.Messages[0].Role)
+		require.Equal(t, "hello", res.Messages[0].Content.(*mcp.TextContent).Text)
+		require.Equal(t, mcp.Role("assistant"), res.Messages[1].Role)
+		require.Equal(t, "answer of gpt-4o", res.Messages[1].Content.(*mcp.TextContent).Text)
+
+		_, err = session.GetPrompt(t.Context(), &mcp.GetPromptParams{Name: "ask_broken", Arguments: map[string]string{"prompt": "hello"}})
+		require.ErrorContains(t, err, "returned status code 429")
+	})
+
+	t.Run("embed_text", func(t *testing.T) {
+		res, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "embed_text", Arguments: map[string]any{"texts": []string{"a", "b"}}})
+		require.NoError(t, err)
+		require.False(t, res.IsError)
+		require.Equal(t, map[string]any{
+			"model":      "embedder",
+			"embeddings": []any{[]any{0.1, 0.2}, []any{0.3}},
+		}, res.StructuredContent)
+
+		req := route.last()
+		require.Equal(t, "/v1/embeddings", req.path)
+		require.Equal(t, "embedder", req.model)
+		require.Equal(t, []any{"a", "b"}, req.body["input"])
+	})
+
+	t.Run("generate_image", func(t *testing.T) {
+		res, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "generate_image", Arguments: map[string]any{"prompt": "a cat", "n": 2}})
+		require.NoError(t, err)
+		require.False(t, res.IsError)
+		require.Len(t, res.Content, 3)
+		require.Equal(t, []byte("png"), res.Content[0].(*mcp.ImageContent).Data)
+		require.Equal(t, "image/png", res.Content[0].(*mcp.ImageContent).MIMEType)
+		require.Equal(t, "https://example.com/image.png", res.Content[1].(*mcp.ResourceLink).URI)
+		require.Equal(t, "Revised prompt: a cat", res.Content[2].(*mcp.TextContent).Text)
+
+		req := route.last()
+		require.Equal(t, "/v1/images/generations", req.path)
+		require.Equal(t, "painter", req.model)
+		require.Equal(t, map[string]any{"model": "painter", "prompt": "a cat", "n": float64(2)}, req.body)
+	})
+}
+
+func TestServer_TLS(t *testing.T) {
+	routeServer := httptest.NewTLSServer(&fakeRoute{})
+	t.Cleanup(routeServer.Close)
+	roots := x509.NewCertPool()
+	roots.AddCert(routeServer.Certificate())
+
+	ask := func(t *testing.T, host string, trusted bool) *mcp.CallToolResult {
+		s, err := Start(slog.New(slog.DiscardHandler), "test", Config{URL: routeServer.URL, Host: host, ChatModels: []string{"gpt-4o"}})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = s.Close() })
+		if trusted {
+			s.client.Transport.(*http.Transport).TLSClientConfig.RootCAs = roots
+		}
+		client := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil)
+		session, err := client.Connect(t.Context(), &mcp.StreamableClientTransport{Endpoint: s.URL()}, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = session.Close() })
+		res, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "ask_gpt-4o", Arguments: map[string]any{"prompt": "hello"}})
+		require.NoError(t, err)
+		return res
+	}
+
+	// The certificate of the listener is verified for the host of the requests.
+	res := ask(t, "example.com", true)
+	require.False(t, res.IsError)
+	require.Equal(t, "answer of gpt-4o", res.Content[0].(*mcp.TextContent).Text)
+	res = ask(t, "ai.example.org", true)
+	require.True(t, res.IsError)
+	require.Contains(t, res.Content[0].(*mcp.TextContent).Text, "certificate is valid for")
+	res = ask(t, "example.com", false)
+	require.True(t, res.IsError)
+	require.Contains(t, res.Content[0].(*mcp.TextContent).Text, "certificate signed by unknown authority")
+}
+
+func TestToolName(t *testing.T) {
+	require.Equal(t, "ask_gpt-4o", ToolName("gpt-4o"))
+	require.Equal(t, "ask_claude-3.5-sonnet", ToolName("claude-3.5-sonnet"))
+	require.Equal(t, "ask_meta_llama3_8b", ToolName("meta/llama3:8b"))
+}
+
+// headerTransport sets the given headers on the requests.
+type headerTransport map[string]string
+
+// RoundTrip implements [http.RoundTripper].
+func (h headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range h {
+		req.Header.Set(k, v)
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
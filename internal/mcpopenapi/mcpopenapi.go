@@ -0,0 +1,217 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package mcpopenapi exposes the operations of a REST API described by an OpenAPI document as a Streamable HTTP MCP
+// server on the loopback interface. The tool calls are sent as HTTP requests to the backend through Envoy.
+package mcpopenapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/mcplocal"
+	"github.com/envoyproxy/ai-gateway/internal/version"
+)
+
+// maxResponseSize is the maximum size of the responses of the backend returned to the MCP clients.
+const maxResponseSize = 4 << 20
+
+// Config is the configuration of the operations exposed by a server.
+type Config struct {
+	// URL is the base URL of the backend listener of Envoy, e.g. "http://127.0.0.1:10088".
+	URL string
+	// Route is the name of the MCP route of the backend, used to route the requests in Envoy.
+	Route filterapi.MCPRouteName
+	// Backend is the name of the backend, used to route the requests in Envoy.
+	Backend filterapi.MCPBackendName
+	// BasePath is the path prefixed to the paths of the operations.
+	BasePath string
+	// Operations are the operations exposed as tools.
+	Operations []filterapi.MCPOpenAPIOperation
+	// SessionIdleTimeout closes the MCP sessions that haven't received any request for this duration.
+	// Zero means that idle sessions are never closed.
+	SessionIdleTimeout time.Duration
+}
+
+// Server is a Streamable HTTP MCP server whose tools are served by the operations of a REST API.
+type Server struct {
+	*mcplocal.Server
+	cfg    Config
+	logger *slog.Logger
+	client *http.Client
+}
+
+// Start starts a server with the given name and configuration, listening on a random loopback port.
+func Start(logger *slog.Logger, name string, cfg Config) (*Server, error) {
+	s := &Server{
+		cfg:    cfg,
+		logger: logger.With(slog.String("openapi_server", name)),
+		client: &http.Client{},
+	}
+	local, err := mcplocal.Start(s.logger, s.newMCPServer(), cfg.SessionIdleTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start the %s OpenAPI server: %w", name, err)
+	}
+	s.Server = local
+	return s, nil
+}
+
+// newMCPServer creates the MCP server with the tools of the configured operations.
+func (s *Server) newMCPServer() *mcp.Server {
+	server := mcp.NewServer(&mcp.Implementation{Name: "envoy-ai-gateway-openapi", Version: version.Parse()}, nil)
+	for i := range s.cfg.Operations {
+		op := &s.cfg.Operations[i]
+		inputSchema := op.InputSchema
+		if inputSchema == nil {
+			inputSchema = map[string]any{"type": "object"}
+		}
+		server.AddTool(&mcp.Tool{
+			Name:        op.Name,
+			Description: op.Description,
+			InputSchema: inputSchema,
+			Annotations: &mcp.ToolAnnotations{ReadOnlyHint: op.Method == http.MethodGet || op.Method == http.MethodHead},
+		}, s.toolHandler(op))
+	}
+	return server
+}
+
+// toolHandler returns the handler of the tool of the given operation.
+func (s *Server) toolHandler(op *filterapi.MCPOpenAPIOperation) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := make(map[string]any)
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return mcplocal.ToolError(fmt.Sprintf("invalid arguments: %v", err)), nil
+			}
+		}
+		httpReq, err := s.newRequest(ctx, op, args)
+		if err != nil {
+			return mcplocal.ToolError(err.Error()), nil
+		}
+		if req.Extra != nil {
+			for k, v := range req.Extra.Header {
+				if mcplocal.IsForwardedHeader(k) && httpReq.Header.Get(k) == "" {
+					httpReq.Header[k] = v
+				}
+			}
+		}
+		httpReq.Header.Set(internalapi.MCPBackendHeader, s.cfg.Backend)
+		httpReq.Header.Set(internalapi.MCPRouteHeader, s.cfg.Route)
+
+		resp, err := s.client.Do(httpReq)
+		if err != nil {
+			return mcplocal.ToolError(fmt.Sprintf("failed to send the %s request: %v", op.Name, err)), nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+		if err != nil {
+			return mcplocal.ToolError(fmt.Sprintf("failed to read the %s response: %v", op.Name, err)), nil
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return mcplocal.ToolError(fmt.Sprintf("%s returned status code %d: %s", op.Name, resp.StatusCode, body)), nil
+		}
+		return toolResult(resp.StatusCode, body), nil
+	}
+}
+
+// newRequest builds the HTTP request of the given operation from the given tool arguments.
+func (s *Server) newRequest(ctx context.Context, op *filterapi.MCPOpenAPIOperation, args map[string]any) (*http.Request, error) {
+	path := op.Path
+	query := url.Values{}
+	header := http.Header{}
+	for _, param := range op.Parameters {
+		v, ok := args[param.Name]
+		if !ok || v == nil {
+			if param.In == "path" {
+				return nil, fmt.Errorf("the %s argument is required", param.Name)
+			}
+			continue
+		}
+		switch param.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+param.Name+"}", url.PathEscape(parameterValue(v)))
+		case "query":
+			if values, isArray := v.([]any); isArray {
+				for _, item := range values {
+					query.Add(param.Name, parameterValue(item))
+				}
+			} else {
+				query.Set(param.Name, parameterValue(v))
+			}
+		case "header":
+			header.Set(param.Name, parameterValue(v))
+		}
+	}
+
+	var body io.Reader
+	if op.BodyProperty != "" {
+		if v, ok := args[op.BodyProperty]; ok {
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode the %s argument: %w", op.BodyProperty, err)
+			}
+			body = bytes.NewReader(encoded)
+			header.Set("Content-Type", "application/json")
+		}
+	}
+
+	u := strings.TrimSuffix(s.cfg.URL, "/") + s.cfg.BasePath + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, op.Method, u, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the %s request: %w", op.Name, err)
+	}
+	req.Header = header
+	req.Header.Set("Accept", "application/json, */*;q=0.8")
+	return req, nil
+}
+
+// parameterValue returns the string representation of the given parameter value. The arrays are serialized as
+// comma-separated values, and the objects as JSON.
+func parameterValue(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case []any:
+		values := make([]string, len(t))
+		for i, item := range t {
+			values[i] = parameterValue(item)
+		}
+		return strings.Join(values, ",")
+	case map[string]any:
+		encoded, _ := json.Marshal(t)
+		return string(encoded)
+	}
+	return fmt.Sprint(v)
+}
+
+// toolResult returns the tool result of the given successful response body. The JSON objects are also returned as
+// structured content.
+func toolResult(status int, body []byte) *mcp.CallToolResult {
+	text := string(body)
+	if len(bytes.TrimSpace(body)) == 0 {
+		text = fmt.Sprintf("The request succeeded with status code %d.", status)
+	}
+	result := &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}
+	var structured map[string]any
+	if err := json.Unmarshal(body, &structured); err == nil && structured != nil {
+		result.StructuredContent = structured
+	}
+	return result
+}
@@ -0,0 +1,180 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpopenapi
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+// fakeListener records the requests sent to the backend listener and answers them like a REST API.
+type fakeListener struct {
+	mu       sync.Mutex
+	requests []*recordedRequest
+}
+
+type recordedRequest struct {
+	method, uri, backend, route, auth, trace, contentType, session string
+	body                                                           string
+}
+
+func (f *fakeListener) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	f.requests = append(f.requests, &recordedRequest{
+		method: r.Method, uri: r.URL.RequestURI(), backend: r.Header.Get(internalapi.MCPBackendHeader),
+		route: r.Header.Get(internalapi.MCPRouteHeader), auth: r.Header.Get("Authorization"), trace: r.Header.Get("X-Trace"),
+		contentType: r.Header.Get("Content-Type"), session: r.Header.Get("Mcp-Session-Id"), body: string(body),
+	})
+	f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/pets":
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"name":"rex"}]`))
+	case r.Method == http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"rex"}`))
+	case r.Method == http.MethodPost:
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`invalid pet`))
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (f *fakeListener) last() *recordedRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[len(f.requests)-1]
+}
+
+func TestServer(t *testing.T) {
+	listener := &fakeListener{}
+	listenerServer := httptest.NewServer(listener)
+	t.Cleanup(listenerServer.Close)
+
+	backend, err := Parse([]byte(petStore))
+	require.NoError(t, err)
+	s, err := Start(slog.New(slog.DiscardHandler), "test", Config{
+		URL:        listenerServer.URL,
+		Route:      "ns/route",
+		Backend:    "pets",
+		BasePath:   backend.BasePath,
+		Operations: backend.Operations,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	require.Regexp(t, `^http://127\.0\.0\.1:\d+/mcp$`, s.URL())
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil)
+	session, err := client.Connect(t.Context(), &mcp.StreamableClientTransport{
+		Endpoint:   s.URL(),
+		HTTPClient: &http.Client{Transport: headerTransport{"Authorization": "Bearer user", internalapi.MCPBackendHeader: "other"}},
+	}, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+
+	tools, err := session.ListTools(t.Context(), nil)
+	require.NoError(t, err)
+	var toolNames []string
+	for _, tool := range tools.Tools {
+		toolNames = append(toolNames, tool.Name)
+	}
+	require.Equal(t, []string{"create_pet", "get_pets_petId", "listPets", "listPets_2"}, toolNames)
+
+	t.Run("query parameters", func(t *testing.T) {
+		res, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "listPets", Arguments: map[string]any{"limit": 10}})
+		require.NoError(t, err)
+		require.False(t, res.IsError)
+		require.Equal(t, `[{"name":"rex"}]`, res.Content[0].(*mcp.TextContent).Text)
+		require.Nil(t, res.StructuredContent)
+
+		req := listener.last()
+		require.Equal(t, http.MethodGet, req.method)
+		require.Equal(t, "/v1/pets?limit=10", req.uri)
+		require.Equal(t, "pets", req.backend)
+		require.Equal(t, "ns/route", req.route)
+		require.Equal(t, "Bearer user", req.auth)
+		require.Empty(t, req.session)
+	})
+
+	t.Run("path and header parameters", func(t *testing.T) {
+		res, err := session.CallTool(t.Context(), &mcp.CallToolParams{
+			Name:      "get_pets_petId",
+			Arguments: map[string]any{"petId": "a/b", "X-Trace": "abc"},
+		})
+		require.NoError(t, err)
+		require.False(t, res.IsError)
+		require.Equal(t, map[string]any{"name": "rex"}, res.StructuredContent)
+
+		req := listener.last()
+		require.Equal(t, "/v1/pets/a%2Fb", req.uri)
+		require.Equal(t, "abc", req.trace)
+	})
+
+	t.Run("body", func(t *testing.T) {
+		res, err := session.CallTool(t.Context(), &mcp.CallToolParams{
+			Name:      "create_pet",
+			Arguments: map[string]any{"body": map[string]any{"name": "rex"}},
+		})
+		require.NoError(t, err)
+		require.True(t, res.IsError)
+		require.Equal(t, "create_pet returned status code 400: invalid pet", res.Content[0].(*mcp.TextContent).Text)
+
+		req := listener.last()
+		require.Equal(t, http.MethodPost, req.method)
+		require.Equal(t, "application/json", req.contentType)
+		require.JSONEq(t, `{"name":"rex"}`, req.body)
+	})
+
+	t.Run("empty response", func(t *testing.T) {
+		res, err := session.CallTool(t.Context(), &mcp.CallToolParams{
+			Name:      "listPets_2",
+			Arguments: map[string]any{"petId": "1", "body": []any{"a", "b"}},
+		})
+		require.NoError(t, err)
+		require.False(t, res.IsError)
+		require.Equal(t, "The request succeeded with status code 204.", res.Content[0].(*mcp.TextContent).Text)
+		require.Equal(t, "/v1/pets/1?body=a&body=b", listener.last().uri)
+	})
+
+	t.Run("missing path parameter", func(t *testing.T) {
+		res, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "listPets_2", Arguments: map[string]any{}})
+		require.NoError(t, err)
+		require.True(t, res.IsError)
+		require.Equal(t, "the petId argument is required", res.Content[0].(*mcp.TextContent).Text)
+	})
+}
+
+func TestParameterValue(t *testing.T) {
+	require.Equal(t, "a", parameterValue("a"))
+	require.Equal(t, "1.5", parameterValue(1.5))
+	require.Equal(t, "true", parameterValue(true))
+	require.Equal(t, "a,1", parameterValue([]any{"a", 1}))
+	require.JSONEq(t, `{"a":1}`, parameterValue(map[string]any{"a": 1}))
+}
+
+// headerTransport sets the given headers on the requests.
+type headerTransport map[string]string
+
+// RoundTrip implements [http.RoundTripper].
+func (h headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range h {
+		req.Header.Set(k, v)
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
@@ -0,0 +1,363 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpopenapi
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+const (
+	// bodyProperty is the property of the arguments of the tools holding the JSON request body.
+	bodyProperty = "body"
+	// fallbackBodyProperty is used instead of bodyProperty when an operation has a parameter with the same name.
+	fallbackBodyProperty = "requestBody"
+	// maxToolNameLength is the maximum length of the names of the tools.
+	maxToolNameLength = 64
+	// maxRefDepth is the maximum depth of the nested references resolved in the schemas.
+	maxRefDepth = 32
+)
+
+// methods are the HTTP methods of the operations, in the order they are exposed.
+var methods = []string{"get", "put", "post", "delete", "patch", "head", "options"}
+
+// serverVariable matches the variables of the server URLs, such as {version}.
+var serverVariable = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Parse parses the given OpenAPI 3 document, in JSON or YAML, into the configuration of its operations exposed as
+// MCP tools.
+//
+// The input schema of each tool has a property for each path, query and header parameter of the operation, and a
+// "body" property for its JSON request body. The operations requiring a request body that is not JSON are skipped.
+func Parse(doc []byte) (*filterapi.MCPOpenAPIBackend, error) {
+	raw, err := yaml.YAMLToJSON(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the OpenAPI document: %w", err)
+	}
+	var root map[string]any
+	if err = json.Unmarshal(raw, &root); err != nil || root == nil {
+		return nil, errors.New("the OpenAPI document is not an object")
+	}
+	if version, _ := root["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q: only OpenAPI 3 documents are supported", version)
+	}
+
+	basePath, err := serverBasePath(root)
+	if err != nil {
+		return nil, err
+	}
+	r := &resolver{root: root}
+	backend := &filterapi.MCPOpenAPIBackend{BasePath: basePath}
+	names := make(map[string]struct{})
+	paths, _ := root["paths"].(map[string]any)
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		item, _ := r.resolve(paths[path]).(map[string]any)
+		for _, method := range methods {
+			op, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			operation, ok := r.operation(method, path, item, op)
+			if !ok {
+				continue
+			}
+			operation.Name = uniqueName(names, operation.Name)
+			backend.Operations = append(backend.Operations, operation)
+		}
+	}
+	if len(backend.Operations) == 0 {
+		return nil, errors.New("the OpenAPI document has no supported operations")
+	}
+	return backend, nil
+}
+
+// serverBasePath returns the path of the first server of the given document, with its variables replaced by their
+// default values.
+func serverBasePath(root map[string]any) (string, error) {
+	servers, _ := root["servers"].([]any)
+	if len(servers) == 0 {
+		return "", nil
+	}
+	server, _ := servers[0].(map[string]any)
+	rawURL, _ := server["url"].(string)
+	variables, _ := server["variables"].(map[string]any)
+	rawURL = serverVariable.ReplaceAllStringFunc(rawURL, func(v string) string {
+		variable, _ := variables[strings.Trim(v, "{}")].(map[string]any)
+		def, _ := variable["default"].(string)
+		return def
+	})
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid server URL %q: %w", rawURL, err)
+	}
+	return strings.TrimSuffix(u.Path, "/"), nil
+}
+
+// operation converts the given operation of the given path item. It returns false if the operation is not supported.
+func (r *resolver) operation(method, path string, item, op map[string]any) (filterapi.MCPOpenAPIOperation, bool) {
+	operation := filterapi.MCPOpenAPIOperation{
+		Name:        toolName(method, path, op),
+		Description: description(method, path, op),
+		Method:      strings.ToUpper(method),
+		Path:        path,
+	}
+	properties := make(map[string]any)
+	var required []string
+	for _, param := range r.parameters(item, op) {
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		if _, ok := properties[name]; ok || name == "" {
+			continue
+		}
+		switch in {
+		case "path", "query":
+		case "header":
+			switch strings.ToLower(name) {
+			case "accept", "content-type", "authorization":
+				// These are set by the MCP proxy or injected by the security policy of the backend.
+				continue
+			}
+		default:
+			continue
+		}
+		schema, _ := r.resolveSchema(param["schema"], 0).(map[string]any)
+		if schema == nil {
+			schema = map[string]any{}
+		}
+		if d, ok := param["description"].(string); ok && d != "" {
+			if _, ok = schema["description"]; !ok {
+				schema = maps.Clone(schema)
+				schema["description"] = d
+			}
+		}
+		properties[name] = schema
+		operation.Parameters = append(operation.Parameters, filterapi.MCPOpenAPIParameter{Name: name, In: in})
+		if req, _ := param["required"].(bool); req || in == "path" {
+			required = append(required, name)
+		}
+	}
+
+	if body, ok := r.resolve(op["requestBody"]).(map[string]any); ok {
+		bodyRequired, _ := body["required"].(bool)
+		content, _ := body["content"].(map[string]any)
+		schema, isJSON := r.jsonBodySchema(content)
+		switch {
+		case isJSON:
+			operation.BodyProperty = bodyProperty
+			if _, ok = properties[bodyProperty]; ok {
+				operation.BodyProperty = fallbackBodyProperty
+			}
+			if d, ok := body["description"].(string); ok && d != "" {
+				if _, ok = schema["description"]; !ok {
+					schema = maps.Clone(schema)
+					schema["description"] = d
+				}
+			}
+			properties[operation.BodyProperty] = schema
+			if bodyRequired {
+				required = append(required, operation.BodyProperty)
+			}
+		case bodyRequired:
+			return operation, false
+		}
+	}
+
+	operation.InputSchema = map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		operation.InputSchema["required"] = required
+	}
+	return operation, true
+}
+
+// parameters returns the parameters of the given operation, including the ones of its path item that it doesn't
+// override.
+func (r *resolver) parameters(item, op map[string]any) []map[string]any {
+	var params []map[string]any
+	seen := make(map[string]struct{})
+	itemParams, _ := item["parameters"].([]any)
+	opParams, _ := op["parameters"].([]any)
+	// The parameters of the operation take precedence over the ones of the path item.
+	for _, p := range slices.Concat(opParams, itemParams) {
+		param, ok := r.resolve(p).(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		if _, ok = seen[in+"/"+name]; ok {
+			continue
+		}
+		seen[in+"/"+name] = struct{}{}
+		params = append(params, param)
+	}
+	// The path parameters come first so that they are never shadowed by another parameter with the same name.
+	slices.SortStableFunc(params, func(a, b map[string]any) int {
+		return parameterOrder(a) - parameterOrder(b)
+	})
+	return params
+}
+
+// parameterOrder returns the rank of the location of the given parameter.
+func parameterOrder(param map[string]any) int {
+	switch param["in"] {
+	case "path":
+		return 0
+	case "query":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// jsonBodySchema returns the schema of the JSON media type of the given request body content, if any.
+func (r *resolver) jsonBodySchema(content map[string]any) (map[string]any, bool) {
+	for _, mediaType := range slices.Sorted(maps.Keys(content)) {
+		mt := strings.ToLower(strings.TrimSpace(strings.Split(mediaType, ";")[0]))
+		if mt != "application/json" && !strings.HasSuffix(mt, "+json") {
+			continue
+		}
+		media, _ := content[mediaType].(map[string]any)
+		schema, _ := r.resolveSchema(media["schema"], 0).(map[string]any)
+		if schema == nil {
+			schema = map[string]any{}
+		}
+		return schema, true
+	}
+	return nil, false
+}
+
+// toolName returns the name of the tool of the given operation: its operationId, or its method and path if it has
+// none. The characters that are not allowed in the tool names are replaced by underscores.
+func toolName(method, path string, op map[string]any) string {
+	name, _ := op["operationId"].(string)
+	if name == "" {
+		name = method + "_" + strings.NewReplacer("{", "", "}", "").Replace(strings.Trim(path, "/"))
+	}
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+			return r
+		}
+		return '_'
+	}, name)
+	if len(name) > maxToolNameLength {
+		name = name[:maxToolNameLength]
+	}
+	return name
+}
+
+// uniqueName returns the given name, suffixed with a number if it is already used, and records it as used.
+func uniqueName(names map[string]struct{}, name string) string {
+	unique := name
+	for i := 2; ; i++ {
+		if _, ok := names[unique]; !ok {
+			break
+		}
+		suffix := fmt.Sprintf("_%d", i)
+		unique = name[:min(len(name), maxToolNameLength-len(suffix))] + suffix
+	}
+	names[unique] = struct{}{}
+	return unique
+}
+
+// description returns the description of the tool of the given operation.
+func description(method, path string, op map[string]any) string {
+	summary, _ := op["summary"].(string)
+	desc, _ := op["description"].(string)
+	summary, desc = strings.TrimSpace(summary), strings.TrimSpace(desc)
+	switch {
+	case summary != "" && desc != "" && summary != desc:
+		return summary + "\n\n" + desc
+	case summary != "":
+		return summary
+	case desc != "":
+		return desc
+	}
+	return strings.ToUpper(method) + " " + path
+}
+
+// resolver resolves the local references of an OpenAPI document.
+type resolver struct {
+	root map[string]any
+}
+
+// resolve returns the value referenced by the given value if it is a reference, or the value itself otherwise.
+func (r *resolver) resolve(v any) any {
+	for range maxRefDepth {
+		ref, ok := reference(v)
+		if !ok {
+			return v
+		}
+		v = r.lookup(ref)
+	}
+	return nil
+}
+
+// resolveSchema returns a copy of the given schema with all its references resolved. The recursive references are
+// replaced by empty schemas, which accept any value.
+func (r *resolver) resolveSchema(v any, depth int, visiting ...string) any {
+	if depth > maxRefDepth {
+		return map[string]any{}
+	}
+	switch t := v.(type) {
+	case map[string]any:
+		if ref, ok := reference(t); ok {
+			if slices.Contains(visiting, ref) {
+				return map[string]any{}
+			}
+			return r.resolveSchema(r.lookup(ref), depth+1, append(slices.Clip(visiting), ref)...)
+		}
+		out := make(map[string]any, len(t))
+		for k, child := range t {
+			out[k] = r.resolveSchema(child, depth+1, visiting...)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, child := range t {
+			out[i] = r.resolveSchema(child, depth+1, visiting...)
+		}
+		return out
+	}
+	return v
+}
+
+// lookup returns the value at the given local reference, such as "#/components/schemas/Pet", or nil if it doesn't
+// exist.
+func (r *resolver) lookup(ref string) any {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+	var v any = r.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[strings.NewReplacer("~1", "/", "~0", "~").Replace(token)]
+	}
+	return v
+}
+
+// reference returns the reference of the given value if it is a reference object.
+func reference(v any) (string, bool) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return "", false
+	}
+	ref, ok := m["$ref"].(string)
+	return ref, ok
+}
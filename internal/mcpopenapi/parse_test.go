@@ -0,0 +1,216 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpopenapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+)
+
+const petStore = `
+openapi: 3.0.3
+servers:
+  - url: https://{region}.example.com/{version}/
+    variables:
+      region:
+        default: eu
+      version:
+        default: v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List the pets.
+      parameters:
+        - name: limit
+          in: query
+          description: The maximum number of pets.
+          schema:
+            type: integer
+        - name: Authorization
+          in: header
+          schema:
+            type: string
+        - name: session
+          in: cookie
+          schema:
+            type: string
+    post:
+      operationId: create pet
+      description: Create a pet.
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+  /pets/{petId}:
+    parameters:
+      - $ref: '#/components/parameters/petId'
+    get:
+      summary: Get a pet.
+      parameters:
+        - name: X-Trace
+          in: header
+          required: true
+          schema:
+            type: string
+    put:
+      operationId: uploadPhoto
+      requestBody:
+        required: true
+        content:
+          image/png: {}
+    delete:
+      operationId: listPets
+      parameters:
+        - name: body
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+      requestBody:
+        content:
+          application/merge-patch+json:
+            schema:
+              type: object
+components:
+  parameters:
+    petId:
+      name: petId
+      in: path
+      schema:
+        type: string
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Pet'
+`
+
+func TestParse(t *testing.T) {
+	backend, err := Parse([]byte(petStore))
+	require.NoError(t, err)
+	require.Equal(t, "/v1", backend.BasePath)
+
+	require.Equal(t, []filterapi.MCPOpenAPIOperation{
+		{
+			Name:        "listPets",
+			Description: "List the pets.",
+			Method:      "GET",
+			Path:        "/pets",
+			Parameters:  []filterapi.MCPOpenAPIParameter{{Name: "limit", In: "query"}},
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"limit": map[string]any{"type": "integer", "description": "The maximum number of pets."},
+				},
+			},
+		},
+		{
+			Name:         "create_pet",
+			Description:  "Create a pet.",
+			Method:       "POST",
+			Path:         "/pets",
+			BodyProperty: "body",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"body": map[string]any{
+						"type":     "object",
+						"required": []any{"name"},
+						"properties": map[string]any{
+							"name": map[string]any{"type": "string"},
+							// The recursive reference is replaced by an empty schema.
+							"children": map[string]any{"type": "array", "items": map[string]any{}},
+						},
+					},
+				},
+				"required": []string{"body"},
+			},
+		},
+		{
+			Name:        "get_pets_petId",
+			Description: "Get a pet.",
+			Method:      "GET",
+			Path:        "/pets/{petId}",
+			Parameters:  []filterapi.MCPOpenAPIParameter{{Name: "petId", In: "path"}, {Name: "X-Trace", In: "header"}},
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"petId":   map[string]any{"type": "string"},
+					"X-Trace": map[string]any{"type": "string"},
+				},
+				"required": []string{"petId", "X-Trace"},
+			},
+		},
+		// The uploadPhoto operation requires a body that is not JSON, so it is skipped.
+		{
+			Name:         "listPets_2",
+			Description:  "DELETE /pets/{petId}",
+			Method:       "DELETE",
+			Path:         "/pets/{petId}",
+			Parameters:   []filterapi.MCPOpenAPIParameter{{Name: "petId", In: "path"}, {Name: "body", In: "query"}},
+			BodyProperty: "requestBody",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"petId":       map[string]any{"type": "string"},
+					"body":        map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+					"requestBody": map[string]any{"type": "object"},
+				},
+				"required": []string{"petId"},
+			},
+		},
+	}, backend.Operations)
+}
+
+func TestParse_JSON(t *testing.T) {
+	backend, err := Parse([]byte(`{"openapi":"3.1.0","servers":[{"url":"/api"}],"paths":{"/ping":{"head":{"operationId":"ping"}}}}`))
+	require.NoError(t, err)
+	require.Equal(t, "/api", backend.BasePath)
+	require.Len(t, backend.Operations, 1)
+	require.Equal(t, "HEAD", backend.Operations[0].Method)
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name, doc, err string
+	}{
+		{name: "invalid", doc: "{", err: "failed to parse the OpenAPI document"},
+		{name: "not an object", doc: "[]", err: "the OpenAPI document is not an object"},
+		{name: "swagger", doc: "swagger: '2.0'", err: `unsupported OpenAPI version ""`},
+		{name: "no operations", doc: "openapi: 3.0.0\npaths: {}", err: "no supported operations"},
+		{name: "invalid server", doc: "openapi: 3.0.0\nservers: [{url: 'http://[::1'}]", err: "invalid server URL"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.doc))
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestUniqueName(t *testing.T) {
+	names := map[string]struct{}{}
+	require.Equal(t, "op", uniqueName(names, "op"))
+	require.Equal(t, "op_2", uniqueName(names, "op"))
+	require.Equal(t, "op_3", uniqueName(names, "op"))
+
+	long := strings.Repeat("a", maxToolNameLength)
+	require.Equal(t, long, uniqueName(names, long))
+	require.Equal(t, strings.Repeat("a", maxToolNameLength-2)+"_2", uniqueName(names, long))
+}
@@ -0,0 +1,319 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/exporters/autoexport"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/redaction"
+)
+
+// AuditArgumentsMode specifies how the arguments of the audited requests are recorded.
+type AuditArgumentsMode string
+
+const (
+	// AuditArgumentsRedact records the structure of the arguments with every value redacted.
+	AuditArgumentsRedact AuditArgumentsMode = "redact"
+	// AuditArgumentsHash records a hash of the arguments, which allows correlating identical requests.
+	AuditArgumentsHash AuditArgumentsMode = "hash"
+)
+
+// auditedMethods are the MCP methods recorded in the audit log.
+var auditedMethods = map[string]struct{}{
+	"tools/call":     {},
+	"resources/read": {},
+	"prompts/get":    {},
+}
+
+type (
+	// AuditSink is the destination of the audit records.
+	AuditSink interface {
+		// Record records the given audit record. It is called concurrently by the request handlers.
+		Record(ctx context.Context, record *AuditRecord)
+		// Close flushes the pending records and releases the resources of the sink.
+		Close(ctx context.Context) error
+	}
+
+	// AuditRecord is the audit record of a tools/call, resources/read or prompts/get request, or of a
+	// sampling/createMessage or elicitation/create request of a backend subject to its policy.
+	AuditRecord struct {
+		// Time is the time the request was received.
+		Time time.Time `json:"time"`
+		// Method is the MCP method of the request.
+		Method string `json:"method"`
+		// Subject is the "sub" claim of the access token of the client, if any.
+		Subject string `json:"subject,omitempty"`
+		// Route is the name of the MCPRoute.
+		Route string `json:"route"`
+		// Backend is the name of the backend the request was routed to, if it was resolved.
+		Backend string `json:"backend,omitempty"`
+		// Name is the name of the tool or prompt, or the URI of the resource, as requested by the client.
+		Name string `json:"name"`
+		// Arguments are the arguments of the request with every value redacted.
+		Arguments any `json:"arguments,omitempty"`
+		// ArgumentsHash is the hash of the arguments of the request, as sent by the client.
+		ArgumentsHash string `json:"argumentsHash,omitempty"`
+		// Status is the outcome of the request: "success", "failed" when the tool returned isError=true, or "error".
+		Status metrics.MCPStatusType `json:"status"`
+		// IsError is true when the tool returned a result with isError=true.
+		IsError bool `json:"isError,omitempty"`
+		// ErrorType is the type of the error when the status is "error".
+		ErrorType metrics.MCPErrorType `json:"errorType,omitempty"`
+		// LatencyMs is the time it took to handle the request in milliseconds.
+		LatencyMs int64 `json:"latencyMs"`
+	}
+
+	// AuditLog records the MCP requests reaching the backends in an AuditSink.
+	AuditLog struct {
+		sink      AuditSink
+		arguments AuditArgumentsMode
+	}
+
+	// jsonlAuditSink writes the audit records as JSON lines.
+	jsonlAuditSink struct {
+		mu sync.Mutex
+		w  io.Writer
+		// closer is nil when the writer must not be closed, such as stdout.
+		closer io.Closer
+		l      *slog.Logger
+	}
+
+	// otlpAuditSink exports the audit records as OpenTelemetry log records.
+	otlpAuditSink struct {
+		provider *sdklog.LoggerProvider
+		logger   otellog.Logger
+	}
+)
+
+// NewAuditLog creates the audit log writing to the given destination: "otlp" exports the records as OpenTelemetry
+// logs configured with the standard OTEL_EXPORTER_OTLP_* environment variables, "stdout" writes them as JSON lines
+// to the given stdout, and any other value is the path of the file the JSON lines are appended to.
+func NewAuditLog(ctx context.Context, l *slog.Logger, destination string, arguments AuditArgumentsMode, stdout io.Writer) (*AuditLog, error) {
+	if err := arguments.validate(); err != nil {
+		return nil, err
+	}
+	var sink AuditSink
+	switch destination {
+	case "":
+		return nil, errors.New("missing audit log destination")
+	case "otlp":
+		exporter, err := autoexport.NewLogExporter(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create audit log exporter: %w", err)
+		}
+		res, err := resource.New(ctx, resource.WithFromEnv(), resource.WithTelemetrySDK())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create resource from env: %w", err)
+		}
+		sink = newOTLPAuditSink(exporter, res)
+	case "stdout":
+		sink = &jsonlAuditSink{w: stdout, l: l}
+	default:
+		f, err := os.OpenFile(destination, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log file: %w", err)
+		}
+		sink = &jsonlAuditSink{w: f, closer: f, l: l}
+	}
+	return &AuditLog{sink: sink, arguments: arguments}, nil
+}
+
+// ParseAuditArgumentsMode parses the given mode of recording the arguments of the audited requests.
+func ParseAuditArgumentsMode(s string) (AuditArgumentsMode, error) {
+	mode := AuditArgumentsMode(s)
+	return mode, mode.validate()
+}
+
+func (a AuditArgumentsMode) validate() error {
+	switch a {
+	case AuditArgumentsRedact, AuditArgumentsHash:
+		return nil
+	default:
+		return fmt.Errorf("invalid audit arguments mode %q: must be %q or %q", a, AuditArgumentsRedact, AuditArgumentsHash)
+	}
+}
+
+// Close flushes the pending records and closes the sink.
+func (a *AuditLog) Close(ctx context.Context) error {
+	return a.sink.Close(ctx)
+}
+
+// recordAudit records the given request in the audit log, if enabled and the method is audited. The rawParams are
+// the params of the request as sent by the client, since the handlers rewrite them for the backends.
+func (m *mcpRequestContext) recordAudit(ctx context.Context, r *http.Request, s *session, method string, rawParams []byte,
+	backend string, err error, errType metrics.MCPErrorType, startAt time.Time,
+) {
+	if m.auditLog == nil || s == nil {
+		return
+	}
+	if _, ok := auditedMethods[method]; !ok {
+		return
+	}
+
+	record := &AuditRecord{
+		Time:      startAt,
+		Method:    method,
+		Subject:   extractSubject(r),
+		Route:     s.route,
+		Backend:   backend,
+		Status:    metrics.MCPStatusSuccess,
+		LatencyMs: time.Since(startAt).Milliseconds(),
+	}
+	if err != nil {
+		var toolErr *errToolCall
+		if errors.As(err, &toolErr) {
+			record.Status = metrics.MCPStatusFailed
+			record.IsError = true
+		} else {
+			record.Status = metrics.MCPStatusError
+			record.ErrorType = errType
+		}
+	}
+
+	var p struct {
+		Name      string          `json:"name"`
+		URI       string          `json:"uri"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	_ = json.Unmarshal(rawParams, &p) // The params have been validated by the handler.
+	if method == "tools/call" && p.Name == callToolName {
+		// Record the tool called through the call_tool meta-tool of the tool search mode.
+		var call struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if json.Unmarshal(p.Arguments, &call) == nil && call.Name != "" {
+			p.Name, p.Arguments = call.Name, call.Arguments
+		}
+	}
+	record.Name = p.Name
+	if method == "resources/read" {
+		record.Name = p.URI
+	}
+	if len(p.Arguments) > 0 && string(p.Arguments) != "null" {
+		switch m.auditLog.arguments {
+		case AuditArgumentsHash:
+			record.ArgumentsHash = redaction.ComputeContentHash(string(p.Arguments))
+		default:
+			var args any
+			if json.Unmarshal(p.Arguments, &args) == nil {
+				record.Arguments = redactArgumentValues(args)
+			}
+		}
+	}
+	m.auditLog.sink.Record(ctx, record)
+}
+
+// redactArgumentValues redacts every value of the given JSON value, keeping the object keys and the array lengths.
+func redactArgumentValues(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = redactArgumentValues(e)
+		}
+		return v
+	case []any:
+		for i, e := range v {
+			v[i] = redactArgumentValues(e)
+		}
+		return v
+	case string:
+		return redaction.RedactString(v)
+	case nil, bool:
+		return v
+	default:
+		return redaction.RedactString(fmt.Sprint(v))
+	}
+}
+
+// Record implements [AuditSink.Record].
+func (j *jsonlAuditSink) Record(_ context.Context, record *AuditRecord) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		j.l.Error("failed to encode audit record", slog.String("error", err.Error()))
+		return
+	}
+	line = append(line, '\n')
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err = j.w.Write(line); err != nil {
+		j.l.Error("failed to write audit record", slog.String("error", err.Error()))
+	}
+}
+
+// Close implements [AuditSink.Close].
+func (j *jsonlAuditSink) Close(context.Context) error {
+	if j.closer == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.closer.Close()
+}
+
+func newOTLPAuditSink(exporter sdklog.Exporter, res *resource.Resource) *otlpAuditSink {
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+		sdklog.WithResource(res),
+	)
+	return &otlpAuditSink{provider: provider, logger: provider.Logger("envoyproxy/ai-gateway/mcp-audit")}
+}
+
+// Record implements [AuditSink.Record].
+func (o *otlpAuditSink) Record(ctx context.Context, record *AuditRecord) {
+	var r otellog.Record
+	r.SetTimestamp(record.Time)
+	r.SetObservedTimestamp(time.Now())
+	r.SetEventName("mcp.audit")
+	r.SetSeverity(otellog.SeverityInfo)
+	r.SetBody(otellog.StringValue(record.Method + " " + record.Name))
+	r.AddAttributes(
+		otellog.String("mcp.method.name", record.Method),
+		otellog.String("mcp.route", record.Route),
+		otellog.String("mcp.name", record.Name),
+		otellog.String("status", string(record.Status)),
+		otellog.Bool("mcp.is_error", record.IsError),
+		otellog.Int64("mcp.latency_ms", record.LatencyMs),
+	)
+	if record.Subject != "" {
+		r.AddAttributes(otellog.String("enduser.id", record.Subject))
+	}
+	if record.Backend != "" {
+		r.AddAttributes(otellog.String("mcp.backend", record.Backend))
+	}
+	if record.Arguments != nil {
+		if args, err := json.Marshal(record.Arguments); err == nil {
+			r.AddAttributes(otellog.String("mcp.arguments", string(args)))
+		}
+	}
+	if record.ArgumentsHash != "" {
+		r.AddAttributes(otellog.String("mcp.arguments.hash", record.ArgumentsHash))
+	}
+	if record.ErrorType != "" {
+		r.AddAttributes(otellog.String("error.type", string(record.ErrorType)))
+	}
+	o.logger.Emit(ctx, r)
+}
+
+// Close implements [AuditSink.Close].
+func (o *otlpAuditSink) Close(ctx context.Context) error {
+	return o.provider.Shutdown(ctx)
+}
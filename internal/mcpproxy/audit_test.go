@@ -0,0 +1,213 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/redaction"
+)
+
+type recordingAuditSink struct {
+	records []*AuditRecord
+}
+
+func (r *recordingAuditSink) Record(_ context.Context, record *AuditRecord) {
+	r.records = append(r.records, record)
+}
+
+func (r *recordingAuditSink) Close(context.Context) error { return nil }
+
+type recordingLogExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (r *recordingLogExporter) Export(_ context.Context, records []sdklog.Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, record := range records {
+		r.records = append(r.records, record.Clone())
+	}
+	return nil
+}
+
+func (r *recordingLogExporter) Shutdown(context.Context) error   { return nil }
+func (r *recordingLogExporter) ForceFlush(context.Context) error { return nil }
+
+func TestNewAuditLog(t *testing.T) {
+	l := slog.New(slog.DiscardHandler)
+	_, err := NewAuditLog(t.Context(), l, "stdout", "plain", nil)
+	require.EqualError(t, err, `invalid audit arguments mode "plain": must be "redact" or "hash"`)
+	_, err = NewAuditLog(t.Context(), l, "", AuditArgumentsRedact, nil)
+	require.EqualError(t, err, "missing audit log destination")
+	_, err = NewAuditLog(t.Context(), l, filepath.Join(t.TempDir(), "missing", "audit.jsonl"), AuditArgumentsRedact, nil)
+	require.ErrorContains(t, err, "failed to open audit log file")
+
+	t.Run("stdout", func(t *testing.T) {
+		var stdout bytes.Buffer
+		audit, err := NewAuditLog(t.Context(), l, "stdout", AuditArgumentsRedact, &stdout)
+		require.NoError(t, err)
+		audit.sink.Record(t.Context(), &AuditRecord{Method: "tools/call", Name: "backend1__tool", Status: metrics.MCPStatusSuccess})
+		require.NoError(t, audit.Close(t.Context()))
+		require.JSONEq(t, `{"time":"0001-01-01T00:00:00Z","method":"tools/call","route":"","name":"backend1__tool","status":"success","latencyMs":0}`,
+			stdout.String())
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+		require.NoError(t, os.WriteFile(path, []byte("{}\n"), 0o600))
+		audit, err := NewAuditLog(t.Context(), l, path, AuditArgumentsHash, nil)
+		require.NoError(t, err)
+		audit.sink.Record(t.Context(), &AuditRecord{Method: "tools/call", Name: "a"})
+		audit.sink.Record(t.Context(), &AuditRecord{Method: "prompts/get", Name: "b"})
+		require.NoError(t, audit.Close(t.Context()))
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		// The records are appended as JSON lines.
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		require.Len(t, lines, 3)
+		require.Contains(t, lines[1], `"name":"a"`)
+		require.Contains(t, lines[2], `"name":"b"`)
+	})
+}
+
+func TestRecordAudit(t *testing.T) {
+	startAt := time.Now().Add(-time.Second)
+	sink := &recordingAuditSink{}
+	proxy := newTestMCPProxy()
+	s := &session{route: "test-route"}
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("Authorization", "Bearer "+requireJWT(t, "alice", time.Time{}))
+	toolParams := []byte(`{"name":"backend1__test-tool","arguments":{"owner":"envoyproxy","count":2,"private":true,"tags":["a"]}}`)
+
+	// Nothing is recorded when the audit log is disabled.
+	proxy.recordAudit(t.Context(), req, s, "tools/call", toolParams, "backend1", nil, "", startAt)
+
+	proxy.auditLog = &AuditLog{sink: sink, arguments: AuditArgumentsRedact}
+	proxy.recordAudit(t.Context(), req, s, "tools/call", toolParams, "backend1", nil, "", startAt)
+	proxy.recordAudit(t.Context(), req, s, "tools/call", toolParams, "backend1",
+		&errToolCall{toolName: "test-tool", backend: "backend1", err: errors.New("boom")}, metrics.MCPErrorInternal, startAt)
+	proxy.recordAudit(t.Context(), req, s, "tools/call", []byte(`{"name":"unknown__tool"}`), "",
+		errBackendNotFound, metrics.MCPErrorInvalidParam, startAt)
+	proxy.recordAudit(t.Context(), req, s, "resources/read", []byte(`{"uri":"backend1+file:///a"}`), "backend1", nil, "", startAt)
+	// The methods that are not audited and the requests without a session are ignored.
+	proxy.recordAudit(t.Context(), req, s, "tools/list", []byte(`{}`), "", nil, "", startAt)
+	proxy.recordAudit(t.Context(), req, nil, "tools/call", toolParams, "", errSessionNotFound, metrics.MCPErrorInvalidParam, startAt)
+
+	proxy.auditLog.arguments = AuditArgumentsHash
+	promptParams := []byte(`{"name":"backend2__prompt","arguments":{"topic":"secret"}}`)
+	proxy.recordAudit(t.Context(), httptest.NewRequest(http.MethodPost, "/mcp", nil), s, "prompts/get", promptParams, "backend2", nil, "", startAt)
+	// The tools called through the call_tool meta-tool are recorded.
+	proxy.recordAudit(t.Context(), req, s, "tools/call", []byte(`{"name":"call_tool","arguments":{"name":"backend2__forecast","arguments":{"city":"Tokyo"}}}`),
+		"backend2", nil, "", startAt)
+
+	require.Len(t, sink.records, 6)
+	for _, record := range sink.records {
+		require.Equal(t, startAt, record.Time)
+		require.Equal(t, "test-route", record.Route)
+		require.GreaterOrEqual(t, record.LatencyMs, int64(1000))
+	}
+
+	require.Equal(t, "tools/call", sink.records[0].Method)
+	require.Equal(t, "alice", sink.records[0].Subject)
+	require.Equal(t, "backend1", sink.records[0].Backend)
+	require.Equal(t, "backend1__test-tool", sink.records[0].Name)
+	require.Equal(t, metrics.MCPStatusSuccess, sink.records[0].Status)
+	require.False(t, sink.records[0].IsError)
+	require.Empty(t, sink.records[0].ErrorType)
+	require.Equal(t, map[string]any{
+		"owner":   redaction.RedactString("envoyproxy"),
+		"count":   redaction.RedactString("2"),
+		"private": true,
+		"tags":    []any{redaction.RedactString("a")},
+	}, sink.records[0].Arguments)
+	require.Empty(t, sink.records[0].ArgumentsHash)
+
+	require.Equal(t, metrics.MCPStatusFailed, sink.records[1].Status)
+	require.True(t, sink.records[1].IsError)
+	require.Empty(t, sink.records[1].ErrorType)
+
+	require.Equal(t, "unknown__tool", sink.records[2].Name)
+	require.Empty(t, sink.records[2].Backend)
+	require.Equal(t, metrics.MCPStatusError, sink.records[2].Status)
+	require.Equal(t, metrics.MCPErrorInvalidParam, sink.records[2].ErrorType)
+	require.Nil(t, sink.records[2].Arguments)
+
+	require.Equal(t, "resources/read", sink.records[3].Method)
+	require.Equal(t, "backend1+file:///a", sink.records[3].Name)
+
+	require.Equal(t, "prompts/get", sink.records[4].Method)
+	require.Empty(t, sink.records[4].Subject)
+	require.Nil(t, sink.records[4].Arguments)
+	require.Equal(t, redaction.ComputeContentHash(`{"topic":"secret"}`), sink.records[4].ArgumentsHash)
+
+	require.Equal(t, "backend2__forecast", sink.records[5].Name)
+	require.Equal(t, redaction.ComputeContentHash(`{"city":"Tokyo"}`), sink.records[5].ArgumentsHash)
+}
+
+func TestOTLPAuditSink(t *testing.T) {
+	exporter := &recordingLogExporter{}
+	sink := newOTLPAuditSink(exporter, resource.Empty())
+	now := time.Now()
+	sink.Record(t.Context(), &AuditRecord{
+		Time:      now,
+		Method:    "tools/call",
+		Subject:   "alice",
+		Route:     "route",
+		Backend:   "backend1",
+		Name:      "backend1__tool",
+		Arguments: map[string]any{"owner": "[REDACTED]"},
+		Status:    metrics.MCPStatusError,
+		ErrorType: metrics.MCPErrorInvalidToolArguments,
+		LatencyMs: 12,
+	})
+	require.NoError(t, sink.Close(t.Context()))
+
+	require.Len(t, exporter.records, 1)
+	record := exporter.records[0]
+	require.Equal(t, "mcp.audit", record.EventName())
+	require.True(t, now.Equal(record.Timestamp()))
+	require.Equal(t, otellog.SeverityInfo, record.Severity())
+	require.Equal(t, "tools/call backend1__tool", record.Body().AsString())
+	attrs := map[string]string{}
+	record.WalkAttributes(func(kv otellog.KeyValue) bool {
+		attrs[kv.Key] = kv.Value.String()
+		return true
+	})
+	args, _ := json.Marshal(map[string]any{"owner": "[REDACTED]"})
+	require.Equal(t, map[string]string{
+		"mcp.method.name": "tools/call",
+		"mcp.route":       "route",
+		"mcp.name":        "backend1__tool",
+		"status":          "error",
+		"mcp.is_error":    "false",
+		"mcp.latency_ms":  "12",
+		"enduser.id":      "alice",
+		"mcp.backend":     "backend1",
+		"mcp.arguments":   string(args),
+		"error.type":      "invalid_tool_arguments",
+	}, attrs)
+}
@@ -6,6 +6,7 @@
 package mcpproxy
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
@@ -28,10 +29,16 @@ type (
 		toolChangeSignaler         changeSignaler // signals tool changes to active sessions.
 		l                          *slog.Logger
 		sessionCrypto              SessionCrypto
+		sessionStore               SessionStore // nil unless the session state is stored.
+		tokens                     *tokenCache  // tokens obtained with the token exchange.
+		toolSchemas                *toolSchemaCache
+		toolSearchIndexes          *toolSearchIndexCache
+		auditLog                   *AuditLog // nil unless the requests are audited.
 		tracer                     tracingapi.MCPTracer
 		client                     http.Client
 		logRequestHeaderAttributes map[string]string
-		maxRequestBodySize         int64 // maximum allowed POST body size in bytes
+		maxRequestBodySize         int64                                  // maximum allowed POST body size in bytes
+		localServers               map[localServerKey]*runningLocalServer // running local MCP servers.
 	}
 
 	mcpProxyConfig struct {
@@ -44,6 +51,12 @@ type (
 		toolSelectors  map[filterapi.MCPBackendName]*toolSelector
 		authorization  *compiledAuthorization
 		forwardHeaders []string
+		rateLimiter    *rateLimiter
+		localURLs      map[filterapi.MCPBackendName]string // backend name -> URL of the local MCP server.
+		legacySSE      *filterapi.MCPLegacySSE
+		toolValidation filterapi.MCPToolValidation
+		toolSearch     *filterapi.MCPToolSearch
+		health         *backendHealth // nil unless the health of the backends is checked.
 	}
 
 	// toolSelector filters tools using include and exclude patterns with exact matches or regular expressions.
@@ -91,6 +104,10 @@ func (m *mcpProxyConfigRoute) sameTools(other *mcpProxyConfigRoute) bool {
 	if !m.authorization.same(other.authorization) {
 		return false
 	}
+	// A restarted local MCP server may expose different tools.
+	if !maps.Equal(m.localURLs, other.localURLs) {
+		return false
+	}
 	return maps.EqualFunc(m.toolSelectors, other.toolSelectors, func(a, b *toolSelector) bool {
 		return a.sameTools(b)
 	})
@@ -178,7 +195,7 @@ func (t *toolSelector) allows(tool string) bool {
 
 // LoadConfig implements [extproc.ConfigReceiver.LoadConfig] which will be called
 // when the configuration is updated on the file system.
-func (p *ProxyConfig) LoadConfig(_ context.Context, config *filterapi.Config) error {
+func (p *ProxyConfig) LoadConfig(ctx context.Context, config *filterapi.Config) error {
 	newConfig := &mcpProxyConfig{}
 	mcpConfig := config.MCPConfig
 	if config.MCPConfig == nil {
@@ -193,20 +210,51 @@ func (p *ProxyConfig) LoadConfig(_ context.Context, config *filterapi.Config) er
 	// the MCP proxy initializes sessions only with the backends tied to that route.
 	newConfig.routes = make(map[filterapi.MCPRouteName]*mcpProxyConfigRoute, len(mcpConfig.Routes))
 
+	localURLs, err := p.syncLocalServers(ctx, mcpConfig)
+	if err != nil {
+		return err
+	}
+
 	for _, route := range mcpConfig.Routes {
 		compiledAuth, err := compileAuthorization(route.Authorization)
 		if err != nil {
 			return fmt.Errorf("failed to compile authorization rules for route %s: %w", route.Name, err)
 		}
 
+		var prevRateLimiter *rateLimiter
+		if p.mcpProxyConfig != nil {
+			if prev := p.routes[route.Name]; prev != nil {
+				prevRateLimiter = prev.rateLimiter
+			}
+		}
+		limiter, err := newRateLimiter(route.RateLimits, prevRateLimiter)
+		if err != nil {
+			return fmt.Errorf("failed to compile rate limits for route %s: %w", route.Name, err)
+		}
+
+		toolValidation := filterapi.MCPToolValidation{Arguments: true}
+		if route.ToolValidation != nil {
+			toolValidation = *route.ToolValidation
+		}
+
 		r := &mcpProxyConfigRoute{
 			backends:       make(map[filterapi.MCPBackendName]filterapi.MCPBackend, len(route.Backends)),
 			toolSelectors:  make(map[filterapi.MCPBackendName]*toolSelector, len(route.Backends)),
 			authorization:  compiledAuth,
 			forwardHeaders: route.ForwardHeaders,
+			rateLimiter:    limiter,
+			legacySSE:      route.LegacySSE,
+			toolValidation: toolValidation,
+			toolSearch:     route.ToolSearch,
 		}
 		for _, backend := range route.Backends {
 			r.backends[backend.Name] = backend
+			if url, ok := localURLs[localServerKey{route: route.Name, backend: backend.Name}]; ok {
+				if r.localURLs == nil {
+					r.localURLs = make(map[filterapi.MCPBackendName]string)
+				}
+				r.localURLs[backend.Name] = url
+			}
 			if s := backend.ToolSelector; s != nil {
 				ts := &toolSelector{
 					include: make(map[string]struct{}),
@@ -231,12 +279,39 @@ func (p *ProxyConfig) LoadConfig(_ context.Context, config *filterapi.Config) er
 				r.toolSelectors[backend.Name] = ts
 			}
 		}
+		var prevHealth *backendHealth
+		if p.mcpProxyConfig != nil {
+			if prev := p.routes[route.Name]; prev != nil {
+				prevHealth = prev.health
+			}
+		}
+		urls := make(map[filterapi.MCPBackendName]string, len(route.Backends))
+		for _, backend := range route.Backends {
+			urls[backend.Name] = cmp.Or(r.localURLs[backend.Name], mcpConfig.BackendListenerAddr)
+		}
+		r.health = p.newBackendHealth(&route, r.backends, urls, prevHealth)
 		newConfig.routes[route.Name] = r
 	}
 
+	// Start the health checking of the new routes and stop the one of the routes that changed or were removed.
+	for name, r := range newConfig.routes {
+		if p.mcpProxyConfig == nil || p.routes[name] == nil || p.routes[name].health != r.health {
+			r.health.start(ctx)
+		}
+	}
+	if p.mcpProxyConfig != nil {
+		for name, prev := range p.routes {
+			if r := newConfig.routes[name]; r == nil || r.health != prev.health {
+				prev.health.close()
+			}
+		}
+	}
+
 	toolsChanged := !p.sameTools(newConfig)
 	p.mcpProxyConfig = newConfig // This is racy, but we don't care.
+	p.toolSchemas.retain(newConfig)
 	if toolsChanged {
+		p.toolSearchIndexes.clear()
 		p.toolChangeSignaler.Signal()
 	}
 
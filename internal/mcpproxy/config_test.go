@@ -85,7 +85,7 @@ func Test_toolSelector_Allows(t *testing.T) {
 }
 
 func TestLoadConfig_NilMCPConfig(t *testing.T) {
-	proxy, _, err := NewMCPProxy(slog.Default(), stubMetrics{}, noopTracer, NewPBKDF2AesGcmSessionCrypto("test", 100), nil)
+	proxy, _, err := NewMCPProxy(slog.Default(), stubMetrics{}, noopTracer, NewPBKDF2AesGcmSessionCrypto("test", 100), nil, nil, nil)
 	require.NoError(t, err)
 
 	config := &filterapi.Config{MCPConfig: nil}
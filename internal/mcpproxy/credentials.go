@@ -0,0 +1,249 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+// errBackendCredential is returned when the credential of the user for a backend cannot be obtained.
+var errBackendCredential = errors.New("failed to obtain the backend credential")
+
+const (
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	accessTokenType        = "urn:ietf:params:oauth:token-type:access_token"
+	// tokenExpirySkew is subtracted from the lifetime of the exchanged tokens so that they are not used right
+	// before they expire.
+	tokenExpirySkew = 30 * time.Second
+)
+
+type (
+	// tokenCache caches the tokens obtained with the OAuth 2.0 Token Exchange until they expire.
+	tokenCache struct {
+		mu        sync.Mutex
+		tokens    map[string]cachedToken
+		lastSweep time.Time
+		// group deduplicates the concurrent exchanges of the same token.
+		group singleflight.Group
+		// now is replaced in tests.
+		now func() time.Time
+	}
+
+	cachedToken struct {
+		token     string
+		expiresAt time.Time
+	}
+
+	// tokenExchangeResponse is the successful response of the token endpoint defined in RFC 8693 Section 2.2.1.
+	tokenExchangeResponse struct {
+		AccessToken     string `json:"access_token"`
+		IssuedTokenType string `json:"issued_token_type"`
+		TokenType       string `json:"token_type"`
+		ExpiresIn       int64  `json:"expires_in"`
+	}
+
+	// tokenErrorResponse is the error response of the token endpoint defined in RFC 6749 Section 5.2.
+	tokenErrorResponse struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+)
+
+func newTokenCache() *tokenCache {
+	return &tokenCache{tokens: make(map[string]cachedToken), now: time.Now}
+}
+
+// setBackendCredential injects the credential of the user of the request for the given backend, if the backend is
+// configured with one. The user is identified by the access token of the client, which has been validated by Envoy.
+func (m *mcpRequestContext) setBackendCredential(ctx context.Context, req *http.Request, backend filterapi.MCPBackend) error {
+	c := backend.Credential
+	if c == nil {
+		return nil
+	}
+	subjectToken, err := bearerToken(m.requestHeaders.Get("Authorization"))
+	if err != nil {
+		return fmt.Errorf("%w for backend %s: %w", errBackendCredential, backend.Name, err)
+	}
+	var credential string
+	if c.TokenExchange != nil {
+		credential, err = m.tokens.exchange(ctx, &m.client, c.TokenExchange, subjectToken)
+		if err != nil {
+			return fmt.Errorf("%w for backend %s: %w", errBackendCredential, backend.Name, err)
+		}
+	} else {
+		var claims jwt.RegisteredClaims
+		_, _, _ = jwt.NewParser().ParseUnverified(subjectToken, &claims)
+		var ok bool
+		if credential, ok = c.UserCredentials[claims.Subject]; !ok || claims.Subject == "" {
+			return fmt.Errorf("%w for backend %s: no credential for subject %q", errBackendCredential, backend.Name, claims.Subject)
+		}
+	}
+	if strings.EqualFold(c.Header, "Authorization") {
+		credential = "Bearer " + credential
+	}
+	req.Header.Set(c.Header, credential)
+	return nil
+}
+
+// exchange returns the token obtained by exchanging the given subject token at the configured token endpoint.
+// The tokens are cached until they expire, and at most until the subject token expires.
+func (t *tokenCache) exchange(ctx context.Context, client *http.Client, config *filterapi.MCPTokenExchange, subjectToken string) (string, error) {
+	key := tokenCacheKey(config, subjectToken)
+	if token, ok := t.get(key); ok {
+		return token, nil
+	}
+	v, err, _ := t.group.Do(key, func() (any, error) {
+		if token, ok := t.get(key); ok {
+			return token, nil
+		}
+		resp, err := requestTokenExchange(ctx, client, config, subjectToken)
+		if err != nil {
+			return "", err
+		}
+		t.put(key, resp.AccessToken, t.expiresAt(resp.ExpiresIn, subjectToken))
+		return resp.AccessToken, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+// expiresAt returns the time until which an exchanged token with the given lifetime in seconds can be cached.
+// The zero time means that the token must not be cached.
+func (t *tokenCache) expiresAt(expiresIn int64, subjectToken string) time.Time {
+	now := t.now()
+	var expiresAt time.Time
+	if expiresIn > 0 {
+		expiresAt = now.Add(time.Duration(expiresIn)*time.Second - tokenExpirySkew)
+	}
+	var claims jwt.RegisteredClaims
+	_, _, _ = jwt.NewParser().ParseUnverified(subjectToken, &claims)
+	if claims.ExpiresAt != nil && (expiresAt.IsZero() || claims.ExpiresAt.Before(expiresAt)) {
+		expiresAt = claims.ExpiresAt.Time
+	}
+	if !expiresAt.After(now) {
+		return time.Time{}
+	}
+	return expiresAt
+}
+
+func (t *tokenCache) get(key string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c, ok := t.tokens[key]
+	if !ok || !t.now().Before(c.expiresAt) {
+		return "", false
+	}
+	return c.token, true
+}
+
+// put caches the given token until the given time. The expired tokens are deleted at most once per minute.
+func (t *tokenCache) put(key, token string, expiresAt time.Time) {
+	if expiresAt.IsZero() {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	if now.Sub(t.lastSweep) > time.Minute {
+		for k, c := range t.tokens {
+			if !now.Before(c.expiresAt) {
+				delete(t.tokens, k)
+			}
+		}
+		t.lastSweep = now
+	}
+	t.tokens[key] = cachedToken{token: token, expiresAt: expiresAt}
+}
+
+// tokenCacheKey returns the key of the token exchanged for the given subject token with the given configuration.
+func tokenCacheKey(config *filterapi.MCPTokenExchange, subjectToken string) string {
+	h := sha256.New()
+	for _, s := range []string{
+		config.TokenEndpoint, config.ClientID, config.Audience, config.Resource,
+		strings.Join(config.Scopes, " "), config.RequestedTokenType, subjectToken,
+	} {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// requestTokenExchange sends the token exchange request defined in RFC 8693 Section 2.1 to the token endpoint.
+func requestTokenExchange(ctx context.Context, client *http.Client, config *filterapi.MCPTokenExchange, subjectToken string) (*tokenExchangeResponse, error) {
+	form := url.Values{
+		"grant_type":         {tokenExchangeGrantType},
+		"subject_token":      {subjectToken},
+		"subject_token_type": {accessTokenType},
+	}
+	if config.RequestedTokenType != "" {
+		form.Set("requested_token_type", config.RequestedTokenType)
+	} else {
+		form.Set("requested_token_type", accessTokenType)
+	}
+	if config.Audience != "" {
+		form.Set("audience", config.Audience)
+	}
+	if config.Resource != "" {
+		form.Set("resource", config.Resource)
+	}
+	if len(config.Scopes) > 0 {
+		form.Set("scope", strings.Join(config.Scopes, " "))
+	}
+	if config.ClientSecret == "" {
+		form.Set("client_id", config.ClientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token exchange request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(config.ClientID), url.QueryEscape(config.ClientSecret))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send token exchange request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token exchange response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp tokenErrorResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, fmt.Errorf("token exchange failed with status %d: %s: %s", resp.StatusCode, errResp.Error, errResp.ErrorDescription)
+		}
+		return nil, fmt.Errorf("token exchange failed with status %d", resp.StatusCode)
+	}
+	var tokenResp tokenExchangeResponse
+	if err = json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, fmt.Errorf("failed to decode token exchange response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return nil, errors.New("token exchange response does not contain an access token")
+	}
+	return &tokenResp, nil
+}
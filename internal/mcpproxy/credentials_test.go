@@ -0,0 +1,165 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+)
+
+func requireJWT(t *testing.T, subject string, expiresAt time.Time) string {
+	claims := jwt.RegisteredClaims{Subject: subject}
+	if !expiresAt.IsZero() {
+		claims.ExpiresAt = jwt.NewNumericDate(expiresAt)
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	require.NoError(t, err)
+	return token
+}
+
+func TestSetBackendCredential(t *testing.T) {
+	subjectToken := requireJWT(t, "alice", time.Now().Add(time.Hour))
+	newProxy := func(authorization string) *mcpRequestContext {
+		proxy := newTestMCPProxy()
+		proxy.tokens = newTokenCache()
+		proxy.requestHeaders = http.Header{}
+		if authorization != "" {
+			proxy.requestHeaders.Set("Authorization", authorization)
+		}
+		return proxy
+	}
+
+	t.Run("no credential", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		require.NoError(t, newProxy("").setBackendCredential(t.Context(), req, filterapi.MCPBackend{Name: "backend1"}))
+		require.Empty(t, req.Header.Get("Authorization"))
+	})
+
+	t.Run("user credentials", func(t *testing.T) {
+		backend := filterapi.MCPBackend{Name: "backend1", Credential: &filterapi.MCPBackendCredential{
+			Header:          "X-Token",
+			UserCredentials: map[string]string{"alice": "alice-pat"},
+		}}
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		require.NoError(t, newProxy("Bearer "+subjectToken).setBackendCredential(t.Context(), req, backend))
+		require.Equal(t, "alice-pat", req.Header.Get("X-Token"))
+
+		err := newProxy("Bearer "+requireJWT(t, "bob", time.Time{})).setBackendCredential(t.Context(), req, backend)
+		require.ErrorIs(t, err, errBackendCredential)
+		require.ErrorContains(t, err, `no credential for subject "bob"`)
+
+		err = newProxy("").setBackendCredential(t.Context(), req, backend)
+		require.ErrorIs(t, err, errBackendCredential)
+		require.ErrorContains(t, err, "missing Authorization header")
+	})
+
+	t.Run("token exchange", func(t *testing.T) {
+		var requests atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			require.NoError(t, r.ParseForm())
+			user, pass, ok := r.BasicAuth()
+			require.True(t, ok)
+			require.Equal(t, "gateway", user)
+			require.Equal(t, "s3cr3t", pass)
+			require.Equal(t, tokenExchangeGrantType, r.PostForm.Get("grant_type"))
+			require.Equal(t, accessTokenType, r.PostForm.Get("subject_token_type"))
+			require.Equal(t, accessTokenType, r.PostForm.Get("requested_token_type"))
+			require.Equal(t, "github", r.PostForm.Get("audience"))
+			require.Equal(t, "repo read:user", r.PostForm.Get("scope"))
+			require.Empty(t, r.PostForm.Get("client_id"))
+			if r.PostForm.Get("subject_token") != subjectToken {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"unknown subject token"}`))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"exchanged","issued_token_type":"urn:ietf:params:oauth:token-type:access_token","token_type":"Bearer","expires_in":300}`))
+		}))
+		t.Cleanup(srv.Close)
+
+		backend := filterapi.MCPBackend{Name: "backend1", Credential: &filterapi.MCPBackendCredential{
+			Header: "Authorization",
+			TokenExchange: &filterapi.MCPTokenExchange{
+				TokenEndpoint: srv.URL,
+				ClientID:      "gateway",
+				ClientSecret:  "s3cr3t",
+				Audience:      "github",
+				Scopes:        []string{"repo", "read:user"},
+			},
+		}}
+		proxy := newProxy("Bearer " + subjectToken)
+		for range 3 {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			require.NoError(t, proxy.setBackendCredential(t.Context(), req, backend))
+			require.Equal(t, "Bearer exchanged", req.Header.Get("Authorization"))
+		}
+		// The exchanged token is cached.
+		require.Equal(t, int32(1), requests.Load())
+
+		// The cached token expires.
+		proxy.tokens.now = func() time.Time { return time.Now().Add(5 * time.Minute) }
+		require.NoError(t, proxy.setBackendCredential(t.Context(), httptest.NewRequest(http.MethodPost, "/", nil), backend))
+		require.Equal(t, int32(2), requests.Load())
+
+		err := newProxy("Bearer "+requireJWT(t, "bob", time.Time{})).setBackendCredential(t.Context(), httptest.NewRequest(http.MethodPost, "/", nil), backend)
+		require.ErrorIs(t, err, errBackendCredential)
+		require.ErrorContains(t, err, "token exchange failed with status 400: invalid_grant: unknown subject token")
+	})
+
+	t.Run("token exchange public client", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, r.ParseForm())
+			_, _, ok := r.BasicAuth()
+			require.False(t, ok)
+			require.Equal(t, "gateway", r.PostForm.Get("client_id"))
+			require.Equal(t, "https://jira.example.com", r.PostForm.Get("resource"))
+			require.Equal(t, "urn:ietf:params:oauth:token-type:jwt", r.PostForm.Get("requested_token_type"))
+			_, _ = w.Write([]byte(`{"access_token":"exchanged","token_type":"N_A"}`))
+		}))
+		t.Cleanup(srv.Close)
+
+		backend := filterapi.MCPBackend{Name: "backend1", Credential: &filterapi.MCPBackendCredential{
+			Header: "X-Jira-Token",
+			TokenExchange: &filterapi.MCPTokenExchange{
+				TokenEndpoint:      srv.URL,
+				ClientID:           "gateway",
+				Resource:           "https://jira.example.com",
+				RequestedTokenType: "urn:ietf:params:oauth:token-type:jwt",
+			},
+		}}
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		require.NoError(t, newProxy("Bearer "+subjectToken).setBackendCredential(t.Context(), req, backend))
+		require.Equal(t, "exchanged", req.Header.Get("X-Jira-Token"))
+	})
+}
+
+func TestTokenCache_expiresAt(t *testing.T) {
+	now := time.Now()
+	c := newTokenCache()
+	c.now = func() time.Time { return now }
+
+	noExp := requireJWT(t, "alice", time.Time{})
+	require.Equal(t, now.Add(5*time.Minute-tokenExpirySkew), c.expiresAt(300, noExp))
+	// Without a lifetime, the token is not cached.
+	require.True(t, c.expiresAt(0, noExp).IsZero())
+	require.True(t, c.expiresAt(10, noExp).IsZero())
+
+	// The token is cached at most until the subject token expires.
+	exp := now.Add(time.Minute).Truncate(time.Second)
+	withExp := requireJWT(t, "alice", exp)
+	require.Equal(t, exp, c.expiresAt(300, withExp))
+	require.Equal(t, exp, c.expiresAt(0, withExp))
+	require.Equal(t, now.Add(10*time.Second+30*time.Second-tokenExpirySkew), c.expiresAt(40, withExp))
+}
@@ -158,6 +158,7 @@ func (m *mcpRequestContext) serveGET(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("transfer-encoding", "chunked")
 	w.WriteHeader(http.StatusAccepted)
+	s.replayEvents(r.Context(), w, lastEventID)
 	if err := s.streamNotifications(r.Context(), w, m.toolChangeSignaler); err != nil && !errors.Is(err, context.Canceled) {
 		m.l.Error("failed to collect notifications", slog.String("session_id", sessionID), slog.String("error", err.Error()))
 		http.Error(w, "failed to collect notifications", http.StatusInternalServerError)
@@ -180,6 +181,11 @@ func (m *mcpRequestContext) serverDELETE(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	_ = s.Close() // Ignore error as it's not recoverable here. Errors per backend are logged in Close().
+	if m.sessionStore != nil {
+		if err = m.sessionStore.DeleteSession(r.Context(), sessionStoreKey(s.id)); err != nil {
+			m.l.Error("failed to delete MCP session state", slog.String("error", err.Error()))
+		}
+	}
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -210,6 +216,7 @@ func (m *mcpRequestContext) servePOST(w http.ResponseWriter, r *http.Request) {
 		params           mcp.Params
 		applicationError bool
 		result           handlerResult
+		rawParams        []byte // params as sent by the client, since the handlers rewrite them.
 	)
 	defer func() {
 		if m.l.Enabled(ctx, slog.LevelDebug) {
@@ -218,6 +225,7 @@ func (m *mcpRequestContext) servePOST(w http.ResponseWriter, r *http.Request) {
 				slog.String("error_type", string(errType)),
 				slog.String("duration", time.Since(startAt).String()))
 		}
+		m.recordAudit(ctx, r, s, requestMethod, rawParams, result.backendName, err, errType, startAt)
 
 		// Some request methods (e.g. notifications/initialized, tools/list) record per-backend
 		// metrics inside their own handlers, or don't involve backends at all. In those cases,
@@ -318,6 +326,7 @@ func (m *mcpRequestContext) servePOST(w http.ResponseWriter, r *http.Request) {
 		}
 	case *jsonrpc.Request:
 		requestMethod = msg.Method
+		rawParams = msg.Params
 		if m.l.Enabled(ctx, slog.LevelDebug) {
 			m.l.Debug("Decoded MCP request",
 				slog.Any("id", msg.ID), slog.String("method", msg.Method), slog.String("params", string(msg.Params)))
@@ -533,6 +542,19 @@ func errorType(err error) metrics.MCPErrorType {
 	}
 
 	// Check for specific error types
+	var rateLimitErr *errRateLimited
+	if errors.As(err, &rateLimitErr) {
+		return metrics.MCPErrorRateLimited
+	}
+	if errors.Is(err, errInvalidToolArguments) {
+		return metrics.MCPErrorInvalidToolArguments
+	}
+	if errors.Is(err, errInvalidToolResult) {
+		return metrics.MCPErrorInvalidToolResult
+	}
+	if errors.Is(err, errBackendUnavailable) {
+		return metrics.MCPErrorBackendUnavailable
+	}
 	if errors.Is(err, errBackendNotFound) || errors.Is(err, errSessionNotFound) || errors.Is(err, errInvalidToolName) {
 		return metrics.MCPErrorInvalidParam
 	}
@@ -571,10 +593,10 @@ func (m *mcpRequestContext) handleInitializeRequest(ctx context.Context, w http.
 		return err
 	}
 
-	result := mcp.InitializeResult{ProtocolVersion: protocolVersion20250618, ServerInfo: &mcp.Implementation{}}
+	result := mcp.InitializeResult{ProtocolVersion: negotiateProtocolVersion(p.ProtocolVersion), ServerInfo: &mcp.Implementation{}}
 	result.ServerInfo.Name = "envoy-ai-gateway"
 	result.ServerInfo.Version = version.Parse()
-	result.Capabilities = s.mergedCapabilities()
+	result.Capabilities = downgradeServerCapabilities(s.mergedCapabilities(), result.ProtocolVersion)
 
 	marshal, err := json.Marshal(result)
 	if err != nil {
@@ -606,16 +628,35 @@ func (m *mcpRequestContext) handleInitializeRequest(ctx context.Context, w http.
 // handleClientToServerResponse handles the response from client to server.
 //
 // The idea is that the request ID is constructed in maybeServerToClientRequestModify to include the original request ID, type, backend name and path prefix.
-// So here we need to parse the ID and restore the original ID before sending it to the backend.
+// So here we need to decrypt the ID and restore the original ID before sending it to the backend.
 func (m *mcpRequestContext) handleClientToServerResponse(ctx context.Context, s *session, w http.ResponseWriter, res *jsonrpc.Response) (handlerResult, error) {
-	clientToServer, ok := res.ID.Raw().(string)
+	encryptedID, ok := res.ID.Raw().(string)
 	// We should've modified the server->client request ID to include the backend name.
 	if !ok {
 		onErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid response ID type: %v", res.ID.Raw()))
 		return handlerResult{}, errors.New("invalid response ID type")
 	}
-	// TODO: we might want to encrypt/sign the ID to prevent tampering just like session in maybeServerToClientRequestModify.
-	//		If we do that, we need to decrypt/verify it here.
+	// The ID can only be decrypted if it was issued by the gateway, so the client cannot redirect the response to
+	// another backend by tampering with it.
+	clientToServer, err := m.sessionCrypto.Decrypt(encryptedID)
+	if err != nil {
+		m.metrics.RecordSecurityEvent(ctx, metrics.MCPSecurityEventInvalidRequestID, nil)
+		onErrorResponse(w, http.StatusBadRequest, "invalid response ID")
+		return handlerResult{}, fmt.Errorf("failed to decrypt response ID: %w", err)
+	}
+	// When the session state is stored, each server->client request can only be responded to once.
+	if m.sessionStore != nil {
+		pending, err := m.sessionStore.TakePendingRequest(ctx, sessionStoreKey(s.id), encryptedID)
+		if err != nil {
+			onErrorResponse(w, http.StatusInternalServerError, "failed to verify response ID")
+			return handlerResult{}, fmt.Errorf("failed to verify response ID: %w", err)
+		}
+		if !pending {
+			m.metrics.RecordSecurityEvent(ctx, metrics.MCPSecurityEventUnknownRequestID, nil)
+			onErrorResponse(w, http.StatusBadRequest, "unknown response ID")
+			return handlerResult{}, errors.New("unknown response ID")
+		}
+	}
 	parts := strings.Split(clientToServer, nameSeparator)
 	if len(parts) != 3 {
 		onErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid response ID format: %s", clientToServer))
@@ -700,6 +741,11 @@ func (m *mcpRequestContext) handleClientToServerResponse(ctx context.Context, s
 }
 
 func (m *mcpRequestContext) handleToolCallRequest(ctx context.Context, s *session, w http.ResponseWriter, req *jsonrpc.Request, p *mcp.CallToolParams, span tracingapi.MCPSpan, r *http.Request) (handlerResult, error) {
+	if route := m.routes[s.route]; route != nil && route.toolSearch != nil {
+		if ok, result, err := m.handleMetaToolCall(ctx, s, w, req, p, span, r, route.toolSearch.MaxResults); ok {
+			return result, err
+		}
+	}
 	backendName, toolName, err := upstreamResourceName(p.Name)
 	if err != nil {
 		onErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid tool name %s: %v", p.Name, err))
@@ -761,6 +807,30 @@ func (m *mcpRequestContext) handleToolCallRequest(ctx context.Context, s *sessio
 		return result, fmt.Errorf("%w: no MCP session found for backend %s", errSessionNotFound, backendName)
 	}
 
+	// Fail fast instead of waiting for a backend that is known to be unhealthy.
+	if !route.health.available(backendName) {
+		onErrorResponse(w, http.StatusServiceUnavailable, fmt.Sprintf("backend %s is unavailable", backendName))
+		return result, fmt.Errorf("%w: %s", errBackendUnavailable, backendName)
+	}
+
+	// Reject the calls with arguments that the backend would not accept, as advertised in the tool schema.
+	if err = m.validateToolArguments(s.route, route, backendName, toolName, p); err != nil {
+		onInvalidToolArgumentsResponse(w, req.ID, err)
+		return result, err
+	}
+
+	// Enforce the rate and concurrency limits of the route before forwarding the call.
+	release, err := route.rateLimiter.acquire(backendName, toolName, r)
+	if err != nil {
+		var rateLimitErr *errRateLimited
+		if errors.As(err, &rateLimitErr) {
+			m.metrics.WithBackend(backendName).RecordRateLimited(ctx, req.Method, rateLimitErr.limitType, p)
+			onRateLimitedResponse(w, req.ID, rateLimitErr)
+		}
+		return result, err
+	}
+	defer release()
+
 	// Send the request to the MCP backend listener.
 	p.Name = toolName
 	param, _ := json.Marshal(p)
@@ -810,10 +880,16 @@ func (m *mcpRequestContext) proxyResponseBody(ctx context.Context, s *session, w
 			var responseError error
 			switch msg := _msg.(type) {
 			case *jsonrpc.Request:
+				if !m.applyServerRequestPolicy(ctx, s, msg, backend.Name) {
+					// The request was denied and answered by the gateway, so there is nothing to send to the client.
+					w.WriteHeader(http.StatusAccepted)
+					return nil
+				}
 				if err = m.maybeServerToClientRequestModify(ctx, msg, backend.Name); err != nil {
 					m.l.Error("failed to modify server->client request", slog.String("error", err.Error()))
 					return err
 				}
+				m.storePendingRequest(ctx, s, msg)
 				body, _ = jsonrpc.EncodeMessage(msg)
 			case *jsonrpc.Response:
 				if req != nil {
@@ -829,11 +905,16 @@ func (m *mcpRequestContext) proxyResponseBody(ctx context.Context, s *session, w
 					} else if toolErr := checkToolCallError(req, msg, backend.Name); toolErr != nil {
 						// Check if this is a tools/call response with isError=true
 						responseError = toolErr
+					} else if err = m.maybeValidateToolResult(s, req, msg, backend.Name); err != nil {
+						// The result has been replaced with an error response.
+						responseError = err
 					}
+					m.maybeDowngradeResult(req, msg)
 
 					body, _ = jsonrpc.EncodeMessage(msg)
 				}
 				m.recordResponse(ctx, msg)
+				m.maybeInvalidateToolSearchIndex(s, msg)
 			}
 
 			// We need to update the content length since we might have modified the ID.
@@ -878,13 +959,20 @@ func (m *mcpRequestContext) proxyResponseBody(ctx context.Context, s *session, w
 					slog.String("event_id", event.id))
 			}
 
+			messages := event.messages[:0]
 			for _, _msg := range event.messages {
+				if msg, ok := _msg.(*jsonrpc.Request); ok && !m.applyServerRequestPolicy(ctx, s, msg, backend.Name) {
+					// The request was denied and answered by the gateway.
+					continue
+				}
+				messages = append(messages, _msg)
 				switch msg := _msg.(type) {
 				case *jsonrpc.Request:
 					if err = m.maybeServerToClientRequestModify(ctx, msg, backend.Name); err != nil {
 						m.l.Error("failed to modify server->client request", slog.String("error", err.Error()))
 						continue
 					}
+					m.storePendingRequest(ctx, s, msg)
 				case *jsonrpc.Response:
 					// Correct the ID to match the original request if possible.
 					if req != nil {
@@ -901,12 +989,21 @@ func (m *mcpRequestContext) proxyResponseBody(ctx context.Context, s *session, w
 						} else if toolErr := checkToolCallError(req, msg, backend.Name); toolErr != nil {
 							// Check if this is a tools/call response with isError=true
 							responseErrors = append(responseErrors, toolErr)
+						} else if err = m.maybeValidateToolResult(s, req, msg, backend.Name); err != nil {
+							// The result has been replaced with an error response.
+							responseErrors = append(responseErrors, err)
 						}
+						m.maybeDowngradeResult(req, msg)
 					}
 					m.recordResponse(ctx, msg)
+					m.maybeInvalidateToolSearchIndex(s, msg)
 				}
 			}
-			event.writeAndMaybeFlush(w)
+			// Nothing is sent to the client when all the messages were denied.
+			if len(messages) > 0 || len(event.messages) == 0 {
+				event.messages = messages
+				event.writeAndMaybeFlush(w)
+			}
 		}
 		if err != nil {
 			if errors.Is(err, io.EOF) || strings.Contains(err.Error(), "context deadline exceeded") {
@@ -922,11 +1019,13 @@ func (m *mcpRequestContext) proxyResponseBody(ctx context.Context, s *session, w
 // https://modelcontextprotocol.io/specification/2025-06-18/basic/utilities/progress#progress
 const progressTokenMetadataKey = "progressToken"
 
-func (m *mcpRequestContext) maybeUpdateProgressTokenMetadata(ctx context.Context, meta mcp.Meta, backendName filterapi.MCPBackendName) bool {
-	// TODO: maybe enctrypt/sign the progress token to prevent tampering just like session/event ID.
+// maybeUpdateProgressTokenMetadata replaces the progress token in the given metadata, if any, with an encrypted token
+// including the backend name, so that the progress notifications sent by the client can be routed to the backend.
+// It returns true if the metadata was modified.
+func (m *mcpRequestContext) maybeUpdateProgressTokenMetadata(ctx context.Context, meta mcp.Meta, backendName filterapi.MCPBackendName) (bool, error) {
 	originalPt, ok := meta[progressTokenMetadataKey]
 	if !ok {
-		return false
+		return false, nil
 	}
 	var newPt string
 	switch v := originalPt.(type) {
@@ -942,17 +1041,21 @@ func (m *mcpRequestContext) maybeUpdateProgressTokenMetadata(ctx context.Context
 		binary.LittleEndian.PutUint64(b, math.Float64bits(v))
 		newPt = fmt.Sprintf("%x%sf%s%s", b, nameSeparator, nameSeparator, backendName)
 	case nil:
-		return false // Valid per spec.
+		return false, nil // Valid per spec.
 	default:
 		m.l.Warn("TODO/BUG: unsupported progressToken type in metadata", slog.String("type", fmt.Sprintf("%T", v)))
-		return false
+		return false, nil
 	}
 
-	meta["progressToken"] = newPt
+	encrypted, err := m.sessionCrypto.Encrypt(newPt)
+	if err != nil {
+		return false, fmt.Errorf("failed to encrypt progressToken: %w", err)
+	}
+	meta[progressTokenMetadataKey] = encrypted
 	if m.l.Enabled(ctx, slog.LevelDebug) {
 		m.l.Debug("Modified progressToken in metadata", slog.Any("old_progress_token", originalPt), slog.String("new_progress_token", newPt), slog.String("backend", backendName))
 	}
-	return true
+	return true, nil
 }
 
 // maybeResponseModify modifies the client->server response to include the backend name where needed.
@@ -985,7 +1088,9 @@ func (m *mcpRequestContext) maybeServerToClientRequestModify(ctx context.Context
 			if err := json.Unmarshal(msg.Params, params); err != nil {
 				return fmt.Errorf("failed to unmarshal roots/list params: %w", err)
 			}
-			if m.maybeUpdateProgressTokenMetadata(ctx, params.Meta, backend) {
+			if modified, err := m.maybeUpdateProgressTokenMetadata(ctx, params.Meta, backend); err != nil {
+				return err
+			} else if modified {
 				msg.Params, _ = json.Marshal(params) // Already decoded params, so ignore error.
 			}
 		}
@@ -995,7 +1100,9 @@ func (m *mcpRequestContext) maybeServerToClientRequestModify(ctx context.Context
 			if err := json.Unmarshal(msg.Params, params); err != nil {
 				return fmt.Errorf("failed to unmarshal sampling/createMessage params: %w", err)
 			}
-			if m.maybeUpdateProgressTokenMetadata(ctx, params.Meta, backend) {
+			if modified, err := m.maybeUpdateProgressTokenMetadata(ctx, params.Meta, backend); err != nil {
+				return err
+			} else if modified {
 				msg.Params, _ = json.Marshal(params) // Already decoded params, so ignore error.
 			}
 		}
@@ -1005,7 +1112,9 @@ func (m *mcpRequestContext) maybeServerToClientRequestModify(ctx context.Context
 			if err := json.Unmarshal(msg.Params, params); err != nil {
 				return fmt.Errorf("failed to unmarshal elicitation/create params: %w", err)
 			}
-			if m.maybeUpdateProgressTokenMetadata(ctx, params.Meta, backend) {
+			if modified, err := m.maybeUpdateProgressTokenMetadata(ctx, params.Meta, backend); err != nil {
+				return err
+			} else if modified {
 				msg.Params, _ = json.Marshal(params) // Already decoded params, so ignore error.
 			}
 		}
@@ -1041,13 +1150,17 @@ func (m *mcpRequestContext) maybeServerToClientRequestModify(ctx context.Context
 	default:
 		return fmt.Errorf("BUG/TODO: unsupported id type %T in the server->client request", v)
 	}
-	// TODO: we might want to encrypt/sign the ID to prevent tampering just like session/event ID.
-	newID, err := jsonrpc.MakeID(prefixedID)
+	// The ID is encrypted so that the client can neither read nor tamper with it. See handleClientToServerResponse.
+	encryptedID, err := m.sessionCrypto.Encrypt(prefixedID)
 	if err != nil {
-		return fmt.Errorf("failed to make new ID %q: %w", prefixedID, err)
+		return fmt.Errorf("failed to encrypt server->client request ID: %w", err)
+	}
+	newID, err := jsonrpc.MakeID(encryptedID)
+	if err != nil {
+		return fmt.Errorf("failed to make new ID %q: %w", encryptedID, err)
 	}
 	if m.l.Enabled(ctx, slog.LevelDebug) {
-		m.l.Debug("Modified server->client request ID", slog.Any("old_id", msg.ID), slog.Any("new_id", newID), slog.String("backend", backend))
+		m.l.Debug("Modified server->client request ID", slog.Any("old_id", msg.ID), slog.String("new_id", prefixedID), slog.String("backend", backend))
 	}
 	msg.ID = newID
 	return nil
@@ -1186,7 +1299,11 @@ func (m *mcpRequestContext) handleResourcesSubscriptionRequest(ctx context.Conte
 		span.RecordRouteToBackend(backend.Name, string(cse.sessionID), false)
 	}
 	req.Params = param
-	return result, m.invokeAndProxyResponse(ctx, s, w, backend, cse, req, p)
+	if err = m.invokeAndProxyResponse(ctx, s, w, backend, cse, req, p); err != nil {
+		return result, err
+	}
+	m.storeSubscription(ctx, s, req.Method, uri)
+	return result, nil
 }
 
 var emptyJSONRPCMessage = []byte(`{}`)
@@ -1372,11 +1489,18 @@ func (m *mcpRequestContext) handleCompletionComplete(ctx context.Context, s *ses
 //
 // The progressToken contains the backend name and path prefix, so we can use that to route the notification to the correct backend.
 func (m *mcpRequestContext) handleClientToServerNotificationsProgress(ctx context.Context, s *session, w http.ResponseWriter, req *jsonrpc.Request, p *mcp.ProgressNotificationParams, span tracingapi.MCPSpan) (handlerResult, error) {
-	pt, ok := p.ProgressToken.(string)
+	encryptedPt, ok := p.ProgressToken.(string)
 	if !ok {
 		onErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid progressToken type %T", p.ProgressToken))
 		return handlerResult{}, fmt.Errorf("invalid progressToken type %T", p.ProgressToken)
 	}
+	// The token was encrypted in maybeUpdateProgressTokenMetadata, so a client cannot forge it to reach another backend.
+	pt, err := m.sessionCrypto.Decrypt(encryptedPt)
+	if err != nil {
+		m.metrics.RecordSecurityEvent(ctx, metrics.MCPSecurityEventInvalidProgressToken, p)
+		onErrorResponse(w, http.StatusBadRequest, "invalid progressToken")
+		return handlerResult{}, fmt.Errorf("failed to decrypt progressToken: %w", err)
+	}
 
 	parts := strings.Split(pt, nameSeparator)
 	if len(parts) != 3 {
@@ -1448,7 +1572,11 @@ func (m *mcpRequestContext) handleClientToServerNotificationsProgress(ctx contex
 func (m *mcpRequestContext) invokeAndProxyResponse(ctx context.Context, s *session, w http.ResponseWriter, backend filterapi.MCPBackend, sess *compositeSessionEntry, req *jsonrpc.Request, params mcp.Params) error {
 	resp, err := m.invokeJSONRPCRequest(ctx, s.route, backend, sess, req, params)
 	if err != nil {
-		onErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("call to %s failed: %v", backend.Name, err))
+		status := http.StatusInternalServerError
+		if errors.Is(err, errBackendCredential) {
+			status = http.StatusForbidden
+		}
+		onErrorResponse(w, status, fmt.Sprintf("call to %s failed: %v", backend.Name, err))
 		return err
 	}
 	defer func() {
@@ -1511,7 +1639,11 @@ func sendToAllBackendsAndAggregateResponses[responseType any, paramsType mcp.Par
 
 	encoded, _ := json.Marshal(p)
 	request.Params = encoded
-	backendMsgs := s.sendToBackendsFiltered(ctx, http.MethodPost, request, p, span, filter)
+	// The ejected backends are left out of the aggregated responses instead of being waited for.
+	health := m.backendHealth(s.route)
+	backendMsgs := s.sendToBackendsFiltered(ctx, http.MethodPost, request, p, span, func(cse *compositeSessionEntry) bool {
+		return (filter == nil || filter(cse)) && health.available(cse.backendName)
+	})
 	return sendToAllBackendsAndAggregateResponsesImpl(ctx, backendMsgs, m, w, s, request, p, mergeFn)
 }
 
@@ -1562,15 +1694,23 @@ func sendToAllBackendsAndAggregateResponsesImpl[responseType any, paramsType mcp
 				event.messages = event.messages[:l-1]
 			}
 			// We need to write any remaining events to the client.
+			messages := event.messages[:0]
 			for _, msg := range event.messages {
 				if reqMsg, ok := msg.(*jsonrpc.Request); ok {
+					if !m.applyServerRequestPolicy(ctx, s, reqMsg, event.backend) {
+						// The request was denied and answered by the gateway.
+						continue
+					}
 					if err := m.maybeServerToClientRequestModify(ctx, reqMsg, event.backend); err != nil {
 						logger.Error("failed to modify server->client request", slog.String("error", err.Error()))
 						onErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to modify server->client request: %v", err))
 						return fmt.Errorf("failed to modify server->client request: %w", err)
 					}
+					m.storePendingRequest(ctx, s, reqMsg)
 				}
+				messages = append(messages, msg)
 			}
+			event.messages = messages
 			if len(event.messages) > 0 {
 				event.writeAndMaybeFlush(w)
 			}
@@ -1619,8 +1759,11 @@ func parseParamsAndMaybeStartSpan[paramType mcp.Params](ctx context.Context, m *
 
 // handleToolsListRequest handles the "tools/list" JSON-RPC method.
 //
-// This aggregates and returns the list of tools from all backends.
+// This aggregates and returns the list of tools from all backends, or the meta-tools in the tool search mode.
 func (m *mcpRequestContext) handleToolsListRequest(ctx context.Context, s *session, w http.ResponseWriter, req *jsonrpc.Request, p *mcp.ListToolsParams, span tracingapi.MCPSpan) error {
+	if route := m.routes[s.route]; route != nil && route.toolSearch != nil {
+		return m.handleToolSearchListRequest(w, req)
+	}
 	// TODO: use cursor for pagination, but in spec it's "SHOULD" not "MUST".
 	return sendToAllBackendsAndAggregateResponses(ctx, m, w, s, req, p, m.mergeToolsList, span,
 		func(cse *compositeSessionEntry) bool { return cse.capabilities != nil && cse.capabilities.Tools != nil })
@@ -1674,6 +1817,7 @@ func (m *mcpRequestContext) mergeToolsList(s *session, responses []broadCastResp
 		return resp
 	}
 
+	version := m.clientProtocolVersion()
 	// Aggregate the tools from all responses.
 	// A backend specific prefix is added to the tool name to avoid name collision.
 	// The tools are filtered based on the toolFilters configured for each backend,
@@ -1684,18 +1828,14 @@ func (m *mcpRequestContext) mergeToolsList(s *session, responses []broadCastResp
 			if selector != nil && !selector.allows(tool.Name) {
 				continue
 			}
-			if route.authorization != nil {
-				allowed, _ := m.authorizeRequest(route.authorization, &authorizationRequest{
-					Headers:   m.requestHeaders,
-					MCPMethod: "tools/call",
-					Backend:   r.backendName,
-					Tool:      tool.Name,
-				})
-				if !allowed {
-					continue
-				}
+			if !m.authorizesToolCall(route, r.backendName, tool.Name) {
+				continue
+			}
+			if err := m.toolSchemas.update(s.route, r.backendName, tool); err != nil {
+				m.l.Warn("tool calls will not be fully validated", slog.String("backend", r.backendName), slog.String("error", err.Error()))
 			}
 			tool.Name = downstreamResourceName(tool.Name, r.backendName)
+			downgradeTool(tool, version)
 			resp.Tools = append(resp.Tools, tool)
 		}
 	}
@@ -1703,6 +1843,21 @@ func (m *mcpRequestContext) mergeToolsList(s *session, responses []broadCastResp
 	return resp
 }
 
+// authorizesToolCall returns true if the authorization rules of the route allow the client of the request to call the
+// given tool of the backend.
+func (m *mcpRequestContext) authorizesToolCall(route *mcpProxyConfigRoute, backend filterapi.MCPBackendName, tool string) bool {
+	if route.authorization == nil {
+		return true
+	}
+	allowed, _ := m.authorizeRequest(route.authorization, &authorizationRequest{
+		Headers:   m.requestHeaders,
+		MCPMethod: "tools/call",
+		Backend:   backend,
+		Tool:      tool,
+	})
+	return allowed
+}
+
 // mergeResourceList merges the list of resources from all backends and prepare the response message to be sent back to the client.
 func (m *mcpRequestContext) mergeResourceList(_ *session, responses []broadCastResponse[mcp.ListResourcesResult]) mcp.ListResourcesResult {
 	// Aggregate the resources from all responses with some logic to match the actual proxy behavior.
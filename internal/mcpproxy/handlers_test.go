@@ -80,6 +80,22 @@ func newTestMCPProxyWithTracer(t tracingapi.MCPTracer) *mcpRequestContext {
 	}
 }
 
+// requireEncrypt encrypts the given plaintext with the session crypto of the test proxies.
+func requireEncrypt(t *testing.T, plaintext string) string {
+	encrypted, err := NewPBKDF2AesGcmSessionCrypto("test", 100).Encrypt(plaintext)
+	require.NoError(t, err)
+	return encrypted
+}
+
+// requireDecrypt decrypts the given ciphertext with the session crypto of the test proxies.
+func requireDecrypt(t *testing.T, encrypted any) string {
+	s, ok := encrypted.(string)
+	require.True(t, ok, "expected a string, got %T", encrypted)
+	decrypted, err := NewPBKDF2AesGcmSessionCrypto("test", 100).Decrypt(s)
+	require.NoError(t, err)
+	return decrypted
+}
+
 func newTestMCPProxyWithOTEL(mr *sdkmetric.ManualReader, tracer tracingapi.MCPTracer) *mcpRequestContext {
 	mcpProxy := newTestMCPProxyWithTracer(tracer)
 	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(mr)).Meter("test")
@@ -423,7 +439,7 @@ func TestServePOST_JSONRPCRequest(t *testing.T) {
 			method:           "notifications/progress",
 			upstreamResponse: `{"jsonrpc":"2.0","id":"1","result":{"completion": {"values":["completed text"]}}}`,
 			expStatusCode:    200,
-			params:           &mcp.ProgressNotificationParams{ProgressToken: "1234__i__backend1"},
+			params:           &mcp.ProgressNotificationParams{ProgressToken: requireEncrypt(t, "1234__i__backend1")},
 		},
 		{
 			name:                 "notifications/progress invalid param",
@@ -437,7 +453,7 @@ func TestServePOST_JSONRPCRequest(t *testing.T) {
 			method:               "notifications/progress",
 			expStatusCode:        400,
 			params:               &mcp.ProgressNotificationParams{ProgressToken: "invalid-token"},
-			expBodyOnNonOKStatus: `invalid progressToken invalid-token`,
+			expBodyOnNonOKStatus: `invalid progressToken`,
 		},
 		{
 			method:        "notifications/initialized",
@@ -750,6 +766,71 @@ func TestServePOST_ToolsCallRequest(t *testing.T) {
 	}
 }
 
+func TestServePOST_ToolsCallRequest_RateLimited(t *testing.T) {
+	backendCalls := 0
+	backendServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		backendCalls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":"1","result":"success"}`))
+	}))
+	t.Cleanup(backendServer.Close)
+
+	mr := sdkmetric.NewManualReader()
+	proxy := newTestMCPProxyWithOTEL(mr, noopTracer)
+	proxy.backendListenerAddr = backendServer.URL
+	limiter, err := newRateLimiter([]filterapi.MCPRateLimitRule{
+		{Backend: "backend1", Tool: "test-tool", Requests: &filterapi.MCPRateLimitValue{Limit: 1, Unit: "Hour"}},
+	}, nil)
+	require.NoError(t, err)
+	proxy.routes["test-route"].rateLimiter = limiter
+
+	sessionID := secureID(t, proxy, "test-route@@backend1:dGVzdC1zZXNzaW9u") // "test-session" base64 encoded.
+	newReq := func() *http.Request {
+		id, err := jsonrpc.MakeID("backend1__test-tool")
+		require.NoError(t, err)
+		paramsData, err := json.Marshal(&mcp.CallToolParams{Name: "backend1__test-tool"})
+		require.NoError(t, err)
+		body, err := jsonrpc.EncodeMessage(&jsonrpc.Request{Method: "tools/call", ID: id, Params: paramsData})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(internalapi.MCPRouteHeader, "test-route")
+		req.Header.Set(sessionIDHeader, sessionID)
+		return req
+	}
+
+	rr := httptest.NewRecorder()
+	proxy.servePOST(rr, newReq())
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, 1, backendCalls)
+
+	rr = httptest.NewRecorder()
+	proxy.servePOST(rr, newReq())
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, 1, backendCalls)
+	require.Equal(t, "3600", rr.Header().Get("Retry-After"))
+	msg, err := jsonrpc.DecodeMessage(rr.Body.Bytes())
+	require.NoError(t, err)
+	resp, ok := msg.(*jsonrpc.Response)
+	require.True(t, ok)
+	var wireErr *jsonrpc.Error
+	require.ErrorAs(t, resp.Error, &wireErr)
+	require.Equal(t, int64(jsonrpcCodeRateLimited), wireErr.Code)
+
+	rateLimited := testotel.GetCounterValue(t, mr, "mcp.rate_limited.requests", attribute.NewSet(
+		attribute.String("mcp.backend", "backend1"),
+		attribute.String("mcp.method.name", "tools/call"),
+		attribute.String("mcp.rate_limit.type", "rate"),
+	))
+	require.Equal(t, 1, int(rateLimited))
+	count, _ := testotel.GetHistogramValues(t, mr, "mcp.request.duration", attribute.NewSet(
+		attribute.String("mcp.backend", "backend1"),
+		attribute.String("error.type", string(metrics.MCPErrorRateLimited)),
+	))
+	require.Equal(t, 1, int(count)) // nolint: gosec
+}
+
 func TestServePOST_UnsupportedMethod(t *testing.T) {
 	mr := sdkmetric.NewManualReader()
 	proxy := newTestMCPProxyWithOTEL(mr, noopTracer)
@@ -1739,24 +1820,29 @@ func TestMCPPRoxy_handleResourceReadRequest(t *testing.T) {
 func TestMCPProxy_maybeUpdateProgressTokenMetadata(t *testing.T) {
 	proxy := newTestMCPProxy()
 	metadata := mcp.Meta{}
-	require.False(t, proxy.maybeUpdateProgressTokenMetadata(t.Context(), metadata, "backend"))
-	metadata[progressTokenMetadataKey] = struct{}{}
-	require.False(t, proxy.maybeUpdateProgressTokenMetadata(t.Context(), metadata, "backend"))
-	metadata[progressTokenMetadataKey] = nil
-	require.False(t, proxy.maybeUpdateProgressTokenMetadata(t.Context(), metadata, "backend"))
-
-	metadata[progressTokenMetadataKey] = "abcd"
-	require.True(t, proxy.maybeUpdateProgressTokenMetadata(t.Context(), metadata, "backend"))
-	// Base64 encoded "abcd" is "YWJjZA==".
-	require.Equal(t, "YWJjZA==__s__backend", metadata[progressTokenMetadataKey])
-
-	metadata[progressTokenMetadataKey] = 1.1
-	require.True(t, proxy.maybeUpdateProgressTokenMetadata(t.Context(), metadata, "backend"))
-	require.Equal(t, "9a9999999999f13f__f__backend", metadata[progressTokenMetadataKey])
-
-	metadata[progressTokenMetadataKey] = int64(1)
-	require.True(t, proxy.maybeUpdateProgressTokenMetadata(t.Context(), metadata, "backend"))
-	require.Equal(t, "1__i__backend", metadata[progressTokenMetadataKey])
+	for _, pt := range []any{struct{}{}, nil} {
+		if pt != nil {
+			metadata[progressTokenMetadataKey] = pt
+		}
+		modified, err := proxy.maybeUpdateProgressTokenMetadata(t.Context(), metadata, "backend")
+		require.NoError(t, err)
+		require.False(t, modified)
+	}
+
+	for _, tc := range []struct {
+		pt  any
+		exp string
+	}{
+		{pt: "abcd", exp: "YWJjZA==__s__backend"}, // Base64 encoded "abcd" is "YWJjZA==".
+		{pt: 1.1, exp: "9a9999999999f13f__f__backend"},
+		{pt: int64(1), exp: "1__i__backend"},
+	} {
+		metadata[progressTokenMetadataKey] = tc.pt
+		modified, err := proxy.maybeUpdateProgressTokenMetadata(t.Context(), metadata, "backend")
+		require.NoError(t, err)
+		require.True(t, modified)
+		require.Equal(t, tc.exp, requireDecrypt(t, metadata[progressTokenMetadataKey]))
+	}
 }
 
 func TestMCPProxy_handleClientToServerNotificationsProgress(t *testing.T) {
@@ -1770,24 +1856,29 @@ func TestMCPProxy_handleClientToServerNotificationsProgress(t *testing.T) {
 			inputProgressToken: struct{}{},
 			expResponseBody:    `invalid progressToken type struct {}`,
 		},
+		{
+			name:               "not encrypted",
+			inputProgressToken: "12345__i__backend2",
+			expResponseBody:    `invalid progressToken`,
+		},
 		{
 			name:               "invalid format",
-			inputProgressToken: "@@@@@@@@@@@@@@",
+			inputProgressToken: requireEncrypt(t, "@@@@@@@@@@@@@@"),
 			expResponseBody:    `invalid progressToken @@@@@@@@@@@@@@`,
 		},
 		{
 			name:               "string type",
-			inputProgressToken: "YWJjZA==__s__backend1", // base64 encoded "abcd".
+			inputProgressToken: requireEncrypt(t, "YWJjZA==__s__backend1"), // base64 encoded "abcd".
 			expUpstreamToken:   `"progressToken":"abcd"`,
 		},
 		{
 			name:               "float64 type",
-			inputProgressToken: "9a9999999999f13f__f__backend1",
+			inputProgressToken: requireEncrypt(t, "9a9999999999f13f__f__backend1"),
 			expUpstreamToken:   `"progressToken":1.1`,
 		},
 		{
 			name:               "int64 type",
-			inputProgressToken: "12345__i__backend1",
+			inputProgressToken: requireEncrypt(t, "12345__i__backend1"),
 			expUpstreamToken:   `"progressToken":12345`,
 		},
 	} {
@@ -1860,9 +1951,9 @@ func TestMCPProxy_maybeServerToClientRequestModify(t *testing.T) {
 				params := &mcp.ListRootsParams{}
 				require.NoError(t, json.Unmarshal(modified.Params, params))
 				// Check the progress token is updated.
-				require.Equal(t, "0000000000049540__f__backend", params.Meta[progressTokenMetadataKey])
+				require.Equal(t, "0000000000049540__f__backend", requireDecrypt(t, params.Meta[progressTokenMetadataKey]))
 				// Then check the ID: aWQ= is the base64 encoded "id".
-				require.Equal(t, "aWQ=__s__backend", modified.ID.Raw().(string))
+				require.Equal(t, "aWQ=__s__backend", requireDecrypt(t, modified.ID.Raw()))
 			},
 		},
 		{
@@ -1872,10 +1963,10 @@ func TestMCPProxy_maybeServerToClientRequestModify(t *testing.T) {
 				params := &mcp.CreateMessageParams{}
 				require.NoError(t, json.Unmarshal(modified.Params, params))
 				// Check the progress token is updated: cHQ= is the base64 encoded "pt".
-				require.Equal(t, "cHQ=__s__backend", params.Meta[progressTokenMetadataKey])
+				require.Equal(t, "cHQ=__s__backend", requireDecrypt(t, params.Meta[progressTokenMetadataKey]))
 				// Then check the ID: 1 is encoded as 1__i__backend because of the roundtrip issue of the jsonrpc library in MCP SDK.
 				// https://github.com/modelcontextprotocol/go-sdk/blob/5d64d61974982512270b554afd45d053c6dc2fb7/internal/jsonrpc2/messages.go#L32
-				require.Equal(t, "1__i__backend", modified.ID.Raw().(string))
+				require.Equal(t, "1__i__backend", requireDecrypt(t, modified.ID.Raw()))
 			},
 		},
 		{
@@ -1885,10 +1976,10 @@ func TestMCPProxy_maybeServerToClientRequestModify(t *testing.T) {
 				params := &mcp.CreateMessageParams{}
 				require.NoError(t, json.Unmarshal(modified.Params, params))
 				// Check the progress token is updated: cHQ= is the base64 encoded "pt".
-				require.Equal(t, "cHQ=__s__backend", params.Meta[progressTokenMetadataKey])
+				require.Equal(t, "cHQ=__s__backend", requireDecrypt(t, params.Meta[progressTokenMetadataKey]))
 				// Then check the ID: 1 is encoded as 1__i__backend because of the roundtrip issue of the jsonrpc library in MCP SDK.
 				// https://github.com/modelcontextprotocol/go-sdk/blob/5d64d61974982512270b554afd45d053c6dc2fb7/internal/jsonrpc2/messages.go#L32
-				require.Equal(t, "1__i__backend", modified.ID.Raw().(string))
+				require.Equal(t, "1__i__backend", requireDecrypt(t, modified.ID.Raw()))
 			},
 		},
 	} {
@@ -1914,7 +2005,7 @@ func TestMCPProxy_handleClientToServerResponse(t *testing.T) {
 		require.Equal(t, http.StatusBadRequest, rr.Code)
 		require.Contains(t, rr.Body.String(), "invalid response ID type: <nil>")
 
-		invalidID, err := jsonrpc.MakeID("invalidformatid")
+		invalidID, err := jsonrpc.MakeID(requireEncrypt(t, "invalidformatid"))
 		require.NoError(t, err)
 		rr = httptest.NewRecorder()
 		_, err = proxy.handleClientToServerResponse(t.Context(), nil, rr, &jsonrpc.Response{ID: invalidID})
@@ -1922,7 +2013,7 @@ func TestMCPProxy_handleClientToServerResponse(t *testing.T) {
 		require.Equal(t, http.StatusBadRequest, rr.Code)
 		require.Contains(t, rr.Body.String(), "invalid response ID format: invalidformatid")
 
-		invalidID2, err := jsonrpc.MakeID("__foo__")
+		invalidID2, err := jsonrpc.MakeID(requireEncrypt(t, "__foo__"))
 		require.NoError(t, err)
 		rr = httptest.NewRecorder()
 		_, err = proxy.handleClientToServerResponse(t.Context(), nil, rr, &jsonrpc.Response{ID: invalidID2})
@@ -1931,13 +2022,31 @@ func TestMCPProxy_handleClientToServerResponse(t *testing.T) {
 		require.Contains(t, rr.Body.String(), `invalid response ID type identifier`)
 	})
 
-	unknownBackendID, err := jsonrpc.MakeID("aWQ=__s__unknownbackend") // aWQK is the base64 encoded "id".
+	t.Run("tampered ID", func(t *testing.T) {
+		mr := sdkmetric.NewManualReader()
+		proxy := newTestMCPProxyWithOTEL(mr, noopTracer)
+		// A client cannot forge the ID of a response to route it to another backend.
+		for _, raw := range []string{"aWQ=__s__backend2", requireEncrypt(t, "aWQ=__s__backend1") + "AA"} {
+			id, err := jsonrpc.MakeID(raw)
+			require.NoError(t, err)
+			rr := httptest.NewRecorder()
+			_, err = proxy.handleClientToServerResponse(t.Context(), nil, rr, &jsonrpc.Response{ID: id})
+			require.ErrorContains(t, err, "failed to decrypt response ID")
+			require.Equal(t, http.StatusBadRequest, rr.Code)
+			require.Contains(t, rr.Body.String(), "invalid response ID")
+		}
+		require.Equal(t, float64(2), testotel.GetCounterValue(t, mr, "mcp.security.events", attribute.NewSet(
+			attribute.String("mcp.security.event.type", string(metrics.MCPSecurityEventInvalidRequestID)),
+		)))
+	})
+
+	unknownBackendID, err := jsonrpc.MakeID(requireEncrypt(t, "aWQ=__s__unknownbackend")) // aWQK is the base64 encoded "id".
 	require.NoError(t, err)
-	intID, err := jsonrpc.MakeID("1__i__backend1")
+	intID, err := jsonrpc.MakeID(requireEncrypt(t, "1__i__backend1"))
 	require.NoError(t, err)
-	strID, err := jsonrpc.MakeID("aWQ=__s__backend1") // aWQK is the base64 encoded "id".
+	strID, err := jsonrpc.MakeID(requireEncrypt(t, "aWQ=__s__backend1")) // aWQK is the base64 encoded "id".
 	require.NoError(t, err)
-	f64ID, err := jsonrpc.MakeID("9a9999999999f13f__f__backend1")
+	f64ID, err := jsonrpc.MakeID(requireEncrypt(t, "9a9999999999f13f__f__backend1"))
 	require.NoError(t, err)
 	for _, tc := range []struct {
 		name   string
@@ -2239,6 +2348,26 @@ func Test_errorType(t *testing.T) {
 			err:      errInvalidToolName,
 			expected: metrics.MCPErrorInvalidParam,
 		},
+		{
+			name:     "rate limited error",
+			err:      &errRateLimited{limitType: metrics.MCPRateLimitTypeRate, retryAfter: time.Second},
+			expected: metrics.MCPErrorRateLimited,
+		},
+		{
+			name:     "invalid tool arguments error",
+			err:      fmt.Errorf("%w for tool backend1__test-tool: missing properties", errInvalidToolArguments),
+			expected: metrics.MCPErrorInvalidToolArguments,
+		},
+		{
+			name:     "invalid tool result error",
+			err:      fmt.Errorf("%w for tool backend1__test-tool: missing structured content", errInvalidToolResult),
+			expected: metrics.MCPErrorInvalidToolResult,
+		},
+		{
+			name:     "backend unavailable error",
+			err:      fmt.Errorf("%w: backend1", errBackendUnavailable),
+			expected: metrics.MCPErrorBackendUnavailable,
+		},
 		{
 			name:     "wrapped backend not found error",
 			err:      fmt.Errorf("failed to call backend: %w", errBackendNotFound),
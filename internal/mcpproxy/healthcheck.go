@@ -0,0 +1,260 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/version"
+)
+
+// errBackendUnavailable is returned when a request is not sent to a backend because it is ejected.
+var errBackendUnavailable = errors.New("backend unavailable")
+
+type (
+	// backendHealth tracks the health of the backends of a route. A backend is ejected after a number of consecutive
+	// failed requests or active probes, and recovers on the first success once the ejection time has elapsed.
+	//
+	// The health is local to this process, so it is tracked per external processor instance.
+	backendHealth struct {
+		// config, backends and urls are the configuration this tracker was built from, used to preserve the state
+		// across config reloads. urls is the URL to send the requests to for each backend.
+		config   filterapi.MCPHealthCheck
+		backends map[filterapi.MCPBackendName]filterapi.MCPBackend
+		urls     map[filterapi.MCPBackendName]string
+
+		l *slog.Logger
+		// probe sends an active probe to the given backend at the given URL.
+		probe func(ctx context.Context, url string, backend filterapi.MCPBackend) error
+		// onChange is called when a backend is ejected or recovers.
+		onChange func()
+		now      func() time.Time
+
+		states map[filterapi.MCPBackendName]*backendHealthState
+		cancel context.CancelFunc
+		done   chan struct{}
+	}
+
+	// backendHealthState is the circuit breaker of a single backend.
+	backendHealthState struct {
+		mu                  sync.Mutex
+		consecutiveFailures int
+		ejected             bool
+		ejectedUntil        time.Time
+	}
+)
+
+// newBackendHealth returns the health tracker of the given route. It returns nil if the health checking is disabled.
+//
+// If prev was built from the same configuration, it is returned as-is so that the health of the backends survives
+// configuration reloads that don't affect it. Otherwise, the returned tracker must be started.
+func (p *ProxyConfig) newBackendHealth(route *filterapi.MCPRoute, backends map[filterapi.MCPBackendName]filterapi.MCPBackend,
+	urls map[filterapi.MCPBackendName]string, prev *backendHealth,
+) *backendHealth {
+	if route.HealthCheck == nil {
+		return nil
+	}
+	if prev != nil && reflect.DeepEqual(prev.config, *route.HealthCheck) &&
+		reflect.DeepEqual(prev.backends, backends) && maps.Equal(prev.urls, urls) {
+		return prev
+	}
+	h := &backendHealth{
+		config:   *route.HealthCheck,
+		backends: backends,
+		urls:     urls,
+		l:        p.l.With(slog.String("route", route.Name)),
+		probe:    p.pingBackend(route.Name),
+		onChange: func() {
+			// The index is rebuilt with the tools of the backends that recovered.
+			p.toolSearchIndexes.invalidate(route.Name)
+			p.toolChangeSignaler.Signal()
+		},
+		now:    time.Now,
+		states: make(map[filterapi.MCPBackendName]*backendHealthState, len(backends)),
+	}
+	for name := range backends {
+		h.states[name] = &backendHealthState{}
+	}
+	return h
+}
+
+// backendHealth returns the health tracker of the given route, or nil if the health checking is disabled.
+func (m *mcpProxyConfig) backendHealth(routeName filterapi.MCPRouteName) *backendHealth {
+	if m == nil {
+		return nil
+	}
+	if r := m.routes[routeName]; r != nil {
+		return r.health
+	}
+	return nil
+}
+
+// available returns true if requests can be sent to the given backend.
+func (h *backendHealth) available(backend filterapi.MCPBackendName) bool {
+	if h == nil {
+		return true
+	}
+	s := h.states[backend]
+	if s == nil {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Once the ejection time has elapsed, the requests are sent to the backend again to find out whether it recovered.
+	return !s.ejected || !h.now().Before(s.ejectedUntil)
+}
+
+// recordResponse records the outcome of an HTTP request sent to the given backend. The requests that cannot be sent
+// or are answered with a 5xx status code are failures.
+func (h *backendHealth) recordResponse(backend filterapi.MCPBackendName, resp *http.Response, err error) {
+	if h == nil {
+		return
+	}
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			// The client went away, which says nothing about the backend.
+			return
+		}
+		h.record(backend, err)
+		return
+	}
+	if resp.StatusCode >= 500 {
+		h.record(backend, fmt.Errorf("status code %d", resp.StatusCode))
+		return
+	}
+	h.record(backend, nil)
+}
+
+// record records the outcome of a request or an active probe to the given backend, nil meaning a success.
+func (h *backendHealth) record(backend filterapi.MCPBackendName, err error) {
+	s := h.states[backend]
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	var changed bool
+	if err == nil {
+		changed = s.ejected
+		s.ejected = false
+		s.consecutiveFailures = 0
+	} else {
+		s.consecutiveFailures++
+		if s.ejected || s.consecutiveFailures >= h.config.UnhealthyThreshold {
+			changed = !s.ejected
+			s.ejected = true
+			s.ejectedUntil = h.now().Add(h.config.EjectionTime)
+		}
+	}
+	s.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	if err == nil {
+		h.l.Info("MCP backend recovered", slog.String("backend", backend))
+	} else {
+		h.l.Warn("MCP backend ejected", slog.String("backend", backend), slog.String("error", err.Error()),
+			slog.Duration("ejection_time", h.config.EjectionTime))
+	}
+	// The tools of the backend are added to or removed from the tools/list results.
+	h.onChange()
+}
+
+// start starts the active probes of the backends. The probes are stopped with close.
+func (h *backendHealth) start(ctx context.Context) {
+	if h == nil {
+		return
+	}
+	// The probes outlive the config loading, so they are bound to the lifetime of the proxy instead.
+	ctx, h.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	h.done = make(chan struct{})
+	go func() {
+		defer close(h.done)
+		ticker := time.NewTicker(h.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				h.probeAll(ctx)
+			}
+		}
+	}()
+}
+
+// probeAll probes all the backends in parallel and waits for the probes to complete.
+func (h *backendHealth) probeAll(ctx context.Context) {
+	var wg sync.WaitGroup
+	for name, backend := range h.backends {
+		if backend.Credential != nil {
+			// The probes are not made on behalf of a user, so they cannot get the credential of the backend.
+			continue
+		}
+		wg.Go(func() {
+			probeCtx, cancel := context.WithTimeout(ctx, h.config.Timeout)
+			defer cancel()
+			err := h.probe(probeCtx, h.urls[name], backend)
+			if ctx.Err() != nil {
+				// The tracker is closed.
+				return
+			}
+			if err != nil && h.l.Enabled(ctx, slog.LevelDebug) {
+				h.l.Debug("MCP backend probe failed", slog.String("backend", name), slog.String("error", err.Error()))
+			}
+			h.record(name, err)
+		})
+	}
+	wg.Wait()
+}
+
+// close stops the active probes of the backends.
+func (h *backendHealth) close() {
+	if h == nil || h.cancel == nil {
+		return
+	}
+	h.cancel()
+	<-h.done
+}
+
+// pingBackend returns the active probe of the backends of the given route. The probe initializes an MCP session with
+// the backend, sends a ping request and closes the session.
+func (p *ProxyConfig) pingBackend(routeName filterapi.MCPRouteName) func(ctx context.Context, url string, backend filterapi.MCPBackend) error {
+	client := mcp.NewClient(&mcp.Implementation{Name: "envoy-ai-gateway-health-check", Version: version.Parse()}, nil)
+	return func(ctx context.Context, url string, backend filterapi.MCPBackend) error {
+		base := p.client.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		session, err := client.Connect(ctx, &mcp.StreamableClientTransport{
+			Endpoint: url,
+			HTTPClient: &http.Client{Transport: &toolClientTransport{
+				route:   routeName,
+				headers: http.Header{http.CanonicalHeaderKey(internalapi.MCPBackendHeader): {backend.Name}},
+				base:    base,
+			}},
+			DisableStandaloneSSE: true,
+			MaxRetries:           -1,
+		}, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = session.Close() }()
+		return session.Ping(ctx, nil)
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func newTestBackendHealth(t *testing.T, threshold int) (*backendHealth, *time.Time, *atomic.Int32) {
+	p := &ProxyConfig{l: slog.New(slog.DiscardHandler), toolChangeSignaler: newMultiWatcherSignaler()}
+	h := p.newBackendHealth(&filterapi.MCPRoute{
+		Name:        "route",
+		HealthCheck: &filterapi.MCPHealthCheck{Interval: time.Hour, Timeout: time.Second, UnhealthyThreshold: threshold, EjectionTime: time.Minute},
+	}, map[filterapi.MCPBackendName]filterapi.MCPBackend{
+		"backend1": {Name: "backend1"},
+		"backend2": {Name: "backend2"},
+	}, nil, nil)
+	now := time.Now()
+	h.now = func() time.Time { return now }
+	var changes atomic.Int32
+	h.onChange = func() { changes.Add(1) }
+	t.Cleanup(h.close)
+	return h, &now, &changes
+}
+
+func TestNewBackendHealth(t *testing.T) {
+	p := &ProxyConfig{l: slog.New(slog.DiscardHandler), toolChangeSignaler: newMultiWatcherSignaler()}
+	backends := map[filterapi.MCPBackendName]filterapi.MCPBackend{"backend1": {Name: "backend1"}}
+	urls := map[filterapi.MCPBackendName]string{"backend1": "http://127.0.0.1:10088"}
+	route := &filterapi.MCPRoute{Name: "route"}
+	require.Nil(t, p.newBackendHealth(route, backends, urls, nil))
+
+	route.HealthCheck = &filterapi.MCPHealthCheck{Interval: time.Second, Timeout: time.Second, UnhealthyThreshold: 3, EjectionTime: time.Minute}
+	prev := p.newBackendHealth(route, backends, urls, nil)
+	require.NotNil(t, prev)
+	require.Contains(t, prev.states, "backend1")
+
+	same := p.newBackendHealth(&filterapi.MCPRoute{Name: "route", HealthCheck: &filterapi.MCPHealthCheck{
+		Interval: time.Second, Timeout: time.Second, UnhealthyThreshold: 3, EjectionTime: time.Minute,
+	}}, map[filterapi.MCPBackendName]filterapi.MCPBackend{"backend1": {Name: "backend1"}}, urls, prev)
+	require.Same(t, prev, same)
+
+	require.NotSame(t, prev, p.newBackendHealth(route, backends,
+		map[filterapi.MCPBackendName]string{"backend1": "http://127.0.0.1:20000"}, prev))
+	require.NotSame(t, prev, p.newBackendHealth(route,
+		map[filterapi.MCPBackendName]filterapi.MCPBackend{"backend1": {Name: "backend1", Path: "/sse"}}, urls, prev))
+}
+
+func TestBackendHealth_record(t *testing.T) {
+	h, now, changes := newTestBackendHealth(t, 2)
+	failure := errors.New("connection refused")
+
+	h.record("backend1", failure)
+	require.True(t, h.available("backend1"))
+	h.record("backend1", nil) // The successes reset the consecutive failures.
+	h.record("backend1", failure)
+	require.True(t, h.available("backend1"))
+	require.Zero(t, changes.Load())
+
+	h.record("backend1", failure)
+	require.False(t, h.available("backend1"))
+	require.True(t, h.available("backend2"))
+	require.True(t, h.available("unknown"))
+	require.Equal(t, int32(1), changes.Load())
+
+	// Once the ejection time has elapsed, the backend is tried again, and ejected again on the first failure.
+	*now = now.Add(time.Minute)
+	require.True(t, h.available("backend1"))
+	h.record("backend1", failure)
+	require.False(t, h.available("backend1"))
+	require.Equal(t, int32(1), changes.Load())
+
+	*now = now.Add(time.Minute)
+	h.record("backend1", nil)
+	require.True(t, h.available("backend1"))
+	require.Equal(t, int32(2), changes.Load())
+
+	var disabled *backendHealth
+	require.True(t, disabled.available("backend1"))
+	disabled.recordResponse("backend1", nil, failure)
+}
+
+func TestBackendHealth_recordResponse(t *testing.T) {
+	h, _, _ := newTestBackendHealth(t, 1)
+
+	h.recordResponse("backend1", nil, context.Canceled)
+	require.True(t, h.available("backend1"))
+	h.recordResponse("backend1", &http.Response{StatusCode: http.StatusNotFound}, nil)
+	require.True(t, h.available("backend1"))
+	h.recordResponse("backend1", &http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+	require.False(t, h.available("backend1"))
+
+	h.recordResponse("backend2", nil, errors.New("connection refused"))
+	require.False(t, h.available("backend2"))
+}
+
+func TestBackendHealth_probeAll(t *testing.T) {
+	h, _, changes := newTestBackendHealth(t, 1)
+	h.backends["backend3"] = filterapi.MCPBackend{
+		Name:       "backend3",
+		Credential: &filterapi.MCPBackendCredential{UserCredentials: map[string]string{"user": "token"}},
+	}
+	h.states["backend3"] = &backendHealthState{}
+	h.urls = map[filterapi.MCPBackendName]string{"backend1": "http://backend1", "backend2": "http://backend2"}
+	var (
+		mu     sync.Mutex
+		probed = map[string]string{}
+	)
+	h.probe = func(_ context.Context, url string, backend filterapi.MCPBackend) error {
+		mu.Lock()
+		defer mu.Unlock()
+		probed[backend.Name] = url
+		if backend.Name == "backend2" {
+			return errors.New("ping failed")
+		}
+		return nil
+	}
+
+	h.probeAll(t.Context())
+	require.Equal(t, map[string]string{"backend1": "http://backend1", "backend2": "http://backend2"}, probed)
+	require.True(t, h.available("backend1"))
+	require.False(t, h.available("backend2"))
+	require.True(t, h.available("backend3"))
+	require.Equal(t, int32(1), changes.Load())
+}
+
+func TestProxyConfig_pingBackend(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	mcpHandler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+	var (
+		mu      sync.Mutex
+		methods []string
+	)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(internalapi.MCPBackendHeader) != "backend1" || r.Header.Get(internalapi.MCPRouteHeader) != "route" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		mu.Lock()
+		methods = append(methods, r.Method)
+		mu.Unlock()
+		mcpHandler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(backend.Close)
+
+	probe := (&ProxyConfig{}).pingBackend("route")
+	require.NoError(t, probe(t.Context(), backend.URL, filterapi.MCPBackend{Name: "backend1"}))
+	mu.Lock()
+	require.NotEmpty(t, methods)
+	// The session of the probe is closed.
+	require.Equal(t, http.MethodDelete, methods[len(methods)-1])
+	mu.Unlock()
+
+	require.Error(t, probe(t.Context(), backend.URL, filterapi.MCPBackend{Name: "backend2"}))
+}
+
+func TestProxyConfig_LoadConfig_HealthCheck(t *testing.T) {
+	p := &ProxyConfig{
+		l:                  slog.New(slog.DiscardHandler),
+		toolChangeSignaler: newMultiWatcherSignaler(),
+		toolSchemas:        newToolSchemaCache(),
+	}
+	config := func(hc *filterapi.MCPHealthCheck) *filterapi.Config {
+		return &filterapi.Config{MCPConfig: &filterapi.MCPConfig{
+			BackendListenerAddr: "http://127.0.0.1:10088",
+			Routes: []filterapi.MCPRoute{{
+				Name:        "route",
+				Backends:    []filterapi.MCPBackend{{Name: "backend1"}},
+				HealthCheck: hc,
+			}},
+		}}
+	}
+	hc := &filterapi.MCPHealthCheck{Interval: time.Hour, Timeout: time.Second, UnhealthyThreshold: 1, EjectionTime: time.Minute}
+
+	require.NoError(t, p.LoadConfig(t.Context(), config(hc)))
+	health := p.routes["route"].health
+	require.NotNil(t, health)
+	require.Equal(t, map[string]string{"backend1": "http://127.0.0.1:10088"}, health.urls)
+	health.record("backend1", errors.New("connection refused"))
+
+	// The health of the backends survives the reloads that don't affect it.
+	require.NoError(t, p.LoadConfig(t.Context(), config(hc)))
+	require.Same(t, health, p.routes["route"].health)
+	require.False(t, p.backendHealth("route").available("backend1"))
+
+	require.NoError(t, p.LoadConfig(t.Context(), config(nil)))
+	require.Nil(t, p.routes["route"].health)
+	select {
+	case <-health.done:
+	default:
+		require.Fail(t, "the active probes must be stopped")
+	}
+}
+
+func TestHandleToolCallRequest_BackendEjected(t *testing.T) {
+	var calls atomic.Int32
+	backendServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(backendServer.Close)
+
+	proxy := newTestMCPProxy()
+	proxy.backendListenerAddr = backendServer.URL
+	route := proxy.routes["test-route"]
+	route.health = proxy.newBackendHealth(&filterapi.MCPRoute{
+		Name:        "test-route",
+		HealthCheck: &filterapi.MCPHealthCheck{Interval: time.Hour, Timeout: time.Second, UnhealthyThreshold: 1, EjectionTime: time.Minute},
+	}, route.backends, nil, nil)
+	s := &session{
+		reqCtx: proxy,
+		perBackendSessions: map[filterapi.MCPBackendName]*compositeSessionEntry{
+			"backend1": {sessionID: "test-session"},
+		},
+		route: "test-route",
+	}
+
+	call := func() *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		_, err := proxy.handleToolCallRequest(t.Context(), s, rr, &jsonrpc.Request{},
+			&mcp.CallToolParams{Name: "backend1__test-tool"}, nil, httptest.NewRequest(http.MethodPost, "/mcp", nil))
+		require.Error(t, err)
+		return rr
+	}
+	require.Equal(t, http.StatusInternalServerError, call().Code)
+
+	rr := call()
+	require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	require.Contains(t, rr.Body.String(), "backend backend1 is unavailable")
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestHandleToolsListRequest_BackendEjected(t *testing.T) {
+	var calls sync.Map
+	backendServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		backend := r.Header.Get(internalapi.MCPBackendHeader)
+		calls.Store(backend, true)
+		body, _ := io.ReadAll(r.Body)
+		msg, _ := jsonrpc.DecodeMessage(body)
+		result, _ := json.Marshal(&mcp.ListToolsResult{Tools: []*mcp.Tool{{Name: "tool", InputSchema: map[string]any{"type": "object"}}}})
+		respBody, _ := jsonrpc.EncodeMessage(&jsonrpc.Response{ID: msg.(*jsonrpc.Request).ID, Result: result})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(respBody)
+	}))
+	t.Cleanup(backendServer.Close)
+
+	proxy := newTestMCPProxy()
+	proxy.backendListenerAddr = backendServer.URL
+	proxy.toolSchemas = newToolSchemaCache()
+	route := proxy.routes["test-route"]
+	route.toolSelectors = nil
+	route.health = proxy.newBackendHealth(&filterapi.MCPRoute{
+		Name:        "test-route",
+		HealthCheck: &filterapi.MCPHealthCheck{Interval: time.Hour, Timeout: time.Second, UnhealthyThreshold: 1, EjectionTime: time.Minute},
+	}, route.backends, nil, nil)
+	route.health.record("backend2", errors.New("connection refused"))
+	toolsCapabilities := &mcp.ServerCapabilities{Tools: &mcp.ToolCapabilities{}}
+	s := &session{
+		reqCtx: proxy,
+		perBackendSessions: map[filterapi.MCPBackendName]*compositeSessionEntry{
+			"backend1": {backendName: "backend1", sessionID: "session1", capabilities: toolsCapabilities},
+			"backend2": {backendName: "backend2", sessionID: "session2", capabilities: toolsCapabilities},
+		},
+		route: "test-route",
+	}
+
+	rr := httptest.NewRecorder()
+	req := &jsonrpc.Request{ID: mustJSONRPCRequestID(), Method: "tools/list"}
+	require.NoError(t, proxy.handleToolsListRequest(t.Context(), s, rr, req, &mcp.ListToolsParams{}, nil))
+	body := rr.Body.String()
+	msg, err := jsonrpc.DecodeMessage([]byte(strings.TrimSpace(body[strings.LastIndex(body, "data: ")+len("data: "):])))
+	require.NoError(t, err)
+	var result mcp.ListToolsResult
+	require.NoError(t, json.Unmarshal(msg.(*jsonrpc.Response).Result, &result))
+	require.Len(t, result.Tools, 1)
+	require.Equal(t, "backend1__tool", result.Tools[0].Name)
+	_, called := calls.Load("backend2")
+	require.False(t, called)
+}
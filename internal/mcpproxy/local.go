@@ -0,0 +1,231 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/mcpmodels"
+	"github.com/envoyproxy/ai-gateway/internal/mcpopenapi"
+	"github.com/envoyproxy/ai-gateway/internal/mcpsse"
+	"github.com/envoyproxy/ai-gateway/internal/mcpstdio"
+)
+
+type (
+	// localServerKey identifies a local server by the route and the backend it belongs to.
+	localServerKey struct {
+		route   filterapi.MCPRouteName
+		backend filterapi.MCPBackendName
+	}
+
+	// localServer is a Streamable HTTP MCP server run by the MCP proxy itself for a backend that cannot be reached
+	// directly through the backend listener: a stdio MCP server, a bridge to a backend speaking the legacy
+	// HTTP+SSE transport, the models of an AIGatewayRoute, or the operations of a REST API.
+	localServer interface {
+		URL() string
+		Close() error
+	}
+
+	// runningLocalServer is a running local server along with the configuration it was started with.
+	runningLocalServer struct {
+		config localServerConfig
+		server localServer
+	}
+
+	// localServerConfig is the configuration of a local server. Exactly one of the fields is set.
+	localServerConfig struct {
+		stdio *filterapi.MCPStdioBackend
+		// sseEndpoint is the URL of the SSE endpoint of the backend on the backend listener.
+		sseEndpoint string
+		// aiGatewayRoute is the configuration of the models of an AIGatewayRoute exposed as MCP tools.
+		aiGatewayRoute *filterapi.MCPAIGatewayRouteBackend
+		// openAPI is the configuration of the operations of a REST API exposed as MCP tools.
+		openAPI *mcpopenapi.Config
+	}
+)
+
+// inheritedStdioEnv are the environment variables of the MCP proxy that are passed down to the stdio MCP servers,
+// so that the commands can be resolved and run as they would be in a shell. They can be overridden by the backend env.
+var inheritedStdioEnv = []string{"PATH", "HOME"}
+
+// sseBridgeIdleTimeout is the duration after which the unused sessions of the SSE bridges, the models servers and
+// the OpenAPI servers are closed.
+const sseBridgeIdleTimeout = time.Hour
+
+// syncLocalServers starts the local servers of the given routes, reusing the ones that are already running with
+// the same configuration, and closes the ones that are not configured anymore. It returns the URL of each server.
+func (p *ProxyConfig) syncLocalServers(ctx context.Context, mcpConfig *filterapi.MCPConfig) (map[localServerKey]string, error) {
+	if p.localServers == nil {
+		p.localServers = make(map[localServerKey]*runningLocalServer)
+	}
+	urls := make(map[localServerKey]string)
+	for _, route := range mcpConfig.Routes {
+		for _, backend := range route.Backends {
+			var cfg localServerConfig
+			switch {
+			case backend.Stdio != nil:
+				cfg.stdio = backend.Stdio
+			case backend.AIGatewayRoute != nil:
+				cfg.aiGatewayRoute = backend.AIGatewayRoute
+			case backend.OpenAPI != nil:
+				cfg.openAPI = &mcpopenapi.Config{
+					URL:                mcpConfig.BackendListenerAddr,
+					Route:              route.Name,
+					Backend:            backend.Name,
+					BasePath:           backend.OpenAPI.BasePath,
+					Operations:         backend.OpenAPI.Operations,
+					SessionIdleTimeout: sseBridgeIdleTimeout,
+				}
+			case backend.Transport == filterapi.MCPBackendTransportSSE:
+				cfg.sseEndpoint = mcpConfig.BackendListenerAddr + backend.Path
+			default:
+				continue
+			}
+			key := localServerKey{route: route.Name, backend: backend.Name}
+			if s, ok := p.localServers[key]; ok {
+				if reflect.DeepEqual(s.config, cfg) {
+					urls[key] = s.server.URL()
+					continue
+				}
+				_ = s.server.Close()
+				delete(p.localServers, key)
+			}
+			server, err := p.startLocalServer(ctx, fmt.Sprintf("%s/%s", route.Name, backend.Name), cfg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to start local MCP server for backend %s in route %s: %w", backend.Name, route.Name, err)
+			}
+			p.localServers[key] = &runningLocalServer{config: cfg, server: server}
+			urls[key] = server.URL()
+		}
+	}
+	for key, s := range p.localServers {
+		if _, ok := urls[key]; !ok {
+			_ = s.server.Close()
+			delete(p.localServers, key)
+		}
+	}
+	return urls, nil
+}
+
+// startLocalServer starts the local server with the given name and configuration.
+func (p *ProxyConfig) startLocalServer(ctx context.Context, name string, cfg localServerConfig) (localServer, error) {
+	if cfg.stdio != nil {
+		// The servers outlive the config loading, so they are bound to the lifetime of the proxy instead.
+		// The process is started in the background, so that a slow or hung server doesn't block the config loading.
+		server, err := mcpstdio.Start(context.WithoutCancel(ctx), p.l, name, stdioConfig(cfg.stdio))
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			// The sessions initialized before the server was ready don't have its tools.
+			<-server.Ready()
+			if server.Available() && p.toolChangeSignaler != nil {
+				p.toolChangeSignaler.Signal()
+			}
+		}()
+		return server, nil
+	}
+	if b := cfg.aiGatewayRoute; b != nil {
+		return mcpmodels.Start(p.l, name, mcpmodels.Config{
+			URL:                b.URL,
+			Host:               b.Host,
+			ChatModels:         b.ChatModels,
+			EmbeddingModel:     b.EmbeddingModel,
+			ImageModel:         b.ImageModel,
+			SessionIdleTimeout: sseBridgeIdleTimeout,
+		})
+	}
+	if cfg.openAPI != nil {
+		return mcpopenapi.Start(p.l, name, *cfg.openAPI)
+	}
+	return mcpsse.StartBridge(p.l, name, sseConnectFunc(cfg.sseEndpoint), sseBridgeIdleTimeout)
+}
+
+// stdioConfig converts the given stdio backend configuration into the configuration of the stdio MCP server.
+func stdioConfig(b *filterapi.MCPStdioBackend) mcpstdio.Config {
+	env := make([]string, 0, len(inheritedStdioEnv)+len(b.Env))
+	for _, k := range inheritedStdioEnv {
+		if v, ok := os.LookupEnv(k); ok {
+			env = append(env, k+"="+v)
+		}
+	}
+	// When a variable is set more than once, the last value takes precedence.
+	for _, k := range slices.Sorted(maps.Keys(b.Env)) {
+		env = append(env, k+"="+b.Env[k])
+	}
+	return mcpstdio.Config{
+		Command:            b.Command,
+		Args:               b.Args,
+		Env:                env,
+		PerSession:         b.PerSession,
+		MaxProcesses:       b.MaxProcesses,
+		SessionIdleTimeout: b.SessionIdleTimeout,
+		MemoryLimitBytes:   b.MemoryLimitBytes,
+		CPUTimeLimit:       b.CPUTimeLimit,
+	}
+}
+
+// sseConnectFunc returns the function connecting the sessions of an SSE bridge to the given SSE endpoint.
+//
+// The headers set by the MCP proxy on the initialization request, such as the routing and the forwarded headers,
+// are set on all the requests of the connection, so that they are routed and authenticated like the others.
+func sseConnectFunc(endpoint string) mcpsse.ConnectFunc {
+	return func(r *http.Request) (mcp.Connection, error) {
+		header := r.Header.Clone()
+		for _, h := range []string{
+			"Accept", "Content-Type", "Content-Length", sessionIDHeader, protocolVersionHeader, lastEventIDHeader,
+			internalapi.MCPMetadataHeaderRequestID, internalapi.MCPMetadataHeaderMethod, internalapi.MCPMetadataHeaderToolName,
+		} {
+			header.Del(h)
+		}
+		transport := &mcp.SSEClientTransport{
+			Endpoint:   endpoint,
+			HTTPClient: &http.Client{Transport: &sseHeaderTransport{header: header}},
+		}
+		// The SSE stream is closed with the connection.
+		return transport.Connect(context.WithoutCancel(r.Context()))
+	}
+}
+
+// sseHeaderTransport is an [http.RoundTripper] setting the given headers on the requests of an SSE connection.
+type sseHeaderTransport struct {
+	header http.Header
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *sseHeaderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range t.header {
+		req.Header[k] = v
+	}
+	if req.Method == http.MethodPost {
+		// The message endpoint is advertised by the backend, so its path must be preserved.
+		req.Header.Set(internalapi.MCPSSEMessageHeader, "true")
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// backendURL returns the URL to send the requests for the given backend to. This is the local server for the stdio,
+// SSE, AIGatewayRoute and OpenAPI backends, and the backend listener of Envoy otherwise.
+func (m *mcpProxyConfig) backendURL(routeName filterapi.MCPRouteName, backendName filterapi.MCPBackendName) string {
+	if r := m.routes[routeName]; r != nil {
+		if url, ok := r.localURLs[backendName]; ok {
+			return url
+		}
+	}
+	return m.backendListenerAddr
+}
@@ -0,0 +1,298 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+func TestLoadConfig_StdioBackends(t *testing.T) {
+	// A command that exits immediately is enough to check how the servers are managed, since the servers
+	// keep retrying to start the process in the background.
+	command, err := exec.LookPath("false")
+	if err != nil {
+		t.Skip("false command not available")
+	}
+	proxy := &ProxyConfig{
+		mcpProxyConfig:     &mcpProxyConfig{},
+		toolChangeSignaler: newMultiWatcherSignaler(),
+		l:                  slog.New(slog.DiscardHandler),
+	}
+	t.Cleanup(func() {
+		for _, s := range proxy.localServers {
+			_ = s.server.Close()
+		}
+	})
+
+	newConfig := func(args ...string) *filterapi.Config {
+		return &filterapi.Config{
+			MCPConfig: &filterapi.MCPConfig{
+				BackendListenerAddr: "http://localhost:8080",
+				Routes: []filterapi.MCPRoute{
+					{
+						Name: "route1",
+						Backends: []filterapi.MCPBackend{
+							{Name: "backend1"},
+							{Name: "backend2", Stdio: &filterapi.MCPStdioBackend{Command: command, Args: args}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	require.NoError(t, proxy.LoadConfig(t.Context(), newConfig("a")))
+	require.Len(t, proxy.localServers, 1)
+	require.Equal(t, "http://localhost:8080", proxy.backendURL("route1", "backend1"))
+	url := proxy.backendURL("route1", "backend2")
+	require.Regexp(t, `^http://127\.0\.0\.1:\d+/[A-Z2-7]{26}/mcp$`, url)
+	require.Equal(t, "http://localhost:8080", proxy.backendURL("unknown", "backend2"))
+
+	// Reloading the same configuration keeps the running server.
+	ch := proxy.toolChangeSignaler.Watch()
+	require.NoError(t, proxy.LoadConfig(t.Context(), newConfig("a")))
+	require.Equal(t, url, proxy.backendURL("route1", "backend2"))
+	select {
+	case <-ch:
+		t.Fatal("unexpected tools changed notification")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Changing the configuration restarts the server, which may expose different tools.
+	require.NoError(t, proxy.LoadConfig(t.Context(), newConfig("b")))
+	require.Len(t, proxy.localServers, 1)
+	require.NotEqual(t, url, proxy.backendURL("route1", "backend2"))
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("expected tools changed notification")
+	}
+
+	// Removing the backend stops the server.
+	require.NoError(t, proxy.LoadConfig(t.Context(), &filterapi.Config{MCPConfig: &filterapi.MCPConfig{
+		BackendListenerAddr: "http://localhost:8080",
+		Routes:              []filterapi.MCPRoute{{Name: "route1", Backends: []filterapi.MCPBackend{{Name: "backend1"}}}},
+	}}))
+	require.Empty(t, proxy.localServers)
+}
+
+func TestLoadConfig_AIGatewayRouteBackends(t *testing.T) {
+	proxy := &ProxyConfig{
+		mcpProxyConfig:     &mcpProxyConfig{},
+		toolChangeSignaler: newMultiWatcherSignaler(),
+		l:                  slog.New(slog.DiscardHandler),
+	}
+	t.Cleanup(func() {
+		for _, s := range proxy.localServers {
+			_ = s.server.Close()
+		}
+	})
+
+	require.NoError(t, proxy.LoadConfig(t.Context(), &filterapi.Config{MCPConfig: &filterapi.MCPConfig{
+		BackendListenerAddr: "http://localhost:8080",
+		Routes: []filterapi.MCPRoute{{Name: "route1", Backends: []filterapi.MCPBackend{
+			{Name: "models", AIGatewayRoute: &filterapi.MCPAIGatewayRouteBackend{
+				URL: "http://127.0.0.1:10080", ChatModels: []string{"gpt-4o"},
+			}},
+		}}},
+	}}))
+	require.Len(t, proxy.localServers, 1)
+	url := proxy.backendURL("route1", "models")
+	require.Regexp(t, `^http://127\.0\.0\.1:\d+/mcp$`, url)
+
+	// The tools of the models are served by the local server.
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(t.Context(),
+		&mcp.StreamableClientTransport{Endpoint: url}, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+	tools, err := session.ListTools(t.Context(), nil)
+	require.NoError(t, err)
+	require.Len(t, tools.Tools, 1)
+	require.Equal(t, "ask_gpt-4o", tools.Tools[0].Name)
+}
+
+func TestLoadConfig_OpenAPIBackends(t *testing.T) {
+	// A fake backend listener routing the requests of the REST API backend.
+	backendListener := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(internalapi.MCPBackendHeader) != "pets" || r.Header.Get(internalapi.MCPRouteHeader) != "route1" {
+			http.Error(w, "no route", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+	}))
+	t.Cleanup(backendListener.Close)
+
+	proxy := &ProxyConfig{
+		mcpProxyConfig:     &mcpProxyConfig{},
+		toolChangeSignaler: newMultiWatcherSignaler(),
+		l:                  slog.New(slog.DiscardHandler),
+	}
+	t.Cleanup(func() {
+		for _, s := range proxy.localServers {
+			_ = s.server.Close()
+		}
+	})
+
+	require.NoError(t, proxy.LoadConfig(t.Context(), &filterapi.Config{MCPConfig: &filterapi.MCPConfig{
+		BackendListenerAddr: backendListener.URL,
+		Routes: []filterapi.MCPRoute{{Name: "route1", Backends: []filterapi.MCPBackend{
+			{Name: "pets", OpenAPI: &filterapi.MCPOpenAPIBackend{
+				BasePath: "/v1",
+				Operations: []filterapi.MCPOpenAPIOperation{{
+					Name: "getPet", Method: http.MethodGet, Path: "/pets/{id}",
+					Parameters: []filterapi.MCPOpenAPIParameter{{Name: "id", In: "path"}},
+				}},
+			}},
+		}}},
+	}}))
+	require.Len(t, proxy.localServers, 1)
+	url := proxy.backendURL("route1", "pets")
+	require.Regexp(t, `^http://127\.0\.0\.1:\d+/mcp$`, url)
+
+	// The operations are served by the local server, which sends the requests to the backend listener.
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(t.Context(),
+		&mcp.StreamableClientTransport{Endpoint: url}, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+	res, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "getPet", Arguments: map[string]any{"id": 1}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	require.Equal(t, map[string]any{"path": "/v1/pets/1"}, res.StructuredContent)
+}
+
+func TestLoadConfig_StdioBackendsDoNotBlock(t *testing.T) {
+	// A command that never completes the MCP initialization.
+	command, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("sleep command not available")
+	}
+	proxy := &ProxyConfig{
+		mcpProxyConfig:     &mcpProxyConfig{},
+		toolChangeSignaler: newMultiWatcherSignaler(),
+		l:                  slog.New(slog.DiscardHandler),
+	}
+	t.Cleanup(func() {
+		for _, s := range proxy.localServers {
+			_ = s.server.Close()
+		}
+	})
+
+	start := time.Now()
+	require.NoError(t, proxy.LoadConfig(t.Context(), &filterapi.Config{MCPConfig: &filterapi.MCPConfig{
+		BackendListenerAddr: "http://localhost:8080",
+		Routes: []filterapi.MCPRoute{{Name: "route1", Backends: []filterapi.MCPBackend{
+			{Name: "backend1", Stdio: &filterapi.MCPStdioBackend{Command: command, Args: []string{"60"}}},
+			{Name: "backend2", Stdio: &filterapi.MCPStdioBackend{Command: command, Args: []string{"60"}}},
+		}}},
+	}}))
+	require.Less(t, time.Since(start), 5*time.Second)
+	require.Len(t, proxy.localServers, 2)
+
+	// The server rejects the sessions until it is ready.
+	client := mcp.NewClient(&mcp.Implementation{Name: t.Name()}, nil)
+	_, err = client.Connect(t.Context(), &mcp.StreamableClientTransport{Endpoint: proxy.backendURL("route1", "backend1")}, nil)
+	require.Error(t, err)
+}
+
+func Test_stdioConfig(t *testing.T) {
+	t.Setenv("PATH", "/usr/bin")
+	t.Setenv("HOME", "/home/test")
+	cfg := stdioConfig(&filterapi.MCPStdioBackend{
+		Command:            "server",
+		Args:               []string{"--flag"},
+		Env:                map[string]string{"B": "2", "A": "1", "HOME": "/tmp"},
+		PerSession:         true,
+		MaxProcesses:       3,
+		SessionIdleTimeout: time.Minute,
+		MemoryLimitBytes:   1024,
+		CPUTimeLimit:       time.Second,
+	})
+	require.Equal(t, "server", cfg.Command)
+	require.Equal(t, []string{"--flag"}, cfg.Args)
+	require.Equal(t, []string{"PATH=/usr/bin", "HOME=/home/test", "A=1", "B=2", "HOME=/tmp"}, cfg.Env)
+	require.True(t, cfg.PerSession)
+	require.Equal(t, 3, cfg.MaxProcesses)
+	require.Equal(t, time.Minute, cfg.SessionIdleTimeout)
+	require.Equal(t, uint64(1024), cfg.MemoryLimitBytes)
+	require.Equal(t, time.Second, cfg.CPUTimeLimit)
+}
+
+func TestLegacySSE_EndToEnd(t *testing.T) {
+	// A backend speaking the legacy transport behind a fake backend listener, which routes the message requests
+	// as they are and the other requests to the SSE endpoint of the backend.
+	type args struct {
+		Text string `json:"text"`
+	}
+	backendServer := mcp.NewServer(&mcp.Implementation{Name: "legacy"}, nil)
+	mcp.AddTool(backendServer, &mcp.Tool{Name: "echo", Description: "echo tool"},
+		func(_ context.Context, _ *mcp.CallToolRequest, args args) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: args.Text}}}, nil, nil
+		})
+	sseHandler := mcp.NewSSEHandler(func(*http.Request) *mcp.Server { return backendServer }, nil)
+	var sseRequests atomic.Int32
+	backendListener := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(internalapi.MCPBackendHeader) != "legacy" || r.Header.Get(internalapi.MCPRouteHeader) != "route1" {
+			http.Error(w, "no route", http.StatusNotFound)
+			return
+		}
+		if r.Header.Get(internalapi.MCPSSEMessageHeader) == "" {
+			sseRequests.Add(1)
+			r.URL.Path = "/sse"
+		}
+		sseHandler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(backendListener.Close)
+
+	proxy, mux, err := NewMCPProxy(slog.New(slog.DiscardHandler), stubMetrics{}, noopTracer, NewPBKDF2AesGcmSessionCrypto("test", 100), nil, nil, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		for _, s := range proxy.localServers {
+			_ = s.server.Close()
+		}
+	})
+	require.NoError(t, proxy.LoadConfig(t.Context(), &filterapi.Config{MCPConfig: &filterapi.MCPConfig{
+		BackendListenerAddr: backendListener.URL,
+		Routes: []filterapi.MCPRoute{{
+			Name:      "route1",
+			Backends:  []filterapi.MCPBackend{{Name: "legacy", Transport: filterapi.MCPBackendTransportSSE, Path: "/sse"}},
+			LegacySSE: &filterapi.MCPLegacySSE{SSEPath: "/sse", MessagePath: "/messages"},
+		}},
+	}}))
+	require.Regexp(t, `^http://127\.0\.0\.1:\d+/mcp$`, proxy.backendURL("route1", "legacy"))
+
+	// A legacy client connected to the MCP proxy, with the route header set like the main route does.
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Set(internalapi.MCPRouteHeader, "route1")
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(gateway.Close)
+	client := mcp.NewClient(&mcp.Implementation{Name: t.Name()}, nil)
+	cs, err := client.Connect(t.Context(), &mcp.SSEClientTransport{Endpoint: gateway.URL + "/sse"}, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cs.Close() })
+
+	tools, err := cs.ListTools(t.Context(), nil)
+	require.NoError(t, err)
+	require.Len(t, tools.Tools, 1)
+	res, err := cs.CallTool(t.Context(), &mcp.CallToolParams{Name: tools.Tools[0].Name, Arguments: map[string]any{"text": "hello"}})
+	require.NoError(t, err)
+	require.Equal(t, "hello", res.Content[0].(*mcp.TextContent).Text)
+	require.Positive(t, sseRequests.Load())
+}
@@ -15,6 +15,7 @@ import (
 	"maps"
 	"net/http"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -28,6 +29,7 @@ import (
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 	"github.com/envoyproxy/ai-gateway/internal/json"
 	"github.com/envoyproxy/ai-gateway/internal/lang"
+	"github.com/envoyproxy/ai-gateway/internal/mcpsse"
 	"github.com/envoyproxy/ai-gateway/internal/metrics"
 	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
 )
@@ -56,17 +58,45 @@ func getMaxRequestBodySize() int64 {
 }
 
 // NewMCPProxy creates a new MCPProxy instance.
-func NewMCPProxy(l *slog.Logger, mcpMetrics metrics.MCPMetrics, tracer tracingapi.MCPTracer, sessionCrypto SessionCrypto, logRequestHeaderAttributes map[string]string) (*ProxyConfig, *http.ServeMux, error) {
+//
+// The sessionStore is optional. When nil, the state of the sessions is only kept in the session IDs.
+// The auditLog is optional. When nil, the requests are not audited.
+func NewMCPProxy(l *slog.Logger, mcpMetrics metrics.MCPMetrics, tracer tracingapi.MCPTracer, sessionCrypto SessionCrypto, sessionStore SessionStore, auditLog *AuditLog, logRequestHeaderAttributes map[string]string) (*ProxyConfig, *http.ServeMux, error) {
 	toolChangeSignaler := newMultiWatcherSignaler() // used to signal changes to all active sessions.
 	cfg := &ProxyConfig{
 		toolChangeSignaler:         toolChangeSignaler,
 		tracer:                     tracer,
 		sessionCrypto:              sessionCrypto,
+		sessionStore:               sessionStore,
+		tokens:                     newTokenCache(),
+		toolSchemas:                newToolSchemaCache(),
+		toolSearchIndexes:          newToolSearchIndexCache(),
+		auditLog:                   auditLog,
 		l:                          l,
 		client:                     http.Client{}, // No timeout as it's enforced at Envoy level.
 		logRequestHeaderAttributes: maps.Clone(logRequestHeaderAttributes),
 		maxRequestBodySize:         getMaxRequestBodySize(),
 	}
+	serve := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxy := &mcpRequestContext{
+			metrics:        mcpMetrics.WithRequestAttributes(r),
+			ProxyConfig:    cfg,
+			requestHeaders: r.Header,
+			originalPath:   originalPathForRequest(r),
+		}
+		switch r.Method {
+		case http.MethodGet:
+			proxy.serveGET(w, r)
+		case http.MethodPost:
+			proxy.servePOST(w, r)
+		case http.MethodDelete:
+			proxy.serverDELETE(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	// The legacy HTTP+SSE endpoints are served on top of the Streamable HTTP transport.
+	legacy := mcpsse.NewLegacyHandler(serve, l)
 	mux := http.NewServeMux()
 	mux.HandleFunc(
 		// Must match all paths since the route selection happens at Envoy level and the "route" header is already
@@ -75,26 +105,33 @@ func NewMCPProxy(l *slog.Logger, mcpMetrics metrics.MCPMetrics, tracer tracingap
 		// For example, if we mistakenly set /mcp here, only the route with prefix /mcp will be matched, and other routes
 		// with different prefixes will not be matched, which is not desired.
 		"/", func(w http.ResponseWriter, r *http.Request) {
-			proxy := &mcpRequestContext{
-				metrics:        mcpMetrics.WithRequestAttributes(r),
-				ProxyConfig:    cfg,
-				requestHeaders: r.Header,
-				originalPath:   originalPathForRequest(r),
-			}
-			switch r.Method {
-			case http.MethodGet:
-				proxy.serveGET(w, r)
-			case http.MethodPost:
-				proxy.servePOST(w, r)
-			case http.MethodDelete:
-				proxy.serverDELETE(w, r)
-			default:
-				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			if sse := cfg.legacySSE(r); sse != nil {
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == sse.SSEPath:
+					legacy.ServeSSE(w, r, sse.MessagePath)
+					return
+				case r.Method == http.MethodPost && r.URL.Path == sse.MessagePath:
+					legacy.ServeMessage(w, r)
+					return
+				}
 			}
+			serve(w, r)
 		})
 	return cfg, mux, nil
 }
 
+// legacySSE returns the legacy HTTP+SSE configuration of the route of the given request, or nil if not enabled.
+func (p *ProxyConfig) legacySSE(r *http.Request) *filterapi.MCPLegacySSE {
+	cfg := p.mcpProxyConfig
+	if cfg == nil {
+		return nil
+	}
+	if route := cfg.routes[r.Header.Get(internalapi.MCPRouteHeader)]; route != nil {
+		return route.legacySSE
+	}
+	return nil
+}
+
 func originalPathForRequest(r *http.Request) string {
 	if r.RequestURI != "" {
 		return r.RequestURI
@@ -183,7 +220,16 @@ func (m *mcpRequestContext) newSession(ctx context.Context, p *mcp.InitializePar
 	if m.l.Enabled(ctx, slog.LevelDebug) {
 		m.l.Debug("initializing MCP sessions to backends", slog.String("route", routeName), slog.Any("backends", backends))
 	}
+	// Each backend negotiates its own protocol version, and the messages are converted to the version of the client.
+	backendParams := *p
+	backendParams.ProtocolVersion = latestProtocolVersion
+	health := backends.health
 	for _, backend := range backends.backends {
+		if !health.available(backend.Name) {
+			// Don't wait for the ejected backends. The session is created with the rest of the backends.
+			m.l.Warn("skipping ejected MCP backend", slog.String("backend", backend.Name))
+			continue
+		}
 		entryIndex := counter
 		counter++
 		// Initialize sessions to all backends in parallel to reduce the overall latency of session creation.
@@ -192,7 +238,7 @@ func (m *mcpRequestContext) newSession(ctx context.Context, p *mcp.InitializePar
 				m.l.Debug("creating MCP session", slog.String("backend", backend.Name))
 			}
 			backendStartAt := time.Now()
-			initResult, err := m.initializeSession(ctx, routeName, backend, p, startAt)
+			initResult, err := m.initializeSession(ctx, routeName, backend, &backendParams, startAt)
 			if err != nil {
 				m.l.Error("failed to create MCP session", slog.String("backend", backend.Name), slog.String("error", err.Error()))
 				// If one backend fails, don't fail the overall connection. Create a session to the rest of the backends, as they
@@ -208,9 +254,10 @@ func (m *mcpRequestContext) newSession(ctx context.Context, p *mcp.InitializePar
 				span.RecordRouteToBackend(backend.Name, string(initResult.sessionID), true)
 			}
 			entries[entryIndex] = compositeSessionEntry{
-				sessionID:    initResult.sessionID,
-				backendName:  backend.Name,
-				capabilities: initResult.result.Capabilities,
+				sessionID:       initResult.sessionID,
+				backendName:     backend.Name,
+				capabilities:    initResult.result.Capabilities,
+				protocolVersion: initResult.result.ProtocolVersion,
 			}
 		})
 	}
@@ -386,8 +433,12 @@ func (m *mcpRequestContext) initializeSession(ctx context.Context, routeName fil
 			m.l.Warn("Failed to decode MCP initialize result", slog.String("error", err.Error()))
 			return nil, fmt.Errorf("failed to decode MCP initialize result: %w", err)
 		}
+		if !slices.Contains(supportedProtocolVersions, initResult.ProtocolVersion) {
+			return nil, fmt.Errorf("unsupported MCP protocol version %q", initResult.ProtocolVersion)
+		}
 		if m.l.Enabled(ctx, slog.LevelDebug) {
-			m.l.Debug("MCP session initialized", slog.Any("capabilities", initResult.Capabilities))
+			m.l.Debug("MCP session initialized", slog.Any("capabilities", initResult.Capabilities),
+				slog.String("protocol_version", initResult.ProtocolVersion))
 		}
 		backendMetrics := m.metrics.WithBackend(backend.Name)
 		backendMetrics.RecordServerCapabilities(ctx, initResult.Capabilities, p)
@@ -400,7 +451,8 @@ func (m *mcpRequestContext) initializeSession(ctx context.Context, routeName fil
 		// Send the notifications/initialized request to the MCP backend listener.
 		mcpReq := &jsonrpc.Request{Method: "notifications/initialized", Params: emptyJSONRPCMessage}
 		resp, err := m.invokeJSONRPCRequest(ctx, routeName, backend, &compositeSessionEntry{
-			sessionID: gatewayToMCPServerSessionID(sessionID),
+			sessionID:       gatewayToMCPServerSessionID(sessionID),
+			protocolVersion: initResult.ProtocolVersion,
 		}, mcpReq, p)
 		if err != nil {
 			return nil, fmt.Errorf("failed to send MCP notifications/initialized request: %w", err)
@@ -428,7 +480,7 @@ func (m *mcpRequestContext) invokeJSONRPCRequest(ctx context.Context, routeName
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode MCP message: %w", err)
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.backendListenerAddr, bytes.NewReader(encoded))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.backendURL(routeName, backend.Name), bytes.NewReader(encoded))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create MCP notifications/initialized request: %w", err)
 	}
@@ -442,6 +494,9 @@ func (m *mcpRequestContext) invokeJSONRPCRequest(ctx context.Context, routeName
 		if len(cse.lastEventID) > 0 {
 			req.Header.Set(lastEventIDHeader, cse.lastEventID)
 		}
+		if len(cse.protocolVersion) > 0 {
+			req.Header.Set(protocolVersionHeader, cse.protocolVersion)
+		}
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json, text/event-stream")
@@ -464,7 +519,12 @@ func (m *mcpRequestContext) invokeJSONRPCRequest(ctx context.Context, routeName
 		}
 	}
 
+	if err = m.setBackendCredential(ctx, req, backend); err != nil {
+		return nil, err
+	}
+
 	resp, err := m.client.Do(req)
+	m.backendHealth(routeName).recordResponse(backend.Name, resp, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send MCP notifications/initialized request: %w", err)
 	}
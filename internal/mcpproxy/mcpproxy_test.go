@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -81,7 +82,7 @@ func TestGetMaxRequestBodySize(t *testing.T) {
 
 func TestNewMCPProxy(t *testing.T) {
 	l := slog.Default()
-	proxy, mux, err := NewMCPProxy(l, stubMetrics{}, noopTracer, NewPBKDF2AesGcmSessionCrypto("test", 100), nil)
+	proxy, mux, err := NewMCPProxy(l, stubMetrics{}, noopTracer, NewPBKDF2AesGcmSessionCrypto("test", 100), nil, nil, nil)
 
 	require.NoError(t, err)
 	require.NotNil(t, proxy)
@@ -90,7 +91,7 @@ func TestNewMCPProxy(t *testing.T) {
 
 func TestMCPProxy_HTTPMethods(t *testing.T) {
 	l := slog.Default()
-	_, mux, err := NewMCPProxy(l, stubMetrics{}, noopTracer, NewPBKDF2AesGcmSessionCrypto("test", 100), nil)
+	_, mux, err := NewMCPProxy(l, stubMetrics{}, noopTracer, NewPBKDF2AesGcmSessionCrypto("test", 100), nil, nil, nil)
 	require.NoError(t, err)
 
 	// Test unsupported method.
@@ -399,6 +400,21 @@ func TestInitializeSession_InitializeFailure(t *testing.T) {
 	require.Contains(t, err.Error(), "failed with status code")
 }
 
+func TestInitializeSession_UnsupportedProtocolVersion(t *testing.T) {
+	backendServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(sessionIDHeader, "test-session-123")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(strings.Replace(validInitializeResponse, `"2025-06-18"`, `"2099-01-01"`, 1)))
+	}))
+	defer backendServer.Close()
+
+	proxy := newTestMCPProxy()
+	proxy.backendListenerAddr = backendServer.URL
+
+	_, err := proxy.initializeSession(t.Context(), "route1", filterapi.MCPBackend{Name: "test-backend"}, &mcp.InitializeParams{}, time.Now())
+	require.ErrorContains(t, err, `unsupported MCP protocol version "2099-01-01"`)
+}
+
 func TestInitializeSession_NotificationsInitializedFailure(t *testing.T) {
 	// Mock backend server.
 	var callCount perBackendCallCount
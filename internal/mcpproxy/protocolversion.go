@@ -0,0 +1,163 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+const (
+	protocolVersion20241105 = "2024-11-05"
+	protocolVersion20250326 = "2025-03-26"
+	protocolVersion20250618 = "2025-06-18"
+	// latestProtocolVersion is the protocol version the gateway speaks natively. The messages of the peers
+	// negotiating an older version are converted from and to this version.
+	latestProtocolVersion = protocolVersion20250618
+)
+
+// supportedProtocolVersions are the MCP protocol versions supported by the gateway, from the oldest to the latest.
+var supportedProtocolVersions = []string{protocolVersion20241105, protocolVersion20250326, protocolVersion20250618}
+
+// negotiateProtocolVersion returns the protocol version to use with a client requesting the given version: the
+// requested version if it is supported, the latest version otherwise.
+//
+// https://modelcontextprotocol.io/specification/2025-06-18/basic/lifecycle#version-negotiation
+func negotiateProtocolVersion(requested string) string {
+	if slices.Contains(supportedProtocolVersions, requested) {
+		return requested
+	}
+	return latestProtocolVersion
+}
+
+// protocolVersionBefore returns true if the protocol version v is older than the given version. The versions are
+// dates, so they are ordered lexicographically.
+func protocolVersionBefore(v, version string) bool {
+	return v < version
+}
+
+// clientProtocolVersion returns the protocol version negotiated with the client of the current request.
+//
+// The clients send the negotiated version in the MCP-Protocol-Version header of each request since 2025-06-18. When the
+// header is missing, the version is assumed to be 2025-03-26 as required by the specification.
+//
+// https://modelcontextprotocol.io/specification/2025-06-18/basic/transports#protocol-version-header
+func (m *mcpRequestContext) clientProtocolVersion() string {
+	v := m.requestHeaders.Get(protocolVersionHeader)
+	if v == "" {
+		return protocolVersion20250326
+	}
+	return negotiateProtocolVersion(v)
+}
+
+// downgradeServerCapabilities drops the server capabilities that don't exist in the given protocol version.
+func downgradeServerCapabilities(caps *mcp.ServerCapabilities, version string) *mcp.ServerCapabilities {
+	if protocolVersionBefore(version, protocolVersion20250326) {
+		caps.Completions = nil
+	}
+	return caps
+}
+
+// downgradeTool drops the fields of the given tool definition that don't exist in the given protocol version.
+func downgradeTool(tool *mcp.Tool, version string) {
+	if protocolVersionBefore(version, protocolVersion20250618) {
+		tool.Title = ""
+		tool.OutputSchema = nil
+		tool.Meta = nil
+		tool.Icons = nil
+	}
+	if protocolVersionBefore(version, protocolVersion20250326) {
+		tool.Annotations = nil
+	}
+}
+
+// maybeDowngradeResult converts the result of the given response to the protocol version of the client, if older
+// than the version spoken by the gateway.
+func (m *mcpRequestContext) maybeDowngradeResult(req *jsonrpc.Request, msg *jsonrpc.Response) {
+	if msg.Error != nil || msg.Result == nil || req.Method != "tools/call" {
+		return
+	}
+	version := m.clientProtocolVersion()
+	if !protocolVersionBefore(version, latestProtocolVersion) {
+		return
+	}
+	result, err := downgradeToolCallResult(msg.Result, version)
+	if err != nil {
+		m.l.Warn("failed to convert tools/call result to the protocol version of the client",
+			slog.String("version", version), slog.String("error", err.Error()))
+		return
+	}
+	msg.Result = result
+}
+
+// downgradeToolCallResult converts the given tools/call result to the given protocol version.
+//
+// The result is rewritten field by field so that the other fields are preserved:
+//   - Before 2025-06-18, the structured content is removed. If the result has no content, a text content with the
+//     serialized structured content is added instead, as recommended for backward compatibility.
+//   - Before 2025-06-18, the resource links are converted to text contents with the URI of the resource.
+//   - Before 2025-03-26, the audio contents are converted to text contents.
+func downgradeToolCallResult(raw []byte, version string) ([]byte, error) {
+	var result map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tools/call result: %w", err)
+	}
+	var content []map[string]json.RawMessage
+	if c, ok := result["content"]; ok {
+		if err := json.Unmarshal(c, &content); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal tools/call result content: %w", err)
+		}
+	}
+
+	modified := false
+	for i, block := range content {
+		var typ string
+		_ = json.Unmarshal(block["type"], &typ)
+		var text string
+		switch {
+		case typ == "resource_link" && protocolVersionBefore(version, protocolVersion20250618):
+			var uri, name string
+			_ = json.Unmarshal(block["uri"], &uri)
+			_ = json.Unmarshal(block["name"], &name)
+			text = uri
+			if name != "" {
+				text = fmt.Sprintf("%s: %s", name, uri)
+			}
+		case typ == "audio" && protocolVersionBefore(version, protocolVersion20250326):
+			var mimeType string
+			_ = json.Unmarshal(block["mimeType"], &mimeType)
+			text = fmt.Sprintf("[audio content of type %s omitted]", mimeType)
+		default:
+			continue
+		}
+		content[i] = map[string]json.RawMessage{"type": json.RawMessage(`"text"`), "text": rawJSON(text)}
+		modified = true
+	}
+	if structured, ok := result["structuredContent"]; ok && protocolVersionBefore(version, protocolVersion20250618) {
+		if len(content) == 0 {
+			content = append(content, map[string]json.RawMessage{"type": json.RawMessage(`"text"`), "text": rawJSON(string(structured))})
+		}
+		delete(result, "structuredContent")
+		modified = true
+	}
+	if !modified {
+		return raw, nil
+	}
+	result["content"] = rawJSON(content)
+	return json.Marshal(result)
+}
+
+// rawJSON marshals the given value that is known to be serializable.
+func rawJSON(v any) json.RawMessage {
+	b, _ := json.Marshal(v)
+	return b
+}
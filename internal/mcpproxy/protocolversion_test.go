@@ -0,0 +1,131 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateProtocolVersion(t *testing.T) {
+	require.Equal(t, protocolVersion20241105, negotiateProtocolVersion(protocolVersion20241105))
+	require.Equal(t, protocolVersion20250326, negotiateProtocolVersion(protocolVersion20250326))
+	require.Equal(t, protocolVersion20250618, negotiateProtocolVersion(protocolVersion20250618))
+	require.Equal(t, latestProtocolVersion, negotiateProtocolVersion("2099-01-01"))
+	require.Equal(t, latestProtocolVersion, negotiateProtocolVersion(""))
+}
+
+func TestClientProtocolVersion(t *testing.T) {
+	proxy := newTestMCPProxy()
+	// The version defaults to 2025-03-26 when the client does not send the header.
+	require.Equal(t, protocolVersion20250326, proxy.clientProtocolVersion())
+	proxy.requestHeaders = http.Header{"Mcp-Protocol-Version": {protocolVersion20241105}}
+	require.Equal(t, protocolVersion20241105, proxy.clientProtocolVersion())
+	proxy.requestHeaders = http.Header{"Mcp-Protocol-Version": {"unknown"}}
+	require.Equal(t, latestProtocolVersion, proxy.clientProtocolVersion())
+}
+
+func TestDowngradeServerCapabilities(t *testing.T) {
+	caps := downgradeServerCapabilities(&mcp.ServerCapabilities{Completions: &mcp.CompletionCapabilities{}}, protocolVersion20250326)
+	require.NotNil(t, caps.Completions)
+	caps = downgradeServerCapabilities(&mcp.ServerCapabilities{
+		Completions: &mcp.CompletionCapabilities{},
+		Tools:       &mcp.ToolCapabilities{},
+	}, protocolVersion20241105)
+	require.Nil(t, caps.Completions)
+	require.NotNil(t, caps.Tools)
+}
+
+func TestDowngradeTool(t *testing.T) {
+	newTool := func() *mcp.Tool {
+		return &mcp.Tool{
+			Name:         "tool",
+			Title:        "Tool",
+			Description:  "A tool",
+			Annotations:  &mcp.ToolAnnotations{ReadOnlyHint: true},
+			OutputSchema: map[string]any{"type": "object"},
+			Meta:         mcp.Meta{"key": "value"},
+		}
+	}
+
+	tool := newTool()
+	downgradeTool(tool, protocolVersion20250618)
+	require.Equal(t, newTool(), tool)
+
+	tool = newTool()
+	downgradeTool(tool, protocolVersion20250326)
+	require.Equal(t, &mcp.Tool{Name: "tool", Description: "A tool", Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true}}, tool)
+
+	tool = newTool()
+	downgradeTool(tool, protocolVersion20241105)
+	require.Equal(t, &mcp.Tool{Name: "tool", Description: "A tool"}, tool)
+}
+
+func TestDowngradeToolCallResult(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		version string
+		result  string
+		exp     string
+	}{
+		{
+			name:    "latest",
+			version: protocolVersion20250618,
+			result:  `{"content":[{"type":"resource_link","uri":"file:///a","name":"a"}],"structuredContent":{"a":1}}`,
+			exp:     `{"content":[{"type":"resource_link","uri":"file:///a","name":"a"}],"structuredContent":{"a":1}}`,
+		},
+		{
+			name:    "structured content with content",
+			version: protocolVersion20250326,
+			result:  `{"content":[{"type":"text","text":"{\"a\":1}"}],"structuredContent":{"a":1},"isError":false}`,
+			exp:     `{"content":[{"type":"text","text":"{\"a\":1}"}],"isError":false}`,
+		},
+		{
+			name:    "structured content without content",
+			version: protocolVersion20250326,
+			result:  `{"content":[],"structuredContent":{"a":1}}`,
+			exp:     `{"content":[{"type":"text","text":"{\"a\":1}"}]}`,
+		},
+		{
+			name:    "resource link",
+			version: protocolVersion20250326,
+			result:  `{"content":[{"type":"resource_link","uri":"file:///a","name":"a"},{"type":"audio","data":"AA==","mimeType":"audio/wav"}]}`,
+			exp:     `{"content":[{"type":"text","text":"a: file:///a"},{"type":"audio","data":"AA==","mimeType":"audio/wav"}]}`,
+		},
+		{
+			name:    "audio",
+			version: protocolVersion20241105,
+			result:  `{"content":[{"type":"audio","data":"AA==","mimeType":"audio/wav"},{"type":"text","text":"hi"}]}`,
+			exp:     `{"content":[{"type":"text","text":"[audio content of type audio/wav omitted]"},{"type":"text","text":"hi"}]}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := downgradeToolCallResult([]byte(tc.result), tc.version)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.exp, string(res))
+		})
+	}
+
+	_, err := downgradeToolCallResult([]byte(`[]`), protocolVersion20241105)
+	require.Error(t, err)
+}
+
+func TestMaybeDowngradeResult(t *testing.T) {
+	proxy := newTestMCPProxy()
+	proxy.requestHeaders = http.Header{"Mcp-Protocol-Version": {protocolVersion20250326}}
+	result := `{"content":[],"structuredContent":{"a":1}}`
+
+	msg := &jsonrpc.Response{Result: []byte(result)}
+	proxy.maybeDowngradeResult(&jsonrpc.Request{Method: "tools/list"}, msg)
+	require.JSONEq(t, result, string(msg.Result))
+
+	proxy.maybeDowngradeResult(&jsonrpc.Request{Method: "tools/call"}, msg)
+	require.JSONEq(t, `{"content":[{"type":"text","text":"{\"a\":1}"}]}`, string(msg.Result))
+}
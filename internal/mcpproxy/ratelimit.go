@@ -0,0 +1,286 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"golang.org/x/time/rate"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+)
+
+// jsonrpcCodeRateLimited is the JSON-RPC error code returned when a tool call is rejected by a rate limit.
+// The MCP spec doesn't define one, so we use a code from the implementation-defined server error range.
+const jsonrpcCodeRateLimited = -32029
+
+// maxRateLimitBuckets is the maximum number of per-client buckets a single rule keeps. Once reached, the idle
+// buckets are evicted first, then the least recently used one that has no in-flight calls.
+const maxRateLimitBuckets = 10000
+
+// concurrencyRetryAfter is the retry hint returned when a tool call is rejected by a concurrency limit.
+// Unlike the rate limits, there is no way to know when an in-flight call will complete.
+const concurrencyRetryAfter = time.Second
+
+type (
+	// rateLimiter enforces the rate and concurrency limits of a route on tool calls.
+	//
+	// The limits are local to this process, so they are enforced per external processor instance.
+	rateLimiter struct {
+		// config is the configuration this limiter was built from, used to preserve the state across config reloads.
+		config []filterapi.MCPRateLimitRule
+		rules  []*rateLimitRule
+	}
+
+	// rateLimitRule is a compiled filterapi.MCPRateLimitRule holding the per-client buckets.
+	rateLimitRule struct {
+		backend        filterapi.MCPBackendName
+		tool           string
+		clientKey      *filterapi.MCPRateLimitClientKey
+		limit          rate.Limit // zero when there is no rate limit.
+		burst          int
+		window         time.Duration
+		maxConcurrency int
+		maxBuckets     int
+
+		mu      sync.Mutex
+		buckets map[string]*rateLimitBucket
+	}
+
+	// rateLimitBucket is the state of a rule for a single client key.
+	rateLimitBucket struct {
+		limiter  *rate.Limiter
+		inFlight int
+		lastUsed time.Time
+	}
+
+	// errRateLimited is returned when a tool call is rejected by a rate or concurrency limit.
+	errRateLimited struct {
+		limitType  metrics.MCPRateLimitType
+		retryAfter time.Duration
+	}
+)
+
+func (e *errRateLimited) Error() string {
+	return fmt.Sprintf("%s limit exceeded, retry after %s", e.limitType, e.retryAfter)
+}
+
+// retryAfterSeconds returns the retry hint rounded up to whole seconds, which is at least one.
+func (e *errRateLimited) retryAfterSeconds() int {
+	return max(1, int(math.Ceil(e.retryAfter.Seconds())))
+}
+
+// rateLimitUnitDuration returns the duration of the given rate limit unit.
+func rateLimitUnitDuration(unit string) (time.Duration, error) {
+	switch unit {
+	case "Second":
+		return time.Second, nil
+	case "Minute":
+		return time.Minute, nil
+	case "Hour":
+		return time.Hour, nil
+	case "Day":
+		return 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("unknown rate limit unit %q", unit)
+	}
+}
+
+// newRateLimiter compiles the given rate limit rules. It returns nil if there are no rules.
+//
+// If prev was built from the same configuration, it is returned as-is so that the state of the limits
+// survives configuration reloads that don't affect them.
+func newRateLimiter(rules []filterapi.MCPRateLimitRule, prev *rateLimiter) (*rateLimiter, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	if prev != nil && reflect.DeepEqual(prev.config, rules) {
+		return prev, nil
+	}
+	rl := &rateLimiter{config: rules}
+	for i := range rules {
+		r := &rules[i]
+		compiled := &rateLimitRule{
+			backend:        r.Backend,
+			tool:           r.Tool,
+			clientKey:      r.ClientKey,
+			maxConcurrency: int(r.MaxConcurrency),
+			maxBuckets:     maxRateLimitBuckets,
+			buckets:        make(map[string]*rateLimitBucket),
+		}
+		if r.Requests != nil {
+			if r.Requests.Limit == 0 {
+				return nil, fmt.Errorf("rate limit rule %d: limit must be greater than zero", i)
+			}
+			window, err := rateLimitUnitDuration(r.Requests.Unit)
+			if err != nil {
+				return nil, fmt.Errorf("rate limit rule %d: %w", i, err)
+			}
+			compiled.window = window
+			compiled.burst = int(r.Requests.Limit)
+			compiled.limit = rate.Limit(float64(r.Requests.Limit) / window.Seconds())
+		}
+		if r.ClientKey != nil && r.ClientKey.Type == filterapi.MCPRateLimitClientKeyTypeHeader && r.ClientKey.Header == "" {
+			return nil, fmt.Errorf("rate limit rule %d: header must be set for the Header client key", i)
+		}
+		rl.rules = append(rl.rules, compiled)
+	}
+	return rl, nil
+}
+
+// matches returns true if the rule applies to the given backend and tool.
+func (r *rateLimitRule) matches(backend filterapi.MCPBackendName, tool string) bool {
+	if r.backend != "" && r.backend != backend {
+		return false
+	}
+	return r.tool == "" || r.tool == tool
+}
+
+// key returns the client key of the given request for this rule.
+func (r *rateLimitRule) key(req *http.Request) string {
+	if r.clientKey == nil {
+		return ""
+	}
+	switch r.clientKey.Type {
+	case filterapi.MCPRateLimitClientKeyTypeSubject:
+		return extractSubject(req)
+	case filterapi.MCPRateLimitClientKeyTypeHeader:
+		return req.Header.Get(r.clientKey.Header)
+	default:
+		return ""
+	}
+}
+
+// acquire tries to take a slot from the bucket of the given key. On success, the returned function must be called
+// once the call completes to release the concurrency slot.
+func (r *rateLimitRule) acquire(key string, now time.Time) (release func(), cancel func(), err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.buckets[key]
+	if !ok {
+		if len(r.buckets) >= r.maxBuckets {
+			r.evictIdleLocked(now)
+		}
+		if len(r.buckets) >= r.maxBuckets && !r.evictLeastRecentlyUsedLocked() {
+			// Every bucket has in-flight calls, so there is no room for a new client until one of them completes.
+			return nil, nil, &errRateLimited{limitType: metrics.MCPRateLimitTypeConcurrency, retryAfter: concurrencyRetryAfter}
+		}
+		b = &rateLimitBucket{}
+		if r.limit > 0 {
+			b.limiter = rate.NewLimiter(r.limit, r.burst)
+		}
+		r.buckets[key] = b
+	}
+	b.lastUsed = now
+
+	if r.maxConcurrency > 0 && b.inFlight >= r.maxConcurrency {
+		return nil, nil, &errRateLimited{limitType: metrics.MCPRateLimitTypeConcurrency, retryAfter: concurrencyRetryAfter}
+	}
+	cancel = func() {}
+	if b.limiter != nil {
+		reservation := b.limiter.ReserveN(now, 1)
+		if delay := reservation.DelayFrom(now); delay > 0 {
+			reservation.CancelAt(now)
+			return nil, nil, &errRateLimited{limitType: metrics.MCPRateLimitTypeRate, retryAfter: delay}
+		}
+		cancel = func() { reservation.CancelAt(now) }
+	}
+	b.inFlight++
+	release = func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		b.inFlight--
+	}
+	return release, cancel, nil
+}
+
+// evictIdleLocked removes the buckets that have no in-flight calls and have been idle for longer than the
+// rate limit window, which means their limiter is full again. r.mu must be held.
+func (r *rateLimitRule) evictIdleLocked(now time.Time) {
+	for key, b := range r.buckets {
+		if b.inFlight == 0 && now.Sub(b.lastUsed) >= r.window {
+			delete(r.buckets, key)
+		}
+	}
+}
+
+// evictLeastRecentlyUsedLocked removes the least recently used bucket that has no in-flight calls. It returns false
+// if there is no such bucket. r.mu must be held.
+func (r *rateLimitRule) evictLeastRecentlyUsedLocked() bool {
+	var (
+		oldestKey string
+		oldest    *rateLimitBucket
+	)
+	for key, b := range r.buckets {
+		if b.inFlight == 0 && (oldest == nil || b.lastUsed.Before(oldest.lastUsed)) {
+			oldestKey, oldest = key, b
+		}
+	}
+	if oldest == nil {
+		return false
+	}
+	delete(r.buckets, oldestKey)
+	return true
+}
+
+// acquire checks all the rules matching the given tool call. On success, the returned function must be called
+// once the call completes. If any rule is exceeded, the slots taken from the previously checked rules are given back.
+func (l *rateLimiter) acquire(backend filterapi.MCPBackendName, tool string, req *http.Request) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	now := time.Now()
+	var releases, cancels []func()
+	for _, rule := range l.rules {
+		if !rule.matches(backend, tool) {
+			continue
+		}
+		release, cancel, err := rule.acquire(rule.key(req), now)
+		if err != nil {
+			for i := range releases {
+				releases[i]()
+				cancels[i]()
+			}
+			return nil, err
+		}
+		releases = append(releases, release)
+		cancels = append(cancels, cancel)
+	}
+	return func() {
+		for _, release := range releases {
+			release()
+		}
+	}, nil
+}
+
+// onRateLimitedResponse writes a JSON-RPC error response for a request rejected by a rate limit, including
+// the retry hints both in the error data and in the Retry-After header.
+func onRateLimitedResponse(w http.ResponseWriter, id jsonrpc.ID, rateLimitErr *errRateLimited) {
+	retryAfter := rateLimitErr.retryAfterSeconds()
+	data, _ := json.Marshal(map[string]any{
+		"retryAfterSeconds": retryAfter,
+		"limitType":         string(rateLimitErr.limitType),
+	})
+	encoded, _ := jsonrpc.EncodeMessage(&jsonrpc.Response{ID: id, Error: &jsonrpc.Error{
+		Code:    jsonrpcCodeRateLimited,
+		Message: rateLimitErr.Error(),
+		Data:    data,
+	}})
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(encoded)
+}
@@ -0,0 +1,248 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	t.Run("no rules", func(t *testing.T) {
+		rl, err := newRateLimiter(nil, nil)
+		require.NoError(t, err)
+		require.Nil(t, rl)
+	})
+	t.Run("invalid unit", func(t *testing.T) {
+		_, err := newRateLimiter([]filterapi.MCPRateLimitRule{
+			{Requests: &filterapi.MCPRateLimitValue{Limit: 1, Unit: "Week"}},
+		}, nil)
+		require.ErrorContains(t, err, `rate limit rule 0: unknown rate limit unit "Week"`)
+	})
+	t.Run("zero limit", func(t *testing.T) {
+		_, err := newRateLimiter([]filterapi.MCPRateLimitRule{
+			{Requests: &filterapi.MCPRateLimitValue{Limit: 0, Unit: "Second"}},
+		}, nil)
+		require.ErrorContains(t, err, "rate limit rule 0: limit must be greater than zero")
+	})
+	t.Run("missing header", func(t *testing.T) {
+		_, err := newRateLimiter([]filterapi.MCPRateLimitRule{
+			{MaxConcurrency: 1, ClientKey: &filterapi.MCPRateLimitClientKey{Type: filterapi.MCPRateLimitClientKeyTypeHeader}},
+		}, nil)
+		require.ErrorContains(t, err, "rate limit rule 0: header must be set for the Header client key")
+	})
+	t.Run("reuses previous limiter with the same config", func(t *testing.T) {
+		rules := []filterapi.MCPRateLimitRule{{Requests: &filterapi.MCPRateLimitValue{Limit: 10, Unit: "Minute"}}}
+		prev, err := newRateLimiter(rules, nil)
+		require.NoError(t, err)
+		require.Len(t, prev.rules, 1)
+		require.InDelta(t, 10.0/60.0, float64(prev.rules[0].limit), 1e-9)
+		require.Equal(t, 10, prev.rules[0].burst)
+
+		same, err := newRateLimiter([]filterapi.MCPRateLimitRule{{Requests: &filterapi.MCPRateLimitValue{Limit: 10, Unit: "Minute"}}}, prev)
+		require.NoError(t, err)
+		require.Same(t, prev, same)
+
+		changed, err := newRateLimiter([]filterapi.MCPRateLimitRule{{Requests: &filterapi.MCPRateLimitValue{Limit: 20, Unit: "Minute"}}}, prev)
+		require.NoError(t, err)
+		require.NotSame(t, prev, changed)
+	})
+}
+
+func TestRateLimiter_acquire(t *testing.T) {
+	t.Run("nil limiter", func(t *testing.T) {
+		var rl *rateLimiter
+		release, err := rl.acquire("backend1", "tool", httptest.NewRequest("POST", "/mcp", nil))
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("rate limit per tool", func(t *testing.T) {
+		rl, err := newRateLimiter([]filterapi.MCPRateLimitRule{
+			{Backend: "backend1", Tool: "tool-a", Requests: &filterapi.MCPRateLimitValue{Limit: 2, Unit: "Hour"}},
+		}, nil)
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/mcp", nil)
+
+		for range 2 {
+			release, err := rl.acquire("backend1", "tool-a", req)
+			require.NoError(t, err)
+			release()
+		}
+		_, err = rl.acquire("backend1", "tool-a", req)
+		var rateLimitErr *errRateLimited
+		require.ErrorAs(t, err, &rateLimitErr)
+		require.Equal(t, metrics.MCPRateLimitTypeRate, rateLimitErr.limitType)
+		// One token is refilled every 30 minutes.
+		require.InDelta(t, 30*time.Minute, rateLimitErr.retryAfter, float64(time.Second))
+		require.Equal(t, 1800, rateLimitErr.retryAfterSeconds())
+
+		// Other tools and backends are not affected.
+		release, err := rl.acquire("backend1", "tool-b", req)
+		require.NoError(t, err)
+		release()
+		release, err = rl.acquire("backend2", "tool-a", req)
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("concurrency limit", func(t *testing.T) {
+		rl, err := newRateLimiter([]filterapi.MCPRateLimitRule{{MaxConcurrency: 1}}, nil)
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/mcp", nil)
+
+		release, err := rl.acquire("backend1", "tool-a", req)
+		require.NoError(t, err)
+		_, err = rl.acquire("backend2", "tool-b", req)
+		var rateLimitErr *errRateLimited
+		require.ErrorAs(t, err, &rateLimitErr)
+		require.Equal(t, metrics.MCPRateLimitTypeConcurrency, rateLimitErr.limitType)
+		require.Equal(t, 1, rateLimitErr.retryAfterSeconds())
+
+		release()
+		release, err = rl.acquire("backend2", "tool-b", req)
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("header client key", func(t *testing.T) {
+		rl, err := newRateLimiter([]filterapi.MCPRateLimitRule{{
+			ClientKey: &filterapi.MCPRateLimitClientKey{Type: filterapi.MCPRateLimitClientKeyTypeHeader, Header: "x-user"},
+			Requests:  &filterapi.MCPRateLimitValue{Limit: 1, Unit: "Day"},
+		}}, nil)
+		require.NoError(t, err)
+
+		alice := httptest.NewRequest("POST", "/mcp", nil)
+		alice.Header.Set("x-user", "alice")
+		bob := httptest.NewRequest("POST", "/mcp", nil)
+		bob.Header.Set("x-user", "bob")
+
+		release, err := rl.acquire("backend1", "tool", alice)
+		require.NoError(t, err)
+		release()
+		_, err = rl.acquire("backend1", "tool", alice)
+		require.Error(t, err)
+		release, err = rl.acquire("backend1", "tool", bob)
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("subject client key", func(t *testing.T) {
+		rl, err := newRateLimiter([]filterapi.MCPRateLimitRule{{
+			ClientKey:      &filterapi.MCPRateLimitClientKey{Type: filterapi.MCPRateLimitClientKeyTypeSubject},
+			MaxConcurrency: 1,
+		}}, nil)
+		require.NoError(t, err)
+
+		newReq := func(sub string) *http.Request {
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Subject: sub}).SignedString([]byte("secret"))
+			require.NoError(t, err)
+			req := httptest.NewRequest("POST", "/mcp", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			return req
+		}
+
+		release, err := rl.acquire("backend1", "tool", newReq("alice"))
+		require.NoError(t, err)
+		defer release()
+		_, err = rl.acquire("backend1", "tool", newReq("alice"))
+		require.Error(t, err)
+		releaseBob, err := rl.acquire("backend1", "tool", newReq("bob"))
+		require.NoError(t, err)
+		releaseBob()
+	})
+
+	t.Run("rejection gives back slots of other rules", func(t *testing.T) {
+		rl, err := newRateLimiter([]filterapi.MCPRateLimitRule{
+			{Requests: &filterapi.MCPRateLimitValue{Limit: 1, Unit: "Hour"}, MaxConcurrency: 1},
+			{Backend: "backend1", MaxConcurrency: 1},
+		}, nil)
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/mcp", nil)
+
+		// Occupy the backend1 concurrency slot only through the second rule.
+		backendRule := rl.rules[1]
+		releaseBackend, _, err := backendRule.acquire("", time.Now())
+		require.NoError(t, err)
+
+		_, err = rl.acquire("backend1", "tool", req)
+		require.Error(t, err)
+		// The first rule's rate token and concurrency slot must have been given back.
+		releaseBackend()
+		release, err := rl.acquire("backend1", "tool", req)
+		require.NoError(t, err)
+		release()
+	})
+}
+
+func TestRateLimitRule_evictIdleLocked(t *testing.T) {
+	rule := &rateLimitRule{window: time.Minute, buckets: map[string]*rateLimitBucket{}}
+	now := time.Now()
+	rule.buckets["idle"] = &rateLimitBucket{lastUsed: now.Add(-2 * time.Minute)}
+	rule.buckets["recent"] = &rateLimitBucket{lastUsed: now.Add(-time.Second)}
+	rule.buckets["in-flight"] = &rateLimitBucket{lastUsed: now.Add(-2 * time.Minute), inFlight: 1}
+	rule.evictIdleLocked(now)
+	require.Len(t, rule.buckets, 2)
+	require.NotContains(t, rule.buckets, "idle")
+}
+
+func TestRateLimitRule_acquire_maxBuckets(t *testing.T) {
+	rule := &rateLimitRule{window: time.Minute, maxConcurrency: 1, maxBuckets: 2, buckets: map[string]*rateLimitBucket{}}
+	now := time.Now()
+
+	releaseA, _, err := rule.acquire("a", now)
+	require.NoError(t, err)
+	releaseB, _, err := rule.acquire("b", now.Add(time.Second))
+	require.NoError(t, err)
+
+	// Every bucket is recent and in flight, so a new client is rejected instead of growing the buckets.
+	_, _, err = rule.acquire("c", now.Add(2*time.Second))
+	var rateLimitErr *errRateLimited
+	require.ErrorAs(t, err, &rateLimitErr)
+	require.Len(t, rule.buckets, 2)
+
+	// Once the calls complete, the least recently used bucket is evicted for the new client.
+	releaseA()
+	releaseB()
+	releaseC, _, err := rule.acquire("c", now.Add(3*time.Second))
+	require.NoError(t, err)
+	releaseC()
+	require.Len(t, rule.buckets, 2)
+	require.NotContains(t, rule.buckets, "a")
+	require.Contains(t, rule.buckets, "b")
+}
+
+func TestOnRateLimitedResponse(t *testing.T) {
+	id, err := jsonrpc.MakeID("req-1")
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	onRateLimitedResponse(rr, id, &errRateLimited{limitType: metrics.MCPRateLimitTypeRate, retryAfter: 1500 * time.Millisecond})
+
+	require.Equal(t, 200, rr.Code)
+	require.Equal(t, "2", rr.Header().Get("Retry-After"))
+	require.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	msg, err := jsonrpc.DecodeMessage(rr.Body.Bytes())
+	require.NoError(t, err)
+	resp, ok := msg.(*jsonrpc.Response)
+	require.True(t, ok)
+	require.Equal(t, id, resp.ID)
+	var wireErr *jsonrpc.Error
+	require.ErrorAs(t, resp.Error, &wireErr)
+	require.Equal(t, int64(jsonrpcCodeRateLimited), wireErr.Code)
+	var data map[string]any
+	require.NoError(t, json.Unmarshal(wireErr.Data, &data))
+	require.Equal(t, map[string]any{"retryAfterSeconds": float64(2), "limitType": "rate"}, data)
+}
@@ -0,0 +1,190 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+)
+
+// samplingIncludeContexts ranks the includeContext values of the sampling requests from the narrowest to the widest.
+var samplingIncludeContexts = map[string]int{"none": 0, "thisServer": 1, "allServers": 2}
+
+// applyServerRequestPolicy applies the policy of the given backend to the given server->client request before it is
+// forwarded to the client. The sampling requests are limited in place.
+//
+// It returns false when the request is denied. The backend has then been answered with a JSON-RPC error, and the
+// request must not be forwarded to the client.
+func (m *mcpRequestContext) applyServerRequestPolicy(ctx context.Context, s *session, msg *jsonrpc.Request, backendName filterapi.MCPBackendName) bool {
+	if msg.Method != "sampling/createMessage" && msg.Method != "elicitation/create" {
+		return true
+	}
+	backend, err := m.getBackendForRoute(s.route, backendName)
+	if err != nil || backend.ServerRequests == nil {
+		return true
+	}
+
+	var (
+		action  filterapi.MCPServerRequestAction
+		denyErr *jsonrpc.Error
+	)
+	switch msg.Method {
+	case "sampling/createMessage":
+		policy := backend.ServerRequests.Sampling
+		if policy == nil {
+			return true
+		}
+		action = policy.Action
+		denyErr = limitSamplingRequest(msg, policy)
+	case "elicitation/create":
+		policy := backend.ServerRequests.Elicitation
+		if policy == nil {
+			return true
+		}
+		action = policy.Action
+		denyErr = checkElicitationRequest(msg, policy)
+	}
+	switch {
+	case action == filterapi.MCPServerRequestActionDeny:
+		denyErr = &jsonrpc.Error{Code: jsonrpc.CodeInvalidRequest, Message: fmt.Sprintf("%s is not allowed by the gateway", msg.Method)}
+	case action == filterapi.MCPServerRequestActionAudit && m.auditLog == nil && denyErr == nil:
+		// The requests that must be audited cannot be forwarded without the audit log.
+		denyErr = &jsonrpc.Error{Code: jsonrpc.CodeInvalidRequest, Message: fmt.Sprintf("%s is not allowed by the gateway", msg.Method)}
+		m.l.Warn("audit log is not enabled, denying the server->client request to be audited",
+			slog.String("method", msg.Method), slog.String("backend", backendName))
+	}
+
+	if denyErr == nil {
+		if action == filterapi.MCPServerRequestActionAudit {
+			m.recordServerRequestAudit(ctx, s, msg.Method, backendName, nil)
+		}
+		return true
+	}
+	m.l.Warn("denied server->client request", slog.String("method", msg.Method), slog.String("backend", backendName),
+		slog.String("reason", denyErr.Message))
+	m.metrics.RecordSecurityEvent(ctx, metrics.MCPSecurityEventServerRequestDenied, nil)
+	m.recordServerRequestAudit(ctx, s, msg.Method, backendName, denyErr)
+	m.respondToServerRequest(ctx, s, backend, &jsonrpc.Response{ID: msg.ID, Error: denyErr})
+	return false
+}
+
+// limitSamplingRequest caps the maxTokens and the includeContext of the given sampling request according to the
+// given policy. It returns an error if the params of the request cannot be decoded.
+func limitSamplingRequest(msg *jsonrpc.Request, policy *filterapi.MCPSamplingPolicy) *jsonrpc.Error {
+	if policy.MaxTokens == 0 && policy.IncludeContext == "" {
+		return nil
+	}
+	// The params are rewritten field by field so that the fields unknown to the gateway are preserved.
+	var params map[string]json.RawMessage
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: "invalid sampling/createMessage params"}
+	}
+	modified := false
+	if policy.MaxTokens > 0 {
+		var maxTokens int64
+		if raw, ok := params["maxTokens"]; ok && json.Unmarshal(raw, &maxTokens) == nil && maxTokens > policy.MaxTokens {
+			params["maxTokens"], _ = json.Marshal(policy.MaxTokens)
+			modified = true
+		}
+	}
+	if limit, ok := samplingIncludeContexts[policy.IncludeContext]; ok {
+		var includeContext string
+		if raw, ok := params["includeContext"]; ok && json.Unmarshal(raw, &includeContext) == nil &&
+			samplingIncludeContexts[includeContext] > limit {
+			params["includeContext"], _ = json.Marshal(policy.IncludeContext)
+			modified = true
+		}
+	}
+	if modified {
+		msg.Params, _ = json.Marshal(params) // Already decoded params, so ignore error.
+	}
+	return nil
+}
+
+// checkElicitationRequest returns an error if the schema requested by the given elicitation request has a property
+// matching one of the denied fields of the given policy.
+func checkElicitationRequest(msg *jsonrpc.Request, policy *filterapi.MCPElicitationPolicy) *jsonrpc.Error {
+	var params struct {
+		RequestedSchema struct {
+			Properties map[string]struct {
+				Title string `json:"title"`
+			} `json:"properties"`
+		} `json:"requestedSchema"`
+	}
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: "invalid elicitation/create params"}
+	}
+	for name, property := range params.RequestedSchema.Properties {
+		for _, denied := range policy.DeniedFields {
+			denied = normalizeFieldName(denied)
+			if denied == "" {
+				continue
+			}
+			if strings.Contains(normalizeFieldName(name), denied) || strings.Contains(normalizeFieldName(property.Title), denied) {
+				return &jsonrpc.Error{
+					Code:    jsonrpc.CodeInvalidParams,
+					Message: fmt.Sprintf("elicitation of the sensitive field %q is not allowed by the gateway", name),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeFieldName lowercases the given field name and removes its spaces, dashes and underscores.
+func normalizeFieldName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_':
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
+
+// respondToServerRequest sends the given response to a server->client request to the given backend on behalf of the
+// client.
+func (m *mcpRequestContext) respondToServerRequest(ctx context.Context, s *session, backend filterapi.MCPBackend, res *jsonrpc.Response) {
+	cse := s.getCompositeSessionEntry(backend.Name)
+	if cse == nil {
+		return
+	}
+	resp, err := m.invokeJSONRPCRequest(ctx, s.route, backend, cse, res, nil)
+	if err != nil {
+		m.l.Error("failed to respond to server->client request", slog.String("backend", backend.Name), slog.String("error", err.Error()))
+		return
+	}
+	ensureHTTPConnectionReused(resp)
+}
+
+// recordServerRequestAudit records the given server->client request in the audit log, if enabled. The error is the
+// reason the request was denied, if it was.
+func (m *mcpRequestContext) recordServerRequestAudit(ctx context.Context, s *session, method string, backend filterapi.MCPBackendName, denyErr *jsonrpc.Error) {
+	if m.auditLog == nil {
+		return
+	}
+	record := &AuditRecord{
+		Time:    time.Now(),
+		Method:  method,
+		Route:   s.route,
+		Backend: backend,
+		Status:  metrics.MCPStatusSuccess,
+	}
+	if denyErr != nil {
+		record.Status = metrics.MCPStatusError
+		record.ErrorType = metrics.MCPErrorServerRequestDenied
+	}
+	m.auditLog.sink.Record(ctx, record)
+}
@@ -0,0 +1,202 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+)
+
+func TestLimitSamplingRequest(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		policy filterapi.MCPSamplingPolicy
+		params string
+		exp    string
+	}{
+		{
+			name:   "no limits",
+			policy: filterapi.MCPSamplingPolicy{},
+			params: `{"maxTokens":1000,"includeContext":"allServers","messages":[]}`,
+			exp:    `{"maxTokens":1000,"includeContext":"allServers","messages":[]}`,
+		},
+		{
+			name:   "capped",
+			policy: filterapi.MCPSamplingPolicy{MaxTokens: 100, IncludeContext: "thisServer"},
+			params: `{"maxTokens":1000,"includeContext":"allServers","messages":[],"tools":[{"name":"t"}]}`,
+			exp:    `{"maxTokens":100,"includeContext":"thisServer","messages":[],"tools":[{"name":"t"}]}`,
+		},
+		{
+			name:   "within limits",
+			policy: filterapi.MCPSamplingPolicy{MaxTokens: 100, IncludeContext: "thisServer"},
+			params: `{"maxTokens":10,"includeContext":"none","messages":[]}`,
+			exp:    `{"maxTokens":10,"includeContext":"none","messages":[]}`,
+		},
+		{
+			name:   "context stripped",
+			policy: filterapi.MCPSamplingPolicy{IncludeContext: "none"},
+			params: `{"maxTokens":10,"includeContext":"thisServer","messages":[]}`,
+			exp:    `{"maxTokens":10,"includeContext":"none","messages":[]}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := &jsonrpc.Request{Method: "sampling/createMessage", Params: []byte(tc.params)}
+			require.Nil(t, limitSamplingRequest(msg, &tc.policy))
+			require.JSONEq(t, tc.exp, string(msg.Params))
+		})
+	}
+
+	err := limitSamplingRequest(&jsonrpc.Request{Params: []byte(`[]`)}, &filterapi.MCPSamplingPolicy{MaxTokens: 1})
+	require.NotNil(t, err)
+	require.Equal(t, int64(jsonrpc.CodeInvalidParams), err.Code)
+}
+
+func TestCheckElicitationRequest(t *testing.T) {
+	policy := &filterapi.MCPElicitationPolicy{DeniedFields: []string{"password", "apiKey", ""}}
+	for _, tc := range []struct {
+		name   string
+		schema string
+		denied bool
+	}{
+		{name: "allowed", schema: `{"type":"object","properties":{"name":{"type":"string"},"email":{"type":"string"}}}`},
+		{name: "no schema", schema: `null`},
+		{name: "name", schema: `{"type":"object","properties":{"Password":{"type":"string"}}}`, denied: true},
+		{name: "normalized name", schema: `{"type":"object","properties":{"openai_api-key":{"type":"string"}}}`, denied: true},
+		{name: "title", schema: `{"type":"object","properties":{"value":{"type":"string","title":"Your API key"}}}`, denied: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := &jsonrpc.Request{Method: "elicitation/create", Params: []byte(`{"message":"hi","requestedSchema":` + tc.schema + `}`)}
+			err := checkElicitationRequest(msg, policy)
+			if tc.denied {
+				require.NotNil(t, err)
+				require.Equal(t, int64(jsonrpc.CodeInvalidParams), err.Code)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestApplyServerRequestPolicy(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		responses []string
+	)
+	backendServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		responses = append(responses, r.Header.Get(internalapi.MCPBackendHeader)+" "+string(body))
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(backendServer.Close)
+
+	newProxy := func(policy *filterapi.MCPServerRequestPolicy) (*mcpRequestContext, *session) {
+		proxy := newTestMCPProxy()
+		proxy.backendListenerAddr = backendServer.URL
+		proxy.routes["test-route"].backends["backend1"] = filterapi.MCPBackend{Name: "backend1", ServerRequests: policy}
+		s := &session{
+			reqCtx: proxy,
+			route:  "test-route",
+			perBackendSessions: map[filterapi.MCPBackendName]*compositeSessionEntry{
+				"backend1": {backendName: "backend1", sessionID: "session1"},
+			},
+		}
+		return proxy, s
+	}
+	samplingRequest := func() *jsonrpc.Request {
+		return &jsonrpc.Request{ID: mustJSONRPCRequestID(), Method: "sampling/createMessage", Params: []byte(`{"maxTokens":1000,"messages":[]}`)}
+	}
+
+	t.Run("no policy", func(t *testing.T) {
+		proxy, s := newProxy(nil)
+		require.True(t, proxy.applyServerRequestPolicy(t.Context(), s, samplingRequest(), "backend1"))
+		proxy, s = newProxy(&filterapi.MCPServerRequestPolicy{
+			Elicitation: &filterapi.MCPElicitationPolicy{Action: filterapi.MCPServerRequestActionDeny},
+		})
+		require.True(t, proxy.applyServerRequestPolicy(t.Context(), s, samplingRequest(), "backend1"))
+		require.True(t, proxy.applyServerRequestPolicy(t.Context(), s, &jsonrpc.Request{Method: "roots/list"}, "backend1"))
+	})
+
+	t.Run("allow", func(t *testing.T) {
+		proxy, s := newProxy(&filterapi.MCPServerRequestPolicy{
+			Sampling: &filterapi.MCPSamplingPolicy{Action: filterapi.MCPServerRequestActionAllow, MaxTokens: 10},
+		})
+		msg := samplingRequest()
+		require.True(t, proxy.applyServerRequestPolicy(t.Context(), s, msg, "backend1"))
+		require.JSONEq(t, `{"maxTokens":10,"messages":[]}`, string(msg.Params))
+	})
+
+	t.Run("deny", func(t *testing.T) {
+		proxy, s := newProxy(&filterapi.MCPServerRequestPolicy{
+			Sampling: &filterapi.MCPSamplingPolicy{Action: filterapi.MCPServerRequestActionDeny},
+		})
+		sink := &recordingAuditSink{}
+		proxy.auditLog = &AuditLog{sink: sink, arguments: AuditArgumentsRedact}
+		mu.Lock()
+		responses = nil
+		mu.Unlock()
+
+		msg := samplingRequest()
+		require.False(t, proxy.applyServerRequestPolicy(t.Context(), s, msg, "backend1"))
+		mu.Lock()
+		require.Len(t, responses, 1)
+		require.True(t, strings.HasPrefix(responses[0], "backend1 "))
+		res, err := jsonrpc.DecodeMessage([]byte(strings.TrimPrefix(responses[0], "backend1 ")))
+		mu.Unlock()
+		require.NoError(t, err)
+		require.Equal(t, msg.ID, res.(*jsonrpc.Response).ID)
+		require.Equal(t, int64(jsonrpc.CodeInvalidRequest), res.(*jsonrpc.Response).Error.(*jsonrpc.Error).Code)
+
+		require.Len(t, sink.records, 1)
+		require.Equal(t, "sampling/createMessage", sink.records[0].Method)
+		require.Equal(t, "backend1", sink.records[0].Backend)
+		require.Equal(t, metrics.MCPStatusError, sink.records[0].Status)
+		require.Equal(t, metrics.MCPErrorServerRequestDenied, sink.records[0].ErrorType)
+	})
+
+	t.Run("sensitive elicitation", func(t *testing.T) {
+		proxy, s := newProxy(&filterapi.MCPServerRequestPolicy{
+			Elicitation: &filterapi.MCPElicitationPolicy{Action: filterapi.MCPServerRequestActionAllow, DeniedFields: []string{"password"}},
+		})
+		require.True(t, proxy.applyServerRequestPolicy(t.Context(), s, &jsonrpc.Request{
+			ID: mustJSONRPCRequestID(), Method: "elicitation/create",
+			Params: []byte(`{"message":"hi","requestedSchema":{"type":"object","properties":{"name":{"type":"string"}}}}`),
+		}, "backend1"))
+		require.False(t, proxy.applyServerRequestPolicy(t.Context(), s, &jsonrpc.Request{
+			ID: mustJSONRPCRequestID(), Method: "elicitation/create",
+			Params: []byte(`{"message":"hi","requestedSchema":{"type":"object","properties":{"password":{"type":"string"}}}}`),
+		}, "backend1"))
+	})
+
+	t.Run("audit", func(t *testing.T) {
+		policy := &filterapi.MCPServerRequestPolicy{
+			Sampling: &filterapi.MCPSamplingPolicy{Action: filterapi.MCPServerRequestActionAudit},
+		}
+		// The requests cannot be audited without the audit log.
+		proxy, s := newProxy(policy)
+		require.False(t, proxy.applyServerRequestPolicy(t.Context(), s, samplingRequest(), "backend1"))
+
+		proxy, s = newProxy(policy)
+		sink := &recordingAuditSink{}
+		proxy.auditLog = &AuditLog{sink: sink, arguments: AuditArgumentsRedact}
+		require.True(t, proxy.applyServerRequestPolicy(t.Context(), s, samplingRequest(), "backend1"))
+		require.Len(t, sink.records, 1)
+		require.Equal(t, metrics.MCPStatusSuccess, sink.records[0].Status)
+		require.Equal(t, "test-route", sink.records[0].Route)
+	})
+}
@@ -7,6 +7,7 @@ package mcpproxy
 
 import (
 	"bytes"
+	"cmp"
 	"compress/gzip"
 	"context"
 	"encoding/base64"
@@ -34,9 +35,8 @@ import (
 const (
 	// https://github.com/modelcontextprotocol/go-sdk/blob/392f719bd1956e7601cf85f7a9b24c7010cffb4c/mcp/streamable.go#L31-L32
 
-	sessionIDHeader         = "mcp-session-id"
-	protocolVersionHeader   = "mcp-protocol-version"
-	protocolVersion20250618 = "2025-06-18"
+	sessionIDHeader       = "mcp-session-id"
+	protocolVersionHeader = "mcp-protocol-version"
 
 	lastEventIDHeader = "Last-Event-Id"
 )
@@ -73,7 +73,7 @@ func (s *session) Close() error {
 			// Stateless backend, nothing to do.
 			continue
 		}
-		req, err := http.NewRequest(http.MethodDelete, s.reqCtx.backendListenerAddr, nil)
+		req, err := http.NewRequest(http.MethodDelete, s.reqCtx.backendURL(s.route, backendName), nil)
 		if err != nil {
 			s.reqCtx.l.Error("failed to create DELETE request to MCP server to close session",
 				slog.String("backend", backendName),
@@ -85,6 +85,16 @@ func (s *session) Close() error {
 		addMCPHeaders(req, nil, nil, s.route, backendName)
 		s.reqCtx.applyOriginalPathHeaders(req)
 		req.Header.Set(sessionIDHeader, sessionID.String())
+		if backend, err := s.reqCtx.getBackendForRoute(s.route, backendName); err == nil {
+			if err = s.reqCtx.setBackendCredential(req.Context(), req, backend); err != nil {
+				s.reqCtx.l.Error("failed to close session on MCP server",
+					slog.String("backend", backendName),
+					slog.String("session_id", string(sessionID)),
+					slog.String("error", err.Error()),
+				)
+				continue
+			}
+		}
 		resp, err := s.reqCtx.client.Do(req)
 		if err != nil {
 			s.reqCtx.l.Error("failed to send DELETE request to MCP server to close session",
@@ -236,16 +246,36 @@ func (s *session) streamNotifications(ctx context.Context, w http.ResponseWriter
 					slog.String("prev_event_id", prev),
 					slog.String("event_id", event.id))
 			}
+			messages := event.messages[:0]
 			for _, _msg := range event.messages {
+				record := true
 				// Maybe the server->client request made during the notification handling needs to be modified.
 				if msg, ok := _msg.(*jsonrpc.Request); ok {
+					if !s.reqCtx.applyServerRequestPolicy(ctx, s, msg, event.backend) {
+						// The request was denied and answered by the gateway.
+						continue
+					}
 					if err := s.reqCtx.maybeServerToClientRequestModify(ctx, msg, event.backend); err != nil {
 						s.reqCtx.l.Error("failed to modify server->client request", slog.String("error", err.Error()))
-						continue
+						record = false
 					}
 				}
-				s.reqCtx.recordResponse(ctx, _msg)
+				// Resource updates are broadcast by some backends regardless of the subscriptions of the session.
+				if !s.isSubscribed(ctx, _msg) {
+					continue
+				}
+				if record {
+					s.reqCtx.recordResponse(ctx, _msg)
+				}
+				s.reqCtx.maybeInvalidateToolSearchIndex(s, _msg)
+				messages = append(messages, _msg)
+			}
+			if len(messages) == 0 && len(event.messages) > 0 {
+				// All the messages were filtered out, so there is nothing to send to the client.
+				continue
 			}
+			event.messages = messages
+			s.storeEvent(ctx, event.sseEvent)
 			event.writeAndMaybeFlush(w)
 			// Reset the heartbeat ticker so that the next heartbeat will be sent after the full interval.
 			// This avoids sending heartbeats too frequently when there are events.
@@ -356,7 +386,7 @@ func (s *session) sendRequestPerBackend(ctx context.Context, eventChan chan<- *b
 		body = bytes.NewReader(encodedReq)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, httpMethod, s.reqCtx.backendListenerAddr, body)
+	req, err := http.NewRequestWithContext(ctx, httpMethod, s.reqCtx.backendURL(routeName, backend.Name), body)
 	if err != nil {
 		return fmt.Errorf("failed to create GET request: %w", err)
 	}
@@ -364,7 +394,7 @@ func (s *session) sendRequestPerBackend(ctx context.Context, eventChan chan<- *b
 	addMCPHeaders(req, request, params, routeName, backend.Name)
 	s.reqCtx.applyLogHeaderMappings(req, request)
 	s.reqCtx.applyOriginalPathHeaders(req)
-	req.Header.Set(protocolVersionHeader, protocolVersion20250618)
+	req.Header.Set(protocolVersionHeader, cmp.Or(cse.protocolVersion, latestProtocolVersion))
 	req.Header.Set(sessionIDHeader, cse.sessionID.String())
 	if httpMethod != http.MethodGet {
 		req.Header.Set("Content-type", "application/json")
@@ -388,12 +418,20 @@ func (s *session) sendRequestPerBackend(ctx context.Context, eventChan chan<- *b
 	if lastEventID := cse.lastEventID; lastEventID != "" {
 		req.Header.Set(lastEventIDHeader, lastEventID)
 	}
+	if err = s.reqCtx.setBackendCredential(ctx, req, backend); err != nil {
+		return err
+	}
 	if s.reqCtx.l.Enabled(ctx, slog.LevelDebug) {
+		url := req.URL.String()
+		if route := s.reqCtx.routes[s.route]; route != nil && route.localURLs[backend.Name] != "" {
+			// The path of the local servers can contain their token.
+			url = req.URL.Scheme + "://" + req.URL.Host
+		}
 		args := []any{
 			slog.String("backend", backend.Name),
 			slog.String("session_id", sessionID),
 			slog.String("http_method", httpMethod),
-			slog.String("url", req.URL.String()),
+			slog.String("url", url),
 		}
 		if request != nil {
 			args = append(args,
@@ -410,6 +448,7 @@ func (s *session) sendRequestPerBackend(ctx context.Context, eventChan chan<- *b
 	}
 	startAt := time.Now()
 	httpResp, err := s.reqCtx.client.Do(req)
+	s.reqCtx.backendHealth(routeName).recordResponse(backend.Name, httpResp, err)
 	if err != nil {
 		if errors.Is(err, context.Canceled) {
 			return nil
@@ -551,6 +590,8 @@ type (
 		sessionID    gatewayToMCPServerSessionID
 		lastEventID  string
 		capabilities *mcpsdk.ServerCapabilities
+		// protocolVersion is the protocol version negotiated with the backend.
+		protocolVersion string
 	}
 )
 
@@ -715,11 +756,11 @@ func (c clientToGatewaySessionID) backendSessionIDs() (map[filterapi.MCPBackendN
 	// The subject (prefix[firstAt+1:]) is retained inside the encrypted session ID for
 	// anti-hijacking purposes but is not needed during parsing.
 
-	// Each backend segment format: {backendName}:{base64(sessionID)}:{capHex}
-	// The capHex field is optional for backward compatibility with old session IDs.
+	// Each backend segment format: {backendName}:{base64(sessionID)}:{capHex}:{protocolVersion}
+	// The capHex and protocolVersion fields are optional for backward compatibility with old session IDs.
 	for _, part := range strings.Split(backendSessions, ",") {
-		// Split into at most 3 fields: backendName, base64SessionID, capHex.
-		fields := strings.SplitN(part, ":", 3)
+		// Split into at most 4 fields: backendName, base64SessionID, capHex, protocolVersion.
+		fields := strings.SplitN(part, ":", 4)
 		if len(fields) < 2 {
 			return nil, "", fmt.Errorf("invalid session ID: missing ':' separator in backend session ID part %q", part)
 		}
@@ -740,15 +781,21 @@ func (c clientToGatewaySessionID) backendSessionIDs() (map[filterapi.MCPBackendN
 		// Parse capability flags from the third field, defaulting to all capabilities
 		// for backward compatibility with session IDs that don't include them.
 		var caps *mcpsdk.ServerCapabilities
-		if len(fields) == 3 {
+		if len(fields) >= 3 {
 			caps = decodeCapabilityFlags(fields[2])
 		} else {
 			caps = decodeCapabilityFlags("") // defaults to all capabilities
 		}
+		// The session IDs without the protocol version were created when only the latest version was spoken.
+		protocolVersion := protocolVersion20250618
+		if len(fields) == 4 {
+			protocolVersion = fields[3]
+		}
 		perBackendSessionIDs[backendName] = &compositeSessionEntry{
-			backendName:  backendName,
-			sessionID:    sessionID,
-			capabilities: caps,
+			backendName:     backendName,
+			sessionID:       sessionID,
+			capabilities:    caps,
+			protocolVersion: protocolVersion,
 		}
 	}
 	return perBackendSessionIDs, route, nil
@@ -768,6 +815,8 @@ func clientToGatewaySessionIDFromEntries(subject string, entries []compositeSess
 		_, _ = b.WriteString(base64.StdEncoding.EncodeToString([]byte(entry.sessionID)))
 		_, _ = b.WriteString(":")
 		_, _ = b.WriteString(encodeCapabilityFlags(entry.capabilities))
+		_, _ = b.WriteString(":")
+		_, _ = b.WriteString(cmp.Or(entry.protocolVersion, latestProtocolVersion))
 		_, _ = b.WriteString(",")
 	}
 	sessionID := b.String()[:b.Len()-1] // string the trailing ','.
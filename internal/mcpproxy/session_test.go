@@ -48,6 +48,10 @@ func (stubMetrics) RecordClientCapabilities(context.Context, *mcpsdk.ClientCapab
 func (stubMetrics) RecordServerCapabilities(context.Context, *mcpsdk.ServerCapabilities, mcpsdk.Params) {
 }
 func (stubMetrics) RecordProgress(context.Context, mcpsdk.Params) {}
+func (stubMetrics) RecordRateLimited(context.Context, string, metrics.MCPRateLimitType, mcpsdk.Params) {
+}
+func (stubMetrics) RecordSecurityEvent(context.Context, metrics.MCPSecurityEventType, mcpsdk.Params) {
+}
 
 func TestEncodeCapabilityFlags(t *testing.T) {
 	t.Parallel()
@@ -339,6 +343,24 @@ func TestClientToGatewaySessionIDFromEntries_WithCapabilities(t *testing.T) {
 	require.Nil(t, m["b2"].capabilities.Logging)
 }
 
+func TestClientToGatewaySessionIDFromEntries_WithProtocolVersion(t *testing.T) {
+	t.Parallel()
+	entries := []compositeSessionEntry{
+		{backendName: "b1", sessionID: "sid-1", protocolVersion: protocolVersion20241105},
+		{backendName: "b2", sessionID: "sid-2"},
+	}
+	id := clientToGatewaySessionIDFromEntries("subj", entries, "route1")
+	m, _, err := id.backendSessionIDs()
+	require.NoError(t, err)
+	require.Equal(t, protocolVersion20241105, m["b1"].protocolVersion)
+	require.Equal(t, latestProtocolVersion, m["b2"].protocolVersion)
+
+	// Session IDs without the protocol version default to 2025-06-18.
+	m, _, err = clientToGatewaySessionID("route1@subj@b1:" + base64.StdEncoding.EncodeToString([]byte("sid-1")) + ":000").backendSessionIDs()
+	require.NoError(t, err)
+	require.Equal(t, protocolVersion20250618, m["b1"].protocolVersion)
+}
+
 func TestBackendSessionIDs_EmailSubject(t *testing.T) {
 	t.Parallel()
 	backendA := "backendA"
@@ -0,0 +1,347 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+// SessionStore stores the state of the MCP sessions that cannot be encoded in the session IDs, so that it can be
+// shared by the replicas of the MCP proxy: the replay buffers of the event streams, the resource subscriptions, and
+// the server->client requests awaiting a response from the client.
+//
+// The sessions are identified by opaque keys derived from the session IDs. The state of a session expires after
+// it has not been written to for a while.
+type SessionStore interface {
+	// AppendEvent appends the given event to the replay buffer of the session. Only the most recent events are kept.
+	AppendEvent(ctx context.Context, session string, event StoredEvent) error
+	// EventsAfter returns the events appended to the replay buffer of the session after the event with the given ID.
+	// The boolean is false if the event is not in the replay buffer anymore.
+	EventsAfter(ctx context.Context, session string, eventID string) ([]StoredEvent, bool, error)
+	// AddSubscription records that the session is subscribed to the resource with the given URI.
+	AddSubscription(ctx context.Context, session string, uri string) error
+	// RemoveSubscription records that the session is not subscribed to the resource with the given URI anymore.
+	RemoveSubscription(ctx context.Context, session string, uri string) error
+	// IsSubscribed returns true if the session is subscribed to the resource with the given URI.
+	IsSubscribed(ctx context.Context, session string, uri string) (bool, error)
+	// AddPendingRequest records a server->client request with the given ID awaiting a response from the client.
+	AddPendingRequest(ctx context.Context, session string, requestID string) error
+	// TakePendingRequest removes the server->client request with the given ID, and returns true if it was pending.
+	TakePendingRequest(ctx context.Context, session string, requestID string) (bool, error)
+	// DeleteSession deletes the state of the session.
+	DeleteSession(ctx context.Context, session string) error
+	// Close releases the resources of the store.
+	Close() error
+}
+
+// StoredEvent is an event of the stream of a session kept for replay.
+type StoredEvent struct {
+	// ID is the event ID sent to the client.
+	ID string `json:"id"`
+	// Data is the encoded event as sent to the client.
+	Data []byte `json:"data"`
+}
+
+const (
+	// maxReplayEvents is the maximum number of events kept in the replay buffer of a session.
+	maxReplayEvents = 100
+	// sessionStateTTL is the duration after which the state of a session that has not been written to expires.
+	sessionStateTTL = 24 * time.Hour
+)
+
+// NewSessionStore returns the session store described by the given string: "memory" for a store local to the
+// process, or a Redis URL such as "redis://host:6379/0" for a store shared by all the replicas.
+func NewSessionStore(store string) (SessionStore, error) {
+	if store == "memory" {
+		return newMemorySessionStore(), nil
+	}
+	u, err := url.Parse(store)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session store %q: %w", store, err)
+	}
+	switch u.Scheme {
+	case "redis", "rediss":
+		return newRedisSessionStore(store)
+	default:
+		return nil, fmt.Errorf("invalid session store %q: must be \"memory\" or a redis:// or rediss:// URL", store)
+	}
+}
+
+// sessionStoreKey returns the key of the given session in the session store. The session ID is hashed so that the
+// store neither holds the session IDs nor depends on their length.
+func sessionStoreKey(id secureClientToGatewaySessionID) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])
+}
+
+type (
+	// memorySessionStore is a [SessionStore] local to the process, suitable for a single replica.
+	memorySessionStore struct {
+		mu        sync.Mutex
+		sessions  map[string]*memorySessionState
+		lastSweep time.Time
+		// now is replaced in tests.
+		now func() time.Time
+	}
+
+	// memorySessionState is the state of a session in the memorySessionStore.
+	memorySessionState struct {
+		events        []StoredEvent
+		subscriptions map[string]struct{}
+		pending       map[string]struct{}
+		expiresAt     time.Time
+	}
+)
+
+func newMemorySessionStore() *memorySessionStore {
+	return &memorySessionStore{sessions: make(map[string]*memorySessionState), now: time.Now}
+}
+
+// write returns the state of the given session for writing, creating it if needed, and extends its expiration.
+// The expired sessions are deleted at most once per TTL. It must be called with the lock held.
+func (m *memorySessionStore) write(session string) *memorySessionState {
+	now := m.now()
+	if now.Sub(m.lastSweep) > sessionStateTTL {
+		for k, s := range m.sessions {
+			if now.After(s.expiresAt) {
+				delete(m.sessions, k)
+			}
+		}
+		m.lastSweep = now
+	}
+	s, ok := m.sessions[session]
+	if !ok || now.After(s.expiresAt) {
+		s = &memorySessionState{subscriptions: make(map[string]struct{}), pending: make(map[string]struct{})}
+		m.sessions[session] = s
+	}
+	s.expiresAt = now.Add(sessionStateTTL)
+	return s
+}
+
+// read returns the state of the given session, or nil if it does not exist or has expired. It must be called with
+// the lock held.
+func (m *memorySessionStore) read(session string) *memorySessionState {
+	s, ok := m.sessions[session]
+	if !ok || m.now().After(s.expiresAt) {
+		return nil
+	}
+	return s
+}
+
+// AppendEvent implements [SessionStore.AppendEvent].
+func (m *memorySessionStore) AppendEvent(_ context.Context, session string, event StoredEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.write(session)
+	s.events = append(s.events, event)
+	if len(s.events) > maxReplayEvents {
+		s.events = slices.Clone(s.events[len(s.events)-maxReplayEvents:])
+	}
+	return nil
+}
+
+// EventsAfter implements [SessionStore.EventsAfter].
+func (m *memorySessionStore) EventsAfter(_ context.Context, session string, eventID string) ([]StoredEvent, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.read(session)
+	if s == nil {
+		return nil, false, nil
+	}
+	return eventsAfter(s.events, eventID)
+}
+
+// eventsAfter returns the events after the one with the given ID, and whether it was found.
+func eventsAfter(events []StoredEvent, eventID string) ([]StoredEvent, bool, error) {
+	i := slices.IndexFunc(events, func(e StoredEvent) bool { return e.ID == eventID })
+	if i < 0 {
+		return nil, false, nil
+	}
+	return slices.Clone(events[i+1:]), true, nil
+}
+
+// AddSubscription implements [SessionStore.AddSubscription].
+func (m *memorySessionStore) AddSubscription(_ context.Context, session string, uri string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.write(session).subscriptions[uri] = struct{}{}
+	return nil
+}
+
+// RemoveSubscription implements [SessionStore.RemoveSubscription].
+func (m *memorySessionStore) RemoveSubscription(_ context.Context, session string, uri string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.write(session).subscriptions, uri)
+	return nil
+}
+
+// IsSubscribed implements [SessionStore.IsSubscribed].
+func (m *memorySessionStore) IsSubscribed(_ context.Context, session string, uri string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.read(session)
+	if s == nil {
+		return false, nil
+	}
+	_, ok := s.subscriptions[uri]
+	return ok, nil
+}
+
+// AddPendingRequest implements [SessionStore.AddPendingRequest].
+func (m *memorySessionStore) AddPendingRequest(_ context.Context, session string, requestID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.write(session).pending[requestID] = struct{}{}
+	return nil
+}
+
+// TakePendingRequest implements [SessionStore.TakePendingRequest].
+func (m *memorySessionStore) TakePendingRequest(_ context.Context, session string, requestID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.read(session)
+	if s == nil {
+		return false, nil
+	}
+	_, ok := s.pending[requestID]
+	delete(s.pending, requestID)
+	return ok, nil
+}
+
+// DeleteSession implements [SessionStore.DeleteSession].
+func (m *memorySessionStore) DeleteSession(_ context.Context, session string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, session)
+	return nil
+}
+
+// Close implements [SessionStore.Close].
+func (m *memorySessionStore) Close() error { return nil }
+
+// storeEvent appends the given event, as written to the client, to the replay buffer of the session, and records
+// the server->client requests it carries as pending. It is a no-op unless a session store is configured.
+func (s *session) storeEvent(ctx context.Context, event *sseEvent) {
+	store := s.reqCtx.sessionStore
+	if store == nil || event.id == "" {
+		return
+	}
+	for _, msg := range event.messages {
+		if req, ok := msg.(*jsonrpc.Request); ok {
+			s.reqCtx.storePendingRequest(ctx, s, req)
+		}
+	}
+	var buf bytes.Buffer
+	event.writeAndMaybeFlush(&buf)
+	if err := store.AppendEvent(ctx, sessionStoreKey(s.id), StoredEvent{ID: event.id, Data: buf.Bytes()}); err != nil {
+		s.reqCtx.l.Error("failed to store MCP event", slog.String("error", err.Error()))
+	}
+}
+
+// storePendingRequest records the given server->client request as awaiting a response from the client, if it is
+// a request whose ID was rewritten by maybeServerToClientRequestModify.
+func (m *mcpRequestContext) storePendingRequest(ctx context.Context, s *session, req *jsonrpc.Request) {
+	store := m.sessionStore
+	if store == nil || !req.IsCall() {
+		return
+	}
+	id, ok := req.ID.Raw().(string)
+	if !ok {
+		return
+	}
+	if err := store.AddPendingRequest(ctx, sessionStoreKey(s.id), id); err != nil {
+		m.l.Error("failed to store pending server->client request", slog.String("error", err.Error()))
+	}
+}
+
+// replayEvents writes to the client the stored events of the session sent after the given event ID, and moves the
+// last event IDs of the backends past them so that the backends do not replay them again.
+func (s *session) replayEvents(ctx context.Context, w http.ResponseWriter, lastEventID string) {
+	store := s.reqCtx.sessionStore
+	if store == nil || lastEventID == "" {
+		return
+	}
+	events, found, err := store.EventsAfter(ctx, sessionStoreKey(s.id), lastEventID)
+	if err != nil {
+		s.reqCtx.l.Error("failed to load stored MCP events", slog.String("error", err.Error()))
+		return
+	}
+	if !found || len(events) == 0 {
+		return
+	}
+	for _, e := range events {
+		_, _ = w.Write(e.Data)
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	decrypted, err := s.reqCtx.sessionCrypto.Decrypt(events[len(events)-1].ID)
+	if err != nil {
+		s.reqCtx.l.Error("failed to decrypt stored event ID", slog.String("error", err.Error()))
+		return
+	}
+	for backend, id := range clientToGatewayEventID(decrypted).backendEventIDs() {
+		s.setLastEventID(backend, id)
+	}
+	if s.reqCtx.l.Enabled(ctx, slog.LevelDebug) {
+		s.reqCtx.l.Debug("Replayed stored MCP events", slog.Int("count", len(events)))
+	}
+}
+
+// storeSubscription records the subscription to or unsubscription from the resource with the given URI, as seen
+// by the client, according to the method.
+func (m *mcpRequestContext) storeSubscription(ctx context.Context, s *session, method string, uri string) {
+	store := m.sessionStore
+	if store == nil {
+		return
+	}
+	var err error
+	if method == "resources/subscribe" {
+		err = store.AddSubscription(ctx, sessionStoreKey(s.id), uri)
+	} else {
+		err = store.RemoveSubscription(ctx, sessionStoreKey(s.id), uri)
+	}
+	if err != nil {
+		m.l.Error("failed to store resource subscription", slog.String("uri", uri), slog.String("error", err.Error()))
+	}
+}
+
+// isSubscribed returns false if the given message is a resource update notification for a resource the client is
+// not subscribed to, and true otherwise. It must be called once the resource URI has been rewritten for the client.
+// All the notifications are relayed unless a session store is configured.
+func (s *session) isSubscribed(ctx context.Context, msg jsonrpc.Message) bool {
+	store := s.reqCtx.sessionStore
+	req, ok := msg.(*jsonrpc.Request)
+	if store == nil || !ok || req.Method != "notifications/resources/updated" {
+		return true
+	}
+	params := &mcpsdk.ResourceUpdatedNotificationParams{}
+	if err := json.Unmarshal(req.Params, params); err != nil {
+		return true
+	}
+	subscribed, err := store.IsSubscribed(ctx, sessionStoreKey(s.id), params.URI)
+	if err != nil {
+		s.reqCtx.l.Error("failed to load resource subscription", slog.String("uri", params.URI), slog.String("error", err.Error()))
+		return true
+	}
+	return subscribed
+}
@@ -0,0 +1,126 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+// redisSessionStore is a [SessionStore] backed by Redis, shared by all the replicas of the MCP proxy.
+//
+// The state of each session is kept under three keys sharing the same hash tag, so that they are assigned to the
+// same slot in a Redis cluster:
+//
+//   - aigw:mcp:{<session>}:events: a list of the JSON encoded events of the replay buffer.
+//   - aigw:mcp:{<session>}:subscriptions: a set of the subscribed resource URIs.
+//   - aigw:mcp:{<session>}:pending: a set of the IDs of the pending server->client requests.
+type redisSessionStore struct {
+	client *redis.Client
+}
+
+func newRedisSessionStore(url string) (*redisSessionStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	return &redisSessionStore{client: redis.NewClient(opts)}, nil
+}
+
+func redisSessionKeys(session string) (events, subscriptions, pending string) {
+	prefix := "aigw:mcp:{" + session + "}:"
+	return prefix + "events", prefix + "subscriptions", prefix + "pending"
+}
+
+// AppendEvent implements [SessionStore.AppendEvent].
+func (r *redisSessionStore) AppendEvent(ctx context.Context, session string, event StoredEvent) error {
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	events, _, _ := redisSessionKeys(session)
+	_, err = r.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.RPush(ctx, events, encoded)
+		p.LTrim(ctx, events, -maxReplayEvents, -1)
+		p.Expire(ctx, events, sessionStateTTL)
+		return nil
+	})
+	return err
+}
+
+// EventsAfter implements [SessionStore.EventsAfter].
+func (r *redisSessionStore) EventsAfter(ctx context.Context, session string, eventID string) ([]StoredEvent, bool, error) {
+	events, _, _ := redisSessionKeys(session)
+	encoded, err := r.client.LRange(ctx, events, 0, -1).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	stored := make([]StoredEvent, len(encoded))
+	for i, e := range encoded {
+		if err = json.Unmarshal([]byte(e), &stored[i]); err != nil {
+			return nil, false, fmt.Errorf("failed to decode event: %w", err)
+		}
+	}
+	return eventsAfter(stored, eventID)
+}
+
+// AddSubscription implements [SessionStore.AddSubscription].
+func (r *redisSessionStore) AddSubscription(ctx context.Context, session string, uri string) error {
+	_, subscriptions, _ := redisSessionKeys(session)
+	return r.addToSet(ctx, subscriptions, uri)
+}
+
+// RemoveSubscription implements [SessionStore.RemoveSubscription].
+func (r *redisSessionStore) RemoveSubscription(ctx context.Context, session string, uri string) error {
+	_, subscriptions, _ := redisSessionKeys(session)
+	return r.client.SRem(ctx, subscriptions, uri).Err()
+}
+
+// IsSubscribed implements [SessionStore.IsSubscribed].
+func (r *redisSessionStore) IsSubscribed(ctx context.Context, session string, uri string) (bool, error) {
+	_, subscriptions, _ := redisSessionKeys(session)
+	return r.client.SIsMember(ctx, subscriptions, uri).Result()
+}
+
+// AddPendingRequest implements [SessionStore.AddPendingRequest].
+func (r *redisSessionStore) AddPendingRequest(ctx context.Context, session string, requestID string) error {
+	_, _, pending := redisSessionKeys(session)
+	return r.addToSet(ctx, pending, requestID)
+}
+
+// TakePendingRequest implements [SessionStore.TakePendingRequest].
+func (r *redisSessionStore) TakePendingRequest(ctx context.Context, session string, requestID string) (bool, error) {
+	_, _, pending := redisSessionKeys(session)
+	// SREM is atomic, so only one of the concurrent responses to the same request is accepted.
+	removed, err := r.client.SRem(ctx, pending, requestID).Result()
+	if err != nil {
+		return false, err
+	}
+	return removed == 1, nil
+}
+
+// DeleteSession implements [SessionStore.DeleteSession].
+func (r *redisSessionStore) DeleteSession(ctx context.Context, session string) error {
+	events, subscriptions, pending := redisSessionKeys(session)
+	return r.client.Del(ctx, events, subscriptions, pending).Err()
+}
+
+// Close implements [SessionStore.Close].
+func (r *redisSessionStore) Close() error { return r.client.Close() }
+
+// addToSet adds the given member to the set with the given key, and extends its expiration.
+func (r *redisSessionStore) addToSet(ctx context.Context, key, member string) error {
+	_, err := r.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.SAdd(ctx, key, member)
+		p.Expire(ctx, key, sessionStateTTL)
+		return nil
+	})
+	return err
+}
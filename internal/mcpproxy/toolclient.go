@@ -0,0 +1,79 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/version"
+)
+
+// ToolClient opens MCP sessions on the MCPRoutes by sending the requests directly to the MCP proxy. This is used by
+// the gateway-side tool execution of the chat completions, so that the tool calls go through the same backend
+// selection, credentials, validation and auditing as the tool calls of the MCP clients.
+type ToolClient struct {
+	client *mcp.Client
+	// transport is the HTTP transport connecting to the MCP proxy.
+	transport http.RoundTripper
+}
+
+// NewToolClient creates a new ToolClient connecting to the MCP proxy listening on the given address.
+func NewToolClient(addr net.Addr) *ToolClient {
+	var dialer net.Dialer
+	return &ToolClient{
+		client: mcp.NewClient(&mcp.Implementation{Name: "envoy-ai-gateway", Version: version.Parse()}, nil),
+		transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, addr.Network(), addr.String())
+			},
+		},
+	}
+}
+
+// Connect opens an MCP session on the given route. The given headers are added to the requests sent to the MCP proxy
+// unless set by the MCP client, such as the authorization header of the original request.
+func (c *ToolClient) Connect(ctx context.Context, route filterapi.MCPRouteName, headers http.Header) (*mcp.ClientSession, error) {
+	session, err := c.client.Connect(ctx, &mcp.StreamableClientTransport{
+		// The connections are always made to the MCP proxy, which selects the route by header.
+		Endpoint: "http://localhost/mcp",
+		HTTPClient: &http.Client{Transport: &toolClientTransport{
+			route: route, headers: headers, base: c.transport,
+		}},
+		// The sessions are only used to list and call the tools.
+		DisableStandaloneSSE: true,
+		MaxRetries:           -1,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MCP route %s: %w", route, err)
+	}
+	return session, nil
+}
+
+// toolClientTransport adds the route header and the headers of the original request to the requests.
+type toolClientTransport struct {
+	route   filterapi.MCPRouteName
+	headers http.Header
+	base    http.RoundTripper
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *toolClientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		if req.Header.Get(k) == "" {
+			req.Header[k] = v
+		}
+	}
+	req.Header.Set(internalapi.MCPRouteHeader, t.route)
+	return t.base.RoundTrip(req)
+}
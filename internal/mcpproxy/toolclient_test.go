@@ -0,0 +1,63 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+func TestToolClient(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "echo"}, func(_ context.Context, _ *mcp.CallToolRequest, in map[string]any) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: in["text"].(string)}}}, nil, nil
+	})
+	mcpHandler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+	var (
+		mu      sync.Mutex
+		headers []http.Header
+	)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		headers = append(headers, r.Header.Clone())
+		mu.Unlock()
+		mcpHandler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(proxy.Close)
+
+	c := NewToolClient(proxy.Listener.Addr())
+	session, err := c.Connect(t.Context(), "ns/route", http.Header{
+		"Authorization": {"Bearer token"},
+		"Content-Type":  {"text/plain"},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+
+	tools, err := session.ListTools(t.Context(), nil)
+	require.NoError(t, err)
+	require.Len(t, tools.Tools, 1)
+	res, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "echo", Arguments: map[string]any{"text": "hello"}})
+	require.NoError(t, err)
+	require.Equal(t, "hello", res.Content[0].(*mcp.TextContent).Text)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, headers)
+	for _, h := range headers {
+		require.Equal(t, "ns/route", h.Get(internalapi.MCPRouteHeader))
+		require.Equal(t, "Bearer token", h.Get("Authorization"))
+		// The headers set by the MCP client are not overridden.
+		require.Equal(t, "application/json", h.Get("Content-Type"))
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+var (
+	// errInvalidToolArguments is returned when the arguments of a tool call don't conform to the input schema of the tool.
+	errInvalidToolArguments = errors.New("invalid tool arguments")
+	// errInvalidToolResult is returned when the result of a tool call doesn't conform to the output schema of the tool.
+	errInvalidToolResult = errors.New("invalid tool result")
+)
+
+type (
+	// toolSchemaCache remembers the schemas of the tools from the last tools/list responses of the backends,
+	// so that the tool calls can be validated by the proxy.
+	toolSchemaCache struct {
+		mu      sync.RWMutex
+		schemas map[toolSchemaKey]*toolSchemas
+	}
+
+	toolSchemaKey struct {
+		route   filterapi.MCPRouteName
+		backend filterapi.MCPBackendName
+		tool    string
+	}
+
+	// toolSchemas holds the compiled schemas of a tool. A nil schema means that the corresponding part of the
+	// tool calls is not validated.
+	toolSchemas struct {
+		// inputSchema and outputSchema are the schemas as advertised by the backend, used to avoid recompiling them
+		// when they didn't change.
+		inputSchema, outputSchema any
+		input, output             *jsonschema.Resolved
+	}
+)
+
+// supportedSchemaVersions are the JSON Schema dialects the validator supports.
+var supportedSchemaVersions = map[string]struct{}{
+	"": {},
+	"http://json-schema.org/draft-07/schema#":      {},
+	"https://json-schema.org/draft-07/schema#":     {},
+	"https://json-schema.org/draft/2020-12/schema": {},
+}
+
+func newToolSchemaCache() *toolSchemaCache {
+	return &toolSchemaCache{schemas: make(map[toolSchemaKey]*toolSchemas)}
+}
+
+// update records the schemas of the given tool, as listed by the backend. The tool name must not be prefixed
+// with the backend name. The schemas that cannot be compiled are ignored and the error is returned.
+func (c *toolSchemaCache) update(route filterapi.MCPRouteName, backend filterapi.MCPBackendName, tool *mcp.Tool) error {
+	if c == nil {
+		return nil
+	}
+	key := toolSchemaKey{route: route, backend: backend, tool: tool.Name}
+	c.mu.RLock()
+	prev := c.schemas[key]
+	c.mu.RUnlock()
+	if prev != nil && reflect.DeepEqual(prev.inputSchema, tool.InputSchema) && reflect.DeepEqual(prev.outputSchema, tool.OutputSchema) {
+		return nil
+	}
+
+	schemas := &toolSchemas{inputSchema: tool.InputSchema, outputSchema: tool.OutputSchema}
+	var inputErr, outputErr error
+	if schemas.input, inputErr = compileToolSchema(tool.InputSchema); inputErr != nil {
+		inputErr = fmt.Errorf("invalid input schema of tool %s: %w", tool.Name, inputErr)
+	}
+	if schemas.output, outputErr = compileToolSchema(tool.OutputSchema); outputErr != nil {
+		outputErr = fmt.Errorf("invalid output schema of tool %s: %w", tool.Name, outputErr)
+	}
+	c.mu.Lock()
+	c.schemas[key] = schemas
+	c.mu.Unlock()
+	return errors.Join(inputErr, outputErr)
+}
+
+// get returns the schemas of the given tool, or nil if the tool has not been listed yet.
+func (c *toolSchemaCache) get(route filterapi.MCPRouteName, backend filterapi.MCPBackendName, tool string) *toolSchemas {
+	if c == nil {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.schemas[toolSchemaKey{route: route, backend: backend, tool: tool}]
+}
+
+// retain deletes the schemas of the tools of the routes and backends that are no longer configured.
+func (c *toolSchemaCache) retain(config *mcpProxyConfig) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.schemas {
+		route := config.routes[key.route]
+		if route == nil {
+			delete(c.schemas, key)
+			continue
+		}
+		if _, ok := route.backends[key.backend]; !ok {
+			delete(c.schemas, key)
+		}
+	}
+}
+
+// compileToolSchema compiles the given JSON schema of a tool. It returns nil if there is no schema.
+func compileToolSchema(schema any) (*jsonschema.Resolved, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var s jsonschema.Schema
+	if err = json.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+	if _, ok := supportedSchemaVersions[s.Schema]; !ok {
+		return nil, fmt.Errorf("unsupported schema version %s", s.Schema)
+	}
+	// Remote references are not resolved, so the schemas referring to them are not used.
+	return s.Resolve(nil)
+}
+
+// validateToolArguments validates the arguments of the given tool call against the input schema of the tool,
+// if the validation is enabled for the route and the tool has been listed.
+func (m *mcpRequestContext) validateToolArguments(routeName filterapi.MCPRouteName, route *mcpProxyConfigRoute, backend filterapi.MCPBackendName, tool string, p *mcp.CallToolParams) error {
+	if !route.toolValidation.Arguments {
+		return nil
+	}
+	schemas := m.toolSchemas.get(routeName, backend, tool)
+	if schemas == nil || schemas.input == nil {
+		return nil
+	}
+	args := p.Arguments
+	if args == nil {
+		// Missing arguments are equivalent to an empty object.
+		args = map[string]any{}
+	}
+	if err := schemas.input.Validate(args); err != nil {
+		return fmt.Errorf("%w for tool %s: %w", errInvalidToolArguments, p.Name, err)
+	}
+	return nil
+}
+
+// maybeValidateToolResult validates the structured content of a tools/call result against the output schema of
+// the tool, if the validation is enabled for the route and the tool has been listed. A result that doesn't conform
+// to the schema is replaced with a JSON-RPC internal error, and the validation error is returned.
+func (m *mcpRequestContext) maybeValidateToolResult(s *session, req *jsonrpc.Request, msg *jsonrpc.Response, backend filterapi.MCPBackendName) error {
+	if req.Method != "tools/call" || msg.Result == nil || s == nil {
+		return nil
+	}
+	route := m.routes[s.route]
+	if route == nil || !route.toolValidation.StructuredContent {
+		return nil
+	}
+	var p mcp.CallToolParams
+	if err := json.Unmarshal(req.Params, &p); err != nil {
+		return nil
+	}
+	schemas := m.toolSchemas.get(s.route, backend, p.Name)
+	if schemas == nil || schemas.output == nil {
+		return nil
+	}
+	var result mcp.CallToolResult
+	if err := json.Unmarshal(msg.Result, &result); err != nil || result.IsError {
+		return nil
+	}
+
+	var err error
+	if result.StructuredContent == nil {
+		// https://modelcontextprotocol.io/specification/2025-06-18/server/tools#output-schema
+		err = errors.New("missing structured content")
+	} else {
+		err = schemas.output.Validate(result.StructuredContent)
+	}
+	if err == nil {
+		return nil
+	}
+	err = fmt.Errorf("%w for tool %s: %w", errInvalidToolResult, downstreamResourceName(p.Name, backend), err)
+	msg.Result = nil
+	msg.Error = &jsonrpc.Error{Code: jsonrpc.CodeInternalError, Message: err.Error()}
+	return err
+}
+
+// onInvalidToolArgumentsResponse writes the JSON-RPC invalid params error for a tool call with invalid arguments.
+func onInvalidToolArgumentsResponse(w http.ResponseWriter, id jsonrpc.ID, err error) {
+	encoded, _ := jsonrpc.EncodeMessage(&jsonrpc.Response{ID: id, Error: &jsonrpc.Error{
+		Code:    jsonrpc.CodeInvalidParams,
+		Message: err.Error(),
+	}})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(encoded)
+}
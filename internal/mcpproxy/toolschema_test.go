@@ -0,0 +1,162 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+)
+
+var (
+	testInputSchema = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"owner": map[string]any{"type": "string"},
+			"count": map[string]any{"type": "integer", "minimum": 1},
+		},
+		"required": []any{"owner"},
+	}
+	testOutputSchema = map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"stars": map[string]any{"type": "integer"}},
+		"required":   []any{"stars"},
+	}
+)
+
+func TestToolSchemaCache(t *testing.T) {
+	c := newToolSchemaCache()
+	require.Nil(t, c.get("route", "backend1", "tool"))
+
+	require.NoError(t, c.update("route", "backend1", &mcp.Tool{Name: "tool", InputSchema: testInputSchema}))
+	schemas := c.get("route", "backend1", "tool")
+	require.NotNil(t, schemas)
+	require.NotNil(t, schemas.input)
+	require.Nil(t, schemas.output)
+	require.Nil(t, c.get("route", "backend2", "tool"))
+
+	// Unchanged schemas are not compiled again.
+	require.NoError(t, c.update("route", "backend1", &mcp.Tool{Name: "tool", InputSchema: testInputSchema}))
+	require.Same(t, schemas, c.get("route", "backend1", "tool"))
+	require.NoError(t, c.update("route", "backend1", &mcp.Tool{Name: "tool", InputSchema: testInputSchema, OutputSchema: testOutputSchema}))
+	require.NotSame(t, schemas, c.get("route", "backend1", "tool"))
+	require.NotNil(t, c.get("route", "backend1", "tool").output)
+
+	// The schemas that cannot be compiled are not used.
+	err := c.update("route", "backend1", &mcp.Tool{Name: "tool", InputSchema: map[string]any{
+		"$schema": "https://json-schema.org/draft-04/schema#",
+	}, OutputSchema: testOutputSchema})
+	require.EqualError(t, err, "invalid input schema of tool tool: unsupported schema version https://json-schema.org/draft-04/schema#")
+	require.Nil(t, c.get("route", "backend1", "tool").input)
+	require.NotNil(t, c.get("route", "backend1", "tool").output)
+	err = c.update("route", "backend1", &mcp.Tool{Name: "remote", InputSchema: map[string]any{"$ref": "https://example.com/schema.json"}})
+	require.ErrorContains(t, err, "invalid input schema of tool remote")
+	require.Nil(t, c.get("route", "backend1", "remote").input)
+
+	t.Run("retain", func(t *testing.T) {
+		require.NoError(t, c.update("route", "backend2", &mcp.Tool{Name: "tool", InputSchema: testInputSchema}))
+		require.NoError(t, c.update("other-route", "backend1", &mcp.Tool{Name: "tool", InputSchema: testInputSchema}))
+		c.retain(&mcpProxyConfig{routes: map[filterapi.MCPRouteName]*mcpProxyConfigRoute{
+			"route": {backends: map[filterapi.MCPBackendName]filterapi.MCPBackend{"backend1": {Name: "backend1"}}},
+		}})
+		require.NotNil(t, c.get("route", "backend1", "tool"))
+		require.Nil(t, c.get("route", "backend2", "tool"))
+		require.Nil(t, c.get("other-route", "backend1", "tool"))
+	})
+}
+
+func TestHandleToolCallRequest_ToolValidation(t *testing.T) {
+	var calls atomic.Int32
+	structuredContent := `{"stars":42}`
+	backendServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		msg, _ := jsonrpc.DecodeMessage(body)
+		respBody, _ := jsonrpc.EncodeMessage(&jsonrpc.Response{
+			ID:     msg.(*jsonrpc.Request).ID,
+			Result: []byte(`{"content":[],"structuredContent":` + structuredContent + `}`),
+		})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(respBody)
+	}))
+	t.Cleanup(backendServer.Close)
+
+	proxy := newTestMCPProxy()
+	proxy.backendListenerAddr = backendServer.URL
+	// The structured content is only returned to the clients speaking 2025-06-18 or later.
+	proxy.requestHeaders = http.Header{"Mcp-Protocol-Version": {protocolVersion20250618}}
+	proxy.toolSchemas = newToolSchemaCache()
+	proxy.routes["test-route"].toolValidation = filterapi.MCPToolValidation{Arguments: true, StructuredContent: true}
+	require.NoError(t, proxy.toolSchemas.update("test-route", "backend1", &mcp.Tool{
+		Name: "test-tool", InputSchema: testInputSchema, OutputSchema: testOutputSchema,
+	}))
+	s := &session{
+		reqCtx: proxy,
+		perBackendSessions: map[filterapi.MCPBackendName]*compositeSessionEntry{
+			"backend1": {sessionID: "test-session"},
+		},
+		route: "test-route",
+	}
+	callTool := func(args any) (*httptest.ResponseRecorder, error) {
+		rr := httptest.NewRecorder()
+		req := &jsonrpc.Request{ID: mustJSONRPCRequestID(), Method: "tools/call"}
+		params := &mcp.CallToolParams{Name: "backend1__test-tool", Arguments: args}
+		_, err := proxy.handleToolCallRequest(t.Context(), s, rr, req, params, nil, httptest.NewRequest(http.MethodPost, "/mcp", nil))
+		return rr, err
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		rr, err := callTool(map[string]any{"owner": "envoyproxy", "count": float64(2)})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Contains(t, rr.Body.String(), `"stars":42`)
+		require.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		for _, args := range []any{
+			nil,
+			map[string]any{"owner": 1},
+			map[string]any{"owner": "envoyproxy", "count": float64(0)},
+		} {
+			rr, err := callTool(args)
+			require.ErrorIs(t, err, errInvalidToolArguments)
+			require.ErrorContains(t, err, "invalid tool arguments for tool backend1__test-tool")
+			require.Equal(t, http.StatusOK, rr.Code)
+			msg, decodeErr := jsonrpc.DecodeMessage(rr.Body.Bytes())
+			require.NoError(t, decodeErr)
+			var jsonrpcErr *jsonrpc.Error
+			require.ErrorAs(t, msg.(*jsonrpc.Response).Error, &jsonrpcErr)
+			require.Equal(t, int64(jsonrpc.CodeInvalidParams), jsonrpcErr.Code)
+		}
+		// The invalid calls don't reach the backend.
+		require.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("invalid structured content", func(t *testing.T) {
+		structuredContent = `{"stars":"many"}`
+		rr, err := callTool(map[string]any{"owner": "envoyproxy"})
+		require.ErrorIs(t, err, errInvalidToolResult)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.NotContains(t, rr.Body.String(), "structuredContent")
+		require.Contains(t, rr.Body.String(), "invalid tool result for tool backend1__test-tool")
+	})
+
+	t.Run("validation disabled", func(t *testing.T) {
+		proxy.routes["test-route"].toolValidation = filterapi.MCPToolValidation{}
+		rr, err := callTool(map[string]any{"owner": 1})
+		require.NoError(t, err)
+		require.Contains(t, rr.Body.String(), "many")
+	})
+}
@@ -0,0 +1,488 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// The names of the meta-tools returned by tools/list in the tool search mode. They don't contain the name separator,
+// so they never collide with the prefixed names of the backend tools.
+const (
+	searchToolsName  = "search_tools"
+	describeToolName = "describe_tool"
+	callToolName     = "call_tool"
+)
+
+// toolSearchIndexTTL is the time after which the tool search index of a route is rebuilt, in case a backend changed
+// its tools without sending notifications/tools/list_changed.
+const toolSearchIndexTTL = 5 * time.Minute
+
+// metaTools are the tools listed in the tool search mode.
+var metaTools = []*mcp.Tool{
+	{
+		Name: searchToolsName,
+		Description: "Search the available tools by keywords. Returns the names and descriptions of the best matching tools. " +
+			"Use describe_tool to get the input schema of a tool before calling it with call_tool.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"query": map[string]any{"type": "string", "description": "Keywords describing the task to accomplish."},
+				"limit": map[string]any{"type": "integer", "minimum": 1, "description": "Maximum number of tools to return."},
+			},
+			"required": []any{"query"},
+		},
+	},
+	{
+		Name:        describeToolName,
+		Description: "Get the full definition of a tool returned by search_tools, including its input schema.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name": map[string]any{"type": "string", "description": "Name of the tool."},
+			},
+			"required": []any{"name"},
+		},
+	},
+	{
+		Name:        callToolName,
+		Description: "Call a tool returned by search_tools with arguments conforming to its input schema.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name":      map[string]any{"type": "string", "description": "Name of the tool."},
+				"arguments": map[string]any{"type": "object", "description": "Arguments of the tool call."},
+			},
+			"required": []any{"name"},
+		},
+	},
+}
+
+type (
+	// toolSearchResponse is decoded from the tools/list responses of the backends and encoded as the result of a
+	// search_tools or describe_tool call, so that the tool search reuses the aggregation of the tools/list responses.
+	toolSearchResponse struct {
+		list   mcp.ListToolsResult
+		result *mcp.CallToolResult
+	}
+
+	// toolSearchIndexCache keeps the tool search index of each route, so that the tools of the backends are not
+	// listed and indexed on every search. An index is rebuilt after toolSearchIndexTTL, or on the next search after
+	// a backend of the route sends notifications/tools/list_changed, is ejected or recovers, or after the tools of
+	// the routes are reconfigured.
+	toolSearchIndexCache struct {
+		mu      sync.Mutex
+		now     func() time.Time
+		indexes map[filterapi.MCPRouteName]*toolSearchIndex
+	}
+
+	// toolSearchIndex is the BM25 index of the tools of a route allowed by its tool selectors. The authorization
+	// rules and the protocol version of the clients are applied to the search results, since they depend on the
+	// client.
+	toolSearchIndex struct {
+		tools     []toolSearchEntry
+		bm25      *bm25Index
+		expiresAt time.Time
+	}
+
+	toolSearchEntry struct {
+		backend filterapi.MCPBackendName
+		// name is the name of the tool on the backend, without the backend prefix.
+		name string
+		// tool is the tool as listed to the clients, with the prefixed name.
+		tool *mcp.Tool
+	}
+
+	// toolSearchMatch is a tool returned by search_tools.
+	toolSearchMatch struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// bm25Index is an Okapi BM25 index of the names, descriptions and argument names of the tools.
+	bm25Index struct {
+		docs []bm25Document
+		// df is the number of documents containing each term.
+		df     map[string]int
+		avgLen float64
+	}
+
+	bm25Document struct {
+		tf     map[string]int
+		length int
+	}
+)
+
+// BM25 parameters, with the values commonly used for short documents.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (t *toolSearchResponse) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &t.list)
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (t toolSearchResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.result)
+}
+
+// handleMetaToolCall handles the tools/call requests of the meta-tools of the tool search mode. It returns false if
+// the tool is not a meta-tool.
+func (m *mcpRequestContext) handleMetaToolCall(ctx context.Context, s *session, w http.ResponseWriter, req *jsonrpc.Request,
+	p *mcp.CallToolParams, span tracingapi.MCPSpan, r *http.Request, maxResults int,
+) (bool, handlerResult, error) {
+	var args struct {
+		Query     string `json:"query"`
+		Limit     int    `json:"limit"`
+		Name      string `json:"name"`
+		Arguments any    `json:"arguments"`
+	}
+	switch p.Name {
+	case searchToolsName, describeToolName, callToolName:
+	default:
+		return false, handlerResult{}, nil
+	}
+	if p.Arguments != nil {
+		raw, _ := json.Marshal(p.Arguments)
+		if err := json.Unmarshal(raw, &args); err != nil {
+			err = fmt.Errorf("%w for tool %s: %w", errInvalidToolArguments, p.Name, err)
+			onInvalidToolArgumentsResponse(w, req.ID, err)
+			return true, handlerResult{}, err
+		}
+	}
+
+	var result func(idx *toolSearchIndex) *mcp.CallToolResult
+	switch p.Name {
+	case callToolName:
+		if args.Name == "" || args.Name == searchToolsName || args.Name == describeToolName || args.Name == callToolName {
+			err := fmt.Errorf("%w for tool %s: invalid tool name %q", errInvalidToolArguments, p.Name, args.Name)
+			onInvalidToolArgumentsResponse(w, req.ID, err)
+			return true, handlerResult{}, err
+		}
+		p.Name, p.Arguments = args.Name, args.Arguments
+		result, err := m.handleToolCallRequest(ctx, s, w, req, p, span, r)
+		return true, result, err
+	case searchToolsName:
+		if strings.TrimSpace(args.Query) == "" {
+			err := fmt.Errorf("%w for tool %s: missing query", errInvalidToolArguments, p.Name)
+			onInvalidToolArgumentsResponse(w, req.ID, err)
+			return true, handlerResult{}, err
+		}
+		limit := maxResults
+		if args.Limit > 0 && args.Limit < limit {
+			limit = args.Limit
+		}
+		result = func(idx *toolSearchIndex) *mcp.CallToolResult {
+			return newMetaToolResult(map[string]any{"tools": m.searchTools(s, idx, args.Query, limit)}, false)
+		}
+	case describeToolName:
+		result = func(idx *toolSearchIndex) *mcp.CallToolResult {
+			return m.describeTool(s, idx, args.Name)
+		}
+	}
+
+	if idx := m.toolSearchIndexes.get(s.route); idx != nil {
+		return true, handlerResult{}, m.writeMetaToolResult(w, req, result(idx))
+	}
+	// The index of the route is built from the tools listed by the backends for this session, and reused by the
+	// searches of all the sessions of the route.
+	mergeFn := func(s *session, responses []broadCastResponse[toolSearchResponse]) toolSearchResponse {
+		idx := m.newToolSearchIndex(s, responses)
+		m.toolSearchIndexes.set(s.route, idx)
+		return toolSearchResponse{result: result(idx)}
+	}
+	listReq := &jsonrpc.Request{ID: req.ID, Method: "tools/list"}
+	err := sendToAllBackendsAndAggregateResponses(ctx, m, w, s, listReq, &mcp.ListToolsParams{}, mergeFn, span,
+		func(cse *compositeSessionEntry) bool { return cse.capabilities != nil && cse.capabilities.Tools != nil })
+	if errors.Is(err, errBackendResponseError) {
+		// The tools of the other backends have been searched, and the index is rebuilt on the next search.
+		m.toolSearchIndexes.invalidate(s.route)
+		m.l.Warn("tool search without the tools of some backends", slog.String("error", err.Error()))
+		err = nil
+	}
+	return true, handlerResult{}, err
+}
+
+// newToolSearchIndex indexes the tools of the given tools/list responses allowed by the tool selectors of the route of
+// the session.
+func (m *mcpRequestContext) newToolSearchIndex(s *session, responses []broadCastResponse[toolSearchResponse]) *toolSearchIndex {
+	idx := &toolSearchIndex{}
+	var tools []*mcp.Tool
+	if route := m.routes[s.route]; route != nil {
+		for _, r := range responses {
+			selector := route.toolSelectors[r.backendName]
+			for _, tool := range r.res.list.Tools {
+				if selector != nil && !selector.allows(tool.Name) {
+					continue
+				}
+				if err := m.toolSchemas.update(s.route, r.backendName, tool); err != nil {
+					m.l.Warn("tool calls will not be fully validated", slog.String("backend", r.backendName), slog.String("error", err.Error()))
+				}
+				entry := toolSearchEntry{backend: r.backendName, name: tool.Name, tool: tool}
+				tool.Name = downstreamResourceName(tool.Name, r.backendName)
+				idx.tools = append(idx.tools, entry)
+				tools = append(tools, tool)
+			}
+		}
+	}
+	idx.bm25 = newBM25Index(tools)
+	return idx
+}
+
+// searchTools returns at most limit tools of the index matching the query that the client of the session can call.
+func (m *mcpRequestContext) searchTools(s *session, idx *toolSearchIndex, query string, limit int) []toolSearchMatch {
+	matches := make([]toolSearchMatch, 0, limit)
+	for _, i := range idx.bm25.search(query, len(idx.tools)) {
+		if len(matches) == limit {
+			break
+		}
+		if e := idx.tools[i]; m.toolSearchAllows(s, e) {
+			matches = append(matches, toolSearchMatch{Name: e.tool.Name, Description: e.tool.Description})
+		}
+	}
+	return matches
+}
+
+// describeTool returns the result of describe_tool for the tool of the index with the given prefixed name.
+func (m *mcpRequestContext) describeTool(s *session, idx *toolSearchIndex, name string) *mcp.CallToolResult {
+	for _, e := range idx.tools {
+		if e.tool.Name == name && m.toolSearchAllows(s, e) {
+			tool := *e.tool
+			downgradeTool(&tool, m.clientProtocolVersion())
+			return newMetaToolResult(&tool, false)
+		}
+	}
+	return newMetaToolResult(fmt.Sprintf("unknown tool %q", name), true)
+}
+
+// toolSearchAllows returns true if the given tool of the index can be called by the client of the session.
+func (m *mcpRequestContext) toolSearchAllows(s *session, e toolSearchEntry) bool {
+	route := m.routes[s.route]
+	if route == nil {
+		return false
+	}
+	if _, ok := s.perBackendSessions[e.backend]; !ok || !route.health.available(e.backend) {
+		return false
+	}
+	return m.authorizesToolCall(route, e.backend, e.name)
+}
+
+// writeMetaToolResult writes the given result of a meta-tool call as the JSON-RPC response to the given request.
+func (m *mcpRequestContext) writeMetaToolResult(w http.ResponseWriter, req *jsonrpc.Request, result *mcp.CallToolResult) error {
+	encodedResult, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+	encodedResp, _ := jsonrpc.EncodeMessage(&jsonrpc.Response{ID: req.ID, Result: encodedResult})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(encodedResp); err != nil {
+		m.l.Error("failed to write response", slog.String("error", err.Error()))
+	}
+	return nil
+}
+
+// maybeInvalidateToolSearchIndex drops the tool search index of the route of the session when the given message from
+// a backend notifies that its tools changed.
+func (m *mcpRequestContext) maybeInvalidateToolSearchIndex(s *session, msg jsonrpc.Message) {
+	if req, ok := msg.(*jsonrpc.Request); ok && req.Method == "notifications/tools/list_changed" {
+		m.toolSearchIndexes.invalidate(s.route)
+	}
+}
+
+// handleToolSearchListRequest handles the "tools/list" JSON-RPC method in the tool search mode.
+func (m *mcpRequestContext) handleToolSearchListRequest(w http.ResponseWriter, req *jsonrpc.Request) error {
+	encodedResult, err := json.Marshal(&mcp.ListToolsResult{Tools: metaTools})
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+	encodedResp, _ := jsonrpc.EncodeMessage(&jsonrpc.Response{ID: req.ID, Result: encodedResult})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(encodedResp); err != nil {
+		m.l.Error("failed to write response", slog.String("error", err.Error()))
+	}
+	return nil
+}
+
+func newToolSearchIndexCache() *toolSearchIndexCache {
+	return &toolSearchIndexCache{now: time.Now, indexes: make(map[filterapi.MCPRouteName]*toolSearchIndex)}
+}
+
+// get returns the index of the given route, or nil if it has not been built or has expired.
+func (c *toolSearchIndexCache) get(route filterapi.MCPRouteName) *toolSearchIndex {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	idx := c.indexes[route]
+	if idx != nil && !c.now().Before(idx.expiresAt) {
+		delete(c.indexes, route)
+		return nil
+	}
+	return idx
+}
+
+// set stores the index of the given route until it expires.
+func (c *toolSearchIndexCache) set(route filterapi.MCPRouteName, idx *toolSearchIndex) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	idx.expiresAt = c.now().Add(toolSearchIndexTTL)
+	c.indexes[route] = idx
+}
+
+// invalidate drops the index of the given route, which is rebuilt on the next search.
+func (c *toolSearchIndexCache) invalidate(route filterapi.MCPRouteName) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.indexes, route)
+}
+
+// clear drops the indexes of all the routes.
+func (c *toolSearchIndexCache) clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.indexes)
+}
+
+// newMetaToolResult returns the result of a meta-tool call with the given value as both the text and the
+// structured content. Strings are returned as text only.
+func newMetaToolResult(v any, isError bool) *mcp.CallToolResult {
+	if text, ok := v.(string); ok {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}, IsError: isError}
+	}
+	encoded, _ := json.Marshal(v)
+	return &mcp.CallToolResult{
+		Content:           []mcp.Content{&mcp.TextContent{Text: string(encoded)}},
+		StructuredContent: json.RawMessage(encoded),
+		IsError:           isError,
+	}
+}
+
+func newBM25Index(tools []*mcp.Tool) *bm25Index {
+	idx := &bm25Index{docs: make([]bm25Document, len(tools)), df: make(map[string]int)}
+	var totalLen int
+	for i, tool := range tools {
+		// The name is the most descriptive part of a tool, so its terms are counted twice.
+		terms := tokenizeToolText(tool.Name)
+		terms = append(terms, terms...)
+		terms = append(terms, tokenizeToolText(tool.Title)...)
+		terms = append(terms, tokenizeToolText(tool.Description)...)
+		if schema, ok := tool.InputSchema.(map[string]any); ok {
+			if properties, ok := schema["properties"].(map[string]any); ok {
+				for name := range properties {
+					terms = append(terms, tokenizeToolText(name)...)
+				}
+			}
+		}
+		doc := bm25Document{tf: make(map[string]int), length: len(terms)}
+		for _, term := range terms {
+			if doc.tf[term] == 0 {
+				idx.df[term]++
+			}
+			doc.tf[term]++
+		}
+		idx.docs[i] = doc
+		totalLen += doc.length
+	}
+	if len(tools) > 0 {
+		idx.avgLen = float64(totalLen) / float64(len(tools))
+	}
+	return idx
+}
+
+// search returns the indexes of at most limit documents matching the query, from the best to the worst match.
+func (idx *bm25Index) search(query string, limit int) []int {
+	type scored struct {
+		doc   int
+		score float64
+	}
+	terms := tokenizeToolText(query)
+	n := float64(len(idx.docs))
+	var results []scored
+	for i, doc := range idx.docs {
+		var score float64
+		for _, term := range terms {
+			tf := float64(doc.tf[term])
+			if tf == 0 {
+				continue
+			}
+			df := float64(idx.df[term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(doc.length)/idx.avgLen))
+		}
+		if score > 0 {
+			results = append(results, scored{doc: i, score: score})
+		}
+	}
+	slices.SortStableFunc(results, func(a, b scored) int { return cmp.Compare(b.score, a.score) })
+	docs := make([]int, 0, min(limit, len(results)))
+	for _, r := range results[:min(limit, len(results))] {
+		docs = append(docs, r.doc)
+	}
+	return docs
+}
+
+// tokenizeToolText splits the given text into lowercase terms at the non-alphanumeric characters and at the
+// camelCase boundaries, so that "github__listPullRequests" matches "pull requests".
+func tokenizeToolText(text string) []string {
+	var (
+		terms []string
+		term  []rune
+		prev  rune
+	)
+	flush := func() {
+		if len(term) > 0 {
+			terms = append(terms, strings.ToLower(string(term)))
+			term = term[:0]
+		}
+	}
+	for _, r := range text {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && unicode.IsLower(prev):
+			flush()
+			term = append(term, r)
+		default:
+			term = append(term, r)
+		}
+		prev = r
+	}
+	flush()
+	return terms
+}
@@ -0,0 +1,243 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpproxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestTokenizeToolText(t *testing.T) {
+	require.Equal(t, []string{"github", "list", "pull", "requests"}, tokenizeToolText("github__listPullRequests"))
+	require.Equal(t, []string{"get", "the", "weather", "in", "a", "city"}, tokenizeToolText("Get the weather, in a city."))
+	require.Equal(t, []string{"s3", "bucket", "v2"}, tokenizeToolText("s3-bucket.v2"))
+	require.Empty(t, tokenizeToolText(" _- "))
+}
+
+func TestBM25Index(t *testing.T) {
+	tools := []*mcp.Tool{
+		{Name: "github__create_issue", Description: "Create a new issue in a GitHub repository."},
+		{Name: "github__list_pull_requests", Description: "List the pull requests of a repository."},
+		{Name: "jira__create_issue", Description: "Create a Jira issue in a project."},
+		{Name: "weather__forecast", Description: "Get the weather forecast for a city.", InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"city": map[string]any{"type": "string"}},
+		}},
+	}
+	idx := newBM25Index(tools)
+	search := func(query string, limit int) []string {
+		var names []string
+		for _, i := range idx.search(query, limit) {
+			names = append(names, tools[i].Name)
+		}
+		return names
+	}
+
+	require.Equal(t, []string{"github__list_pull_requests"}, search("pull requests", 10))
+	require.Equal(t, []string{"jira__create_issue"}, search("jira issue", 1))
+	require.ElementsMatch(t, []string{"github__create_issue", "jira__create_issue"}, search("create issue", 10))
+	require.Equal(t, []string{"weather__forecast"}, search("city", 10))
+	require.Equal(t, "github__create_issue", search("github", 10)[0])
+	require.Empty(t, search("database", 10))
+	require.Empty(t, newBM25Index(nil).search("anything", 10))
+}
+
+func TestToolSearch(t *testing.T) {
+	var calledTool string
+	var listCount int
+	backendServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		msg, _ := jsonrpc.DecodeMessage(body)
+		req := msg.(*jsonrpc.Request)
+		var result []byte
+		switch req.Method {
+		case "tools/list":
+			listCount++
+			var tools []*mcp.Tool
+			switch r.Header.Get(internalapi.MCPBackendHeader) {
+			case "backend1":
+				tools = []*mcp.Tool{
+					{Name: "test-tool", Description: "Create an issue in a repository.", InputSchema: map[string]any{"type": "object"}},
+					{Name: "filtered-tool", Description: "Create an issue everywhere.", InputSchema: map[string]any{"type": "object"}},
+				}
+			case "backend2":
+				tools = []*mcp.Tool{
+					{Name: "forecast", Description: "Get the weather forecast for a city.", InputSchema: map[string]any{
+						"type":       "object",
+						"properties": map[string]any{"city": map[string]any{"type": "string"}},
+						"required":   []any{"city"},
+					}},
+				}
+			}
+			result, _ = json.Marshal(&mcp.ListToolsResult{Tools: tools})
+		case "tools/call":
+			var p mcp.CallToolParams
+			_ = json.Unmarshal(req.Params, &p)
+			calledTool = r.Header.Get(internalapi.MCPBackendHeader) + "/" + p.Name
+			result = []byte(`{"content":[{"type":"text","text":"sunny"}]}`)
+		}
+		respBody, _ := jsonrpc.EncodeMessage(&jsonrpc.Response{ID: req.ID, Result: result})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(respBody)
+	}))
+	t.Cleanup(backendServer.Close)
+
+	proxy := newTestMCPProxy()
+	proxy.backendListenerAddr = backendServer.URL
+	proxy.toolSchemas = newToolSchemaCache()
+	proxy.routes["test-route"].toolValidation = filterapi.MCPToolValidation{Arguments: true}
+	proxy.routes["test-route"].toolSearch = &filterapi.MCPToolSearch{MaxResults: 10}
+	toolsCapabilities := &mcp.ServerCapabilities{Tools: &mcp.ToolCapabilities{}}
+	s := &session{
+		reqCtx: proxy,
+		perBackendSessions: map[filterapi.MCPBackendName]*compositeSessionEntry{
+			"backend1": {sessionID: "session1", capabilities: toolsCapabilities},
+			"backend2": {sessionID: "session2", capabilities: toolsCapabilities},
+		},
+		route: "test-route",
+	}
+	callTool := func(name string, args any) (*httptest.ResponseRecorder, error) {
+		rr := httptest.NewRecorder()
+		req := &jsonrpc.Request{ID: mustJSONRPCRequestID(), Method: "tools/call"}
+		_, err := proxy.handleToolCallRequest(t.Context(), s, rr, req, &mcp.CallToolParams{Name: name, Arguments: args}, nil,
+			httptest.NewRequest(http.MethodPost, "/mcp", nil))
+		return rr, err
+	}
+	// requireResult returns the result of the last JSON-RPC message of the response.
+	requireResult := func(rr *httptest.ResponseRecorder) *mcp.CallToolResult {
+		body := rr.Body.String()
+		if i := strings.LastIndex(body, "data: "); i >= 0 {
+			body = strings.TrimSpace(body[i+len("data: "):])
+		}
+		msg, err := jsonrpc.DecodeMessage([]byte(body))
+		require.NoError(t, err)
+		var result mcp.CallToolResult
+		require.NoError(t, json.Unmarshal(msg.(*jsonrpc.Response).Result, &result))
+		return &result
+	}
+
+	t.Run("tools/list", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := &jsonrpc.Request{ID: mustJSONRPCRequestID(), Method: "tools/list"}
+		require.NoError(t, proxy.handleToolsListRequest(t.Context(), s, rr, req, &mcp.ListToolsParams{}, nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		msg, err := jsonrpc.DecodeMessage(rr.Body.Bytes())
+		require.NoError(t, err)
+		var result mcp.ListToolsResult
+		require.NoError(t, json.Unmarshal(msg.(*jsonrpc.Response).Result, &result))
+		var names []string
+		for _, tool := range result.Tools {
+			names = append(names, tool.Name)
+		}
+		require.Equal(t, []string{"search_tools", "describe_tool", "call_tool"}, names)
+	})
+
+	t.Run("search_tools", func(t *testing.T) {
+		rr, err := callTool("search_tools", map[string]any{"query": "create issue"})
+		require.NoError(t, err)
+		result := requireResult(rr)
+		require.False(t, result.IsError)
+		// The tools excluded by the tool selectors are not searched.
+		require.JSONEq(t, `{"tools":[{"name":"backend1__test-tool","description":"Create an issue in a repository."}]}`,
+			result.Content[0].(*mcp.TextContent).Text)
+
+		rr, err = callTool("search_tools", map[string]any{"query": "weather", "limit": 1})
+		require.NoError(t, err)
+		require.Contains(t, requireResult(rr).Content[0].(*mcp.TextContent).Text, `"name":"backend2__forecast"`)
+
+		rr, err = callTool("search_tools", map[string]any{"query": " "})
+		require.ErrorIs(t, err, errInvalidToolArguments)
+		require.Contains(t, rr.Body.String(), "missing query")
+	})
+
+	t.Run("describe_tool", func(t *testing.T) {
+		rr, err := callTool("describe_tool", map[string]any{"name": "backend2__forecast"})
+		require.NoError(t, err)
+		result := requireResult(rr)
+		require.False(t, result.IsError)
+		var tool mcp.Tool
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &tool))
+		require.Equal(t, "backend2__forecast", tool.Name)
+		require.Equal(t, "Get the weather forecast for a city.", tool.Description)
+		require.NotNil(t, tool.InputSchema)
+
+		rr, err = callTool("describe_tool", map[string]any{"name": "backend1__filtered-tool"})
+		require.NoError(t, err)
+		result = requireResult(rr)
+		require.True(t, result.IsError)
+		require.Equal(t, `unknown tool "backend1__filtered-tool"`, result.Content[0].(*mcp.TextContent).Text)
+	})
+
+	t.Run("index cache", func(t *testing.T) {
+		now := time.Now()
+		proxy.toolSearchIndexes = newToolSearchIndexCache()
+		proxy.toolSearchIndexes.now = func() time.Time { return now }
+		t.Cleanup(func() { proxy.toolSearchIndexes = nil })
+		search := func(query string) string {
+			rr, err := callTool("search_tools", map[string]any{"query": query})
+			require.NoError(t, err)
+			return requireResult(rr).Content[0].(*mcp.TextContent).Text
+		}
+
+		listCount = 0
+		require.Contains(t, search("weather"), `"name":"backend2__forecast"`)
+		require.Equal(t, 2, listCount)
+		// The index of the route is reused by the next searches and descriptions.
+		require.Contains(t, search("create issue"), `"name":"backend1__test-tool"`)
+		rr, err := callTool("describe_tool", map[string]any{"name": "backend2__forecast"})
+		require.NoError(t, err)
+		require.False(t, requireResult(rr).IsError)
+		require.Equal(t, 2, listCount)
+
+		// The index is rebuilt when a backend notifies that its tools changed.
+		proxy.maybeInvalidateToolSearchIndex(s, &jsonrpc.Request{Method: "notifications/tools/list_changed"})
+		require.Contains(t, search("weather"), `"name":"backend2__forecast"`)
+		require.Equal(t, 4, listCount)
+		proxy.maybeInvalidateToolSearchIndex(s, &jsonrpc.Request{Method: "notifications/resources/list_changed"})
+		search("weather")
+		require.Equal(t, 4, listCount)
+
+		// The index is rebuilt when it expires.
+		now = now.Add(toolSearchIndexTTL)
+		search("weather")
+		require.Equal(t, 6, listCount)
+	})
+
+	t.Run("call_tool", func(t *testing.T) {
+		rr, err := callTool("call_tool", map[string]any{"name": "backend2__forecast", "arguments": map[string]any{"city": "Tokyo"}})
+		require.NoError(t, err)
+		require.Contains(t, rr.Body.String(), "sunny")
+		require.Equal(t, "backend2/forecast", calledTool)
+
+		// The arguments are validated against the schema learned by the previous searches.
+		_, err = callTool("call_tool", map[string]any{"name": "backend2__forecast", "arguments": map[string]any{}})
+		require.ErrorIs(t, err, errInvalidToolArguments)
+
+		for _, name := range []string{"", "call_tool"} {
+			rr, err = callTool("call_tool", map[string]any{"name": name})
+			require.ErrorIs(t, err, errInvalidToolArguments)
+			require.Contains(t, rr.Body.String(), "invalid tool name")
+		}
+
+		// The tools can still be called directly.
+		calledTool = ""
+		_, err = callTool("backend2__forecast", map[string]any{"city": "Paris"})
+		require.NoError(t, err)
+		require.Equal(t, "backend2/forecast", calledTool)
+	})
+}
@@ -0,0 +1,394 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package mcpsse bridges the legacy HTTP+SSE transport of the MCP specification 2024-11-05 and the Streamable HTTP
+// transport, in both directions.
+//
+// [Bridge] serves a backend speaking the legacy transport as a Streamable HTTP MCP server, and [LegacyHandler]
+// serves a Streamable HTTP MCP server to the clients speaking the legacy transport.
+package mcpsse
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	sessionIDHeader       = "Mcp-Session-Id"
+	protocolVersionHeader = "Mcp-Protocol-Version"
+	// maxMessageSize is the maximum size of a message received by the bridges.
+	maxMessageSize = 4 * 1024 * 1024
+	// pendingMessages is the number of server messages buffered for a session while no stream is consuming them.
+	pendingMessages = 100
+)
+
+var (
+	// reapInterval is the interval at which the idle sessions are closed.
+	reapInterval = time.Minute
+	// errSessionClosed is returned when the connection of a session is closed while waiting for a response.
+	errSessionClosed = errors.New("session closed")
+)
+
+// ConnectFunc opens a connection to the backend for the MCP session initialized by the given request.
+//
+// The connection must outlive the request, so its context must not be used to bound the connection.
+type ConnectFunc func(*http.Request) (mcp.Connection, error)
+
+// Bridge is a Streamable HTTP MCP server that forwards each MCP session to its own connection to a backend,
+// typically speaking the legacy HTTP+SSE transport through an [mcp.SSEClientTransport].
+//
+// The responses of the backend are returned in the response of the corresponding request, and the requests and
+// notifications sent by the backend are delivered over the stream opened with a GET request.
+type Bridge struct {
+	name        string
+	connect     ConnectFunc
+	idleTimeout time.Duration
+	logger      *slog.Logger
+	url         string
+	httpServer  *http.Server
+	done        chan struct{}
+	closeOnce   sync.Once
+	wg          sync.WaitGroup
+
+	mu       sync.Mutex
+	sessions map[string]*bridgeSession
+}
+
+// bridgeSession is an MCP session forwarded to a backend connection.
+type bridgeSession struct {
+	id   string
+	conn mcp.Connection
+	// done is closed when the connection is closed.
+	done chan struct{}
+	// messages are the requests and notifications sent by the backend, delivered over the GET stream.
+	messages chan jsonrpc.Message
+
+	mu sync.Mutex
+	// pending are the channels waiting for the response of the requests in flight, keyed by the request ID.
+	pending map[jsonrpc.ID]chan *jsonrpc.Response
+	// lastActive is the last time the session was used, and streams is the number of open GET streams.
+	lastActive time.Time
+	streams    int
+}
+
+// StartBridge starts serving a bridge on the loopback interface. Each MCP session initialized with the bridge is
+// forwarded to a connection opened with the given function. Sessions that are not used for idleTimeout are closed,
+// unless idleTimeout is zero.
+func StartBridge(logger *slog.Logger, name string, connect ConnectFunc, idleTimeout time.Duration) (*Bridge, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the %s SSE bridge: %w", name, err)
+	}
+	b := &Bridge{
+		name:        name,
+		connect:     connect,
+		idleTimeout: idleTimeout,
+		logger:      logger.With(slog.String("sse_bridge", name)),
+		url:         fmt.Sprintf("http://%s/mcp", listener.Addr().String()),
+		done:        make(chan struct{}),
+		sessions:    make(map[string]*bridgeSession),
+	}
+	b.httpServer = &http.Server{Handler: b, ReadHeaderTimeout: 120 * time.Second}
+	go func() {
+		b.logger.Info("serving SSE bridge", slog.String("url", b.url))
+		if serveErr := b.httpServer.Serve(listener); serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+			b.logger.Error("SSE bridge server error", slog.String("error", serveErr.Error()))
+		}
+	}()
+	if idleTimeout > 0 {
+		b.wg.Add(1)
+		go b.reapSessions()
+	}
+	return b, nil
+}
+
+// URL returns the Streamable HTTP endpoint of the bridge.
+func (b *Bridge) URL() string {
+	return b.url
+}
+
+// Close stops the bridge and closes all its sessions.
+func (b *Bridge) Close() error {
+	b.closeOnce.Do(func() { close(b.done) })
+	b.mu.Lock()
+	for _, s := range b.sessions {
+		_ = s.conn.Close()
+	}
+	b.mu.Unlock()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := b.httpServer.Shutdown(shutdownCtx)
+	b.wg.Wait()
+	return err
+}
+
+// ServeHTTP implements [http.Handler].
+func (b *Bridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		b.servePOST(w, r)
+	case http.MethodGet:
+		b.serveGET(w, r)
+	case http.MethodDelete:
+		s := b.session(r)
+		if s == nil {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+		_ = s.conn.Close()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// session returns the session of the given request, or nil if it doesn't exist.
+func (b *Bridge) session(r *http.Request) *bridgeSession {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.sessions[r.Header.Get(sessionIDHeader)]
+	if s != nil {
+		s.touch(0)
+	}
+	return s
+}
+
+func (b *Bridge) servePOST(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageSize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	msg, err := jsonrpc.DecodeMessage(body)
+	if err != nil {
+		http.Error(w, "invalid JSON-RPC message", http.StatusBadRequest)
+		return
+	}
+
+	var s *bridgeSession
+	if r.Header.Get(sessionIDHeader) == "" {
+		if req, ok := msg.(*jsonrpc.Request); !ok || req.Method != "initialize" {
+			http.Error(w, "session ID is required", http.StatusBadRequest)
+			return
+		}
+		if s, err = b.newSession(r); err != nil {
+			b.logger.Error("failed to connect to the SSE backend", slog.String("error", err.Error()))
+			http.Error(w, "failed to connect to the backend", http.StatusBadGateway)
+			return
+		}
+		w.Header().Set(sessionIDHeader, s.id)
+	} else if s = b.session(r); s == nil {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+
+	req, ok := msg.(*jsonrpc.Request)
+	if !ok || !req.IsCall() {
+		// Notifications and responses to the backend requests don't have a response.
+		if err = s.conn.Write(r.Context(), msg); err != nil {
+			http.Error(w, "failed to send the message to the backend", http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	resp, err := s.call(r.Context(), req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to call the backend: %v", err), http.StatusBadGateway)
+		return
+	}
+	encoded, err := jsonrpc.EncodeMessage(resp)
+	if err != nil {
+		http.Error(w, "failed to encode the response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(encoded)
+}
+
+func (b *Bridge) serveGET(w http.ResponseWriter, r *http.Request) {
+	s := b.session(r)
+	if s == nil {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	s.touch(1)
+	defer s.touch(-1)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flush(w)
+	for {
+		select {
+		case msg := <-s.messages:
+			encoded, err := jsonrpc.EncodeMessage(msg)
+			if err != nil {
+				b.logger.Error("failed to encode the backend message", slog.String("error", err.Error()))
+				continue
+			}
+			if err = writeEvent(w, "message", encoded); err != nil {
+				return
+			}
+		case <-s.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// newSession connects to the backend and starts a new session.
+func (b *Bridge) newSession(r *http.Request) (*bridgeSession, error) {
+	conn, err := b.connect(r)
+	if err != nil {
+		return nil, err
+	}
+	s := &bridgeSession{
+		id:         rand.Text(),
+		conn:       conn,
+		done:       make(chan struct{}),
+		messages:   make(chan jsonrpc.Message, pendingMessages),
+		pending:    make(map[jsonrpc.ID]chan *jsonrpc.Response),
+		lastActive: time.Now(),
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	select {
+	case <-b.done:
+		_ = conn.Close()
+		return nil, errors.New("bridge is closed")
+	default:
+	}
+	b.sessions[s.id] = s
+	b.wg.Add(1)
+	go b.readLoop(s)
+	return s, nil
+}
+
+// readLoop dispatches the messages received from the backend until the connection is closed.
+func (b *Bridge) readLoop(s *bridgeSession) {
+	defer b.wg.Done()
+	defer func() {
+		_ = s.conn.Close()
+		b.mu.Lock()
+		delete(b.sessions, s.id)
+		b.mu.Unlock()
+		close(s.done)
+	}()
+	for {
+		msg, err := s.conn.Read(context.Background())
+		if err != nil {
+			return
+		}
+		switch m := msg.(type) {
+		case *jsonrpc.Response:
+			s.mu.Lock()
+			ch := s.pending[m.ID]
+			delete(s.pending, m.ID)
+			s.mu.Unlock()
+			if ch != nil {
+				ch <- m
+			}
+		default:
+			select {
+			case s.messages <- msg:
+			default:
+				b.logger.Warn("dropping a message from the SSE backend, no stream is consuming them",
+					slog.String("session_id", s.id))
+			}
+		}
+	}
+}
+
+// call sends the given request to the backend and waits for its response.
+func (s *bridgeSession) call(ctx context.Context, req *jsonrpc.Request) (*jsonrpc.Response, error) {
+	ch := make(chan *jsonrpc.Response, 1)
+	s.mu.Lock()
+	s.pending[req.ID] = ch
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, req.ID)
+		s.mu.Unlock()
+	}()
+
+	if err := s.conn.Write(ctx, req); err != nil {
+		return nil, err
+	}
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-s.done:
+		return nil, errSessionClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// touch marks the session as active, and updates the number of open streams by the given delta.
+func (s *bridgeSession) touch(streams int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastActive = time.Now()
+	s.streams += streams
+}
+
+// reapSessions periodically closes the idle sessions until the bridge is closed.
+func (b *Bridge) reapSessions() {
+	defer b.wg.Done()
+	ticker := time.NewTicker(reapInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			b.reap(time.Now())
+		case <-b.done:
+			return
+		}
+	}
+}
+
+// reap closes the sessions without any open stream that haven't been used for the idle timeout.
+func (b *Bridge) reap(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, s := range b.sessions {
+		s.mu.Lock()
+		idle := s.streams == 0 && now.Sub(s.lastActive) >= b.idleTimeout
+		s.mu.Unlock()
+		if idle {
+			b.logger.Info("closing idle SSE bridge session", slog.String("session_id", s.id))
+			_ = s.conn.Close()
+		}
+	}
+}
+
+// writeEvent writes a server-sent event and flushes it.
+func writeEvent(w http.ResponseWriter, name string, data []byte) error {
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data); err != nil {
+		return err
+	}
+	flush(w)
+	return nil
+}
+
+func flush(w http.ResponseWriter) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,309 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpsse
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+)
+
+// LegacyHandler serves a Streamable HTTP MCP server to the clients speaking the legacy HTTP+SSE transport.
+//
+// The client opens an SSE stream, over which the handler advertises the endpoint the client posts its messages to.
+// Each message is then served by the Streamable HTTP handler, and the messages it returns are sent over the SSE stream.
+// The state of the sessions is kept in memory, so all the requests of a session must reach the same handler.
+type LegacyHandler struct {
+	next   http.Handler
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	sessions map[string]*legacySession
+}
+
+// legacySession is an MCP session of a client speaking the legacy transport.
+type legacySession struct {
+	// ctx is canceled when the SSE stream of the client is closed.
+	ctx context.Context
+	// header are the headers of the request that opened the SSE stream.
+	header http.Header
+	// messages are the messages to send over the SSE stream.
+	messages chan []byte
+
+	mu sync.Mutex
+	// mcpSessionID is the session ID returned by the Streamable HTTP handler on initialization.
+	mcpSessionID string
+	// protocolVersion is the protocol version requested by the client on initialization. It is sent in the
+	// MCP-Protocol-Version header of the following requests, which the legacy clients don't send themselves.
+	protocolVersion string
+}
+
+// NewLegacyHandler returns a new LegacyHandler serving the given Streamable HTTP handler.
+func NewLegacyHandler(next http.Handler, logger *slog.Logger) *LegacyHandler {
+	return &LegacyHandler{next: next, logger: logger, sessions: make(map[string]*legacySession)}
+}
+
+// ServeSSE serves the SSE stream of a new session. The given message path is advertised to the client as the
+// endpoint to post its messages to.
+func (h *LegacyHandler) ServeSSE(w http.ResponseWriter, r *http.Request, messagePath string) {
+	id := rand.Text()
+	s := &legacySession{ctx: r.Context(), header: r.Header.Clone(), messages: make(chan []byte, pendingMessages)}
+	h.mu.Lock()
+	h.sessions[id] = s
+	h.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		delete(h.sessions, id)
+		h.mu.Unlock()
+		h.closeSession(s)
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	endpoint := messagePath + "?sessionId=" + url.QueryEscape(id)
+	if err := writeEvent(w, "endpoint", []byte(endpoint)); err != nil {
+		return
+	}
+	for {
+		select {
+		case msg := <-s.messages:
+			if err := writeEvent(w, "message", msg); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// ServeMessage serves a message posted by a client to the endpoint advertised over its SSE stream.
+//
+// The requests are served asynchronously, and their responses are sent over the SSE stream. The initialization
+// request, the notifications and the responses are served synchronously to preserve their ordering.
+func (h *LegacyHandler) ServeMessage(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	s := h.sessions[r.URL.Query().Get("sessionId")]
+	h.mu.Unlock()
+	if s == nil {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageSize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	msg, err := jsonrpc.DecodeMessage(body)
+	if err != nil {
+		http.Error(w, "invalid JSON-RPC message", http.StatusBadRequest)
+		return
+	}
+
+	req, isRequest := msg.(*jsonrpc.Request)
+	isCall := isRequest && req.IsCall()
+	if isCall && req.Method != "initialize" {
+		go h.forward(r, s, body, req)
+	} else {
+		h.forward(r, s, body, req)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// forward serves the given message with the Streamable HTTP handler, and sends the resulting messages over the SSE
+// stream of the session. The request is nil unless the message is a request.
+func (h *LegacyHandler) forward(r *http.Request, s *legacySession, body []byte, req *jsonrpc.Request) {
+	inner := r.Clone(s.ctx)
+	inner.Body = io.NopCloser(bytes.NewReader(body))
+	inner.ContentLength = int64(len(body))
+	inner.Header.Set("Content-Type", "application/json")
+	inner.Header.Set("Accept", "application/json, text/event-stream")
+	s.mu.Lock()
+	if s.mcpSessionID != "" {
+		inner.Header.Set(sessionIDHeader, s.mcpSessionID)
+	}
+	if s.protocolVersion != "" {
+		inner.Header.Set(protocolVersionHeader, s.protocolVersion)
+	}
+	s.mu.Unlock()
+
+	rec := newEventRecorder(s.send)
+	h.next.ServeHTTP(rec, inner)
+	rec.finish()
+	if rec.status >= http.StatusBadRequest {
+		h.logger.Warn("failed to serve the message of a legacy SSE client",
+			slog.Int("status", rec.status), slog.String("body", strings.TrimSpace(rec.body.String())))
+		if req != nil && req.IsCall() && !rec.sent {
+			s.sendError(req.ID, fmt.Sprintf("request failed with status %d", rec.status))
+		}
+		return
+	}
+
+	if req != nil && req.Method == "initialize" {
+		if id := rec.header.Get(sessionIDHeader); id != "" {
+			var params struct {
+				ProtocolVersion string `json:"protocolVersion"`
+			}
+			_ = json.Unmarshal(req.Params, &params)
+			s.mu.Lock()
+			s.mcpSessionID = id
+			s.protocolVersion = params.ProtocolVersion
+			s.mu.Unlock()
+			// Relay the messages sent by the server outside of the responses.
+			go h.stream(s)
+		}
+	}
+}
+
+// stream relays the messages of the GET stream of the Streamable HTTP handler until the session is closed.
+func (h *LegacyHandler) stream(s *legacySession) {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodGet, "/", nil)
+	if err != nil {
+		return
+	}
+	req.Header = s.header.Clone()
+	req.Header.Set("Accept", "text/event-stream")
+	s.mu.Lock()
+	req.Header.Set(sessionIDHeader, s.mcpSessionID)
+	if s.protocolVersion != "" {
+		req.Header.Set(protocolVersionHeader, s.protocolVersion)
+	}
+	s.mu.Unlock()
+	rec := newEventRecorder(s.send)
+	h.next.ServeHTTP(rec, req)
+}
+
+// closeSession terminates the session of the Streamable HTTP handler, if it was initialized.
+func (h *LegacyHandler) closeSession(s *legacySession) {
+	s.mu.Lock()
+	id := s.mcpSessionID
+	s.mu.Unlock()
+	if id == "" {
+		return
+	}
+	req, err := http.NewRequestWithContext(context.WithoutCancel(s.ctx), http.MethodDelete, "/", nil)
+	if err != nil {
+		return
+	}
+	req.Header = s.header.Clone()
+	req.Header.Set(sessionIDHeader, id)
+	h.next.ServeHTTP(newEventRecorder(func([]byte) {}), req)
+}
+
+// send queues the given message to be sent over the SSE stream, unless the stream is closed.
+func (s *legacySession) send(msg []byte) {
+	select {
+	case s.messages <- msg:
+	case <-s.ctx.Done():
+	}
+}
+
+// sendError sends an error response to the request with the given ID.
+func (s *legacySession) sendError(id jsonrpc.ID, message string) {
+	encoded, err := jsonrpc.EncodeMessage(&jsonrpc.Response{
+		ID:    id,
+		Error: &jsonrpc.Error{Code: jsonrpc.CodeInternalError, Message: message},
+	})
+	if err == nil {
+		s.send(encoded)
+	}
+}
+
+// eventRecorder is an [http.ResponseWriter] that extracts the JSON-RPC messages written by a Streamable HTTP handler,
+// either as a JSON response or as server-sent events.
+type eventRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+	// onMessage is called with each message, as soon as it is complete for server-sent events.
+	onMessage func([]byte)
+	// sent is true if at least one message was extracted.
+	sent bool
+}
+
+func newEventRecorder(onMessage func([]byte)) *eventRecorder {
+	return &eventRecorder{header: make(http.Header), onMessage: onMessage}
+}
+
+// Header implements [http.ResponseWriter].
+func (e *eventRecorder) Header() http.Header { return e.header }
+
+// WriteHeader implements [http.ResponseWriter].
+func (e *eventRecorder) WriteHeader(status int) {
+	if e.status == 0 {
+		e.status = status
+	}
+}
+
+// Write implements [http.ResponseWriter].
+func (e *eventRecorder) Write(b []byte) (int, error) {
+	e.WriteHeader(http.StatusOK)
+	e.body.Write(bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n")))
+	if e.isEventStream() {
+		for {
+			event, rest, ok := bytes.Cut(e.body.Bytes(), []byte("\n\n"))
+			if !ok {
+				break
+			}
+			e.emitEvent(event)
+			remaining := bytes.Clone(rest)
+			e.body.Reset()
+			e.body.Write(remaining)
+		}
+	}
+	return len(b), nil
+}
+
+// Flush implements [http.Flusher].
+func (e *eventRecorder) Flush() {}
+
+// finish extracts the message of a JSON response once the handler has returned.
+func (e *eventRecorder) finish() {
+	if e.status == 0 {
+		e.status = http.StatusOK
+	}
+	if e.isEventStream() {
+		e.emitEvent(e.body.Bytes())
+		return
+	}
+	if e.status < http.StatusBadRequest && strings.HasPrefix(e.header.Get("Content-Type"), "application/json") {
+		if msg := bytes.TrimSpace(e.body.Bytes()); json.Valid(msg) && len(msg) > 0 {
+			e.emit(msg)
+		}
+	}
+}
+
+func (e *eventRecorder) isEventStream() bool {
+	return strings.HasPrefix(e.header.Get("Content-Type"), "text/event-stream")
+}
+
+// emitEvent emits the data of the given server-sent event, if any.
+func (e *eventRecorder) emitEvent(event []byte) {
+	var data [][]byte
+	for line := range bytes.SplitSeq(event, []byte("\n")) {
+		if v, ok := bytes.CutPrefix(line, []byte("data:")); ok {
+			data = append(data, bytes.TrimPrefix(v, []byte(" ")))
+		}
+	}
+	if len(data) > 0 {
+		e.emit(bytes.Join(data, []byte("\n")))
+	}
+}
+
+func (e *eventRecorder) emit(msg []byte) {
+	e.sent = true
+	e.onMessage(bytes.Clone(msg))
+}
@@ -0,0 +1,243 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpsse
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer returns an MCP server with an echo tool, and a roots tool that lists the roots of the client,
+// which exercises the requests sent by the server to the client.
+func newTestServer() *mcp.Server {
+	type args struct {
+		Text string `json:"text"`
+	}
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "echo", Description: "echo tool"},
+		func(_ context.Context, _ *mcp.CallToolRequest, args args) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: args.Text}}}, nil, nil
+		})
+	mcp.AddTool(server, &mcp.Tool{Name: "roots", Description: "lists the roots of the client"},
+		func(ctx context.Context, req *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+			roots, err := req.Session.ListRoots(ctx, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+			var uris []string
+			for _, r := range roots.Roots {
+				uris = append(uris, r.URI)
+			}
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: strings.Join(uris, ",")}}}, nil, nil
+		})
+	return server
+}
+
+func requireTools(t *testing.T, ctx context.Context, transport mcp.Transport) *mcp.ClientSession {
+	client := mcp.NewClient(&mcp.Implementation{Name: t.Name()}, nil)
+	client.AddRoots(&mcp.Root{URI: "file:///a"}, &mcp.Root{URI: "file:///b"})
+	cs, err := client.Connect(ctx, transport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cs.Close() })
+
+	tools, err := cs.ListTools(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, tools.Tools, 2)
+
+	res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: "echo", Arguments: map[string]any{"text": "hello"}})
+	require.NoError(t, err)
+	require.Equal(t, "hello", res.Content[0].(*mcp.TextContent).Text)
+
+	res, err = cs.CallTool(ctx, &mcp.CallToolParams{Name: "roots", Arguments: map[string]any{}})
+	require.NoError(t, err)
+	require.False(t, res.IsError, "%s", res.Content[0].(*mcp.TextContent).Text)
+	require.Equal(t, "file:///a,file:///b", res.Content[0].(*mcp.TextContent).Text)
+	return cs
+}
+
+func TestBridge(t *testing.T) {
+	backend := httptest.NewServer(mcp.NewSSEHandler(func(*http.Request) *mcp.Server { return newTestServer() }, nil))
+	t.Cleanup(backend.Close)
+
+	b, err := StartBridge(slog.New(slog.DiscardHandler), "test", func(*http.Request) (mcp.Connection, error) {
+		return (&mcp.SSEClientTransport{Endpoint: backend.URL}).Connect(context.Background())
+	}, 0)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = b.Close() })
+
+	cs := requireTools(t, t.Context(), &mcp.StreamableClientTransport{Endpoint: b.URL()})
+	b.mu.Lock()
+	require.Len(t, b.sessions, 1)
+	b.mu.Unlock()
+
+	// Closing the client session closes the backend connection.
+	require.NoError(t, cs.Close())
+	require.Eventually(t, func() bool {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return len(b.sessions) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestBridge_Errors(t *testing.T) {
+	b, err := StartBridge(slog.New(slog.DiscardHandler), "test", func(*http.Request) (mcp.Connection, error) {
+		return (&mcp.SSEClientTransport{Endpoint: "http://127.0.0.1:1/sse"}).Connect(context.Background())
+	}, 0)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = b.Close() })
+
+	for _, tc := range []struct {
+		name      string
+		method    string
+		sessionID string
+		body      string
+		expStatus int
+	}{
+		{name: "invalid message", method: http.MethodPost, body: "{", expStatus: http.StatusBadRequest},
+		{name: "not initialize", method: http.MethodPost, body: `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`, expStatus: http.StatusBadRequest},
+		{name: "connection failure", method: http.MethodPost, body: `{"jsonrpc":"2.0","id":1,"method":"initialize"}`, expStatus: http.StatusBadGateway},
+		{name: "unknown session", method: http.MethodPost, sessionID: "unknown", body: `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`, expStatus: http.StatusNotFound},
+		{name: "unknown session GET", method: http.MethodGet, sessionID: "unknown", expStatus: http.StatusNotFound},
+		{name: "unknown session DELETE", method: http.MethodDelete, sessionID: "unknown", expStatus: http.StatusNotFound},
+		{name: "method not allowed", method: http.MethodPut, expStatus: http.StatusMethodNotAllowed},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequestWithContext(t.Context(), tc.method, b.URL(), strings.NewReader(tc.body))
+			require.NoError(t, err)
+			if tc.sessionID != "" {
+				req.Header.Set(sessionIDHeader, tc.sessionID)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			require.Equal(t, tc.expStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestBridge_reap(t *testing.T) {
+	backend := httptest.NewServer(mcp.NewSSEHandler(func(*http.Request) *mcp.Server { return newTestServer() }, nil))
+	t.Cleanup(backend.Close)
+	b, err := StartBridge(slog.New(slog.DiscardHandler), "test", func(*http.Request) (mcp.Connection, error) {
+		return (&mcp.SSEClientTransport{Endpoint: backend.URL}).Connect(context.Background())
+	}, time.Minute)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = b.Close() })
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, b.URL(),
+		strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	b.reap(time.Now())
+	b.mu.Lock()
+	require.Len(t, b.sessions, 1)
+	b.mu.Unlock()
+
+	b.reap(time.Now().Add(time.Minute))
+	require.Eventually(t, func() bool {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return len(b.sessions) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func newLegacyServer(t *testing.T, next http.Handler) *httptest.Server {
+	h := NewLegacyHandler(next, slog.New(slog.DiscardHandler))
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sse", func(w http.ResponseWriter, r *http.Request) { h.ServeSSE(w, r, "/messages") })
+	mux.HandleFunc("POST /messages", h.ServeMessage)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLegacyHandler(t *testing.T) {
+	var deleted atomic.Bool
+	streamable := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return newTestServer() }, nil)
+	server := newLegacyServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted.Store(true)
+		}
+		streamable.ServeHTTP(w, r)
+	}))
+
+	cs := requireTools(t, t.Context(), &mcp.SSEClientTransport{Endpoint: server.URL + "/sse"})
+
+	// Closing the SSE stream terminates the Streamable HTTP session.
+	require.NoError(t, cs.Close())
+	require.Eventually(t, deleted.Load, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestLegacyHandler_ServeMessage_Errors(t *testing.T) {
+	server := newLegacyServer(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+
+	resp, err := http.Post(server.URL+"/messages?sessionId=unknown", "application/json", strings.NewReader("{}"))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// A request failing in the Streamable HTTP handler gets an error response over the SSE stream.
+	client := mcp.NewClient(&mcp.Implementation{Name: t.Name()}, nil)
+	_, err = client.Connect(t.Context(), &mcp.SSEClientTransport{Endpoint: server.URL + "/sse"}, nil)
+	require.ErrorContains(t, err, "request failed with status 503")
+}
+
+func TestLegacyToBridge(t *testing.T) {
+	// A legacy client connected to a legacy backend through both bridges.
+	backend := httptest.NewServer(mcp.NewSSEHandler(func(*http.Request) *mcp.Server { return newTestServer() }, nil))
+	t.Cleanup(backend.Close)
+	b, err := StartBridge(slog.New(slog.DiscardHandler), "test", func(*http.Request) (mcp.Connection, error) {
+		return (&mcp.SSEClientTransport{Endpoint: backend.URL}).Connect(context.Background())
+	}, 0)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = b.Close() })
+
+	server := newLegacyServer(t, b)
+	requireTools(t, t.Context(), &mcp.SSEClientTransport{Endpoint: server.URL + "/sse"})
+}
+
+func TestEventRecorder(t *testing.T) {
+	var msgs []string
+	rec := newEventRecorder(func(b []byte) { msgs = append(msgs, string(b)) })
+	rec.Header().Set("Content-Type", "text/event-stream")
+	_, _ = rec.Write([]byte("event: message\r\ndata: {\"a\":1}\r\n\r\nevent: message\ndata: {\"b\":"))
+	require.Equal(t, []string{`{"a":1}`}, msgs)
+	_, _ = rec.Write([]byte("2}\n\n: comment\n\n"))
+	rec.finish()
+	require.Equal(t, []string{`{"a":1}`, `{"b":2}`}, msgs)
+	require.True(t, rec.sent)
+
+	msgs = nil
+	rec = newEventRecorder(func(b []byte) { msgs = append(msgs, string(b)) })
+	rec.Header().Set("Content-Type", "application/json")
+	_, _ = rec.Write([]byte(`{"c":`))
+	_, _ = rec.Write([]byte("3}\n"))
+	require.Empty(t, msgs)
+	rec.finish()
+	require.Equal(t, []string{`{"c":3}`}, msgs)
+
+	msgs = nil
+	rec = newEventRecorder(func(b []byte) { msgs = append(msgs, string(b)) })
+	rec.WriteHeader(http.StatusAccepted)
+	rec.finish()
+	require.Empty(t, msgs)
+	require.False(t, rec.sent)
+}
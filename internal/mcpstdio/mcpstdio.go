@@ -0,0 +1,367 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package mcpstdio runs stdio MCP servers as supervised child processes and exposes them
+// as Streamable HTTP MCP servers on the loopback interface.
+//
+// The endpoint of each server contains a random token, so that the other processes of the host, which can connect
+// to the loopback interface as well, cannot run the commands of the servers without knowing it.
+package mcpstdio
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var (
+	// minRestartBackoff and maxRestartBackoff bound the delay before a shared process is restarted.
+	minRestartBackoff = time.Second
+	maxRestartBackoff = 30 * time.Second
+	// restartBackoffReset is how long a process must run for the restart backoff to be reset.
+	restartBackoffReset = time.Minute
+	// reapInterval is the interval at which the per-session processes are checked for termination.
+	reapInterval = 5 * time.Second
+	// sessionStartGracePeriod is how long a per-session process is kept without any MCP session attached,
+	// which gives the client time to complete the initialization.
+	sessionStartGracePeriod = 30 * time.Second
+)
+
+// Config is the configuration of a stdio MCP server.
+type Config struct {
+	// Command is the command to run.
+	Command string
+	// Args are the arguments of the command.
+	Args []string
+	// Env is the environment of the process in the "KEY=value" form.
+	// If nil, the process inherits the environment of the current process.
+	Env []string
+	// PerSession starts a dedicated process for each MCP session instead of sharing a single process
+	// across all of them.
+	PerSession bool
+	// MaxProcesses is the maximum number of concurrent processes when PerSession is set. Zero means unlimited.
+	MaxProcesses int
+	// SessionIdleTimeout closes the MCP sessions that haven't received any request for this duration.
+	// Zero means that idle sessions are never closed.
+	SessionIdleTimeout time.Duration
+	// MemoryLimitBytes is the maximum size of the virtual memory of each process. Zero means no limit.
+	//
+	// This limits the address space of the process rather than the memory it uses, so it doesn't suit the runtimes
+	// reserving large amounts of virtual memory upfront, such as Node.js: the servers run with npx fail to start
+	// even with a generous limit.
+	MemoryLimitBytes uint64
+	// CPUTimeLimit is the maximum CPU time each process can consume. Zero means no limit.
+	//
+	// The limits are only supported on Linux, where they are set before the command is executed.
+	CPUTimeLimit time.Duration
+}
+
+// Server is a Streamable HTTP MCP server that proxies the requests to stdio MCP server processes.
+//
+// In the shared mode, a single process serves all the MCP sessions and it is restarted with an exponential
+// backoff when it exits. In the per-session mode, a new process is started for each MCP session and
+// terminated when the session ends.
+type Server struct {
+	name       string
+	cfg        Config
+	logger     *slog.Logger
+	url        string
+	httpServer *http.Server
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+
+	// shared is the proxy server used in the shared mode, forwarding the requests to current.
+	shared  *mcp.Server
+	current atomic.Pointer[process]
+	// ready is closed once the first attempt to start the shared process completes.
+	ready chan struct{}
+
+	// mu protects the fields of the per-session mode below.
+	mu sync.Mutex
+	// sessions are the per-session proxy servers and their processes.
+	sessions map[*mcp.Server]*sessionProcess
+	// starting is the number of per-session processes being started.
+	starting int
+}
+
+// sessionProcess is a process started for a single MCP session.
+type sessionProcess struct {
+	process   *process
+	startedAt time.Time
+	// attached is true once the MCP session has been seen attached to the proxy server.
+	attached bool
+}
+
+// Start starts serving the stdio MCP server with the given name and configuration.
+//
+// This doesn't wait for the processes to be started. In the shared mode, the process is started and initialized in
+// the background, restarting it if it fails, and new MCP sessions are rejected until it is running. Use Ready to
+// wait for the first attempt to complete. The server runs until Close is called or the given context is done.
+func Start(ctx context.Context, logger *slog.Logger, name string, cfg Config) (*Server, error) {
+	if cfg.Command == "" {
+		return nil, errors.New("command is required")
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the %s stdio MCP server: %w", name, err)
+	}
+	path := "/" + rand.Text() + "/mcp"
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Server{
+		name:   name,
+		cfg:    cfg,
+		logger: logger.With(slog.String("stdio_server", name)),
+		url:    fmt.Sprintf("http://%s%s", listener.Addr().String(), path),
+		ctx:    ctx,
+		cancel: cancel,
+		ready:  make(chan struct{}),
+	}
+
+	var getServer func(*http.Request) *mcp.Server
+	if cfg.PerSession {
+		s.sessions = make(map[*mcp.Server]*sessionProcess)
+		getServer = s.newSessionServer
+		s.wg.Add(1)
+		go s.reapSessions()
+		close(s.ready)
+	} else {
+		s.shared = mcp.NewServer(&mcp.Implementation{Name: "stdio-" + name}, nil)
+		getServer = s.getSharedServer
+		s.wg.Add(1)
+		go s.superviseShared()
+	}
+
+	handler := mcp.NewStreamableHTTPHandler(getServer, &mcp.StreamableHTTPOptions{
+		SessionTimeout: cfg.SessionIdleTimeout,
+	})
+	s.httpServer = &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if subtle.ConstantTimeCompare([]byte(r.URL.Path), []byte(path)) != 1 {
+				http.NotFound(w, r)
+				return
+			}
+			handler.ServeHTTP(w, r)
+		}),
+		ReadHeaderTimeout: 120 * time.Second,
+	}
+	go func() {
+		// The URL is not logged since it contains the token of the server.
+		s.logger.Info("serving stdio MCP server", slog.String("address", listener.Addr().String()))
+		if serveErr := s.httpServer.Serve(listener); serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+			s.logger.Error("stdio MCP server error", slog.String("error", serveErr.Error()))
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		_ = s.Close()
+	}()
+	return s, nil
+}
+
+// Ready returns a channel that is closed once the first attempt to start the shared process completes, whether it
+// succeeded or not. In the per-session mode, the processes are started on demand, so it is closed right away.
+func (s *Server) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Available returns true if the server accepts new MCP sessions, which is when the shared process is running.
+// In the per-session mode, the processes are started on demand, so it always returns true.
+func (s *Server) Available() bool {
+	return s.cfg.PerSession || s.current.Load() != nil
+}
+
+// URL returns the Streamable HTTP endpoint of the server. It contains the token of the server, so it must not be
+// disclosed to the processes that are not allowed to run the command of the server.
+func (s *Server) URL() string {
+	return s.url
+}
+
+// Close stops the server and terminates all its processes.
+func (s *Server) Close() error {
+	s.cancel()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := s.httpServer.Shutdown(shutdownCtx)
+	s.wg.Wait()
+	return err
+}
+
+// superviseShared runs the shared process and restarts it when it exits, until the server is closed.
+// The ready channel is closed once the first attempt to start the process completes.
+func (s *Server) superviseShared() {
+	defer s.wg.Done()
+	var registered *features
+	backoff := minRestartBackoff
+	for attempt := 0; ; attempt++ {
+		startedAt := time.Now()
+		p, err := startProcess(s.ctx, s.logger, s.name, &s.cfg)
+		if err == nil {
+			var current *features
+			if current, err = proxyFeatures(s.ctx, p.session, s.shared, s.sharedSession, registered); err != nil {
+				p.close()
+				err = fmt.Errorf("failed to list the features of the stdio MCP server: %w", err)
+			} else {
+				registered = current
+				s.current.Store(p)
+			}
+		}
+		if attempt == 0 {
+			close(s.ready)
+		}
+
+		if err != nil {
+			s.logger.Error("failed to start stdio MCP server", slog.String("error", err.Error()))
+		} else {
+			select {
+			case <-p.done:
+			case <-s.ctx.Done():
+			}
+			s.current.Store(nil)
+			p.close()
+			if time.Since(startedAt) >= restartBackoffReset {
+				backoff = minRestartBackoff
+			}
+		}
+
+		if s.ctx.Err() != nil {
+			return
+		}
+		s.logger.Warn("restarting stdio MCP server", slog.String("backoff", backoff.String()))
+		select {
+		case <-time.After(backoff):
+		case <-s.ctx.Done():
+			return
+		}
+		backoff = min(2*backoff, maxRestartBackoff)
+	}
+}
+
+// getSharedServer returns the proxy server of the shared process for a new MCP session. It returns nil, which makes
+// the handler reject the request, while the process is not running.
+func (s *Server) getSharedServer(*http.Request) *mcp.Server {
+	if !s.Available() {
+		s.logger.Warn("rejecting MCP session, the stdio MCP server process is not running")
+		return nil
+	}
+	return s.shared
+}
+
+// sharedSession returns the client session of the shared process.
+func (s *Server) sharedSession() (*mcp.ClientSession, error) {
+	p := s.current.Load()
+	if p == nil {
+		return nil, errNotRunning
+	}
+	return p.getSession()
+}
+
+// newSessionServer starts a new process for a new MCP session and returns a proxy server dedicated to it.
+// It returns nil, which makes the handler reject the request, if the process cannot be started.
+func (s *Server) newSessionServer(r *http.Request) *mcp.Server {
+	s.mu.Lock()
+	if s.cfg.MaxProcesses > 0 && len(s.sessions)+s.starting >= s.cfg.MaxProcesses {
+		s.mu.Unlock()
+		s.logger.Warn("rejecting MCP session, the maximum number of stdio MCP server processes is reached",
+			slog.Int("max_processes", s.cfg.MaxProcesses))
+		return nil
+	}
+	s.starting++
+	s.mu.Unlock()
+
+	var server *mcp.Server
+	sp := &sessionProcess{}
+	p, err := startProcess(r.Context(), s.logger, s.name, &s.cfg)
+	if err == nil {
+		sp.process = p
+		server = mcp.NewServer(&mcp.Implementation{Name: "stdio-" + s.name}, &mcp.ServerOptions{
+			// Sessions may end before the reaper sees them, so the attachment is recorded as soon as it happens.
+			InitializedHandler: func(context.Context, *mcp.InitializedRequest) {
+				s.mu.Lock()
+				defer s.mu.Unlock()
+				sp.attached = true
+			},
+		})
+		if _, err = proxyFeatures(r.Context(), p.session, server, p.getSession, nil); err != nil {
+			err = fmt.Errorf("failed to list the features of the stdio MCP server: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.starting--
+	if err == nil && s.ctx.Err() != nil {
+		err = errors.New("server is closed")
+	}
+	if err != nil {
+		if p != nil {
+			go p.close()
+		}
+		s.logger.Error("failed to start stdio MCP server for a new session", slog.String("error", err.Error()))
+		return nil
+	}
+	sp.startedAt = time.Now()
+	s.sessions[server] = sp
+	return server
+}
+
+// reapSessions periodically terminates the per-session processes whose session has ended, until the server is closed.
+func (s *Server) reapSessions() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(reapInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.reap(time.Now(), false)
+		case <-s.ctx.Done():
+			s.reap(time.Now(), true)
+			return
+		}
+	}
+}
+
+// reap terminates the per-session processes whose MCP session has ended, or that had no MCP session attached within
+// the grace period, and closes the MCP sessions whose process has exited. If all is true, all the processes are terminated.
+func (s *Server) reap(now time.Time, all bool) {
+	var terminated []*process
+	s.mu.Lock()
+	for server, sp := range s.sessions {
+		var sessions []*mcp.ServerSession
+		for ss := range server.Sessions() {
+			sessions = append(sessions, ss)
+		}
+		if len(sessions) > 0 {
+			sp.attached = true
+		}
+		exited := sp.process.exited()
+		if !all && !exited && (len(sessions) > 0 || (!sp.attached && now.Sub(sp.startedAt) < sessionStartGracePeriod)) {
+			continue
+		}
+		for _, ss := range sessions {
+			_ = ss.Close()
+		}
+		delete(s.sessions, server)
+		terminated = append(terminated, sp.process)
+	}
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, p := range terminated {
+		wg.Go(p.close)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,256 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpstdio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const runMCPTestServer = "__RUN_MCP_TEST_SERVER__"
+
+func TestMain(m *testing.M) {
+	// If the runMCPTestServer variable is set, run the mcp test server
+	// instead of running tests (aka the fork and exec trick).
+	if os.Getenv(runMCPTestServer) == "true" {
+		_ = os.Unsetenv(runMCPTestServer)
+		runTestStdioServer()
+		return
+	}
+
+	os.Exit(m.Run())
+}
+
+func testConfig(t *testing.T) Config {
+	cmd, err := os.Executable()
+	require.NoError(t, err)
+	return Config{
+		Command: cmd,
+		Env:     append(os.Environ(), runMCPTestServer+"=true"),
+	}
+}
+
+func connect(t *testing.T, url string) *mcp.ClientSession {
+	client := mcp.NewClient(&mcp.Implementation{Name: t.Name()}, nil)
+	cs, err := client.Connect(t.Context(), &mcp.StreamableClientTransport{Endpoint: url}, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cs.Close() })
+	return cs
+}
+
+func callTool(ctx context.Context, cs *mcp.ClientSession, name string) (string, error) {
+	res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: map[string]any{"text": "hello"}})
+	if err != nil {
+		return "", err
+	}
+	if res.IsError || len(res.Content) != 1 {
+		return "", fmt.Errorf("unexpected result: %+v", res)
+	}
+	return res.Content[0].(*mcp.TextContent).Text, nil
+}
+
+func TestServer_Shared(t *testing.T) {
+	defer func(prev time.Duration) { minRestartBackoff = prev }(minRestartBackoff)
+	minRestartBackoff = 10 * time.Millisecond
+
+	s, err := Start(t.Context(), slog.New(slog.DiscardHandler), "test", testConfig(t))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	<-s.Ready()
+
+	cs := connect(t, s.URL())
+	out, err := callTool(t.Context(), cs, "echo")
+	require.NoError(t, err)
+	require.Equal(t, "hello", out)
+
+	// The requests without the token of the server are rejected.
+	u, err := url.Parse(s.URL())
+	require.NoError(t, err)
+	require.Regexp(t, `^/[A-Z2-7]{26}/mcp$`, u.Path)
+	for _, path := range []string{"/mcp", "/", "/" + strings.Repeat("A", 26) + "/mcp"} {
+		resp, err := http.Post("http://"+u.Host+path, "application/json", strings.NewReader(`{}`))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+	}
+
+	pid, err := callTool(t.Context(), cs, "pid")
+	require.NoError(t, err)
+	// The same process serves all the sessions.
+	otherPID, err := callTool(t.Context(), connect(t, s.URL()), "pid")
+	require.NoError(t, err)
+	require.Equal(t, pid, otherPID)
+
+	// Crash the process, which must be restarted.
+	_, _ = callTool(t.Context(), cs, "exit")
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		newPID, err := callTool(t.Context(), cs, "pid")
+		require.NoError(c, err)
+		require.NotEqual(c, pid, newPID)
+	}, 10*time.Second, 50*time.Millisecond)
+}
+
+func TestServer_PerSession(t *testing.T) {
+	defer func(prev time.Duration) { reapInterval = prev }(reapInterval)
+	reapInterval = 10 * time.Millisecond
+
+	cfg := testConfig(t)
+	cfg.PerSession = true
+	cfg.MaxProcesses = 2
+	s, err := Start(t.Context(), slog.New(slog.DiscardHandler), "test", cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	cs1 := connect(t, s.URL())
+	pid1, err := callTool(t.Context(), cs1, "pid")
+	require.NoError(t, err)
+	cs2 := connect(t, s.URL())
+	pid2, err := callTool(t.Context(), cs2, "pid")
+	require.NoError(t, err)
+	require.NotEqual(t, pid1, pid2)
+
+	// The maximum number of processes is reached.
+	client := mcp.NewClient(&mcp.Implementation{Name: t.Name()}, nil)
+	_, err = client.Connect(t.Context(), &mcp.StreamableClientTransport{Endpoint: s.URL()}, nil)
+	require.Error(t, err)
+
+	// Closing a session terminates its process, which makes room for a new one.
+	require.NoError(t, cs1.Close())
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.sessions) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	pid3, err := callTool(t.Context(), connect(t, s.URL()), "pid")
+	require.NoError(t, err)
+	require.NotEqual(t, pid2, pid3)
+
+	// Closing the server terminates all the processes.
+	require.NoError(t, s.Close())
+	require.Empty(t, s.sessions)
+}
+
+func TestStart_Errors(t *testing.T) {
+	_, err := Start(t.Context(), slog.New(slog.DiscardHandler), "test", Config{})
+	require.ErrorContains(t, err, "command is required")
+
+	// A shared server whose command cannot be started keeps retrying in the background.
+	defer func(prev time.Duration) { minRestartBackoff = prev }(minRestartBackoff)
+	minRestartBackoff = time.Hour
+	s, err := Start(t.Context(), slog.New(slog.DiscardHandler), "test", Config{Command: "/non/existent/command"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	<-s.Ready()
+	_, err = s.sharedSession()
+	require.ErrorIs(t, err, errNotRunning)
+	// The new sessions are rejected while the process is not running.
+	client := mcp.NewClient(&mcp.Implementation{Name: t.Name()}, nil)
+	_, err = client.Connect(t.Context(), &mcp.StreamableClientTransport{Endpoint: s.URL()}, nil)
+	require.Error(t, err)
+}
+
+func TestStartProcess_Stderr(t *testing.T) {
+	var buf lockedBuffer
+	cfg := testConfig(t)
+	p, err := startProcess(t.Context(), slog.New(slog.NewTextHandler(&buf, nil)), "test", &cfg)
+	require.NoError(t, err)
+	t.Cleanup(p.close)
+
+	_, _ = callTool(t.Context(), p.session, "exit")
+	<-p.done
+	// The stderr of the process is logged with its pid.
+	require.Contains(t, buf.String(), fmt.Sprintf(`msg="stdio MCP server output" pid=%d stderr=exiting`, p.cmd.Process.Pid))
+}
+
+func TestLogWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := &logWriter{logger: slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))}
+
+	_, err := w.Write([]byte("first line\nsecond "))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("line\r\n\npartial"))
+	require.NoError(t, err)
+	w.flush()
+	require.Equal(t, `level=INFO msg="stdio MCP server output" stderr="first line"
+level=INFO msg="stdio MCP server output" stderr="second line"
+level=INFO msg="stdio MCP server output" stderr=partial
+`, buf.String())
+}
+
+// runTestStdioServer runs a simple MCP stdio server.
+// This method will be run in a subprocess via TestMain.
+func runTestStdioServer() {
+	type args struct {
+		Text string `json:"text"`
+	}
+	textResult := func(text string) *mcp.CallToolResult {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}
+	}
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-stdio"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "echo", Description: "echo tool"},
+		func(_ context.Context, _ *mcp.CallToolRequest, args args) (*mcp.CallToolResult, any, error) {
+			return textResult(args.Text), nil, nil
+		})
+	mcp.AddTool(server, &mcp.Tool{Name: "pid", Description: "returns the process ID"},
+		func(context.Context, *mcp.CallToolRequest, args) (*mcp.CallToolResult, any, error) {
+			return textResult(strconv.Itoa(os.Getpid())), nil, nil
+		})
+	mcp.AddTool(server, &mcp.Tool{Name: "limits", Description: "returns the resource limits of the process"},
+		func(context.Context, *mcp.CallToolRequest, args) (*mcp.CallToolResult, any, error) {
+			limits, err := os.ReadFile("/proc/self/limits")
+			if err != nil {
+				return nil, nil, err
+			}
+			return textResult(string(limits)), nil, nil
+		})
+	mcp.AddTool(server, &mcp.Tool{Name: "exit", Description: "exits the process"},
+		func(context.Context, *mcp.CallToolRequest, args) (*mcp.CallToolResult, any, error) {
+			_, _ = fmt.Fprintln(os.Stderr, "exiting")
+			os.Exit(1)
+			return nil, nil, nil
+		})
+
+	_ = server.Run(context.Background(), &mcp.StdioTransport{})
+}
+
+// lockedBuffer is a bytes.Buffer that is safe for concurrent use.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
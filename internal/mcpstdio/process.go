@@ -0,0 +1,201 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpstdio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// terminateTimeout is how long a process is given to exit after its stdin is closed before it is killed.
+const terminateTimeout = 5 * time.Second
+
+// initializeTimeout is how long a process is given to complete the MCP initialization after it is started.
+const initializeTimeout = 30 * time.Second
+
+// maxLogLineLength is the maximum length of a single log line captured from the stderr of a process.
+const maxLogLineLength = 4096
+
+// errNotRunning is returned when a request is made while the stdio MCP server process is not running.
+var errNotRunning = errors.New("stdio MCP server is not running")
+
+// process is a running stdio MCP server process with an initialized MCP client session.
+type process struct {
+	cmd     *exec.Cmd
+	logger  *slog.Logger
+	session *mcp.ClientSession
+	// done is closed when the process exits.
+	done chan struct{}
+	// closers are the parent ends of the stdio pipes, closed if the client session could not be established.
+	closers []io.Closer
+}
+
+// startProcess starts the configured command and initializes an MCP client session over its stdio.
+// The stderr of the process is captured into the logger.
+func startProcess(ctx context.Context, logger *slog.Logger, name string, cfg *Config) (*process, error) {
+	cmd := exec.Command(cfg.Command, cfg.Args...) // nolint: gosec
+	cmd.Env = cfg.Env
+	if err := configureCommand(cmd, cfg); err != nil {
+		return nil, fmt.Errorf("failed to apply resource limits: %w", err)
+	}
+
+	// Use explicit pipes instead of cmd.StdinPipe and cmd.StdoutPipe, so that waiting for the process
+	// to exit doesn't race with the MCP client reading from its stdout.
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		_ = stdinR.Close()
+		_ = stdinW.Close()
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	// The stderr is also an explicit pipe, so that it is only read once the logger of the process, which
+	// depends on its pid, is known.
+	stderrR, stderrW, err := os.Pipe()
+	if err != nil {
+		_ = stdinR.Close()
+		_ = stdinW.Close()
+		_ = stdoutR.Close()
+		_ = stdoutW.Close()
+		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdinR, stdoutW, stderrW
+
+	err = cmd.Start()
+	// The child ends of the pipes are not used by this process anymore.
+	_ = stdinR.Close()
+	_ = stdoutW.Close()
+	_ = stderrW.Close()
+	if err != nil {
+		_ = stdinW.Close()
+		_ = stdoutR.Close()
+		_ = stderrR.Close()
+		return nil, fmt.Errorf("failed to start %q: %w", cfg.Command, err)
+	}
+
+	p := &process{
+		cmd:     cmd,
+		logger:  logger.With(slog.Int("pid", cmd.Process.Pid)),
+		done:    make(chan struct{}),
+		closers: []io.Closer{stdinW, stdoutR},
+	}
+	stderrDone := make(chan struct{})
+	go func() {
+		defer close(stderrDone)
+		stderr := &logWriter{logger: p.logger}
+		_, _ = io.Copy(stderr, stderrR)
+		stderr.flush()
+		_ = stderrR.Close()
+	}()
+	go func() {
+		waitErr := cmd.Wait()
+		<-stderrDone
+		p.logger.Info("stdio MCP server process exited", slog.Any("error", waitErr))
+		close(p.done)
+	}()
+	p.logger.Info("started stdio MCP server process", slog.String("command", cfg.Command), slog.Any("args", cfg.Args))
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "envoy-ai-gateway-stdio-" + name}, nil)
+	connectCtx, cancel := context.WithTimeout(ctx, initializeTimeout)
+	defer cancel()
+	cs, err := client.Connect(connectCtx, &mcp.IOTransport{Reader: stdoutR, Writer: stdinW}, nil)
+	if err != nil {
+		p.close()
+		return nil, fmt.Errorf("failed to initialize MCP session with %q: %w", cfg.Command, err)
+	}
+	p.session = cs
+	return p, nil
+}
+
+// exited returns true if the process has exited.
+func (p *process) exited() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// getSession returns the client session of the process, or an error if the process has exited.
+func (p *process) getSession() (*mcp.ClientSession, error) {
+	if p.exited() {
+		return nil, errNotRunning
+	}
+	return p.session, nil
+}
+
+// close gracefully terminates the process by closing its stdin, and kills it if it doesn't exit in time.
+func (p *process) close() {
+	if p.session != nil {
+		_ = p.session.Close()
+	} else {
+		for _, c := range p.closers {
+			_ = c.Close()
+		}
+	}
+	select {
+	case <-p.done:
+	case <-time.After(terminateTimeout):
+		p.logger.Warn("stdio MCP server process did not exit in time, killing it")
+		killProcess(p.cmd)
+		<-p.done
+	}
+}
+
+// logWriter is an io.Writer that logs each line written to it. It is used to capture the stderr of a process.
+//
+// It is not safe for concurrent use, which is fine since the stderr of a process is copied from a single goroutine.
+type logWriter struct {
+	logger *slog.Logger
+	buf    []byte
+}
+
+// Write implements [io.Writer.Write].
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.log(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	if len(w.buf) >= maxLogLineLength {
+		w.flush()
+	}
+	return len(p), nil
+}
+
+// flush logs the remaining buffered output, if any.
+func (w *logWriter) flush() {
+	if len(w.buf) > 0 {
+		w.log(w.buf)
+		w.buf = w.buf[:0]
+	}
+}
+
+func (w *logWriter) log(line []byte) {
+	line = bytes.TrimRight(line, "\r")
+	if len(line) > maxLogLineLength {
+		line = line[:maxLogLineLength]
+	}
+	if len(line) > 0 {
+		w.logger.Info("stdio MCP server output", slog.String("stderr", string(line)))
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+//go:build linux
+
+package mcpstdio
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// resourceLimitsEnv is the environment variable that makes the current executable set the resource limits of its
+// process and execute the stdio MCP server command in place of itself. Its value is "<memory>:<cpu seconds>:<path>".
+const resourceLimitsEnv = "__ENVOY_AI_GATEWAY_MCP_STDIO_LIMITS__"
+
+func init() {
+	if v, ok := os.LookupEnv(resourceLimitsEnv); ok {
+		if err := execWithResourceLimits(v); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to run the stdio MCP server with resource limits: %v\n", err)
+			os.Exit(127)
+		}
+	}
+}
+
+// configureCommand sets the process attributes of the command.
+//
+// When resource limits are configured, the current executable is started instead of the command, with the
+// resourceLimitsEnv variable set, so that the limits are set before the command is executed. Setting them with
+// prlimit after the process is started would let the command run without limits for a while.
+func configureCommand(cmd *exec.Cmd, cfg *Config) error {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		// Run the server in its own process group so that the whole process tree can be killed.
+		Setpgid: true,
+		// Don't leave the server running if the gateway process dies.
+		Pdeathsig: syscall.SIGKILL,
+	}
+	if cfg.MemoryLimitBytes == 0 && cfg.CPUTimeLimit == 0 {
+		return nil
+	}
+	if cmd.Err != nil {
+		// The command cannot be found, which is reported when it is started.
+		return nil
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the current executable: %w", err)
+	}
+	var cpuSeconds uint64
+	if cfg.CPUTimeLimit > 0 {
+		cpuSeconds = uint64(math.Ceil(cfg.CPUTimeLimit.Seconds()))
+	}
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = append(env, fmt.Sprintf("%s=%d:%d:%s", resourceLimitsEnv, cfg.MemoryLimitBytes, cpuSeconds, cmd.Path))
+	cmd.Path = self
+	return nil
+}
+
+// execWithResourceLimits sets the resource limits encoded in the given resourceLimitsEnv value on the current
+// process, and executes the command in place of it. It only returns on errors.
+func execWithResourceLimits(value string) error {
+	parts := strings.SplitN(value, ":", 3)
+	if len(parts) != 3 {
+		return fmt.Errorf("invalid %s value %q", resourceLimitsEnv, value)
+	}
+	memory, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid memory limit: %w", err)
+	}
+	cpuSeconds, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid CPU time limit: %w", err)
+	}
+	if memory > 0 {
+		if err = unix.Setrlimit(unix.RLIMIT_AS, &unix.Rlimit{Cur: memory, Max: memory}); err != nil {
+			return fmt.Errorf("failed to set the memory limit: %w", err)
+		}
+	}
+	if cpuSeconds > 0 {
+		if err = unix.Setrlimit(unix.RLIMIT_CPU, &unix.Rlimit{Cur: cpuSeconds, Max: cpuSeconds}); err != nil {
+			return fmt.Errorf("failed to set the CPU time limit: %w", err)
+		}
+	}
+	env := slices.DeleteFunc(os.Environ(), func(kv string) bool { return strings.HasPrefix(kv, resourceLimitsEnv+"=") })
+	return syscall.Exec(parts[2], os.Args, env)
+}
+
+// killProcess kills the process group of the command.
+func killProcess(cmd *exec.Cmd) {
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,40 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+//go:build linux
+
+package mcpstdio
+
+import (
+	"log/slog"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_ResourceLimits(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.MemoryLimitBytes = 8 << 30
+	cfg.CPUTimeLimit = 90 * time.Second
+	s, err := Start(t.Context(), slog.New(slog.DiscardHandler), "test", cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	<-s.Ready()
+	require.True(t, s.Available())
+
+	// The limits are set before the command is executed, and the command doesn't see the wrapper variable.
+	limits, err := callTool(t.Context(), connect(t, s.URL()), "limits")
+	require.NoError(t, err)
+	require.Regexp(t, regexp.MustCompile(`Max cpu time\s+90\s+90\s+seconds`), limits)
+	require.Regexp(t, regexp.MustCompile(`Max address space\s+8589934592\s+8589934592\s+bytes`), limits)
+}
+
+func TestExecWithResourceLimits_Errors(t *testing.T) {
+	for _, value := range []string{"", "1:2", "x:0:/bin/true", "0:x:/bin/true"} {
+		require.Error(t, execWithResourceLimits(value), value)
+	}
+}
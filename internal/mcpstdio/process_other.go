@@ -0,0 +1,26 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+//go:build !linux
+
+package mcpstdio
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// configureCommand sets the process attributes of the command.
+func configureCommand(_ *exec.Cmd, cfg *Config) error {
+	if cfg.MemoryLimitBytes > 0 || cfg.CPUTimeLimit > 0 {
+		return errors.New("resource limits are only supported on Linux")
+	}
+	return nil
+}
+
+// killProcess kills the process of the command.
+func killProcess(cmd *exec.Cmd) {
+	_ = cmd.Process.Kill()
+}
@@ -0,0 +1,128 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package mcpstdio
+
+import (
+	"context"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// sessionFunc returns the client session of the stdio MCP server the requests are forwarded to.
+type sessionFunc func() (*mcp.ClientSession, error)
+
+// features holds the names of the features registered in a proxy server. It is used to remove the features
+// that are no longer exposed when the stdio MCP server is restarted.
+type features struct {
+	tools             []string
+	resources         []string
+	resourceTemplates []string
+	prompts           []string
+}
+
+// proxyFeatures registers in the server the tools, resources and prompts exposed by the given client session.
+// The registered handlers forward the requests to the session returned by getSession at the time of the request.
+//
+// The features registered by a previous call, passed as prev, that are no longer exposed are removed from the server.
+func proxyFeatures(ctx context.Context, cs *mcp.ClientSession, server *mcp.Server, getSession sessionFunc, prev *features) (*features, error) {
+	current := &features{}
+	capabilities := cs.InitializeResult().Capabilities
+
+	if capabilities.Tools != nil {
+		for tool, err := range cs.Tools(ctx, nil) {
+			if err != nil {
+				return nil, err
+			}
+			current.tools = append(current.tools, tool.Name)
+			server.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				session, err := getSession()
+				if err != nil {
+					return nil, err
+				}
+				return session.CallTool(ctx, &mcp.CallToolParams{
+					Meta:      req.Params.Meta,
+					Name:      req.Params.Name,
+					Arguments: req.Params.Arguments,
+				})
+			})
+		}
+	}
+
+	if capabilities.Resources != nil {
+		readResource := func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			session, err := getSession()
+			if err != nil {
+				return nil, err
+			}
+			return session.ReadResource(ctx, &mcp.ReadResourceParams{
+				Meta: req.Params.Meta,
+				URI:  req.Params.URI,
+			})
+		}
+		for resource, err := range cs.Resources(ctx, nil) {
+			if err != nil {
+				return nil, err
+			}
+			current.resources = append(current.resources, resource.URI)
+			server.AddResource(resource, readResource)
+		}
+		for template, err := range cs.ResourceTemplates(ctx, nil) {
+			if err != nil {
+				return nil, err
+			}
+			current.resourceTemplates = append(current.resourceTemplates, template.URITemplate)
+			server.AddResourceTemplate(template, readResource)
+		}
+	}
+
+	if capabilities.Prompts != nil {
+		for prompt, err := range cs.Prompts(ctx, nil) {
+			if err != nil {
+				return nil, err
+			}
+			current.prompts = append(current.prompts, prompt.Name)
+			server.AddPrompt(prompt, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				session, err := getSession()
+				if err != nil {
+					return nil, err
+				}
+				return session.GetPrompt(ctx, &mcp.GetPromptParams{
+					Meta:      req.Params.Meta,
+					Name:      req.Params.Name,
+					Arguments: req.Params.Arguments,
+				})
+			})
+		}
+	}
+
+	if prev != nil {
+		if removed := removedNames(prev.tools, current.tools); len(removed) > 0 {
+			server.RemoveTools(removed...)
+		}
+		if removed := removedNames(prev.resources, current.resources); len(removed) > 0 {
+			server.RemoveResources(removed...)
+		}
+		if removed := removedNames(prev.resourceTemplates, current.resourceTemplates); len(removed) > 0 {
+			server.RemoveResourceTemplates(removed...)
+		}
+		if removed := removedNames(prev.prompts, current.prompts); len(removed) > 0 {
+			server.RemovePrompts(removed...)
+		}
+	}
+	return current, nil
+}
+
+// removedNames returns the names in prev that are not in current.
+func removedNames(prev, current []string) []string {
+	var removed []string
+	for _, name := range prev {
+		if !slices.Contains(current, name) {
+			removed = append(removed, name)
+		}
+	}
+	return removed
+}
@@ -0,0 +1,90 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package metrics
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// nolint: godot
+const (
+	// API Key Pool Requests is a counter metric that records the responses of the backends to the requests sent with
+	// a key of an API key pool.
+	//
+	// Dimensions:
+	// - api_key.fingerprint
+	// - http.response.status_code
+	apiKeyPoolRequests = "api_key_pool.requests"
+	// API Key Pool Ejections is a counter metric that records the keys of the API key pools ejected after being
+	// rejected or rate limited by the backend.
+	//
+	// Dimensions:
+	// - api_key.fingerprint
+	// - api_key_pool.ejection.reason
+	apiKeyPoolEjections = "api_key_pool.ejections"
+	// API key fingerprint attribute, which identifies a key without revealing it.
+	apiKeyAttributeFingerprint = "api_key.fingerprint"
+	// HTTP response status code attribute.
+	apiKeyPoolAttributeStatusCode = "http.response.status_code"
+	// API key ejection reason attribute. See APIKeyEjectionReason for all reasons.
+	apiKeyPoolAttributeEjectionReason = "api_key_pool.ejection.reason"
+)
+
+// APIKeyEjectionReason is the reason a key is ejected from an API key pool.
+type APIKeyEjectionReason string
+
+const (
+	// APIKeyEjectionUnauthorized indicates that the backend rejected the key with a 401 response.
+	APIKeyEjectionUnauthorized APIKeyEjectionReason = "unauthorized"
+	// APIKeyEjectionRateLimited indicates that the backend rate limited the key with a 429 response.
+	APIKeyEjectionRateLimited APIKeyEjectionReason = "rate_limited"
+)
+
+// APIKeyPoolMetrics is the interface for the usage metrics of the keys of the API key pools.
+type APIKeyPoolMetrics interface {
+	// RecordResponse records the status code of a response to a request sent with the key of the given fingerprint.
+	RecordResponse(ctx context.Context, fingerprint string, statusCode int)
+	// RecordEjection records the ejection of the key of the given fingerprint.
+	RecordEjection(ctx context.Context, fingerprint string, reason APIKeyEjectionReason)
+}
+
+type apiKeyPool struct {
+	requests  metric.Float64Counter
+	ejections metric.Float64Counter
+}
+
+// NewAPIKeyPool creates a new API key pool metrics instance.
+func NewAPIKeyPool(meter metric.Meter) APIKeyPoolMetrics {
+	return &apiKeyPool{
+		requests: mustRegisterCounter(meter,
+			apiKeyPoolRequests,
+			metric.WithDescription("Total number of responses to the requests sent with the keys of the API key pools"),
+		),
+		ejections: mustRegisterCounter(meter,
+			apiKeyPoolEjections,
+			metric.WithDescription("Total number of ejections of the keys of the API key pools"),
+		),
+	}
+}
+
+// RecordResponse implements [APIKeyPoolMetrics.RecordResponse].
+func (a *apiKeyPool) RecordResponse(ctx context.Context, fingerprint string, statusCode int) {
+	a.requests.Add(ctx, 1, metric.WithAttributes(
+		attribute.String(apiKeyAttributeFingerprint, fingerprint),
+		attribute.Int(apiKeyPoolAttributeStatusCode, statusCode),
+	))
+}
+
+// RecordEjection implements [APIKeyPoolMetrics.RecordEjection].
+func (a *apiKeyPool) RecordEjection(ctx context.Context, fingerprint string, reason APIKeyEjectionReason) {
+	a.ejections.Add(ctx, 1, metric.WithAttributes(
+		attribute.String(apiKeyAttributeFingerprint, fingerprint),
+		attribute.String(apiKeyPoolAttributeEjectionReason, string(reason)),
+	))
+}
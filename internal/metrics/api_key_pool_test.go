@@ -0,0 +1,40 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric"
+
+	"github.com/envoyproxy/ai-gateway/internal/testing/testotel"
+)
+
+func TestAPIKeyPool(t *testing.T) {
+	mr := metric.NewManualReader()
+	meter := metric.NewMeterProvider(metric.WithReader(mr)).Meter("test")
+	m := NewAPIKeyPool(meter)
+
+	m.RecordResponse(t.Context(), "sha256:0123456789ab", 200)
+	m.RecordResponse(t.Context(), "sha256:0123456789ab", 200)
+	m.RecordResponse(t.Context(), "sha256:0123456789ab", 429)
+	m.RecordEjection(t.Context(), "sha256:0123456789ab", APIKeyEjectionRateLimited)
+
+	require.Equal(t, float64(2), testotel.GetCounterValue(t, mr, apiKeyPoolRequests, attribute.NewSet(
+		attribute.String(apiKeyAttributeFingerprint, "sha256:0123456789ab"),
+		attribute.Int(apiKeyPoolAttributeStatusCode, 200),
+	)))
+	require.Equal(t, float64(1), testotel.GetCounterValue(t, mr, apiKeyPoolRequests, attribute.NewSet(
+		attribute.String(apiKeyAttributeFingerprint, "sha256:0123456789ab"),
+		attribute.Int(apiKeyPoolAttributeStatusCode, 429),
+	)))
+	require.Equal(t, float64(1), testotel.GetCounterValue(t, mr, apiKeyPoolEjections, attribute.NewSet(
+		attribute.String(apiKeyAttributeFingerprint, "sha256:0123456789ab"),
+		attribute.String(apiKeyPoolAttributeEjectionReason, string(APIKeyEjectionRateLimited)),
+	)))
+}
@@ -45,6 +45,10 @@ const (
 	genaiProviderGCPAnthropic = "gcp.anthropic"
 	genaiProviderAnthropic    = "anthropic"
 	genaiProviderCohere       = "cohere"
+	genaiProviderOCIGenAI     = "oci.generative_ai"
+	genaiProviderDashScope    = "dashscope"
+	genaiProviderOllama       = "ollama"
+	genaiProviderHuggingFace  = "huggingface"
 
 	genaiTokenTypeInput  = "input"
 	genaiTokenTypeOutput = "output"
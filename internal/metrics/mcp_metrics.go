@@ -42,6 +42,18 @@ const (
 	mcpCapabilitiesNegotiated = "mcp.capabilities.negotiated"
 	// MCP Progress Notifications is a counter metric that records the total number of MCP progress notifications sent.
 	mpcProgressNotifications = "mcp.progress.notifications"
+	// MCP Rate Limited Requests is a counter metric that records the total number of MCP requests rejected by rate limits.
+	//
+	// Dimensions:
+	// - mcp.method.name
+	// - mcp.rate_limit.type
+	mcpRateLimitedRequests = "mcp.rate_limited.requests"
+	// MCP Security Events is a counter metric that records the total number of MCP messages rejected because
+	// they carry a value issued by the gateway that failed verification, such as a tampered request ID.
+	//
+	// Dimensions:
+	// - mcp.security.event.type
+	mcpSecurityEvents = "mcp.security.events"
 	// MCP JSON-RPC method name attribute.
 	mcpAttributeMethodName = "mcp.method.name"
 	// MCP status attribute, which is either "success" or "error". See mcpStatusType for all statuses.
@@ -54,6 +66,10 @@ const (
 	mcpAttributeCapabilitySide = "capability.side"
 	// MCP backend attribute, which identifies the upstream MCP backend that handled the request.
 	mcpAttributeBackend = "mcp.backend"
+	// MCP rate limit type attribute, which is either "rate" or "concurrency". See MCPRateLimitType for all types.
+	mcpAttributeRateLimitType = "mcp.rate_limit.type"
+	// MCP security event type attribute. See MCPSecurityEventType for all types.
+	mcpAttributeSecurityEventType = "mcp.security.event.type"
 )
 
 // MCPErrorType defines the type of error that occurred during an MCP request.
@@ -74,6 +90,43 @@ const (
 	MCPErrorInvalidSessionID MCPErrorType = "invalid_session_id"
 	// MCPErrorInternal indicates that an internal error occurred.
 	MCPErrorInternal MCPErrorType = "internal_error"
+	// MCPErrorRateLimited indicates that the request was rejected by a rate or concurrency limit.
+	MCPErrorRateLimited MCPErrorType = "rate_limited"
+	// MCPErrorInvalidToolArguments indicates that the arguments of a tool call don't conform to the input schema of the tool.
+	MCPErrorInvalidToolArguments MCPErrorType = "invalid_tool_arguments"
+	// MCPErrorInvalidToolResult indicates that the result of a tool call doesn't conform to the output schema of the tool.
+	MCPErrorInvalidToolResult MCPErrorType = "invalid_tool_result"
+	// MCPErrorBackendUnavailable indicates that the request was not sent to the backend because it is ejected as unhealthy.
+	MCPErrorBackendUnavailable MCPErrorType = "backend_unavailable"
+	// MCPErrorServerRequestDenied indicates that a server->client request was denied by the policy of the backend.
+	MCPErrorServerRequestDenied MCPErrorType = "server_request_denied"
+)
+
+// MCPRateLimitType defines the kind of limit that rejected an MCP request.
+type MCPRateLimitType string
+
+const (
+	// MCPRateLimitTypeRate indicates that the request exceeded the allowed number of requests per time unit.
+	MCPRateLimitTypeRate MCPRateLimitType = "rate"
+	// MCPRateLimitTypeConcurrency indicates that the request exceeded the allowed number of in-flight requests.
+	MCPRateLimitTypeConcurrency MCPRateLimitType = "concurrency"
+)
+
+// MCPSecurityEventType defines the kind of verification failure of an MCP message.
+type MCPSecurityEventType string
+
+const (
+	// MCPSecurityEventInvalidRequestID indicates that the ID of a response to a server->client request could not be
+	// verified.
+	MCPSecurityEventInvalidRequestID MCPSecurityEventType = "invalid_request_id"
+	// MCPSecurityEventInvalidProgressToken indicates that the progress token of a notification could not be verified.
+	MCPSecurityEventInvalidProgressToken MCPSecurityEventType = "invalid_progress_token"
+	// MCPSecurityEventUnknownRequestID indicates that a response was sent to a server->client request that is not
+	// awaiting a response, because it was never sent to the session or was already responded to.
+	MCPSecurityEventUnknownRequestID MCPSecurityEventType = "unknown_request_id"
+	// MCPSecurityEventServerRequestDenied indicates that a sampling or elicitation request of a backend was denied by
+	// the policy of the backend.
+	MCPSecurityEventServerRequestDenied MCPSecurityEventType = "server_request_denied"
 )
 
 // MCPStatusType defines the status of an MCP request.
@@ -131,6 +184,10 @@ type MCPMetrics interface {
 	RecordServerCapabilities(ctx context.Context, capabilities *mcpsdk.ServerCapabilities, meta mcpsdk.Params)
 	// RecordProgress records a progress notification sent/received.
 	RecordProgress(ctx context.Context, meta mcpsdk.Params)
+	// RecordRateLimited records a request rejected by a rate or concurrency limit.
+	RecordRateLimited(ctx context.Context, methodName string, limitType MCPRateLimitType, meta mcpsdk.Params)
+	// RecordSecurityEvent records a message rejected because it failed verification.
+	RecordSecurityEvent(ctx context.Context, eventType MCPSecurityEventType, meta mcpsdk.Params)
 }
 
 type mcp struct {
@@ -139,6 +196,8 @@ type mcp struct {
 	initializationDuration        metric.Float64Histogram
 	capabilitiesNegotiated        metric.Float64Counter
 	progressNotifications         metric.Float64Counter
+	rateLimitedRequests           metric.Float64Counter
+	securityEvents                metric.Float64Counter
 	requestHeaderAttributeMapping map[string]string // maps HTTP headers to metric attribute names.
 	defaultAttributes             []attribute.KeyValue
 }
@@ -172,6 +231,16 @@ func NewMCP(meter metric.Meter, requestHeaderAttributeMapping map[string]string)
 			mpcProgressNotifications,
 			metric.WithDescription("Total number of MCP progress notifications sent"),
 		),
+		rateLimitedRequests: mustRegisterCounter(
+			meter,
+			mcpRateLimitedRequests,
+			metric.WithDescription("Total number of MCP requests rejected by rate limits"),
+		),
+		securityEvents: mustRegisterCounter(
+			meter,
+			mcpSecurityEvents,
+			metric.WithDescription("Total number of MCP messages rejected because they failed verification"),
+		),
 	}
 }
 
@@ -183,6 +252,8 @@ func (m *mcp) WithBackend(backend string) MCPMetrics {
 		initializationDuration:        m.initializationDuration,
 		capabilitiesNegotiated:        m.capabilitiesNegotiated,
 		progressNotifications:         m.progressNotifications,
+		rateLimitedRequests:           m.rateLimitedRequests,
+		securityEvents:                m.securityEvents,
 		requestHeaderAttributeMapping: m.requestHeaderAttributeMapping,
 		defaultAttributes: append(
 			slices.Clone(m.defaultAttributes),
@@ -201,6 +272,8 @@ func (m *mcp) WithRequestAttributes(req *http.Request) MCPMetrics {
 		initializationDuration:        m.initializationDuration,
 		capabilitiesNegotiated:        m.capabilitiesNegotiated,
 		progressNotifications:         m.progressNotifications,
+		rateLimitedRequests:           m.rateLimitedRequests,
+		securityEvents:                m.securityEvents,
 		requestHeaderAttributeMapping: m.requestHeaderAttributeMapping,
 	}
 
@@ -265,6 +338,20 @@ func (m *mcp) RecordProgress(ctx context.Context, params mcpsdk.Params) {
 	m.progressNotifications.Add(ctx, 1, m.withDefaultAttributes(params))
 }
 
+// RecordRateLimited implements [MCPMetrics.RecordRateLimited].
+func (m *mcp) RecordRateLimited(ctx context.Context, methodName string, limitType MCPRateLimitType, params mcpsdk.Params) {
+	m.rateLimitedRequests.Add(ctx, 1, m.withDefaultAttributes(params,
+		attribute.Key(mcpAttributeMethodName).String(methodName),
+		attribute.Key(mcpAttributeRateLimitType).String(string(limitType)),
+	))
+}
+
+// RecordSecurityEvent implements [MCPMetrics.RecordSecurityEvent].
+func (m *mcp) RecordSecurityEvent(ctx context.Context, eventType MCPSecurityEventType, params mcpsdk.Params) {
+	m.securityEvents.Add(ctx, 1, m.withDefaultAttributes(params,
+		attribute.Key(mcpAttributeSecurityEventType).String(string(eventType))))
+}
+
 // RecordClientCapabilities implements [MCPMetrics.RecordClientCapabilities].
 func (m *mcp) RecordClientCapabilities(ctx context.Context, capabilities *mcpsdk.ClientCapabilities, params mcpsdk.Params) {
 	if capabilities == nil {
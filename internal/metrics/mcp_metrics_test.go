@@ -205,6 +205,52 @@ func TestRecordProgressNotifications(t *testing.T) {
 	require.Equal(t, float64(2), val)
 }
 
+func TestRecordRateLimited(t *testing.T) {
+	mr := metric.NewManualReader()
+	meter := metric.NewMeterProvider(metric.WithReader(mr)).Meter("test")
+
+	m := NewMCP(meter, nil)
+	require.NotNil(t, m)
+
+	m.WithBackend("test-backend").RecordRateLimited(t.Context(), "tools/call", MCPRateLimitTypeRate, nil)
+	m.WithBackend("test-backend").RecordRateLimited(t.Context(), "tools/call", MCPRateLimitTypeConcurrency, nil)
+	m.WithBackend("test-backend").RecordRateLimited(t.Context(), "tools/call", MCPRateLimitTypeRate, nil)
+
+	val := testotel.GetCounterValue(t, mr, mcpRateLimitedRequests, attribute.NewSet(
+		attribute.String(mcpAttributeBackend, "test-backend"),
+		attribute.Key(mcpAttributeMethodName).String("tools/call"),
+		attribute.Key(mcpAttributeRateLimitType).String(string(MCPRateLimitTypeRate)),
+	))
+	require.Equal(t, float64(2), val)
+	val = testotel.GetCounterValue(t, mr, mcpRateLimitedRequests, attribute.NewSet(
+		attribute.String(mcpAttributeBackend, "test-backend"),
+		attribute.Key(mcpAttributeMethodName).String("tools/call"),
+		attribute.Key(mcpAttributeRateLimitType).String(string(MCPRateLimitTypeConcurrency)),
+	))
+	require.Equal(t, float64(1), val)
+}
+
+func TestRecordSecurityEvent(t *testing.T) {
+	mr := metric.NewManualReader()
+	meter := metric.NewMeterProvider(metric.WithReader(mr)).Meter("test")
+
+	m := NewMCP(meter, nil)
+	require.NotNil(t, m)
+
+	m.RecordSecurityEvent(t.Context(), MCPSecurityEventInvalidProgressToken, nil)
+	m.RecordSecurityEvent(t.Context(), MCPSecurityEventInvalidRequestID, nil)
+	m.RecordSecurityEvent(t.Context(), MCPSecurityEventInvalidRequestID, nil)
+
+	val := testotel.GetCounterValue(t, mr, mcpSecurityEvents, attribute.NewSet(
+		attribute.Key(mcpAttributeSecurityEventType).String(string(MCPSecurityEventInvalidProgressToken)),
+	))
+	require.Equal(t, float64(1), val)
+	val = testotel.GetCounterValue(t, mr, mcpSecurityEvents, attribute.NewSet(
+		attribute.Key(mcpAttributeSecurityEventType).String(string(MCPSecurityEventInvalidRequestID)),
+	))
+	require.Equal(t, float64(2), val)
+}
+
 func TestWithBackend(t *testing.T) {
 	mr := metric.NewManualReader()
 	meter := metric.NewMeterProvider(metric.WithReader(mr)).Meter("test")
@@ -101,6 +101,14 @@ func (b *metricsImpl) SetBackend(backend *filterapi.Backend) {
 		b.backend = genaiProviderAnthropic
 	case filterapi.APISchemaCohere:
 		b.backend = genaiProviderCohere
+	case filterapi.APISchemaOCIGenAI:
+		b.backend = genaiProviderOCIGenAI
+	case filterapi.APISchemaDashScope:
+		b.backend = genaiProviderDashScope
+	case filterapi.APISchemaOllama:
+		b.backend = genaiProviderOllama
+	case filterapi.APISchemaHuggingFaceTGI:
+		b.backend = genaiProviderHuggingFace
 	default:
 		b.backend = backend.Name
 	}
@@ -113,12 +121,15 @@ func (b *metricsImpl) buildBaseAttributes(headers map[string]string) attribute.S
 	origModel := attribute.Key(genaiAttributeOriginalModel).String(b.originalModel)
 	reqModel := attribute.Key(genaiAttributeRequestModel).String(b.requestModel)
 	respModel := attribute.Key(genaiAttributeResponseModel).String(b.responseModel)
+	attrs := []attribute.KeyValue{opt, provider, origModel, reqModel, respModel}
+	if virtualKey, ok := headers[internalapi.VirtualKeyHeader]; ok {
+		attrs = append(attrs, attribute.Key(internalapi.VirtualKeyAttribute).String(virtualKey))
+	}
 	if len(b.requestHeaderAttributeMapping) == 0 {
-		return attribute.NewSet(opt, provider, origModel, reqModel, respModel)
+		return attribute.NewSet(attrs...)
 	}
 
 	// Add header values as attributes based on the header mapping if headers are provided.
-	attrs := []attribute.KeyValue{opt, provider, origModel, reqModel, respModel}
 	for headerName, labelName := range b.requestHeaderAttributeMapping {
 		if headerValue, exists := headers[headerName]; exists {
 			attrs = append(attrs, attribute.Key(labelName).String(headerValue))
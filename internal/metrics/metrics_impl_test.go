@@ -247,6 +247,33 @@ func TestHeaderLabelMapping(t *testing.T) {
 	assert.Equal(t, uint64(1), count)
 }
 
+func TestVirtualKeyAttribute(t *testing.T) {
+	t.Parallel()
+	mr := metric.NewManualReader()
+	meter := metric.NewMeterProvider(metric.WithReader(mr)).Meter("test")
+	pm := NewMetricsFactory(meter, nil, GenAIOperationChat).NewMetrics().(*metricsImpl)
+
+	headers := map[string]string{internalapi.VirtualKeyHeader: "ns/team-a"}
+	pm.SetOriginalModel("test-model")
+	pm.SetRequestModel("test-model")
+	pm.SetResponseModel("test-model")
+	pm.SetBackend(&filterapi.Backend{Schema: filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI}})
+	pm.RecordTokenUsage(t.Context(), TokenUsage{inputTokens: 10, inputTokenSet: true}, headers)
+
+	attrs := attribute.NewSet(
+		attribute.Key(genaiAttributeOperationName).String(string(GenAIOperationChat)),
+		attribute.Key(genaiAttributeProviderName).String(genaiProviderOpenAI),
+		attribute.Key(genaiAttributeOriginalModel).String("test-model"),
+		attribute.Key(genaiAttributeRequestModel).String("test-model"),
+		attribute.Key(genaiAttributeResponseModel).String("test-model"),
+		attribute.Key(genaiAttributeTokenType).String(genaiTokenTypeInput),
+		attribute.Key(internalapi.VirtualKeyAttribute).String("ns/team-a"),
+	)
+	count, sum := getHistogramValues(t, mr, genaiMetricClientTokenUsage, attrs)
+	assert.Equal(t, uint64(1), count)
+	assert.Equal(t, 10.0, sum)
+}
+
 // TestModelNameHeaderKey tests that the model used in metrics is taken from
 // the internalapi.ModelNameHeaderKey when present, which allows backend-specific
 // model overrides to be tracked in metrics.
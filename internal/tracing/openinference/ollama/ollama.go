@@ -0,0 +1,243 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package ollama provides OpenInference semantic conventions hooks for
+// the native Ollama API used by the ExtProc router filter.
+package ollama
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/ollama"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// startOpts sets trace.SpanKindInternal as that's the span kind used in OpenInference.
+var startOpts = []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindInternal)}
+
+// ChatRecorder implements recorders for OpenInference spans of the Ollama chat API.
+type ChatRecorder struct {
+	traceConfig *openinference.TraceConfig
+}
+
+// NewChatRecorderFromEnv creates a tracingapi.OllamaChatRecorder from environment variables
+// using the OpenInference configuration specification.
+func NewChatRecorderFromEnv() tracingapi.OllamaChatRecorder {
+	return NewChatRecorder(nil)
+}
+
+// NewChatRecorder creates a tracingapi.OllamaChatRecorder with the given config using
+// the OpenInference configuration specification.
+//
+// Parameters:
+//   - config: configuration for redaction. Defaults to NewTraceConfigFromEnv().
+func NewChatRecorder(config *openinference.TraceConfig) tracingapi.OllamaChatRecorder {
+	if config == nil {
+		config = openinference.NewTraceConfigFromEnv()
+	}
+	return &ChatRecorder{traceConfig: config}
+}
+
+// StartParams implements the same method as defined in tracingapi.OllamaChatRecorder.
+func (r *ChatRecorder) StartParams(*ollama.ChatRequest, []byte) (spanName string, opts []trace.SpanStartOption) {
+	return "Chat", startOpts
+}
+
+// RecordRequest implements the same method as defined in tracingapi.OllamaChatRecorder.
+func (r *ChatRecorder) RecordRequest(span trace.Span, req *ollama.ChatRequest, body []byte) {
+	attrs := []attribute.KeyValue{
+		attribute.String(openinference.SpanKind, openinference.SpanKindLLM),
+		attribute.String(openinference.LLMSystem, openinference.LLMSystemOllama),
+		attribute.String(openinference.LLMModelName, req.Model),
+	}
+	if r.traceConfig.HideInputs {
+		attrs = append(attrs, attribute.String(openinference.InputValue, openinference.RedactedValue))
+	} else {
+		attrs = append(attrs,
+			attribute.String(openinference.InputValue, string(body)),
+			attribute.String(openinference.InputMimeType, openinference.MimeTypeJSON),
+		)
+	}
+	if !r.traceConfig.HideLLMInvocationParameters {
+		// The invocation parameters are all the parameters except the messages and the tools.
+		params := *req
+		params.Messages, params.Tools = nil, nil
+		if paramsJSON, err := json.Marshal(params); err == nil {
+			attrs = append(attrs, attribute.String(openinference.LLMInvocationParameters, string(paramsJSON)))
+		}
+	}
+	if !r.traceConfig.HideInputs && !r.traceConfig.HideInputMessages {
+		for i := range req.Messages {
+			msg := &req.Messages[i]
+			content := msg.Content
+			if r.traceConfig.HideInputText {
+				content = openinference.RedactedValue
+			}
+			attrs = append(attrs,
+				attribute.String(openinference.InputMessageAttribute(i, openinference.MessageRole), msg.Role),
+				attribute.String(openinference.InputMessageAttribute(i, openinference.MessageContent), content),
+			)
+		}
+	}
+	span.SetAttributes(attrs...)
+}
+
+// RecordResponseChunks implements the same method as defined in tracingapi.OllamaChatRecorder.
+// The chunks are merged into a single response which is then recorded.
+func (r *ChatRecorder) RecordResponseChunks(span trace.Span, chunks []*ollama.ChatResponse) {
+	if len(chunks) == 0 {
+		return
+	}
+	span.AddEvent("First Token Stream Event")
+	var content, thinking strings.Builder
+	resp := &ollama.ChatResponse{}
+	for _, chunk := range chunks {
+		content.WriteString(chunk.Message.Content)
+		thinking.WriteString(chunk.Message.Thinking)
+		resp.Message.ToolCalls = append(resp.Message.ToolCalls, chunk.Message.ToolCalls...)
+		if chunk.Done {
+			resp.Model, resp.CreatedAt, resp.Done, resp.DoneReason, resp.Metrics = chunk.Model, chunk.CreatedAt, true, chunk.DoneReason, chunk.Metrics
+		}
+	}
+	resp.Message.Role = ollama.RoleAssistant
+	resp.Message.Content = content.String()
+	resp.Message.Thinking = thinking.String()
+	r.RecordResponse(span, resp)
+}
+
+// RecordResponseOnError implements the same method as defined in tracingapi.OllamaChatRecorder.
+func (r *ChatRecorder) RecordResponseOnError(span trace.Span, statusCode int, body []byte) {
+	openinference.RecordResponseError(span, statusCode, string(body))
+}
+
+// RecordResponse implements the same method as defined in tracingapi.OllamaChatRecorder.
+func (r *ChatRecorder) RecordResponse(span trace.Span, resp *ollama.ChatResponse) {
+	attrs := tokenCountAttributes(resp.PromptEvalCount, resp.EvalCount)
+	if !r.traceConfig.HideOutputs {
+		attrs = append(attrs, attribute.String(openinference.OutputMimeType, openinference.MimeTypeJSON))
+		if !r.traceConfig.HideOutputMessages {
+			content := resp.Message.Content
+			if r.traceConfig.HideOutputText {
+				content = openinference.RedactedValue
+			}
+			attrs = append(attrs,
+				attribute.String(openinference.OutputMessageAttribute(0, openinference.MessageRole), resp.Message.Role),
+				attribute.String(openinference.OutputMessageAttribute(0, openinference.MessageContent), content),
+			)
+			for i, tc := range resp.Message.ToolCalls {
+				attrs = append(attrs,
+					attribute.String(openinference.OutputMessageToolCallAttribute(0, i, openinference.ToolCallFunctionName), tc.Function.Name),
+					attribute.String(openinference.OutputMessageToolCallAttribute(0, i, openinference.ToolCallFunctionArguments), string(tc.Function.Arguments)),
+				)
+			}
+		}
+	}
+	bodyString := openinference.RedactedValue
+	if !r.traceConfig.HideOutputs {
+		if marshaled, err := json.Marshal(resp); err == nil {
+			bodyString = string(marshaled)
+		}
+	}
+	attrs = append(attrs, attribute.String(openinference.OutputValue, bodyString))
+	span.SetAttributes(attrs...)
+	span.SetStatus(codes.Ok, "")
+}
+
+// EmbedRecorder implements recorders for OpenInference spans of the Ollama embed API.
+type EmbedRecorder struct {
+	tracingapi.NoopChunkRecorder[struct{}]
+	traceConfig *openinference.TraceConfig
+}
+
+// NewEmbedRecorderFromEnv creates a tracingapi.OllamaEmbedRecorder from environment variables
+// using the OpenInference configuration specification.
+func NewEmbedRecorderFromEnv() tracingapi.OllamaEmbedRecorder {
+	return NewEmbedRecorder(nil)
+}
+
+// NewEmbedRecorder creates a tracingapi.OllamaEmbedRecorder with the given config using
+// the OpenInference configuration specification.
+//
+// Parameters:
+//   - config: configuration for redaction. Defaults to NewTraceConfigFromEnv().
+func NewEmbedRecorder(config *openinference.TraceConfig) tracingapi.OllamaEmbedRecorder {
+	if config == nil {
+		config = openinference.NewTraceConfigFromEnv()
+	}
+	return &EmbedRecorder{traceConfig: config}
+}
+
+// StartParams implements the same method as defined in tracingapi.OllamaEmbedRecorder.
+func (r *EmbedRecorder) StartParams(*ollama.EmbedRequest, []byte) (spanName string, opts []trace.SpanStartOption) {
+	return "Embed", startOpts
+}
+
+// RecordRequest implements the same method as defined in tracingapi.OllamaEmbedRecorder.
+func (r *EmbedRecorder) RecordRequest(span trace.Span, req *ollama.EmbedRequest, body []byte) {
+	attrs := []attribute.KeyValue{
+		attribute.String(openinference.SpanKind, openinference.SpanKindEmbedding),
+		attribute.String(openinference.LLMSystem, openinference.LLMSystemOllama),
+		attribute.String(openinference.EmbeddingModelName, req.Model),
+	}
+	if r.traceConfig.HideInputs {
+		attrs = append(attrs, attribute.String(openinference.InputValue, openinference.RedactedValue))
+	} else {
+		attrs = append(attrs,
+			attribute.String(openinference.InputValue, string(body)),
+			attribute.String(openinference.InputMimeType, openinference.MimeTypeJSON),
+		)
+		if !r.traceConfig.HideEmbeddingsText {
+			for i, text := range req.Input {
+				attrs = append(attrs, attribute.String(openinference.EmbeddingTextAttribute(i), text))
+			}
+		}
+	}
+	if !r.traceConfig.HideLLMInvocationParameters {
+		params := *req
+		params.Input = nil
+		if paramsJSON, err := json.Marshal(params); err == nil {
+			attrs = append(attrs, attribute.String(openinference.EmbeddingInvocationParameters, string(paramsJSON)))
+		}
+	}
+	span.SetAttributes(attrs...)
+}
+
+// RecordResponseOnError implements the same method as defined in tracingapi.OllamaEmbedRecorder.
+func (r *EmbedRecorder) RecordResponseOnError(span trace.Span, statusCode int, body []byte) {
+	openinference.RecordResponseError(span, statusCode, string(body))
+}
+
+// RecordResponse implements the same method as defined in tracingapi.OllamaEmbedRecorder.
+func (r *EmbedRecorder) RecordResponse(span trace.Span, resp *ollama.EmbedResponse) {
+	attrs := tokenCountAttributes(resp.PromptEvalCount, 0)
+	if !r.traceConfig.HideOutputs && !r.traceConfig.HideEmbeddingsVectors {
+		for i, embedding := range resp.Embeddings {
+			attrs = append(attrs, attribute.Float64Slice(openinference.EmbeddingVectorAttribute(i), embedding))
+		}
+	}
+	span.SetAttributes(attrs...)
+	span.SetStatus(codes.Ok, "")
+}
+
+// tokenCountAttributes returns the token count attributes of the prompt and the completion tokens.
+func tokenCountAttributes(promptTokens, completionTokens int) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if promptTokens > 0 {
+		attrs = append(attrs, attribute.Int(openinference.LLMTokenCountPrompt, promptTokens))
+	}
+	if completionTokens > 0 {
+		attrs = append(attrs, attribute.Int(openinference.LLMTokenCountCompletion, completionTokens))
+	}
+	if total := promptTokens + completionTokens; total > 0 {
+		attrs = append(attrs, attribute.Int(openinference.LLMTokenCountTotal, total))
+	}
+	return attrs
+}
@@ -48,6 +48,8 @@ const (
 	LLMSystemCohere = "cohere"
 	// LLMSystemAnthropic for Anthropic systems.
 	LLMSystemAnthropic = "anthropic"
+	// LLMSystemOllama for Ollama systems.
+	LLMSystemOllama = "ollama"
 )
 
 // Input/Output constants.
@@ -14,7 +14,9 @@ import (
 	"go.opentelemetry.io/otel/trace/noop"
 
 	cohereschema "github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/ollama"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
 )
 
@@ -40,6 +42,8 @@ var (
 	_ tracingapi.TranscriptionTracer   = (*transcriptionTracer)(nil)
 	_ tracingapi.TranslationTracer     = (*translationTracer)(nil)
 	_ tracingapi.RerankTracer          = (*rerankTracer)(nil)
+	_ tracingapi.OllamaChatTracer      = (*ollamaChatTracer)(nil)
+	_ tracingapi.OllamaEmbedTracer     = (*ollamaEmbedTracer)(nil)
 )
 
 type (
@@ -52,6 +56,8 @@ type (
 	transcriptionTracer   = requestTracerImpl[openai.TranscriptionRequest, openai.TranscriptionResponse, openai.TranscriptionStreamEvent]
 	translationTracer     = requestTracerImpl[openai.TranslationRequest, openai.TranslationResponse, struct{}]
 	rerankTracer          = requestTracerImpl[cohereschema.RerankV2Request, cohereschema.RerankV2Response, struct{}]
+	ollamaChatTracer      = requestTracerImpl[ollama.ChatRequest, ollama.ChatResponse, ollama.ChatResponse]
+	ollamaEmbedTracer     = requestTracerImpl[ollama.EmbedRequest, ollama.EmbedResponse, struct{}]
 )
 
 func newRequestTracer[ReqT any, RespT any, RespChunkT any](
@@ -93,6 +99,9 @@ func (t *requestTracerImpl[ReqT, RespT, ChunkT]) StartSpanAndInjectHeaders(
 
 	t.recorder.RecordRequest(span, req, body)
 
+	if virtualKey, ok := headers[internalapi.VirtualKeyHeader]; ok {
+		span.SetAttributes(attribute.String(internalapi.VirtualKeyAttribute, virtualKey))
+	}
 	if len(t.headerAttributes) > 0 {
 		attrs := make([]attribute.KeyValue, 0, len(t.headerAttributes))
 		for headerName, attrName := range t.headerAttributes {
@@ -227,3 +236,27 @@ func newMessageTracer(tracer trace.Tracer, propagator propagation.TextMapPropaga
 		},
 	)
 }
+
+func newOllamaChatTracer(tracer trace.Tracer, propagator propagation.TextMapPropagator, recorder tracingapi.OllamaChatRecorder, headerAttributes map[string]string) tracingapi.OllamaChatTracer {
+	return newRequestTracer(
+		tracer,
+		propagator,
+		recorder,
+		headerAttributes,
+		func(span trace.Span, recorder tracingapi.OllamaChatRecorder) tracingapi.OllamaChatSpan {
+			return &ollamaChatSpan{span: span, recorder: recorder}
+		},
+	)
+}
+
+func newOllamaEmbedTracer(tracer trace.Tracer, propagator propagation.TextMapPropagator, recorder tracingapi.OllamaEmbedRecorder, headerAttributes map[string]string) tracingapi.OllamaEmbedTracer {
+	return newRequestTracer(
+		tracer,
+		propagator,
+		recorder,
+		headerAttributes,
+		func(span trace.Span, recorder tracingapi.OllamaEmbedRecorder) tracingapi.OllamaEmbedSpan {
+			return &ollamaEmbedSpan{span: span, recorder: recorder}
+		},
+	)
+}
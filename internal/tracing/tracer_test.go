@@ -22,6 +22,7 @@ import (
 
 	"github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 	"github.com/envoyproxy/ai-gateway/internal/json"
 	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
 )
@@ -293,6 +294,27 @@ func TestRequestTracer_HeaderAttributeMapping(t *testing.T) {
 			},
 		})
 	})
+
+	t.Run("virtual key", func(t *testing.T) {
+		headers := map[string]string{internalapi.VirtualKeyHeader: "ns/team-a"}
+		reqBody, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		runRequestTracerLifecycleTest(t, requestTracerLifecycleTest[openai.ChatCompletionRequest, openai.ChatCompletionResponse, openai.ChatCompletionResponseChunk]{
+			constructor:      chatCompletionTracerCtor,
+			req:              req,
+			headers:          headers,
+			reqBody:          reqBody,
+			expectedSpanName: fmt.Sprintf("non-stream len: %d", len(reqBody)),
+			expectedSpanType: (*chatCompletionSpan)(nil),
+			recordAndEnd: func(span tracingapi.ChatCompletionSpan) {
+				span.EndSpan()
+			},
+			assertAttrs: func(t *testing.T, attrs []attribute.KeyValue) {
+				require.Contains(t, attrs, attribute.String(internalapi.VirtualKeyAttribute, "ns/team-a"))
+			},
+		})
+	})
 }
 
 func TestNewCompletionTracer_BuildsGenericRequestTracer(t *testing.T) {
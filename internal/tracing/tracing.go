@@ -21,6 +21,7 @@ import (
 	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference"
 	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference/anthropic"
 	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference/ollama"
 	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference/openai"
 	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
 )
@@ -38,6 +39,8 @@ type tracingImpl struct {
 	translationTracer     tracingapi.TranslationTracer
 	rerankTracer          tracingapi.RerankTracer
 	messageTracer         tracingapi.MessageTracer
+	ollamaChatTracer      tracingapi.OllamaChatTracer
+	ollamaEmbedTracer     tracingapi.OllamaEmbedTracer
 	mcpTracer             tracingapi.MCPTracer
 	// shutdown is nil when we didn't create tp.
 	shutdown func(context.Context) error
@@ -98,6 +101,16 @@ func (t *tracingImpl) MessageTracer() tracingapi.MessageTracer {
 	return t.messageTracer
 }
 
+// OllamaChatTracer implements the same method as documented on tracingapi.Tracing.
+func (t *tracingImpl) OllamaChatTracer() tracingapi.OllamaChatTracer {
+	return t.ollamaChatTracer
+}
+
+// OllamaEmbedTracer implements the same method as documented on tracingapi.Tracing.
+func (t *tracingImpl) OllamaEmbedTracer() tracingapi.OllamaEmbedTracer {
+	return t.ollamaEmbedTracer
+}
+
 // Shutdown implements the same method as documented on tracingapi.Tracing.
 func (t *tracingImpl) Shutdown(ctx context.Context) error {
 	if t.shutdown != nil {
@@ -221,6 +234,8 @@ func NewTracingFromEnv(ctx context.Context, stdout io.Writer, headerAttributeMap
 	translationRecorder := openai.NewTranslationRecorderFromEnv()
 	rerankRecorder := cohere.NewRerankRecorderFromEnv()
 	messageRecorder := anthropic.NewMessageRecorderFromEnv()
+	ollamaChatRecorder := ollama.NewChatRecorderFromEnv()
+	ollamaEmbedRecorder := ollama.NewEmbedRecorderFromEnv()
 
 	tracer := tp.Tracer("envoyproxy/ai-gateway")
 	return &tracingImpl{
@@ -283,6 +298,18 @@ func NewTracingFromEnv(ctx context.Context, stdout io.Writer, headerAttributeMap
 			messageRecorder,
 			headerAttrs,
 		),
+		ollamaChatTracer: newOllamaChatTracer(
+			tracer,
+			propagator,
+			ollamaChatRecorder,
+			headerAttrs,
+		),
+		ollamaEmbedTracer: newOllamaEmbedTracer(
+			tracer,
+			propagator,
+			ollamaEmbedRecorder,
+			headerAttrs,
+		),
 		mcpTracer: newMCPTracer(tracer, propagator, headerAttrs),
 		shutdown:  tp.Shutdown, // we have to shut down what we create.
 	}, nil
@@ -15,6 +15,7 @@ import (
 
 	anthropicschema "github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/ollama"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
 )
 
@@ -42,6 +43,10 @@ type (
 		RerankTracer() RerankTracer
 		// MessageTracer creates spans for Anthropic messages requests.
 		MessageTracer() MessageTracer
+		// OllamaChatTracer creates spans for Ollama chat requests on /api/chat endpoint.
+		OllamaChatTracer() OllamaChatTracer
+		// OllamaEmbedTracer creates spans for Ollama embed requests on /api/embed endpoint.
+		OllamaEmbedTracer() OllamaEmbedTracer
 		// MCPTracer creates spans for MCP requests.
 		MCPTracer() MCPTracer
 		// Shutdown shuts down the tracer, flushing any buffered spans.
@@ -86,6 +91,11 @@ type (
 	RerankTracer = RequestTracer[cohere.RerankV2Request, cohere.RerankV2Response, struct{}]
 	// MessageTracer creates spans for Anthropic messages requests.
 	MessageTracer = RequestTracer[anthropicschema.MessagesRequest, anthropicschema.MessagesResponse, anthropicschema.MessagesStreamChunk]
+	// OllamaChatTracer creates spans for Ollama chat requests.
+	// Note: Ollama streaming chunks are ChatResponse objects, the last of which carries the metrics.
+	OllamaChatTracer = RequestTracer[ollama.ChatRequest, ollama.ChatResponse, ollama.ChatResponse]
+	// OllamaEmbedTracer creates spans for Ollama embed requests.
+	OllamaEmbedTracer = RequestTracer[ollama.EmbedRequest, ollama.EmbedResponse, struct{}]
 )
 
 type (
@@ -121,6 +131,10 @@ type (
 	RerankSpan = Span[cohere.RerankV2Response, struct{}]
 	// MessageSpan represents an Anthropic messages request span.
 	MessageSpan = Span[anthropicschema.MessagesResponse, anthropicschema.MessagesStreamChunk]
+	// OllamaChatSpan represents an Ollama chat request span.
+	OllamaChatSpan = Span[ollama.ChatResponse, ollama.ChatResponse]
+	// OllamaEmbedSpan represents an Ollama embed request span.
+	OllamaEmbedSpan = Span[ollama.EmbedResponse, struct{}]
 )
 
 type (
@@ -170,6 +184,10 @@ type (
 	RerankRecorder = SpanRecorder[cohere.RerankV2Request, cohere.RerankV2Response, struct{}]
 	// MessageRecorder records attributes to a span according to a semantic convention.
 	MessageRecorder = SpanRecorder[anthropicschema.MessagesRequest, anthropicschema.MessagesResponse, anthropicschema.MessagesStreamChunk]
+	// OllamaChatRecorder records attributes to a span according to a semantic convention.
+	OllamaChatRecorder = SpanRecorder[ollama.ChatRequest, ollama.ChatResponse, ollama.ChatResponse]
+	// OllamaEmbedRecorder records attributes to a span according to a semantic convention.
+	OllamaEmbedRecorder = SpanRecorder[ollama.EmbedRequest, ollama.EmbedResponse, struct{}]
 )
 
 // NoopChunkRecorder provides a no-op RecordResponseChunks implementation for recorders that don't emit streaming chunks.
@@ -234,6 +252,16 @@ func (NoopTracing) MessageTracer() MessageTracer {
 	return NoopMessageTracer{}
 }
 
+// OllamaChatTracer implements Tracing.OllamaChatTracer.
+func (NoopTracing) OllamaChatTracer() OllamaChatTracer {
+	return NoopOllamaChatTracer{}
+}
+
+// OllamaEmbedTracer implements Tracing.OllamaEmbedTracer.
+func (NoopTracing) OllamaEmbedTracer() OllamaEmbedTracer {
+	return NoopOllamaEmbedTracer{}
+}
+
 // Shutdown implements Tracing.Shutdown.
 func (NoopTracing) Shutdown(context.Context) error {
 	return nil
@@ -262,6 +290,10 @@ type (
 	NoopRerankTracer = NoopTracer[cohere.RerankV2Request, cohere.RerankV2Response, struct{}]
 	// NoopMessageTracer implements MessageTracer.
 	NoopMessageTracer = NoopTracer[anthropicschema.MessagesRequest, anthropicschema.MessagesResponse, anthropicschema.MessagesStreamChunk]
+	// NoopOllamaChatTracer implements OllamaChatTracer.
+	NoopOllamaChatTracer = NoopTracer[ollama.ChatRequest, ollama.ChatResponse, ollama.ChatResponse]
+	// NoopOllamaEmbedTracer implements OllamaEmbedTracer.
+	NoopOllamaEmbedTracer = NoopTracer[ollama.EmbedRequest, ollama.EmbedResponse, struct{}]
 )
 
 // StartSpanAndInjectHeaders implements RequestTracer.StartSpanAndInjectHeaders.
@@ -0,0 +1,186 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/tidwall/sjson"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/ollama"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewOllamaToOllamaChatTranslator creates a passthrough translator for the Ollama chat API.
+func NewOllamaToOllamaChatTranslator(modelNameOverride internalapi.ModelNameOverride) OllamaChatTranslator {
+	return &ollamaToOllamaChatTranslator{modelNameOverride: modelNameOverride}
+}
+
+// ollamaToOllamaChatTranslator is a passthrough translator for the Ollama chat API.
+// May apply model overrides but otherwise preserves the Ollama format.
+type ollamaToOllamaChatTranslator struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+	stream            bool
+	// buffered holds the incomplete line of the streamed response.
+	buffered []byte
+	// streamingResponseModel and streamingTokenUsage are the model and the usage found in the streamed response so far.
+	streamingResponseModel internalapi.ResponseModel
+	streamingTokenUsage    metrics.TokenUsage
+}
+
+// RequestBody implements [OllamaChatTranslator.RequestBody].
+func (o *ollamaToOllamaChatTranslator) RequestBody(original []byte, req *ollama.ChatRequest, forceBodyMutation bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.stream = req.IsStream()
+	o.requestModel = req.Model
+	newHeaders, newBody, err = ollamaPassthroughRequestBody(original, ollama.ChatPath, o.modelNameOverride, forceBodyMutation)
+	if o.modelNameOverride != "" {
+		o.requestModel = o.modelNameOverride
+	}
+	return
+}
+
+// ollamaPassthroughRequestBody sets the path of the request to the given one and applies the model name override.
+// This is shared by the chat and the embed passthrough translators.
+func ollamaPassthroughRequestBody(original []byte, path string, modelNameOverride internalapi.ModelNameOverride, forceBodyMutation bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	if modelNameOverride != "" {
+		newBody, err = sjson.SetBytesOptions(original, "model", modelNameOverride, sjsonOptions)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to set model name: %w", err)
+		}
+	}
+	if forceBodyMutation && len(newBody) == 0 {
+		newBody = original
+	}
+	newHeaders = []internalapi.Header{{pathHeaderName, path}}
+	if len(newBody) > 0 {
+		newHeaders = append(newHeaders, internalapi.Header{contentLengthHeaderName, strconv.Itoa(len(newBody))})
+	}
+	return
+}
+
+// ResponseHeaders implements [OllamaChatTranslator.ResponseHeaders].
+func (o *ollamaToOllamaChatTranslator) ResponseHeaders(_ map[string]string) (
+	newHeaders []internalapi.Header, err error,
+) {
+	return nil, nil
+}
+
+// ResponseBody implements [OllamaChatTranslator.ResponseBody].
+func (o *ollamaToOllamaChatTranslator) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.OllamaChatSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	if o.stream {
+		var buf []byte
+		if buf, err = io.ReadAll(body); err != nil {
+			return nil, nil, tokenUsage, o.requestModel, fmt.Errorf("failed to read body: %w", err)
+		}
+		var lines [][]byte
+		lines, o.buffered = ndjsonLines(append(o.buffered, buf...))
+		for _, line := range lines {
+			resp := &ollama.ChatResponse{}
+			if json.Unmarshal(line, resp) != nil {
+				continue
+			}
+			if span != nil {
+				span.RecordResponseChunk(resp)
+			}
+			if resp.Model != "" {
+				o.streamingResponseModel = resp.Model
+			}
+			if resp.Done {
+				o.streamingTokenUsage = ollamaTokenUsage(resp.PromptEvalCount, resp.EvalCount)
+			}
+		}
+		return nil, nil, o.streamingTokenUsage, cmp.Or(o.streamingResponseModel, o.requestModel), nil
+	}
+
+	resp := &ollama.ChatResponse{}
+	if err = json.NewDecoder(body).Decode(resp); err != nil {
+		return nil, nil, tokenUsage, o.requestModel, fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(resp)
+	}
+	return nil, nil, ollamaTokenUsage(resp.PromptEvalCount, resp.EvalCount), cmp.Or(resp.Model, o.requestModel), nil
+}
+
+// ResponseError implements [OllamaChatTranslator.ResponseError].
+func (o *ollamaToOllamaChatTranslator) ResponseError(map[string]string, io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	return nil, nil, nil
+}
+
+// NewOllamaToOllamaEmbedTranslator creates a passthrough translator for the Ollama embed API.
+func NewOllamaToOllamaEmbedTranslator(modelNameOverride internalapi.ModelNameOverride) OllamaEmbedTranslator {
+	return &ollamaToOllamaEmbedTranslator{modelNameOverride: modelNameOverride}
+}
+
+// ollamaToOllamaEmbedTranslator is a passthrough translator for the Ollama embed API.
+// May apply model overrides but otherwise preserves the Ollama format.
+type ollamaToOllamaEmbedTranslator struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+}
+
+// RequestBody implements [OllamaEmbedTranslator.RequestBody].
+func (o *ollamaToOllamaEmbedTranslator) RequestBody(original []byte, req *ollama.EmbedRequest, forceBodyMutation bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.requestModel = req.Model
+	newHeaders, newBody, err = ollamaPassthroughRequestBody(original, ollama.EmbedPath, o.modelNameOverride, forceBodyMutation)
+	if o.modelNameOverride != "" {
+		o.requestModel = o.modelNameOverride
+	}
+	return
+}
+
+// ResponseHeaders implements [OllamaEmbedTranslator.ResponseHeaders].
+func (o *ollamaToOllamaEmbedTranslator) ResponseHeaders(_ map[string]string) (
+	newHeaders []internalapi.Header, err error,
+) {
+	return nil, nil
+}
+
+// ResponseBody implements [OllamaEmbedTranslator.ResponseBody].
+func (o *ollamaToOllamaEmbedTranslator) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.OllamaEmbedSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	resp := &ollama.EmbedResponse{}
+	if err = json.NewDecoder(body).Decode(resp); err != nil {
+		return nil, nil, tokenUsage, o.requestModel, fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(resp)
+	}
+	return nil, nil, ollamaTokenUsage(resp.PromptEvalCount, 0), cmp.Or(resp.Model, o.requestModel), nil
+}
+
+// ResponseError implements [OllamaEmbedTranslator.ResponseError].
+func (o *ollamaToOllamaEmbedTranslator) ResponseError(map[string]string, io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	return nil, nil, nil
+}
+
+// ollamaTokenUsage returns the token usage of the given numbers of the prompt and the generated tokens.
+func ollamaTokenUsage(promptTokens, completionTokens int) (tokenUsage metrics.TokenUsage) {
+	tokenUsage.SetInputTokens(uint32(promptTokens))                    //nolint:gosec
+	tokenUsage.SetOutputTokens(uint32(completionTokens))               //nolint:gosec
+	tokenUsage.SetTotalTokens(uint32(promptTokens + completionTokens)) //nolint:gosec
+	return
+}
@@ -0,0 +1,86 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/ollama"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+func TestOllamaToOllamaChatTranslator_RequestBody(t *testing.T) {
+	original := []byte(`{"model":"llama3.2","messages":[{"role":"user","content":"hi"}],"keep_alive":"5m"}`)
+	req := &ollama.ChatRequest{Model: "llama3.2"}
+
+	headers, body, err := NewOllamaToOllamaChatTranslator("").RequestBody(original, req, false)
+	require.NoError(t, err)
+	require.Nil(t, body)
+	require.Equal(t, []internalapi.Header{{pathHeaderName, "/api/chat"}}, headers)
+
+	headers, body, err = NewOllamaToOllamaChatTranslator("").RequestBody(original, req, true)
+	require.NoError(t, err)
+	require.Equal(t, original, body)
+	require.Equal(t, []internalapi.Header{{pathHeaderName, "/api/chat"}, {contentLengthHeaderName, strconv.Itoa(len(body))}}, headers)
+
+	_, body, err = NewOllamaToOllamaChatTranslator("qwen3").RequestBody(original, req, false)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"model":"qwen3","messages":[{"role":"user","content":"hi"}],"keep_alive":"5m"}`, string(body))
+}
+
+func TestOllamaToOllamaChatTranslator_ResponseBody(t *testing.T) {
+	t.Run("non-streaming", func(t *testing.T) {
+		tr := NewOllamaToOllamaChatTranslator("")
+		_, _, err := tr.RequestBody(nil, &ollama.ChatRequest{Model: "llama3.2", Stream: new(bool)}, false)
+		require.NoError(t, err)
+		_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(
+			`{"model":"llama3.2:latest","message":{"role":"assistant","content":"hello"},"done":true,"prompt_eval_count":10,"eval_count":5}`), true, nil)
+		require.NoError(t, err)
+		require.Nil(t, body)
+		require.Equal(t, "llama3.2:latest", responseModel)
+		totalTokens, _ := tokenUsage.TotalTokens()
+		require.Equal(t, uint32(15), totalTokens)
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		tr := NewOllamaToOllamaChatTranslator("")
+		_, _, err := tr.RequestBody(nil, &ollama.ChatRequest{Model: "llama3.2"}, false)
+		require.NoError(t, err)
+		_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(
+			`{"model":"llama3.2:latest","message":{"role":"assistant","content":"hel"},"done":false}`+"\n"+`{"model":"llama3.2:latest","mess`), false, nil)
+		require.NoError(t, err)
+		require.Nil(t, body)
+		require.Equal(t, "llama3.2:latest", responseModel)
+		_, ok := tokenUsage.InputTokens()
+		require.False(t, ok)
+
+		_, _, tokenUsage, _, err = tr.ResponseBody(nil, strings.NewReader(
+			`age":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":10,"eval_count":5}`+"\n"), true, nil)
+		require.NoError(t, err)
+		inputTokens, _ := tokenUsage.InputTokens()
+		outputTokens, _ := tokenUsage.OutputTokens()
+		require.Equal(t, [2]uint32{10, 5}, [2]uint32{inputTokens, outputTokens})
+	})
+}
+
+func TestOllamaToOllamaEmbedTranslator(t *testing.T) {
+	tr := NewOllamaToOllamaEmbedTranslator("all-minilm")
+	headers, body, err := tr.RequestBody([]byte(`{"model":"nomic-embed-text","input":"hi"}`), &ollama.EmbedRequest{Model: "nomic-embed-text"}, false)
+	require.NoError(t, err)
+	require.Equal(t, "/api/embed", headers[0].Value())
+	require.JSONEq(t, `{"model":"all-minilm","input":"hi"}`, string(body))
+
+	_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(`{"model":"all-minilm","embeddings":[[0.1]],"prompt_eval_count":2}`), true, nil)
+	require.NoError(t, err)
+	require.Nil(t, body)
+	require.Equal(t, "all-minilm", responseModel)
+	inputTokens, _ := tokenUsage.InputTokens()
+	require.Equal(t, uint32(2), inputTokens)
+}
@@ -0,0 +1,552 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/ollama"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewOllamaToChatCompletionTranslator creates a translator from the Ollama chat API to the backends other than Ollama.
+// The request is converted to an OpenAI chat completion request, which is then translated by the given
+// translator for the backend, and the OpenAI responses of the translator are converted back to the Ollama format.
+func NewOllamaToChatCompletionTranslator(inner OpenAIChatCompletionTranslator) OllamaChatTranslator {
+	return &ollamaToChatCompletionTranslator{inner: inner}
+}
+
+// ollamaToChatCompletionTranslator translates the Ollama chat API to OpenAI Chat Completions API and delegates
+// the rest of the translation to the inner translator.
+type ollamaToChatCompletionTranslator struct {
+	inner  OpenAIChatCompletionTranslator
+	stream bool
+	// buffered holds the incomplete server-sent event of the streamed response of the inner translator.
+	buffered []byte
+	// The fields below accumulate the streamed response, the end of which is sent as the last Ollama object.
+	streamingResponseModel internalapi.ResponseModel
+	streamingTokenUsage    metrics.TokenUsage
+	created                time.Time
+	doneReason             string
+	usage                  openai.Usage
+	toolCalls              []ollama.ToolCall
+}
+
+// RequestBody implements [OllamaChatTranslator.RequestBody].
+func (o *ollamaToChatCompletionTranslator) RequestBody(_ []byte, req *ollama.ChatRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.stream = req.IsStream()
+	body, err := ollamaToOpenAIChatRequest(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	var openAIReq openai.ChatCompletionRequest
+	if err = json.Unmarshal(body, &openAIReq); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", internalapi.ErrInvalidRequestBody, err)
+	}
+	// The body must always be mutated since the original one is in the Ollama format.
+	if newHeaders, newBody, err = o.inner.RequestBody(body, &openAIReq, true); err != nil {
+		return nil, nil, err
+	}
+	return withOllamaTranslatedBody(newHeaders, newBody, body)
+}
+
+// withOllamaTranslatedBody sets the body translated from the Ollama format when the inner translator
+// passes through the request body.
+func withOllamaTranslatedBody(newHeaders []internalapi.Header, newBody, translated []byte) ([]internalapi.Header, []byte, error) {
+	if newBody == nil {
+		newBody = translated
+		newHeaders = append(newHeaders, internalapi.Header{contentLengthHeaderName, strconv.Itoa(len(newBody))})
+	}
+	return newHeaders, newBody, nil
+}
+
+// ollamaToOpenAIChatRequest converts the Ollama chat request to the JSON of an OpenAI chat completion request.
+//
+// Ollama identifies the tool call a tool message responds to by the name of the tool, so the IDs of the tool calls
+// are generated and each tool message is matched to the earliest unanswered call of the tool.
+func ollamaToOpenAIChatRequest(req *ollama.ChatRequest) ([]byte, error) {
+	openAIReq := map[string]any{"model": req.Model}
+	var messages []map[string]any
+	var pending []ollamaPendingToolCall
+	var callCount int
+	for i := range req.Messages {
+		msg := &req.Messages[i]
+		switch msg.Role {
+		case ollama.RoleSystem:
+			messages = append(messages, map[string]any{"role": openai.ChatMessageRoleSystem, "content": msg.Content})
+		case ollama.RoleUser:
+			m := map[string]any{"role": openai.ChatMessageRoleUser, "content": msg.Content}
+			if len(msg.Images) > 0 {
+				parts := []map[string]any{{"type": "text", "text": msg.Content}}
+				for _, image := range msg.Images {
+					parts = append(parts, map[string]any{"type": "image_url", "image_url": map[string]any{"url": ollamaImageDataURI(image)}})
+				}
+				m["content"] = parts
+			}
+			messages = append(messages, m)
+		case ollama.RoleAssistant:
+			m := map[string]any{"role": openai.ChatMessageRoleAssistant, "content": msg.Content}
+			var toolCalls []map[string]any
+			for _, tc := range msg.ToolCalls {
+				callCount++
+				id := "call_" + strconv.Itoa(callCount)
+				pending = append(pending, ollamaPendingToolCall{id: id, name: tc.Function.Name})
+				toolCalls = append(toolCalls, map[string]any{
+					"id":       id,
+					"type":     openai.ChatCompletionMessageToolCallTypeFunction,
+					"function": map[string]any{"name": tc.Function.Name, "arguments": string(tc.Function.Arguments)},
+				})
+			}
+			if len(toolCalls) > 0 {
+				m["tool_calls"] = toolCalls
+			}
+			messages = append(messages, m)
+		case ollama.RoleTool:
+			var id string
+			for j, p := range pending {
+				if msg.ToolName == "" || p.name == msg.ToolName {
+					id = p.id
+					pending = append(pending[:j], pending[j+1:]...)
+					break
+				}
+			}
+			if id == "" {
+				return nil, fmt.Errorf("%w: the tool message of %q doesn't respond to any tool call", internalapi.ErrInvalidRequestBody, msg.ToolName)
+			}
+			messages = append(messages, map[string]any{"role": openai.ChatMessageRoleTool, "content": msg.Content, "tool_call_id": id})
+		default:
+			return nil, fmt.Errorf("%w: unsupported message role %q", internalapi.ErrInvalidRequestBody, msg.Role)
+		}
+	}
+	openAIReq["messages"] = messages
+
+	if len(req.Tools) > 0 {
+		tools := make([]map[string]any, 0, len(req.Tools))
+		for _, tool := range req.Tools {
+			function := map[string]any{"name": tool.Function.Name}
+			if tool.Function.Description != "" {
+				function["description"] = tool.Function.Description
+			}
+			if len(tool.Function.Parameters) > 0 {
+				function["parameters"] = tool.Function.Parameters
+			}
+			tools = append(tools, map[string]any{"type": openai.ToolTypeFunction, "function": function})
+		}
+		openAIReq["tools"] = tools
+	}
+
+	switch format := gjson.ParseBytes(req.Format); {
+	case len(req.Format) == 0:
+	case format.Type == gjson.String && format.Str == "json":
+		openAIReq["response_format"] = map[string]any{"type": openai.ChatCompletionResponseFormatTypeJSONObject}
+	case format.IsObject():
+		openAIReq["response_format"] = map[string]any{
+			"type":        openai.ChatCompletionResponseFormatTypeJSONSchema,
+			"json_schema": map[string]any{"name": "response", "schema": req.Format},
+		}
+	default:
+		return nil, fmt.Errorf("%w: format must be either \"json\" or a JSON schema", internalapi.ErrInvalidRequestBody)
+	}
+
+	// Only the thinking levels have the counterpart in OpenAI. The boolean is left to the default of the model.
+	if think := gjson.ParseBytes(req.Think); think.Type == gjson.String {
+		openAIReq["reasoning_effort"] = think.Str
+	}
+
+	for option, field := range map[string]string{
+		"temperature":       "temperature",
+		"top_p":             "top_p",
+		"seed":              "seed",
+		"num_predict":       "max_tokens",
+		"stop":              "stop",
+		"frequency_penalty": "frequency_penalty",
+		"presence_penalty":  "presence_penalty",
+	} {
+		if v, ok := req.Options[option]; ok {
+			openAIReq[field] = v
+		}
+	}
+
+	if req.IsStream() {
+		openAIReq["stream"] = true
+		openAIReq["stream_options"] = map[string]any{"include_usage": true}
+	}
+	body, err := json.Marshal(openAIReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	return body, nil
+}
+
+// ollamaPendingToolCall is a tool call that no tool message has responded to yet.
+type ollamaPendingToolCall struct {
+	id, name string
+}
+
+// ollamaImageDataURI returns the data URI of the base64 encoded image, whose media type is detected from the content
+// since Ollama doesn't have it.
+func ollamaImageDataURI(image string) string {
+	// 512 bytes are enough to detect the content type, which are 684 base64 characters.
+	head := image[:min(len(image), 684)]
+	decoded, _ := base64.StdEncoding.DecodeString(head[:len(head)/4*4])
+	mediaType := http.DetectContentType(decoded)
+	if !strings.HasPrefix(mediaType, "image/") {
+		mediaType = mimeTypeImageJPEG
+	}
+	return "data:" + mediaType + ";base64," + image
+}
+
+// ResponseHeaders implements [OllamaChatTranslator.ResponseHeaders].
+func (o *ollamaToChatCompletionTranslator) ResponseHeaders(headers map[string]string) (
+	newHeaders []internalapi.Header, err error,
+) {
+	if _, err = o.inner.ResponseHeaders(headers); err != nil {
+		return nil, err
+	}
+	contentType := jsonContentType
+	if o.stream {
+		contentType = ollama.NDJSONContentType
+	}
+	return []internalapi.Header{{contentTypeHeaderName, contentType}}, nil
+}
+
+// ResponseBody implements [OllamaChatTranslator.ResponseBody].
+func (o *ollamaToChatCompletionTranslator) ResponseBody(respHeaders map[string]string, body io.Reader, endOfStream bool, span tracingapi.OllamaChatSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to read body: %w", err)
+	}
+	_, openAIBody, tokenUsage, responseModel, err := o.inner.ResponseBody(respHeaders, bytes.NewReader(raw), endOfStream, nil)
+	if err != nil {
+		return nil, nil, tokenUsage, "", err
+	}
+	// The inner translator returns nil when the response is already in the OpenAI format.
+	if openAIBody == nil {
+		openAIBody = raw
+	}
+	if o.stream {
+		return o.handleStreamingResponse(openAIBody, endOfStream, tokenUsage, responseModel, span)
+	}
+
+	var openAIResp openai.ChatCompletionResponse
+	if err = json.Unmarshal(openAIBody, &openAIResp); err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	resp := &ollama.ChatResponse{
+		Model:     responseModel,
+		CreatedAt: time.Time(openAIResp.Created).UTC().Format(time.RFC3339Nano),
+		Message:   ollama.Message{Role: ollama.RoleAssistant},
+		Done:      true,
+		Metrics:   ollama.Metrics{PromptEvalCount: openAIResp.Usage.PromptTokens, EvalCount: openAIResp.Usage.CompletionTokens},
+	}
+	if len(openAIResp.Choices) > 0 {
+		choice := &openAIResp.Choices[0]
+		if choice.Message.Content != nil {
+			resp.Message.Content = *choice.Message.Content
+		}
+		if choice.Message.ReasoningContent != nil {
+			resp.Message.Thinking, _ = choice.Message.ReasoningContent.Value.(string)
+		}
+		for i, tc := range choice.Message.ToolCalls {
+			resp.Message.ToolCalls = append(resp.Message.ToolCalls, ollamaToolCall(i, tc.Function.Name, tc.Function.Arguments))
+		}
+		resp.DoneReason = openAIFinishReasonToOllama(choice.FinishReason)
+	}
+
+	newBody, err = json.Marshal(resp)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(resp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// handleStreamingResponse converts the OpenAI chunks of the inner translator to the newline delimited JSON objects
+// of Ollama. The tool calls, the finish reason and the usage are accumulated and sent in the last object at the end
+// of the stream since OpenAI sends the usage after the finish reason.
+func (o *ollamaToChatCompletionTranslator) handleStreamingResponse(openAIBody []byte, endOfStream bool, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, span tracingapi.OllamaChatSpan) (
+	newHeaders []internalapi.Header, newBody []byte, _ metrics.TokenUsage, _ internalapi.ResponseModel, err error,
+) {
+	if responseModel != "" {
+		o.streamingResponseModel = responseModel
+	}
+	o.streamingTokenUsage.Override(tokenUsage)
+	var events [][]byte
+	events, o.buffered = sseEventsData(append(o.buffered, openAIBody...))
+	for _, data := range events {
+		if bytes.Equal(data, sseDoneMessage) {
+			continue
+		}
+		var chunk openai.ChatCompletionResponseChunk
+		if err = json.Unmarshal(data, &chunk); err != nil {
+			return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal stream chunk: %w", err)
+		}
+		if o.created.IsZero() {
+			o.created = time.Time(chunk.Created)
+		}
+		if chunk.Usage != nil {
+			o.usage = *chunk.Usage
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		choice := &chunk.Choices[0]
+		if choice.FinishReason != "" {
+			o.doneReason = openAIFinishReasonToOllama(choice.FinishReason)
+		}
+		if choice.Delta == nil {
+			continue
+		}
+		for _, tc := range choice.Delta.ToolCalls {
+			// The arguments of a tool call are streamed in pieces following the first delta which has the name.
+			o.appendToolCallDelta(int(tc.Index), tc.Function.Name, tc.Function.Arguments)
+		}
+		resp := o.newStreamResponse()
+		if choice.Delta.Content != nil {
+			resp.Message.Content = *choice.Delta.Content
+		}
+		if choice.Delta.ReasoningContent != nil {
+			resp.Message.Thinking = choice.Delta.ReasoningContent.Text
+		}
+		if resp.Message.Content == "" && resp.Message.Thinking == "" {
+			continue
+		}
+		if err = o.appendStreamResponse(resp, &newBody, span); err != nil {
+			return nil, nil, tokenUsage, "", err
+		}
+	}
+
+	if endOfStream {
+		resp := o.newStreamResponse()
+		resp.Done = true
+		resp.DoneReason = cmp.Or(o.doneReason, ollama.DoneReasonStop)
+		resp.PromptEvalCount, resp.EvalCount = o.usage.PromptTokens, o.usage.CompletionTokens
+		for i := range o.toolCalls {
+			args := o.toolCalls[i].Function.Arguments
+			if len(args) == 0 || !gjson.ValidBytes(args) {
+				o.toolCalls[i].Function.Arguments = json.RawMessage("{}")
+			}
+		}
+		resp.Message.ToolCalls = o.toolCalls
+		if err = o.appendStreamResponse(resp, &newBody, span); err != nil {
+			return nil, nil, tokenUsage, "", err
+		}
+	}
+	// Return an empty body rather than nil so that Envoy doesn't pass through the original OpenAI events.
+	if newBody == nil {
+		newBody = []byte{}
+	}
+	return nil, newBody, o.streamingTokenUsage, o.streamingResponseModel, nil
+}
+
+// appendToolCallDelta accumulates the delta of the tool call at the given index.
+func (o *ollamaToChatCompletionTranslator) appendToolCallDelta(index int, name, arguments string) {
+	for i := range o.toolCalls {
+		if f := &o.toolCalls[i].Function; f.Index == index {
+			f.Arguments = append(f.Arguments, arguments...)
+			return
+		}
+	}
+	o.toolCalls = append(o.toolCalls, ollama.ToolCall{
+		Function: ollama.ToolCallFunction{Index: index, Name: name, Arguments: json.RawMessage(arguments)},
+	})
+}
+
+// newStreamResponse returns an Ollama object of the streamed response without the message content.
+func (o *ollamaToChatCompletionTranslator) newStreamResponse() *ollama.ChatResponse {
+	created := o.created
+	if created.IsZero() {
+		created = time.Now()
+	}
+	return &ollama.ChatResponse{
+		Model:     o.streamingResponseModel,
+		CreatedAt: created.UTC().Format(time.RFC3339Nano),
+		Message:   ollama.Message{Role: ollama.RoleAssistant},
+	}
+}
+
+// appendStreamResponse appends the given Ollama object as a line to the body and records it to the span.
+func (o *ollamaToChatCompletionTranslator) appendStreamResponse(resp *ollama.ChatResponse, body *[]byte, span tracingapi.OllamaChatSpan) error {
+	line, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stream chunk: %w", err)
+	}
+	*body = append(*body, line...)
+	*body = append(*body, '\n')
+	if span != nil {
+		span.RecordResponseChunk(resp)
+	}
+	return nil
+}
+
+// ollamaToolCall returns the Ollama tool call of the given OpenAI function call. The arguments are kept as a JSON
+// string when they aren't a valid JSON object.
+func ollamaToolCall(index int, name, arguments string) ollama.ToolCall {
+	args := json.RawMessage(arguments)
+	if arguments != "" && !gjson.Valid(arguments) {
+		args, _ = json.Marshal(arguments)
+	}
+	return ollama.ToolCall{Function: ollama.ToolCallFunction{Index: index, Name: name, Arguments: args}}
+}
+
+// openAIFinishReasonToOllama converts the OpenAI finish reason to the done reason of Ollama.
+func openAIFinishReasonToOllama(reason openai.ChatCompletionChoicesFinishReason) string {
+	if reason == openai.ChatCompletionChoicesFinishReasonLength {
+		return ollama.DoneReasonLength
+	}
+	return ollama.DoneReasonStop
+}
+
+// ResponseError implements [OllamaChatTranslator.ResponseError].
+func (o *ollamaToChatCompletionTranslator) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	return convertOpenAIErrorToOllama(respHeaders, body, o.inner.ResponseError)
+}
+
+// convertOpenAIErrorToOllama converts the error response of the backend to the Ollama error format using the
+// given error conversion of the inner translator. This is shared by the chat and the embed translators.
+func convertOpenAIErrorToOllama(respHeaders map[string]string, body io.Reader,
+	innerResponseError func(map[string]string, io.Reader) ([]internalapi.Header, []byte, error),
+) (newHeaders []internalapi.Header, newBody []byte, err error) {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read error body: %w", err)
+	}
+	_, openAIBody, err := innerResponseError(respHeaders, bytes.NewReader(raw))
+	if err != nil {
+		return nil, nil, err
+	}
+	if openAIBody == nil {
+		openAIBody = raw
+	}
+	ollamaErr := ollama.Error{Error: string(openAIBody)}
+	var openAIErr openai.Error
+	if json.Unmarshal(openAIBody, &openAIErr) == nil && openAIErr.Error.Message != "" {
+		ollamaErr.Error = openAIErr.Error.Message
+	}
+	newBody, err = json.Marshal(ollamaErr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal error body: %w", err)
+	}
+	return buildHeaders(newBody), newBody, nil
+}
+
+// NewOllamaToEmbeddingTranslator creates a translator from the Ollama embed API to the backends other than Ollama.
+// The request is converted to an OpenAI embedding request, which is then translated by the given translator for
+// the backend, and the OpenAI response of the translator is converted back to the Ollama format.
+func NewOllamaToEmbeddingTranslator(inner OpenAIEmbeddingTranslator) OllamaEmbedTranslator {
+	return &ollamaToEmbeddingTranslator{inner: inner}
+}
+
+// ollamaToEmbeddingTranslator translates the Ollama embed API to OpenAI Embeddings API and delegates the rest of
+// the translation to the inner translator.
+type ollamaToEmbeddingTranslator struct {
+	inner OpenAIEmbeddingTranslator
+}
+
+// RequestBody implements [OllamaEmbedTranslator.RequestBody].
+func (o *ollamaToEmbeddingTranslator) RequestBody(_ []byte, req *ollama.EmbedRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	openAIReq := map[string]any{"model": req.Model, "input": []string(req.Input)}
+	if req.Dimensions != nil {
+		openAIReq["dimensions"] = *req.Dimensions
+	}
+	body, err := json.Marshal(openAIReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	var embeddingReq openai.EmbeddingRequest
+	if err = json.Unmarshal(body, &embeddingReq); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", internalapi.ErrInvalidRequestBody, err)
+	}
+	if newHeaders, newBody, err = o.inner.RequestBody(body, &embeddingReq, true); err != nil {
+		return nil, nil, err
+	}
+	return withOllamaTranslatedBody(newHeaders, newBody, body)
+}
+
+// ResponseHeaders implements [OllamaEmbedTranslator.ResponseHeaders].
+func (o *ollamaToEmbeddingTranslator) ResponseHeaders(headers map[string]string) (
+	newHeaders []internalapi.Header, err error,
+) {
+	if _, err = o.inner.ResponseHeaders(headers); err != nil {
+		return nil, err
+	}
+	return []internalapi.Header{{contentTypeHeaderName, jsonContentType}}, nil
+}
+
+// ResponseBody implements [OllamaEmbedTranslator.ResponseBody].
+func (o *ollamaToEmbeddingTranslator) ResponseBody(respHeaders map[string]string, body io.Reader, endOfStream bool, span tracingapi.OllamaEmbedSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to read body: %w", err)
+	}
+	_, openAIBody, tokenUsage, responseModel, err := o.inner.ResponseBody(respHeaders, bytes.NewReader(raw), endOfStream, nil)
+	if err != nil {
+		return nil, nil, tokenUsage, "", err
+	}
+	if openAIBody == nil {
+		openAIBody = raw
+	}
+	var openAIResp openai.EmbeddingResponse
+	if err = json.Unmarshal(openAIBody, &openAIResp); err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	resp := &ollama.EmbedResponse{
+		Model:           responseModel,
+		Embeddings:      make([][]float64, 0, len(openAIResp.Data)),
+		PromptEvalCount: openAIResp.Usage.PromptTokens,
+	}
+	for _, data := range openAIResp.Data {
+		embedding, ok := data.Embedding.Value.([]float64)
+		if !ok {
+			return nil, nil, tokenUsage, "", fmt.Errorf("unexpected embedding type %T", data.Embedding.Value)
+		}
+		resp.Embeddings = append(resp.Embeddings, embedding)
+	}
+
+	newBody, err = json.Marshal(resp)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(resp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// ResponseError implements [OllamaEmbedTranslator.ResponseError].
+func (o *ollamaToEmbeddingTranslator) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	return convertOpenAIErrorToOllama(respHeaders, body, o.inner.ResponseError)
+}
@@ -0,0 +1,179 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/ollama"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestOllamaToChatCompletionTranslator_RequestBody(t *testing.T) {
+	var req ollama.ChatRequest
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"model": "gpt-4o",
+		"messages": [
+			{"role": "system", "content": "You are helpful."},
+			{"role": "user", "content": "What is in this image?", "images": ["iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg=="]},
+			{"role": "assistant", "content": "", "tool_calls": [{"function": {"name": "get_weather", "arguments": {"city":"Tokyo"}}}, {"function": {"name": "get_time", "arguments": {}}}]},
+			{"role": "tool", "content": "noon", "tool_name": "get_time"},
+			{"role": "tool", "content": "sunny", "tool_name": "get_weather"}
+		],
+		"tools": [{"type": "function", "function": {"name": "get_weather", "parameters": {"type": "object"}}}],
+		"format": "json",
+		"think": "low",
+		"options": {"temperature": 0.5, "num_predict": 100, "num_ctx": 8192},
+		"keep_alive": "5m"
+	}`), &req))
+
+	tr := NewOllamaToChatCompletionTranslator(NewChatCompletionOpenAIToOpenAITranslator("v1", ""))
+	headers, body, err := tr.RequestBody(nil, &req, false)
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{
+		{pathHeaderName, "/v1/chat/completions"},
+		{contentLengthHeaderName, strconv.Itoa(len(body))},
+	}, headers)
+	require.JSONEq(t, `{
+		"model": "gpt-4o",
+		"messages": [
+			{"role": "system", "content": "You are helpful."},
+			{"role": "user", "content": [
+				{"type": "text", "text": "What is in this image?"},
+				{"type": "image_url", "image_url": {"url": "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg=="}}
+			]},
+			{"role": "assistant", "content": "", "tool_calls": [
+				{"id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": "{\"city\":\"Tokyo\"}"}},
+				{"id": "call_2", "type": "function", "function": {"name": "get_time", "arguments": "{}"}}
+			]},
+			{"role": "tool", "content": "noon", "tool_call_id": "call_2"},
+			{"role": "tool", "content": "sunny", "tool_call_id": "call_1"}
+		],
+		"tools": [{"type": "function", "function": {"name": "get_weather", "parameters": {"type": "object"}}}],
+		"response_format": {"type": "json_object"},
+		"reasoning_effort": "low",
+		"temperature": 0.5,
+		"max_tokens": 100,
+		"stream": true,
+		"stream_options": {"include_usage": true}
+	}`, string(body))
+
+	t.Run("unanswered tool message", func(t *testing.T) {
+		_, _, err := tr.RequestBody(nil, &ollama.ChatRequest{Messages: []ollama.Message{{Role: ollama.RoleTool, ToolName: "f"}}}, false)
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+	})
+}
+
+func TestOllamaToChatCompletionTranslator_ResponseBody(t *testing.T) {
+	tr := NewOllamaToChatCompletionTranslator(NewChatCompletionOpenAIToOpenAITranslator("v1", ""))
+	_, _, err := tr.RequestBody(nil, &ollama.ChatRequest{Model: "gpt-4o", Stream: new(bool)}, false)
+	require.NoError(t, err)
+	headers, err := tr.ResponseHeaders(map[string]string{contentTypeHeaderName: jsonContentType})
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{{contentTypeHeaderName, jsonContentType}}, headers)
+
+	headers, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(`{
+		"id": "chatcmpl-1",
+		"object": "chat.completion",
+		"created": 1767323045,
+		"model": "gpt-4o-2024-08-06",
+		"choices": [{"index": 0, "message": {"role": "assistant", "content": null, "tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": "{\"city\":\"Tokyo\"}"}}]}, "finish_reason": "tool_calls"}],
+		"usage": {"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15}
+	}`), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(body))}}, headers)
+	require.Equal(t, "gpt-4o-2024-08-06", responseModel)
+	require.JSONEq(t, `{
+		"model": "gpt-4o-2024-08-06",
+		"created_at": "2026-01-02T03:04:05Z",
+		"message": {"role": "assistant", "content": "", "tool_calls": [{"function": {"name": "get_weather", "arguments": {"city": "Tokyo"}}}]},
+		"done": true,
+		"done_reason": "stop",
+		"prompt_eval_count": 10,
+		"eval_count": 5
+	}`, string(body))
+	totalTokens, _ := tokenUsage.TotalTokens()
+	require.Equal(t, uint32(15), totalTokens)
+}
+
+func TestOllamaToChatCompletionTranslator_ResponseBody_Streaming(t *testing.T) {
+	tr := NewOllamaToChatCompletionTranslator(NewChatCompletionOpenAIToOpenAITranslator("v1", ""))
+	_, _, err := tr.RequestBody(nil, &ollama.ChatRequest{Model: "gpt-4o"}, false)
+	require.NoError(t, err)
+	headers, err := tr.ResponseHeaders(map[string]string{contentTypeHeaderName: eventStreamContentType})
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{{contentTypeHeaderName, "application/x-ndjson"}}, headers)
+
+	// The third event is split across the calls.
+	_, body, _, _, err := tr.ResponseBody(nil, strings.NewReader(
+		`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","created":1767323045,"model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":"Hel"}}]}`+"\n\n"+
+			`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","created":1767323045,"model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":""}}]}}]}`+"\n\n"+
+			`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","created":1767323045,"model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":`), false, nil)
+	require.NoError(t, err)
+	require.Equal(t, `{"model":"gpt-4o","created_at":"2026-01-02T03:04:05Z","message":{"role":"assistant","content":"Hel"},"done":false}`+"\n", string(body))
+
+	_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(
+		`\"Tokyo\"}"}}]}}]}`+"\n\n"+
+			`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","created":1767323045,"model":"gpt-4o","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`+"\n\n"+
+			`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","created":1767323045,"model":"gpt-4o","choices":[],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`+"\n\n"+
+			"data: [DONE]\n\n"), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, "gpt-4o", responseModel)
+	lines, rest := ndjsonLines(body)
+	require.Empty(t, rest)
+	require.Len(t, lines, 1)
+	require.JSONEq(t, `{
+		"model": "gpt-4o",
+		"created_at": "2026-01-02T03:04:05Z",
+		"message": {"role": "assistant", "content": "", "tool_calls": [{"function": {"name": "get_weather", "arguments": {"city": "Tokyo"}}}]},
+		"done": true,
+		"done_reason": "stop",
+		"prompt_eval_count": 10,
+		"eval_count": 5
+	}`, string(lines[0]))
+	outputTokens, _ := tokenUsage.OutputTokens()
+	require.Equal(t, uint32(5), outputTokens)
+}
+
+func TestOllamaToChatCompletionTranslator_ResponseError(t *testing.T) {
+	tr := NewOllamaToChatCompletionTranslator(NewChatCompletionOpenAIToOllamaTranslator(""))
+	_, body, err := tr.ResponseError(map[string]string{statusHeaderName: "404"}, strings.NewReader(`{"error":"model not found"}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"error":"model not found"}`, string(body))
+
+	tr = NewOllamaToChatCompletionTranslator(NewChatCompletionOpenAIToOpenAITranslator("v1", ""))
+	_, body, err = tr.ResponseError(map[string]string{statusHeaderName: "429", contentTypeHeaderName: jsonContentType},
+		strings.NewReader(`{"error":{"type":"rate_limit_exceeded","message":"Rate limit reached"}}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"error":"Rate limit reached"}`, string(body))
+}
+
+func TestOllamaToEmbeddingTranslator(t *testing.T) {
+	var req ollama.EmbedRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"model": "text-embedding-3-small", "input": "hello", "dimensions": 2}`), &req))
+	tr := NewOllamaToEmbeddingTranslator(NewEmbeddingOpenAIToOpenAITranslator("v1", ""))
+	headers, body, err := tr.RequestBody(nil, &req, false)
+	require.NoError(t, err)
+	require.Equal(t, "/v1/embeddings", headers[0].Value())
+	require.JSONEq(t, `{"model": "text-embedding-3-small", "input": ["hello"], "dimensions": 2}`, string(body))
+
+	_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(`{
+		"object": "list",
+		"model": "text-embedding-3-small",
+		"data": [{"object": "embedding", "index": 0, "embedding": [0.1, 0.2]}],
+		"usage": {"prompt_tokens": 1, "total_tokens": 1}
+	}`), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, "text-embedding-3-small", responseModel)
+	require.JSONEq(t, `{"model": "text-embedding-3-small", "embeddings": [[0.1, 0.2]], "prompt_eval_count": 1}`, string(body))
+	inputTokens, _ := tokenUsage.InputTokens()
+	require.Equal(t, uint32(1), inputTokens)
+}
@@ -0,0 +1,382 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/dashscope"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+const dashScopeBackendError = "DashScopeBackendError"
+
+// NewChatCompletionOpenAIToDashScopeTranslator implements [Factory] for OpenAI to DashScope translation.
+func NewChatCompletionOpenAIToDashScopeTranslator(modelNameOverride internalapi.ModelNameOverride) OpenAIChatCompletionTranslator {
+	return &openAIToDashScopeTranslatorV1ChatCompletion{modelNameOverride: modelNameOverride}
+}
+
+// openAIToDashScopeTranslatorV1ChatCompletion translates OpenAI Chat Completions API to the native text generation
+// API of Alibaba Cloud Model Studio (DashScope).
+// https://www.alibabacloud.com/help/en/model-studio/qwen-api-reference
+type openAIToDashScopeTranslatorV1ChatCompletion struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+	stream            bool
+	// bufferedBody holds the incomplete server-sent event of the streamed response.
+	bufferedBody []byte
+	// created is the time of the first chunk of the streamed response.
+	created time.Time
+}
+
+// RequestBody implements [OpenAIChatCompletionTranslator.RequestBody].
+func (o *openAIToDashScopeTranslatorV1ChatCompletion) RequestBody(_ []byte, openAIReq *openai.ChatCompletionRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.requestModel = openAIReq.Model
+	if o.modelNameOverride != "" {
+		o.requestModel = o.modelNameOverride
+	}
+	o.stream = openAIReq.Stream
+
+	req := &dashscope.GenerationRequest{Model: o.requestModel}
+	for i := range openAIReq.Messages {
+		var msg dashscope.Message
+		msg, err = openAIMessageToDashScopeMessage(&openAIReq.Messages[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Input.Messages = append(req.Input.Messages, msg)
+	}
+	req.Parameters, err = openAIToDashScopeParameters(openAIReq)
+	if err != nil {
+		return nil, nil, err
+	}
+	newBody, err = json.Marshal(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, dashscope.TextGenerationPath},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	if o.stream {
+		newHeaders = append(newHeaders, internalapi.Header{dashscope.SSEHeaderName, "enable"})
+	}
+	return
+}
+
+// openAIToDashScopeParameters converts the generation parameters of the OpenAI request to DashScope's.
+func openAIToDashScopeParameters(openAIReq *openai.ChatCompletionRequest) (*dashscope.Parameters, error) {
+	params := &dashscope.Parameters{
+		ResultFormat: dashscope.ResultFormatMessage,
+		// The streamed events carry the deltas as in OpenAI's rather than the whole output so far.
+		IncrementalOutput: openAIReq.Stream,
+		MaxTokens:         openAIReq.MaxTokens,
+		Temperature:       openAIReq.Temperature,
+		TopP:              openAIReq.TopP,
+		PresencePenalty:   openAIReq.PresencePenalty,
+		Seed:              openAIReq.Seed,
+		N:                 openAIReq.N,
+		ParallelToolCalls: openAIReq.ParallelToolCalls,
+	}
+	if openAIReq.MaxCompletionTokens != nil {
+		params.MaxTokens = openAIReq.MaxCompletionTokens
+	}
+	if openAIReq.Stop.OfString.Valid() {
+		params.Stop = []string{openAIReq.Stop.OfString.String()}
+	} else if openAIReq.Stop.OfStringArray != nil {
+		params.Stop = openAIReq.Stop.OfStringArray
+	}
+	// The tools, the tool choice and the response format of DashScope follow the OpenAI format.
+	var err error
+	if len(openAIReq.Tools) > 0 {
+		if params.Tools, err = json.Marshal(openAIReq.Tools); err != nil {
+			return nil, fmt.Errorf("failed to marshal tools: %w", err)
+		}
+	}
+	if openAIReq.ToolChoice != nil {
+		if params.ToolChoice, err = json.Marshal(openAIReq.ToolChoice); err != nil {
+			return nil, fmt.Errorf("failed to marshal tool_choice: %w", err)
+		}
+	}
+	if openAIReq.ResponseFormat != nil {
+		if params.ResponseFormat, err = json.Marshal(openAIReq.ResponseFormat); err != nil {
+			return nil, fmt.Errorf("failed to marshal response_format: %w", err)
+		}
+	}
+	return params, nil
+}
+
+// openAIMessageToDashScopeMessage converts an OpenAI message to a DashScope message.
+func openAIMessageToDashScopeMessage(msg *openai.ChatCompletionMessageParamUnion) (dashscope.Message, error) {
+	switch {
+	case msg.OfSystem != nil:
+		return dashscope.Message{Role: openai.ChatMessageRoleSystem, Content: textOfContentUnion(msg.OfSystem.Content)}, nil
+	case msg.OfDeveloper != nil:
+		return dashscope.Message{Role: openai.ChatMessageRoleSystem, Content: textOfContentUnion(msg.OfDeveloper.Content)}, nil
+	case msg.OfTool != nil:
+		return dashscope.Message{
+			Role:       openai.ChatMessageRoleTool,
+			Content:    textOfContentUnion(msg.OfTool.Content),
+			ToolCallID: msg.OfTool.ToolCallID,
+		}, nil
+	case msg.OfAssistant != nil:
+		ret := dashscope.Message{Role: openai.ChatMessageRoleAssistant, Content: textOfAssistantContent(msg.OfAssistant.Content)}
+		for i, tc := range msg.OfAssistant.ToolCalls {
+			toolCall := dashscope.ToolCall{
+				Index:    int64(i),
+				Type:     string(openai.ChatCompletionMessageToolCallTypeFunction),
+				Function: dashscope.ToolCallFunction{Name: tc.Function.Name, Arguments: tc.Function.Arguments},
+			}
+			if tc.ID != nil {
+				toolCall.ID = *tc.ID
+			}
+			ret.ToolCalls = append(ret.ToolCalls, toolCall)
+		}
+		return ret, nil
+	case msg.OfUser != nil:
+		ret := dashscope.Message{Role: openai.ChatMessageRoleUser, Name: msg.OfUser.Name}
+		switch v := msg.OfUser.Content.Value.(type) {
+		case string:
+			ret.Content = v
+		case []openai.ChatCompletionContentPartUserUnionParam:
+			var b strings.Builder
+			for _, part := range v {
+				if part.OfText == nil {
+					return dashscope.Message{}, fmt.Errorf("%w: only text content parts are supported by the DashScope text generation API", internalapi.ErrInvalidRequestBody)
+				}
+				b.WriteString(part.OfText.Text)
+			}
+			ret.Content = b.String()
+		}
+		return ret, nil
+	}
+	return dashscope.Message{}, fmt.Errorf("%w: unsupported message", internalapi.ErrInvalidRequestBody)
+}
+
+// ResponseHeaders implements [OpenAIChatCompletionTranslator.ResponseHeaders].
+func (o *openAIToDashScopeTranslatorV1ChatCompletion) ResponseHeaders(_ map[string]string) (
+	newHeaders []internalapi.Header, err error,
+) {
+	if o.stream {
+		newHeaders = []internalapi.Header{{contentTypeHeaderName, eventStreamContentType}}
+	}
+	return
+}
+
+// ResponseBody implements [OpenAIChatCompletionTranslator.ResponseBody].
+//
+// DashScope doesn't return the model in the responses, so the request model is returned as the response model.
+func (o *openAIToDashScopeTranslatorV1ChatCompletion) ResponseBody(_ map[string]string, body io.Reader, endOfStream bool, span tracingapi.ChatCompletionSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	responseModel = o.requestModel
+	if o.stream {
+		return o.handleStreamingResponse(body, endOfStream, span)
+	}
+
+	var resp dashscope.GenerationResponse
+	if err = json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	openAIResp := &openai.ChatCompletionResponse{
+		ID:      resp.RequestID,
+		Object:  "chat.completion",
+		Model:   responseModel,
+		Created: openai.JSONUNIXTime(time.Now()),
+		Choices: make([]openai.ChatCompletionResponseChoice, 0, len(resp.Output.Choices)),
+	}
+	for i := range resp.Output.Choices {
+		choice := &resp.Output.Choices[i]
+		message := openai.ChatCompletionResponseChoiceMessage{Role: openai.ChatMessageRoleAssistant}
+		if choice.Message.Content != "" {
+			message.Content = &choice.Message.Content
+		}
+		if choice.Message.ReasoningContent != "" {
+			message.ReasoningContent = &openai.ReasoningContentUnion{Value: choice.Message.ReasoningContent}
+		}
+		for _, tc := range choice.Message.ToolCalls {
+			message.ToolCalls = append(message.ToolCalls, openai.ChatCompletionMessageToolCallParam{
+				ID:       &tc.ID,
+				Type:     openai.ChatCompletionMessageToolCallTypeFunction,
+				Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: tc.Function.Name, Arguments: tc.Function.Arguments},
+			})
+		}
+		openAIResp.Choices = append(openAIResp.Choices, openai.ChatCompletionResponseChoice{
+			Index:        int64(i),
+			Message:      message,
+			FinishReason: openai.ChatCompletionChoicesFinishReason(choice.FinishReason),
+		})
+	}
+	if resp.Usage != nil {
+		openAIResp.Usage = dashScopeUsageToOpenAIUsage(resp.Usage)
+		tokenUsage = tokenUsageFromOpenAIUsage(&openAIResp.Usage)
+	}
+
+	newBody, err = json.Marshal(openAIResp)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(openAIResp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// handleStreamingResponse converts the server-sent events of the DashScope streamed response to OpenAI chunks.
+// Each event carries the usage so far, which is sent in a separate chunk once the generation is finished.
+func (o *openAIToDashScopeTranslatorV1ChatCompletion) handleStreamingResponse(body io.Reader, endOfStream bool, span tracingapi.ChatCompletionSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	responseModel = o.requestModel
+	buf, err := io.ReadAll(io.MultiReader(bytes.NewReader(o.bufferedBody), body))
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to read body: %w", err)
+	}
+	if o.created.IsZero() {
+		o.created = time.Now()
+	}
+	var events [][]byte
+	events, o.bufferedBody = sseEventsData(buf)
+	for _, data := range events {
+		var event dashscope.GenerationResponse
+		if err = json.Unmarshal(data, &event); err != nil {
+			return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal stream event: %w", err)
+		}
+		chunk := &openai.ChatCompletionResponseChunk{
+			ID:      event.RequestID,
+			Object:  "chat.completion.chunk",
+			Created: openai.JSONUNIXTime(o.created),
+			Model:   o.requestModel,
+			Choices: make([]openai.ChatCompletionResponseChunkChoice, 0, len(event.Output.Choices)),
+		}
+		var finished bool
+		for i := range event.Output.Choices {
+			choice := &event.Output.Choices[i]
+			delta := &openai.ChatCompletionResponseChunkChoiceDelta{Role: openai.ChatMessageRoleAssistant}
+			if choice.Message.Content != "" {
+				delta.Content = &choice.Message.Content
+			}
+			if choice.Message.ReasoningContent != "" {
+				delta.ReasoningContent = &openai.StreamReasoningContent{Text: choice.Message.ReasoningContent}
+			}
+			for _, tc := range choice.Message.ToolCalls {
+				toolCall := openai.ChatCompletionChunkChoiceDeltaToolCall{
+					Index:    tc.Index,
+					Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: tc.Function.Name, Arguments: tc.Function.Arguments},
+				}
+				if tc.ID != "" {
+					toolCall.ID = &tc.ID
+					toolCall.Type = openai.ChatCompletionMessageToolCallTypeFunction
+				}
+				delta.ToolCalls = append(delta.ToolCalls, toolCall)
+			}
+			chunkChoice := openai.ChatCompletionResponseChunkChoice{Index: int64(i), Delta: delta}
+			if choice.FinishReason != "" && choice.FinishReason != dashscope.FinishReasonNull {
+				chunkChoice.FinishReason = openai.ChatCompletionChoicesFinishReason(choice.FinishReason)
+				finished = true
+			}
+			chunk.Choices = append(chunk.Choices, chunkChoice)
+		}
+		if err = serializeOpenAIChatCompletionChunk(chunk, &newBody); err != nil {
+			return nil, nil, tokenUsage, "", err
+		}
+		if span != nil {
+			span.RecordResponseChunk(chunk)
+		}
+
+		if event.Usage == nil {
+			continue
+		}
+		usage := dashScopeUsageToOpenAIUsage(event.Usage)
+		tokenUsage = tokenUsageFromOpenAIUsage(&usage)
+		if finished {
+			usageChunk := &openai.ChatCompletionResponseChunk{
+				ID:      event.RequestID,
+				Object:  "chat.completion.chunk",
+				Created: openai.JSONUNIXTime(o.created),
+				Model:   o.requestModel,
+				Choices: []openai.ChatCompletionResponseChunkChoice{},
+				Usage:   &usage,
+			}
+			if err = serializeOpenAIChatCompletionChunk(usageChunk, &newBody); err != nil {
+				return nil, nil, tokenUsage, "", err
+			}
+			if span != nil {
+				span.RecordResponseChunk(usageChunk)
+			}
+		}
+	}
+
+	if endOfStream {
+		newBody = append(newBody, sseDoneFullLine...)
+	}
+	// Return an empty body rather than nil so that Envoy doesn't pass through the original DashScope events.
+	if newBody == nil {
+		newBody = []byte{}
+	}
+	return
+}
+
+// dashScopeUsageToOpenAIUsage converts the DashScope usage to OpenAI's.
+func dashScopeUsageToOpenAIUsage(usage *dashscope.Usage) openai.Usage {
+	ret := openai.Usage{
+		PromptTokens:     usage.InputTokens,
+		CompletionTokens: usage.OutputTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
+	if usage.PromptTokensDetails != nil {
+		ret.PromptTokensDetails = &openai.PromptTokensDetails{CachedTokens: usage.PromptTokensDetails.CachedTokens}
+	}
+	if usage.OutputTokensDetails != nil {
+		ret.CompletionTokensDetails = &openai.CompletionTokensDetails{ReasoningTokens: usage.OutputTokensDetails.ReasoningTokens}
+	}
+	return ret
+}
+
+// ResponseError implements [OpenAIChatCompletionTranslator.ResponseError].
+func (o *openAIToDashScopeTranslatorV1ChatCompletion) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	return convertDashScopeErrorToOpenAI(respHeaders, body)
+}
+
+// convertDashScopeErrorToOpenAI converts the DashScope error responses to the OpenAI error format.
+// This is shared by the chat completion and the embedding translators.
+func convertDashScopeErrorToOpenAI(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	buf, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read error body: %w", err)
+	}
+	statusCode := respHeaders[statusHeaderName]
+	openAIErr := openai.Error{
+		Type:  "error",
+		Error: openai.ErrorType{Type: dashScopeBackendError, Message: string(buf), Code: &statusCode},
+	}
+	var dashScopeErr dashscope.Error
+	if json.Unmarshal(buf, &dashScopeErr) == nil && dashScopeErr.Message != "" {
+		openAIErr.Error.Type = dashScopeErr.Code
+		openAIErr.Error.Message = dashScopeErr.Message
+	}
+	newBody, err = json.Marshal(openAIErr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal error body: %w", err)
+	}
+	return buildHeaders(newBody), newBody, nil
+}
@@ -0,0 +1,108 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/dashscope"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewEmbeddingOpenAIToDashScopeTranslator implements [Factory] for OpenAI to DashScope embedding translation.
+func NewEmbeddingOpenAIToDashScopeTranslator(modelNameOverride internalapi.ModelNameOverride) OpenAIEmbeddingTranslator {
+	return &openAIToDashScopeTranslatorV1Embedding{modelNameOverride: modelNameOverride}
+}
+
+// openAIToDashScopeTranslatorV1Embedding translates OpenAI embedding requests to the native text embedding API of
+// Alibaba Cloud Model Studio (DashScope).
+// https://www.alibabacloud.com/help/en/model-studio/text-embedding-synchronous-api
+type openAIToDashScopeTranslatorV1Embedding struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+}
+
+// RequestBody implements [OpenAIEmbeddingTranslator.RequestBody].
+func (o *openAIToDashScopeTranslatorV1Embedding) RequestBody(_ []byte, req *openai.EmbeddingRequest, _ bool) (
+	newHeaders []internalapi.Header, mutatedBody []byte, err error,
+) {
+	o.requestModel = req.Model
+	if o.modelNameOverride != "" {
+		o.requestModel = o.modelNameOverride
+	}
+	texts, err := embeddingInputTexts(req, "DashScope")
+	if err != nil {
+		return nil, nil, err
+	}
+	dashScopeReq := &dashscope.EmbeddingRequest{Model: o.requestModel, Input: dashscope.EmbeddingInput{Texts: texts}}
+	if req.Dimensions != nil {
+		dashScopeReq.Parameters = &dashscope.EmbeddingParameters{Dimension: req.Dimensions}
+	}
+	mutatedBody, err = json.Marshal(dashScopeReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, dashscope.TextEmbeddingPath},
+		{contentLengthHeaderName, strconv.Itoa(len(mutatedBody))},
+	}
+	return
+}
+
+// ResponseHeaders implements [OpenAIEmbeddingTranslator.ResponseHeaders].
+func (o *openAIToDashScopeTranslatorV1Embedding) ResponseHeaders(_ map[string]string) (
+	newHeaders []internalapi.Header, err error,
+) {
+	return nil, nil
+}
+
+// ResponseBody implements [OpenAIEmbeddingTranslator.ResponseBody].
+func (o *openAIToDashScopeTranslatorV1Embedding) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.EmbeddingsSpan) (
+	newHeaders []internalapi.Header, mutatedBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	var resp dashscope.EmbeddingResponse
+	if err = json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	openAIResp := openai.EmbeddingResponse{Object: "list", Model: o.requestModel, Data: make([]openai.Embedding, 0, len(resp.Output.Embeddings))}
+	for _, embedding := range resp.Output.Embeddings {
+		openAIResp.Data = append(openAIResp.Data, openai.Embedding{
+			Object:    "embedding",
+			Index:     embedding.TextIndex,
+			Embedding: openai.EmbeddingUnion{Value: embedding.Embedding},
+		})
+	}
+	if resp.Usage != nil {
+		// The text embedding API only reports the total tokens, which are all input tokens.
+		openAIResp.Usage = openai.EmbeddingUsage{PromptTokens: resp.Usage.TotalTokens, TotalTokens: resp.Usage.TotalTokens}
+		tokenUsage.SetInputTokens(uint32(resp.Usage.TotalTokens)) //nolint:gosec
+		tokenUsage.SetTotalTokens(uint32(resp.Usage.TotalTokens)) //nolint:gosec
+	}
+
+	mutatedBody, err = json.Marshal(openAIResp)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(&openAIResp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(mutatedBody))}}
+	responseModel = o.requestModel
+	return
+}
+
+// ResponseError implements [OpenAIEmbeddingTranslator.ResponseError].
+func (o *openAIToDashScopeTranslatorV1Embedding) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, mutatedBody []byte, err error,
+) {
+	return convertDashScopeErrorToOpenAI(respHeaders, body)
+}
@@ -0,0 +1,43 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+)
+
+func TestOpenAIToDashScopeTranslatorV1Embedding(t *testing.T) {
+	tr := NewEmbeddingOpenAIToDashScopeTranslator("")
+	headers, body, err := tr.RequestBody(nil, &openai.EmbeddingRequest{
+		EmbeddingBaseRequest: openai.EmbeddingBaseRequest{Model: "text-embedding-v4", Dimensions: ptr.To(512)},
+		OfCompletion:         &openai.EmbeddingCompletionRequest{Input: openai.EmbeddingRequestInput{Value: "hello"}},
+	}, false)
+	require.NoError(t, err)
+	require.Equal(t, "/api/v1/services/embeddings/text-embedding/text-embedding", headers[0].Value())
+	require.JSONEq(t, `{"model": "text-embedding-v4", "input": {"texts": ["hello"]}, "parameters": {"dimension": 512}}`, string(body))
+
+	_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(`{
+		"request_id": "req-1",
+		"output": {"embeddings": [{"text_index": 0, "embedding": [0.1, 0.2]}]},
+		"usage": {"total_tokens": 2}
+	}`), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, "text-embedding-v4", responseModel)
+	require.JSONEq(t, `{
+		"object": "list",
+		"model": "text-embedding-v4",
+		"data": [{"object": "embedding", "index": 0, "embedding": [0.1, 0.2]}],
+		"usage": {"prompt_tokens": 2, "total_tokens": 2}
+	}`, string(body))
+	inputTokens, _ := tokenUsage.InputTokens()
+	require.Equal(t, uint32(2), inputTokens)
+}
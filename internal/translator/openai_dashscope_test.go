@@ -0,0 +1,135 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestOpenAIToDashScopeTranslatorV1ChatCompletion_RequestBody(t *testing.T) {
+	t.Run("full request", func(t *testing.T) {
+		var req openai.ChatCompletionRequest
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"model": "qwen-plus",
+			"messages": [
+				{"role": "developer", "content": "You are helpful."},
+				{"role": "user", "content": [{"type": "text", "text": "Weather in "}, {"type": "text", "text": "Hangzhou?"}]},
+				{"role": "assistant", "content": "", "tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": "{}"}}]},
+				{"role": "tool", "tool_call_id": "call_1", "content": "sunny"}
+			],
+			"max_completion_tokens": 100,
+			"stop": ["END"],
+			"stream": true,
+			"tools": [{"type": "function", "function": {"name": "get_weather", "parameters": {"type": "object"}}}],
+			"tool_choice": "auto"
+		}`), &req))
+
+		headers, body, err := NewChatCompletionOpenAIToDashScopeTranslator("").RequestBody(nil, &req, false)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{
+			{pathHeaderName, "/api/v1/services/aigc/text-generation/generation"},
+			{contentLengthHeaderName, strconv.Itoa(len(body))},
+			{"x-dashscope-sse", "enable"},
+		}, headers)
+		require.JSONEq(t, `{
+			"model": "qwen-plus",
+			"input": {"messages": [
+				{"role": "system", "content": "You are helpful."},
+				{"role": "user", "content": "Weather in Hangzhou?"},
+				{"role": "assistant", "content": "", "tool_calls": [{"index": 0, "id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": "{}"}}]},
+				{"role": "tool", "content": "sunny", "tool_call_id": "call_1"}
+			]},
+			"parameters": {
+				"result_format": "message",
+				"incremental_output": true,
+				"max_tokens": 100,
+				"stop": ["END"],
+				"tools": [{"type": "function", "function": {"name": "get_weather", "parameters": {"type": "object"}}}],
+				"tool_choice": "auto"
+			}
+		}`, string(body))
+	})
+
+	t.Run("model override", func(t *testing.T) {
+		_, body, err := NewChatCompletionOpenAIToDashScopeTranslator("qwen-max").RequestBody(nil, &openai.ChatCompletionRequest{Model: "qwen"}, false)
+		require.NoError(t, err)
+		require.Contains(t, string(body), `"model":"qwen-max"`)
+	})
+
+	t.Run("unsupported content", func(t *testing.T) {
+		var req openai.ChatCompletionRequest
+		require.NoError(t, json.Unmarshal([]byte(`{"model": "m", "messages": [{"role": "user", "content": [{"type": "image_url", "image_url": {"url": "https://example.com/a.png"}}]}]}`), &req))
+		_, _, err := NewChatCompletionOpenAIToDashScopeTranslator("").RequestBody(nil, &req, false)
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+	})
+}
+
+func TestOpenAIToDashScopeTranslatorV1ChatCompletion_ResponseBody(t *testing.T) {
+	tr := NewChatCompletionOpenAIToDashScopeTranslator("")
+	_, _, err := tr.RequestBody(nil, &openai.ChatCompletionRequest{Model: "qwen-plus"}, false)
+	require.NoError(t, err)
+
+	_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(`{
+		"request_id": "req-1",
+		"output": {"choices": [{"finish_reason": "stop", "message": {"role": "assistant", "content": "Sunny.", "reasoning_content": "Let me think."}}]},
+		"usage": {"input_tokens": 10, "output_tokens": 5, "total_tokens": 15, "output_tokens_details": {"reasoning_tokens": 3}}
+	}`), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, "qwen-plus", responseModel)
+	var resp openai.ChatCompletionResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	require.Equal(t, "req-1", resp.ID)
+	require.Equal(t, "qwen-plus", resp.Model)
+	require.Equal(t, "Sunny.", *resp.Choices[0].Message.Content)
+	require.Equal(t, "Let me think.", resp.Choices[0].Message.ReasoningContent.Value)
+	require.Equal(t, openai.ChatCompletionChoicesFinishReasonStop, resp.Choices[0].FinishReason)
+	require.Equal(t, 15, resp.Usage.TotalTokens)
+	reasoningTokens, _ := tokenUsage.ReasoningTokens()
+	outputTokens, _ := tokenUsage.OutputTokens()
+	require.Equal(t, [2]uint32{3, 5}, [2]uint32{reasoningTokens, outputTokens})
+}
+
+func TestOpenAIToDashScopeTranslatorV1ChatCompletion_ResponseBody_Streaming(t *testing.T) {
+	tr := NewChatCompletionOpenAIToDashScopeTranslator("")
+	_, _, err := tr.RequestBody(nil, &openai.ChatCompletionRequest{Model: "qwen-plus", Stream: true}, false)
+	require.NoError(t, err)
+
+	_, body, tokenUsage, _, err := tr.ResponseBody(nil, strings.NewReader("id:1\nevent:result\n:HTTP_STATUS/200\n"+
+		`data:{"output":{"choices":[{"message":{"content":"Sun","role":"assistant"},"finish_reason":"null"}]},"usage":{"total_tokens":11,"output_tokens":1,"input_tokens":10},"request_id":"req-1"}`+"\n\n"+
+		"id:2\nevent:result\n:HTTP_STATUS/200\n"+
+		`data:{"output":{"choices":[{"message":{"content":"ny.","role":"assistant"},"finish_reason":"stop"}]},"usage":{"total_tokens":12,"output_tokens":2,"input_tokens":10},"request_id":"req-1"}`+"\n\n"), true, nil)
+	require.NoError(t, err)
+	require.True(t, bytes.HasSuffix(body, sseDoneFullLine))
+	chunks := parseOpenAIChunks(t, body)
+	require.Len(t, chunks, 3)
+	require.Equal(t, "Sun", *chunks[0].Choices[0].Delta.Content)
+	require.Empty(t, chunks[0].Choices[0].FinishReason)
+	require.Nil(t, chunks[0].Usage)
+	require.Equal(t, "ny.", *chunks[1].Choices[0].Delta.Content)
+	require.Equal(t, openai.ChatCompletionChoicesFinishReasonStop, chunks[1].Choices[0].FinishReason)
+	require.Equal(t, 12, chunks[2].Usage.TotalTokens)
+	require.Empty(t, chunks[2].Choices)
+	outputTokens, _ := tokenUsage.OutputTokens()
+	require.Equal(t, uint32(2), outputTokens)
+}
+
+func TestOpenAIToDashScopeTranslatorV1ChatCompletion_ResponseError(t *testing.T) {
+	tr := NewChatCompletionOpenAIToDashScopeTranslator("")
+	headers, body, err := tr.ResponseError(map[string]string{statusHeaderName: "401"},
+		strings.NewReader(`{"code":"InvalidApiKey","message":"Invalid API-key provided.","request_id":"req-1"}`))
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{{contentTypeHeaderName, jsonContentType}, {contentLengthHeaderName, strconv.Itoa(len(body))}}, headers)
+	require.JSONEq(t, `{"type":"error","error":{"type":"InvalidApiKey","message":"Invalid API-key provided.","code":"401"}}`, string(body))
+}
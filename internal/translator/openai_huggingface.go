@@ -0,0 +1,305 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/huggingface"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+const huggingFaceBackendError = "HuggingFaceBackendError"
+
+// NewCompletionOpenAIToHuggingFaceTGITranslator implements [Factory] for OpenAI to Hugging Face TGI translation
+// for completions.
+func NewCompletionOpenAIToHuggingFaceTGITranslator(modelNameOverride internalapi.ModelNameOverride) OpenAICompletionTranslator {
+	return &openAIToHuggingFaceTGITranslatorV1Completion{modelNameOverride: modelNameOverride}
+}
+
+// openAIToHuggingFaceTGITranslatorV1Completion translates OpenAI Completions API to the native text generation API of
+// Hugging Face Text Generation Inference (TGI).
+// https://huggingface.github.io/text-generation-inference/#/Text%20Generation%20Inference/generate
+//
+// A TGI server serves a single model, so the model of the request is only used as the model of the response.
+type openAIToHuggingFaceTGITranslatorV1Completion struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+	stream            bool
+	// bufferedBody holds the incomplete server-sent event of the streamed response.
+	bufferedBody []byte
+	// id and created are the ID and the time of the response shared by all the chunks.
+	id      string
+	created time.Time
+}
+
+// RequestBody implements [OpenAICompletionTranslator.RequestBody].
+func (o *openAIToHuggingFaceTGITranslatorV1Completion) RequestBody(_ []byte, openAIReq *openai.CompletionRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.requestModel = openAIReq.Model
+	if o.modelNameOverride != "" {
+		o.requestModel = o.modelNameOverride
+	}
+	o.stream = openAIReq.Stream
+
+	req := &huggingface.GenerateRequest{}
+	switch v := openAIReq.Prompt.Value.(type) {
+	case string:
+		req.Inputs = v
+	case []string:
+		if len(v) != 1 {
+			return nil, nil, fmt.Errorf("%w: TGI only supports a single prompt", internalapi.ErrInvalidRequestBody)
+		}
+		req.Inputs = v[0]
+	default:
+		return nil, nil, fmt.Errorf("%w: TGI only supports text prompts", internalapi.ErrInvalidRequestBody)
+	}
+	if openAIReq.N != nil && *openAIReq.N > 1 {
+		return nil, nil, fmt.Errorf("%w: TGI only supports a single completion", internalapi.ErrInvalidRequestBody)
+	}
+	req.Parameters, err = openAIToHuggingFaceTGIParameters(openAIReq)
+	if err != nil {
+		return nil, nil, err
+	}
+	newBody, err = json.Marshal(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	path := huggingface.GeneratePath
+	if o.stream {
+		path = huggingface.GenerateStreamPath
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, path},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// openAIToHuggingFaceTGIParameters converts the generation parameters of the OpenAI request to TGI's.
+func openAIToHuggingFaceTGIParameters(openAIReq *openai.CompletionRequest) (*huggingface.GenerateParameters, error) {
+	params := &huggingface.GenerateParameters{
+		// The details carry the finish reason and the number of the generated tokens.
+		Details:          true,
+		BestOf:           openAIReq.BestOf,
+		FrequencyPenalty: openAIReq.FrequencyPenalty,
+		Seed:             openAIReq.Seed,
+		Temperature:      openAIReq.Temperature,
+		TopP:             openAIReq.TopP,
+		MaxNewTokens:     openAIReq.MaxTokens,
+	}
+	if openAIReq.Echo {
+		params.ReturnFullText = ptr.To(true)
+	}
+	if openAIReq.Temperature != nil && *openAIReq.Temperature > 0 {
+		// TGI uses greedy decoding unless sampling is enabled.
+		params.DoSample = ptr.To(true)
+	}
+	switch v := openAIReq.Stop.(type) {
+	case nil:
+	case string:
+		params.Stop = []string{v}
+	case []any:
+		for _, s := range v {
+			str, ok := s.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: stop must be a string or a list of strings", internalapi.ErrInvalidRequestBody)
+			}
+			params.Stop = append(params.Stop, str)
+		}
+	case []string:
+		params.Stop = v
+	default:
+		return nil, fmt.Errorf("%w: stop must be a string or a list of strings", internalapi.ErrInvalidRequestBody)
+	}
+	return params, nil
+}
+
+// ResponseHeaders implements [OpenAICompletionTranslator.ResponseHeaders].
+func (o *openAIToHuggingFaceTGITranslatorV1Completion) ResponseHeaders(_ map[string]string) (
+	newHeaders []internalapi.Header, err error,
+) {
+	if o.stream {
+		newHeaders = []internalapi.Header{{contentTypeHeaderName, eventStreamContentType}}
+	}
+	return
+}
+
+// ResponseBody implements [OpenAICompletionTranslator.ResponseBody].
+//
+// TGI doesn't return the model in the responses, so the request model is returned as the response model.
+func (o *openAIToHuggingFaceTGITranslatorV1Completion) ResponseBody(respHeaders map[string]string, body io.Reader, endOfStream bool, span tracingapi.CompletionSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	responseModel = o.requestModel
+	if o.id == "" {
+		o.id, o.created = "cmpl-"+uuid.New().String(), time.Now()
+	}
+	if o.stream {
+		return o.handleStreamingResponse(body, endOfStream, span)
+	}
+
+	var resp huggingface.GenerateResponse
+	if err = json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	openAIResp := o.newResponse()
+	choice := openai.CompletionChoice{Text: resp.GeneratedText, Index: ptr.To(0)}
+	// The response body only has the number of the generated tokens, and the number of the prompt tokens is in the headers.
+	usage := &openai.Usage{}
+	usage.PromptTokens, _ = strconv.Atoi(respHeaders[huggingface.PromptTokensHeaderName])
+	usage.CompletionTokens, _ = strconv.Atoi(respHeaders[huggingface.GeneratedTokensHeaderName])
+	if resp.Details != nil {
+		choice.FinishReason = huggingFaceFinishReasonToOpenAI(resp.Details.FinishReason)
+		usage.CompletionTokens = resp.Details.GeneratedTokens
+	}
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	openAIResp.Choices = []openai.CompletionChoice{choice}
+	openAIResp.Usage = usage
+	tokenUsage = tokenUsageFromOpenAIUsage(usage)
+
+	newBody, err = json.Marshal(openAIResp)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(openAIResp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// handleStreamingResponse converts the server-sent events of the TGI streamed response to OpenAI chunks.
+// The last event carries the details, which are sent as the finish reason and the usage.
+func (o *openAIToHuggingFaceTGITranslatorV1Completion) handleStreamingResponse(body io.Reader, endOfStream bool, span tracingapi.CompletionSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	responseModel = o.requestModel
+	buf, err := io.ReadAll(io.MultiReader(bytes.NewReader(o.bufferedBody), body))
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to read body: %w", err)
+	}
+	var events [][]byte
+	events, o.bufferedBody = sseEventsData(buf)
+	for _, data := range events {
+		var event huggingface.StreamResponse
+		if err = json.Unmarshal(data, &event); err != nil {
+			return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal stream event: %w", err)
+		}
+		var chunks []*openai.CompletionResponse
+		// The special tokens such as the end of sequence aren't part of the text.
+		if !event.Token.Special || event.Details != nil {
+			chunk := o.newResponse()
+			choice := openai.CompletionChoice{Index: ptr.To(0)}
+			if !event.Token.Special {
+				choice.Text = event.Token.Text
+			}
+			if event.Details != nil {
+				choice.FinishReason = huggingFaceFinishReasonToOpenAI(event.Details.FinishReason)
+			}
+			chunk.Choices = []openai.CompletionChoice{choice}
+			chunks = append(chunks, chunk)
+		}
+		if event.Details != nil {
+			usage := &openai.Usage{
+				PromptTokens:     event.Details.InputLength,
+				CompletionTokens: event.Details.GeneratedTokens,
+				TotalTokens:      event.Details.InputLength + event.Details.GeneratedTokens,
+			}
+			tokenUsage = tokenUsageFromOpenAIUsage(usage)
+			usageChunk := o.newResponse()
+			usageChunk.Choices = []openai.CompletionChoice{}
+			usageChunk.Usage = usage
+			chunks = append(chunks, usageChunk)
+		}
+		for _, chunk := range chunks {
+			var chunkBytes []byte
+			if chunkBytes, err = json.Marshal(chunk); err != nil {
+				return nil, nil, tokenUsage, "", fmt.Errorf("failed to marshal stream chunk: %w", err)
+			}
+			newBody = append(newBody, sseDataPrefix...)
+			newBody = append(newBody, chunkBytes...)
+			newBody = append(newBody, '\n', '\n')
+			if span != nil {
+				span.RecordResponseChunk(chunk)
+			}
+		}
+	}
+
+	if endOfStream {
+		newBody = append(newBody, sseDoneFullLine...)
+	}
+	// Return an empty body rather than nil so that Envoy doesn't pass through the original TGI events.
+	if newBody == nil {
+		newBody = []byte{}
+	}
+	return
+}
+
+// newResponse returns an OpenAI completion response without the choices.
+func (o *openAIToHuggingFaceTGITranslatorV1Completion) newResponse() *openai.CompletionResponse {
+	return &openai.CompletionResponse{
+		ID:      o.id,
+		Object:  "text_completion",
+		Created: openai.JSONUNIXTime(o.created),
+		Model:   o.requestModel,
+	}
+}
+
+// huggingFaceFinishReasonToOpenAI converts the finish reason of TGI to OpenAI's.
+func huggingFaceFinishReasonToOpenAI(reason string) string {
+	if reason == huggingface.FinishReasonLength {
+		return string(openai.ChatCompletionChoicesFinishReasonLength)
+	}
+	return string(openai.ChatCompletionChoicesFinishReasonStop)
+}
+
+// ResponseError implements [OpenAICompletionTranslator.ResponseError].
+func (o *openAIToHuggingFaceTGITranslatorV1Completion) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	return convertHuggingFaceErrorToOpenAI(respHeaders, body)
+}
+
+// convertHuggingFaceErrorToOpenAI converts the TGI and TEI error responses to the OpenAI error format.
+// This is shared by the completion and the embedding translators.
+func convertHuggingFaceErrorToOpenAI(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	buf, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read error body: %w", err)
+	}
+	statusCode := respHeaders[statusHeaderName]
+	openAIErr := openai.Error{
+		Type:  "error",
+		Error: openai.ErrorType{Type: huggingFaceBackendError, Message: string(buf), Code: &statusCode},
+	}
+	var hfErr huggingface.Error
+	if json.Unmarshal(buf, &hfErr) == nil && hfErr.Error != "" {
+		if hfErr.ErrorType != "" {
+			openAIErr.Error.Type = hfErr.ErrorType
+		}
+		openAIErr.Error.Message = hfErr.Error
+	}
+	newBody, err = json.Marshal(openAIErr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal error body: %w", err)
+	}
+	return buildHeaders(newBody), newBody, nil
+}
@@ -0,0 +1,109 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/huggingface"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewEmbeddingOpenAIToHuggingFaceTEITranslator implements [Factory] for OpenAI to Hugging Face TEI embedding translation.
+func NewEmbeddingOpenAIToHuggingFaceTEITranslator(modelNameOverride internalapi.ModelNameOverride) OpenAIEmbeddingTranslator {
+	return &openAIToHuggingFaceTEITranslatorV1Embedding{modelNameOverride: modelNameOverride}
+}
+
+// openAIToHuggingFaceTEITranslatorV1Embedding translates OpenAI embedding requests to the native embedding API of
+// Hugging Face Text Embeddings Inference (TEI).
+// https://huggingface.github.io/text-embeddings-inference/#/Text%20Embeddings%20Inference/embed
+//
+// A TEI server serves a single model, so the model of the request is only used as the model of the response.
+type openAIToHuggingFaceTEITranslatorV1Embedding struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+}
+
+// RequestBody implements [OpenAIEmbeddingTranslator.RequestBody].
+func (o *openAIToHuggingFaceTEITranslatorV1Embedding) RequestBody(_ []byte, req *openai.EmbeddingRequest, _ bool) (
+	newHeaders []internalapi.Header, mutatedBody []byte, err error,
+) {
+	o.requestModel = req.Model
+	if o.modelNameOverride != "" {
+		o.requestModel = o.modelNameOverride
+	}
+	texts, err := embeddingInputTexts(req, "TEI")
+	if err != nil {
+		return nil, nil, err
+	}
+	mutatedBody, err = json.Marshal(&huggingface.EmbedRequest{Inputs: texts, Dimensions: req.Dimensions})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, huggingface.EmbedPath},
+		{contentLengthHeaderName, strconv.Itoa(len(mutatedBody))},
+	}
+	return
+}
+
+// ResponseHeaders implements [OpenAIEmbeddingTranslator.ResponseHeaders].
+func (o *openAIToHuggingFaceTEITranslatorV1Embedding) ResponseHeaders(_ map[string]string) (
+	newHeaders []internalapi.Header, err error,
+) {
+	return nil, nil
+}
+
+// ResponseBody implements [OpenAIEmbeddingTranslator.ResponseBody].
+func (o *openAIToHuggingFaceTEITranslatorV1Embedding) ResponseBody(respHeaders map[string]string, body io.Reader, _ bool, span tracingapi.EmbeddingsSpan) (
+	newHeaders []internalapi.Header, mutatedBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	var resp huggingface.EmbedResponse
+	if err = json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	responseModel = o.requestModel
+	// The response body only has the embeddings, and the number of the input tokens is in the headers.
+	inputTokens, _ := strconv.Atoi(respHeaders[huggingface.ComputeTokensHeaderName])
+	openAIResp := openai.EmbeddingResponse{
+		Object: "list",
+		Model:  responseModel,
+		Data:   make([]openai.Embedding, 0, len(resp)),
+		Usage:  openai.EmbeddingUsage{PromptTokens: inputTokens, TotalTokens: inputTokens},
+	}
+	for i, embedding := range resp {
+		openAIResp.Data = append(openAIResp.Data, openai.Embedding{
+			Object:    "embedding",
+			Index:     i,
+			Embedding: openai.EmbeddingUnion{Value: embedding},
+		})
+	}
+	tokenUsage.SetInputTokens(uint32(inputTokens)) //nolint:gosec
+	tokenUsage.SetTotalTokens(uint32(inputTokens)) //nolint:gosec
+
+	mutatedBody, err = json.Marshal(openAIResp)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(&openAIResp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(mutatedBody))}}
+	return
+}
+
+// ResponseError implements [OpenAIEmbeddingTranslator.ResponseError].
+func (o *openAIToHuggingFaceTEITranslatorV1Embedding) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, mutatedBody []byte, err error,
+) {
+	return convertHuggingFaceErrorToOpenAI(respHeaders, body)
+}
@@ -0,0 +1,42 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+)
+
+func TestOpenAIToHuggingFaceTEITranslatorV1Embedding(t *testing.T) {
+	tr := NewEmbeddingOpenAIToHuggingFaceTEITranslator("")
+	headers, body, err := tr.RequestBody(nil, &openai.EmbeddingRequest{
+		EmbeddingBaseRequest: openai.EmbeddingBaseRequest{Model: "BAAI/bge-large-en-v1.5"},
+		OfCompletion:         &openai.EmbeddingCompletionRequest{Input: openai.EmbeddingRequestInput{Value: "hello"}},
+	}, false)
+	require.NoError(t, err)
+	require.Equal(t, "/embed", headers[0].Value())
+	require.JSONEq(t, `{"inputs": ["hello"]}`, string(body))
+
+	_, body, tokenUsage, responseModel, err := tr.ResponseBody(map[string]string{"x-compute-tokens": "3"}, strings.NewReader(`[[0.1, 0.2]]`), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, "BAAI/bge-large-en-v1.5", responseModel)
+	require.JSONEq(t, `{
+		"object": "list",
+		"model": "BAAI/bge-large-en-v1.5",
+		"data": [{"object": "embedding", "index": 0, "embedding": [0.1, 0.2]}],
+		"usage": {"prompt_tokens": 3, "total_tokens": 3}
+	}`, string(body))
+	inputTokens, _ := tokenUsage.InputTokens()
+	require.Equal(t, uint32(3), inputTokens)
+
+	_, body, err = tr.ResponseError(map[string]string{statusHeaderName: "413"}, strings.NewReader(`{"error":"batch size 64 > maximum allowed batch size 32","error_type":"Validation"}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type":"error","error":{"type":"Validation","message":"batch size 64 > maximum allowed batch size 32","code":"413"}}`, string(body))
+}
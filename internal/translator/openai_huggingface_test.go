@@ -0,0 +1,142 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestOpenAIToHuggingFaceTGITranslatorV1Completion_RequestBody(t *testing.T) {
+	t.Run("full request", func(t *testing.T) {
+		var req openai.CompletionRequest
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"model": "mistralai/Mistral-7B-v0.1",
+			"prompt": "Once upon a time",
+			"max_tokens": 20,
+			"temperature": 0.7,
+			"seed": 42,
+			"stop": ["\n", "END"],
+			"echo": true
+		}`), &req))
+		tr := NewCompletionOpenAIToHuggingFaceTGITranslator("")
+		headers, body, err := tr.RequestBody(nil, &req, false)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{
+			{pathHeaderName, "/generate"},
+			{contentLengthHeaderName, strconv.Itoa(len(body))},
+		}, headers)
+		require.JSONEq(t, `{
+			"inputs": "Once upon a time",
+			"parameters": {
+				"details": true,
+				"do_sample": true,
+				"max_new_tokens": 20,
+				"return_full_text": true,
+				"seed": 42,
+				"stop": ["\n", "END"],
+				"temperature": 0.7
+			}
+		}`, string(body))
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		headers, _, err := NewCompletionOpenAIToHuggingFaceTGITranslator("").RequestBody(nil, &openai.CompletionRequest{
+			Prompt: openai.PromptUnion{Value: []string{"hi"}}, Stream: true,
+		}, false)
+		require.NoError(t, err)
+		require.Equal(t, "/generate_stream", headers[0].Value())
+	})
+
+	t.Run("multiple prompts", func(t *testing.T) {
+		_, _, err := NewCompletionOpenAIToHuggingFaceTGITranslator("").RequestBody(nil, &openai.CompletionRequest{
+			Prompt: openai.PromptUnion{Value: []string{"a", "b"}},
+		}, false)
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+	})
+}
+
+func TestOpenAIToHuggingFaceTGITranslatorV1Completion_ResponseBody(t *testing.T) {
+	tr := NewCompletionOpenAIToHuggingFaceTGITranslator("")
+	_, _, err := tr.RequestBody(nil, &openai.CompletionRequest{Model: "tgi", Prompt: openai.PromptUnion{Value: "hi"}}, false)
+	require.NoError(t, err)
+
+	_, body, tokenUsage, responseModel, err := tr.ResponseBody(map[string]string{"x-prompt-tokens": "3"},
+		strings.NewReader(`{"generated_text": " there", "details": {"finish_reason": "eos_token", "generated_tokens": 2, "tokens": []}}`), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, "tgi", responseModel)
+	var resp openai.CompletionResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	require.Equal(t, "text_completion", resp.Object)
+	require.Equal(t, " there", resp.Choices[0].Text)
+	require.Equal(t, "stop", resp.Choices[0].FinishReason)
+	require.Equal(t, &openai.Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5}, resp.Usage)
+	totalTokens, _ := tokenUsage.TotalTokens()
+	require.Equal(t, uint32(5), totalTokens)
+}
+
+func TestOpenAIToHuggingFaceTGITranslatorV1Completion_ResponseBody_Streaming(t *testing.T) {
+	tr := NewCompletionOpenAIToHuggingFaceTGITranslator("")
+	_, _, err := tr.RequestBody(nil, &openai.CompletionRequest{Model: "tgi", Prompt: openai.PromptUnion{Value: "hi"}, Stream: true}, false)
+	require.NoError(t, err)
+	headers, err := tr.ResponseHeaders(nil)
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{{contentTypeHeaderName, eventStreamContentType}}, headers)
+
+	_, body, _, _, err := tr.ResponseBody(nil, strings.NewReader(
+		`data:{"index":1,"token":{"id":1,"text":" the","logprob":-0.1,"special":false},"generated_text":null,"details":null}`+"\n\n"+
+			`data:{"index":2,"token":{"id":2,"text":"re","log`), false, nil)
+	require.NoError(t, err)
+	chunks := parseCompletionChunks(t, body)
+	require.Len(t, chunks, 1)
+	require.Equal(t, " the", chunks[0].Choices[0].Text)
+
+	_, body, tokenUsage, _, err := tr.ResponseBody(nil, strings.NewReader(
+		`prob":-0.1,"special":false},"generated_text":null,"details":null}`+"\n\n"+
+			`data:{"index":3,"token":{"id":3,"text":"</s>","logprob":-0.1,"special":true},"generated_text":" there","details":{"finish_reason":"length","generated_tokens":3,"input_length":2}}`+"\n\n"), true, nil)
+	require.NoError(t, err)
+	require.True(t, bytes.HasSuffix(body, sseDoneFullLine))
+	chunks = parseCompletionChunks(t, body)
+	require.Len(t, chunks, 3)
+	require.Equal(t, "re", chunks[0].Choices[0].Text)
+	// The special token isn't part of the text.
+	require.Empty(t, chunks[1].Choices[0].Text)
+	require.Equal(t, "length", chunks[1].Choices[0].FinishReason)
+	require.Equal(t, &openai.Usage{PromptTokens: 2, CompletionTokens: 3, TotalTokens: 5}, chunks[2].Usage)
+	inputTokens, _ := tokenUsage.InputTokens()
+	require.Equal(t, uint32(2), inputTokens)
+}
+
+func TestOpenAIToHuggingFaceTGITranslatorV1Completion_ResponseError(t *testing.T) {
+	tr := NewCompletionOpenAIToHuggingFaceTGITranslator("")
+	_, body, err := tr.ResponseError(map[string]string{statusHeaderName: "422"},
+		strings.NewReader(`{"error":"Input validation error: inputs must have less than 4096 tokens","error_type":"validation"}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type":"error","error":{"type":"validation","message":"Input validation error: inputs must have less than 4096 tokens","code":"422"}}`, string(body))
+}
+
+// parseCompletionChunks parses the OpenAI completion chunks of the given server-sent events, ignoring the [DONE] one.
+func parseCompletionChunks(t *testing.T, body []byte) []openai.CompletionResponse {
+	data, _ := sseEventsData(body)
+	var chunks []openai.CompletionResponse
+	for _, d := range data {
+		if bytes.Equal(d, sseDoneMessage) {
+			continue
+		}
+		var chunk openai.CompletionResponse
+		require.NoError(t, json.Unmarshal(d, &chunk))
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
@@ -0,0 +1,458 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/oci"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+const (
+	ociGenAIBackendError = "OCIGenAIBackendError"
+	// ociRequestIDHeaderName is the header of the OCI responses holding the unique ID of the request.
+	ociRequestIDHeaderName = "opc-request-id"
+	// ociDedicatedEndpointOCIDPrefix is the prefix of the OCIDs of the dedicated AI cluster endpoints.
+	ociDedicatedEndpointOCIDPrefix = "ocid1.generativeaiendpoint."
+)
+
+// errOCICompartmentNotSet is returned when the compartment OCID is not set as the version of the OCIGenAI schema.
+var errOCICompartmentNotSet = errors.New("the compartment OCID must be set as the version of the OCIGenAI schema")
+
+// NewChatCompletionOpenAIToOCIGenAITranslator implements [Factory] for OpenAI to OCI Generative AI translation.
+// The compartmentID is the OCID of the compartment the requests are made in.
+func NewChatCompletionOpenAIToOCIGenAITranslator(compartmentID string, modelNameOverride internalapi.ModelNameOverride) OpenAIChatCompletionTranslator {
+	return &openAIToOCIGenAITranslatorV1ChatCompletion{compartmentID: compartmentID, modelNameOverride: modelNameOverride}
+}
+
+// openAIToOCIGenAITranslatorV1ChatCompletion translates OpenAI Chat Completions API to the chat action of the OCI
+// Generative AI inference API in the generic format.
+// https://docs.oracle.com/en-us/iaas/api/#/en/generative-ai-inference/20231130/ChatResult/Chat
+type openAIToOCIGenAITranslatorV1ChatCompletion struct {
+	compartmentID     string
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+	stream            bool
+	// bufferedBody holds the incomplete server-sent event of the streamed response.
+	bufferedBody []byte
+	// created is the time of the first chunk of the streamed response.
+	created time.Time
+	// toolCallIndex is the number of tool calls seen so far in the streamed response.
+	toolCallIndex int64
+}
+
+// ociServingMode returns the serving mode of the given model, which is either the OCID of a dedicated AI cluster
+// endpoint or the name or the OCID of an on-demand model.
+func ociServingMode(model string) oci.ServingMode {
+	if strings.HasPrefix(model, ociDedicatedEndpointOCIDPrefix) {
+		return oci.ServingMode{ServingType: oci.ServingTypeDedicated, EndpointID: model}
+	}
+	return oci.ServingMode{ServingType: oci.ServingTypeOnDemand, ModelID: model}
+}
+
+// RequestBody implements [OpenAIChatCompletionTranslator.RequestBody].
+func (o *openAIToOCIGenAITranslatorV1ChatCompletion) RequestBody(_ []byte, openAIReq *openai.ChatCompletionRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	if o.compartmentID == "" {
+		return nil, nil, errOCICompartmentNotSet
+	}
+	o.requestModel = openAIReq.Model
+	if o.modelNameOverride != "" {
+		o.requestModel = o.modelNameOverride
+	}
+	o.stream = openAIReq.Stream
+
+	chatRequest, err := openAIToOCIChatRequest(openAIReq)
+	if err != nil {
+		return nil, nil, err
+	}
+	newBody, err = json.Marshal(&oci.ChatDetails{
+		CompartmentID: o.compartmentID,
+		ServingMode:   ociServingMode(o.requestModel),
+		ChatRequest:   chatRequest,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, "/" + oci.APIVersion + "/actions/chat"},
+		{contentTypeHeaderName, jsonContentType},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// openAIToOCIChatRequest converts the OpenAI chat completion request to the OCI generic chat request.
+func openAIToOCIChatRequest(openAIReq *openai.ChatCompletionRequest) (*oci.ChatRequest, error) {
+	req := &oci.ChatRequest{
+		APIFormat:           oci.APIFormatGeneric,
+		IsStream:            openAIReq.Stream,
+		NumGenerations:      openAIReq.N,
+		Seed:                openAIReq.Seed,
+		MaxTokens:           openAIReq.MaxTokens,
+		MaxCompletionTokens: openAIReq.MaxCompletionTokens,
+		Temperature:         openAIReq.Temperature,
+		TopP:                openAIReq.TopP,
+		FrequencyPenalty:    openAIReq.FrequencyPenalty,
+		PresencePenalty:     openAIReq.PresencePenalty,
+		ReasoningEffort:     strings.ToUpper(string(openAIReq.ReasoningEffort)),
+		IsParallelToolCalls: openAIReq.ParallelToolCalls,
+	}
+	if openAIReq.Stream {
+		// The usage is always requested to account the tokens of the streamed responses.
+		req.StreamOptions = &oci.StreamOptions{IsIncludeUsage: true}
+	}
+	if openAIReq.Stop.OfString.Valid() {
+		req.Stop = []string{openAIReq.Stop.OfString.String()}
+	} else if openAIReq.Stop.OfStringArray != nil {
+		req.Stop = openAIReq.Stop.OfStringArray
+	}
+
+	for i := range openAIReq.Messages {
+		msg, err := openAIMessageToOCIMessage(&openAIReq.Messages[i])
+		if err != nil {
+			return nil, err
+		}
+		req.Messages = append(req.Messages, msg)
+	}
+
+	for _, tool := range openAIReq.Tools {
+		if tool.Type != openai.ToolTypeFunction || tool.Function == nil {
+			return nil, fmt.Errorf("%w: unsupported tool type %q", internalapi.ErrInvalidRequestBody, tool.Type)
+		}
+		req.Tools = append(req.Tools, oci.ToolDefinition{
+			Type:        oci.ToolTypeFunction,
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+			Parameters:  tool.Function.Parameters,
+		})
+	}
+	if openAIReq.ToolChoice != nil {
+		switch v := openAIReq.ToolChoice.Value.(type) {
+		case string:
+			switch openai.ToolChoiceType(v) {
+			case openai.ToolChoiceTypeAuto:
+				req.ToolChoice = &oci.ToolChoice{Type: oci.ToolChoiceTypeAuto}
+			case openai.ToolChoiceTypeNone:
+				req.ToolChoice = &oci.ToolChoice{Type: oci.ToolChoiceTypeNone}
+			case openai.ToolChoiceTypeRequired:
+				req.ToolChoice = &oci.ToolChoice{Type: oci.ToolChoiceTypeRequired}
+			default:
+				return nil, fmt.Errorf("%w: unsupported tool_choice %q", internalapi.ErrInvalidRequestBody, v)
+			}
+		case openai.ChatCompletionNamedToolChoice:
+			req.ToolChoice = &oci.ToolChoice{Type: oci.ToolChoiceTypeFunction, Name: v.Function.Name}
+		}
+	}
+	return req, nil
+}
+
+// openAIMessageToOCIMessage converts an OpenAI message to an OCI generic chat message.
+func openAIMessageToOCIMessage(msg *openai.ChatCompletionMessageParamUnion) (oci.Message, error) {
+	switch {
+	case msg.OfSystem != nil:
+		return oci.Message{Role: oci.RoleSystem, Content: ociTextContent(textOfContentUnion(msg.OfSystem.Content))}, nil
+	case msg.OfDeveloper != nil:
+		// The DEVELOPER role is not supported by all the models, so SYSTEM is used instead.
+		return oci.Message{Role: oci.RoleSystem, Content: ociTextContent(textOfContentUnion(msg.OfDeveloper.Content))}, nil
+	case msg.OfTool != nil:
+		return oci.Message{
+			Role:       oci.RoleTool,
+			Content:    ociTextContent(textOfContentUnion(msg.OfTool.Content)),
+			ToolCallID: msg.OfTool.ToolCallID,
+		}, nil
+	case msg.OfAssistant != nil:
+		ret := oci.Message{Role: oci.RoleAssistant, Content: ociTextContent(textOfAssistantContent(msg.OfAssistant.Content))}
+		for _, tc := range msg.OfAssistant.ToolCalls {
+			toolCall := oci.ToolCall{Type: oci.ToolTypeFunction, Name: tc.Function.Name, Arguments: tc.Function.Arguments}
+			if tc.ID != nil {
+				toolCall.ID = *tc.ID
+			}
+			ret.ToolCalls = append(ret.ToolCalls, toolCall)
+		}
+		return ret, nil
+	case msg.OfUser != nil:
+		ret := oci.Message{Role: oci.RoleUser, Name: msg.OfUser.Name}
+		switch v := msg.OfUser.Content.Value.(type) {
+		case string:
+			ret.Content = ociTextContent(v)
+		case []openai.ChatCompletionContentPartUserUnionParam:
+			for _, part := range v {
+				switch {
+				case part.OfText != nil:
+					ret.Content = append(ret.Content, oci.Content{Type: oci.ContentTypeText, Text: part.OfText.Text})
+				case part.OfImageURL != nil:
+					ret.Content = append(ret.Content, oci.Content{Type: oci.ContentTypeImage, ImageURL: &oci.ImageURL{
+						URL: part.OfImageURL.ImageURL.URL, Detail: strings.ToUpper(string(part.OfImageURL.ImageURL.Detail)),
+					}})
+				default:
+					return oci.Message{}, fmt.Errorf("%w: only text and image_url content parts are supported by OCI Generative AI", internalapi.ErrInvalidRequestBody)
+				}
+			}
+		}
+		return ret, nil
+	}
+	return oci.Message{}, fmt.Errorf("%w: unsupported message", internalapi.ErrInvalidRequestBody)
+}
+
+// ociTextContent returns the content made of the given text, or nil if the text is empty.
+func ociTextContent(text string) []oci.Content {
+	if text == "" {
+		return nil
+	}
+	return []oci.Content{{Type: oci.ContentTypeText, Text: text}}
+}
+
+// ResponseHeaders implements [OpenAIChatCompletionTranslator.ResponseHeaders].
+func (o *openAIToOCIGenAITranslatorV1ChatCompletion) ResponseHeaders(_ map[string]string) (
+	newHeaders []internalapi.Header, err error,
+) {
+	if o.stream {
+		newHeaders = []internalapi.Header{{contentTypeHeaderName, eventStreamContentType}}
+	}
+	return
+}
+
+// ResponseBody implements [OpenAIChatCompletionTranslator.ResponseBody].
+func (o *openAIToOCIGenAITranslatorV1ChatCompletion) ResponseBody(respHeaders map[string]string, body io.Reader, endOfStream bool, span tracingapi.ChatCompletionSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	if o.stream {
+		return o.handleStreamingResponse(respHeaders, body, endOfStream, span)
+	}
+
+	var result oci.ChatResult
+	if err = json.NewDecoder(body).Decode(&result); err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	responseModel = o.requestModel
+	if result.ModelID != "" {
+		responseModel = result.ModelID
+	}
+	openAIResp := &openai.ChatCompletionResponse{
+		ID:      respHeaders[ociRequestIDHeaderName],
+		Object:  "chat.completion",
+		Model:   responseModel,
+		Created: openai.JSONUNIXTime(time.Now()),
+		Choices: []openai.ChatCompletionResponseChoice{},
+	}
+	if resp := result.ChatResponse; resp != nil {
+		if created, parseErr := time.Parse(time.RFC3339, resp.TimeCreated); parseErr == nil {
+			openAIResp.Created = openai.JSONUNIXTime(created)
+		}
+		for i := range resp.Choices {
+			choice := &resp.Choices[i]
+			message := openai.ChatCompletionResponseChoiceMessage{Role: openai.ChatMessageRoleAssistant}
+			if text := ociMessageText(&choice.Message); text != "" {
+				message.Content = &text
+			}
+			for _, tc := range choice.Message.ToolCalls {
+				message.ToolCalls = append(message.ToolCalls, openai.ChatCompletionMessageToolCallParam{
+					ID:       &tc.ID,
+					Type:     openai.ChatCompletionMessageToolCallTypeFunction,
+					Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: tc.Name, Arguments: tc.Arguments},
+				})
+			}
+			openAIResp.Choices = append(openAIResp.Choices, openai.ChatCompletionResponseChoice{
+				Index:        choice.Index,
+				Message:      message,
+				FinishReason: ociFinishReasonToOpenAI(choice.FinishReason),
+			})
+		}
+		if resp.Usage != nil {
+			openAIResp.Usage = ociUsageToOpenAIUsage(resp.Usage)
+			tokenUsage = tokenUsageFromOpenAIUsage(&openAIResp.Usage)
+		}
+	}
+
+	newBody, err = json.Marshal(openAIResp)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(openAIResp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// handleStreamingResponse converts the server-sent events of the OCI streamed chat response to OpenAI chunks.
+func (o *openAIToOCIGenAITranslatorV1ChatCompletion) handleStreamingResponse(respHeaders map[string]string, body io.Reader, endOfStream bool, span tracingapi.ChatCompletionSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	responseModel = o.requestModel
+	buf, err := io.ReadAll(io.MultiReader(bytes.NewReader(o.bufferedBody), body))
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to read body: %w", err)
+	}
+	if o.created.IsZero() {
+		o.created = time.Now()
+	}
+	var events [][]byte
+	events, o.bufferedBody = sseEventsData(buf)
+	for _, data := range events {
+		// Skip the [DONE] marker, the OpenAI one is added at the end of the stream.
+		if bytes.Equal(data, sseDoneMessage) {
+			continue
+		}
+		var event oci.StreamEvent
+		if err = json.Unmarshal(data, &event); err != nil {
+			return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal stream event: %w", err)
+		}
+		chunk := &openai.ChatCompletionResponseChunk{
+			ID:      respHeaders[ociRequestIDHeaderName],
+			Object:  "chat.completion.chunk",
+			Created: openai.JSONUNIXTime(o.created),
+			Model:   o.requestModel,
+			Choices: []openai.ChatCompletionResponseChunkChoice{},
+		}
+		if event.Message != nil || event.FinishReason != "" {
+			choice := openai.ChatCompletionResponseChunkChoice{Index: event.Index}
+			if event.Message != nil {
+				delta := &openai.ChatCompletionResponseChunkChoiceDelta{Role: openai.ChatMessageRoleAssistant}
+				if text := ociMessageText(event.Message); text != "" {
+					delta.Content = &text
+				}
+				delta.ToolCalls = o.toolCallDeltas(event.Message.ToolCalls)
+				choice.Delta = delta
+			}
+			if event.FinishReason != "" {
+				choice.FinishReason = ociFinishReasonToOpenAI(event.FinishReason)
+			}
+			chunk.Choices = append(chunk.Choices, choice)
+		}
+		if event.Usage != nil {
+			usage := ociUsageToOpenAIUsage(event.Usage)
+			chunk.Usage = &usage
+			tokenUsage = tokenUsageFromOpenAIUsage(&usage)
+		}
+		if len(chunk.Choices) == 0 && chunk.Usage == nil {
+			continue
+		}
+		if err = serializeOpenAIChatCompletionChunk(chunk, &newBody); err != nil {
+			return nil, nil, tokenUsage, "", err
+		}
+		if span != nil {
+			span.RecordResponseChunk(chunk)
+		}
+	}
+
+	if endOfStream {
+		newBody = append(newBody, sseDoneFullLine...)
+	}
+	// Return an empty body rather than nil so that Envoy doesn't pass through the original OCI events.
+	if newBody == nil {
+		newBody = []byte{}
+	}
+	return
+}
+
+// toolCallDeltas converts the tool calls of a streamed message to OpenAI tool call deltas. A tool call with an ID
+// starts a new tool call, while the ones without are the continuation of the arguments of the last one.
+func (o *openAIToOCIGenAITranslatorV1ChatCompletion) toolCallDeltas(toolCalls []oci.ToolCall) []openai.ChatCompletionChunkChoiceDeltaToolCall {
+	var deltas []openai.ChatCompletionChunkChoiceDeltaToolCall
+	for _, tc := range toolCalls {
+		delta := openai.ChatCompletionChunkChoiceDeltaToolCall{
+			Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: tc.Name, Arguments: tc.Arguments},
+		}
+		if tc.ID != "" {
+			id := tc.ID
+			delta.ID = &id
+			delta.Type = openai.ChatCompletionMessageToolCallTypeFunction
+			o.toolCallIndex++
+		}
+		delta.Index = max(o.toolCallIndex-1, 0)
+		deltas = append(deltas, delta)
+	}
+	return deltas
+}
+
+// ociMessageText returns the concatenated text of the contents of the given message.
+func ociMessageText(msg *oci.Message) string {
+	var b strings.Builder
+	for _, c := range msg.Content {
+		if c.Type == oci.ContentTypeText {
+			b.WriteString(c.Text)
+		}
+	}
+	return b.String()
+}
+
+// ociFinishReasonToOpenAI converts the finish reason of OCI, which depends on the model provider, to OpenAI's.
+func ociFinishReasonToOpenAI(reason string) openai.ChatCompletionChoicesFinishReason {
+	switch strings.ToLower(reason) {
+	case "stop", "complete", "end_turn":
+		return openai.ChatCompletionChoicesFinishReasonStop
+	case "length", "max_tokens":
+		return openai.ChatCompletionChoicesFinishReasonLength
+	case "tool_calls", "tool_call":
+		return openai.ChatCompletionChoicesFinishReasonToolCalls
+	case "content_filter", "error_toxic":
+		return openai.ChatCompletionChoicesFinishReasonContentFilter
+	}
+	return openai.ChatCompletionChoicesFinishReason(strings.ToLower(reason))
+}
+
+// ociUsageToOpenAIUsage converts the OCI usage to OpenAI's.
+func ociUsageToOpenAIUsage(usage *oci.Usage) openai.Usage {
+	ret := openai.Usage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
+	if usage.PromptTokensDetails != nil {
+		ret.PromptTokensDetails = &openai.PromptTokensDetails{CachedTokens: usage.PromptTokensDetails.CachedTokens}
+	}
+	if usage.CompletionTokensDetails != nil {
+		ret.CompletionTokensDetails = &openai.CompletionTokensDetails{ReasoningTokens: usage.CompletionTokensDetails.ReasoningTokens}
+	}
+	return ret
+}
+
+// ResponseError implements [OpenAIChatCompletionTranslator.ResponseError].
+func (o *openAIToOCIGenAITranslatorV1ChatCompletion) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	return convertOCIGenAIErrorToOpenAI(respHeaders, body)
+}
+
+// convertOCIGenAIErrorToOpenAI converts the OCI error responses to the OpenAI error format.
+// This is shared by the chat completion and the embedding translators.
+func convertOCIGenAIErrorToOpenAI(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	buf, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read error body: %w", err)
+	}
+	statusCode := respHeaders[statusHeaderName]
+	openAIErr := openai.Error{
+		Type:  "error",
+		Error: openai.ErrorType{Type: ociGenAIBackendError, Message: string(buf), Code: &statusCode},
+	}
+	var ociErr oci.Error
+	if json.Unmarshal(buf, &ociErr) == nil && ociErr.Message != "" {
+		openAIErr.Error.Type = ociErr.Code
+		openAIErr.Error.Message = ociErr.Message
+	}
+	newBody, err = json.Marshal(openAIErr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal error body: %w", err)
+	}
+	return buildHeaders(newBody), newBody, nil
+}
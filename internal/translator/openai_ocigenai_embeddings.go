@@ -0,0 +1,132 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/oci"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewEmbeddingOpenAIToOCIGenAITranslator implements [Factory] for OpenAI to OCI Generative AI embedding translation.
+// The compartmentID is the OCID of the compartment the requests are made in.
+func NewEmbeddingOpenAIToOCIGenAITranslator(compartmentID string, modelNameOverride internalapi.ModelNameOverride) OpenAIEmbeddingTranslator {
+	return &openAIToOCIGenAITranslatorV1Embedding{compartmentID: compartmentID, modelNameOverride: modelNameOverride}
+}
+
+// openAIToOCIGenAITranslatorV1Embedding translates OpenAI embedding requests to the embedText action of the OCI
+// Generative AI inference API.
+// https://docs.oracle.com/en-us/iaas/api/#/en/generative-ai-inference/20231130/EmbedTextResult/EmbedText
+type openAIToOCIGenAITranslatorV1Embedding struct {
+	compartmentID     string
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+}
+
+// RequestBody implements [OpenAIEmbeddingTranslator.RequestBody].
+func (o *openAIToOCIGenAITranslatorV1Embedding) RequestBody(_ []byte, req *openai.EmbeddingRequest, _ bool) (
+	newHeaders []internalapi.Header, mutatedBody []byte, err error,
+) {
+	if o.compartmentID == "" {
+		return nil, nil, errOCICompartmentNotSet
+	}
+	o.requestModel = req.Model
+	if o.modelNameOverride != "" {
+		o.requestModel = o.modelNameOverride
+	}
+	inputs, err := embeddingInputTexts(req, "OCI Generative AI")
+	if err != nil {
+		return nil, nil, err
+	}
+	mutatedBody, err = json.Marshal(&oci.EmbedTextDetails{
+		CompartmentID:    o.compartmentID,
+		ServingMode:      ociServingMode(o.requestModel),
+		Inputs:           inputs,
+		OutputDimensions: req.Dimensions,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, "/" + oci.APIVersion + "/actions/embedText"},
+		{contentTypeHeaderName, jsonContentType},
+		{contentLengthHeaderName, strconv.Itoa(len(mutatedBody))},
+	}
+	return
+}
+
+// embeddingInputTexts returns the texts of the input-based embedding request, which are the only ones supported by
+// the given provider.
+func embeddingInputTexts(req *openai.EmbeddingRequest, provider string) ([]string, error) {
+	if req.OfCompletion == nil {
+		return nil, fmt.Errorf("%w: %s requires an input-based embedding request (messages not supported)", internalapi.ErrInvalidRequestBody, provider)
+	}
+	switch v := req.OfCompletion.Input.Value.(type) {
+	case string:
+		return []string{v}, nil
+	case []string:
+		return v, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported input type %T", internalapi.ErrInvalidRequestBody, req.OfCompletion.Input.Value)
+}
+
+// ResponseHeaders implements [OpenAIEmbeddingTranslator.ResponseHeaders].
+func (o *openAIToOCIGenAITranslatorV1Embedding) ResponseHeaders(_ map[string]string) (
+	newHeaders []internalapi.Header, err error,
+) {
+	return nil, nil
+}
+
+// ResponseBody implements [OpenAIEmbeddingTranslator.ResponseBody].
+func (o *openAIToOCIGenAITranslatorV1Embedding) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.EmbeddingsSpan) (
+	newHeaders []internalapi.Header, mutatedBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	var result oci.EmbedTextResult
+	if err = json.NewDecoder(body).Decode(&result); err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	responseModel = o.requestModel
+	if result.ModelID != "" {
+		responseModel = result.ModelID
+	}
+	openAIResp := openai.EmbeddingResponse{Object: "list", Model: responseModel, Data: make([]openai.Embedding, 0, len(result.Embeddings))}
+	for i, embedding := range result.Embeddings {
+		openAIResp.Data = append(openAIResp.Data, openai.Embedding{
+			Object:    "embedding",
+			Index:     i,
+			Embedding: openai.EmbeddingUnion{Value: embedding},
+		})
+	}
+	if result.Usage != nil {
+		openAIResp.Usage = openai.EmbeddingUsage{PromptTokens: result.Usage.PromptTokens, TotalTokens: result.Usage.TotalTokens}
+		tokenUsage.SetInputTokens(uint32(result.Usage.PromptTokens)) //nolint:gosec
+		tokenUsage.SetTotalTokens(uint32(result.Usage.TotalTokens))  //nolint:gosec
+	}
+
+	mutatedBody, err = json.Marshal(openAIResp)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(&openAIResp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(mutatedBody))}}
+	return
+}
+
+// ResponseError implements [OpenAIEmbeddingTranslator.ResponseError].
+func (o *openAIToOCIGenAITranslatorV1Embedding) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, mutatedBody []byte, err error,
+) {
+	return convertOCIGenAIErrorToOpenAI(respHeaders, body)
+}
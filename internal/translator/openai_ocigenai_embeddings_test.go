@@ -0,0 +1,68 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+func TestOpenAIToOCIGenAITranslatorV1Embedding_RequestBody(t *testing.T) {
+	tr := NewEmbeddingOpenAIToOCIGenAITranslator(testOCICompartmentID, "")
+	headers, body, err := tr.RequestBody(nil, &openai.EmbeddingRequest{
+		EmbeddingBaseRequest: openai.EmbeddingBaseRequest{Model: "cohere.embed-v4.0", Dimensions: ptr.To(256)},
+		OfCompletion: &openai.EmbeddingCompletionRequest{
+			Input: openai.EmbeddingRequestInput{Value: []string{"hello", "world"}},
+		},
+	}, false)
+	require.NoError(t, err)
+	require.Equal(t, "/20231130/actions/embedText", headers[0].Value())
+	require.JSONEq(t, `{
+		"compartmentId": "ocid1.compartment.oc1..aaaa",
+		"servingMode": {"servingType": "ON_DEMAND", "modelId": "cohere.embed-v4.0"},
+		"inputs": ["hello", "world"],
+		"outputDimensions": 256
+	}`, string(body))
+
+	_, _, err = tr.RequestBody(nil, &openai.EmbeddingRequest{OfChat: &openai.EmbeddingChatRequest{}}, false)
+	require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+	_, _, err = NewEmbeddingOpenAIToOCIGenAITranslator("", "").RequestBody(nil, &openai.EmbeddingRequest{}, false)
+	require.ErrorIs(t, err, errOCICompartmentNotSet)
+}
+
+func TestOpenAIToOCIGenAITranslatorV1Embedding_ResponseBody(t *testing.T) {
+	tr := NewEmbeddingOpenAIToOCIGenAITranslator(testOCICompartmentID, "")
+	_, _, err := tr.RequestBody(nil, &openai.EmbeddingRequest{
+		EmbeddingBaseRequest: openai.EmbeddingBaseRequest{Model: "cohere.embed-v4.0"},
+		OfCompletion:         &openai.EmbeddingCompletionRequest{Input: openai.EmbeddingRequestInput{Value: "hello"}},
+	}, false)
+	require.NoError(t, err)
+
+	_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(`{
+		"id": "id-1", "modelId": "cohere.embed-v4.0", "embeddings": [[0.1, 0.2], [0.3, 0.4]],
+		"usage": {"promptTokens": 4, "totalTokens": 4}
+	}`), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, "cohere.embed-v4.0", responseModel)
+	require.JSONEq(t, `{
+		"object": "list",
+		"model": "cohere.embed-v4.0",
+		"data": [
+			{"object": "embedding", "index": 0, "embedding": [0.1, 0.2]},
+			{"object": "embedding", "index": 1, "embedding": [0.3, 0.4]}
+		],
+		"usage": {"prompt_tokens": 4, "total_tokens": 4}
+	}`, string(body))
+	inputTokens, ok := tokenUsage.InputTokens()
+	require.True(t, ok)
+	require.Equal(t, uint32(4), inputTokens)
+}
@@ -0,0 +1,196 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+const testOCICompartmentID = "ocid1.compartment.oc1..aaaa"
+
+func TestOpenAIToOCIGenAITranslatorV1ChatCompletion_RequestBody(t *testing.T) {
+	t.Run("full request", func(t *testing.T) {
+		var req openai.ChatCompletionRequest
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"model": "meta.llama-3.3-70b-instruct",
+			"messages": [
+				{"role": "system", "content": "You are helpful."},
+				{"role": "user", "content": [{"type": "text", "text": "What is in this image?"}, {"type": "image_url", "image_url": {"url": "data:image/png;base64,AAAA", "detail": "low"}}]},
+				{"role": "assistant", "content": "", "tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": "{\"city\":\"Tokyo\"}"}}]},
+				{"role": "tool", "tool_call_id": "call_1", "content": "sunny"}
+			],
+			"max_tokens": 100,
+			"temperature": 0.5,
+			"stop": "END",
+			"stream": true,
+			"tools": [{"type": "function", "function": {"name": "get_weather", "parameters": {"type": "object"}}}],
+			"tool_choice": {"type": "function", "function": {"name": "get_weather"}}
+		}`), &req))
+
+		tr := NewChatCompletionOpenAIToOCIGenAITranslator(testOCICompartmentID, "")
+		headers, body, err := tr.RequestBody(nil, &req, false)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{
+			{pathHeaderName, "/20231130/actions/chat"},
+			{contentTypeHeaderName, jsonContentType},
+			{contentLengthHeaderName, strconv.Itoa(len(body))},
+		}, headers)
+		require.JSONEq(t, `{
+			"compartmentId": "ocid1.compartment.oc1..aaaa",
+			"servingMode": {"servingType": "ON_DEMAND", "modelId": "meta.llama-3.3-70b-instruct"},
+			"chatRequest": {
+				"apiFormat": "GENERIC",
+				"messages": [
+					{"role": "SYSTEM", "content": [{"type": "TEXT", "text": "You are helpful."}]},
+					{"role": "USER", "content": [{"type": "TEXT", "text": "What is in this image?"}, {"type": "IMAGE", "imageUrl": {"url": "data:image/png;base64,AAAA", "detail": "LOW"}}]},
+					{"role": "ASSISTANT", "toolCalls": [{"id": "call_1", "type": "FUNCTION", "name": "get_weather", "arguments": "{\"city\":\"Tokyo\"}"}]},
+					{"role": "TOOL", "toolCallId": "call_1", "content": [{"type": "TEXT", "text": "sunny"}]}
+				],
+				"isStream": true,
+				"streamOptions": {"isIncludeUsage": true},
+				"maxTokens": 100,
+				"temperature": 0.5,
+				"stop": ["END"],
+				"tools": [{"type": "FUNCTION", "name": "get_weather", "parameters": {"type": "object"}}],
+				"toolChoice": {"type": "FUNCTION", "name": "get_weather"}
+			}
+		}`, string(body))
+	})
+
+	t.Run("dedicated endpoint", func(t *testing.T) {
+		tr := NewChatCompletionOpenAIToOCIGenAITranslator(testOCICompartmentID, "ocid1.generativeaiendpoint.oc1.us-chicago-1.aaaa")
+		_, body, err := tr.RequestBody(nil, &openai.ChatCompletionRequest{Model: "llama"}, false)
+		require.NoError(t, err)
+		require.Contains(t, string(body), `"servingMode":{"servingType":"DEDICATED","endpointId":"ocid1.generativeaiendpoint.oc1.us-chicago-1.aaaa"}`)
+	})
+
+	t.Run("compartment not set", func(t *testing.T) {
+		_, _, err := NewChatCompletionOpenAIToOCIGenAITranslator("", "").RequestBody(nil, &openai.ChatCompletionRequest{}, false)
+		require.ErrorIs(t, err, errOCICompartmentNotSet)
+	})
+
+	t.Run("unsupported content", func(t *testing.T) {
+		var req openai.ChatCompletionRequest
+		require.NoError(t, json.Unmarshal([]byte(`{"model": "m", "messages": [{"role": "user", "content": [{"type": "input_audio", "input_audio": {"data": "AAAA", "format": "wav"}}]}]}`), &req))
+		_, _, err := NewChatCompletionOpenAIToOCIGenAITranslator(testOCICompartmentID, "").RequestBody(nil, &req, false)
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+	})
+}
+
+func TestOpenAIToOCIGenAITranslatorV1ChatCompletion_ResponseBody(t *testing.T) {
+	tr := NewChatCompletionOpenAIToOCIGenAITranslator(testOCICompartmentID, "")
+	_, _, err := tr.RequestBody(nil, &openai.ChatCompletionRequest{Model: "meta.llama-3.3-70b-instruct"}, false)
+	require.NoError(t, err)
+
+	headers, body, tokenUsage, responseModel, err := tr.ResponseBody(map[string]string{"opc-request-id": "req-1"}, strings.NewReader(`{
+		"modelId": "meta.llama-3.3-70b-instruct",
+		"modelVersion": "1.0.0",
+		"chatResponse": {
+			"apiFormat": "GENERIC",
+			"timeCreated": "2026-01-02T03:04:05.000Z",
+			"choices": [
+				{"index": 0, "message": {"role": "ASSISTANT", "content": [{"type": "TEXT", "text": "Hello"}, {"type": "TEXT", "text": "!"}]}, "finishReason": "stop"},
+				{"index": 1, "message": {"role": "ASSISTANT", "toolCalls": [{"id": "call_1", "type": "FUNCTION", "name": "get_weather", "arguments": "{}"}]}, "finishReason": "tool_calls"}
+			],
+			"usage": {"promptTokens": 10, "completionTokens": 5, "totalTokens": 15, "promptTokensDetails": {"cachedTokens": 2}}
+		}
+	}`), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(body))}}, headers)
+	require.Equal(t, "meta.llama-3.3-70b-instruct", responseModel)
+	require.JSONEq(t, `{
+		"id": "req-1",
+		"object": "chat.completion",
+		"model": "meta.llama-3.3-70b-instruct",
+		"created": 1767323045,
+		"choices": [
+			{"index": 0, "message": {"role": "assistant", "content": "Hello!"}, "finish_reason": "stop"},
+			{"index": 1, "message": {"role": "assistant", "tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": "{}"}}]}, "finish_reason": "tool_calls"}
+		],
+		"usage": {"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15, "prompt_tokens_details": {"cached_tokens": 2}}
+	}`, string(body))
+	inputTokens, _ := tokenUsage.InputTokens()
+	outputTokens, _ := tokenUsage.OutputTokens()
+	cachedTokens, _ := tokenUsage.CachedInputTokens()
+	require.Equal(t, [3]uint32{10, 5, 2}, [3]uint32{inputTokens, outputTokens, cachedTokens})
+}
+
+func TestOpenAIToOCIGenAITranslatorV1ChatCompletion_ResponseBody_Streaming(t *testing.T) {
+	tr := NewChatCompletionOpenAIToOCIGenAITranslator(testOCICompartmentID, "")
+	_, _, err := tr.RequestBody(nil, &openai.ChatCompletionRequest{Model: "meta.llama-3.3-70b-instruct", Stream: true}, false)
+	require.NoError(t, err)
+	headers, err := tr.ResponseHeaders(nil)
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{{contentTypeHeaderName, eventStreamContentType}}, headers)
+
+	respHeaders := map[string]string{"opc-request-id": "req-1"}
+	// The second event is split across the calls.
+	_, body, _, _, err := tr.ResponseBody(respHeaders, strings.NewReader(
+		`data: {"index":0,"message":{"role":"ASSISTANT","content":[{"type":"TEXT","text":"Hel"}]}}`+"\n\n"+
+			`data: {"index":0,"message":{"role":"ASSISTANT","content":[{"type":"TE`), false, nil)
+	require.NoError(t, err)
+	chunks := parseOpenAIChunks(t, body)
+	require.Len(t, chunks, 1)
+	require.Equal(t, "Hel", *chunks[0].Choices[0].Delta.Content)
+	require.Equal(t, "req-1", chunks[0].ID)
+
+	_, body, tokenUsage, _, err := tr.ResponseBody(respHeaders, strings.NewReader(
+		`XT","text":"lo"}],"toolCalls":[{"id":"call_1","type":"FUNCTION","name":"get_weather","arguments":""}]}}`+"\n\n"+
+			`data: {"index":0,"message":{"role":"ASSISTANT","toolCalls":[{"arguments":"{}"}]}}`+"\n\n"+
+			`data: {"index":0,"finishReason":"tool_calls"}`+"\n\n"+
+			`data: {"usage":{"promptTokens":10,"completionTokens":5,"totalTokens":15}}`+"\n\n"+
+			`data: [DONE]`+"\n\n"), true, nil)
+	require.NoError(t, err)
+	require.True(t, bytes.HasSuffix(body, sseDoneFullLine))
+	// The [DONE] marker of OCI is skipped rather than translated.
+	require.Equal(t, 1, bytes.Count(body, sseDoneMessage))
+	chunks = parseOpenAIChunks(t, body)
+	require.Len(t, chunks, 4)
+	require.Equal(t, "lo", *chunks[0].Choices[0].Delta.Content)
+	require.Equal(t, "call_1", *chunks[0].Choices[0].Delta.ToolCalls[0].ID)
+	require.Equal(t, int64(0), chunks[1].Choices[0].Delta.ToolCalls[0].Index)
+	require.Equal(t, "{}", chunks[1].Choices[0].Delta.ToolCalls[0].Function.Arguments)
+	require.Equal(t, openai.ChatCompletionChoicesFinishReasonToolCalls, chunks[2].Choices[0].FinishReason)
+	require.Equal(t, 15, chunks[3].Usage.TotalTokens)
+	totalTokens, _ := tokenUsage.TotalTokens()
+	require.Equal(t, uint32(15), totalTokens)
+}
+
+func TestOpenAIToOCIGenAITranslatorV1ChatCompletion_ResponseError(t *testing.T) {
+	tr := NewChatCompletionOpenAIToOCIGenAITranslator(testOCICompartmentID, "")
+	_, body, err := tr.ResponseError(map[string]string{statusHeaderName: "401"},
+		strings.NewReader(`{"code":"NotAuthenticated","message":"The required information to complete authentication was not provided."}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type":"error","error":{"type":"NotAuthenticated","message":"The required information to complete authentication was not provided.","code":"401"}}`, string(body))
+
+	_, body, err = tr.ResponseError(map[string]string{statusHeaderName: "503"}, strings.NewReader("upstream unavailable"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type":"error","error":{"type":"OCIGenAIBackendError","message":"upstream unavailable","code":"503"}}`, string(body))
+}
+
+// parseOpenAIChunks parses the OpenAI chunks of the given server-sent events, ignoring the [DONE] one.
+func parseOpenAIChunks(t *testing.T, body []byte) []openai.ChatCompletionResponseChunk {
+	data, _ := sseEventsData(body)
+	var chunks []openai.ChatCompletionResponseChunk
+	for _, d := range data {
+		if bytes.Equal(d, sseDoneMessage) {
+			continue
+		}
+		var chunk openai.ChatCompletionResponseChunk
+		require.NoError(t, json.Unmarshal(d, &chunk))
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
@@ -0,0 +1,485 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tidwall/gjson"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/ollama"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+const ollamaBackendError = "OllamaBackendError"
+
+// NewChatCompletionOpenAIToOllamaTranslator implements [Factory] for OpenAI to Ollama translation.
+func NewChatCompletionOpenAIToOllamaTranslator(modelNameOverride internalapi.ModelNameOverride) OpenAIChatCompletionTranslator {
+	return &openAIToOllamaTranslatorV1ChatCompletion{modelNameOverride: modelNameOverride}
+}
+
+// openAIToOllamaTranslatorV1ChatCompletion translates OpenAI Chat Completions API to the native chat API of Ollama.
+// https://github.com/ollama/ollama/blob/main/docs/api.md#generate-a-chat-completion
+type openAIToOllamaTranslatorV1ChatCompletion struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+	stream            bool
+	// bufferedBody holds the incomplete line of the streamed response.
+	bufferedBody []byte
+	// id and created are the ID and the time of the streamed response shared by all the chunks.
+	id      string
+	created time.Time
+	// toolCallIndex is the index of the next tool call in the streamed response.
+	toolCallIndex int64
+}
+
+// RequestBody implements [OpenAIChatCompletionTranslator.RequestBody].
+func (o *openAIToOllamaTranslatorV1ChatCompletion) RequestBody(_ []byte, openAIReq *openai.ChatCompletionRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.requestModel = openAIReq.Model
+	if o.modelNameOverride != "" {
+		o.requestModel = o.modelNameOverride
+	}
+	o.stream = openAIReq.Stream
+
+	req, err := openAIToOllamaChatRequest(openAIReq)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Model = o.requestModel
+	newBody, err = json.Marshal(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, ollama.ChatPath},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// openAIToOllamaChatRequest converts the OpenAI request to the Ollama chat request except for the model.
+func openAIToOllamaChatRequest(openAIReq *openai.ChatCompletionRequest) (*ollama.ChatRequest, error) {
+	// Ollama streams the response unless stream is explicitly set to false.
+	req := &ollama.ChatRequest{Stream: &openAIReq.Stream}
+
+	// Ollama identifies the tool call a tool message responds to by the name of the tool rather than the ID.
+	toolNames := make(map[string]string)
+	for i := range openAIReq.Messages {
+		msg := &openAIReq.Messages[i]
+		if msg.OfAssistant != nil {
+			for _, tc := range msg.OfAssistant.ToolCalls {
+				if tc.ID != nil {
+					toolNames[*tc.ID] = tc.Function.Name
+				}
+			}
+		}
+		ollamaMsg, err := openAIMessageToOllamaMessage(msg, toolNames)
+		if err != nil {
+			return nil, err
+		}
+		req.Messages = append(req.Messages, ollamaMsg)
+	}
+
+	// Ollama doesn't support the tool choice, so the tools are omitted when the model must not call any tool.
+	if choice, ok := toolChoiceString(openAIReq.ToolChoice); !ok || choice != string(openai.ToolChoiceTypeNone) {
+		for _, tool := range openAIReq.Tools {
+			if tool.Type != openai.ToolTypeFunction || tool.Function == nil {
+				return nil, fmt.Errorf("%w: only function tools are supported by Ollama", internalapi.ErrInvalidRequestBody)
+			}
+			ollamaTool := ollama.Tool{
+				Type:     string(openai.ToolTypeFunction),
+				Function: ollama.ToolFunction{Name: tool.Function.Name, Description: tool.Function.Description},
+			}
+			if tool.Function.Parameters != nil {
+				params, err := json.Marshal(tool.Function.Parameters)
+				if err != nil {
+					return nil, fmt.Errorf("failed to marshal tool parameters: %w", err)
+				}
+				ollamaTool.Function.Parameters = params
+			}
+			req.Tools = append(req.Tools, ollamaTool)
+		}
+	}
+
+	if rf := openAIReq.ResponseFormat; rf != nil {
+		switch {
+		case rf.OfJSONObject != nil:
+			req.Format = json.RawMessage(`"json"`)
+		case rf.OfJSONSchema != nil:
+			req.Format = rf.OfJSONSchema.JSONSchema.Schema
+		}
+	}
+
+	switch {
+	case openAIReq.Thinking != nil && openAIReq.Thinking.OfDisabled != nil:
+		req.Think = json.RawMessage("false")
+	case openAIReq.Thinking != nil:
+		req.Think = json.RawMessage("true")
+	case openAIReq.ReasoningEffort == openai.ReasoningEffortNone:
+		req.Think = json.RawMessage("false")
+	case openAIReq.ReasoningEffort != "":
+		req.Think = json.RawMessage("true")
+	}
+
+	req.Options = openAIToOllamaOptions(openAIReq)
+	if openAIReq.OllamaVendorFields != nil {
+		req.KeepAlive = openAIReq.KeepAlive
+	}
+	return req, nil
+}
+
+// toolChoiceString returns the tool choice if it is one of "none", "auto" or "required".
+func toolChoiceString(choice *openai.ChatCompletionToolChoiceUnion) (string, bool) {
+	if choice == nil {
+		return "", false
+	}
+	s, ok := choice.Value.(string)
+	return s, ok
+}
+
+// openAIToOllamaOptions converts the generation parameters of the OpenAI request to the Ollama options.
+// The options in the Ollama vendor fields take precedence over the translated ones.
+func openAIToOllamaOptions(openAIReq *openai.ChatCompletionRequest) map[string]any {
+	options := make(map[string]any)
+	if openAIReq.Temperature != nil {
+		options["temperature"] = *openAIReq.Temperature
+	}
+	if openAIReq.TopP != nil {
+		options["top_p"] = *openAIReq.TopP
+	}
+	if openAIReq.Seed != nil {
+		options["seed"] = *openAIReq.Seed
+	}
+	if openAIReq.MaxCompletionTokens != nil {
+		options["num_predict"] = *openAIReq.MaxCompletionTokens
+	} else if openAIReq.MaxTokens != nil {
+		options["num_predict"] = *openAIReq.MaxTokens
+	}
+	if openAIReq.FrequencyPenalty != nil {
+		options["frequency_penalty"] = *openAIReq.FrequencyPenalty
+	}
+	if openAIReq.PresencePenalty != nil {
+		options["presence_penalty"] = *openAIReq.PresencePenalty
+	}
+	if openAIReq.Stop.OfString.Valid() {
+		options["stop"] = []string{openAIReq.Stop.OfString.String()}
+	} else if openAIReq.Stop.OfStringArray != nil {
+		options["stop"] = openAIReq.Stop.OfStringArray
+	}
+	if openAIReq.OllamaVendorFields != nil {
+		for k, v := range openAIReq.Options {
+			options[k] = v
+		}
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	return options
+}
+
+// openAIMessageToOllamaMessage converts an OpenAI message to an Ollama message.
+// toolNames maps the IDs of the preceding tool calls to the names of the tools.
+func openAIMessageToOllamaMessage(msg *openai.ChatCompletionMessageParamUnion, toolNames map[string]string) (ollama.Message, error) {
+	switch {
+	case msg.OfSystem != nil:
+		return ollama.Message{Role: ollama.RoleSystem, Content: textOfContentUnion(msg.OfSystem.Content)}, nil
+	case msg.OfDeveloper != nil:
+		return ollama.Message{Role: ollama.RoleSystem, Content: textOfContentUnion(msg.OfDeveloper.Content)}, nil
+	case msg.OfTool != nil:
+		return ollama.Message{
+			Role:     ollama.RoleTool,
+			Content:  textOfContentUnion(msg.OfTool.Content),
+			ToolName: toolNames[msg.OfTool.ToolCallID],
+		}, nil
+	case msg.OfAssistant != nil:
+		ret := ollama.Message{Role: ollama.RoleAssistant, Content: textOfAssistantContent(msg.OfAssistant.Content)}
+		for i, tc := range msg.OfAssistant.ToolCalls {
+			args := json.RawMessage(tc.Function.Arguments)
+			if len(args) == 0 {
+				args = json.RawMessage("{}")
+			} else if !gjson.ValidBytes(args) {
+				return ollama.Message{}, fmt.Errorf("%w: the arguments of the tool call %q are not valid JSON", internalapi.ErrInvalidRequestBody, tc.Function.Name)
+			}
+			ret.ToolCalls = append(ret.ToolCalls, ollama.ToolCall{
+				Function: ollama.ToolCallFunction{Index: i, Name: tc.Function.Name, Arguments: args},
+			})
+		}
+		return ret, nil
+	case msg.OfUser != nil:
+		ret := ollama.Message{Role: ollama.RoleUser}
+		switch v := msg.OfUser.Content.Value.(type) {
+		case string:
+			ret.Content = v
+		case []openai.ChatCompletionContentPartUserUnionParam:
+			var b strings.Builder
+			for _, part := range v {
+				switch {
+				case part.OfText != nil:
+					b.WriteString(part.OfText.Text)
+				case part.OfImageURL != nil:
+					// Ollama only accepts the base64 encoded images without the data URI prefix.
+					_, data, err := parseDataURI(part.OfImageURL.ImageURL.URL)
+					if err != nil {
+						return ollama.Message{}, fmt.Errorf("%w: only base64 encoded data URI images are supported by Ollama", internalapi.ErrInvalidRequestBody)
+					}
+					ret.Images = append(ret.Images, base64.StdEncoding.EncodeToString(data))
+				default:
+					return ollama.Message{}, fmt.Errorf("%w: only text and image content parts are supported by Ollama", internalapi.ErrInvalidRequestBody)
+				}
+			}
+			ret.Content = b.String()
+		}
+		return ret, nil
+	}
+	return ollama.Message{}, fmt.Errorf("%w: unsupported message", internalapi.ErrInvalidRequestBody)
+}
+
+// ResponseHeaders implements [OpenAIChatCompletionTranslator.ResponseHeaders].
+func (o *openAIToOllamaTranslatorV1ChatCompletion) ResponseHeaders(_ map[string]string) (
+	newHeaders []internalapi.Header, err error,
+) {
+	if o.stream {
+		newHeaders = []internalapi.Header{{contentTypeHeaderName, eventStreamContentType}}
+	}
+	return
+}
+
+// ResponseBody implements [OpenAIChatCompletionTranslator.ResponseBody].
+func (o *openAIToOllamaTranslatorV1ChatCompletion) ResponseBody(_ map[string]string, body io.Reader, endOfStream bool, span tracingapi.ChatCompletionSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	if o.stream {
+		return o.handleStreamingResponse(body, endOfStream, span)
+	}
+
+	var resp ollama.ChatResponse
+	if err = json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	responseModel = o.requestModel
+	if resp.Model != "" {
+		responseModel = resp.Model
+	}
+	message := openai.ChatCompletionResponseChoiceMessage{Role: openai.ChatMessageRoleAssistant}
+	if resp.Message.Content != "" {
+		message.Content = &resp.Message.Content
+	}
+	if resp.Message.Thinking != "" {
+		message.ReasoningContent = &openai.ReasoningContentUnion{Value: resp.Message.Thinking}
+	}
+	for _, tc := range resp.Message.ToolCalls {
+		message.ToolCalls = append(message.ToolCalls, openai.ChatCompletionMessageToolCallParam{
+			ID:       ptr.To(uuid.New().String()),
+			Type:     openai.ChatCompletionMessageToolCallTypeFunction,
+			Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: tc.Function.Name, Arguments: string(tc.Function.Arguments)},
+		})
+	}
+	openAIResp := &openai.ChatCompletionResponse{
+		ID:      "chatcmpl-" + uuid.New().String(),
+		Object:  "chat.completion",
+		Model:   responseModel,
+		Created: openai.JSONUNIXTime(ollamaCreatedAt(resp.CreatedAt)),
+		Choices: []openai.ChatCompletionResponseChoice{{
+			Message:      message,
+			FinishReason: ollamaDoneReasonToOpenAI(resp.DoneReason, len(resp.Message.ToolCalls) > 0),
+		}},
+		Usage: ollamaMetricsToOpenAIUsage(&resp.Metrics),
+	}
+	tokenUsage = tokenUsageFromOpenAIUsage(&openAIResp.Usage)
+
+	newBody, err = json.Marshal(openAIResp)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(openAIResp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// handleStreamingResponse converts the newline delimited JSON objects of the Ollama streamed response to OpenAI chunks.
+// The last object carries the done reason and the metrics, which are sent as the finish reason and the usage.
+func (o *openAIToOllamaTranslatorV1ChatCompletion) handleStreamingResponse(body io.Reader, endOfStream bool, span tracingapi.ChatCompletionSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	responseModel = o.requestModel
+	buf, err := io.ReadAll(io.MultiReader(bytes.NewReader(o.bufferedBody), body))
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to read body: %w", err)
+	}
+	if o.id == "" {
+		o.id, o.created = "chatcmpl-"+uuid.New().String(), time.Now()
+	}
+	var lines [][]byte
+	lines, o.bufferedBody = ndjsonLines(buf)
+	for _, line := range lines {
+		var resp ollama.ChatResponse
+		if err = json.Unmarshal(line, &resp); err != nil {
+			return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal stream chunk: %w", err)
+		}
+		if resp.Model != "" {
+			responseModel = resp.Model
+		}
+		delta := &openai.ChatCompletionResponseChunkChoiceDelta{Role: openai.ChatMessageRoleAssistant}
+		if resp.Message.Content != "" {
+			delta.Content = &resp.Message.Content
+		}
+		if resp.Message.Thinking != "" {
+			delta.ReasoningContent = &openai.StreamReasoningContent{Text: resp.Message.Thinking}
+		}
+		for _, tc := range resp.Message.ToolCalls {
+			// Ollama sends each tool call as a whole, so every tool call is a complete OpenAI delta.
+			delta.ToolCalls = append(delta.ToolCalls, openai.ChatCompletionChunkChoiceDeltaToolCall{
+				Index:    o.toolCallIndex,
+				ID:       ptr.To(uuid.New().String()),
+				Type:     openai.ChatCompletionMessageToolCallTypeFunction,
+				Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: tc.Function.Name, Arguments: string(tc.Function.Arguments)},
+			})
+			o.toolCallIndex++
+		}
+		choice := openai.ChatCompletionResponseChunkChoice{Delta: delta}
+		if resp.Done {
+			choice.FinishReason = ollamaDoneReasonToOpenAI(resp.DoneReason, o.toolCallIndex > 0)
+		}
+		chunk := o.newChunk(responseModel)
+		chunk.Choices = []openai.ChatCompletionResponseChunkChoice{choice}
+		if err = serializeOpenAIChatCompletionChunk(chunk, &newBody); err != nil {
+			return nil, nil, tokenUsage, "", err
+		}
+		if span != nil {
+			span.RecordResponseChunk(chunk)
+		}
+
+		if !resp.Done {
+			continue
+		}
+		usage := ollamaMetricsToOpenAIUsage(&resp.Metrics)
+		tokenUsage = tokenUsageFromOpenAIUsage(&usage)
+		usageChunk := o.newChunk(responseModel)
+		usageChunk.Choices = []openai.ChatCompletionResponseChunkChoice{}
+		usageChunk.Usage = &usage
+		if err = serializeOpenAIChatCompletionChunk(usageChunk, &newBody); err != nil {
+			return nil, nil, tokenUsage, "", err
+		}
+		if span != nil {
+			span.RecordResponseChunk(usageChunk)
+		}
+	}
+
+	if endOfStream {
+		newBody = append(newBody, sseDoneFullLine...)
+	}
+	// Return an empty body rather than nil so that Envoy doesn't pass through the original Ollama objects.
+	if newBody == nil {
+		newBody = []byte{}
+	}
+	return
+}
+
+// newChunk returns an OpenAI chunk of the streamed response without the choices.
+func (o *openAIToOllamaTranslatorV1ChatCompletion) newChunk(model string) *openai.ChatCompletionResponseChunk {
+	return &openai.ChatCompletionResponseChunk{
+		ID:      o.id,
+		Object:  "chat.completion.chunk",
+		Created: openai.JSONUNIXTime(o.created),
+		Model:   model,
+	}
+}
+
+// ResponseError implements [OpenAIChatCompletionTranslator.ResponseError].
+func (o *openAIToOllamaTranslatorV1ChatCompletion) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	return convertOllamaErrorToOpenAI(respHeaders, body)
+}
+
+// ndjsonLines splits the buffer into the complete non-empty lines of newline delimited JSON
+// and the rest, which is the incomplete last line.
+func ndjsonLines(buf []byte) (lines [][]byte, rest []byte) {
+	for {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			break
+		}
+		if line := bytes.TrimSpace(buf[:i]); len(line) > 0 {
+			lines = append(lines, line)
+		}
+		buf = buf[i+1:]
+	}
+	if len(buf) > 0 {
+		rest = bytes.Clone(buf)
+	}
+	return
+}
+
+// ollamaCreatedAt parses the creation time of the Ollama response, defaulting to the current time.
+func ollamaCreatedAt(createdAt string) time.Time {
+	if t, err := time.Parse(time.RFC3339Nano, createdAt); err == nil {
+		return t
+	}
+	return time.Now()
+}
+
+// ollamaDoneReasonToOpenAI converts the done reason of Ollama to the OpenAI finish reason.
+// Ollama reports "stop" when the model called tools, so hasToolCalls tells them apart.
+func ollamaDoneReasonToOpenAI(reason string, hasToolCalls bool) openai.ChatCompletionChoicesFinishReason {
+	switch {
+	case reason == ollama.DoneReasonLength:
+		return openai.ChatCompletionChoicesFinishReasonLength
+	case hasToolCalls:
+		return openai.ChatCompletionChoicesFinishReasonToolCalls
+	}
+	return openai.ChatCompletionChoicesFinishReasonStop
+}
+
+// ollamaMetricsToOpenAIUsage converts the Ollama metrics to the OpenAI usage.
+func ollamaMetricsToOpenAIUsage(m *ollama.Metrics) openai.Usage {
+	return openai.Usage{
+		PromptTokens:     m.PromptEvalCount,
+		CompletionTokens: m.EvalCount,
+		TotalTokens:      m.PromptEvalCount + m.EvalCount,
+	}
+}
+
+// convertOllamaErrorToOpenAI converts the Ollama error responses to the OpenAI error format.
+// This is shared by the chat completion and the embedding translators.
+func convertOllamaErrorToOpenAI(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	buf, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read error body: %w", err)
+	}
+	statusCode := respHeaders[statusHeaderName]
+	openAIErr := openai.Error{
+		Type:  "error",
+		Error: openai.ErrorType{Type: ollamaBackendError, Message: string(buf), Code: &statusCode},
+	}
+	var ollamaErr ollama.Error
+	if json.Unmarshal(buf, &ollamaErr) == nil && ollamaErr.Error != "" {
+		openAIErr.Error.Message = ollamaErr.Error
+	}
+	newBody, err = json.Marshal(openAIErr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal error body: %w", err)
+	}
+	return buildHeaders(newBody), newBody, nil
+}
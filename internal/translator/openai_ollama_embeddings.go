@@ -0,0 +1,111 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/ollama"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewEmbeddingOpenAIToOllamaTranslator implements [Factory] for OpenAI to Ollama embedding translation.
+func NewEmbeddingOpenAIToOllamaTranslator(modelNameOverride internalapi.ModelNameOverride) OpenAIEmbeddingTranslator {
+	return &openAIToOllamaTranslatorV1Embedding{modelNameOverride: modelNameOverride}
+}
+
+// openAIToOllamaTranslatorV1Embedding translates OpenAI embedding requests to the native embed API of Ollama.
+// https://github.com/ollama/ollama/blob/main/docs/api.md#generate-embeddings
+type openAIToOllamaTranslatorV1Embedding struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+}
+
+// RequestBody implements [OpenAIEmbeddingTranslator.RequestBody].
+func (o *openAIToOllamaTranslatorV1Embedding) RequestBody(_ []byte, req *openai.EmbeddingRequest, _ bool) (
+	newHeaders []internalapi.Header, mutatedBody []byte, err error,
+) {
+	o.requestModel = req.Model
+	if o.modelNameOverride != "" {
+		o.requestModel = o.modelNameOverride
+	}
+	texts, err := embeddingInputTexts(req, "Ollama")
+	if err != nil {
+		return nil, nil, err
+	}
+	ollamaReq := &ollama.EmbedRequest{Model: o.requestModel, Input: texts, Dimensions: req.Dimensions}
+	if req.OllamaVendorFields != nil {
+		ollamaReq.Options, ollamaReq.KeepAlive = req.Options, req.KeepAlive
+	}
+	mutatedBody, err = json.Marshal(ollamaReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, ollama.EmbedPath},
+		{contentLengthHeaderName, strconv.Itoa(len(mutatedBody))},
+	}
+	return
+}
+
+// ResponseHeaders implements [OpenAIEmbeddingTranslator.ResponseHeaders].
+func (o *openAIToOllamaTranslatorV1Embedding) ResponseHeaders(_ map[string]string) (
+	newHeaders []internalapi.Header, err error,
+) {
+	return nil, nil
+}
+
+// ResponseBody implements [OpenAIEmbeddingTranslator.ResponseBody].
+func (o *openAIToOllamaTranslatorV1Embedding) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.EmbeddingsSpan) (
+	newHeaders []internalapi.Header, mutatedBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	var resp ollama.EmbedResponse
+	if err = json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	responseModel = o.requestModel
+	if resp.Model != "" {
+		responseModel = resp.Model
+	}
+	openAIResp := openai.EmbeddingResponse{
+		Object: "list",
+		Model:  responseModel,
+		Data:   make([]openai.Embedding, 0, len(resp.Embeddings)),
+		Usage:  openai.EmbeddingUsage{PromptTokens: resp.PromptEvalCount, TotalTokens: resp.PromptEvalCount},
+	}
+	for i, embedding := range resp.Embeddings {
+		openAIResp.Data = append(openAIResp.Data, openai.Embedding{
+			Object:    "embedding",
+			Index:     i,
+			Embedding: openai.EmbeddingUnion{Value: embedding},
+		})
+	}
+	tokenUsage.SetInputTokens(uint32(resp.PromptEvalCount)) //nolint:gosec
+	tokenUsage.SetTotalTokens(uint32(resp.PromptEvalCount)) //nolint:gosec
+
+	mutatedBody, err = json.Marshal(openAIResp)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(&openAIResp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(mutatedBody))}}
+	return
+}
+
+// ResponseError implements [OpenAIEmbeddingTranslator.ResponseError].
+func (o *openAIToOllamaTranslatorV1Embedding) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, mutatedBody []byte, err error,
+) {
+	return convertOllamaErrorToOpenAI(respHeaders, body)
+}
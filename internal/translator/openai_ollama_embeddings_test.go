@@ -0,0 +1,47 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestOpenAIToOllamaTranslatorV1Embedding(t *testing.T) {
+	var req openai.EmbeddingRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"model": "nomic-embed-text", "input": ["hello", "world"], "dimensions": 256, "keep_alive": -1}`), &req))
+	tr := NewEmbeddingOpenAIToOllamaTranslator("")
+	headers, body, err := tr.RequestBody(nil, &req, false)
+	require.NoError(t, err)
+	require.Equal(t, "/api/embed", headers[0].Value())
+	require.JSONEq(t, `{"model": "nomic-embed-text", "input": ["hello", "world"], "dimensions": 256, "keep_alive": -1}`, string(body))
+
+	_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(`{
+		"model": "nomic-embed-text:latest",
+		"embeddings": [[0.1, 0.2], [0.3, 0.4]],
+		"total_duration": 14143917,
+		"prompt_eval_count": 4
+	}`), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, "nomic-embed-text:latest", responseModel)
+	require.JSONEq(t, `{
+		"object": "list",
+		"model": "nomic-embed-text:latest",
+		"data": [{"object": "embedding", "index": 0, "embedding": [0.1, 0.2]}, {"object": "embedding", "index": 1, "embedding": [0.3, 0.4]}],
+		"usage": {"prompt_tokens": 4, "total_tokens": 4}
+	}`, string(body))
+	inputTokens, _ := tokenUsage.InputTokens()
+	require.Equal(t, uint32(4), inputTokens)
+
+	_, body, err = tr.ResponseError(map[string]string{statusHeaderName: "400"}, strings.NewReader(`{"error":"input length exceeds the context length"}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type":"error","error":{"type":"OllamaBackendError","message":"input length exceeds the context length","code":"400"}}`, string(body))
+}
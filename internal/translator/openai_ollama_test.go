@@ -0,0 +1,177 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestOpenAIToOllamaTranslatorV1ChatCompletion_RequestBody(t *testing.T) {
+	t.Run("full request", func(t *testing.T) {
+		var req openai.ChatCompletionRequest
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"model": "llama3.2",
+			"messages": [
+				{"role": "system", "content": "You are helpful."},
+				{"role": "user", "content": [{"type": "text", "text": "What is in this image?"}, {"type": "image_url", "image_url": {"url": "data:image/png;base64,AAAA"}}]},
+				{"role": "assistant", "content": "", "tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": "{\"city\":\"Tokyo\"}"}}]},
+				{"role": "tool", "tool_call_id": "call_1", "content": "sunny"}
+			],
+			"max_completion_tokens": 100,
+			"temperature": 0.5,
+			"stop": "END",
+			"response_format": {"type": "json_schema", "json_schema": {"name": "weather", "schema": {"type": "object"}}},
+			"tools": [{"type": "function", "function": {"name": "get_weather", "parameters": {"type": "object"}}}],
+			"keep_alive": "10m",
+			"options": {"num_ctx": 8192, "temperature": 0.1}
+		}`), &req))
+
+		tr := NewChatCompletionOpenAIToOllamaTranslator("")
+		headers, body, err := tr.RequestBody(nil, &req, false)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{
+			{pathHeaderName, "/api/chat"},
+			{contentLengthHeaderName, strconv.Itoa(len(body))},
+		}, headers)
+		require.JSONEq(t, `{
+			"model": "llama3.2",
+			"messages": [
+				{"role": "system", "content": "You are helpful."},
+				{"role": "user", "content": "What is in this image?", "images": ["AAAA"]},
+				{"role": "assistant", "content": "", "tool_calls": [{"function": {"name": "get_weather", "arguments": {"city": "Tokyo"}}}]},
+				{"role": "tool", "content": "sunny", "tool_name": "get_weather"}
+			],
+			"tools": [{"type": "function", "function": {"name": "get_weather", "parameters": {"type": "object"}}}],
+			"format": {"type": "object"},
+			"options": {"num_predict": 100, "temperature": 0.1, "stop": ["END"], "num_ctx": 8192},
+			"stream": false,
+			"keep_alive": "10m"
+		}`, string(body))
+	})
+
+	t.Run("model override and json mode", func(t *testing.T) {
+		var req openai.ChatCompletionRequest
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"model": "gpt-4o",
+			"messages": [{"role": "user", "content": "hi"}],
+			"stream": true,
+			"response_format": {"type": "json_object"},
+			"tools": [{"type": "function", "function": {"name": "f"}}],
+			"tool_choice": "none",
+			"reasoning_effort": "none"
+		}`), &req))
+		_, body, err := NewChatCompletionOpenAIToOllamaTranslator("qwen3").RequestBody(nil, &req, false)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"model": "qwen3",
+			"messages": [{"role": "user", "content": "hi"}],
+			"format": "json",
+			"stream": true,
+			"think": false
+		}`, string(body))
+	})
+
+	t.Run("image url", func(t *testing.T) {
+		var req openai.ChatCompletionRequest
+		require.NoError(t, json.Unmarshal([]byte(`{"model": "m", "messages": [{"role": "user", "content": [{"type": "image_url", "image_url": {"url": "https://example.com/cat.png"}}]}]}`), &req))
+		_, _, err := NewChatCompletionOpenAIToOllamaTranslator("").RequestBody(nil, &req, false)
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+	})
+}
+
+func TestOpenAIToOllamaTranslatorV1ChatCompletion_ResponseBody(t *testing.T) {
+	tr := NewChatCompletionOpenAIToOllamaTranslator("")
+	_, _, err := tr.RequestBody(nil, &openai.ChatCompletionRequest{Model: "llama3.2"}, false)
+	require.NoError(t, err)
+
+	headers, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(`{
+		"model": "llama3.2:latest",
+		"created_at": "2026-01-02T03:04:05.123456Z",
+		"message": {"role": "assistant", "content": "", "thinking": "Let me check.", "tool_calls": [{"function": {"name": "get_weather", "arguments": {"city": "Tokyo"}}}]},
+		"done": true,
+		"done_reason": "stop",
+		"prompt_eval_count": 10,
+		"eval_count": 5
+	}`), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(body))}}, headers)
+	require.Equal(t, "llama3.2:latest", responseModel)
+
+	var resp openai.ChatCompletionResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	require.Equal(t, int64(1767323045), time.Time(resp.Created).Unix())
+	require.Len(t, resp.Choices, 1)
+	require.Equal(t, openai.ChatCompletionChoicesFinishReasonToolCalls, resp.Choices[0].FinishReason)
+	require.Nil(t, resp.Choices[0].Message.Content)
+	require.Equal(t, "Let me check.", resp.Choices[0].Message.ReasoningContent.Value)
+	require.Equal(t, "get_weather", resp.Choices[0].Message.ToolCalls[0].Function.Name)
+	require.JSONEq(t, `{"city": "Tokyo"}`, resp.Choices[0].Message.ToolCalls[0].Function.Arguments)
+	require.NotEmpty(t, *resp.Choices[0].Message.ToolCalls[0].ID)
+	require.Equal(t, openai.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}, resp.Usage)
+	totalTokens, _ := tokenUsage.TotalTokens()
+	require.Equal(t, uint32(15), totalTokens)
+}
+
+func TestOpenAIToOllamaTranslatorV1ChatCompletion_ResponseBody_Streaming(t *testing.T) {
+	tr := NewChatCompletionOpenAIToOllamaTranslator("")
+	_, _, err := tr.RequestBody(nil, &openai.ChatCompletionRequest{Model: "llama3.2", Stream: true}, false)
+	require.NoError(t, err)
+	headers, err := tr.ResponseHeaders(nil)
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{{contentTypeHeaderName, eventStreamContentType}}, headers)
+
+	// The second line is split across the calls.
+	_, body, _, _, err := tr.ResponseBody(nil, strings.NewReader(
+		`{"model":"llama3.2","created_at":"2026-01-02T03:04:05Z","message":{"role":"assistant","content":"Hel"},"done":false}`+"\n"+
+			`{"model":"llama3.2","created_at":"2026-01-02T03:04:05Z","message":{"role":"assis`), false, nil)
+	require.NoError(t, err)
+	chunks := parseOpenAIChunks(t, body)
+	require.Len(t, chunks, 1)
+	require.Equal(t, "Hel", *chunks[0].Choices[0].Delta.Content)
+
+	_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(
+		`tant","content":"lo"},"done":false}`+"\n"+
+			`{"model":"llama3.2","created_at":"2026-01-02T03:04:05Z","message":{"role":"assistant","content":""},"done":true,"done_reason":"length","prompt_eval_count":10,"eval_count":5}`+"\n"), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, "llama3.2", responseModel)
+	require.True(t, bytes.HasSuffix(body, sseDoneFullLine))
+	chunks = parseOpenAIChunks(t, body)
+	require.Len(t, chunks, 3)
+	require.Equal(t, "lo", *chunks[0].Choices[0].Delta.Content)
+	require.Equal(t, openai.ChatCompletionChoicesFinishReasonLength, chunks[1].Choices[0].FinishReason)
+	require.Equal(t, 15, chunks[2].Usage.TotalTokens)
+	// All the chunks share the same ID.
+	require.Equal(t, chunks[0].ID, chunks[2].ID)
+	outputTokens, _ := tokenUsage.OutputTokens()
+	require.Equal(t, uint32(5), outputTokens)
+}
+
+func TestOpenAIToOllamaTranslatorV1ChatCompletion_ResponseError(t *testing.T) {
+	tr := NewChatCompletionOpenAIToOllamaTranslator("")
+	_, body, err := tr.ResponseError(map[string]string{statusHeaderName: "404"}, strings.NewReader(`{"error":"model \"llama9\" not found, try pulling it first"}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type":"error","error":{"type":"OllamaBackendError","message":"model \"llama9\" not found, try pulling it first","code":"404"}}`, string(body))
+}
+
+func TestNdjsonLines(t *testing.T) {
+	lines, rest := ndjsonLines([]byte("{\"a\":1}\n\n{\"b\":2}\r\n{\"c\""))
+	require.Equal(t, [][]byte{[]byte(`{"a":1}`), []byte(`{"b":2}`)}, lines)
+	require.Equal(t, []byte(`{"c"`), rest)
+
+	lines, rest = ndjsonLines([]byte("{}\n"))
+	require.Equal(t, [][]byte{[]byte(`{}`)}, lines)
+	require.Nil(t, rest)
+}
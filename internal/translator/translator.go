@@ -13,6 +13,7 @@ import (
 
 	anthropicschema "github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
 	cohereschema "github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/ollama"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 	"github.com/envoyproxy/ai-gateway/internal/metrics"
@@ -132,6 +133,10 @@ type (
 	OpenAIAudioTranscriptionTranslator = Translator[openai.TranscriptionRequest, tracingapi.TranscriptionSpan]
 	// OpenAIAudioTranslationTranslator translates the OpenAI's /v1/audio/translations endpoint.
 	OpenAIAudioTranslationTranslator = Translator[openai.TranslationRequest, tracingapi.TranslationSpan]
+	// OllamaChatTranslator translates the Ollama's /api/chat endpoint.
+	OllamaChatTranslator = Translator[ollama.ChatRequest, tracingapi.OllamaChatSpan]
+	// OllamaEmbedTranslator translates the Ollama's /api/embed endpoint.
+	OllamaEmbedTranslator = Translator[ollama.EmbedRequest, tracingapi.OllamaEmbedSpan]
 )
 
 var (
@@ -6,12 +6,15 @@
 package translator
 
 import (
+	"bytes"
 	"encoding/base64"
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
 	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
 )
 
 const (
@@ -72,3 +75,77 @@ func serializeOpenAIChatCompletionChunk(chunk *openai.ChatCompletionResponseChun
 	*buf = append(*buf, '\n', '\n')
 	return nil
 }
+
+// textOfContentUnion returns the text of the content of a system, developer or tool message.
+func textOfContentUnion(content openai.ContentUnion) string {
+	switch v := content.Value.(type) {
+	case string:
+		return v
+	case []openai.ChatCompletionContentPartTextParam:
+		var b strings.Builder
+		for _, part := range v {
+			b.WriteString(part.Text)
+		}
+		return b.String()
+	}
+	return ""
+}
+
+// textOfAssistantContent returns the text of the content of an assistant message.
+func textOfAssistantContent(content openai.StringOrAssistantRoleContentUnion) string {
+	var parts []openai.ChatCompletionAssistantMessageParamContent
+	switch v := content.Value.(type) {
+	case string:
+		return v
+	case openai.ChatCompletionAssistantMessageParamContent:
+		parts = []openai.ChatCompletionAssistantMessageParamContent{v}
+	case []openai.ChatCompletionAssistantMessageParamContent:
+		parts = v
+	}
+	var b strings.Builder
+	for _, part := range parts {
+		if part.Type == openai.ChatCompletionAssistantMessageParamContentTypeText && part.Text != nil {
+			b.WriteString(*part.Text)
+		}
+	}
+	return b.String()
+}
+
+// tokenUsageFromOpenAIUsage returns the token usage of the given OpenAI usage.
+func tokenUsageFromOpenAIUsage(usage *openai.Usage) (tokenUsage metrics.TokenUsage) {
+	tokenUsage.SetInputTokens(uint32(usage.PromptTokens))      //nolint:gosec
+	tokenUsage.SetOutputTokens(uint32(usage.CompletionTokens)) //nolint:gosec
+	tokenUsage.SetTotalTokens(uint32(usage.TotalTokens))       //nolint:gosec
+	if usage.PromptTokensDetails != nil {
+		tokenUsage.SetCachedInputTokens(uint32(usage.PromptTokensDetails.CachedTokens)) //nolint:gosec
+	}
+	if usage.CompletionTokensDetails != nil {
+		tokenUsage.SetReasoningTokens(uint32(usage.CompletionTokensDetails.ReasoningTokens)) //nolint:gosec
+	}
+	return
+}
+
+// sseEventsData splits the given server-sent events into the data of each complete event, and returns the rest
+// of the input that doesn't make a complete event yet. The "id", "event" and comment lines of the events are ignored.
+func sseEventsData(buf []byte) (data [][]byte, rest []byte) {
+	buf = bytes.ReplaceAll(buf, []byte("\r\n"), []byte("\n"))
+	for {
+		i := bytes.Index(buf, []byte("\n\n"))
+		if i < 0 {
+			return data, buf
+		}
+		var eventData []byte
+		for _, line := range bytes.Split(buf[:i], []byte("\n")) {
+			if after, ok := bytes.CutPrefix(line, []byte("data:")); ok {
+				if len(eventData) > 0 {
+					eventData = append(eventData, '\n')
+				}
+				eventData = append(eventData, bytes.TrimPrefix(after, []byte(" "))...)
+			}
+		}
+		if len(eventData) > 0 {
+			data = append(data, eventData)
+		}
+		buf = buf[i+2:]
+	}
+}
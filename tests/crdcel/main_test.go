@@ -135,19 +135,19 @@ func TestBackendSecurityPolicies(t *testing.T) {
 		},
 		{
 			name:   "azure_credentials_missing_client_id.yaml",
-			expErr: "spec.azureCredentials.clientID in body should be at least 1 chars long",
+			expErr: "clientID and tenantID must be specified unless managedIdentity is set",
 		},
 		{
 			name:   "azure_credentials_missing_tenant_id.yaml",
-			expErr: "spec.azureCredentials.tenantID in body should be at least 1 chars long",
+			expErr: "clientID and tenantID must be specified unless managedIdentity is set",
 		},
 		{
 			name:   "azure_missing_auth.yaml",
-			expErr: "Exactly one of clientSecretRef or oidcExchangeToken must be specified",
+			expErr: "Exactly one of clientSecretRef, oidcExchangeToken, managedIdentity or workloadIdentity must be specified",
 		},
 		{
 			name:   "azure_multiple_auth.yaml",
-			expErr: "Exactly one of clientSecretRef or oidcExchangeToken must be specified",
+			expErr: "Exactly one of clientSecretRef, oidcExchangeToken, managedIdentity or workloadIdentity must be specified",
 		},
 		// CEL validation test cases - these should fail due to type mismatch.
 		{
@@ -184,6 +184,36 @@ func TestBackendSecurityPolicies(t *testing.T) {
 		{name: "aws_oidc.yaml"},
 		{name: "gcp_oidc.yaml"},
 		{name: "anthropic-apikey.yaml"},
+		{name: "apikey_pool.yaml"},
+		{
+			name:   "apikey_pool_with_apikey.yaml",
+			expErr: "When type is APIKeyPool, only apiKeyPool field should be set",
+		},
+		{
+			name:   "apikey_pool_no_keys.yaml",
+			expErr: "spec.apiKeyPool.keys in body should have at least 1 items",
+		},
+		{name: "vault.yaml"},
+		{name: "azure_managed_identity.yaml"},
+		{name: "azure_workload_identity.yaml"},
+		{
+			name:   "azure_workload_identity_missing_tenant_id.yaml",
+			expErr: "clientID and tenantID must be specified unless managedIdentity is set",
+		},
+		{
+			name:   "vault_kv_and_dynamic.yaml",
+			expErr: "Exactly one of kv or dynamic must be specified",
+		},
+		{
+			name:   "vault_aws_without_region.yaml",
+			expErr: "region must be specified for AWSCredentials",
+		},
+		{name: "client_certificate.yaml"},
+		{name: "request_signing.yaml"},
+		{
+			name:   "request_signing_with_client_certificate.yaml",
+			expErr: "When type is RequestSigning, only requestSigning field should be set",
+		},
 		{name: "targetrefs_basic.yaml"},
 		{name: "targetrefs_multiple.yaml"},
 		{name: "targetrefs_inferencepool.yaml"},
@@ -280,6 +310,55 @@ func TestMCPRoutes(t *testing.T) {
 			expErr: "spec.securityPolicy.authorization.rules[0].source.jwt: Invalid value: \"object\": either scopes or claims must be specified",
 		},
 		{name: "authorization_without_jwt_source.yaml"},
+		{name: "rate_limit.yaml"},
+		{
+			name:   "rate_limit_missing_limits.yaml",
+			expErr: "spec.rateLimit.rules[0]: Invalid value: \"object\": at least one of requests or maxConcurrency must be specified",
+		},
+		{
+			name:   "rate_limit_tool_without_backend.yaml",
+			expErr: "spec.rateLimit.rules[0]: Invalid value: \"object\": backend must be specified when tool is specified",
+		},
+		{
+			name:   "rate_limit_header_key_missing_header.yaml",
+			expErr: "spec.rateLimit.rules[0].clientKey: Invalid value: \"object\": header must be specified if and only if type is Header",
+		},
+		{name: "stdio.yaml"},
+		{
+			name:   "stdio_with_security_policy.yaml",
+			expErr: "spec.backendRefs[0]: Invalid value: \"object\": securityPolicy cannot be used with stdio",
+		},
+		{
+			name:   "stdio_max_processes_shared.yaml",
+			expErr: "spec.backendRefs[0].stdio: Invalid value: \"object\": maxProcesses can only be specified with the PerSession isolation",
+		},
+		{name: "legacy_sse.yaml"},
+		{
+			name:   "legacy_sse_same_paths.yaml",
+			expErr: "spec.legacySSE: Invalid value: \"object\": ssePath and messagePath must be different",
+		},
+		{
+			name:   "stdio_sse_transport.yaml",
+			expErr: "spec.backendRefs[0]: Invalid value: \"object\": transport cannot be SSE with stdio",
+		},
+		{name: "openapi.yaml"},
+		{
+			name:   "openapi_configmap_and_url.yaml",
+			expErr: "spec.backendRefs[0].openAPI: Invalid value: \"object\": exactly one of configMapRef or url must be set",
+		},
+		{
+			name:   "openapi_sse_transport.yaml",
+			expErr: "spec.backendRefs[0]: Invalid value: \"object\": transport cannot be SSE with openAPI",
+		},
+		{name: "backend_user_credentials.yaml"},
+		{
+			name:   "backend_user_credentials_without_oauth.yaml",
+			expErr: "spec: Invalid value: \"object\": securityPolicy.oauth must be configured when a backend uses tokenExchange or userCredentials",
+		},
+		{
+			name:   "backend_api_key_and_token_exchange.yaml",
+			expErr: "spec.backendRefs[0].securityPolicy: Invalid value: \"object\": only one of apiKey, tokenExchange or userCredentials can be set",
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			data, err := testdata.ReadFile(path.Join("testdata/mcpgatewayroutes", tc.name))
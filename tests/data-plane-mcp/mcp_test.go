@@ -779,10 +779,9 @@ func testSamplingCreateMessage(t *testing.T, m *mcpEnv) {
 	require.NotNil(t, req.Params)
 	require.IsTypef(t, &mcp.CreateMessageParams{}, req.Params, "expected CreateMessageParams, actual %T", req.Params)
 
-	// The gateway encodes progress tokens as: base64(original)__<type>__<backend-name>
-	// where type is 's' for string, 'i' for int, 'f' for float.
-	// Original token "sampling-foo" becomes "c2FtcGxpbmctZm9v__s__default-mcp-backend".
-	requireNotificationProgressSpan(t, m.collector.TakeSpan(), "foo", "c2FtcGxpbmctZm9v__s__default-mcp-backend")
+	// The gateway encrypts progress tokens along with the backend name, so the span records the token
+	// as received by the client.
+	requireNotificationProgressSpan(t, m.collector.TakeSpan(), "foo", req.Params.Meta["progressToken"].(string))
 
 	requireMetricGreaterThan(t, m, "mcp_progress_notifications_total", nil, 0)
 }
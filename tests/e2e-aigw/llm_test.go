@@ -6,13 +6,19 @@
 package e2emcp
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"io"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
 
+	"github.com/envoyproxy/ai-gateway/internal/apischema/ollama"
+	"github.com/envoyproxy/ai-gateway/internal/json"
 	internaltesting "github.com/envoyproxy/ai-gateway/internal/testing"
 )
 
@@ -42,3 +48,66 @@ func TestAIGWRun_LLM(t *testing.T) {
 		return fmt.Errorf("no content in response")
 	}, 10*time.Second, 2*time.Second, "chat completion never succeeded")
 }
+
+// TestAIGWRun_OllamaAPI tests the inbound Ollama API, which is translated to the OpenAI API of the backend.
+func TestAIGWRun_OllamaAPI(t *testing.T) {
+	startAIGWCLI(t, aigwBin, localOllamaEnv, "run")
+
+	ctx := t.Context()
+
+	for _, stream := range []bool{false, true} {
+		t.Run(fmt.Sprintf("stream=%t", stream), func(t *testing.T) {
+			internaltesting.RequireEventuallyNoError(t, func() error {
+				body, err := json.Marshal(ollama.ChatRequest{
+					Model:    ollamaModel,
+					Messages: []ollama.Message{{Role: "user", Content: "Say this is a test"}},
+					Stream:   &stream,
+				})
+				if err != nil {
+					return err
+				}
+				req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://127.0.0.1:1975/ollama/api/chat", bytes.NewReader(body))
+				if err != nil {
+					return err
+				}
+				req.Header.Set("Content-Type", "application/json")
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					return fmt.Errorf("chat failed: %w", err)
+				}
+				defer resp.Body.Close()
+				if resp.StatusCode != http.StatusOK {
+					b, _ := io.ReadAll(resp.Body)
+					return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, b)
+				}
+
+				// The non-streamed response is a single JSON object, and the streamed one is newline delimited JSON.
+				var content string
+				var done bool
+				scanner := bufio.NewScanner(resp.Body)
+				scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+				for scanner.Scan() {
+					if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+						continue
+					}
+					var chatResp ollama.ChatResponse
+					if err = json.Unmarshal(scanner.Bytes(), &chatResp); err != nil {
+						return fmt.Errorf("failed to parse %q: %w", scanner.Text(), err)
+					}
+					content += chatResp.Message.Content + chatResp.Message.Thinking
+					done = chatResp.Done
+				}
+				if err = scanner.Err(); err != nil {
+					return err
+				}
+				if !done {
+					return fmt.Errorf("the last response is not done")
+				}
+				if content == "" {
+					return fmt.Errorf("no content in response")
+				}
+				return nil
+			}, 10*time.Second, 2*time.Second, "chat never succeeded")
+		})
+	}
+}